@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"strings"
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
@@ -28,7 +29,7 @@ import (
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 )
 
 func TestConvertTo(t *testing.T) {
@@ -102,7 +103,7 @@ func TestConvertFrom(t *testing.T) {
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"allowAllInClusterTraffic\":false,\"apiServerLoadBalancer\":{\"additionalPorts\":[80,443],\"enabled\":true},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"managedSecurityGroups\":false,\"network\":{},\"subnet\":{}},\"status\":{\"ready\":false}}",
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"apiServerLoadBalancer\":{\"additionalPorts\":[80,443],\"enabled\":true},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"network\":{},\"subnet\":{}},\"status\":{\"ready\":false}}",
 					},
 				},
 			},
@@ -221,80 +222,121 @@ func TestFuzzyConversion(t *testing.T) {
 				v1alpha3Filter.TenantID = v1alpha3Filter.ProjectID
 			},
 
-			// Don't test hub-spoke-hub conversion of v1alpha6 fields which are not in v1alpha3
-			func(v1alpha6Cluster *infrav1.OpenStackCluster, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6Cluster)
+			func(v1alpha3SecurityGroupParam *SecurityGroupParam, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha3SecurityGroupParam)
 
-				v1alpha6Cluster.ObjectMeta.Annotations = map[string]string{}
+				// The filter is not used when a UUID is given, and its
+				// name is replaced by the name of the parameter
+				if v1alpha3SecurityGroupParam.UUID != "" {
+					v1alpha3SecurityGroupParam.Name = ""
+					v1alpha3SecurityGroupParam.Filter = SecurityGroupFilter{}
+				}
+				v1alpha3SecurityGroupParam.Filter.Name = ""
+				v1alpha3SecurityGroupParam.Filter.ID = ""
+			},
+			func(v1alpha3SecurityGroupFilter *SecurityGroupFilter, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha3SecurityGroupFilter)
+
+				v1alpha3SecurityGroupFilter.Limit = 0
+				v1alpha3SecurityGroupFilter.Marker = ""
+				v1alpha3SecurityGroupFilter.SortKey = ""
+				v1alpha3SecurityGroupFilter.SortDir = ""
+
+				// TenantID and ProjectID are the same thing, so TenantID is removed in infrav1
+				v1alpha3SecurityGroupFilter.TenantID = ""
+			},
+
+			// Don't test hub-spoke-hub conversion of infrav1 fields which are not in v1alpha3
+			func(v1alpha7Cluster *infrav1.OpenStackCluster, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Cluster)
+
+				v1alpha7Cluster.ObjectMeta.Annotations = map[string]string{}
 
-				v1alpha6Cluster.Spec.APIServerFixedIP = ""
-				v1alpha6Cluster.Spec.AllowAllInClusterTraffic = false
-				v1alpha6Cluster.Spec.DisableAPIServerFloatingIP = false
-				v1alpha6Cluster.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
-				if v1alpha6Cluster.Spec.Bastion != nil {
-					v1alpha6Cluster.Spec.Bastion.Instance.ImageUUID = ""
-					v1alpha6Cluster.Spec.Bastion.Instance.Ports = nil
+				v1alpha7Cluster.Spec.APIServerFixedIP = ""
+				if v1alpha7Cluster.Spec.ManagedSecurityGroups != nil {
+					v1alpha7Cluster.Spec.ManagedSecurityGroups.AllowAllInClusterTraffic = false
 				}
-				v1alpha6Cluster.Spec.ControlPlaneOmitAvailabilityZone = false
+				v1alpha7Cluster.Spec.DisableAPIServerFloatingIP = false
+				v1alpha7Cluster.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
+				if v1alpha7Cluster.Spec.Bastion != nil {
+					v1alpha7Cluster.Spec.Bastion.Instance.ImageUUID = ""
+					v1alpha7Cluster.Spec.Bastion.Instance.Ports = nil
+					v1alpha7Cluster.Spec.Bastion.Instance.Region = ""
+				}
+				v1alpha7Cluster.Spec.ControlPlaneOmitAvailabilityZone = false
 
-				v1alpha6Cluster.Status.FailureMessage = nil
-				v1alpha6Cluster.Status.FailureReason = nil
+				v1alpha7Cluster.Status.FailureMessage = nil
+				v1alpha7Cluster.Status.FailureReason = nil
+				v1alpha7Cluster.Status.Conditions = nil
+				v1alpha7Cluster.Status.DryRunPlan = nil
 
-				if v1alpha6Cluster.Status.Bastion != nil {
-					v1alpha6Cluster.Status.Bastion.ImageUUID = ""
-					v1alpha6Cluster.Status.Bastion.Networks = nil
+				if v1alpha7Cluster.Status.Bastion != nil {
+					v1alpha7Cluster.Status.Bastion.ImageUUID = ""
+					v1alpha7Cluster.Status.Bastion.Networks = nil
 				}
 
-				if v1alpha6Cluster.Status.Network != nil {
-					if v1alpha6Cluster.Status.Network.APIServerLoadBalancer != nil {
-						v1alpha6Cluster.Status.Network.APIServerLoadBalancer.AllowedCIDRs = nil
+				if v1alpha7Cluster.Status.Network != nil {
+					if v1alpha7Cluster.Status.Network.APIServerLoadBalancer != nil {
+						v1alpha7Cluster.Status.Network.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
-					if v1alpha6Cluster.Status.Network.Router != nil {
-						v1alpha6Cluster.Status.Network.Router.IPs = []string{}
+					if v1alpha7Cluster.Status.Network.Router != nil {
+						v1alpha7Cluster.Status.Network.Router.IPs = []string{}
 					}
 				}
 
-				if v1alpha6Cluster.Status.ExternalNetwork != nil {
-					if v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer != nil {
-						v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer.AllowedCIDRs = nil
+				if v1alpha7Cluster.Status.ExternalNetwork != nil {
+					if v1alpha7Cluster.Status.ExternalNetwork.APIServerLoadBalancer != nil {
+						v1alpha7Cluster.Status.ExternalNetwork.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
-					if v1alpha6Cluster.Status.ExternalNetwork.Router != nil {
-						v1alpha6Cluster.Status.ExternalNetwork.Router.IPs = []string{}
+					if v1alpha7Cluster.Status.ExternalNetwork.Router != nil {
+						v1alpha7Cluster.Status.ExternalNetwork.Router.IPs = []string{}
 					}
 				}
 			},
-			func(v1alpha6Machine *infrav1.OpenStackMachine, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6Machine)
+			func(v1alpha7Machine *infrav1.OpenStackMachine, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Machine)
 
-				v1alpha6Machine.ObjectMeta.Annotations = map[string]string{}
-				v1alpha6Machine.Spec.Ports = nil
-				v1alpha6Machine.Spec.ImageUUID = ""
+				v1alpha7Machine.ObjectMeta.Annotations = map[string]string{}
+				v1alpha7Machine.Spec.Ports = nil
+				v1alpha7Machine.Spec.ImageUUID = ""
+				v1alpha7Machine.Spec.Region = ""
 			},
-			func(v1alpha6MachineTemplate *infrav1.OpenStackMachineTemplate, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6MachineTemplate)
+			func(v1alpha7MachineTemplate *infrav1.OpenStackMachineTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7MachineTemplate)
 
-				v1alpha6MachineTemplate.ObjectMeta.Annotations = map[string]string{}
+				v1alpha7MachineTemplate.ObjectMeta.Annotations = map[string]string{}
 
-				v1alpha6MachineTemplate.Spec.Template.Spec.Image = ""
-				v1alpha6MachineTemplate.Spec.Template.Spec.ImageUUID = ""
-				v1alpha6MachineTemplate.Spec.Template.Spec.Ports = nil
+				v1alpha7MachineTemplate.Spec.Template.Spec.Image = ""
+				v1alpha7MachineTemplate.Spec.Template.Spec.ImageUUID = ""
+				v1alpha7MachineTemplate.Spec.Template.Spec.Ports = nil
+				v1alpha7MachineTemplate.Spec.Template.Spec.Region = ""
 			},
-			func(v1alpha6Network *infrav1.Network, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6Network)
+			func(v1alpha7Network *infrav1.Network, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Network)
 
-				v1alpha6Network.PortOpts = nil
+				v1alpha7Network.PortOpts = nil
 			},
-			func(v1alpha6OpenStackIdentityRef *infrav1.OpenStackIdentityReference, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6OpenStackIdentityRef)
+			func(v1alpha7OpenStackIdentityRef *infrav1.OpenStackIdentityReference, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7OpenStackIdentityRef)
 
 				// IdentityRef was assumed to be a Secret in v1alpha3
-				v1alpha6OpenStackIdentityRef.Kind = "Secret"
+				v1alpha7OpenStackIdentityRef.Kind = "Secret"
 			},
-			func(v1alpha6RootVolume *infrav1.RootVolume, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6RootVolume)
+			func(v1alpha7RootVolume *infrav1.RootVolume, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7RootVolume)
 
-				v1alpha6RootVolume.VolumeType = ""
-				v1alpha6RootVolume.AvailabilityZone = ""
+				v1alpha7RootVolume.VolumeType = ""
+				v1alpha7RootVolume.AvailabilityZone = ""
+			},
+			func(v1alpha7FilterByNeutronTags *infrav1.FilterByNeutronTags, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7FilterByNeutronTags)
+
+				// Tags are converted to comma separated lists, which cannot
+				// contain empty tags or tags with commas
+				v1alpha7FilterByNeutronTags.Tags = validNeutronTags(v1alpha7FilterByNeutronTags.Tags)
+				v1alpha7FilterByNeutronTags.TagsAny = validNeutronTags(v1alpha7FilterByNeutronTags.TagsAny)
+				v1alpha7FilterByNeutronTags.NotTags = validNeutronTags(v1alpha7FilterByNeutronTags.NotTags)
+				v1alpha7FilterByNeutronTags.NotTagsAny = validNeutronTags(v1alpha7FilterByNeutronTags.NotTagsAny)
 			},
 		}
 	}
@@ -320,3 +362,13 @@ func TestFuzzyConversion(t *testing.T) {
 		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
 	}))
 }
+
+func validNeutronTags(tags []infrav1.NeutronTag) []infrav1.NeutronTag {
+	var ret []infrav1.NeutronTag
+	for _, tag := range tags {
+		if tag != "" && !strings.Contains(string(tag), ",") {
+			ret = append(ret, tag)
+		}
+	}
+	return ret
+}
@@ -852,6 +852,8 @@ func autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha3_OpenStackClusterSta
 	}
 	// WARNING: in.FailureReason requires manual conversion: does not exist in peer-type
 	// WARNING: in.FailureMessage requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.DryRunPlan requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -973,6 +975,7 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha3_OpenStackMachineSpec(
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
 	out.CloudName = in.CloudName
+	// WARNING: in.Region requires manual conversion: does not exist in peer-type
 	out.Flavor = in.Flavor
 	out.Image = in.Image
 	// WARNING: in.ImageUUID requires manual conversion: does not exist in peer-type
@@ -21,7 +21,8 @@ import (
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1alpha6 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 )
 
 var _ ctrlconversion.Convertible = &OpenStackCluster{}
@@ -29,15 +30,33 @@ var _ ctrlconversion.Convertible = &OpenStackCluster{}
 func (r *OpenStackCluster) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackCluster)
 
-	if err := Convert_v1alpha4_OpenStackCluster_To_v1alpha6_OpenStackCluster(r, dst, nil); err != nil {
+	// Manually restore data.
+	restored := &infrav1.OpenStackCluster{}
+	ok, err := utilconversion.UnmarshalData(r, restored)
+	if err != nil {
 		return err
 	}
 
-	// Manually restore data.
-	restored := &infrav1.OpenStackCluster{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackCluster{}
+	if err := Convert_v1alpha4_OpenStackCluster_To_v1alpha6_OpenStackCluster(r, mid, nil); err != nil {
+		return err
+	}
+	if ok {
+		// Hand the hub data on to the conversion of v1alpha6
+		if err := utilconversion.MarshalData(restored, mid); err != nil {
+			return err
+		}
+	}
+	if err := mid.ConvertTo(dst); err != nil {
 		return err
 	}
+	if !ok {
+		return nil
+	}
+
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.DryRunPlan = restored.Status.DryRunPlan
 
 	if restored.Spec.APIServerLoadBalancer.AllowedCIDRs != nil {
 		dst.Spec.APIServerLoadBalancer.AllowedCIDRs = restored.Spec.APIServerLoadBalancer.AllowedCIDRs
@@ -49,7 +68,11 @@ func (r *OpenStackCluster) ConvertTo(dstRaw ctrlconversion.Hub) error {
 func (r *OpenStackCluster) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackCluster)
 
-	if err := Convert_v1alpha6_OpenStackCluster_To_v1alpha4_OpenStackCluster(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackCluster{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackCluster_To_v1alpha4_OpenStackCluster(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -66,13 +89,24 @@ var _ ctrlconversion.Convertible = &OpenStackClusterList{}
 func (r *OpenStackClusterList) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackClusterList)
 
-	return Convert_v1alpha4_OpenStackClusterList_To_v1alpha6_OpenStackClusterList(r, dst, nil)
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackClusterList{}
+	if err := Convert_v1alpha4_OpenStackClusterList_To_v1alpha6_OpenStackClusterList(r, mid, nil); err != nil {
+		return err
+	}
+
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackClusterList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackClusterList)
 
-	return Convert_v1alpha6_OpenStackClusterList_To_v1alpha4_OpenStackClusterList(src, r, nil)
+	mid := &infrav1alpha6.OpenStackClusterList{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+
+	return Convert_v1alpha6_OpenStackClusterList_To_v1alpha4_OpenStackClusterList(mid, r, nil)
 }
 
 var _ ctrlconversion.Convertible = &OpenStackClusterTemplate{}
@@ -80,23 +114,23 @@ var _ ctrlconversion.Convertible = &OpenStackClusterTemplate{}
 func (r *OpenStackClusterTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackClusterTemplate)
 
-	if err := Convert_v1alpha4_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(r, dst, nil); err != nil {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackClusterTemplate{}
+	if err := Convert_v1alpha4_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(r, mid, nil); err != nil {
 		return err
 	}
 
-	// Manually restore data.
-	restored := &infrav1.OpenStackClusterTemplate{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
-		return err
-	}
-
-	return nil
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackClusterTemplate) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackClusterTemplate)
 
-	if err := Convert_v1alpha6_OpenStackClusterTemplate_To_v1alpha4_OpenStackClusterTemplate(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackClusterTemplate{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackClusterTemplate_To_v1alpha4_OpenStackClusterTemplate(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -113,13 +147,24 @@ var _ ctrlconversion.Convertible = &OpenStackClusterTemplateList{}
 func (r *OpenStackClusterTemplateList) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackClusterTemplateList)
 
-	return Convert_v1alpha4_OpenStackClusterTemplateList_To_v1alpha6_OpenStackClusterTemplateList(r, dst, nil)
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackClusterTemplateList{}
+	if err := Convert_v1alpha4_OpenStackClusterTemplateList_To_v1alpha6_OpenStackClusterTemplateList(r, mid, nil); err != nil {
+		return err
+	}
+
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackClusterTemplateList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackClusterTemplateList)
 
-	return Convert_v1alpha6_OpenStackClusterTemplateList_To_v1alpha4_OpenStackClusterTemplateList(src, r, nil)
+	mid := &infrav1alpha6.OpenStackClusterTemplateList{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+
+	return Convert_v1alpha6_OpenStackClusterTemplateList_To_v1alpha4_OpenStackClusterTemplateList(mid, r, nil)
 }
 
 var _ ctrlconversion.Convertible = &OpenStackMachine{}
@@ -127,15 +172,32 @@ var _ ctrlconversion.Convertible = &OpenStackMachine{}
 func (r *OpenStackMachine) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachine)
 
-	if err := Convert_v1alpha4_OpenStackMachine_To_v1alpha6_OpenStackMachine(r, dst, nil); err != nil {
+	// Manually restore data.
+	restored := &infrav1.OpenStackMachine{}
+	ok, err := utilconversion.UnmarshalData(r, restored)
+	if err != nil {
 		return err
 	}
 
-	// Manually restore data.
-	restored := &infrav1.OpenStackMachine{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachine{}
+	if err := Convert_v1alpha4_OpenStackMachine_To_v1alpha6_OpenStackMachine(r, mid, nil); err != nil {
+		return err
+	}
+	if ok {
+		// Hand the hub data on to the conversion of v1alpha6
+		if err := utilconversion.MarshalData(restored, mid); err != nil {
+			return err
+		}
+	}
+	if err := mid.ConvertTo(dst); err != nil {
 		return err
 	}
+	if !ok {
+		return nil
+	}
+
+	dst.Spec.Region = restored.Spec.Region
 
 	return nil
 }
@@ -143,7 +205,11 @@ func (r *OpenStackMachine) ConvertTo(dstRaw ctrlconversion.Hub) error {
 func (r *OpenStackMachine) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachine)
 
-	if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha4_OpenStackMachine(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackMachine{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha4_OpenStackMachine(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -160,13 +226,24 @@ var _ ctrlconversion.Convertible = &OpenStackMachineList{}
 func (r *OpenStackMachineList) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachineList)
 
-	return Convert_v1alpha4_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(r, dst, nil)
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachineList{}
+	if err := Convert_v1alpha4_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(r, mid, nil); err != nil {
+		return err
+	}
+
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackMachineList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachineList)
 
-	return Convert_v1alpha6_OpenStackMachineList_To_v1alpha4_OpenStackMachineList(src, r, nil)
+	mid := &infrav1alpha6.OpenStackMachineList{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+
+	return Convert_v1alpha6_OpenStackMachineList_To_v1alpha4_OpenStackMachineList(mid, r, nil)
 }
 
 var _ ctrlconversion.Convertible = &OpenStackMachineTemplate{}
@@ -174,15 +251,32 @@ var _ ctrlconversion.Convertible = &OpenStackMachineTemplate{}
 func (r *OpenStackMachineTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachineTemplate)
 
-	if err := Convert_v1alpha4_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(r, dst, nil); err != nil {
+	// Manually restore data.
+	restored := &infrav1.OpenStackMachineTemplate{}
+	ok, err := utilconversion.UnmarshalData(r, restored)
+	if err != nil {
 		return err
 	}
 
-	// Manually restore data.
-	restored := &infrav1.OpenStackMachineTemplate{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachineTemplate{}
+	if err := Convert_v1alpha4_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(r, mid, nil); err != nil {
 		return err
 	}
+	if ok {
+		// Hand the hub data on to the conversion of v1alpha6
+		if err := utilconversion.MarshalData(restored, mid); err != nil {
+			return err
+		}
+	}
+	if err := mid.ConvertTo(dst); err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	dst.Spec.Template.Spec.Region = restored.Spec.Template.Spec.Region
 
 	return nil
 }
@@ -190,7 +284,11 @@ func (r *OpenStackMachineTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 func (r *OpenStackMachineTemplate) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachineTemplate)
 
-	if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha4_OpenStackMachineTemplate(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackMachineTemplate{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha4_OpenStackMachineTemplate(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -207,16 +305,27 @@ var _ ctrlconversion.Convertible = &OpenStackMachineTemplateList{}
 func (r *OpenStackMachineTemplateList) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachineTemplateList)
 
-	return Convert_v1alpha4_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineTemplateList(r, dst, nil)
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachineTemplateList{}
+	if err := Convert_v1alpha4_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineTemplateList(r, mid, nil); err != nil {
+		return err
+	}
+
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackMachineTemplateList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachineTemplateList)
 
-	return Convert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha4_OpenStackMachineTemplateList(src, r, nil)
+	mid := &infrav1alpha6.OpenStackMachineTemplateList{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+
+	return Convert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha4_OpenStackMachineTemplateList(mid, r, nil)
 }
 
-func Convert_v1alpha4_SubnetFilter_To_v1alpha6_SubnetFilter(in *SubnetFilter, out *infrav1.SubnetFilter, s conversion.Scope) error {
+func Convert_v1alpha4_SubnetFilter_To_v1alpha6_SubnetFilter(in *SubnetFilter, out *infrav1alpha6.SubnetFilter, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Description = in.Description
 	if in.ProjectID != "" {
@@ -237,12 +346,12 @@ func Convert_v1alpha4_SubnetFilter_To_v1alpha6_SubnetFilter(in *SubnetFilter, ou
 	return nil
 }
 
-func Convert_v1alpha6_SubnetFilter_To_v1alpha4_SubnetFilter(in *infrav1.SubnetFilter, out *SubnetFilter, s conversion.Scope) error {
+func Convert_v1alpha6_SubnetFilter_To_v1alpha4_SubnetFilter(in *infrav1alpha6.SubnetFilter, out *SubnetFilter, s conversion.Scope) error {
 	out.TenantID = in.ProjectID
 	return autoConvert_v1alpha6_SubnetFilter_To_v1alpha4_SubnetFilter(in, out, s)
 }
 
-func Convert_v1alpha4_Filter_To_v1alpha6_NetworkFilter(in *Filter, out *infrav1.NetworkFilter, s conversion.Scope) error {
+func Convert_v1alpha4_Filter_To_v1alpha6_NetworkFilter(in *Filter, out *infrav1alpha6.NetworkFilter, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Description = in.Description
 	if in.ProjectID != "" {
@@ -258,7 +367,7 @@ func Convert_v1alpha4_Filter_To_v1alpha6_NetworkFilter(in *Filter, out *infrav1.
 	return nil
 }
 
-func Convert_v1alpha6_NetworkFilter_To_v1alpha4_Filter(in *infrav1.NetworkFilter, out *Filter, s conversion.Scope) error {
+func Convert_v1alpha6_NetworkFilter_To_v1alpha4_Filter(in *infrav1alpha6.NetworkFilter, out *Filter, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Description = in.Description
 	out.ProjectID = in.ProjectID
@@ -271,18 +380,18 @@ func Convert_v1alpha6_NetworkFilter_To_v1alpha4_Filter(in *infrav1.NetworkFilter
 	return nil
 }
 
-func Convert_v1alpha4_PortOpts_To_v1alpha6_PortOpts(in *PortOpts, out *infrav1.PortOpts, s conversion.Scope) error {
+func Convert_v1alpha4_PortOpts_To_v1alpha6_PortOpts(in *PortOpts, out *infrav1alpha6.PortOpts, s conversion.Scope) error {
 	err := autoConvert_v1alpha4_PortOpts_To_v1alpha6_PortOpts(in, out, s)
 	if err != nil {
 		return err
 	}
 	if in.NetworkID != "" {
-		out.Network = &infrav1.NetworkFilter{ID: in.NetworkID}
+		out.Network = &infrav1alpha6.NetworkFilter{ID: in.NetworkID}
 	}
 	return nil
 }
 
-func Convert_v1alpha6_PortOpts_To_v1alpha4_PortOpts(in *infrav1.PortOpts, out *PortOpts, s conversion.Scope) error {
+func Convert_v1alpha6_PortOpts_To_v1alpha4_PortOpts(in *infrav1alpha6.PortOpts, out *PortOpts, s conversion.Scope) error {
 	err := autoConvert_v1alpha6_PortOpts_To_v1alpha4_PortOpts(in, out, s)
 	if err != nil {
 		return err
@@ -290,11 +399,22 @@ func Convert_v1alpha6_PortOpts_To_v1alpha4_PortOpts(in *infrav1.PortOpts, out *P
 	if in.Network != nil {
 		out.NetworkID = in.Network.ID
 	}
+	// Security groups referenced by UUID can be kept in SecurityGroups,
+	// other filters are not supported in v1alpha4
+	for _, securityGroupParam := range in.SecurityGroupFilters {
+		if securityGroupParam.UUID == "" {
+			continue
+		}
+		if out.SecurityGroups == nil {
+			out.SecurityGroups = &[]string{}
+		}
+		*out.SecurityGroups = append(*out.SecurityGroups, securityGroupParam.UUID)
+	}
 	return nil
 }
 
-func Convert_Slice_v1alpha4_Network_To_Slice_v1alpha6_Network(in *[]Network, out *[]infrav1.Network, s conversion.Scope) error {
-	*out = make([]infrav1.Network, len(*in))
+func Convert_Slice_v1alpha4_Network_To_Slice_v1alpha6_Network(in *[]Network, out *[]infrav1alpha6.Network, s conversion.Scope) error {
+	*out = make([]infrav1alpha6.Network, len(*in))
 	for i := range *in {
 		if err := Convert_v1alpha4_Network_To_v1alpha6_Network(&(*in)[i], &(*out)[i], s); err != nil {
 			return err
@@ -304,7 +424,7 @@ func Convert_Slice_v1alpha4_Network_To_Slice_v1alpha6_Network(in *[]Network, out
 	return nil
 }
 
-func Convert_Slice_v1alpha6_Network_To_Slice_v1alpha4_Network(in *[]infrav1.Network, out *[]Network, s conversion.Scope) error {
+func Convert_Slice_v1alpha6_Network_To_Slice_v1alpha4_Network(in *[]infrav1alpha6.Network, out *[]Network, s conversion.Scope) error {
 	*out = make([]Network, len(*in))
 	for i := range *in {
 		if err := Convert_v1alpha6_Network_To_v1alpha4_Network(&(*in)[i], &(*out)[i], s); err != nil {
@@ -314,18 +434,18 @@ func Convert_Slice_v1alpha6_Network_To_Slice_v1alpha4_Network(in *[]infrav1.Netw
 	return nil
 }
 
-func Convert_v1alpha4_FixedIP_To_v1alpha6_FixedIP(in *FixedIP, out *infrav1.FixedIP, s conversion.Scope) error {
+func Convert_v1alpha4_FixedIP_To_v1alpha6_FixedIP(in *FixedIP, out *infrav1alpha6.FixedIP, s conversion.Scope) error {
 	err := autoConvert_v1alpha4_FixedIP_To_v1alpha6_FixedIP(in, out, s)
 	if err != nil {
 		return err
 	}
 	if in.SubnetID != "" {
-		out.Subnet = &infrav1.SubnetFilter{ID: in.SubnetID}
+		out.Subnet = &infrav1alpha6.SubnetFilter{ID: in.SubnetID}
 	}
 	return nil
 }
 
-func Convert_v1alpha6_FixedIP_To_v1alpha4_FixedIP(in *infrav1.FixedIP, out *FixedIP, s conversion.Scope) error {
+func Convert_v1alpha6_FixedIP_To_v1alpha4_FixedIP(in *infrav1alpha6.FixedIP, out *FixedIP, s conversion.Scope) error {
 	err := autoConvert_v1alpha6_FixedIP_To_v1alpha4_FixedIP(in, out, s)
 	if err != nil {
 		return err
@@ -343,18 +463,18 @@ func Convert_v1alpha6_FixedIP_To_v1alpha4_FixedIP(in *infrav1.FixedIP, out *Fixe
  * - SourceUUID is removed in v1alpha6, comes from the parent context
  */
 
-func Convert_v1alpha6_RootVolume_To_v1alpha4_RootVolume(in *infrav1.RootVolume, out *RootVolume, s conversion.Scope) error {
+func Convert_v1alpha6_RootVolume_To_v1alpha4_RootVolume(in *infrav1alpha6.RootVolume, out *RootVolume, s conversion.Scope) error {
 	out.DeviceType = "disk"
 	out.SourceType = "image"
 	// SourceUUID needs to come from the parent context
 	return autoConvert_v1alpha6_RootVolume_To_v1alpha4_RootVolume(in, out, s)
 }
 
-func Convert_v1alpha4_RootVolume_To_v1alpha6_RootVolume(in *RootVolume, out *infrav1.RootVolume, s conversion.Scope) error {
+func Convert_v1alpha4_RootVolume_To_v1alpha6_RootVolume(in *RootVolume, out *infrav1alpha6.RootVolume, s conversion.Scope) error {
 	return autoConvert_v1alpha4_RootVolume_To_v1alpha6_RootVolume(in, out, s)
 }
 
-func Convert_v1alpha4_Instance_To_v1alpha6_Instance(in *Instance, out *infrav1.Instance, s conversion.Scope) error {
+func Convert_v1alpha4_Instance_To_v1alpha6_Instance(in *Instance, out *infrav1alpha6.Instance, s conversion.Scope) error {
 	if err := autoConvert_v1alpha4_Instance_To_v1alpha6_Instance(in, out, s); err != nil {
 		return err
 	}
@@ -364,7 +484,7 @@ func Convert_v1alpha4_Instance_To_v1alpha6_Instance(in *Instance, out *infrav1.I
 	return nil
 }
 
-func Convert_v1alpha6_Instance_To_v1alpha4_Instance(in *infrav1.Instance, out *Instance, s conversion.Scope) error {
+func Convert_v1alpha6_Instance_To_v1alpha4_Instance(in *infrav1alpha6.Instance, out *Instance, s conversion.Scope) error {
 	if err := autoConvert_v1alpha6_Instance_To_v1alpha4_Instance(in, out, s); err != nil {
 		return err
 	}
@@ -375,7 +495,7 @@ func Convert_v1alpha6_Instance_To_v1alpha4_Instance(in *infrav1.Instance, out *I
 	return nil
 }
 
-func Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(in *OpenStackMachineSpec, out *infrav1.OpenStackMachineSpec, s conversion.Scope) error {
+func Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(in *OpenStackMachineSpec, out *infrav1alpha6.OpenStackMachineSpec, s conversion.Scope) error {
 	if err := autoConvert_v1alpha4_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(in, out, s); err != nil {
 		return err
 	}
@@ -385,7 +505,7 @@ func Convert_v1alpha4_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(in *
 	return nil
 }
 
-func Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(in *infrav1.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
+func Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(in *infrav1alpha6.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
 	if err := autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(in, out, s); err != nil {
 		return err
 	}
@@ -396,10 +516,15 @@ func Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(in *
 	return nil
 }
 
-func Convert_v1alpha6_Router_To_v1alpha4_Router(in *infrav1.Router, out *Router, s conversion.Scope) error {
+func Convert_v1alpha6_Router_To_v1alpha4_Router(in *infrav1alpha6.Router, out *Router, s conversion.Scope) error {
 	return autoConvert_v1alpha6_Router_To_v1alpha4_Router(in, out, s)
 }
 
-func Convert_v1alpha6_LoadBalancer_To_v1alpha4_LoadBalancer(in *infrav1.LoadBalancer, out *LoadBalancer, s conversion.Scope) error {
+func Convert_v1alpha6_LoadBalancer_To_v1alpha4_LoadBalancer(in *infrav1alpha6.LoadBalancer, out *LoadBalancer, s conversion.Scope) error {
 	return autoConvert_v1alpha6_LoadBalancer_To_v1alpha4_LoadBalancer(in, out, s)
 }
+
+func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in *infrav1alpha6.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
+	// Conditions and DryRunPlan have no equivalent in v1alpha4
+	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in, out, s)
+}
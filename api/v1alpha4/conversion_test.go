@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha4
 
 import (
+	"strings"
 	"testing"
 
 	fuzz "github.com/google/gofuzz"
@@ -28,7 +29,7 @@ import (
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 )
 
 func TestConvertTo(t *testing.T) {
@@ -173,7 +174,7 @@ func TestConvertFrom(t *testing.T) {
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"allowAllInClusterTraffic\":false,\"apiServerLoadBalancer\":{\"additionalPorts\":[80,443],\"enabled\":true},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"managedSecurityGroups\":false,\"network\":{},\"subnet\":{}},\"status\":{\"ready\":false}}",
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"apiServerLoadBalancer\":{\"additionalPorts\":[80,443],\"enabled\":true},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"network\":{},\"subnet\":{}},\"status\":{\"ready\":false}}",
 					},
 				},
 			},
@@ -188,7 +189,7 @@ func TestConvertFrom(t *testing.T) {
 				Spec: OpenStackClusterTemplateSpec{},
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"template\":{\"spec\":{\"allowAllInClusterTraffic\":false,\"apiServerLoadBalancer\":{},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"managedSecurityGroups\":false,\"network\":{},\"subnet\":{}}}}}",
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"template\":{\"metadata\":{},\"spec\":{\"apiServerLoadBalancer\":{},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"network\":{},\"subnet\":{}}}}}",
 					},
 				},
 			},
@@ -221,6 +222,11 @@ func TestFuzzyConversion(t *testing.T) {
 				if v1alpha4Cluster.Spec.Bastion != nil {
 					v1alpha4Cluster.Spec.Bastion.Instance.Image = ""
 				}
+
+				// AllowAllInClusterTraffic is only used with managed security groups
+				if !v1alpha4Cluster.Spec.ManagedSecurityGroups {
+					v1alpha4Cluster.Spec.AllowAllInClusterTraffic = false
+				}
 			},
 			func(v1alpha3SubnetFilter *SubnetFilter, c fuzz.Continue) {
 				c.FuzzNoCustom(v1alpha3SubnetFilter)
@@ -290,129 +296,201 @@ func TestFuzzyConversion(t *testing.T) {
 				if v1alpha4ClusterTemplate.Spec.Template.Spec.Bastion != nil {
 					v1alpha4ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Image = ""
 				}
+
+				if !v1alpha4ClusterTemplate.Spec.Template.Spec.ManagedSecurityGroups {
+					v1alpha4ClusterTemplate.Spec.Template.Spec.AllowAllInClusterTraffic = false
+				}
+			},
+
+			func(v1alpha4SecurityGroupParam *SecurityGroupParam, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha4SecurityGroupParam)
+
+				// The filter is not used when a UUID is given, and its
+				// name is replaced by the name of the parameter
+				if v1alpha4SecurityGroupParam.UUID != "" {
+					v1alpha4SecurityGroupParam.Name = ""
+					v1alpha4SecurityGroupParam.Filter = SecurityGroupFilter{}
+				}
+				v1alpha4SecurityGroupParam.Filter.Name = ""
+				v1alpha4SecurityGroupParam.Filter.ID = ""
+			},
+			func(v1alpha4PortOpts *PortOpts, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha4PortOpts)
+
+				// Empty security group UUIDs and an empty list of security
+				// groups are not preserved
+				if v1alpha4PortOpts.SecurityGroups != nil {
+					var securityGroups []string
+					for _, securityGroup := range *v1alpha4PortOpts.SecurityGroups {
+						if securityGroup != "" {
+							securityGroups = append(securityGroups, securityGroup)
+						}
+					}
+					v1alpha4PortOpts.SecurityGroups = nil
+					if len(securityGroups) > 0 {
+						v1alpha4PortOpts.SecurityGroups = &securityGroups
+					}
+				}
+			},
+			func(v1alpha4SecurityGroupFilter *SecurityGroupFilter, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha4SecurityGroupFilter)
+
+				v1alpha4SecurityGroupFilter.Limit = 0
+				v1alpha4SecurityGroupFilter.Marker = ""
+				v1alpha4SecurityGroupFilter.SortKey = ""
+				v1alpha4SecurityGroupFilter.SortDir = ""
+
+				// TenantID and ProjectID are the same thing, so TenantID is removed in infrav1
+				v1alpha4SecurityGroupFilter.TenantID = ""
 			},
 
 			// Don't test hub-spoke-hub conversion of infrav1 fields which are not in v1alpha4
-			func(v1alpha6PortOpts *infrav1.PortOpts, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6PortOpts)
+			func(v1alpha7PortOpts *infrav1.PortOpts, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7PortOpts)
 
 				// v1alpha4 PortOpts has only NetworkID, so only Network.ID filter can be translated
-				if v1alpha6PortOpts.Network != nil {
-					v1alpha6PortOpts.Network = &infrav1.NetworkFilter{ID: v1alpha6PortOpts.Network.ID}
+				if v1alpha7PortOpts.Network != nil {
+					v1alpha7PortOpts.Network = &infrav1.NetworkFilter{ID: v1alpha7PortOpts.Network.ID}
 
 					// We have no way to differentiate between a nil NetworkFilter and an
 					// empty NetworkFilter after conversion because they both translate into an
 					// empty string in v1alpha4
-					if *v1alpha6PortOpts.Network == (infrav1.NetworkFilter{}) {
-						v1alpha6PortOpts.Network = nil
+					if v1alpha7PortOpts.Network.IsEmpty() {
+						v1alpha7PortOpts.Network = nil
+					}
+				}
+
+				// Only security groups given by ID can be translated
+				var securityGroupFilters []infrav1.SecurityGroupFilter
+				for _, securityGroupFilter := range v1alpha7PortOpts.SecurityGroupFilters {
+					if securityGroupFilter.IsIDOnly() {
+						securityGroupFilters = append(securityGroupFilters, securityGroupFilter)
 					}
 				}
-				v1alpha6PortOpts.SecurityGroupFilters = nil
+				v1alpha7PortOpts.SecurityGroupFilters = securityGroupFilters
+
+				// ID, FixedIPPerSubnet and ExtraDHCPOpts are not restored in the port options of
+				// networks in the status
+				v1alpha7PortOpts.ID = ""
+				v1alpha7PortOpts.FixedIPPerSubnet = false
+				v1alpha7PortOpts.ExtraDHCPOpts = nil
 			},
-			func(v1alpha6FixedIP *infrav1.FixedIP, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6FixedIP)
+			func(v1alpha7FixedIP *infrav1.FixedIP, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7FixedIP)
 
 				// v1alpha4 only supports subnet specified by ID
-				if v1alpha6FixedIP.Subnet != nil {
-					v1alpha6FixedIP.Subnet = &infrav1.SubnetFilter{ID: v1alpha6FixedIP.Subnet.ID}
+				if v1alpha7FixedIP.Subnet != nil {
+					v1alpha7FixedIP.Subnet = &infrav1.SubnetFilter{ID: v1alpha7FixedIP.Subnet.ID}
 
 					// We have no way to differentiate between a nil SubnetFilter and an
 					// empty SubnetFilter after conversion because they both translate into an
 					// empty string in v1alpha4
-					if *v1alpha6FixedIP.Subnet == (infrav1.SubnetFilter{}) {
-						v1alpha6FixedIP.Subnet = nil
+					if v1alpha7FixedIP.Subnet.IsEmpty() {
+						v1alpha7FixedIP.Subnet = nil
 					}
 				}
 			},
-			func(v1alpha6Cluster *infrav1.OpenStackCluster, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6Cluster)
+			func(v1alpha7Cluster *infrav1.OpenStackCluster, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Cluster)
 
-				v1alpha6Cluster.ObjectMeta.Annotations = map[string]string{}
+				v1alpha7Cluster.ObjectMeta.Annotations = map[string]string{}
 
-				v1alpha6Cluster.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
+				v1alpha7Cluster.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
 
-				v1alpha6Cluster.Spec.ControlPlaneOmitAvailabilityZone = false
+				v1alpha7Cluster.Spec.ControlPlaneOmitAvailabilityZone = false
 
-				if v1alpha6Cluster.Spec.Bastion != nil {
-					v1alpha6Cluster.Spec.Bastion.Instance.Image = ""
+				if v1alpha7Cluster.Spec.Bastion != nil {
+					v1alpha7Cluster.Spec.Bastion.Instance.Image = ""
+					v1alpha7Cluster.Spec.Bastion.Instance.Region = ""
 				}
 
-				if v1alpha6Cluster.Status.Bastion != nil {
-					v1alpha6Cluster.Status.Bastion.ImageUUID = ""
-					v1alpha6Cluster.Status.Bastion.Image = ""
-					v1alpha6Cluster.Status.Bastion.Networks = nil
+				if v1alpha7Cluster.Status.Bastion != nil {
+					v1alpha7Cluster.Status.Bastion.ImageUUID = ""
+					v1alpha7Cluster.Status.Bastion.Image = ""
+					v1alpha7Cluster.Status.Bastion.Networks = nil
 				}
 
-				if v1alpha6Cluster.Status.Network != nil {
-					if v1alpha6Cluster.Status.Network.APIServerLoadBalancer != nil {
-						v1alpha6Cluster.Status.Network.APIServerLoadBalancer.AllowedCIDRs = nil
+				if v1alpha7Cluster.Status.Network != nil {
+					if v1alpha7Cluster.Status.Network.APIServerLoadBalancer != nil {
+						v1alpha7Cluster.Status.Network.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
-					if v1alpha6Cluster.Status.Network.Router != nil {
-						v1alpha6Cluster.Status.Network.Router.IPs = []string{}
+					if v1alpha7Cluster.Status.Network.Router != nil {
+						v1alpha7Cluster.Status.Network.Router.IPs = []string{}
 					}
 				}
 
-				if v1alpha6Cluster.Status.ExternalNetwork != nil {
-					if v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer != nil {
-						v1alpha6Cluster.Status.ExternalNetwork.APIServerLoadBalancer.AllowedCIDRs = nil
+				if v1alpha7Cluster.Status.ExternalNetwork != nil {
+					if v1alpha7Cluster.Status.ExternalNetwork.APIServerLoadBalancer != nil {
+						v1alpha7Cluster.Status.ExternalNetwork.APIServerLoadBalancer.AllowedCIDRs = nil
 					}
-					if v1alpha6Cluster.Status.ExternalNetwork.Router != nil {
-						v1alpha6Cluster.Status.ExternalNetwork.Router.IPs = []string{}
+					if v1alpha7Cluster.Status.ExternalNetwork.Router != nil {
+						v1alpha7Cluster.Status.ExternalNetwork.Router.IPs = []string{}
 					}
 				}
 			},
-			func(v1alpha6Machine *infrav1.OpenStackMachine, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6Machine)
+			func(v1alpha7Machine *infrav1.OpenStackMachine, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Machine)
 
-				v1alpha6Machine.ObjectMeta.Annotations = map[string]string{}
+				v1alpha7Machine.ObjectMeta.Annotations = map[string]string{}
 
 				// In v1alpha4 boot from volume only supports
 				// image by UUID, and boot from local only
 				// suppots image by name
-				if v1alpha6Machine.Spec.RootVolume != nil && v1alpha6Machine.Spec.RootVolume.Size > 0 {
-					v1alpha6Machine.Spec.Image = ""
+				if v1alpha7Machine.Spec.RootVolume != nil && v1alpha7Machine.Spec.RootVolume.Size > 0 {
+					v1alpha7Machine.Spec.Image = ""
 				} else {
-					v1alpha6Machine.Spec.ImageUUID = ""
+					v1alpha7Machine.Spec.ImageUUID = ""
 				}
 			},
-			func(v1alpha6MachineTemplate *infrav1.OpenStackMachineTemplate, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6MachineTemplate)
+			func(v1alpha7MachineTemplate *infrav1.OpenStackMachineTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7MachineTemplate)
 
-				v1alpha6MachineTemplate.ObjectMeta.Annotations = map[string]string{}
+				v1alpha7MachineTemplate.ObjectMeta.Annotations = map[string]string{}
 
-				v1alpha6MachineTemplate.Spec.Template.Spec.Image = ""
+				v1alpha7MachineTemplate.Spec.Template.Spec.Image = ""
 			},
-			func(v1alpha6Instance *infrav1.Instance, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6Instance)
+			func(v1alpha7Instance *infrav1.Instance, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Instance)
 
 				// In v1alpha4 boot from volume only supports
 				// image by UUID, and boot from local only
 				// suppots image by name
-				if v1alpha6Instance.RootVolume != nil && v1alpha6Instance.RootVolume.Size > 0 {
-					v1alpha6Instance.Image = ""
+				if v1alpha7Instance.RootVolume != nil && v1alpha7Instance.RootVolume.Size > 0 {
+					v1alpha7Instance.Image = ""
 				} else {
-					v1alpha6Instance.ImageUUID = ""
+					v1alpha7Instance.ImageUUID = ""
 				}
 			},
-			func(v1alpha6RootVolume *infrav1.RootVolume, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6RootVolume)
+			func(v1alpha7RootVolume *infrav1.RootVolume, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7RootVolume)
 
-				v1alpha6RootVolume.VolumeType = ""
-				v1alpha6RootVolume.AvailabilityZone = ""
+				v1alpha7RootVolume.VolumeType = ""
+				v1alpha7RootVolume.AvailabilityZone = ""
 			},
-			func(v1alpha6ClusterTemplate *infrav1.OpenStackClusterTemplate, c fuzz.Continue) {
-				c.FuzzNoCustom(v1alpha6ClusterTemplate)
+			func(v1alpha7ClusterTemplate *infrav1.OpenStackClusterTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7ClusterTemplate)
 
-				v1alpha6ClusterTemplate.ObjectMeta.Annotations = map[string]string{}
+				v1alpha7ClusterTemplate.ObjectMeta.Annotations = map[string]string{}
 
-				v1alpha6ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
+				v1alpha7ClusterTemplate.Spec.Template.Spec.APIServerLoadBalancer.AllowedCIDRs = nil
 
-				v1alpha6ClusterTemplate.Spec.Template.Spec.ControlPlaneOmitAvailabilityZone = false
+				v1alpha7ClusterTemplate.Spec.Template.Spec.ControlPlaneOmitAvailabilityZone = false
 
-				if v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion != nil {
-					v1alpha6ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Image = ""
+				if v1alpha7ClusterTemplate.Spec.Template.Spec.Bastion != nil {
+					v1alpha7ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Image = ""
+					v1alpha7ClusterTemplate.Spec.Template.Spec.Bastion.Instance.Region = ""
 				}
 			},
+			func(v1alpha7FilterByNeutronTags *infrav1.FilterByNeutronTags, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7FilterByNeutronTags)
+
+				// Tags are converted to comma separated lists, which cannot
+				// contain empty tags or tags with commas
+				v1alpha7FilterByNeutronTags.Tags = validNeutronTags(v1alpha7FilterByNeutronTags.Tags)
+				v1alpha7FilterByNeutronTags.TagsAny = validNeutronTags(v1alpha7FilterByNeutronTags.TagsAny)
+				v1alpha7FilterByNeutronTags.NotTags = validNeutronTags(v1alpha7FilterByNeutronTags.NotTags)
+				v1alpha7FilterByNeutronTags.NotTagsAny = validNeutronTags(v1alpha7FilterByNeutronTags.NotTagsAny)
+			},
 		}
 	}
 
@@ -444,3 +522,13 @@ func TestFuzzyConversion(t *testing.T) {
 		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
 	}))
 }
+
+func validNeutronTags(tags []infrav1.NeutronTag) []infrav1.NeutronTag {
+	var ret []infrav1.NeutronTag
+	for _, tag := range tags {
+		if tag != "" && !strings.Contains(string(tag), ",") {
+			ret = append(ret, tag)
+		}
+	}
+	return ret
+}
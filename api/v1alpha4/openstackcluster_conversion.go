@@ -23,7 +23,7 @@ import (
 	clusterv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1alpha6 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
 )
 
 // Convert_v1alpha4_APIEndpoint_To_v1beta1_APIEndpoint is an autogenerated conversion function.
@@ -36,14 +36,14 @@ func Convert_v1beta1_APIEndpoint_To_v1alpha4_APIEndpoint(in *clusterv1.APIEndpoi
 	return clusterv1alpha4.Convert_v1beta1_APIEndpoint_To_v1alpha4_APIEndpoint(in, out, s)
 }
 
-func Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(in *OpenStackClusterSpec, out *infrav1.OpenStackClusterSpec, s conversion.Scope) error {
+func Convert_v1alpha4_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(in *OpenStackClusterSpec, out *infrav1alpha6.OpenStackClusterSpec, s conversion.Scope) error {
 	out.APIServerLoadBalancer.Enabled = in.ManagedAPIServerLoadBalancer
 	out.APIServerLoadBalancer.AdditionalPorts = *(*[]int)(unsafe.Pointer(&in.APIServerLoadBalancerAdditionalPorts))
 
 	return autoConvert_v1alpha4_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(in, out, s)
 }
 
-func Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(in *infrav1.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
+func Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha4_OpenStackClusterSpec(in *infrav1alpha6.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
 	out.ManagedAPIServerLoadBalancer = in.APIServerLoadBalancer.Enabled
 	out.APIServerLoadBalancerAdditionalPorts = *(*[]int)(unsafe.Pointer(&in.APIServerLoadBalancer.AdditionalPorts))
 
@@ -120,11 +120,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackClusterTemplate)(nil), (*v1alpha6.OpenStackClusterTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha4_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(a.(*OpenStackClusterTemplate), b.(*v1alpha6.OpenStackClusterTemplate), scope)
 	}); err != nil {
@@ -390,6 +385,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha6.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(a.(*v1alpha6.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
 	}); err != nil {
@@ -988,14 +988,11 @@ func autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterSta
 	}
 	out.FailureReason = (*errors.ClusterStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.DryRunPlan requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in *v1alpha6.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha4_OpenStackClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha4_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(in *OpenStackClusterTemplate, out *v1alpha6.OpenStackClusterTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha4_OpenStackClusterTemplateSpec_To_v1alpha6_OpenStackClusterTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1262,6 +1259,7 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha4_OpenStackMachineSpec(
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
 	out.CloudName = in.CloudName
+	// WARNING: in.Region requires manual conversion: does not exist in peer-type
 	out.Flavor = in.Flavor
 	out.Image = in.Image
 	// WARNING: in.ImageUUID requires manual conversion: does not exist in peer-type
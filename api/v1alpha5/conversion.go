@@ -21,7 +21,8 @@ import (
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1alpha6 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 )
 
 var _ ctrlconversion.Convertible = &OpenStackCluster{}
@@ -29,15 +30,33 @@ var _ ctrlconversion.Convertible = &OpenStackCluster{}
 func (r *OpenStackCluster) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackCluster)
 
-	if err := Convert_v1alpha5_OpenStackCluster_To_v1alpha6_OpenStackCluster(r, dst, nil); err != nil {
+	// Manually restore data.
+	restored := &infrav1.OpenStackCluster{}
+	ok, err := utilconversion.UnmarshalData(r, restored)
+	if err != nil {
 		return err
 	}
 
-	// Manually restore data.
-	restored := &infrav1.OpenStackCluster{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackCluster{}
+	if err := Convert_v1alpha5_OpenStackCluster_To_v1alpha6_OpenStackCluster(r, mid, nil); err != nil {
 		return err
 	}
+	if ok {
+		// Hand the hub data on to the conversion of v1alpha6
+		if err := utilconversion.MarshalData(restored, mid); err != nil {
+			return err
+		}
+	}
+	if err := mid.ConvertTo(dst); err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.DryRunPlan = restored.Status.DryRunPlan
 
 	return nil
 }
@@ -45,7 +64,11 @@ func (r *OpenStackCluster) ConvertTo(dstRaw ctrlconversion.Hub) error {
 func (r *OpenStackCluster) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackCluster)
 
-	if err := Convert_v1alpha6_OpenStackCluster_To_v1alpha5_OpenStackCluster(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackCluster{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackCluster_To_v1alpha5_OpenStackCluster(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -62,13 +85,24 @@ var _ ctrlconversion.Convertible = &OpenStackClusterList{}
 func (r *OpenStackClusterList) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackClusterList)
 
-	return Convert_v1alpha5_OpenStackClusterList_To_v1alpha6_OpenStackClusterList(r, dst, nil)
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackClusterList{}
+	if err := Convert_v1alpha5_OpenStackClusterList_To_v1alpha6_OpenStackClusterList(r, mid, nil); err != nil {
+		return err
+	}
+
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackClusterList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackClusterList)
 
-	return Convert_v1alpha6_OpenStackClusterList_To_v1alpha5_OpenStackClusterList(src, r, nil)
+	mid := &infrav1alpha6.OpenStackClusterList{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+
+	return Convert_v1alpha6_OpenStackClusterList_To_v1alpha5_OpenStackClusterList(mid, r, nil)
 }
 
 var _ ctrlconversion.Convertible = &OpenStackClusterTemplate{}
@@ -76,23 +110,23 @@ var _ ctrlconversion.Convertible = &OpenStackClusterTemplate{}
 func (r *OpenStackClusterTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackClusterTemplate)
 
-	if err := Convert_v1alpha5_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(r, dst, nil); err != nil {
-		return err
-	}
-
-	// Manually restore data.
-	restored := &infrav1.OpenStackClusterTemplate{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackClusterTemplate{}
+	if err := Convert_v1alpha5_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(r, mid, nil); err != nil {
 		return err
 	}
 
-	return nil
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackClusterTemplate) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackClusterTemplate)
 
-	if err := Convert_v1alpha6_OpenStackClusterTemplate_To_v1alpha5_OpenStackClusterTemplate(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackClusterTemplate{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackClusterTemplate_To_v1alpha5_OpenStackClusterTemplate(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -109,15 +143,32 @@ var _ ctrlconversion.Convertible = &OpenStackMachine{}
 func (r *OpenStackMachine) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachine)
 
-	if err := Convert_v1alpha5_OpenStackMachine_To_v1alpha6_OpenStackMachine(r, dst, nil); err != nil {
+	// Manually restore data.
+	restored := &infrav1.OpenStackMachine{}
+	ok, err := utilconversion.UnmarshalData(r, restored)
+	if err != nil {
 		return err
 	}
 
-	// Manually restore data.
-	restored := &infrav1.OpenStackMachine{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachine{}
+	if err := Convert_v1alpha5_OpenStackMachine_To_v1alpha6_OpenStackMachine(r, mid, nil); err != nil {
+		return err
+	}
+	if ok {
+		// Hand the hub data on to the conversion of v1alpha6
+		if err := utilconversion.MarshalData(restored, mid); err != nil {
+			return err
+		}
+	}
+	if err := mid.ConvertTo(dst); err != nil {
 		return err
 	}
+	if !ok {
+		return nil
+	}
+
+	dst.Spec.Region = restored.Spec.Region
 
 	return nil
 }
@@ -125,7 +176,11 @@ func (r *OpenStackMachine) ConvertTo(dstRaw ctrlconversion.Hub) error {
 func (r *OpenStackMachine) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachine)
 
-	if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha5_OpenStackMachine(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackMachine{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha5_OpenStackMachine(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -142,13 +197,24 @@ var _ ctrlconversion.Convertible = &OpenStackMachineList{}
 func (r *OpenStackMachineList) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachineList)
 
-	return Convert_v1alpha5_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(r, dst, nil)
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachineList{}
+	if err := Convert_v1alpha5_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(r, mid, nil); err != nil {
+		return err
+	}
+
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackMachineList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachineList)
 
-	return Convert_v1alpha6_OpenStackMachineList_To_v1alpha5_OpenStackMachineList(src, r, nil)
+	mid := &infrav1alpha6.OpenStackMachineList{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+
+	return Convert_v1alpha6_OpenStackMachineList_To_v1alpha5_OpenStackMachineList(mid, r, nil)
 }
 
 var _ ctrlconversion.Convertible = &OpenStackMachineTemplate{}
@@ -156,15 +222,32 @@ var _ ctrlconversion.Convertible = &OpenStackMachineTemplate{}
 func (r *OpenStackMachineTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachineTemplate)
 
-	if err := Convert_v1alpha5_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(r, dst, nil); err != nil {
+	// Manually restore data.
+	restored := &infrav1.OpenStackMachineTemplate{}
+	ok, err := utilconversion.UnmarshalData(r, restored)
+	if err != nil {
 		return err
 	}
 
-	// Manually restore data.
-	restored := &infrav1.OpenStackMachineTemplate{}
-	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachineTemplate{}
+	if err := Convert_v1alpha5_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(r, mid, nil); err != nil {
 		return err
 	}
+	if ok {
+		// Hand the hub data on to the conversion of v1alpha6
+		if err := utilconversion.MarshalData(restored, mid); err != nil {
+			return err
+		}
+	}
+	if err := mid.ConvertTo(dst); err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	dst.Spec.Template.Spec.Region = restored.Spec.Template.Spec.Region
 
 	return nil
 }
@@ -172,7 +255,11 @@ func (r *OpenStackMachineTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
 func (r *OpenStackMachineTemplate) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachineTemplate)
 
-	if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha5_OpenStackMachineTemplate(src, r, nil); err != nil {
+	mid := &infrav1alpha6.OpenStackMachineTemplate{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha5_OpenStackMachineTemplate(mid, r, nil); err != nil {
 		return err
 	}
 
@@ -189,16 +276,37 @@ var _ ctrlconversion.Convertible = &OpenStackMachineTemplateList{}
 func (r *OpenStackMachineTemplateList) ConvertTo(dstRaw ctrlconversion.Hub) error {
 	dst := dstRaw.(*infrav1.OpenStackMachineTemplateList)
 
-	return Convert_v1alpha5_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineTemplateList(r, dst, nil)
+	// Convert through v1alpha6, which converts to the hub
+	mid := &infrav1alpha6.OpenStackMachineTemplateList{}
+	if err := Convert_v1alpha5_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineTemplateList(r, mid, nil); err != nil {
+		return err
+	}
+
+	return mid.ConvertTo(dst)
 }
 
 func (r *OpenStackMachineTemplateList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
 	src := srcRaw.(*infrav1.OpenStackMachineTemplateList)
 
-	return Convert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha5_OpenStackMachineTemplateList(src, r, nil)
+	mid := &infrav1alpha6.OpenStackMachineTemplateList{}
+	if err := mid.ConvertFrom(src); err != nil {
+		return err
+	}
+
+	return Convert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha5_OpenStackMachineTemplateList(mid, r, nil)
 }
 
-func Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(in *infrav1.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
+func Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(in *infrav1alpha6.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
 	// Our new flag has no equivalent in v1alpha5
 	return autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(in, out, s)
 }
+
+func Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in *infrav1alpha6.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
+	// Region has no equivalent in v1alpha5
+	return autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in, out, s)
+}
+
+func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in *infrav1alpha6.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
+	// Conditions and DryRunPlan have no equivalent in v1alpha5
+	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in, out, s)
+}
@@ -169,11 +169,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackClusterTemplate)(nil), (*v1alpha6.OpenStackClusterTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(a.(*OpenStackClusterTemplate), b.(*v1alpha6.OpenStackClusterTemplate), scope)
 	}); err != nil {
@@ -249,11 +244,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha6.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(a.(*v1alpha6.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*OpenStackMachineStatus)(nil), (*v1alpha6.OpenStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha5_OpenStackMachineStatus_To_v1alpha6_OpenStackMachineStatus(a.(*OpenStackMachineStatus), b.(*v1alpha6.OpenStackMachineStatus), scope)
 	}); err != nil {
@@ -409,6 +399,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackClusterStatus)(nil), (*OpenStackClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(a.(*v1alpha6.OpenStackClusterStatus), b.(*OpenStackClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha6.OpenStackMachineSpec)(nil), (*OpenStackMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(a.(*v1alpha6.OpenStackMachineSpec), b.(*OpenStackMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -815,7 +815,15 @@ func autoConvert_v1alpha5_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(
 	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
-	out.Bastion = (*v1alpha6.Bastion)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(v1alpha6.Bastion)
+		if err := Convert_v1alpha5_Bastion_To_v1alpha6_Bastion(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
 	out.IdentityRef = (*v1alpha6.OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
 	return nil
 }
@@ -851,7 +859,15 @@ func autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha5_OpenStackClusterSpec(
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	out.ControlPlaneAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.ControlPlaneAvailabilityZones))
 	// WARNING: in.ControlPlaneOmitAvailabilityZone requires manual conversion: does not exist in peer-type
-	out.Bastion = (*Bastion)(unsafe.Pointer(in.Bastion))
+	if in.Bastion != nil {
+		in, out := &in.Bastion, &out.Bastion
+		*out = new(Bastion)
+		if err := Convert_v1alpha6_Bastion_To_v1alpha5_Bastion(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bastion = nil
+	}
 	out.IdentityRef = (*OpenStackIdentityReference)(unsafe.Pointer(in.IdentityRef))
 	return nil
 }
@@ -886,14 +902,11 @@ func autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterSta
 	out.Bastion = (*Instance)(unsafe.Pointer(in.Bastion))
 	out.FailureReason = (*errors.ClusterStatusError)(unsafe.Pointer(in.FailureReason))
 	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.DryRunPlan requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in *v1alpha6.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackClusterStatus_To_v1alpha5_OpenStackClusterStatus(in, out, s)
-}
-
 func autoConvert_v1alpha5_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(in *OpenStackClusterTemplate, out *v1alpha6.OpenStackClusterTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha5_OpenStackClusterTemplateSpec_To_v1alpha6_OpenStackClusterTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1066,7 +1079,17 @@ func Convert_v1alpha6_OpenStackMachine_To_v1alpha5_OpenStackMachine(in *v1alpha6
 
 func autoConvert_v1alpha5_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(in *OpenStackMachineList, out *v1alpha6.OpenStackMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha6.OpenStackMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha6.OpenStackMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_OpenStackMachine_To_v1alpha6_OpenStackMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -1077,7 +1100,17 @@ func Convert_v1alpha5_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(in *
 
 func autoConvert_v1alpha6_OpenStackMachineList_To_v1alpha5_OpenStackMachineList(in *v1alpha6.OpenStackMachineList, out *OpenStackMachineList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]OpenStackMachine)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha5_OpenStackMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -1118,6 +1151,7 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
 	out.CloudName = in.CloudName
+	// WARNING: in.Region requires manual conversion: does not exist in peer-type
 	out.Flavor = in.Flavor
 	out.Image = in.Image
 	out.ImageUUID = in.ImageUUID
@@ -1137,11 +1171,6 @@ func autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(
 	return nil
 }
 
-// Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec is an autogenerated conversion function.
-func Convert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in *v1alpha6.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha6_OpenStackMachineSpec_To_v1alpha5_OpenStackMachineSpec(in, out, s)
-}
-
 func autoConvert_v1alpha5_OpenStackMachineStatus_To_v1alpha6_OpenStackMachineStatus(in *OpenStackMachineStatus, out *v1alpha6.OpenStackMachineStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
@@ -1200,7 +1229,17 @@ func Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha5_OpenStackMachineTempl
 
 func autoConvert_v1alpha5_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineTemplateList(in *OpenStackMachineTemplateList, out *v1alpha6.OpenStackMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha6.OpenStackMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha6.OpenStackMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha5_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -1211,7 +1250,17 @@ func Convert_v1alpha5_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineT
 
 func autoConvert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha5_OpenStackMachineTemplateList(in *v1alpha6.OpenStackMachineTemplateList, out *OpenStackMachineTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]OpenStackMachineTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenStackMachineTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha5_OpenStackMachineTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
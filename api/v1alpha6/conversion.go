@@ -16,26 +16,616 @@ limitations under the License.
 
 package v1alpha6
 
-// Hub marks OpenStackCluster as a conversion hub.
-func (*OpenStackCluster) Hub() {}
+import (
+	"strings"
 
-// Hub marks OpenStackClusterList as a conversion hub.
-func (*OpenStackClusterList) Hub() {}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
 
-// Hub marks OpenStackClusterTemplate as a conversion hub.
-func (*OpenStackClusterTemplate) Hub() {}
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
 
-// Hub marks OpenStackClusterTemplateList as a conversion hub.
-func (*OpenStackClusterTemplateList) Hub() {}
+var _ ctrlconversion.Convertible = &OpenStackCluster{}
 
-// Hub marks OpenStackMachine as a conversion hub.
-func (*OpenStackMachine) Hub() {}
+func (r *OpenStackCluster) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackCluster)
 
-// Hub marks OpenStackMachineList as a conversion hub.
-func (*OpenStackMachineList) Hub() {}
+	if err := Convert_v1alpha6_OpenStackCluster_To_v1alpha7_OpenStackCluster(r, dst, nil); err != nil {
+		return err
+	}
+	// The conversion report only describes the conversion from the hub
+	defer dropConversionReport(dst)
 
-// Hub marks OpenStackMachineTemplate as a conversion hub.
-func (*OpenStackMachineTemplate) Hub() {}
+	// Manually restore data.
+	restored := &infrav1.OpenStackCluster{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
 
-// Hub marks OpenStackMachineTemplateList as a conversion hub.
-func (*OpenStackMachineTemplateList) Hub() {}
+	dst.Spec.ExternalAPIServerEndpoint = restored.Spec.ExternalAPIServerEndpoint
+	restoreIdentityRef(dst.Spec.IdentityRef, restored.Spec.IdentityRef)
+	if dst.Spec.ManagedSecurityGroups != nil && restored.Spec.ManagedSecurityGroups != nil {
+		dst.Spec.ManagedSecurityGroups.IPv6 = restored.Spec.ManagedSecurityGroups.IPv6
+		dst.Spec.ManagedSecurityGroups.NodePortsFromServices = restored.Spec.ManagedSecurityGroups.NodePortsFromServices
+		dst.Spec.ManagedSecurityGroups.RuleProfiles = restored.Spec.ManagedSecurityGroups.RuleProfiles
+	}
+	dst.Spec.FailureDomains = restored.Spec.FailureDomains
+	dst.Spec.ServerGroups = restored.Spec.ServerGroups
+	dst.Spec.APIServerFloatingIPPoolRef = restored.Spec.APIServerFloatingIPPoolRef
+	if dst.Spec.Bastion != nil && restored.Spec.Bastion != nil {
+		dst.Spec.Bastion.Instance.FloatingIPPoolRef = restored.Spec.Bastion.Instance.FloatingIPPoolRef
+		restorePorts(dst.Spec.Bastion.Instance.Ports, restored.Spec.Bastion.Instance.Ports)
+		restoreIdentityRef(dst.Spec.Bastion.Instance.IdentityRef, restored.Spec.Bastion.Instance.IdentityRef)
+		dst.Spec.Bastion.Instance.FailureDomain = restored.Spec.Bastion.Instance.FailureDomain
+		dst.Spec.Bastion.Instance.FallbackFailureDomains = restored.Spec.Bastion.Instance.FallbackFailureDomains
+		dst.Spec.Bastion.Instance.FlavorRequirements = restored.Spec.Bastion.Instance.FlavorRequirements
+		dst.Spec.Bastion.Instance.NodeLabels = restored.Spec.Bastion.Instance.NodeLabels
+		dst.Spec.Bastion.Instance.SSHAuthorizedKeys = restored.Spec.Bastion.Instance.SSHAuthorizedKeys
+	}
+	dst.Spec.Share = restored.Spec.Share
+	dst.Spec.Addons = restored.Spec.Addons
+	dst.Spec.InstanceHA = restored.Spec.InstanceHA
+	dst.Spec.HostMaintenance = restored.Spec.HostMaintenance
+	dst.Spec.ObjectStorage = restored.Spec.ObjectStorage
+	dst.Spec.MachineDNS = restored.Spec.MachineDNS
+	dst.Spec.DeletionPolicy = restored.Spec.DeletionPolicy
+	dst.Spec.ReconcileTimeouts = restored.Spec.ReconcileTimeouts
+	dst.Spec.CNIOverlay = restored.Spec.CNIOverlay
+	dst.Spec.MachineDefaults = restored.Spec.MachineDefaults
+	dst.Spec.MaintenanceWindows = restored.Spec.MaintenanceWindows
+	dst.Spec.RouterFirewall = restored.Spec.RouterFirewall
+	dst.Spec.RouterRoutes = restored.Spec.RouterRoutes
+	dst.Spec.RouterSubnets = restored.Spec.RouterSubnets
+	dst.Status.Share = restored.Status.Share
+	dst.Status.Addons = restored.Status.Addons
+	dst.Status.InstanceHA = restored.Status.InstanceHA
+	dst.Status.ObjectStorage = restored.Status.ObjectStorage
+	dst.Status.ServiceNodePorts = restored.Status.ServiceNodePorts
+	dst.Status.PendingChanges = restored.Status.PendingChanges
+	dst.Status.RouterFirewallGroup = restored.Status.RouterFirewallGroup
+	dst.Status.RouterRoutes = restored.Status.RouterRoutes
+	dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+
+	return nil
+}
+
+func (r *OpenStackCluster) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackCluster)
+
+	if err := Convert_v1alpha7_OpenStackCluster_To_v1alpha6_OpenStackCluster(src, r, nil); err != nil {
+		return err
+	}
+	ownAnnotations(r)
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
+	// Report the fields which are only preserved in the hub data
+	roundTrip := &infrav1.OpenStackCluster{}
+	if err := Convert_v1alpha6_OpenStackCluster_To_v1alpha7_OpenStackCluster(r, roundTrip, nil); err != nil {
+		return err
+	}
+	return infrav1.SetConversionReport(r, src, roundTrip)
+}
+
+var _ ctrlconversion.Convertible = &OpenStackClusterList{}
+
+func (r *OpenStackClusterList) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackClusterList)
+
+	return Convert_v1alpha6_OpenStackClusterList_To_v1alpha7_OpenStackClusterList(r, dst, nil)
+}
+
+func (r *OpenStackClusterList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackClusterList)
+
+	return Convert_v1alpha7_OpenStackClusterList_To_v1alpha6_OpenStackClusterList(src, r, nil)
+}
+
+var _ ctrlconversion.Convertible = &OpenStackClusterTemplate{}
+
+func (r *OpenStackClusterTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackClusterTemplate)
+
+	if err := Convert_v1alpha6_OpenStackClusterTemplate_To_v1alpha7_OpenStackClusterTemplate(r, dst, nil); err != nil {
+		return err
+	}
+	// The conversion report only describes the conversion from the hub
+	defer dropConversionReport(dst)
+
+	// Manually restore data.
+	restored := &infrav1.OpenStackClusterTemplate{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Template.Spec.ExternalAPIServerEndpoint = restored.Spec.Template.Spec.ExternalAPIServerEndpoint
+	restoreIdentityRef(dst.Spec.Template.Spec.IdentityRef, restored.Spec.Template.Spec.IdentityRef)
+	if dst.Spec.Template.Spec.ManagedSecurityGroups != nil && restored.Spec.Template.Spec.ManagedSecurityGroups != nil {
+		dst.Spec.Template.Spec.ManagedSecurityGroups.IPv6 = restored.Spec.Template.Spec.ManagedSecurityGroups.IPv6
+		dst.Spec.Template.Spec.ManagedSecurityGroups.NodePortsFromServices = restored.Spec.Template.Spec.ManagedSecurityGroups.NodePortsFromServices
+		dst.Spec.Template.Spec.ManagedSecurityGroups.RuleProfiles = restored.Spec.Template.Spec.ManagedSecurityGroups.RuleProfiles
+	}
+	dst.Spec.Template.Spec.FailureDomains = restored.Spec.Template.Spec.FailureDomains
+	dst.Spec.Template.Spec.ServerGroups = restored.Spec.Template.Spec.ServerGroups
+	dst.Spec.Template.Spec.APIServerFloatingIPPoolRef = restored.Spec.Template.Spec.APIServerFloatingIPPoolRef
+	if dst.Spec.Template.Spec.Bastion != nil && restored.Spec.Template.Spec.Bastion != nil {
+		dst.Spec.Template.Spec.Bastion.Instance.FloatingIPPoolRef = restored.Spec.Template.Spec.Bastion.Instance.FloatingIPPoolRef
+		restorePorts(dst.Spec.Template.Spec.Bastion.Instance.Ports, restored.Spec.Template.Spec.Bastion.Instance.Ports)
+		restoreIdentityRef(dst.Spec.Template.Spec.Bastion.Instance.IdentityRef, restored.Spec.Template.Spec.Bastion.Instance.IdentityRef)
+		dst.Spec.Template.Spec.Bastion.Instance.FailureDomain = restored.Spec.Template.Spec.Bastion.Instance.FailureDomain
+		dst.Spec.Template.Spec.Bastion.Instance.FallbackFailureDomains = restored.Spec.Template.Spec.Bastion.Instance.FallbackFailureDomains
+		dst.Spec.Template.Spec.Bastion.Instance.FlavorRequirements = restored.Spec.Template.Spec.Bastion.Instance.FlavorRequirements
+		dst.Spec.Template.Spec.Bastion.Instance.NodeLabels = restored.Spec.Template.Spec.Bastion.Instance.NodeLabels
+		dst.Spec.Template.Spec.Bastion.Instance.SSHAuthorizedKeys = restored.Spec.Template.Spec.Bastion.Instance.SSHAuthorizedKeys
+	}
+	dst.Spec.Template.Spec.Share = restored.Spec.Template.Spec.Share
+	dst.Spec.Template.Spec.Addons = restored.Spec.Template.Spec.Addons
+	dst.Spec.Template.Spec.InstanceHA = restored.Spec.Template.Spec.InstanceHA
+	dst.Spec.Template.Spec.HostMaintenance = restored.Spec.Template.Spec.HostMaintenance
+	dst.Spec.Template.Spec.ObjectStorage = restored.Spec.Template.Spec.ObjectStorage
+	dst.Spec.Template.Spec.MachineDNS = restored.Spec.Template.Spec.MachineDNS
+	dst.Spec.Template.Spec.DeletionPolicy = restored.Spec.Template.Spec.DeletionPolicy
+	dst.Spec.Template.Spec.ReconcileTimeouts = restored.Spec.Template.Spec.ReconcileTimeouts
+	dst.Spec.Template.Spec.CNIOverlay = restored.Spec.Template.Spec.CNIOverlay
+	dst.Spec.Template.Spec.MachineDefaults = restored.Spec.Template.Spec.MachineDefaults
+	dst.Spec.Template.Spec.MaintenanceWindows = restored.Spec.Template.Spec.MaintenanceWindows
+	dst.Spec.Template.Spec.RouterFirewall = restored.Spec.Template.Spec.RouterFirewall
+	dst.Spec.Template.Spec.RouterRoutes = restored.Spec.Template.Spec.RouterRoutes
+	dst.Spec.Template.Spec.RouterSubnets = restored.Spec.Template.Spec.RouterSubnets
+	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+
+	return nil
+}
+
+func (r *OpenStackClusterTemplate) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackClusterTemplate)
+
+	if err := Convert_v1alpha7_OpenStackClusterTemplate_To_v1alpha6_OpenStackClusterTemplate(src, r, nil); err != nil {
+		return err
+	}
+	ownAnnotations(r)
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
+	// Report the fields which are only preserved in the hub data
+	roundTrip := &infrav1.OpenStackClusterTemplate{}
+	if err := Convert_v1alpha6_OpenStackClusterTemplate_To_v1alpha7_OpenStackClusterTemplate(r, roundTrip, nil); err != nil {
+		return err
+	}
+	return infrav1.SetConversionReport(r, src, roundTrip)
+}
+
+var _ ctrlconversion.Convertible = &OpenStackClusterTemplateList{}
+
+func (r *OpenStackClusterTemplateList) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackClusterTemplateList)
+
+	return Convert_v1alpha6_OpenStackClusterTemplateList_To_v1alpha7_OpenStackClusterTemplateList(r, dst, nil)
+}
+
+func (r *OpenStackClusterTemplateList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackClusterTemplateList)
+
+	return Convert_v1alpha7_OpenStackClusterTemplateList_To_v1alpha6_OpenStackClusterTemplateList(src, r, nil)
+}
+
+var _ ctrlconversion.Convertible = &OpenStackMachine{}
+
+func (r *OpenStackMachine) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackMachine)
+
+	if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha7_OpenStackMachine(r, dst, nil); err != nil {
+		return err
+	}
+	// The conversion report only describes the conversion from the hub
+	defer dropConversionReport(dst)
+
+	// Manually restore data.
+	restored := &infrav1.OpenStackMachine{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.FloatingIPPoolRef = restored.Spec.FloatingIPPoolRef
+	restorePorts(dst.Spec.Ports, restored.Spec.Ports)
+	restoreIdentityRef(dst.Spec.IdentityRef, restored.Spec.IdentityRef)
+	dst.Spec.FailureDomain = restored.Spec.FailureDomain
+	dst.Spec.FallbackFailureDomains = restored.Spec.FallbackFailureDomains
+	dst.Spec.FlavorRequirements = restored.Spec.FlavorRequirements
+	dst.Spec.NodeLabels = restored.Spec.NodeLabels
+	dst.Spec.SSHAuthorizedKeys = restored.Spec.SSHAuthorizedKeys
+	dst.Status.AvailabilityZone = restored.Status.AvailabilityZone
+	dst.Status.ResourceIDs = restored.Status.ResourceIDs
+	dst.Status.UnschedulableFailureDomains = restored.Status.UnschedulableFailureDomains
+	dst.Status.FallbackFailureDomain = restored.Status.FallbackFailureDomain
+	dst.Status.Capacity = restored.Status.Capacity
+	dst.Status.LastInstanceActionTime = restored.Status.LastInstanceActionTime
+	dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+
+	return nil
+}
+
+func (r *OpenStackMachine) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackMachine)
+
+	if err := Convert_v1alpha7_OpenStackMachine_To_v1alpha6_OpenStackMachine(src, r, nil); err != nil {
+		return err
+	}
+	ownAnnotations(r)
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
+	// Report the fields which are only preserved in the hub data
+	roundTrip := &infrav1.OpenStackMachine{}
+	if err := Convert_v1alpha6_OpenStackMachine_To_v1alpha7_OpenStackMachine(r, roundTrip, nil); err != nil {
+		return err
+	}
+	return infrav1.SetConversionReport(r, src, roundTrip)
+}
+
+var _ ctrlconversion.Convertible = &OpenStackMachineList{}
+
+func (r *OpenStackMachineList) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackMachineList)
+
+	return Convert_v1alpha6_OpenStackMachineList_To_v1alpha7_OpenStackMachineList(r, dst, nil)
+}
+
+func (r *OpenStackMachineList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackMachineList)
+
+	return Convert_v1alpha7_OpenStackMachineList_To_v1alpha6_OpenStackMachineList(src, r, nil)
+}
+
+var _ ctrlconversion.Convertible = &OpenStackMachineTemplate{}
+
+func (r *OpenStackMachineTemplate) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackMachineTemplate)
+
+	if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha7_OpenStackMachineTemplate(r, dst, nil); err != nil {
+		return err
+	}
+	// The conversion report only describes the conversion from the hub
+	defer dropConversionReport(dst)
+
+	// Manually restore data.
+	restored := &infrav1.OpenStackMachineTemplate{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Template.Spec.FloatingIPPoolRef = restored.Spec.Template.Spec.FloatingIPPoolRef
+	restorePorts(dst.Spec.Template.Spec.Ports, restored.Spec.Template.Spec.Ports)
+	restoreIdentityRef(dst.Spec.Template.Spec.IdentityRef, restored.Spec.Template.Spec.IdentityRef)
+	dst.Spec.Template.Spec.FailureDomain = restored.Spec.Template.Spec.FailureDomain
+	dst.Spec.Template.Spec.FallbackFailureDomains = restored.Spec.Template.Spec.FallbackFailureDomains
+	dst.Spec.Template.Spec.FlavorRequirements = restored.Spec.Template.Spec.FlavorRequirements
+	dst.Spec.Template.Spec.NodeLabels = restored.Spec.Template.Spec.NodeLabels
+	dst.Spec.Template.Spec.SSHAuthorizedKeys = restored.Spec.Template.Spec.SSHAuthorizedKeys
+	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Status = restored.Status
+
+	return nil
+}
+
+func (r *OpenStackMachineTemplate) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackMachineTemplate)
+
+	if err := Convert_v1alpha7_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(src, r, nil); err != nil {
+		return err
+	}
+	ownAnnotations(r)
+
+	// Preserve Hub data on down-conversion except for metadata
+	if err := utilconversion.MarshalData(src, r); err != nil {
+		return err
+	}
+
+	// Report the fields which are only preserved in the hub data
+	roundTrip := &infrav1.OpenStackMachineTemplate{}
+	if err := Convert_v1alpha6_OpenStackMachineTemplate_To_v1alpha7_OpenStackMachineTemplate(r, roundTrip, nil); err != nil {
+		return err
+	}
+	return infrav1.SetConversionReport(r, src, roundTrip)
+}
+
+var _ ctrlconversion.Convertible = &OpenStackMachineTemplateList{}
+
+func (r *OpenStackMachineTemplateList) ConvertTo(dstRaw ctrlconversion.Hub) error {
+	dst := dstRaw.(*infrav1.OpenStackMachineTemplateList)
+
+	return Convert_v1alpha6_OpenStackMachineTemplateList_To_v1alpha7_OpenStackMachineTemplateList(r, dst, nil)
+}
+
+func (r *OpenStackMachineTemplateList) ConvertFrom(srcRaw ctrlconversion.Hub) error {
+	src := srcRaw.(*infrav1.OpenStackMachineTemplateList)
+
+	return Convert_v1alpha7_OpenStackMachineTemplateList_To_v1alpha6_OpenStackMachineTemplateList(src, r, nil)
+}
+
+func Convert_v1alpha6_OpenStackClusterSpec_To_v1alpha7_OpenStackClusterSpec(in *OpenStackClusterSpec, out *infrav1.OpenStackClusterSpec, s conversion.Scope) error {
+	if err := autoConvert_v1alpha6_OpenStackClusterSpec_To_v1alpha7_OpenStackClusterSpec(in, out, s); err != nil {
+		return err
+	}
+
+	// AllowAllInClusterTraffic is only used with managed security groups
+	if in.ManagedSecurityGroups {
+		out.ManagedSecurityGroups = &infrav1.ManagedSecurityGroups{
+			AllowAllInClusterTraffic: in.AllowAllInClusterTraffic,
+		}
+	}
+
+	return nil
+}
+
+func Convert_v1alpha7_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(in *infrav1.OpenStackClusterSpec, out *OpenStackClusterSpec, s conversion.Scope) error {
+	if err := autoConvert_v1alpha7_OpenStackClusterSpec_To_v1alpha6_OpenStackClusterSpec(in, out, s); err != nil {
+		return err
+	}
+
+	if in.ManagedSecurityGroups != nil {
+		out.ManagedSecurityGroups = true
+		out.AllowAllInClusterTraffic = in.ManagedSecurityGroups.AllowAllInClusterTraffic
+	}
+
+	return nil
+}
+
+func Convert_v1alpha7_OpenStackClusterStatus_To_v1alpha6_OpenStackClusterStatus(in *infrav1.OpenStackClusterStatus, out *OpenStackClusterStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha7_OpenStackClusterStatus_To_v1alpha6_OpenStackClusterStatus(in, out, s)
+}
+
+func Convert_v1alpha7_OpenStackClusterTemplateResource_To_v1alpha6_OpenStackClusterTemplateResource(in *infrav1.OpenStackClusterTemplateResource, out *OpenStackClusterTemplateResource, s conversion.Scope) error {
+	return autoConvert_v1alpha7_OpenStackClusterTemplateResource_To_v1alpha6_OpenStackClusterTemplateResource(in, out, s)
+}
+
+func Convert_v1alpha7_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(in *infrav1.OpenStackMachineSpec, out *OpenStackMachineSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha7_OpenStackMachineSpec_To_v1alpha6_OpenStackMachineSpec(in, out, s)
+}
+
+func Convert_v1alpha7_OpenStackIdentityReference_To_v1alpha6_OpenStackIdentityReference(in *infrav1.OpenStackIdentityReference, out *OpenStackIdentityReference, s conversion.Scope) error {
+	return autoConvert_v1alpha7_OpenStackIdentityReference_To_v1alpha6_OpenStackIdentityReference(in, out, s)
+}
+
+func Convert_v1alpha7_OpenStackMachineStatus_To_v1alpha6_OpenStackMachineStatus(in *infrav1.OpenStackMachineStatus, out *OpenStackMachineStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha7_OpenStackMachineStatus_To_v1alpha6_OpenStackMachineStatus(in, out, s)
+}
+
+func Convert_v1alpha6_PortOpts_To_v1alpha7_PortOpts(in *PortOpts, out *infrav1.PortOpts, s conversion.Scope) error {
+	if err := autoConvert_v1alpha6_PortOpts_To_v1alpha7_PortOpts(in, out, s); err != nil {
+		return err
+	}
+
+	// Security groups given by their UUIDs become filters by ID
+	if in.SecurityGroups != nil {
+		for _, id := range *in.SecurityGroups {
+			out.SecurityGroupFilters = append(out.SecurityGroupFilters, infrav1.SecurityGroupFilter{ID: id})
+		}
+	}
+
+	return nil
+}
+
+func Convert_v1alpha7_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(in *infrav1.OpenStackMachineTemplate, out *OpenStackMachineTemplate, s conversion.Scope) error {
+	// Status has no equivalent in v1alpha6
+	return autoConvert_v1alpha7_OpenStackMachineTemplate_To_v1alpha6_OpenStackMachineTemplate(in, out, s)
+}
+
+func Convert_v1alpha7_OpenStackMachineTemplateResource_To_v1alpha6_OpenStackMachineTemplateResource(in *infrav1.OpenStackMachineTemplateResource, out *OpenStackMachineTemplateResource, s conversion.Scope) error {
+	return autoConvert_v1alpha7_OpenStackMachineTemplateResource_To_v1alpha6_OpenStackMachineTemplateResource(in, out, s)
+}
+
+func Convert_v1alpha7_PortOpts_To_v1alpha6_PortOpts(in *infrav1.PortOpts, out *PortOpts, s conversion.Scope) error {
+	// All security groups are converted to securityGroupFilters, and ID,
+	// FixedIPPerSubnet and ExtraDHCPOpts are restored by restorePorts
+	return autoConvert_v1alpha7_PortOpts_To_v1alpha6_PortOpts(in, out, s)
+}
+
+// restorePorts restores the fields of ports which do not exist in v1alpha6.
+// ownAnnotations gives obj a copy of its annotations. The generated
+// conversions share the annotations with the converted object, which must
+// not change when they are changed.
+func ownAnnotations(obj metav1.Object) {
+	if obj.GetAnnotations() == nil {
+		return
+	}
+	annotations := make(map[string]string, len(obj.GetAnnotations()))
+	for k, v := range obj.GetAnnotations() {
+		annotations[k] = v
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// dropConversionReport removes the ConversionReportAnnotation from obj
+// without changing the object it was converted from.
+func dropConversionReport(obj metav1.Object) {
+	if _, ok := obj.GetAnnotations()[infrav1.ConversionReportAnnotation]; !ok {
+		return
+	}
+	ownAnnotations(obj)
+	delete(obj.GetAnnotations(), infrav1.ConversionReportAnnotation)
+}
+
+func restorePorts(dst, restored []infrav1.PortOpts) {
+	if len(dst) != len(restored) {
+		return
+	}
+	for i := range dst {
+		dst[i].ID = restored[i].ID
+		dst[i].FixedIPPerSubnet = restored[i].FixedIPPerSubnet
+		dst[i].ExtraDHCPOpts = restored[i].ExtraDHCPOpts
+	}
+}
+
+// restoreIdentityRef restores the fields of an identityRef which do not
+// exist in v1alpha6.
+func restoreIdentityRef(dst, restored *infrav1.OpenStackIdentityReference) {
+	if dst == nil || restored == nil {
+		return
+	}
+	dst.Namespace = restored.Namespace
+}
+
+func Convert_Slice_v1alpha6_Network_To_Slice_v1alpha7_Network(in *[]Network, out *[]infrav1.Network, s conversion.Scope) error {
+	*out = make([]infrav1.Network, len(*in))
+	for i := range *in {
+		if err := Convert_v1alpha6_Network_To_v1alpha7_Network(&(*in)[i], &(*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Convert_Slice_v1alpha7_Network_To_Slice_v1alpha6_Network(in *[]infrav1.Network, out *[]Network, s conversion.Scope) error {
+	*out = make([]Network, len(*in))
+	for i := range *in {
+		if err := Convert_v1alpha7_Network_To_v1alpha6_Network(&(*in)[i], &(*out)[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Convert_v1alpha6_SecurityGroupParam_To_v1alpha7_SecurityGroupFilter(in *SecurityGroupParam, out *infrav1.SecurityGroupFilter, s conversion.Scope) error {
+	// The filter is not used when a UUID is given
+	if in.UUID != "" {
+		out.ID = in.UUID
+		return nil
+	}
+
+	if err := Convert_v1alpha6_SecurityGroupFilter_To_v1alpha7_SecurityGroupFilter(&in.Filter, out, s); err != nil {
+		return err
+	}
+	if in.Name != "" {
+		out.Name = in.Name
+	}
+
+	return nil
+}
+
+func Convert_v1alpha7_SecurityGroupFilter_To_v1alpha6_SecurityGroupParam(in *infrav1.SecurityGroupFilter, out *SecurityGroupParam, s conversion.Scope) error {
+	if in.IsIDOnly() {
+		out.UUID = in.ID
+		return nil
+	}
+
+	if err := Convert_v1alpha7_SecurityGroupFilter_To_v1alpha6_SecurityGroupFilter(in, &out.Filter, s); err != nil {
+		return err
+	}
+	out.Name = in.Name
+	out.Filter.Name = ""
+
+	return nil
+}
+
+func Convert_v1alpha6_SecurityGroupFilter_To_v1alpha7_SecurityGroupFilter(in *SecurityGroupFilter, out *infrav1.SecurityGroupFilter, s conversion.Scope) error {
+	// Limit, Marker, SortKey and SortDir do not change which security groups are selected
+	if err := autoConvert_v1alpha6_SecurityGroupFilter_To_v1alpha7_SecurityGroupFilter(in, out, s); err != nil {
+		return err
+	}
+
+	// TenantID and ProjectID are the same thing, so TenantID is removed in infrav1
+	if out.ProjectID == "" {
+		out.ProjectID = in.TenantID
+	}
+	convertTagsToNeutronTags(in.Tags, in.TagsAny, in.NotTags, in.NotTagsAny, &out.FilterByNeutronTags)
+
+	return nil
+}
+
+func Convert_v1alpha7_SecurityGroupFilter_To_v1alpha6_SecurityGroupFilter(in *infrav1.SecurityGroupFilter, out *SecurityGroupFilter, s conversion.Scope) error {
+	if err := autoConvert_v1alpha7_SecurityGroupFilter_To_v1alpha6_SecurityGroupFilter(in, out, s); err != nil {
+		return err
+	}
+
+	convertNeutronTagsToTags(&in.FilterByNeutronTags, &out.Tags, &out.TagsAny, &out.NotTags, &out.NotTagsAny)
+
+	return nil
+}
+
+func Convert_v1alpha6_NetworkFilter_To_v1alpha7_NetworkFilter(in *NetworkFilter, out *infrav1.NetworkFilter, s conversion.Scope) error {
+	if err := autoConvert_v1alpha6_NetworkFilter_To_v1alpha7_NetworkFilter(in, out, s); err != nil {
+		return err
+	}
+
+	convertTagsToNeutronTags(in.Tags, in.TagsAny, in.NotTags, in.NotTagsAny, &out.FilterByNeutronTags)
+
+	return nil
+}
+
+func Convert_v1alpha7_NetworkFilter_To_v1alpha6_NetworkFilter(in *infrav1.NetworkFilter, out *NetworkFilter, s conversion.Scope) error {
+	if err := autoConvert_v1alpha7_NetworkFilter_To_v1alpha6_NetworkFilter(in, out, s); err != nil {
+		return err
+	}
+
+	convertNeutronTagsToTags(&in.FilterByNeutronTags, &out.Tags, &out.TagsAny, &out.NotTags, &out.NotTagsAny)
+
+	return nil
+}
+
+func Convert_v1alpha6_SubnetFilter_To_v1alpha7_SubnetFilter(in *SubnetFilter, out *infrav1.SubnetFilter, s conversion.Scope) error {
+	if err := autoConvert_v1alpha6_SubnetFilter_To_v1alpha7_SubnetFilter(in, out, s); err != nil {
+		return err
+	}
+
+	convertTagsToNeutronTags(in.Tags, in.TagsAny, in.NotTags, in.NotTagsAny, &out.FilterByNeutronTags)
+
+	return nil
+}
+
+func Convert_v1alpha7_SubnetFilter_To_v1alpha6_SubnetFilter(in *infrav1.SubnetFilter, out *SubnetFilter, s conversion.Scope) error {
+	if err := autoConvert_v1alpha7_SubnetFilter_To_v1alpha6_SubnetFilter(in, out, s); err != nil {
+		return err
+	}
+
+	convertNeutronTagsToTags(&in.FilterByNeutronTags, &out.Tags, &out.TagsAny, &out.NotTags, &out.NotTagsAny)
+
+	return nil
+}
+
+func convertTagsToNeutronTags(tags, tagsAny, notTags, notTagsAny string, out *infrav1.FilterByNeutronTags) {
+	out.Tags = splitTags(tags)
+	out.TagsAny = splitTags(tagsAny)
+	out.NotTags = splitTags(notTags)
+	out.NotTagsAny = splitTags(notTagsAny)
+}
+
+func convertNeutronTagsToTags(in *infrav1.FilterByNeutronTags, tags, tagsAny, notTags, notTagsAny *string) {
+	*tags = joinTags(in.Tags)
+	*tagsAny = joinTags(in.TagsAny)
+	*notTags = joinTags(in.NotTags)
+	*notTagsAny = joinTags(in.NotTagsAny)
+}
+
+// splitTags splits a comma separated list of tags.
+func splitTags(tags string) []infrav1.NeutronTag {
+	if tags == "" {
+		return nil
+	}
+
+	var ret []infrav1.NeutronTag
+	for _, tag := range strings.Split(tags, ",") {
+		ret = append(ret, infrav1.NeutronTag(tag))
+	}
+	return ret
+}
+
+// joinTags joins tags into a comma separated list.
+func joinTags(tags []infrav1.NeutronTag) string {
+	var ret []string
+	for _, tag := range tags {
+		ret = append(ret, string(tag))
+	}
+	return strings.Join(ret, ",")
+}
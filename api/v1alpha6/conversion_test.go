@@ -0,0 +1,393 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha6
+
+import (
+	"strings"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	ctrlconversion "sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestConvertTo(t *testing.T) {
+	g := gomega.NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	const securityGroupID = "5a0b3c4e-b4c3-4a6b-a2c5-8f7b3d0b6a2e"
+
+	tests := []struct {
+		name  string
+		spoke ctrlconversion.Convertible
+		hub   ctrlconversion.Hub
+		want  ctrlconversion.Hub
+	}{
+		{
+			name: "Managed security groups",
+			spoke: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					ManagedSecurityGroups:    true,
+					AllowAllInClusterTraffic: true,
+				},
+			},
+			hub: &infrav1.OpenStackCluster{},
+			want: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{
+						AllowAllInClusterTraffic: true,
+					},
+				},
+			},
+		},
+		{
+			name: "Network and subnet filters by tags",
+			spoke: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					Network: NetworkFilter{Tags: "foo,bar", NotTagsAny: "baz"},
+					Subnet:  SubnetFilter{TagsAny: "foo"},
+				},
+			},
+			hub: &infrav1.OpenStackCluster{},
+			want: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					Network: infrav1.NetworkFilter{
+						FilterByNeutronTags: infrav1.FilterByNeutronTags{
+							Tags:       []infrav1.NeutronTag{"foo", "bar"},
+							NotTagsAny: []infrav1.NeutronTag{"baz"},
+						},
+					},
+					Subnet: infrav1.SubnetFilter{
+						FilterByNeutronTags: infrav1.FilterByNeutronTags{
+							TagsAny: []infrav1.NeutronTag{"foo"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Security groups",
+			spoke: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{
+					SecurityGroups: []SecurityGroupParam{
+						{UUID: securityGroupID},
+						{Name: "foo", Filter: SecurityGroupFilter{TenantID: "project", Tags: "bar"}},
+					},
+					Ports: []PortOpts{
+						{
+							SecurityGroups:       &[]string{securityGroupID},
+							SecurityGroupFilters: []SecurityGroupParam{{Name: "foo"}},
+						},
+					},
+				},
+			},
+			hub: &infrav1.OpenStackMachine{},
+			want: &infrav1.OpenStackMachine{
+				Spec: infrav1.OpenStackMachineSpec{
+					SecurityGroups: []infrav1.SecurityGroupFilter{
+						{ID: securityGroupID},
+						{
+							Name:                "foo",
+							ProjectID:           "project",
+							FilterByNeutronTags: infrav1.FilterByNeutronTags{Tags: []infrav1.NeutronTag{"bar"}},
+						},
+					},
+					Ports: []infrav1.PortOpts{
+						{
+							SecurityGroupFilters: []infrav1.SecurityGroupFilter{{Name: "foo"}, {ID: securityGroupID}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spoke.ConvertTo(tt.hub)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(tt.hub).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestConvertToDropsConversionReport(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	spoke := &OpenStackCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"foo": "bar",
+				"infrastructure.cluster.x-k8s.io/conversion-report": "spec.routerRoutes",
+			},
+		},
+	}
+	hub := &infrav1.OpenStackCluster{}
+	g.Expect(spoke.ConvertTo(hub)).To(gomega.Succeed())
+
+	g.Expect(hub.Annotations).To(gomega.Equal(map[string]string{"foo": "bar"}))
+	// The converted object is left unchanged
+	g.Expect(spoke.Annotations).To(gomega.HaveKeyWithValue("infrastructure.cluster.x-k8s.io/conversion-report", "spec.routerRoutes"))
+}
+
+func TestConvertFrom(t *testing.T) {
+	g := gomega.NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	tests := []struct {
+		name  string
+		spoke ctrlconversion.Convertible
+		hub   ctrlconversion.Hub
+		want  ctrlconversion.Convertible
+	}{
+		{
+			name:  "Managed security groups",
+			spoke: &OpenStackCluster{},
+			hub: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{},
+				},
+			},
+			want: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					ManagedSecurityGroups: true,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"apiServerLoadBalancer\":{},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"managedSecurityGroups\":{\"allowAllInClusterTraffic\":false},\"network\":{},\"subnet\":{}},\"status\":{\"ready\":false}}",
+					},
+				},
+			},
+		},
+		{
+			name:  "Security groups",
+			spoke: &OpenStackMachine{},
+			hub: &infrav1.OpenStackMachine{
+				Spec: infrav1.OpenStackMachineSpec{
+					SecurityGroups: []infrav1.SecurityGroupFilter{
+						{ID: "foo"},
+						{Name: "bar", FilterByNeutronTags: infrav1.FilterByNeutronTags{NotTags: []infrav1.NeutronTag{"baz", "qux"}}},
+					},
+				},
+			},
+			want: &OpenStackMachine{
+				Spec: OpenStackMachineSpec{
+					SecurityGroups: []SecurityGroupParam{
+						{UUID: "foo"},
+						{Name: "bar", Filter: SecurityGroupFilter{NotTags: "baz,qux"}},
+					},
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"cluster.x-k8s.io/conversion-data": "{\"spec\":{\"cloudName\":\"\",\"flavor\":\"\",\"securityGroups\":[{\"id\":\"foo\"},{\"name\":\"bar\",\"notTags\":[\"baz\",\"qux\"]}]},\"status\":{\"ready\":false}}",
+					},
+				},
+			},
+		},
+		{
+			name:  "Fields not in v1alpha6",
+			spoke: &OpenStackCluster{},
+			hub: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					RouterRoutes: []infrav1.RouterRoute{{Destination: "192.168.0.0/16", NextHop: "10.6.0.10"}},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					ObservedGeneration: 2,
+				},
+			},
+			want: &OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"cluster.x-k8s.io/conversion-data":                  "{\"spec\":{\"apiServerLoadBalancer\":{},\"cloudName\":\"\",\"controlPlaneEndpoint\":{\"host\":\"\",\"port\":0},\"disableAPIServerFloatingIP\":false,\"network\":{},\"routerRoutes\":[{\"destination\":\"192.168.0.0/16\",\"nextHop\":\"10.6.0.10\"}],\"subnet\":{}},\"status\":{\"observedGeneration\":2,\"ready\":false}}",
+						"infrastructure.cluster.x-k8s.io/conversion-report": "spec.routerRoutes",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spoke.ConvertFrom(tt.hub)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(tt.spoke).To(gomega.Equal(tt.want))
+		})
+	}
+}
+
+func TestFuzzyConversion(t *testing.T) {
+	g := gomega.NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	fuzzerFuncs := func(_ runtimeserializer.CodecFactory) []interface{} {
+		return []interface{}{
+			// Don't test spoke-hub-spoke conversion of v1alpha6 fields which are not in infrav1
+			func(v1alpha6Cluster *OpenStackCluster, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6Cluster)
+
+				v1alpha6Cluster.ObjectMeta.Annotations = map[string]string{}
+
+				// AllowAllInClusterTraffic is only used with managed security groups
+				if !v1alpha6Cluster.Spec.ManagedSecurityGroups {
+					v1alpha6Cluster.Spec.AllowAllInClusterTraffic = false
+				}
+			},
+			func(v1alpha6ClusterTemplate *OpenStackClusterTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6ClusterTemplate)
+
+				v1alpha6ClusterTemplate.ObjectMeta.Annotations = map[string]string{}
+
+				if !v1alpha6ClusterTemplate.Spec.Template.Spec.ManagedSecurityGroups {
+					v1alpha6ClusterTemplate.Spec.Template.Spec.AllowAllInClusterTraffic = false
+				}
+			},
+			func(v1alpha6Machine *OpenStackMachine, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6Machine)
+
+				v1alpha6Machine.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha6MachineTemplate *OpenStackMachineTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6MachineTemplate)
+
+				v1alpha6MachineTemplate.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha6PortOpts *PortOpts, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6PortOpts)
+
+				// Security groups given by their UUIDs are converted to securityGroupFilters
+				v1alpha6PortOpts.SecurityGroups = nil
+			},
+			func(v1alpha6SecurityGroupParam *SecurityGroupParam, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6SecurityGroupParam)
+
+				// The filter is not used when a UUID is given, and its
+				// name is replaced by the name of the parameter
+				if v1alpha6SecurityGroupParam.UUID != "" {
+					v1alpha6SecurityGroupParam.Name = ""
+					v1alpha6SecurityGroupParam.Filter = SecurityGroupFilter{}
+				}
+				v1alpha6SecurityGroupParam.Filter.Name = ""
+				v1alpha6SecurityGroupParam.Filter.ID = ""
+			},
+			func(v1alpha6SecurityGroupFilter *SecurityGroupFilter, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha6SecurityGroupFilter)
+
+				v1alpha6SecurityGroupFilter.Limit = 0
+				v1alpha6SecurityGroupFilter.Marker = ""
+				v1alpha6SecurityGroupFilter.SortKey = ""
+				v1alpha6SecurityGroupFilter.SortDir = ""
+
+				// TenantID and ProjectID are the same thing, so TenantID is removed in infrav1
+				v1alpha6SecurityGroupFilter.TenantID = ""
+			},
+
+			// Don't test hub-spoke-hub conversion of infrav1 fields which are not in v1alpha6
+			func(v1alpha7Cluster *infrav1.OpenStackCluster, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Cluster)
+
+				v1alpha7Cluster.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha7ClusterTemplate *infrav1.OpenStackClusterTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7ClusterTemplate)
+
+				v1alpha7ClusterTemplate.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha7Machine *infrav1.OpenStackMachine, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Machine)
+
+				v1alpha7Machine.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha7MachineTemplate *infrav1.OpenStackMachineTemplate, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7MachineTemplate)
+
+				v1alpha7MachineTemplate.ObjectMeta.Annotations = map[string]string{}
+			},
+			func(v1alpha7Network *infrav1.Network, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7Network)
+
+				// Only the ports in the spec are restored, networks in the status
+				// just report the port options they were created with
+				if v1alpha7Network.PortOpts != nil {
+					v1alpha7Network.PortOpts.ID = ""
+					v1alpha7Network.PortOpts.FixedIPPerSubnet = false
+					v1alpha7Network.PortOpts.ExtraDHCPOpts = nil
+				}
+			},
+			func(v1alpha7FilterByNeutronTags *infrav1.FilterByNeutronTags, c fuzz.Continue) {
+				c.FuzzNoCustom(v1alpha7FilterByNeutronTags)
+
+				// Tags are converted to comma separated lists, which cannot
+				// contain empty tags or tags with commas
+				v1alpha7FilterByNeutronTags.Tags = validNeutronTags(v1alpha7FilterByNeutronTags.Tags)
+				v1alpha7FilterByNeutronTags.TagsAny = validNeutronTags(v1alpha7FilterByNeutronTags.TagsAny)
+				v1alpha7FilterByNeutronTags.NotTags = validNeutronTags(v1alpha7FilterByNeutronTags.NotTags)
+				v1alpha7FilterByNeutronTags.NotTagsAny = validNeutronTags(v1alpha7FilterByNeutronTags.NotTagsAny)
+			},
+		}
+	}
+
+	t.Run("for OpenStackCluster", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackCluster{},
+		Spoke:       &OpenStackCluster{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+
+	t.Run("for OpenStackClusterTemplate", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackClusterTemplate{},
+		Spoke:       &OpenStackClusterTemplate{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+
+	t.Run("for OpenStackMachine", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackMachine{},
+		Spoke:       &OpenStackMachine{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+
+	t.Run("for OpenStackMachineTemplate", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &infrav1.OpenStackMachineTemplate{},
+		Spoke:       &OpenStackMachineTemplate{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzerFuncs},
+	}))
+}
+
+func validNeutronTags(tags []infrav1.NeutronTag) []infrav1.NeutronTag {
+	var ret []infrav1.NeutronTag
+	for _, tag := range tags {
+		if tag != "" && !strings.Contains(string(tag), ",") {
+			ret = append(ret, tag)
+		}
+	}
+	return ret
+}
@@ -26,6 +26,21 @@ const (
 	// ClusterFinalizer allows ReconcileOpenStackCluster to clean up OpenStack resources associated with OpenStackCluster before
 	// removing it from the apiserver.
 	ClusterFinalizer = "openstackcluster.infrastructure.cluster.x-k8s.io"
+
+	// DryRunAnnotation makes the controller compute the changes to OpenStack
+	// resources it would apply to an OpenStackCluster and publish them in
+	// status.dryRunPlan instead of applying them, when set to "true".
+	DryRunAnnotation = "infrastructure.cluster.x-k8s.io/dry-run"
+
+	// ResourceIDsAnnotation records the IDs of the OpenStack resources of an
+	// OpenStackCluster. Unlike the status it is preserved by clusterctl move,
+	// so that the resources are re-adopted by their IDs after a move.
+	ResourceIDsAnnotation = "infrastructure.cluster.x-k8s.io/resource-ids"
+
+	// InventoryAnnotation makes the controller export an inventory of the
+	// OpenStack resources of an OpenStackCluster as JSON in the ConfigMap
+	// <name>-inventory, when set to "true".
+	InventoryAnnotation = "infrastructure.cluster.x-k8s.io/export-inventory"
 )
 
 // OpenStackClusterSpec defines the desired state of OpenStackCluster.
@@ -210,11 +225,19 @@ type OpenStackClusterStatus struct {
 	// and/or logged in the controller's output.
 	// +optional
 	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the OpenStackCluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// DryRunPlan contains the changes to OpenStack resources the controller
+	// would apply, computed while the dry-run annotation is set.
+	// +optional
+	DryRunPlan *DryRunPlan `json:"dryRunPlan,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=openstackclusters,scope=Namespaced,categories=cluster-api,shortName=osc
-// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this OpenStackCluster belongs"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Cluster infrastructure is ready for OpenStack instances"
@@ -242,6 +265,16 @@ type OpenStackClusterList struct {
 	Items           []OpenStackCluster `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the OpenStackCluster resource.
+func (r *OpenStackCluster) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackCluster to the predescribed clusterv1.Conditions.
+func (r *OpenStackCluster) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	SchemeBuilder.Register(&OpenStackCluster{}, &OpenStackClusterList{})
 }
@@ -27,6 +27,12 @@ const (
 	// MachineFinalizer allows ReconcileOpenStackMachine to clean up OpenStack resources associated with OpenStackMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "openstackmachine.infrastructure.cluster.x-k8s.io"
+
+	// ConsoleAnnotation makes the controller publish the URL of a remote
+	// console of the instance of an OpenStackMachine in the Secret
+	// <name>-console. Its value is the console type: novnc, serial or
+	// spice-html5. The annotation is removed once the URL is published.
+	ConsoleAnnotation = "infrastructure.cluster.x-k8s.io/request-console"
 )
 
 // OpenStackMachineSpec defines the desired state of OpenStackMachine.
@@ -35,12 +41,22 @@ type OpenStackMachineSpec struct {
 	ProviderID *string `json:"providerID,omitempty"`
 
 	// InstanceID is the OpenStack instance ID for this machine.
+	// If set when the machine is created, the existing server with this ID
+	// is adopted instead of creating a new one.
 	InstanceID *string `json:"instanceID,omitempty"`
 
 	// The name of the cloud to use from the clouds secret
 	// +optional
 	CloudName string `json:"cloudName"`
 
+	// Region is the name of the OpenStack region in which the machine is
+	// created. It overrides the region of the cloud, allowing the machines of
+	// a cluster to be spread across multiple regions. The networks and ports
+	// of a machine in a different region than the cluster must be specified
+	// explicitly, as the cluster network is not available there.
+	// +optional
+	Region string `json:"region,omitempty"`
+
 	// The flavor reference for the flavor for your server instance.
 	Flavor string `json:"flavor"`
 
@@ -93,7 +109,8 @@ type OpenStackMachineSpec struct {
 	// The server group to assign the machine to
 	ServerGroupID string `json:"serverGroupID,omitempty"`
 
-	// IdentityRef is a reference to a identity to be used when reconciling this cluster
+	// IdentityRef is a reference to a identity to be used when reconciling this machine.
+	// If not specified, the identityRef and cloudName of the cluster are used instead.
 	// +optional
 	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
 }
@@ -136,7 +153,6 @@ type OpenStackMachineStatus struct {
 }
 
 // +kubebuilder:object:root=true
-// +kubebuilder:storageversion
 // +kubebuilder:resource:path=openstackmachines,scope=Namespaced,categories=cluster-api,shortName=osm
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this OpenStackMachine belongs"
@@ -16,6 +16,10 @@ limitations under the License.
 
 package v1alpha6
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // OpenStackMachineTemplateResource describes the data needed to create a OpenStackMachine from a template.
 type OpenStackMachineTemplateResource struct {
 	// Spec is the specification of the desired behavior of the machine.
@@ -314,3 +318,40 @@ type APIServerLoadBalancer struct {
 	// AllowedCIDRs restrict access to all API-Server listeners to the given address CIDRs.
 	AllowedCIDRs []string `json:"allowedCidrs,omitempty"`
 }
+
+// DryRunPlan is the set of changes to OpenStack resources computed in dry-run mode.
+type DryRunPlan struct {
+	// GeneratedAt is the time the plan was computed.
+	GeneratedAt metav1.Time `json:"generatedAt"`
+
+	// Changes are the changes the controller would apply, in order.
+	// +optional
+	Changes []ResourceChange `json:"changes,omitempty"`
+
+	// Error is set if computing the plan failed, in which case the plan is
+	// incomplete.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ResourceChange is a change to an OpenStack resource.
+type ResourceChange struct {
+	// Action is either Create, Update or Delete.
+	Action string `json:"action"`
+
+	// Kind is the kind of the OpenStack resource, e.g. network or security_group_rule.
+	Kind string `json:"kind"`
+
+	// Name is the name of the resource, if it has one.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ID is the ID of the resource. Resources which would be created have a
+	// placeholder ID starting with "dry-run-".
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Request is the method and path of the OpenStack API request which would
+	// apply the change, e.g. "POST /v2.0/networks".
+	Request string `json:"request"`
+}
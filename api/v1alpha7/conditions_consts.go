@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+const (
+	// InstanceReadyCondition reports on current status of the OpenStack instance. Ready indicates the instance is in a Running state.
+	InstanceReadyCondition clusterv1.ConditionType = "InstanceReady"
+
+	// WaitingForClusterInfrastructureReason used when machine is waiting for cluster infrastructure to be ready before proceeding.
+	WaitingForClusterInfrastructureReason = "WaitingForClusterInfrastructure"
+	// WaitingForBootstrapDataReason used when machine is waiting for bootstrap data to be ready before proceeding.
+	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// InvalidMachineSpecReason used when the machine spec is invalid.
+	InvalidMachineSpecReason = "InvalidMachineSpec"
+	// FlavorRequirementsNotMetReason used when the flavor of the machine does not meet its flavor requirements.
+	FlavorRequirementsNotMetReason = "FlavorRequirementsNotMet"
+	// InstanceCreateFailedReason used when creating the instance failed.
+	InstanceCreateFailedReason = "InstanceCreateFailed"
+	// InstanceAdoptFailedReason used when adopting an existing instance failed.
+	InstanceAdoptFailedReason = "InstanceAdoptFailed"
+	// InstanceNotFoundReason used when the instance couldn't be retrieved.
+	InstanceNotFoundReason = "InstanceNotFound"
+	// InstanceStateErrorReason used when the instance is in error state.
+	InstanceStateErrorReason = "InstanceStateError"
+	// InstanceNoValidHostReason used when no host had capacity for the instance.
+	InstanceNoValidHostReason = "InstanceNoValidHost"
+	// InstanceQuotaExceededReason used when creating the instance exceeded a quota of the project.
+	InstanceQuotaExceededReason = "InstanceQuotaExceeded"
+	// InstanceNoGPUHostsReason used when no host aggregate provides the GPUs requested by the flavor of the instance.
+	InstanceNoGPUHostsReason = "InstanceNoGPUHosts"
+	// InstanceDeletedReason used when the instance is in a deleted state.
+	InstanceDeletedReason = "InstanceDeleted"
+	// InstanceNotReadyReason used when the instance is in a pending state.
+	InstanceNotReadyReason = "InstanceNotReady"
+	// InstanceDeleteFailedReason used when deleting the instance failed.
+	InstanceDeleteFailedReason = "InstanceDeleteFailed"
+	// InstancePortsUpdateFailedReason used when attaching ports to or detaching ports from the running instance failed.
+	InstancePortsUpdateFailedReason = "InstancePortsUpdateFailed"
+	// InstanceRootVolumeResizeFailedReason used when extending the root volume of the running instance failed.
+	InstanceRootVolumeResizeFailedReason = "InstanceRootVolumeResizeFailed"
+	// WaitingForPreTerminateHookReason used when the deletion of the instance waits for pre-terminate hooks to be removed.
+	WaitingForPreTerminateHookReason = "WaitingForPreTerminateHook"
+)
+
+const (
+	// APIServerIngressReadyCondition reports on the current status of the network ingress (Loadbalancer, Floating IP) for Control Plane machines. Ready indicates that the instance can receive requests.
+	APIServerIngressReadyCondition clusterv1.ConditionType = "APIServerIngressReadyCondition"
+
+	// LoadBalancerMemberErrorReason used when the instance could not be added as a loadbalancer member.
+	LoadBalancerMemberErrorReason = "LoadBalancerMemberError"
+	// FloatingIPErrorReason used when the floating ip could not be created or attached.
+	FloatingIPErrorReason = "FloatingIPError"
+)
+
+const (
+	// FloatingIPReadyCondition reports on the current status of the floating IP of a control plane machine. Ready indicates that the floating IP is associated with the instance.
+	FloatingIPReadyCondition clusterv1.ConditionType = "FloatingIPReady"
+
+	// FloatingIPCreateFailedReason used when the floating IP could not be obtained or created.
+	FloatingIPCreateFailedReason = "FloatingIPCreateFailed"
+	// FloatingIPAssociateFailedReason used when the floating IP could not be associated with the instance.
+	FloatingIPAssociateFailedReason = "FloatingIPAssociateFailed"
+	// FloatingIPDeleteFailedReason used when the floating IP could not be deleted.
+	FloatingIPDeleteFailedReason = "FloatingIPDeleteFailed"
+)
+
+const (
+	// NetworkReadyCondition reports on the current status of the cluster network: the external network, network, subnet and router, and the API server floating IP.
+	NetworkReadyCondition clusterv1.ConditionType = "NetworkReady"
+
+	// NetworkReconcileFailedReason used when the network, subnet or router could not be reconciled.
+	NetworkReconcileFailedReason = "NetworkReconcileFailed"
+	// ExternalNetworkReconcileFailedReason used when the external network could not be reconciled.
+	ExternalNetworkReconcileFailedReason = "ExternalNetworkReconcileFailed"
+	// NetworkNotFoundReason used when the network or subnet referenced by the cluster could not be found.
+	NetworkNotFoundReason = "NetworkNotFound"
+	// APIServerEndpointErrorReason used when the endpoint of the API server could not be determined.
+	APIServerEndpointErrorReason = "APIServerEndpointError"
+	// NetworkDeleteFailedReason used when the network, subnet, router or ports could not be deleted.
+	NetworkDeleteFailedReason = "NetworkDeleteFailed"
+)
+
+const (
+	// SecurityGroupsReadyCondition reports on the current status of the managed security groups of the cluster.
+	SecurityGroupsReadyCondition clusterv1.ConditionType = "SecurityGroupsReady"
+
+	// SecurityGroupReconcileFailedReason used when the security groups or their rules could not be reconciled.
+	SecurityGroupReconcileFailedReason = "SecurityGroupReconcileFailed"
+	// SecurityGroupDeleteFailedReason used when the security groups could not be deleted.
+	SecurityGroupDeleteFailedReason = "SecurityGroupDeleteFailed"
+)
+
+const (
+	// LoadBalancerReadyCondition reports on the current status of the API server load balancer.
+	LoadBalancerReadyCondition clusterv1.ConditionType = "LoadBalancerReady"
+
+	// LoadBalancerReconcileFailedReason used when the load balancer, its listeners, pools or monitors could not be reconciled.
+	LoadBalancerReconcileFailedReason = "LoadBalancerReconcileFailed"
+	// LoadBalancerDeleteFailedReason used when the load balancer could not be deleted.
+	LoadBalancerDeleteFailedReason = "LoadBalancerDeleteFailed"
+)
+
+const (
+	// BastionReadyCondition reports on the current status of the bastion host. Ready indicates the bastion is running and has a floating IP.
+	BastionReadyCondition clusterv1.ConditionType = "BastionReady"
+
+	// BastionCreateFailedReason used when the bastion instance could not be created.
+	BastionCreateFailedReason = "BastionCreateFailed"
+	// BastionDeleteFailedReason used when the bastion instance or its security group could not be deleted.
+	BastionDeleteFailedReason = "BastionDeleteFailed"
+)
+
+const (
+	// ShareReadyCondition reports on the current status of the Manila share of the cluster. Ready indicates the share is available and accessible from the cluster.
+	ShareReadyCondition clusterv1.ConditionType = "ShareReady"
+
+	// ShareCreateFailedReason used when the share, its share network or its access rule could not be created.
+	ShareCreateFailedReason = "ShareCreateFailed"
+	// ShareNotReadyReason used when the share or its access rule are not available yet.
+	ShareNotReadyReason = "ShareNotReady"
+	// ShareSecretFailedReason used when the Secret of the share could not be published in the workload cluster.
+	ShareSecretFailedReason = "ShareSecretFailed"
+	// ShareDeleteFailedReason used when the share or its share network could not be deleted.
+	ShareDeleteFailedReason = "ShareDeleteFailed"
+)
+
+const (
+	// AddonsReadyCondition reports on the current status of the addons of the workload cluster. Ready indicates the cloud.conf and the enabled addons are applied.
+	AddonsReadyCondition clusterv1.ConditionType = "AddonsReady"
+
+	// WaitingForControlPlaneReason used when the addons are not applied yet because the control plane of the workload cluster is not initialized.
+	WaitingForControlPlaneReason = "WaitingForControlPlane"
+	// AddonsApplyFailedReason used when the cloud.conf or the addons could not be rendered or applied to the workload cluster.
+	AddonsApplyFailedReason = "AddonsApplyFailed"
+)
+
+const (
+	// InstanceHAReadyCondition reports on the current status of instance high availability with Masakari. Ready indicates the segment exists and the failure notifications are processed.
+	InstanceHAReadyCondition clusterv1.ConditionType = "InstanceHAReady"
+
+	// InstanceHAUnavailableReason used when the cloud does not expose Masakari or its API cannot be used with the credentials of the cluster.
+	InstanceHAUnavailableReason = "InstanceHAUnavailable"
+	// InstanceHASegmentNotFoundReason used when the Masakari segment of the cluster does not exist.
+	InstanceHASegmentNotFoundReason = "InstanceHASegmentNotFound"
+	// InstanceHAReconcileFailedReason used when the notifications could not be listed or the failed machines could not be updated.
+	InstanceHAReconcileFailedReason = "InstanceHAReconcileFailed"
+)
+
+const (
+	// HostMaintenanceReadyCondition reports on the current status of the handling of host maintenance. Ready indicates the compute hosts of the machines are watched.
+	HostMaintenanceReadyCondition clusterv1.ConditionType = "HostMaintenanceReady"
+
+	// ComputeHostsNotVisibleReason used when the compute services are not visible with the credentials of the cluster, so only migrations of instances are detected.
+	ComputeHostsNotVisibleReason = "ComputeHostsNotVisible"
+	// HostMaintenanceReconcileFailedReason used when the maintenance of the compute hosts could not be determined or the nodes could not be updated.
+	HostMaintenanceReconcileFailedReason = "HostMaintenanceReconcileFailed"
+)
+
+const (
+	// ServiceNodePortsReadyCondition reports on the current status of the node port rules generated from the Services of the workload cluster. Ready indicates the node ports of the Services are known.
+	ServiceNodePortsReadyCondition clusterv1.ConditionType = "ServiceNodePortsReady"
+
+	// ServiceNodePortsListFailedReason used when the Services of the workload cluster could not be listed.
+	ServiceNodePortsListFailedReason = "ServiceNodePortsListFailed"
+)
+
+const (
+	// ObjectStorageReadyCondition reports on the current status of the Swift container of the cluster. Ready indicates the container exists and can be used for temporary URLs.
+	ObjectStorageReadyCondition clusterv1.ConditionType = "ObjectStorageReady"
+
+	// ObjectStorageCreateFailedReason used when the container could not be created or updated.
+	ObjectStorageCreateFailedReason = "ObjectStorageCreateFailed"
+	// ObjectStorageDeleteFailedReason used when the container or its objects could not be deleted.
+	ObjectStorageDeleteFailedReason = "ObjectStorageDeleteFailed"
+)
+
+const (
+	// DNSRecordsReadyCondition reports on the current status of the Designate records of a machine. Ready indicates the records point to the addresses of the management port of the machine.
+	DNSRecordsReadyCondition clusterv1.ConditionType = "DNSRecordsReady"
+
+	// DNSRecordsCreateFailedReason used when the records could not be created or updated.
+	DNSRecordsCreateFailedReason = "DNSRecordsCreateFailed"
+	// DNSRecordsDeleteFailedReason used when the records could not be deleted.
+	DNSRecordsDeleteFailedReason = "DNSRecordsDeleteFailed"
+)
+
+const (
+	// QuotaExceededCondition is present while the quotas of the project do not leave enough for the OpenStack resources which are about to be created. Its message lists the exhausted resources.
+	QuotaExceededCondition clusterv1.ConditionType = "QuotaExceeded"
+
+	// InsufficientQuotaReason used when OpenStack resources are not created because the quota of at least one resource is exhausted.
+	InsufficientQuotaReason = "InsufficientQuota"
+)
+
+const (
+	// UnsupportedCloudCondition is present while the cloud lacks services or API extensions which are required by the spec of the OpenStackCluster. Its message lists the missing ones.
+	UnsupportedCloudCondition clusterv1.ConditionType = "UnsupportedCloud"
+
+	// MissingCloudFeaturesReason used when OpenStack resources are not created because the cloud lacks required services or API extensions.
+	MissingCloudFeaturesReason = "MissingCloudFeatures"
+)
+
+const (
+	// ClusterNetworkConflictCondition is present while the pod or service CIDRs of the Cluster overlap the subnets of its nodes, or the MTU of the cluster network is too small for the CNI overlay. Its message lists the problems.
+	ClusterNetworkConflictCondition clusterv1.ConditionType = "ClusterNetworkConflict"
+
+	// ClusterNetworkConflictReason used when the network configuration of the Cluster and the OpenStackCluster conflict.
+	ClusterNetworkConflictReason = "ClusterNetworkConflict"
+)
+
+const (
+	// ProviderIDMismatchCondition is present while the providerID of the Node of an OpenStackMachine does not refer to the instance of the machine, e.g. after the instance was rebuilt or replaced out of band. Its message names both instances.
+	ProviderIDMismatchCondition clusterv1.ConditionType = "ProviderIDMismatch"
+
+	// NodeProviderIDMismatchReason used when the cloud controller manager and CAPO disagree on the instance of a node.
+	NodeProviderIDMismatchReason = "NodeProviderIDMismatch"
+)
+
+const (
+	// DeletionInProgressReason used when a resource could not be deleted yet because the resources using it are still being deleted.
+	DeletionInProgressReason = "DeletionInProgress"
+)
+
+const (
+	// ApprovalDeniedReason used when the approval webhook denied the creation of a security group rule or floating IP.
+	ApprovalDeniedReason = "ApprovalDenied"
+)
+
+const (
+	// FloatingIPPoolReadyCondition reports on the current status of an OpenStackFloatingIPPool. Ready indicates that the pool allocated floating IPs to all its claims.
+	FloatingIPPoolReadyCondition clusterv1.ConditionType = "FloatingIPPoolReady"
+
+	// FloatingIPNetworkNotFoundReason used when the external network or subnet of the pool could not be found.
+	FloatingIPNetworkNotFoundReason = "FloatingIPNetworkNotFound"
+	// FloatingIPPoolExhaustedReason used when a claim is not allocated a floating IP because the pool reached its maximum number of floating IPs.
+	FloatingIPPoolExhaustedReason = "FloatingIPPoolExhausted"
+	// FloatingIPAllocateFailedReason used when a floating IP could not be created or allocated to a claim.
+	FloatingIPAllocateFailedReason = "FloatingIPAllocateFailed"
+	// WaitingForFloatingIPReason used when a floating IP was claimed from a pool but not allocated yet.
+	WaitingForFloatingIPReason = "WaitingForFloatingIP"
+)
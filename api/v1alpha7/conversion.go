@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ConversionReportAnnotation is set by the conversion to an older API version
+// on objects setting spec fields which the older version cannot represent.
+// It lists the paths of these fields in v1alpha7. Their values are preserved
+// in the conversion data annotation and restored when the object is converted
+// back.
+const ConversionReportAnnotation = "infrastructure.cluster.x-k8s.io/conversion-report"
+
+// Hub marks OpenStackCluster as a conversion hub.
+func (*OpenStackCluster) Hub() {}
+
+// Hub marks OpenStackClusterList as a conversion hub.
+func (*OpenStackClusterList) Hub() {}
+
+// Hub marks OpenStackClusterTemplate as a conversion hub.
+func (*OpenStackClusterTemplate) Hub() {}
+
+// Hub marks OpenStackClusterTemplateList as a conversion hub.
+func (*OpenStackClusterTemplateList) Hub() {}
+
+// Hub marks OpenStackMachine as a conversion hub.
+func (*OpenStackMachine) Hub() {}
+
+// Hub marks OpenStackMachineList as a conversion hub.
+func (*OpenStackMachineList) Hub() {}
+
+// Hub marks OpenStackMachineTemplate as a conversion hub.
+func (*OpenStackMachineTemplate) Hub() {}
+
+// Hub marks OpenStackMachineTemplateList as a conversion hub.
+func (*OpenStackMachineTemplateList) Hub() {}
+
+// SetConversionReport sets the ConversionReportAnnotation of dst, which was
+// converted from hub, to the fields of the spec of hub which differ in
+// roundTrip, the result of converting dst back to v1alpha7 without restoring
+// the conversion data. Only the spec is compared, because the status is owned
+// by the controllers, which rewrite it in v1alpha7. The annotation is removed
+// if the conversion was lossless.
+func SetConversionReport(dst metav1.Object, hub, roundTrip runtime.Object) error {
+	hubValue, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hub)
+	if err != nil {
+		return err
+	}
+	roundTripValue, err := runtime.DefaultUnstructuredConverter.ToUnstructured(roundTrip)
+	if err != nil {
+		return err
+	}
+
+	var lost []string
+	for _, path := range changedFields(field.NewPath("spec"), hubValue["spec"], roundTripValue["spec"]) {
+		lost = append(lost, path.String())
+	}
+
+	annotations := dst.GetAnnotations()
+	if len(lost) == 0 {
+		delete(annotations, ConversionReportAnnotation)
+		return nil
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ConversionReportAnnotation] = strings.Join(lost, ", ")
+	dst.SetAnnotations(annotations)
+	return nil
+}
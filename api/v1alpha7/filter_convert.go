@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+)
+
+func (subnetFilter SubnetFilter) ToListOpt() subnets.ListOpts {
+	return subnets.ListOpts{
+		Name:            subnetFilter.Name,
+		Description:     subnetFilter.Description,
+		ProjectID:       subnetFilter.ProjectID,
+		IPVersion:       subnetFilter.IPVersion,
+		GatewayIP:       subnetFilter.GatewayIP,
+		CIDR:            subnetFilter.CIDR,
+		IPv6AddressMode: subnetFilter.IPv6AddressMode,
+		IPv6RAMode:      subnetFilter.IPv6RAMode,
+		ID:              subnetFilter.ID,
+		Tags:            joinTags(subnetFilter.Tags),
+		TagsAny:         joinTags(subnetFilter.TagsAny),
+		NotTags:         joinTags(subnetFilter.NotTags),
+		NotTagsAny:      joinTags(subnetFilter.NotTagsAny),
+	}
+}
+
+func (networkFilter NetworkFilter) ToListOpt() networks.ListOpts {
+	return networks.ListOpts{
+		Name:        networkFilter.Name,
+		Description: networkFilter.Description,
+		ProjectID:   networkFilter.ProjectID,
+		ID:          networkFilter.ID,
+		Tags:        joinTags(networkFilter.Tags),
+		TagsAny:     joinTags(networkFilter.TagsAny),
+		NotTags:     joinTags(networkFilter.NotTags),
+		NotTagsAny:  joinTags(networkFilter.NotTagsAny),
+	}
+}
+
+func (securityGroupFilter SecurityGroupFilter) ToListOpt() groups.ListOpts {
+	return groups.ListOpts{
+		ID:          securityGroupFilter.ID,
+		Name:        securityGroupFilter.Name,
+		Description: securityGroupFilter.Description,
+		ProjectID:   securityGroupFilter.ProjectID,
+		Tags:        joinTags(securityGroupFilter.Tags),
+		TagsAny:     joinTags(securityGroupFilter.TagsAny),
+		NotTags:     joinTags(securityGroupFilter.NotTags),
+		NotTagsAny:  joinTags(securityGroupFilter.NotTagsAny),
+	}
+}
+
+// IsEmpty returns true if no field of the filter is set.
+func (networkFilter NetworkFilter) IsEmpty() bool {
+	return networkFilter.Name == "" && networkFilter.Description == "" && networkFilter.ProjectID == "" &&
+		networkFilter.ID == "" && networkFilter.FilterByNeutronTags.IsEmpty()
+}
+
+// IsEmpty returns true if no field of the filter is set.
+func (subnetFilter SubnetFilter) IsEmpty() bool {
+	return subnetFilter.Name == "" && subnetFilter.Description == "" && subnetFilter.ProjectID == "" &&
+		subnetFilter.IPVersion == 0 && subnetFilter.GatewayIP == "" && subnetFilter.CIDR == "" &&
+		subnetFilter.IPv6AddressMode == "" && subnetFilter.IPv6RAMode == "" && subnetFilter.ID == "" &&
+		subnetFilter.FilterByNeutronTags.IsEmpty()
+}
+
+// IsEmpty returns true if no field of the filter is set.
+func (securityGroupFilter SecurityGroupFilter) IsEmpty() bool {
+	return securityGroupFilter.ID == "" && securityGroupFilter.Name == "" && securityGroupFilter.Description == "" &&
+		securityGroupFilter.ProjectID == "" && securityGroupFilter.FilterByNeutronTags.IsEmpty()
+}
+
+// IsIDOnly returns true if the security group is selected by its ID alone,
+// so that it does not need to be looked up.
+func (securityGroupFilter SecurityGroupFilter) IsIDOnly() bool {
+	return securityGroupFilter.ID != "" && securityGroupFilter.Name == "" && securityGroupFilter.Description == "" &&
+		securityGroupFilter.ProjectID == "" && securityGroupFilter.FilterByNeutronTags.IsEmpty()
+}
+
+// IsEmpty returns true if no tags are set.
+func (tags FilterByNeutronTags) IsEmpty() bool {
+	return len(tags.Tags) == 0 && len(tags.TagsAny) == 0 && len(tags.NotTags) == 0 && len(tags.NotTagsAny) == 0
+}
+
+// joinTags joins tags into the comma separated list expected by the
+// OpenStack API.
+func joinTags(tags []NeutronTag) string {
+	var b strings.Builder
+	for i, tag := range tags {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(string(tag))
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+const defaultIdentityRefKind = "Secret"
+
+// IdentityAllowedNamespacesAnnotation is set on an identity Secret to allow
+// its use by the resources in other namespaces. Its value is a comma
+// separated list of namespaces, or "*" to allow all namespaces.
+const IdentityAllowedNamespacesAnnotation = "infrastructure.cluster.x-k8s.io/allowed-namespaces"
+
+// OpenStackIdentityReference is a reference to an infrastructure
+// provider identity to be used to provision cluster resources.
+type OpenStackIdentityReference struct {
+	// Kind of the identity. Must be supported by the infrastructure
+	// provider and may be either cluster or namespace-scoped.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Name of the infrastructure identity to be used.
+	// Must be either a cluster-scoped resource, or namespaced-scoped
+	// resource the same namespace as the resource(s) being provisioned.
+	Name string `json:"name"`
+
+	// Namespace of the identity, if it is not in the namespace of the
+	// resource(s) being provisioned. The Secret must allow its use from
+	// the namespace of the resource(s) with the
+	// infrastructure.cluster.x-k8s.io/allowed-namespaces annotation, so
+	// that credentials in a central namespace can be shared by the clusters
+	// of a fleet.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
@@ -0,0 +1,824 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+)
+
+const (
+	// ClusterFinalizer allows ReconcileOpenStackCluster to clean up OpenStack resources associated with OpenStackCluster before
+	// removing it from the apiserver.
+	ClusterFinalizer = "openstackcluster.infrastructure.cluster.x-k8s.io"
+
+	// DryRunAnnotation makes the controller compute the changes to OpenStack
+	// resources it would apply to an OpenStackCluster and publish them in
+	// status.dryRunPlan instead of applying them, when set to "true".
+	DryRunAnnotation = "infrastructure.cluster.x-k8s.io/dry-run"
+
+	// ResourceIDsAnnotation records the IDs of the OpenStack resources of an
+	// OpenStackCluster. Unlike the status it is preserved by clusterctl move,
+	// so that the resources are re-adopted by their IDs after a move.
+	ResourceIDsAnnotation = "infrastructure.cluster.x-k8s.io/resource-ids"
+
+	// BastionPausedAnnotation freezes the bastion of an OpenStackCluster while
+	// it is set, e.g. to investigate it: the bastion is neither created,
+	// replaced nor deleted, while the rest of the cluster is reconciled.
+	BastionPausedAnnotation = "infrastructure.cluster.x-k8s.io/bastion-paused"
+
+	// InventoryAnnotation makes the controller export an inventory of the
+	// OpenStack resources of an OpenStackCluster as JSON in the ConfigMap
+	// <name>-inventory, when set to "true".
+	InventoryAnnotation = "infrastructure.cluster.x-k8s.io/export-inventory"
+
+	// DebugHTTPAnnotation makes the controllers log the HTTP requests to
+	// OpenStack and their responses for an OpenStackCluster, its machines and
+	// their servers, with credentials and tokens redacted, when set to
+	// "true".
+	DebugHTTPAnnotation = "infrastructure.cluster.x-k8s.io/debug-http"
+
+	// HostMaintenanceCordonAnnotation is set on the nodes of the workload
+	// cluster which were cordoned because the compute host of their instance
+	// is under maintenance, so that only those nodes are uncordoned again.
+	HostMaintenanceCordonAnnotation = "infrastructure.cluster.x-k8s.io/cordoned-for-host-maintenance"
+
+	// DeletionProtectedAnnotation protects an OpenStackCluster and its
+	// Cluster from being deleted while it is set to "true". Their deletion
+	// is only admitted once ConfirmDeletionAnnotation is also set to the name
+	// of the OpenStackCluster.
+	DeletionProtectedAnnotation = "infrastructure.cluster.x-k8s.io/deletion-protected"
+
+	// ConfirmDeletionAnnotation confirms the deletion of an OpenStackCluster
+	// protected by DeletionProtectedAnnotation when set to its name.
+	ConfirmDeletionAnnotation = "infrastructure.cluster.x-k8s.io/confirm-deletion"
+
+	// HostMaintenanceNodeCondition is the condition of the nodes of the
+	// workload cluster whose instance is affected by host maintenance. It can
+	// be used in the unhealthy conditions of a MachineHealthCheck.
+	HostMaintenanceNodeCondition = "OpenStackHostMaintenance"
+
+	// HostMaintenanceDrainedNodeCondition is the condition of the nodes of
+	// the workload cluster which were drained because their instance is
+	// affected by host maintenance. A MachineHealthCheck using it in its
+	// unhealthy conditions only replaces machines once their node is drained.
+	HostMaintenanceDrainedNodeCondition = "OpenStackHostMaintenanceDrained"
+)
+
+// OpenStackClusterSpec defines the desired state of OpenStackCluster.
+type OpenStackClusterSpec struct {
+	// The name of the cloud to use from the clouds secret
+	// +optional
+	CloudName string `json:"cloudName"`
+
+	// NodeCIDR is the OpenStack Subnet to be created. Cluster actuator will create a
+	// network, a subnet with NodeCIDR, and a router connected to this subnet.
+	// If you leave this empty, no network will be created.
+	NodeCIDR string `json:"nodeCidr,omitempty"`
+
+	// If NodeCIDR cannot be set this can be used to detect an existing network.
+	Network NetworkFilter `json:"network,omitempty"`
+
+	// If NodeCIDR cannot be set this can be used to detect an existing subnet.
+	Subnet SubnetFilter `json:"subnet,omitempty"`
+
+	// DNSNameservers is the list of nameservers for OpenStack Subnet being created.
+	// Set this value when you need create a new network/subnet while the access
+	// through DNS is required.
+	// +listType=set
+	DNSNameservers []string `json:"dnsNameservers,omitempty"`
+	// ExternalRouterIPs is an array of externalIPs on the respective subnets.
+	// This is necessary if the router needs a fixed ip in a specific subnet.
+	ExternalRouterIPs []ExternalRouterIPParam `json:"externalRouterIPs,omitempty"`
+	// RouterRoutes are static routes of the router of the cluster network,
+	// e.g. to reach other networks through an appliance in the cluster
+	// network. They require NodeCIDR, so that the router is created by CAPO.
+	// +optional
+	RouterRoutes []RouterRoute `json:"routerRoutes,omitempty"`
+	// RouterSubnets are existing subnets, e.g. the subnets of failure
+	// domains, which are connected to the router of the cluster network
+	// besides the subnets created by CAPO, so that they gain external
+	// connectivity. Each filter must match exactly one subnet. They require
+	// NodeCIDR, so that the router is created by CAPO.
+	// +optional
+	RouterSubnets []SubnetFilter `json:"routerSubnets,omitempty"`
+	// ExternalNetworkID is the ID of an external OpenStack Network. This is necessary
+	// to get public internet to the VMs.
+	// +optional
+	ExternalNetworkID string `json:"externalNetworkId,omitempty"`
+
+	// RouterFirewall creates a Neutron FWaaS v2 firewall group with the
+	// given firewall policies on the interfaces of the router of the
+	// cluster network, for clouds which mandate filtering at the router. It
+	// requires NodeCIDR, so that the router is created by CAPO.
+	// +optional
+	RouterFirewall *RouterFirewall `json:"routerFirewall,omitempty"`
+
+	// APIServerLoadBalancer configures the optional LoadBalancer for the APIServer.
+	// It must be activated by setting `enabled: true`.
+	// +optional
+	APIServerLoadBalancer APIServerLoadBalancer `json:"apiServerLoadBalancer,omitempty"`
+
+	// DisableAPIServerFloatingIP determines whether or not to attempt to attach a floating
+	// IP to the API server. This allows for the creation of clusters when attaching a floating
+	// IP to the API server (and hence, in many cases, exposing the API server to the internet)
+	// is not possible or desirable, e.g. if using a shared VLAN for communication between
+	// management and workload clusters or when the management cluster is inside the
+	// project network.
+	// This option requires that the API server use a VIP on the cluster network so that the
+	// underlying machines can change without changing ControlPlaneEndpoint.Host.
+	// When using a managed load balancer, this VIP will be managed automatically.
+	// If not using a managed load balancer, cluster configuration will fail without additional
+	// configuration to manage the VIP on the control plane machines, which falls outside of
+	// the scope of this controller.
+	// +optional
+	DisableAPIServerFloatingIP bool `json:"disableAPIServerFloatingIP"`
+
+	// APIServerFloatingIP is the floatingIP which will be associated with the API server.
+	// The floatingIP will be created if it does not already exist.
+	// If not specified, a new floatingIP is allocated.
+	// This field is not used if DisableAPIServerFloatingIP is set to true.
+	APIServerFloatingIP string `json:"apiServerFloatingIP,omitempty"`
+
+	// APIServerFloatingIPPoolRef is a reference to an IPAM pool, usually an
+	// OpenStackFloatingIPPool, from which the floating IP of the API server
+	// is claimed. It cannot be combined with APIServerFloatingIP.
+	// This field is not used if DisableAPIServerFloatingIP is set to true.
+	// +optional
+	APIServerFloatingIPPoolRef *corev1.TypedLocalObjectReference `json:"apiServerFloatingIPPoolRef,omitempty"`
+
+	// APIServerFixedIP is the fixed IP which will be associated with the API server.
+	// In the case where the API server has a floating IP but not a managed load balancer,
+	// this field is not used.
+	// If a managed load balancer is used and this field is not specified, a fixed IP will
+	// be dynamically allocated for the load balancer.
+	// If a managed load balancer is not used AND the API server floating IP is disabled,
+	// this field MUST be specified and should correspond to a pre-allocated port that
+	// holds the fixed IP to be used as a VIP.
+	APIServerFixedIP string `json:"apiServerFixedIP,omitempty"`
+
+	// APIServerPort is the port on which the listener on the APIServer
+	// will be created
+	APIServerPort int `json:"apiServerPort,omitempty"`
+
+	// ManagedSecurityGroups determines whether OpenStack security groups for the cluster
+	// will be managed by the OpenStack provider. If it is not set, pre-existing security
+	// groups must be referenced by the securityGroups of the machines instead.
+	// The managed security groups have rules that allow the Kubelet, etcd, the
+	// Kubernetes API server and the Calico CNI plugin to function correctly.
+	// +optional
+	ManagedSecurityGroups *ManagedSecurityGroups `json:"managedSecurityGroups,omitempty"`
+
+	// DisablePortSecurity disables the port security of the network created for the
+	// Kubernetes cluster, which also disables SecurityGroups
+	DisablePortSecurity bool `json:"disablePortSecurity,omitempty"`
+
+	// Tags for all resources in cluster
+	// +listType=set
+	Tags []string `json:"tags,omitempty"`
+
+	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
+	// +optional
+	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// ExternalAPIServerEndpoint takes controlPlaneEndpoint as is, e.g. the
+	// name of a global load balancer, for clusters whose API server VIP is
+	// handled entirely outside of OpenStack. No load balancer or floating IP
+	// is created for the API server, and only the rule of the managed
+	// security groups for the API server port is managed.
+	// It requires controlPlaneEndpoint to be set.
+	// +optional
+	ExternalAPIServerEndpoint *ExternalAPIServerEndpoint `json:"externalAPIServerEndpoint,omitempty"`
+
+	// ControlPlaneAvailabilityZones is the az to deploy control plane to
+	// +listType=set
+	ControlPlaneAvailabilityZones []string `json:"controlPlaneAvailabilityZones,omitempty"`
+
+	// Indicates whether to omit the az for control plane nodes, allowing the Nova scheduler
+	// to make a decision on which az to use based on other scheduling constraints
+	ControlPlaneOmitAvailabilityZone bool `json:"controlPlaneOmitAvailabilityZone,omitempty"`
+
+	// FailureDomains configures the failure domains of the cluster. By
+	// default, every compute availability zone is a failure domain.
+	// +optional
+	FailureDomains *FailureDomainsConfig `json:"failureDomains,omitempty"`
+
+	// ServerGroups creates a server group in every failure domain of the
+	// cluster. Machines without serverGroupID are added to the server group
+	// of their failure domain, as Nova only enforces some policies between
+	// members in the same availability zone.
+	// +optional
+	ServerGroups *ManagedServerGroups `json:"serverGroups,omitempty"`
+
+	// Bastion is the OpenStack instance to login the nodes
+	//
+	// As a rolling update is not ideal during a bastion host session, we
+	// prevent changes to a running bastion configuration. Set `enabled: false` to
+	// make changes.
+	//+optional
+	Bastion *Bastion `json:"bastion,omitempty"`
+
+	// Share is a Manila share created for the cluster, e.g. for persistent
+	// volumes shared by the workloads of the cluster through the Manila CSI
+	// driver. The export location and credentials of the share are published
+	// in a Secret of the workload cluster.
+	// +optional
+	Share *ManagedShare `json:"share,omitempty"`
+
+	// Addons are the OpenStack cloud provider components installed into the
+	// workload cluster once its control plane is initialized. They are
+	// configured with a cloud.conf rendered from the identity, network and
+	// load balancer of the cluster.
+	// +optional
+	Addons *Addons `json:"addons,omitempty"`
+
+	// InstanceHA enables instance high availability with Masakari for the
+	// machines of the cluster. The instances are created with the HA_Enabled
+	// metadata so that Masakari recovers them, and the failures Masakari
+	// reports for them are used to remediate their machines.
+	// +optional
+	InstanceHA *InstanceHA `json:"instanceHA,omitempty"`
+
+	// HostMaintenance watches the compute hosts of the machines of the
+	// cluster. The nodes of machines whose host is disabled for maintenance,
+	// or whose instance is being migrated, are cordoned and get the
+	// OpenStackHostMaintenance condition, so that they can be replaced by a
+	// MachineHealthCheck before the host is rebooted. The cordoned nodes can
+	// also be drained.
+	// +optional
+	HostMaintenance *HostMaintenance `json:"hostMaintenance,omitempty"`
+
+	// ObjectStorage creates a Swift container for the cluster. Bootstrap data
+	// which exceeds the user data limit of Nova is stored in the container
+	// and fetched by cloud-init through a temporary URL. The container can
+	// also keep audit copies of the bootstrap data and the console logs of
+	// failed machines. It is deleted with all its objects when the cluster
+	// is deleted.
+	// +optional
+	ObjectStorage *ObjectStorage `json:"objectStorage,omitempty"`
+
+	// MachineDNS creates Designate records for the machines of the cluster,
+	// so that tools which cannot use the DNS of the workload cluster reach
+	// the nodes by stable FQDNs. The records are deleted with their machines.
+	// +optional
+	MachineDNS *MachineDNS `json:"machineDNS,omitempty"`
+
+	// DeletionPolicy keeps classes of the OpenStack resources of the cluster
+	// when it is deleted, e.g. a network which is shared with other clusters
+	// or workloads. By default, all of them are deleted.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ReconcileTimeouts configures how long the controller waits for the
+	// OpenStack resources of the cluster and its machines to reach their
+	// desired state.
+	// +optional
+	ReconcileTimeouts *ReconcileTimeouts `json:"reconcileTimeouts,omitempty"`
+
+	// CNIOverlay is the encapsulation used by the CNI of the workload
+	// cluster. If set, the MTU of the cluster network is checked on
+	// admission to leave pods an MTU of at least 1280 bytes once the
+	// encapsulation overhead is subtracted.
+	// +optional
+	CNIOverlay CNIOverlay `json:"cniOverlay,omitempty"`
+
+	// MachineDefaults are the defaults of the machines of the cluster. A
+	// field set in the spec of a machine overrides the default.
+	// +optional
+	MachineDefaults *MachineDefaults `json:"machineDefaults,omitempty"`
+
+	// MaintenanceWindows are the recurring times at which disruptive changes
+	// to the OpenStack resources of the cluster, e.g. rebuilding the bastion
+	// after its spec changed, are applied. Outside of them these changes are
+	// deferred and listed in status.pendingChanges. If unset, they are
+	// applied right away.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// IdentityRef is a reference to a identity to be used when reconciling this cluster
+	// +optional
+	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
+}
+
+// ManagedSecurityGroups configures the security groups managed for the
+// control plane, worker and bastion machines of a cluster.
+type ManagedSecurityGroups struct {
+	// AllowAllInClusterTraffic configures the rules of the managed security
+	// groups so that all ingress and egress between cluster nodes is permitted,
+	// allowing CNIs other than Calico to be used.
+	// +optional
+	AllowAllInClusterTraffic bool `json:"allowAllInClusterTraffic"`
+
+	// IPv6 adds ingress rules for the ICMPv6 router advertisements and
+	// neighbor solicitations and advertisements of IPv6 neighbor discovery to
+	// the managed security groups. Nodes with IPv6 addresses require them on
+	// clouds whose firewall drops ICMPv6 without a matching rule, e.g.
+	// ML2/OVS.
+	// +optional
+	IPv6 bool `json:"ipv6,omitempty"`
+
+	// NodePortsFromServices restricts the node port ingress of the worker
+	// security group to the node ports allocated to the Services of the
+	// workload cluster, instead of opening the whole node port range. The
+	// Services are checked periodically once the control plane is
+	// initialized, so a new node port is opened with a delay of up to a
+	// minute.
+	// +optional
+	NodePortsFromServices bool `json:"nodePortsFromServices,omitempty"`
+
+	// RuleProfiles replace the rules CAPO manages for the security group of
+	// a role with the rules of an existing security group, e.g. one whose
+	// rules are owned by a security team. CAPO still creates, attaches and
+	// deletes the security groups of the roles, and keeps their rules in
+	// sync with the profiles.
+	// +optional
+	RuleProfiles *SecurityGroupRuleProfiles `json:"ruleProfiles,omitempty"`
+}
+
+// SecurityGroupRuleProfiles are the security groups whose rules replace the
+// managed rules of the security groups of the control plane, worker and
+// bastion machines. Rules of a profile whose remote group is the profile
+// itself refer to the managed security group instead. The managed rules of
+// a role without a profile are kept.
+type SecurityGroupRuleProfiles struct {
+	// ControlPlane is the profile of the control plane security group.
+	// +optional
+	ControlPlane *SecurityGroupFilter `json:"controlPlane,omitempty"`
+
+	// Worker is the profile of the worker security group.
+	// +optional
+	Worker *SecurityGroupFilter `json:"worker,omitempty"`
+
+	// Bastion is the profile of the bastion security group.
+	// +optional
+	Bastion *SecurityGroupFilter `json:"bastion,omitempty"`
+}
+
+// ExternalAPIServerEndpoint configures the access to the API server of a
+// cluster whose endpoint is provided outside of OpenStack.
+type ExternalAPIServerEndpoint struct {
+	// AllowedCIDRs restrict the ingress to the API server port of the
+	// control plane machines to the given address CIDRs, e.g. those of the
+	// external load balancer. If empty, the ingress is allowed from anywhere.
+	// +listType=set
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// RouterFirewall configures the firewall group of the router of a cluster.
+// The directions are those of Neutron: ingress is the traffic entering the
+// router from the cluster network, egress the traffic leaving the router into
+// the cluster network.
+type RouterFirewall struct {
+	// IngressPolicy is the firewall policy of the ingress traffic.
+	// +optional
+	IngressPolicy *FirewallPolicyFilter `json:"ingressPolicy,omitempty"`
+
+	// EgressPolicy is the firewall policy of the egress traffic.
+	// +optional
+	EgressPolicy *FirewallPolicyFilter `json:"egressPolicy,omitempty"`
+}
+
+// FirewallPolicyFilter selects an existing firewall policy by its ID, or by
+// its name, which must match exactly one firewall policy.
+type FirewallPolicyFilter struct {
+	// ID of the firewall policy.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name of the firewall policy.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// MachineDefaults are the defaults of the OpenStackMachines of a cluster, so
+// that they do not have to be repeated in every OpenStackMachineTemplate.
+type MachineDefaults struct {
+	// SSHKeyName is the ssh key of the machines without sshKeyName.
+	// +optional
+	SSHKeyName string `json:"sshKeyName,omitempty"`
+
+	// ServerMetadata is added to the server metadata of the machines. The
+	// serverMetadata of a machine takes precedence for the same key.
+	// +optional
+	ServerMetadata map[string]string `json:"serverMetadata,omitempty"`
+
+	// ServerGroupID is the server group of the machines without
+	// serverGroupID, e.g. a server group with an anti-affinity policy.
+	// +optional
+	ServerGroupID string `json:"serverGroupID,omitempty"`
+
+	// FallbackFailureDomains are the fallback failure domains of the
+	// machines without fallbackFailureDomains.
+	// +listType=set
+	// +optional
+	FallbackFailureDomains []string `json:"fallbackFailureDomains,omitempty"`
+
+	// IdentityRef is the identity of the machines without identityRef,
+	// instead of the identity of the cluster.
+	// +optional
+	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
+
+	// CloudName is the name of the cloud of IdentityRef.
+	// +optional
+	CloudName string `json:"cloudName,omitempty"`
+}
+
+// DeletionPolicyType is the policy of a class of OpenStack resources when
+// their cluster is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicyType string
+
+const (
+	// DeletionPolicyDelete deletes the resources together with the cluster.
+	DeletionPolicyDelete DeletionPolicyType = "Delete"
+	// DeletionPolicyRetain keeps the resources when the cluster is deleted.
+	DeletionPolicyRetain DeletionPolicyType = "Retain"
+)
+
+// DeletionPolicy configures which OpenStack resources created for a cluster
+// are kept when it is deleted.
+type DeletionPolicy struct {
+	// Network is the policy of the network, subnets and router created for
+	// the nodeCidr of the cluster and of its failure domains.
+	// +kubebuilder:default=Delete
+	// +optional
+	Network DeletionPolicyType `json:"network,omitempty"`
+
+	// Volumes is the policy of the root volumes of the machines. Nova only
+	// keeps the root volumes of servers created while it is Retain, so it
+	// does not apply to existing machines.
+	// +kubebuilder:default=Delete
+	// +optional
+	Volumes DeletionPolicyType `json:"volumes,omitempty"`
+
+	// FloatingIPs is the policy of the floating IPs allocated for the API
+	// server, its load balancer and the bastion.
+	// +kubebuilder:default=Delete
+	// +optional
+	FloatingIPs DeletionPolicyType `json:"floatingIPs,omitempty"`
+}
+
+// ReconcileTimeouts are the durations for which the controller waits for
+// OpenStack resources to reach their desired state before it reports a
+// failure. The reconcile is retried afterwards.
+type ReconcileTimeouts struct {
+	// InstanceCreate is how long to wait for a server and its root volume to
+	// become active. Defaults to 5m, or to the number of minutes in the
+	// CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT environment variable of
+	// the controller.
+	// +optional
+	InstanceCreate *metav1.Duration `json:"instanceCreate,omitempty"`
+
+	// LoadBalancerCreate is how long to wait for the API server load
+	// balancer to become active after each of its changes. Defaults to 10m.
+	// +optional
+	LoadBalancerCreate *metav1.Duration `json:"loadBalancerCreate,omitempty"`
+
+	// FloatingIPAssociate is how long to wait for a floating IP to become
+	// active once it is associated with a port, or down once it is
+	// disassociated. Defaults to 5m.
+	// +optional
+	FloatingIPAssociate *metav1.Duration `json:"floatingIPAssociate,omitempty"`
+
+	// Deletion is how long to wait for a deleted server, root volume, port,
+	// trunk or load balancer to be gone. Defaults to 5m.
+	// +optional
+	Deletion *metav1.Duration `json:"deletion,omitempty"`
+}
+
+// MaintenanceWindow is a recurring time at which disruptive changes are
+// applied.
+type MaintenanceWindow struct {
+	// Days are the days of the week on which the window starts. If unset,
+	// the window starts every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the time of day in UTC at which the window starts, in the
+	// format HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is the length of the window, at most 24h.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// PendingChange is a disruptive change which is deferred until the next
+// maintenance window.
+type PendingChange struct {
+	// Resource is the resource which is changed, e.g. Bastion.
+	Resource string `json:"resource"`
+
+	// Change describes the change.
+	Change string `json:"change"`
+
+	// Since is the time at which the change was deferred first.
+	Since metav1.Time `json:"since"`
+}
+
+// FailureDomainsConfig configures how the failure domains of a cluster are
+// determined.
+type FailureDomainsConfig struct {
+	// AvailabilityZones limits the compute availability zones which become
+	// failure domains. If empty, all compute availability zones are used.
+	// +listType=set
+	// +optional
+	AvailabilityZones []string `json:"availabilityZones,omitempty"`
+
+	// ExcludedAvailabilityZones are compute availability zones which do not
+	// become failure domains.
+	// +listType=set
+	// +optional
+	ExcludedAvailabilityZones []string `json:"excludedAvailabilityZones,omitempty"`
+
+	// Mappings define the failure domains explicitly instead of discovering
+	// them from the compute availability zones. This is required for clouds
+	// whose compute and storage availability zones or subnets do not match.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Mappings []FailureDomainMapping `json:"mappings,omitempty"`
+
+	// BalanceWorkers assigns a failure domain to worker machines whose
+	// Machine does not have one, spreading them across the failure domains
+	// of the cluster. A machine whose instance finds no host with capacity
+	// in its failure domain is created again in another failure domain.
+	// +optional
+	BalanceWorkers bool `json:"balanceWorkers,omitempty"`
+}
+
+// FailureDomainMapping maps a failure domain to the availability zones,
+// subnet and server group used by the machines placed in it.
+type FailureDomainMapping struct {
+	// Name is the name of the failure domain.
+	Name string `json:"name"`
+
+	// ComputeAvailabilityZone is the availability zone of the servers. If
+	// empty, the Nova scheduler chooses the availability zone.
+	// +optional
+	ComputeAvailabilityZone string `json:"computeAvailabilityZone,omitempty"`
+
+	// StorageAvailabilityZone is the availability zone of the root volumes.
+	// It defaults to the compute availability zone. The availabilityZone of
+	// a root volume takes precedence.
+	// +optional
+	StorageAvailabilityZone string `json:"storageAvailabilityZone,omitempty"`
+
+	// Subnet is the subnet of the default port of the machines which do not
+	// set networks or ports. It defaults to the subnet of the cluster.
+	// +optional
+	Subnet *SubnetFilter `json:"subnet,omitempty"`
+
+	// NodeCIDR creates a subnet with this CIDR in the network of the cluster
+	// for the failure domain, which is used instead of Subnet. The subnet is
+	// connected to the router of the cluster. It requires the nodeCidr of the
+	// cluster, which creates the network.
+	// +optional
+	NodeCIDR string `json:"nodeCidr,omitempty"`
+
+	// ServerGroupID is the id of an existing server group which the machines
+	// placed in the failure domain join if they do not set serverGroupID.
+	// No managed server group is created for the failure domain.
+	// +optional
+	ServerGroupID string `json:"serverGroupID,omitempty"`
+}
+
+// ManagedServerGroups configures the server groups created for the failure
+// domains of a cluster.
+type ManagedServerGroups struct {
+	// Policy is the policy of the server groups.
+	// +kubebuilder:validation:Enum=anti-affinity;soft-anti-affinity;affinity;soft-affinity
+	// +kubebuilder:default=soft-anti-affinity
+	// +optional
+	Policy string `json:"policy,omitempty"`
+}
+
+const (
+	// FailureDomainComputeAvailabilityZoneAttribute is the attribute of a
+	// failure domain in the status which holds its compute availability zone.
+	FailureDomainComputeAvailabilityZoneAttribute = "computeAvailabilityZone"
+	// FailureDomainStorageAvailabilityZoneAttribute is the attribute of a
+	// failure domain in the status which holds its storage availability zone.
+	FailureDomainStorageAvailabilityZoneAttribute = "storageAvailabilityZone"
+	// FailureDomainNetworkIDAttribute is the attribute of a failure domain in
+	// the status which holds the network of its subnet.
+	FailureDomainNetworkIDAttribute = "networkID"
+	// FailureDomainSubnetIDAttribute is the attribute of a failure domain in
+	// the status which holds the id of its subnet.
+	FailureDomainSubnetIDAttribute = "subnetID"
+	// FailureDomainServerGroupIDAttribute is the attribute of a failure
+	// domain in the status which holds the id of its managed server group.
+	FailureDomainServerGroupIDAttribute = "serverGroupID"
+)
+
+// OpenStackClusterStatus defines the observed state of OpenStackCluster.
+type OpenStackClusterStatus struct {
+	Ready bool `json:"ready"`
+
+	// ObservedGeneration is the generation of the spec which was last
+	// reconciled successfully.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Network contains all information about the created OpenStack Network.
+	// It includes Subnets and Router.
+	Network *Network `json:"network,omitempty"`
+
+	// External Network contains information about the created OpenStack external network.
+	ExternalNetwork *Network `json:"externalNetwork,omitempty"`
+
+	// FailureDomains represent OpenStack availability zones
+	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
+
+	// ControlPlaneSecurityGroups contains all the information about the OpenStack
+	// Security Group that needs to be applied to control plane nodes.
+	// TODO: Maybe instead of two properties, we add a property to the group?
+	ControlPlaneSecurityGroup *SecurityGroup `json:"controlPlaneSecurityGroup,omitempty"`
+
+	// WorkerSecurityGroup contains all the information about the OpenStack Security
+	// Group that needs to be applied to worker nodes.
+	WorkerSecurityGroup *SecurityGroup `json:"workerSecurityGroup,omitempty"`
+
+	BastionSecurityGroup *SecurityGroup `json:"bastionSecurityGroup,omitempty"`
+
+	// RouterFirewallGroup is the firewall group of the router of the cluster
+	// network, if spec.routerFirewall is set.
+	// +optional
+	RouterFirewallGroup *FirewallGroup `json:"routerFirewallGroup,omitempty"`
+
+	// RouterRoutes are the static routes which CAPO added to the router of
+	// the cluster network. Other routes of the router are left as they are.
+	// +optional
+	RouterRoutes []RouterRoute `json:"routerRoutes,omitempty"`
+
+	Bastion *Instance `json:"bastion,omitempty"`
+
+	// Share contains information about the Manila share of the cluster.
+	// +optional
+	Share *Share `json:"share,omitempty"`
+
+	// Addons contains the versions of the addons installed into the workload
+	// cluster.
+	// +optional
+	Addons *AddonsStatus `json:"addons,omitempty"`
+
+	// InstanceHA contains the Masakari segment of the cluster and the
+	// notifications processed so far.
+	// +optional
+	InstanceHA *InstanceHAStatus `json:"instanceHA,omitempty"`
+
+	// ObjectStorage contains information about the Swift container of the
+	// cluster.
+	// +optional
+	ObjectStorage *ObjectStorageStatus `json:"objectStorage,omitempty"`
+
+	// ServiceNodePorts are the node ports of the Services of the workload
+	// cluster which are open in the worker security group if
+	// managedSecurityGroups.nodePortsFromServices is set.
+	// +optional
+	ServiceNodePorts []ServiceNodePort `json:"serviceNodePorts,omitempty"`
+
+	// PendingChanges are the disruptive changes which are deferred until the
+	// next maintenance window of the cluster.
+	// +optional
+	PendingChanges []PendingChange `json:"pendingChanges,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the OpenStackCluster and will contain a succinct value suitable
+	// for machine interpretation.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the OpenStackCluster's spec or the configuration of
+	// the controller, and that manual intervention is required. Examples
+	// of terminal errors would be invalid combinations of settings in the
+	// spec, values that are unsupported by the controller, or the
+	// responsible controller itself being critically misconfigured.
+	//
+	// Any transient errors that occur during the reconciliation of
+	// OpenStackClusters can be added as events to the OpenStackCluster object
+	// and/or logged in the controller's output.
+	// +optional
+	FailureReason *capierrors.ClusterStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the OpenStackCluster and will contain a more verbose string suitable
+	// for logging and human consumption.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the OpenStackCluster's spec or the configuration of
+	// the controller, and that manual intervention is required. Examples
+	// of terminal errors would be invalid combinations of settings in the
+	// spec, values that are unsupported by the controller, or the
+	// responsible controller itself being critically misconfigured.
+	//
+	// Any transient errors that occur during the reconciliation of
+	// OpenStackClusters can be added as events to the OpenStackCluster object
+	// and/or logged in the controller's output.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the OpenStackCluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// DryRunPlan contains the changes to OpenStack resources the controller
+	// would apply, computed while the dry-run annotation is set.
+	// +optional
+	DryRunPlan *DryRunPlan `json:"dryRunPlan,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=openstackclusters,scope=Namespaced,categories=cluster-api,shortName=osc
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this OpenStackCluster belongs"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Cluster infrastructure is ready for OpenStack instances"
+// +kubebuilder:printcolumn:name="Network",type="string",JSONPath=".status.network.id",description="Network the cluster is using"
+// +kubebuilder:printcolumn:name="Subnet",type="string",JSONPath=".status.network.subnet.id",description="Subnet the cluster is using"
+// +kubebuilder:printcolumn:name="VIP",type="string",JSONPath=".status.network.apiServerLoadBalancer.ip",description="VIP of the API server load balancer"
+// +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.controlPlaneEndpoint.host",description="API Endpoint",priority=1
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason",description="Reason the cluster infrastructure is not ready",priority=1
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description="Message of the Ready condition",priority=1
+// +kubebuilder:printcolumn:name="Bastion IP",type="string",JSONPath=".status.bastion.floatingIP",description="Bastion address for breakglass access"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackCluster"
+
+// OpenStackCluster is the Schema for the openstackclusters API.
+type OpenStackCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackClusterSpec   `json:"spec,omitempty"`
+	Status OpenStackClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackClusterList contains a list of OpenStackCluster.
+type OpenStackClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackCluster `json:"items"`
+}
+
+// GetConditions returns the observations of the operational state of the OpenStackCluster resource.
+func (r *OpenStackCluster) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackCluster to the predescribed clusterv1.Conditions.
+func (r *OpenStackCluster) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+// IsDeletionProtected returns whether the OpenStackCluster is protected by
+// DeletionProtectedAnnotation without its deletion being confirmed.
+func (r *OpenStackCluster) IsDeletionProtected() bool {
+	return r.Annotations[DeletionProtectedAnnotation] == "true" && r.Annotations[ConfirmDeletionAnnotation] != r.Name
+}
+
+// RetainsNetwork returns whether the network of the cluster is kept when the
+// cluster is deleted.
+func (r *OpenStackCluster) RetainsNetwork() bool {
+	return r.Spec.DeletionPolicy != nil && r.Spec.DeletionPolicy.Network == DeletionPolicyRetain
+}
+
+// RetainsVolumes returns whether the root volumes of the machines are kept
+// when the cluster is deleted.
+func (r *OpenStackCluster) RetainsVolumes() bool {
+	return r.Spec.DeletionPolicy != nil && r.Spec.DeletionPolicy.Volumes == DeletionPolicyRetain
+}
+
+// RetainsFloatingIPs returns whether the floating IPs of the cluster are kept
+// when the cluster is deleted.
+func (r *OpenStackCluster) RetainsFloatingIPs() bool {
+	return r.Spec.DeletionPolicy != nil && r.Spec.DeletionPolicy.FloatingIPs == DeletionPolicyRetain
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackCluster{}, &OpenStackClusterList{})
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var _ = logf.Log.WithName("openstackcluster-resource")
+
+const openStackClusterImmutableMsg = "cannot be modified after the cluster has been created"
+
+func (r *OpenStackCluster) SetupWebhookWithManager(mgr manager.Manager) error {
+	return builder.WebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackcluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,versions=v1alpha7,name=validation.openstackcluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackcluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,versions=v1alpha7,name=default.openstackcluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+var (
+	_ webhook.Defaulter = &OpenStackCluster{}
+	_ webhook.Validator = &OpenStackCluster{}
+)
+
+// Default satisfies the defaulting webhook interface.
+func (r *OpenStackCluster) Default() {
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind == "" {
+		r.Spec.IdentityRef.Kind = defaultIdentityRefKind
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackCluster) ValidateCreate() error {
+	var allErrs field.ErrorList
+
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != defaultIdentityRefKind {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
+	}
+
+	// Only checked for clusters, as the endpoint of a template is usually set
+	// by a ClusterClass patch
+	if r.Spec.ExternalAPIServerEndpoint != nil && !r.Spec.ControlPlaneEndpoint.IsValid() {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "controlPlaneEndpoint"), "must be set when externalAPIServerEndpoint is set"))
+	}
+
+	allErrs = append(allErrs, validateOpenStackClusterSpec(&r.Spec, field.NewPath("spec"))...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackCluster) ValidateUpdate(oldRaw runtime.Object) error {
+	var allErrs field.ErrorList
+	old, ok := oldRaw.(*OpenStackCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackCluster but got a %T", oldRaw))
+	}
+
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != defaultIdentityRefKind {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "identityRef", "kind"),
+				r.Spec.IdentityRef, "must be a Secret"),
+		)
+	}
+
+	// The bastion can be changed, but must still be valid
+	if r.Spec.Bastion != nil && r.Spec.Bastion.Enabled && !reflect.DeepEqual(old.Spec.Bastion, r.Spec.Bastion) {
+		allErrs = append(allErrs, validateOpenStackMachineSpec(&r.Spec.Bastion.Instance, field.NewPath("spec", "bastion", "instance"))...)
+	}
+
+	// Allow changes to Spec.IdentityRef.Name and Spec.IdentityRef.Namespace.
+	if old.Spec.IdentityRef != nil && r.Spec.IdentityRef != nil {
+		old.Spec.IdentityRef.Name = ""
+		r.Spec.IdentityRef.Name = ""
+		old.Spec.IdentityRef.Namespace = ""
+		r.Spec.IdentityRef.Namespace = ""
+	}
+
+	// Allow changes to Spec.IdentityRef if it was unset.
+	if old.Spec.IdentityRef == nil && r.Spec.IdentityRef != nil {
+		old.Spec.IdentityRef = &OpenStackIdentityReference{}
+		r.Spec.IdentityRef = &OpenStackIdentityReference{}
+	}
+
+	if old.Spec.IdentityRef != nil && r.Spec.IdentityRef == nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "identityRef"),
+				r.Spec.IdentityRef, "field cannot be set to nil"),
+		)
+	}
+
+	allErrs = append(allErrs, validateReconcileTimeouts(r.Spec.ReconcileTimeouts, field.NewPath("spec", "reconcileTimeouts"))...)
+	allErrs = append(allErrs, validateMaintenanceWindows(r.Spec.MaintenanceWindows, field.NewPath("spec", "maintenanceWindows"))...)
+	allErrs = append(allErrs, validateRouterFirewall(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateRouterRoutes(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateRouterSubnets(&r.Spec, field.NewPath("spec"))...)
+
+	// Allow change only for the first time.
+	if old.Spec.ControlPlaneEndpoint.Host == "" {
+		old.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{}
+		r.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{}
+	}
+
+	// Allow the deletion policy to be changed until the cluster is deleted,
+	// the deletion of its resources depends on it.
+	if old.DeletionTimestamp.IsZero() {
+		old.Spec.DeletionPolicy = nil
+		r.Spec.DeletionPolicy = nil
+	}
+
+	ignoreMutableClusterSpecFields(&old.Spec, &r.Spec)
+
+	allErrs = append(allErrs, immutableFieldErrors(field.NewPath("spec"), &old.Spec, &r.Spec, openStackClusterImmutableMsg)...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackCluster) ValidateDelete() error {
+	if r.IsDeletionProtected() {
+		return apierrors.NewForbidden(
+			GroupVersion.WithResource("openstackclusters").GroupResource(),
+			r.Name,
+			fmt.Errorf("the cluster is protected by the %s annotation: set the %s annotation to %q to confirm its deletion", DeletionProtectedAnnotation, ConfirmDeletionAnnotation, r.Name),
+		)
+	}
+	return nil
+}
+
+// ignoreMutableClusterSpecFields clears the fields which can be changed in
+// the spec of an existing cluster, so that the remaining fields can be checked
+// for changes.
+func ignoreMutableClusterSpecFields(oldSpec, newSpec *OpenStackClusterSpec) {
+	// Allow changes to the bastion spec.
+	oldSpec.Bastion = &Bastion{}
+	newSpec.Bastion = &Bastion{}
+
+	// Allow the share to be added or removed, and its secret to be renamed.
+	// The share itself cannot be changed, as it would be recreated empty.
+	if oldSpec.Share == nil || newSpec.Share == nil {
+		oldSpec.Share = nil
+		newSpec.Share = nil
+	} else {
+		oldSpec.Share.SecretName = ""
+		newSpec.Share.SecretName = ""
+	}
+
+	// Allow changes to the addons, they are reapplied to the workload cluster.
+	oldSpec.Addons = nil
+	newSpec.Addons = nil
+
+	// Allow instance HA to be enabled, disabled or changed. The HA_Enabled
+	// metadata is only set on instances created afterwards.
+	oldSpec.InstanceHA = nil
+	newSpec.InstanceHA = nil
+
+	// Allow host maintenance handling to be enabled, disabled or changed.
+	oldSpec.HostMaintenance = nil
+	newSpec.HostMaintenance = nil
+
+	// Allow the container to be added or removed, and its options to be
+	// changed.
+	oldSpec.ObjectStorage = nil
+	newSpec.ObjectStorage = nil
+
+	// Allow the timeouts to be changed, they apply from the next reconcile.
+	oldSpec.ReconcileTimeouts = nil
+	newSpec.ReconcileTimeouts = nil
+
+	// Allow the maintenance windows to be changed, deferred changes are
+	// applied in the next window.
+	oldSpec.MaintenanceWindows = nil
+	newSpec.MaintenanceWindows = nil
+
+	// Allow the router firewall to be added, removed or changed, its firewall
+	// group is reconciled.
+	oldSpec.RouterFirewall = nil
+	newSpec.RouterFirewall = nil
+
+	// Allow the static routes of the router to be changed, they are
+	// reconciled.
+	oldSpec.RouterRoutes = nil
+	newSpec.RouterRoutes = nil
+
+	// Allow the subnets connected to the router to be changed.
+	oldSpec.RouterSubnets = nil
+	newSpec.RouterSubnets = nil
+
+	// Allow changes on AllowedCIDRs
+	if newSpec.APIServerLoadBalancer.Enabled {
+		oldSpec.APIServerLoadBalancer.AllowedCIDRs = []string{}
+		newSpec.APIServerLoadBalancer.AllowedCIDRs = []string{}
+	}
+
+	// Allow the ICMPv6 and node port rules and the rule profiles of the
+	// managed security groups, whose rules are reconciled, to be changed.
+	if oldSpec.ManagedSecurityGroups != nil && newSpec.ManagedSecurityGroups != nil {
+		oldSpec.ManagedSecurityGroups.IPv6 = false
+		newSpec.ManagedSecurityGroups.IPv6 = false
+		oldSpec.ManagedSecurityGroups.NodePortsFromServices = false
+		newSpec.ManagedSecurityGroups.NodePortsFromServices = false
+		oldSpec.ManagedSecurityGroups.RuleProfiles = nil
+		newSpec.ManagedSecurityGroups.RuleProfiles = nil
+	}
+
+	// Allow changes to the CIDRs allowed to access an external API server
+	// endpoint, which only change the rule of the managed security group.
+	if oldSpec.ExternalAPIServerEndpoint != nil && newSpec.ExternalAPIServerEndpoint != nil {
+		oldSpec.ExternalAPIServerEndpoint.AllowedCIDRs = nil
+		newSpec.ExternalAPIServerEndpoint.AllowedCIDRs = nil
+	}
+}
+
+// validateOpenStackClusterSpec validates the combinations of fields of an
+// OpenStackClusterSpec which contradict each other.
+func validateOpenStackClusterSpec(spec *OpenStackClusterSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.DisableAPIServerFloatingIP && spec.APIServerFloatingIP != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("apiServerFloatingIP"), "cannot be set when disableAPIServerFloatingIP is true"))
+	}
+
+	if spec.APIServerFloatingIPPoolRef != nil {
+		poolRefPath := path.Child("apiServerFloatingIPPoolRef")
+		switch {
+		case spec.DisableAPIServerFloatingIP:
+			allErrs = append(allErrs, field.Forbidden(poolRefPath, "cannot be set when disableAPIServerFloatingIP is true"))
+		case spec.APIServerLoadBalancer.Enabled:
+			allErrs = append(allErrs, field.Forbidden(poolRefPath, "cannot be set when apiServerLoadBalancer is enabled"))
+		case spec.APIServerFloatingIP != "":
+			allErrs = append(allErrs, field.Forbidden(poolRefPath, "cannot be set together with apiServerFloatingIP"))
+		}
+		allErrs = append(allErrs, validateIPAMPoolRef(spec.APIServerFloatingIPPoolRef, poolRefPath)...)
+	}
+
+	if spec.ExternalAPIServerEndpoint != nil {
+		allErrs = append(allErrs, validateExternalAPIServerEndpoint(spec, path)...)
+	}
+
+	if spec.Bastion != nil && spec.Bastion.Enabled {
+		allErrs = append(allErrs, validateOpenStackMachineSpec(&spec.Bastion.Instance, path.Child("bastion", "instance"))...)
+	}
+
+	if spec.FailureDomains != nil && len(spec.FailureDomains.Mappings) > 0 {
+		if len(spec.FailureDomains.AvailabilityZones) > 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Child("failureDomains", "availabilityZones"), "cannot be set when mappings are set"))
+		}
+		if len(spec.FailureDomains.ExcludedAvailabilityZones) > 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Child("failureDomains", "excludedAvailabilityZones"), "cannot be set when mappings are set"))
+		}
+		for i, mapping := range spec.FailureDomains.Mappings {
+			if mapping.NodeCIDR == "" {
+				continue
+			}
+			nodeCIDRPath := path.Child("failureDomains", "mappings").Index(i).Child("nodeCidr")
+			if spec.NodeCIDR == "" {
+				allErrs = append(allErrs, field.Forbidden(nodeCIDRPath, "requires nodeCidr of the cluster, which creates the network of the subnet"))
+			}
+			if mapping.Subnet != nil {
+				allErrs = append(allErrs, field.Forbidden(nodeCIDRPath, "cannot be set together with subnet"))
+			}
+		}
+	}
+
+	if spec.HostMaintenance != nil && spec.HostMaintenance.Drain && spec.HostMaintenance.DisableCordon {
+		allErrs = append(allErrs, field.Forbidden(path.Child("hostMaintenance", "drain"), "cannot be set when disableCordon is true"))
+	}
+
+	allErrs = append(allErrs, validateReconcileTimeouts(spec.ReconcileTimeouts, path.Child("reconcileTimeouts"))...)
+	allErrs = append(allErrs, validateMaintenanceWindows(spec.MaintenanceWindows, path.Child("maintenanceWindows"))...)
+	allErrs = append(allErrs, validateRouterFirewall(spec, path)...)
+	allErrs = append(allErrs, validateRouterRoutes(spec, path)...)
+	allErrs = append(allErrs, validateRouterSubnets(spec, path)...)
+
+	return allErrs
+}
+
+// validateRouterFirewall validates that the router firewall is only set for a
+// network created by CAPO, and that it selects its policies.
+func validateRouterFirewall(spec *OpenStackClusterSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	firewall := spec.RouterFirewall
+	if firewall == nil {
+		return allErrs
+	}
+	firewallPath := path.Child("routerFirewall")
+	if spec.NodeCIDR == "" {
+		allErrs = append(allErrs, field.Forbidden(firewallPath, "requires nodeCidr, which creates the network and router of the cluster"))
+	}
+	if firewall.IngressPolicy == nil && firewall.EgressPolicy == nil {
+		allErrs = append(allErrs, field.Required(firewallPath, "ingressPolicy or egressPolicy must be set"))
+	}
+	for _, policy := range []struct {
+		name   string
+		filter *FirewallPolicyFilter
+	}{
+		{"ingressPolicy", firewall.IngressPolicy},
+		{"egressPolicy", firewall.EgressPolicy},
+	} {
+		if policy.filter != nil && policy.filter.ID == "" && policy.filter.Name == "" {
+			allErrs = append(allErrs, field.Required(firewallPath.Child(policy.name), "id or name must be set"))
+		}
+	}
+	return allErrs
+}
+
+// validateRouterRoutes validates that the static routes of the router are
+// only set for a network created by CAPO, and that they are valid routes.
+func validateRouterRoutes(spec *OpenStackClusterSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(spec.RouterRoutes) == 0 {
+		return allErrs
+	}
+	routesPath := path.Child("routerRoutes")
+	if spec.NodeCIDR == "" {
+		allErrs = append(allErrs, field.Forbidden(routesPath, "requires nodeCidr, which creates the network and router of the cluster"))
+	}
+	for i, route := range spec.RouterRoutes {
+		if _, _, err := net.ParseCIDR(route.Destination); err != nil {
+			allErrs = append(allErrs, field.Invalid(routesPath.Index(i).Child("destination"), route.Destination, "must be a valid CIDR"))
+		}
+		if net.ParseIP(route.NextHop) == nil {
+			allErrs = append(allErrs, field.Invalid(routesPath.Index(i).Child("nextHop"), route.NextHop, "must be a valid IP address"))
+		}
+	}
+	return allErrs
+}
+
+// validateRouterSubnets validates that the subnets connected to the router are
+// only set for a network created by CAPO, and that they select a subnet.
+func validateRouterSubnets(spec *OpenStackClusterSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(spec.RouterSubnets) == 0 {
+		return allErrs
+	}
+	subnetsPath := path.Child("routerSubnets")
+	if spec.NodeCIDR == "" {
+		allErrs = append(allErrs, field.Forbidden(subnetsPath, "requires nodeCidr, which creates the network and router of the cluster"))
+	}
+	for i := range spec.RouterSubnets {
+		if spec.RouterSubnets[i].IsEmpty() {
+			allErrs = append(allErrs, field.Required(subnetsPath.Index(i), "subnet filter must not be empty"))
+		}
+	}
+	return allErrs
+}
+
+// validateMaintenanceWindows validates that the maintenance windows have a
+// duration of at most a day, so that a window ends before it starts again.
+func validateMaintenanceWindows(windows []MaintenanceWindow, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i := range windows {
+		duration := windows[i].Duration.Duration
+		if duration <= 0 || duration > 24*time.Hour {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("duration"), duration.String(), "must be positive and at most 24h"))
+		}
+	}
+	return allErrs
+}
+
+// validateReconcileTimeouts validates that the timeouts which are set are
+// positive.
+func validateReconcileTimeouts(timeouts *ReconcileTimeouts, path *field.Path) field.ErrorList {
+	if timeouts == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	for _, timeout := range []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"instanceCreate", timeouts.InstanceCreate},
+		{"loadBalancerCreate", timeouts.LoadBalancerCreate},
+		{"floatingIPAssociate", timeouts.FloatingIPAssociate},
+		{"deletion", timeouts.Deletion},
+	} {
+		if timeout.duration != nil && timeout.duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child(timeout.name), timeout.duration.Duration.String(), "must be positive"))
+		}
+	}
+	return allErrs
+}
+
+// validateExternalAPIServerEndpoint validates that a cluster with an external
+// API server endpoint does not set any API server resources managed in
+// OpenStack.
+func validateExternalAPIServerEndpoint(spec *OpenStackClusterSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.APIServerLoadBalancer.Enabled {
+		allErrs = append(allErrs, field.Forbidden(path.Child("apiServerLoadBalancer", "enabled"), "cannot be set when externalAPIServerEndpoint is set"))
+	}
+	if spec.APIServerFloatingIP != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("apiServerFloatingIP"), "cannot be set when externalAPIServerEndpoint is set"))
+	}
+	if spec.APIServerFloatingIPPoolRef != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("apiServerFloatingIPPoolRef"), "cannot be set when externalAPIServerEndpoint is set"))
+	}
+	if spec.APIServerFixedIP != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("apiServerFixedIP"), "cannot be set when externalAPIServerEndpoint is set"))
+	}
+	for i, cidr := range spec.ExternalAPIServerEndpoint.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("externalAPIServerEndpoint", "allowedCIDRs").Index(i), cidr, "must be a valid CIDR"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,1021 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestOpenStackCluster_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name        string
+		oldTemplate *OpenStackCluster
+		newTemplate *OpenStackCluster
+		wantErr     bool
+	}{
+		{
+			name: "OpenStackCluster.Spec.IdentityRef.Kind must always be Secret",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobar",
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "foobar",
+						Name: "foobar",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.IdentityRef.Name is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobar",
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobarbaz",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.IdentityRef.Namespace is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobar",
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind:      "Secret",
+						Name:      "foobar",
+						Namespace: "identities",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.IdentityRef can be changed if it was unset",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobar",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.IdentityRef must not be removed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobar",
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.Bastion is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						Instance: OpenStackMachineSpec{
+							CloudName: "foobar",
+							Image:     "foobar",
+							Flavor:    "minimal",
+						},
+						Enabled: true,
+					},
+				},
+				Status: OpenStackClusterStatus{
+					Bastion: &Instance{
+						Name: "foobar",
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						Instance: OpenStackMachineSpec{
+							CloudName: "foobarbaz",
+							Image:     "foobarbaz",
+							Flavor:    "medium",
+						},
+						Enabled: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing CIDRs on the OpenStackCluster.Spec.APIServerLoadBalancer.AllowedCIDRs is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						AllowedCIDRs: []string{
+							"0.0.0.0/0",
+							"192.168.10.0/24",
+						},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{
+						Enabled: true,
+						AllowedCIDRs: []string{
+							"0.0.0.0/0",
+							"192.168.10.0/24",
+							"10.6.0.0/16",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ExternalAPIServerEndpoint.AllowedCIDRs is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                 "foobar",
+					ControlPlaneEndpoint:      clusterv1.APIEndpoint{Host: "api.example.com", Port: 443},
+					ExternalAPIServerEndpoint: &ExternalAPIServerEndpoint{AllowedCIDRs: []string{"192.168.10.0/24"}},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                 "foobar",
+					ControlPlaneEndpoint:      clusterv1.APIEndpoint{Host: "api.example.com", Port: 443},
+					ExternalAPIServerEndpoint: &ExternalAPIServerEndpoint{AllowedCIDRs: []string{"192.168.10.0/24", "10.6.0.0/16"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Removing OpenStackCluster.Spec.ExternalAPIServerEndpoint is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                 "foobar",
+					ControlPlaneEndpoint:      clusterv1.APIEndpoint{Host: "api.example.com", Port: 443},
+					ExternalAPIServerEndpoint: &ExternalAPIServerEndpoint{},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:            "foobar",
+					ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "api.example.com", Port: 443},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing an enabled OpenStackCluster.Spec.Bastion to an invalid spec is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						Instance: OpenStackMachineSpec{
+							Image:  "foobar",
+							Flavor: "minimal",
+						},
+						Enabled: true,
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						Instance: OpenStackMachineSpec{
+							Flavor: "minimal",
+						},
+						Enabled: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Adding OpenStackCluster.Spec.Share is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Share:     &ManagedShare{Protocol: "NFS", Size: 10},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.Share.SecretName is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Share:     &ManagedShare{Protocol: "NFS", Size: 10},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Share:     &ManagedShare{Protocol: "NFS", Size: 10, SecretName: "foobar"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing the size of OpenStackCluster.Spec.Share is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Share:     &ManagedShare{Protocol: "NFS", Size: 10},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Share:     &ManagedShare{Protocol: "NFS", Size: 20},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Enabling OpenStackCluster.Spec.InstanceHA is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					InstanceHA: &InstanceHA{
+						Segment: "segment",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Enabling OpenStackCluster.Spec.HostMaintenance is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:       "foobar",
+					HostMaintenance: &HostMaintenance{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ManagedSecurityGroups.IPv6 is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: &ManagedSecurityGroups{},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: &ManagedSecurityGroups{IPv6: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ManagedSecurityGroups.NodePortsFromServices is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: &ManagedSecurityGroups{},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: &ManagedSecurityGroups{NodePortsFromServices: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ManagedSecurityGroups.RuleProfiles is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					ManagedSecurityGroups: &ManagedSecurityGroups{},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ManagedSecurityGroups: &ManagedSecurityGroups{
+						RuleProfiles: &SecurityGroupRuleProfiles{Worker: &SecurityGroupFilter{Name: "worker-profile"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.DeletionPolicy is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					DeletionPolicy: &DeletionPolicy{Network: DeletionPolicyRetain},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.DeletionPolicy of a deleted cluster is not allowed",
+			oldTemplate: &OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					DeletionPolicy: &DeletionPolicy{Network: DeletionPolicyRetain},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.ReconcileTimeouts is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ReconcileTimeouts: &ReconcileTimeouts{
+						InstanceCreate: &metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Setting a negative OpenStackCluster.Spec.ReconcileTimeouts is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					ReconcileTimeouts: &ReconcileTimeouts{
+						Deletion: &metav1.Duration{Duration: -time.Minute},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.MaintenanceWindows is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					MaintenanceWindows: []MaintenanceWindow{
+						{Days: []Weekday{"Saturday"}, Start: "02:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Setting an OpenStackCluster.Spec.MaintenanceWindows longer than a day is not allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					MaintenanceWindows: []MaintenanceWindow{
+						{Start: "02:00", Duration: metav1.Duration{Duration: 25 * time.Hour}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.RouterFirewall is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterFirewall: &RouterFirewall{
+						EgressPolicy: &FirewallPolicyFilter{ID: "policy-id"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.RouterRoutes is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterRoutes: []RouterRoute{
+						{Destination: "192.168.0.0/16", NextHop: "10.6.0.10"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.RouterSubnets is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:     "foobar",
+					NodeCIDR:      "10.6.0.0/24",
+					RouterSubnets: []SubnetFilter{{Name: "az-1-subnet"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Changing OpenStackCluster.Spec.Addons is allowed",
+			oldTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Addons: &Addons{
+						CloudControllerManager: &Addon{},
+					},
+				},
+			},
+			newTemplate: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Addons: &Addons{
+						CloudControllerManager: &Addon{Version: "v1.25.5"},
+						CinderCSI:              &Addon{},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.newTemplate.ValidateUpdate(tt.oldTemplate)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestOpenStackCluster_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		template *OpenStackCluster
+		wantErr  bool
+	}{
+		{
+			name: "OpenStackCluster.Spec.IdentityRef with correct spec on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "Secret",
+						Name: "foobar",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.IdentityRef with faulty spec on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					IdentityRef: &OpenStackIdentityReference{
+						Kind: "foobar",
+						Name: "foobar",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerFloatingIP with a disabled API server floating IP on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                  "foobar",
+					DisableAPIServerFloatingIP: true,
+					APIServerFloatingIP:        "192.168.0.10",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.HostMaintenance.Drain on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:       "foobar",
+					HostMaintenance: &HostMaintenance{Drain: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.HostMaintenance.Drain with cordoning disabled on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:       "foobar",
+					HostMaintenance: &HostMaintenance{Drain: true, DisableCordon: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerFloatingIPPoolRef on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					APIServerFloatingIPPoolRef: &corev1.TypedLocalObjectReference{
+						APIGroup: pointer.String("infrastructure.cluster.x-k8s.io"),
+						Kind:     "OpenStackFloatingIPPool",
+						Name:     "public",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.APIServerFloatingIPPoolRef with a load balancer on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:             "foobar",
+					APIServerLoadBalancer: APIServerLoadBalancer{Enabled: true},
+					APIServerFloatingIPPoolRef: &corev1.TypedLocalObjectReference{
+						APIGroup: pointer.String("infrastructure.cluster.x-k8s.io"),
+						Kind:     "OpenStackFloatingIPPool",
+						Name:     "public",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.Bastion with trunked SR-IOV port on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					Bastion: &Bastion{
+						Enabled: true,
+						Instance: OpenStackMachineSpec{
+							Image: "foobar",
+							Trunk: true,
+							Ports: []PortOpts{{VNICType: "direct"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.FailureDomains with mappings on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					FailureDomains: &FailureDomainsConfig{
+						Mappings: []FailureDomainMapping{{
+							Name:                    "fd-1",
+							ComputeAvailabilityZone: "nova-1",
+							StorageAvailabilityZone: "cinder-1",
+						}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.FailureDomains with mappings and excluded availability zones on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					FailureDomains: &FailureDomainsConfig{
+						ExcludedAvailabilityZones: []string{"nova-2"},
+						Mappings:                  []FailureDomainMapping{{Name: "fd-1"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.FailureDomains with a subnet per failure domain on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					FailureDomains: &FailureDomainsConfig{
+						Mappings: []FailureDomainMapping{
+							{Name: "fd-1", ComputeAvailabilityZone: "nova-1", NodeCIDR: "10.6.1.0/24"},
+							{Name: "fd-2", ComputeAvailabilityZone: "nova-2", NodeCIDR: "10.6.2.0/24"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.FailureDomains with a subnet per failure domain without a cluster network on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					FailureDomains: &FailureDomainsConfig{
+						Mappings: []FailureDomainMapping{{Name: "fd-1", NodeCIDR: "10.6.1.0/24"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.FailureDomains with a subnet filter and node CIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					FailureDomains: &FailureDomainsConfig{
+						Mappings: []FailureDomainMapping{{
+							Name:     "fd-1",
+							NodeCIDR: "10.6.1.0/24",
+							Subnet:   &SubnetFilter{Name: "fd-1"},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternalAPIServerEndpoint on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                 "foobar",
+					ControlPlaneEndpoint:      clusterv1.APIEndpoint{Host: "api.example.com", Port: 443},
+					ExternalAPIServerEndpoint: &ExternalAPIServerEndpoint{AllowedCIDRs: []string{"192.168.10.0/24"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternalAPIServerEndpoint without a control plane endpoint on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                 "foobar",
+					ExternalAPIServerEndpoint: &ExternalAPIServerEndpoint{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternalAPIServerEndpoint with a load balancer on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                 "foobar",
+					ControlPlaneEndpoint:      clusterv1.APIEndpoint{Host: "api.example.com", Port: 443},
+					ExternalAPIServerEndpoint: &ExternalAPIServerEndpoint{},
+					APIServerLoadBalancer:     APIServerLoadBalancer{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.ExternalAPIServerEndpoint with an invalid CIDR on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:                 "foobar",
+					ControlPlaneEndpoint:      clusterv1.APIEndpoint{Host: "api.example.com", Port: 443},
+					ExternalAPIServerEndpoint: &ExternalAPIServerEndpoint{AllowedCIDRs: []string{"192.168.10.0"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterFirewall with a policy on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterFirewall: &RouterFirewall{
+						IngressPolicy: &FirewallPolicyFilter{Name: "cluster-ingress"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterFirewall without nodeCidr on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					RouterFirewall: &RouterFirewall{
+						IngressPolicy: &FirewallPolicyFilter{Name: "cluster-ingress"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterFirewall without policies on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:      "foobar",
+					NodeCIDR:       "10.6.0.0/24",
+					RouterFirewall: &RouterFirewall{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterFirewall with an empty policy filter on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterFirewall: &RouterFirewall{
+						EgressPolicy: &FirewallPolicyFilter{},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterRoutes with a valid route on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterRoutes: []RouterRoute{
+						{Destination: "192.168.0.0/16", NextHop: "10.6.0.10"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterRoutes without nodeCidr on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					RouterRoutes: []RouterRoute{
+						{Destination: "192.168.0.0/16", NextHop: "10.6.0.10"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterRoutes with an invalid route on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName: "foobar",
+					NodeCIDR:  "10.6.0.0/24",
+					RouterRoutes: []RouterRoute{
+						{Destination: "192.168.0.0", NextHop: "10.6.0.10"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterSubnets with a subnet on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:     "foobar",
+					NodeCIDR:      "10.6.0.0/24",
+					RouterSubnets: []SubnetFilter{{Name: "az-1-subnet"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterSubnets without nodeCidr on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:     "foobar",
+					RouterSubnets: []SubnetFilter{{Name: "az-1-subnet"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OpenStackCluster.Spec.RouterSubnets with an empty filter on create",
+			template: &OpenStackCluster{
+				Spec: OpenStackClusterSpec{
+					CloudName:     "foobar",
+					NodeCIDR:      "10.6.0.0/24",
+					RouterSubnets: []SubnetFilter{{}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.template.ValidateCreate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestOpenStackCluster_ValidateUpdateFieldPath(t *testing.T) {
+	g := NewWithT(t)
+
+	old := &OpenStackCluster{
+		Spec: OpenStackClusterSpec{
+			CloudName: "foobar",
+			NodeCIDR:  "10.6.0.0/24",
+		},
+	}
+	updated := old.DeepCopy()
+	updated.Spec.NodeCIDR = "10.7.0.0/24"
+
+	err := updated.ValidateUpdate(old)
+	g.Expect(err).To(HaveOccurred())
+
+	var statusErr *apierrors.StatusError
+	g.Expect(errors.As(err, &statusErr)).To(BeTrue())
+	g.Expect(statusErr.ErrStatus.Details.Causes).To(HaveLen(1))
+	g.Expect(statusErr.ErrStatus.Details.Causes[0].Field).To(Equal("spec.nodeCidr"))
+	g.Expect(statusErr.ErrStatus.Details.Causes[0].Message).To(ContainSubstring(openStackClusterImmutableMsg))
+}
+
+func TestOpenStackCluster_ValidateDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:    "Not protected",
+			wantErr: false,
+		},
+		{
+			name:        "Protected",
+			annotations: map[string]string{DeletionProtectedAnnotation: "true"},
+			wantErr:     true,
+		},
+		{
+			name:        "Protected with wrong confirmation",
+			annotations: map[string]string{DeletionProtectedAnnotation: "true", ConfirmDeletionAnnotation: "yes"},
+			wantErr:     true,
+		},
+		{
+			name:        "Protected with confirmation",
+			annotations: map[string]string{DeletionProtectedAnnotation: "true", ConfirmDeletionAnnotation: "foobar"},
+			wantErr:     false,
+		},
+		{
+			name:        "Protection disabled",
+			annotations: map[string]string{DeletionProtectedAnnotation: "false"},
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foobar", Annotations: tt.annotations},
+			}
+			err := openStackCluster.ValidateDelete()
+			if tt.wantErr {
+				g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
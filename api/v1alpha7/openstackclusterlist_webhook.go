@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1alpha6
+package v1alpha7
 
 import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1alpha6
+package v1alpha7
 
 import (
 	"fmt"
@@ -35,8 +35,8 @@ func (r *OpenStackClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) err
 		Complete()
 }
 
-// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackclustertemplate,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclustertemplates,versions=v1alpha6,name=default.openstackclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
-// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackclustertemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclustertemplates,versions=v1alpha6,name=validation.openstackclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackclustertemplate,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclustertemplates,versions=v1alpha7,name=default.openstackclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackclustertemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclustertemplates,versions=v1alpha7,name=validation.openstackclustertemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
 	_ webhook.Defaulter = &OpenStackClusterTemplate{}
@@ -58,6 +58,8 @@ func (r *OpenStackClusterTemplate) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "identityRef", "kind"), "must be a Secret"))
 	}
 
+	allErrs = append(allErrs, validateOpenStackClusterSpec(&r.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
@@ -69,11 +71,21 @@ func (r *OpenStackClusterTemplate) ValidateUpdate(oldRaw runtime.Object) error {
 		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackClusterTemplate but got a %T", oldRaw))
 	}
 
-	if !reflect.DeepEqual(r.Spec.Template.Spec, old.Spec.Template.Spec) {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("OpenStackClusterTemplate", "spec", "template", "spec"), r, openStackClusterTemplateImmutableMsg),
-		)
+	// The topology controller of a ClusterClass applies changes of the
+	// template to the existing clusters, so the fields which can be changed
+	// in an OpenStackCluster can be changed in the template as well.
+	oldSpec := old.Spec.Template.Spec.DeepCopy()
+	newSpec := r.Spec.Template.Spec.DeepCopy()
+	if !reflect.DeepEqual(oldSpec, newSpec) {
+		allErrs = append(allErrs, validateOpenStackClusterSpec(newSpec, field.NewPath("spec", "template", "spec"))...)
 	}
+	ignoreMutableClusterSpecFields(oldSpec, newSpec)
+	// Allow the deletion policy to be changed. Clusters only refuse changes
+	// to it once they are being deleted.
+	oldSpec.DeletionPolicy = nil
+	newSpec.DeletionPolicy = nil
+
+	allErrs = append(allErrs, immutableFieldErrors(field.NewPath("spec", "template", "spec"), oldSpec, newSpec, openStackClusterTemplateImmutableMsg)...)
 
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
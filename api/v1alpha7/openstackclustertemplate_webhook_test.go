@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestOpenStackClusterTemplate_ValidateUpdate(t *testing.T) {
+	newTemplate := func(spec OpenStackClusterSpec) *OpenStackClusterTemplate {
+		return &OpenStackClusterTemplate{
+			Spec: OpenStackClusterTemplateSpec{
+				Template: OpenStackClusterTemplateResource{Spec: spec},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		oldTemplate *OpenStackClusterTemplate
+		newTemplate *OpenStackClusterTemplate
+		wantErr     bool
+	}{
+		{
+			name:        "Changing the network is not allowed",
+			oldTemplate: newTemplate(OpenStackClusterSpec{Network: NetworkFilter{Name: "foo"}}),
+			newTemplate: newTemplate(OpenStackClusterSpec{Network: NetworkFilter{Name: "bar"}}),
+			wantErr:     true,
+		},
+		{
+			name:        "Changing the bastion is allowed",
+			oldTemplate: newTemplate(OpenStackClusterSpec{Bastion: &Bastion{Enabled: true, Instance: OpenStackMachineSpec{Flavor: "m1.small", Image: "foo"}}}),
+			newTemplate: newTemplate(OpenStackClusterSpec{Bastion: &Bastion{Enabled: true, Instance: OpenStackMachineSpec{Flavor: "m1.small", Image: "bar"}}}),
+		},
+		{
+			name:        "Changing the allowed CIDRs of the API server load balancer is allowed",
+			oldTemplate: newTemplate(OpenStackClusterSpec{APIServerLoadBalancer: APIServerLoadBalancer{Enabled: true, AllowedCIDRs: []string{"10.0.0.0/24"}}}),
+			newTemplate: newTemplate(OpenStackClusterSpec{APIServerLoadBalancer: APIServerLoadBalancer{Enabled: true, AllowedCIDRs: []string{"10.0.0.0/24", "192.168.0.0/24"}}}),
+		},
+		{
+			name:        "Changed fields must still be valid",
+			oldTemplate: newTemplate(OpenStackClusterSpec{}),
+			newTemplate: newTemplate(OpenStackClusterSpec{Bastion: &Bastion{Enabled: true, Instance: OpenStackMachineSpec{Flavor: "m1.small"}}}),
+			wantErr:     true,
+		},
+		{
+			name:        "Changing the template metadata is allowed",
+			oldTemplate: newTemplate(OpenStackClusterSpec{}),
+			newTemplate: &OpenStackClusterTemplate{
+				Spec: OpenStackClusterTemplateSpec{
+					Template: OpenStackClusterTemplateResource{
+						ObjectMeta: clusterv1.ObjectMeta{Labels: map[string]string{"environment": "production"}},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.newTemplate.ValidateUpdate(tt.oldTemplate)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// FloatingIPPoolFinalizer allows ReconcileOpenStackFloatingIPPool to release the floating IPs of an
+	// OpenStackFloatingIPPool before removing it from the apiserver.
+	FloatingIPPoolFinalizer = "openstackfloatingippool.infrastructure.cluster.x-k8s.io"
+
+	// FloatingIPAddressFinalizer allows ReconcileOpenStackFloatingIPPool to release a floating IP before the
+	// IPAddress which allocated it to a claim is removed from the apiserver.
+	FloatingIPAddressFinalizer = "openstackfloatingippool.infrastructure.cluster.x-k8s.io/ip-address"
+)
+
+// ReclaimPolicy is what happens to a floating IP created by an
+// OpenStackFloatingIPPool when it is released.
+// +kubebuilder:validation:Enum=Delete;Retain
+type ReclaimPolicy string
+
+const (
+	// ReclaimDelete deletes a released floating IP.
+	ReclaimDelete ReclaimPolicy = "Delete"
+
+	// ReclaimRetain keeps a released floating IP in the pool to be claimed again.
+	ReclaimRetain ReclaimPolicy = "Retain"
+)
+
+// OpenStackFloatingIPPoolSpec defines the desired state of OpenStackFloatingIPPool.
+type OpenStackFloatingIPPoolSpec struct {
+	// The name of the cloud to use from the clouds secret
+	// +optional
+	CloudName string `json:"cloudName,omitempty"`
+
+	// IdentityRef is a reference to a identity to be used when reconciling this pool.
+	// +optional
+	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
+
+	// PreAllocatedFloatingIPs are existing floating IPs which are claimed
+	// before new floating IPs are created. They are never deleted by the pool.
+	// +optional
+	// +listType=set
+	PreAllocatedFloatingIPs []string `json:"preAllocatedFloatingIPs,omitempty"`
+
+	// FloatingIPNetwork is the external network in which floating IPs are
+	// created once the pre-allocated floating IPs are claimed.
+	FloatingIPNetwork NetworkFilter `json:"floatingIPNetwork"`
+
+	// FloatingIPSubnet restricts the floating IPs created by the pool to a
+	// subnet of the external network.
+	// +optional
+	FloatingIPSubnet *SubnetFilter `json:"floatingIPSubnet,omitempty"`
+
+	// MaxIPs is the maximum number of floating IPs in the pool, including
+	// the pre-allocated floating IPs. If it is not set, floating IPs are
+	// created until the quota of the project is exhausted.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxIPs *int `json:"maxIPs,omitempty"`
+
+	// ReclaimPolicy is what happens to floating IPs created by the pool
+	// when they are released. They are deleted by default.
+	// +optional
+	// +kubebuilder:default=Delete
+	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// OpenStackFloatingIPPoolStatus defines the observed state of OpenStackFloatingIPPool.
+type OpenStackFloatingIPPoolStatus struct {
+	// ClaimedIPs are the floating IPs allocated to an IPAddressClaim.
+	// +optional
+	// +listType=set
+	ClaimedIPs []string `json:"claimedIPs,omitempty"`
+
+	// AvailableIPs are the floating IPs of the pool which are not claimed.
+	// +optional
+	// +listType=set
+	AvailableIPs []string `json:"availableIPs,omitempty"`
+
+	// FloatingIPNetworkID is the ID of the external network of the pool.
+	// +optional
+	FloatingIPNetworkID string `json:"floatingIPNetworkID,omitempty"`
+
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackfloatingippools,scope=Namespaced,categories=cluster-api,shortName=osfip
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Floating IP pool ready status"
+// +kubebuilder:printcolumn:name="Network",type="string",JSONPath=".status.floatingIPNetworkID",description="External network of the floating IPs"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackFloatingIPPool"
+
+// OpenStackFloatingIPPool is the Schema for the openstackfloatingippools API.
+// It allocates floating IPs to the IPAddressClaims of the Cluster API IPAM
+// contract which reference it.
+type OpenStackFloatingIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackFloatingIPPoolSpec   `json:"spec,omitempty"`
+	Status OpenStackFloatingIPPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackFloatingIPPoolList contains a list of OpenStackFloatingIPPool.
+type OpenStackFloatingIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackFloatingIPPool `json:"items"`
+}
+
+// GetConditions returns the observations of the operational state of the OpenStackFloatingIPPool resource.
+func (r *OpenStackFloatingIPPool) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackFloatingIPPool to the predescribed clusterv1.Conditions.
+func (r *OpenStackFloatingIPPool) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackFloatingIPPool{}, &OpenStackFloatingIPPoolList{})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"fmt"
+	"net"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const openStackFloatingIPPoolImmutableMsg = "cannot be modified, the floating IPs of the pool would be in another network. Create another OpenStackFloatingIPPool instead"
+
+func (r *OpenStackFloatingIPPool) SetupWebhookWithManager(mgr manager.Manager) error {
+	return builder.WebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackfloatingippool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackfloatingippools,versions=v1alpha7,name=validation.openstackfloatingippool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackfloatingippool,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackfloatingippools,versions=v1alpha7,name=default.openstackfloatingippool.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+var (
+	_ webhook.Defaulter = &OpenStackFloatingIPPool{}
+	_ webhook.Validator = &OpenStackFloatingIPPool{}
+)
+
+// Default satisfies the defaulting webhook interface.
+func (r *OpenStackFloatingIPPool) Default() {
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind == "" {
+		r.Spec.IdentityRef.Kind = defaultIdentityRefKind
+	}
+	if r.Spec.ReclaimPolicy == "" {
+		r.Spec.ReclaimPolicy = ReclaimDelete
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackFloatingIPPool) ValidateCreate() error {
+	var allErrs field.ErrorList
+
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != defaultIdentityRefKind {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
+	}
+
+	allErrs = append(allErrs, validateOpenStackFloatingIPPoolSpec(&r.Spec, field.NewPath("spec"))...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackFloatingIPPool) ValidateUpdate(oldRaw runtime.Object) error {
+	old, ok := oldRaw.(*OpenStackFloatingIPPool)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackFloatingIPPool but got a %T", oldRaw))
+	}
+
+	var allErrs field.ErrorList
+
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != defaultIdentityRefKind {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
+	}
+
+	allErrs = append(allErrs, validateOpenStackFloatingIPPoolSpec(&r.Spec, field.NewPath("spec"))...)
+
+	// The size of the pool and what happens to released floating IPs can be changed
+	allErrs = append(allErrs, immutableFieldErrors(field.NewPath("spec", "floatingIPNetwork"), &old.Spec.FloatingIPNetwork, &r.Spec.FloatingIPNetwork, openStackFloatingIPPoolImmutableMsg)...)
+	if (old.Spec.FloatingIPSubnet == nil) != (r.Spec.FloatingIPSubnet == nil) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "floatingIPSubnet"), openStackFloatingIPPoolImmutableMsg))
+	} else if r.Spec.FloatingIPSubnet != nil {
+		allErrs = append(allErrs, immutableFieldErrors(field.NewPath("spec", "floatingIPSubnet"), old.Spec.FloatingIPSubnet, r.Spec.FloatingIPSubnet, openStackFloatingIPPoolImmutableMsg)...)
+	}
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackFloatingIPPool) ValidateDelete() error {
+	return nil
+}
+
+// validateOpenStackFloatingIPPoolSpec validates the combinations of fields of
+// an OpenStackFloatingIPPoolSpec which contradict each other.
+func validateOpenStackFloatingIPPoolSpec(spec *OpenStackFloatingIPPoolSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.FloatingIPNetwork.IsEmpty() {
+		allErrs = append(allErrs, field.Required(path.Child("floatingIPNetwork"), "must select the external network"))
+	}
+
+	for i, ip := range spec.PreAllocatedFloatingIPs {
+		if net.ParseIP(ip) == nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("preAllocatedFloatingIPs").Index(i), ip, "must be an IP address"))
+		}
+	}
+
+	if spec.MaxIPs != nil && *spec.MaxIPs < len(spec.PreAllocatedFloatingIPs) {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxIPs"), *spec.MaxIPs, "must not be less than the number of pre-allocated floating IPs"))
+	}
+
+	return allErrs
+}
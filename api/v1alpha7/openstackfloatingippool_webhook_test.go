@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+)
+
+func TestOpenStackFloatingIPPool_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name       string
+		spec       OpenStackFloatingIPPoolSpec
+		wantFields []string
+	}{
+		{
+			name: "Valid spec",
+			spec: OpenStackFloatingIPPoolSpec{
+				FloatingIPNetwork:       NetworkFilter{Name: "public"},
+				PreAllocatedFloatingIPs: []string{"192.0.2.10", "192.0.2.11"},
+				MaxIPs:                  pointer.Int(2),
+			},
+		},
+		{
+			name:       "Network is required",
+			spec:       OpenStackFloatingIPPoolSpec{},
+			wantFields: []string{"spec.floatingIPNetwork"},
+		},
+		{
+			name: "Pre-allocated floating IPs must be IP addresses",
+			spec: OpenStackFloatingIPPoolSpec{
+				FloatingIPNetwork:       NetworkFilter{Name: "public"},
+				PreAllocatedFloatingIPs: []string{"192.0.2.10", "foobar"},
+			},
+			wantFields: []string{"spec.preAllocatedFloatingIPs[1]"},
+		},
+		{
+			name: "Pre-allocated floating IPs must not exceed the maximum",
+			spec: OpenStackFloatingIPPoolSpec{
+				FloatingIPNetwork:       NetworkFilter{Name: "public"},
+				PreAllocatedFloatingIPs: []string{"192.0.2.10", "192.0.2.11"},
+				MaxIPs:                  pointer.Int(1),
+			},
+			wantFields: []string{"spec.maxIPs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateOpenStackFloatingIPPoolSpec(&tt.spec, field.NewPath("spec"))
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(Equal(tt.wantFields))
+
+			err := (&OpenStackFloatingIPPool{Spec: tt.spec}).ValidateCreate()
+			g.Expect(err != nil).To(Equal(len(tt.wantFields) > 0))
+		})
+	}
+}
+
+func TestOpenStackFloatingIPPool_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := OpenStackFloatingIPPoolSpec{
+		FloatingIPNetwork: NetworkFilter{Name: "public"},
+		ReclaimPolicy:     ReclaimDelete,
+	}
+
+	resizedSpec := *spec.DeepCopy()
+	resizedSpec.MaxIPs = pointer.Int(5)
+	resizedSpec.ReclaimPolicy = ReclaimRetain
+
+	networkSpec := *spec.DeepCopy()
+	networkSpec.FloatingIPNetwork.Name = "other"
+
+	subnetSpec := *spec.DeepCopy()
+	subnetSpec.FloatingIPSubnet = &SubnetFilter{Name: "public-v4"}
+
+	g.Expect((&OpenStackFloatingIPPool{Spec: resizedSpec}).ValidateUpdate(&OpenStackFloatingIPPool{Spec: spec})).To(Succeed())
+	g.Expect((&OpenStackFloatingIPPool{Spec: networkSpec}).ValidateUpdate(&OpenStackFloatingIPPool{Spec: spec})).NotTo(Succeed())
+	g.Expect((&OpenStackFloatingIPPool{Spec: subnetSpec}).ValidateUpdate(&OpenStackFloatingIPPool{Spec: spec})).NotTo(Succeed())
+}
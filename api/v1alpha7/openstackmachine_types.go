@@ -0,0 +1,286 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+const (
+	// MachineFinalizer allows ReconcileOpenStackMachine to clean up OpenStack resources associated with OpenStackMachine before
+	// removing it from the apiserver.
+	MachineFinalizer = "openstackmachine.infrastructure.cluster.x-k8s.io"
+
+	// ConsoleAnnotation makes the controller publish the URL of a remote
+	// console of the instance of an OpenStackMachine in the Secret
+	// <name>-console. Its value is the console type: novnc, serial or
+	// spice-html5. The annotation is removed once the URL is published.
+	ConsoleAnnotation = "infrastructure.cluster.x-k8s.io/request-console"
+
+	// ReconcileNetworkingAnnotation makes the controllers reconcile the
+	// ports, security groups and floating IP of the instance of an
+	// OpenStackMachine right away, without changing the server itself. It
+	// is passed on to the OpenStackServer of the machine and removed once
+	// the networking is reconciled.
+	ReconcileNetworkingAnnotation = "infrastructure.cluster.x-k8s.io/reconcile-networking"
+)
+
+// OpenStackMachineSpec defines the desired state of OpenStackMachine.
+type OpenStackMachineSpec struct {
+	// ProviderID is the unique identifier as specified by the cloud provider.
+	ProviderID *string `json:"providerID,omitempty"`
+
+	// InstanceID is the OpenStack instance ID for this machine.
+	// If set when the machine is created, the existing server with this ID
+	// is adopted instead of creating a new one.
+	InstanceID *string `json:"instanceID,omitempty"`
+
+	// The name of the cloud to use from the clouds secret
+	// +optional
+	CloudName string `json:"cloudName"`
+
+	// Region is the name of the OpenStack region in which the machine is
+	// created. It overrides the region of the cloud, allowing the machines of
+	// a cluster to be spread across multiple regions. The networks and ports
+	// of a machine in a different region than the cluster must be specified
+	// explicitly, as the cluster network is not available there.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// The flavor reference for the flavor for your server instance.
+	Flavor string `json:"flavor"`
+
+	// FlavorRequirements are extra specs which the flavor must set. The
+	// machine fails before its instance is created if the flavor does not
+	// meet them.
+	// +optional
+	FlavorRequirements *FlavorRequirements `json:"flavorRequirements,omitempty"`
+
+	// The name of the image to use for your server instance.
+	// If the RootVolume is specified, this will be ignored and use rootVolume directly.
+	Image string `json:"image,omitempty"`
+
+	// The uuid of the image to use for your server instance.
+	// if it's empty, Image name will be used
+	ImageUUID string `json:"imageUUID,omitempty"`
+
+	// The ssh key to inject in the instance
+	SSHKeyName string `json:"sshKeyName,omitempty"`
+
+	// SSHAuthorizedKeys are SSH public keys added to the default user of the
+	// instance through its user data, e.g. on clouds which restrict or
+	// prohibit Nova keypairs. They can be used together with or instead of
+	// sshKeyName. Only cloud-config and ignition bootstrap data is supported.
+	// +listType=set
+	// +optional
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+
+	// A networks object. Required parameter when there are multiple networks defined for the tenant.
+	// When you do not specify both networks and ports parameters, the server attaches to the only network created for the current tenant.
+	// This field is deprecated, use ports instead.
+	Networks []NetworkParam `json:"networks,omitempty"`
+
+	// Ports to be attached to the server instance. They are created if a port with the given name does not already exist.
+	// When you do not specify both networks and ports parameters, the server attaches to the only network created for the current tenant.
+	Ports []PortOpts `json:"ports,omitempty"`
+
+	// UUID, IP address of a port from this subnet will be marked as AccessIPv4 on the created compute instance
+	Subnet string `json:"subnet,omitempty"`
+
+	// The floatingIP which will be associated to the machine, only used for master.
+	// The floatingIP should have been created and haven't been associated.
+	FloatingIP string `json:"floatingIP,omitempty"`
+
+	// FloatingIPPoolRef is a reference to an IPAM pool, usually an
+	// OpenStackFloatingIPPool, from which a floating IP is claimed for the
+	// machine and associated with its management port.
+	// +optional
+	FloatingIPPoolRef *corev1.TypedLocalObjectReference `json:"floatingIPPoolRef,omitempty"`
+
+	// The security groups to assign to the instance, selected by their IDs or by filters.
+	// They are added to the security groups managed by the cluster, if any.
+	SecurityGroups []SecurityGroupFilter `json:"securityGroups,omitempty"`
+
+	// Whether the server instance is created on a trunk port or not.
+	Trunk bool `json:"trunk,omitempty"`
+
+	// Machine tags
+	// Requires Nova api 2.52 minimum!
+	// +listType=set
+	Tags []string `json:"tags,omitempty"`
+
+	// Metadata mapping. Allows you to create a map of key value pairs to add to the server instance.
+	ServerMetadata map[string]string `json:"serverMetadata,omitempty"`
+
+	// NodeLabels adds the failure domain, the flavor or server metadata of
+	// the machine as kubelet node labels to its bootstrap data.
+	// +optional
+	NodeLabels *NodeLabels `json:"nodeLabels,omitempty"`
+
+	// Config Drive support
+	ConfigDrive *bool `json:"configDrive,omitempty"`
+
+	// The volume metadata to boot from
+	RootVolume *RootVolume `json:"rootVolume,omitempty"`
+
+	// The server group to assign the machine to
+	ServerGroupID string `json:"serverGroupID,omitempty"`
+
+	// FailureDomain is the failure domain of the machine if its Machine does
+	// not have one. It is set by the controller if the cluster balances its
+	// workers across failure domains, and can change until the instance of
+	// the machine is scheduled.
+	// +optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+
+	// FallbackFailureDomains are tried in order if no host in the failure
+	// domain of the machine has capacity for its instance, or the instance
+	// exceeds a quota. The instance is then created again in the first
+	// failure domain which has not been tried yet.
+	// +listType=set
+	// +optional
+	FallbackFailureDomains []string `json:"fallbackFailureDomains,omitempty"`
+
+	// IdentityRef is a reference to a identity to be used when reconciling this machine.
+	// If not specified, the identityRef and cloudName of the cluster are used instead.
+	// +optional
+	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
+}
+
+// OpenStackMachineStatus defines the observed state of OpenStackMachine.
+type OpenStackMachineStatus struct {
+	// Ready is true when the provider resource is ready.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// ObservedGeneration is the generation of the spec which was last
+	// reconciled successfully.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Addresses contains the OpenStack instance associated addresses.
+	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+
+	// InstanceState is the state of the OpenStack instance for this machine.
+	// +optional
+	InstanceState *InstanceState `json:"instanceState,omitempty"`
+
+	// AvailabilityZone is the availability zone the OpenStack instance was
+	// scheduled to.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// ResourceIDs are the IDs of the OpenStack resources used by the instance
+	// of the machine, as reported by its OpenStackServer.
+	// +optional
+	ResourceIDs *InstanceResourceIDs `json:"resourceIDs,omitempty"`
+
+	// UnschedulableFailureDomains are the failure domains in which no host
+	// had capacity for the instance of the machine. They are skipped when
+	// the controller assigns the next failure domain to the machine.
+	// +optional
+	UnschedulableFailureDomains []string `json:"unschedulableFailureDomains,omitempty"`
+
+	// FallbackFailureDomain is the failure domain from the
+	// fallbackFailureDomains the instance was created in instead of the
+	// failure domain of the machine.
+	// +optional
+	FallbackFailureDomain string `json:"fallbackFailureDomain,omitempty"`
+
+	// Capacity is the resource capacity of the flavor of the instance,
+	// including the number of GPUs it provides.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+
+	// LastInstanceActionTime is the start time of the latest action on the
+	// instance not requested by the controller which was reported as event.
+	// +optional
+	LastInstanceActionTime *metav1.Time `json:"lastInstanceActionTime,omitempty"`
+
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the Machine and will contain a more verbose string suitable
+	// for logging and human consumption.
+	//
+	// This field should not be set for transitive errors that a controller
+	// faces that are expected to be fixed automatically over
+	// time (like service outages), but instead indicate that something is
+	// fundamentally wrong with the Machine's spec or the configuration of
+	// the controller, and that manual intervention is required. Examples
+	// of terminal errors would be invalid combinations of settings in the
+	// spec, values that are unsupported by the controller, or the
+	// responsible controller itself being critically misconfigured.
+	//
+	// Any transient errors that occur during the reconciliation of Machines
+	// can be added as events to the Machine object and/or logged in the
+	// controller's output.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackmachines,scope=Namespaced,categories=cluster-api,shortName=osm
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this OpenStackMachine belongs"
+// +kubebuilder:printcolumn:name="InstanceState",type="string",JSONPath=".status.instanceState",description="OpenStack instance state"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine ready status"
+// +kubebuilder:printcolumn:name="ProviderID",type="string",JSONPath=".spec.providerID",description="OpenStack instance ID"
+// +kubebuilder:printcolumn:name="IP",type="string",JSONPath=".status.addresses[?(@.type==\"InternalIP\")].address",description="Internal IP addresses of the instance"
+// +kubebuilder:printcolumn:name="Flavor",type="string",JSONPath=".spec.flavor",description="Flavor of the instance",priority=1
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image",description="Image of the instance",priority=1
+// +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.availabilityZone",description="Availability zone of the instance",priority=1
+// +kubebuilder:printcolumn:name="Machine",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"Machine\")].name",description="Machine object which owns with this OpenStackMachine"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackMachine"
+
+// OpenStackMachine is the Schema for the openstackmachines API.
+type OpenStackMachine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackMachineSpec   `json:"spec,omitempty"`
+	Status OpenStackMachineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackMachineList contains a list of OpenStackMachine.
+type OpenStackMachineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackMachine `json:"items"`
+}
+
+// GetConditions returns the observations of the operational state of the OpenStackMachine resource.
+func (r *OpenStackMachine) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackMachine to the predescribed clusterv1.Conditions.
+func (r *OpenStackMachine) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackMachine{}, &OpenStackMachineList{})
+}
@@ -14,12 +14,11 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1alpha6
+package v1alpha7
 
 import (
-	"reflect"
+	"fmt"
 
-	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -32,14 +31,16 @@ import (
 // log is for logging in this package.
 var _ = logf.Log.WithName("openstackmachine-resource")
 
+const openStackMachineImmutableMsg = "cannot be modified, the server of an OpenStackMachine is not updated in place. Change the OpenStackMachineTemplate of the machine to replace it instead"
+
 func (r *OpenStackMachine) SetupWebhookWithManager(mgr manager.Manager) error {
 	return builder.WebhookManagedBy(mgr).
 		For(r).
 		Complete()
 }
 
-// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackmachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,versions=v1alpha6,name=validation.openstackmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
-// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackmachine,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,versions=v1alpha6,name=default.openstackmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackmachine,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,versions=v1alpha7,name=validation.openstackmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackmachine,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,versions=v1alpha7,name=default.openstackmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var (
 	_ webhook.Defaulter = &OpenStackMachine{}
@@ -48,9 +49,7 @@ var (
 
 // Default satisfies the defaulting webhook interface.
 func (r *OpenStackMachine) Default() {
-	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind == "" {
-		r.Spec.IdentityRef.Kind = defaultIdentityRefKind
-	}
+	defaultOpenStackMachineSpec(&r.Spec)
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -61,22 +60,16 @@ func (r *OpenStackMachine) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
 	}
 
+	allErrs = append(allErrs, validateOpenStackMachineSpec(&r.Spec, field.NewPath("spec"))...)
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (r *OpenStackMachine) ValidateUpdate(old runtime.Object) error {
-	newOpenStackMachine, err := runtime.DefaultUnstructuredConverter.ToUnstructured(r)
-	if err != nil {
-		return apierrors.NewInvalid(GroupVersion.WithKind("OpenStackMachine").GroupKind(), r.Name, field.ErrorList{
-			field.InternalError(nil, errors.Wrap(err, "failed to convert new OpenStackMachine to unstructured object")),
-		})
-	}
-	oldOpenStackMachine, err := runtime.DefaultUnstructuredConverter.ToUnstructured(old)
-	if err != nil {
-		return apierrors.NewInvalid(GroupVersion.WithKind("OpenStackMachine").GroupKind(), r.Name, field.ErrorList{
-			field.InternalError(nil, errors.Wrap(err, "failed to convert old OpenStackMachine to unstructured object")),
-		})
+func (r *OpenStackMachine) ValidateUpdate(oldRaw runtime.Object) error {
+	old, ok := oldRaw.(*OpenStackMachine)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackMachine but got a %T", oldRaw))
 	}
 
 	var allErrs field.ErrorList
@@ -85,24 +78,37 @@ func (r *OpenStackMachine) ValidateUpdate(old runtime.Object) error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
 	}
 
-	newOpenStackMachineSpec := newOpenStackMachine["spec"].(map[string]interface{})
-	oldOpenStackMachineSpec := oldOpenStackMachine["spec"].(map[string]interface{})
+	oldSpec := old.Spec.DeepCopy()
+	newSpec := r.Spec.DeepCopy()
+
+	// The old object was not defaulted if it was created before the
+	// defaults were introduced
+	defaultOpenStackMachineSpec(oldSpec)
+	defaultOpenStackMachineSpec(newSpec)
 
 	// allow changes to providerID once
-	if oldOpenStackMachineSpec["providerID"] == nil {
-		delete(oldOpenStackMachineSpec, "providerID")
-		delete(newOpenStackMachineSpec, "providerID")
+	if oldSpec.ProviderID == nil {
+		newSpec.ProviderID = nil
 	}
 
-	// allow changes to instanceID once
-	if oldOpenStackMachineSpec["instanceID"] == nil {
-		delete(oldOpenStackMachineSpec, "instanceID")
-		delete(newOpenStackMachineSpec, "instanceID")
+	// allow changes to instanceID once, and to the failure domain until the
+	// machine has an instance
+	if oldSpec.InstanceID == nil {
+		newSpec.InstanceID = nil
+		oldSpec.FailureDomain = ""
+		newSpec.FailureDomain = ""
 	}
 
-	if !reflect.DeepEqual(oldOpenStackMachineSpec, newOpenStackMachineSpec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "cannot be modified"))
-	}
+	// allow adding and removing secondary ports, which are attached to and
+	// detached from the running instance, and changing the security groups
+	// of ports
+	oldSpec.Ports = withoutSecurityGroupFilters(withoutHotPluggablePorts(oldSpec.Networks, oldSpec.Ports))
+	newSpec.Ports = withoutSecurityGroupFilters(withoutHotPluggablePorts(newSpec.Networks, newSpec.Ports))
+
+	// allow growing the root volume, which is extended in place
+	allErrs = append(allErrs, allowRootVolumeGrowth(field.NewPath("spec", "rootVolume"), oldSpec.RootVolume, newSpec.RootVolume)...)
+
+	allErrs = append(allErrs, immutableFieldErrors(field.NewPath("spec"), oldSpec, newSpec, openStackMachineImmutableMsg)...)
 
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
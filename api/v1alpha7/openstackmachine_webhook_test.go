@@ -0,0 +1,302 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+)
+
+func TestOpenStackMachine_Default(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name     string
+		spec     OpenStackMachineSpec
+		wantSpec OpenStackMachineSpec
+	}{
+		{
+			name: "A port on the cluster network is added without networks and ports",
+			spec: OpenStackMachineSpec{
+				Trunk:       true,
+				IdentityRef: &OpenStackIdentityReference{Name: "foobar"},
+			},
+			wantSpec: OpenStackMachineSpec{
+				Trunk:       true,
+				IdentityRef: &OpenStackIdentityReference{Kind: "Secret", Name: "foobar"},
+				Ports:       []PortOpts{{Trunk: pointer.Bool(true)}},
+			},
+		},
+		{
+			name: "Ports inherit the trunk setting of the machine",
+			spec: OpenStackMachineSpec{
+				Ports: []PortOpts{{NameSuffix: "a"}, {NameSuffix: "b", Trunk: pointer.Bool(true)}},
+			},
+			wantSpec: OpenStackMachineSpec{
+				Ports: []PortOpts{{NameSuffix: "a", Trunk: pointer.Bool(false)}, {NameSuffix: "b", Trunk: pointer.Bool(true)}},
+			},
+		},
+		{
+			name: "No port is added with networks",
+			spec: OpenStackMachineSpec{
+				Networks: []NetworkParam{{UUID: "foobar"}},
+			},
+			wantSpec: OpenStackMachineSpec{
+				Networks: []NetworkParam{{UUID: "foobar"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := &OpenStackMachine{Spec: tt.spec}
+			machine.Default()
+			g.Expect(machine.Spec).To(Equal(tt.wantSpec))
+		})
+	}
+}
+
+func TestOpenStackMachine_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name       string
+		spec       OpenStackMachineSpec
+		wantFields []string
+	}{
+		{
+			name: "Valid spec",
+			spec: OpenStackMachineSpec{
+				Image: "foobar",
+				RootVolume: &RootVolume{
+					Size:             50,
+					AvailabilityZone: "az1",
+				},
+				Ports: []PortOpts{{VNICType: "direct", Trunk: pointer.Bool(false)}},
+			},
+		},
+		{
+			name:       "Image is required",
+			spec:       OpenStackMachineSpec{},
+			wantFields: []string{"spec.image"},
+		},
+		{
+			name: "Root volume availability zone requires a disk size",
+			spec: OpenStackMachineSpec{
+				Image:      "foobar",
+				RootVolume: &RootVolume{AvailabilityZone: "az1"},
+			},
+			wantFields: []string{"spec.rootVolume.availabilityZone"},
+		},
+		{
+			name: "Trunk ports must have the normal vNIC type",
+			spec: OpenStackMachineSpec{
+				Image: "foobar",
+				Trunk: true,
+				Ports: []PortOpts{{VNICType: "normal"}, {VNICType: "direct"}},
+			},
+			wantFields: []string{"spec.ports[1].vnicType"},
+		},
+		{
+			name: "Port name suffixes must be unique",
+			spec: OpenStackMachineSpec{
+				Image: "foobar",
+				Ports: []PortOpts{{NameSuffix: "a"}, {NameSuffix: "a"}},
+			},
+			wantFields: []string{"spec.ports[1].nameSuffix"},
+		},
+		{
+			name: "Existing ports must be unique and cannot have other options",
+			spec: OpenStackMachineSpec{
+				Image: "foobar",
+				Trunk: true,
+				Ports: []PortOpts{{ID: "port-a"}, {ID: "port-b", NameSuffix: "b"}, {ID: "port-a"}},
+			},
+			wantFields: []string{"spec.ports[1]", "spec.ports[2].id"},
+		},
+		{
+			name: "Extra DHCP options must be unique per IP version",
+			spec: OpenStackMachineSpec{
+				Image: "foobar",
+				Ports: []PortOpts{{
+					ExtraDHCPOpts: []ExtraDHCPOpt{
+						{Name: "mtu", Value: "1450"},
+						{Name: "mtu", Value: "1400", IPVersion: 6},
+						{Name: "mtu", Value: "1500"},
+					},
+				}},
+			},
+			wantFields: []string{"spec.ports[0].extraDhcpOpts[2].name"},
+		},
+		{
+			name: "Security groups cannot be applied to ports without port security",
+			spec: OpenStackMachineSpec{
+				Image: "foobar",
+				Ports: []PortOpts{{
+					DisablePortSecurity:  pointer.Bool(true),
+					SecurityGroupFilters: []SecurityGroupFilter{{Name: "foobar"}},
+				}},
+			},
+			wantFields: []string{"spec.ports[0].securityGroupFilters"},
+		},
+		{
+			name: "Security group filters must not be empty",
+			spec: OpenStackMachineSpec{
+				Image:          "foobar",
+				SecurityGroups: []SecurityGroupFilter{{Name: "foobar"}, {}},
+				Ports: []PortOpts{{
+					SecurityGroupFilters: []SecurityGroupFilter{{FilterByNeutronTags: FilterByNeutronTags{Tags: []NeutronTag{"foo"}}}, {}},
+				}},
+			},
+			wantFields: []string{"spec.securityGroups[1]", "spec.ports[0].securityGroupFilters[1]"},
+		},
+		{
+			name: "Floating IP pool cannot be combined with a floating IP",
+			spec: OpenStackMachineSpec{
+				Image:      "foobar",
+				FloatingIP: "192.0.2.10",
+				FloatingIPPoolRef: &corev1.TypedLocalObjectReference{
+					APIGroup: pointer.String("infrastructure.cluster.x-k8s.io"),
+					Kind:     "OpenStackFloatingIPPool",
+					Name:     "public",
+				},
+			},
+			wantFields: []string{"spec.floatingIPPoolRef"},
+		},
+		{
+			name: "Floating IP pool reference must be complete",
+			spec: OpenStackMachineSpec{
+				Image:             "foobar",
+				FloatingIPPoolRef: &corev1.TypedLocalObjectReference{Name: "public"},
+			},
+			wantFields: []string{"spec.floatingIPPoolRef.apiGroup", "spec.floatingIPPoolRef.kind"},
+		},
+		{
+			name: "Node labels must be server metadata with valid label keys and values",
+			spec: OpenStackMachineSpec{
+				Image:          "foobar",
+				ServerMetadata: map[string]string{"rack": "r1", "owner name": "team", "comment": "not a label value"},
+				NodeLabels:     &NodeLabels{Metadata: []string{"rack", "owner name", "comment", "missing"}},
+			},
+			wantFields: []string{"spec.nodeLabels.metadata[1]", "spec.serverMetadata[comment]", "spec.nodeLabels.metadata[3]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateOpenStackMachineSpec(&tt.spec, field.NewPath("spec"))
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(Equal(tt.wantFields))
+
+			err := (&OpenStackMachine{Spec: tt.spec}).ValidateCreate()
+			g.Expect(err != nil).To(Equal(len(tt.wantFields) > 0))
+		})
+	}
+}
+
+func TestOpenStackMachine_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name    string
+		oldSpec OpenStackMachineSpec
+		newSpec OpenStackMachineSpec
+		wantErr bool
+	}{
+		{
+			name:    "Setting the provider ID and instance ID once is allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar"},
+			newSpec: OpenStackMachineSpec{Image: "foobar", ProviderID: pointer.String("openstack:///foobar"), InstanceID: pointer.String("foobar")},
+			wantErr: false,
+		},
+		{
+			name:    "Defaulting a machine created before the defaults were introduced is allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar"},
+			newSpec: OpenStackMachineSpec{Image: "foobar", Ports: []PortOpts{{Trunk: pointer.Bool(false)}}},
+			wantErr: false,
+		},
+		{
+			name:    "Changing the failure domain before the instance is created is allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", FailureDomain: "az1"},
+			newSpec: OpenStackMachineSpec{Image: "foobar", FailureDomain: "az2"},
+			wantErr: false,
+		},
+		{
+			name:    "Changing the failure domain of an instance is not allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", FailureDomain: "az1", InstanceID: pointer.String("foobar")},
+			newSpec: OpenStackMachineSpec{Image: "foobar", FailureDomain: "az2", InstanceID: pointer.String("foobar")},
+			wantErr: true,
+		},
+		{
+			name:    "Changing the image is not allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar"},
+			newSpec: OpenStackMachineSpec{Image: "foobarbaz"},
+			wantErr: true,
+		},
+		{
+			name:    "Adding and removing secondary ports with a name suffix is allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{}, {NameSuffix: "storage", Network: &NetworkFilter{ID: "storage"}}}},
+			newSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{}, {NameSuffix: "backup", Network: &NetworkFilter{ID: "backup"}}}},
+			wantErr: false,
+		},
+		{
+			name:    "Adding a secondary port without a name suffix is not allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{}}},
+			newSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{}, {Network: &NetworkFilter{ID: "storage"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "Changing the security groups of ports is allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{}, {NameSuffix: "storage", Network: &NetworkFilter{ID: "storage"}}}},
+			newSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{SecurityGroupFilters: []SecurityGroupFilter{{Name: "primary"}}}, {NameSuffix: "storage", Network: &NetworkFilter{ID: "storage"}, SecurityGroupFilters: []SecurityGroupFilter{{Name: "storage"}}}}},
+			wantErr: false,
+		},
+		{
+			name:    "Growing the root volume is allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), RootVolume: &RootVolume{Size: 50}},
+			newSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), RootVolume: &RootVolume{Size: 100}},
+			wantErr: false,
+		},
+		{
+			name:    "Shrinking the root volume is not allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), RootVolume: &RootVolume{Size: 100}},
+			newSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), RootVolume: &RootVolume{Size: 50}},
+			wantErr: true,
+		},
+		{
+			name:    "Replacing the primary port is not allowed",
+			oldSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{NameSuffix: "primary"}}},
+			newSpec: OpenStackMachineSpec{Image: "foobar", InstanceID: pointer.String("foobar"), Ports: []PortOpts{{NameSuffix: "storage", Network: &NetworkFilter{ID: "storage"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&OpenStackMachine{Spec: tt.newSpec}).ValidateUpdate(&OpenStackMachine{Spec: tt.oldSpec})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
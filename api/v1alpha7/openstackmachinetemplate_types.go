@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResizeRootVolumesAnnotation on an OpenStackMachineTemplate allows
+// increasing the size of its root volume, which extends the root volumes of
+// the machines created from it in place instead of replacing them.
+const ResizeRootVolumesAnnotation = "infrastructure.cluster.x-k8s.io/resize-root-volumes"
+
+// OpenStackMachineTemplateSpec defines the desired state of OpenStackMachineTemplate.
+type OpenStackMachineTemplateSpec struct {
+	Template OpenStackMachineTemplateResource `json:"template"`
+}
+
+// OpenStackMachineTemplateStatus defines the observed state of OpenStackMachineTemplate.
+type OpenStackMachineTemplateStatus struct {
+	// Capacity defines the resource capacity of the flavor referenced by
+	// this template. It is used by the cluster autoscaler to scale a node
+	// group up from zero replicas, see
+	// https://github.com/kubernetes-sigs/cluster-api/blob/main/docs/proposals/20210310-opt-in-autoscaling-from-zero.md
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackmachinetemplates,scope=Namespaced,categories=cluster-api,shortName=osmt
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Flavor",type="string",JSONPath=".spec.template.spec.flavor",description="Flavor of the instances"
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.template.spec.image",description="Image of the instances"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackMachineTemplate"
+
+// OpenStackMachineTemplate is the Schema for the openstackmachinetemplates API.
+type OpenStackMachineTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackMachineTemplateSpec   `json:"spec,omitempty"`
+	Status OpenStackMachineTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackMachineTemplateList contains a list of OpenStackMachineTemplate.
+type OpenStackMachineTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackMachineTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackMachineTemplate{}, &OpenStackMachineTemplateList{})
+}
@@ -14,12 +14,11 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1alpha6
+package v1alpha7
 
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -44,7 +43,7 @@ func (r *OpenStackMachineTemplateWebhook) SetupWebhookWithManager(mgr manager.Ma
 		Complete()
 }
 
-// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha6-openstackmachinetemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinetemplates,versions=v1alpha6,name=validation.openstackmachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackmachinetemplate,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinetemplates,versions=v1alpha7,name=validation.openstackmachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var _ webhook.CustomValidator = &OpenStackMachineTemplateWebhook{}
 
@@ -61,6 +60,12 @@ func (r *OpenStackMachineTemplateWebhook) ValidateCreate(ctx context.Context, ob
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "providerID"), "cannot be set in templates"))
 	}
 
+	if openStackMachineTemplate.Spec.Template.Spec.InstanceID != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "instanceID"), "cannot be set in templates"))
+	}
+
+	allErrs = append(allErrs, validateOpenStackMachineSpec(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec"))...)
+
 	return aggregateObjErrors(openStackMachineTemplate.GroupVersionKind().GroupKind(), openStackMachineTemplate.Name, allErrs)
 }
 
@@ -82,11 +87,17 @@ func (r *OpenStackMachineTemplateWebhook) ValidateUpdate(ctx context.Context, ol
 		return apierrors.NewBadRequest(fmt.Sprintf("expected a admission.Request inside context: %v", err))
 	}
 
-	if !topology.ShouldSkipImmutabilityChecks(req, newObj) &&
-		!reflect.DeepEqual(newObj.Spec.Template.Spec, old.Spec.Template.Spec) {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "template", "spec"), r, OpenStackMachineTemplateImmutableMsg),
-		)
+	if !topology.ShouldSkipImmutabilityChecks(req, newObj) {
+		oldSpec := old.Spec.Template.Spec.DeepCopy()
+		newSpec := newObj.Spec.Template.Spec.DeepCopy()
+
+		// allow growing the root volume of templates which extend the root
+		// volumes of their machines in place
+		if newObj.Annotations[ResizeRootVolumesAnnotation] == "true" {
+			allErrs = append(allErrs, allowRootVolumeGrowth(field.NewPath("spec", "template", "spec", "rootVolume"), oldSpec.RootVolume, newSpec.RootVolume)...)
+		}
+
+		allErrs = append(allErrs, immutableFieldErrors(field.NewPath("spec", "template", "spec"), oldSpec, newSpec, OpenStackMachineTemplateImmutableMsg)...)
 	}
 
 	return aggregateObjErrors(newObj.GroupVersionKind().GroupKind(), newObj.Name, allErrs)
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1alpha6
+package v1alpha7
 
 import (
 	"context"
@@ -93,6 +93,33 @@ func TestOpenStackMachineTemplate_ValidateUpdate(t *testing.T) {
 			},
 			req: &admission.Request{},
 		},
+		{
+			name: "OpenStackMachineTemplate with mutable template metadata",
+			oldTemplate: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+						},
+					},
+				},
+			},
+			newTemplate: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						ObjectMeta: clusterv1.ObjectMeta{
+							Labels: map[string]string{"tier": "gpu"},
+						},
+						Spec: OpenStackMachineSpec{
+							Flavor: "foo",
+							Image:  "bar",
+						},
+					},
+				},
+			},
+			req: &admission.Request{},
+		},
 		{
 			name: "don't allow modification, dry run, no skip immutability annotation set",
 			oldTemplate: &OpenStackMachineTemplate{
@@ -147,9 +174,110 @@ func TestOpenStackMachineTemplate_ValidateUpdate(t *testing.T) {
 			},
 			req: &admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(true)}},
 		},
+		{
+			name: "don't allow growing the root volume without the resize annotation",
+			oldTemplate: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:     "foo",
+							Image:      "bar",
+							RootVolume: &RootVolume{Size: 50},
+						},
+					},
+				},
+			},
+			newTemplate: &OpenStackMachineTemplate{
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:     "foo",
+							Image:      "bar",
+							RootVolume: &RootVolume{Size: 100},
+						},
+					},
+				},
+			},
+			req:     &admission.Request{},
+			wantErr: true,
+		},
+		{
+			name: "allow growing the root volume with the resize annotation",
+			oldTemplate: &OpenStackMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ResizeRootVolumesAnnotation: "true",
+					},
+				},
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:     "foo",
+							Image:      "bar",
+							RootVolume: &RootVolume{Size: 50},
+						},
+					},
+				},
+			},
+			newTemplate: &OpenStackMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ResizeRootVolumesAnnotation: "true",
+					},
+				},
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:     "foo",
+							Image:      "bar",
+							RootVolume: &RootVolume{Size: 100},
+						},
+					},
+				},
+			},
+			req: &admission.Request{},
+		},
+		{
+			name: "don't allow shrinking the root volume with the resize annotation",
+			oldTemplate: &OpenStackMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ResizeRootVolumesAnnotation: "true",
+					},
+				},
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:     "foo",
+							Image:      "bar",
+							RootVolume: &RootVolume{Size: 100},
+						},
+					},
+				},
+			},
+			newTemplate: &OpenStackMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						ResizeRootVolumesAnnotation: "true",
+					},
+				},
+				Spec: OpenStackMachineTemplateSpec{
+					Template: OpenStackMachineTemplateResource{
+						Spec: OpenStackMachineSpec{
+							Flavor:     "foo",
+							Image:      "bar",
+							RootVolume: &RootVolume{Size: 50},
+						},
+					},
+				},
+			},
+			req:     &admission.Request{},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationPhase is the phase of the remediation of a machine.
+type RemediationPhase string
+
+const (
+	// RemediationPhaseSoftRebooting is the phase after the server of the
+	// machine was soft rebooted.
+	RemediationPhaseSoftRebooting RemediationPhase = "SoftRebooting"
+
+	// RemediationPhaseHardRebooting is the phase after the server of the
+	// machine was hard rebooted.
+	RemediationPhaseHardRebooting RemediationPhase = "HardRebooting"
+
+	// RemediationPhaseWaitingForMachineDeletion is the phase after the
+	// reboots did not remediate the machine, and the owner of the machine was
+	// asked to delete and recreate it.
+	RemediationPhaseWaitingForMachineDeletion RemediationPhase = "WaitingForMachineDeletion"
+)
+
+// OpenStackRemediationSpec defines the desired state of OpenStackRemediation.
+type OpenStackRemediationSpec struct {
+	// SkipSoftReboot hard reboots the server of the machine right away,
+	// instead of soft rebooting it first.
+	// +optional
+	SkipSoftReboot bool `json:"skipSoftReboot,omitempty"`
+
+	// Timeout is how long the machine is given to become healthy again after
+	// each reboot, before the next step of the remediation. Defaults to 5m.
+	// +kubebuilder:default="5m"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// OpenStackRemediationStatus defines the observed state of OpenStackRemediation.
+type OpenStackRemediationStatus struct {
+	// Phase is the current step of the remediation.
+	// +optional
+	Phase RemediationPhase `json:"phase,omitempty"`
+
+	// LastRemediated is the time of the last step of the remediation.
+	// +optional
+	LastRemediated *metav1.Time `json:"lastRemediated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackremediations,scope=Namespaced,categories=cluster-api,shortName=osr
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the remediation"
+// +kubebuilder:printcolumn:name="Last Remediated",type="date",JSONPath=".status.lastRemediated",description="Time of the last step of the remediation"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackRemediation"
+
+// OpenStackRemediation is the Schema for the openstackremediations API. It is
+// created by a MachineHealthCheck for an unhealthy machine, which is then
+// rebooted before it is replaced.
+type OpenStackRemediation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackRemediationSpec   `json:"spec,omitempty"`
+	Status OpenStackRemediationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackRemediationList contains a list of OpenStackRemediation.
+type OpenStackRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackRemediation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackRemediation{}, &OpenStackRemediationList{})
+}
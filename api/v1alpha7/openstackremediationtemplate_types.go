@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OpenStackRemediationTemplateSpec defines the desired state of OpenStackRemediationTemplate.
+type OpenStackRemediationTemplateSpec struct {
+	Template OpenStackRemediationTemplateResource `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackremediationtemplates,scope=Namespaced,categories=cluster-api,shortName=osrt
+// +kubebuilder:printcolumn:name="Timeout",type="string",JSONPath=".spec.template.spec.timeout",description="Time to wait for each reboot"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackRemediationTemplate"
+
+// OpenStackRemediationTemplate is the Schema for the openstackremediationtemplates
+// API. It is referenced by the remediationTemplate of a MachineHealthCheck.
+type OpenStackRemediationTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OpenStackRemediationTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackRemediationTemplateList contains a list of OpenStackRemediationTemplate.
+type OpenStackRemediationTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackRemediationTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackRemediationTemplate{}, &OpenStackRemediationTemplateList{})
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ServerFinalizer allows ReconcileOpenStackServer to clean up the OpenStack resources of an OpenStackServer before
+	// removing it from the apiserver.
+	ServerFinalizer = "openstackserver.infrastructure.cluster.x-k8s.io"
+)
+
+// OpenStackServerSpec defines the desired state of OpenStackServer.
+type OpenStackServerSpec struct {
+	// InstanceID is the ID of an existing server which is adopted instead
+	// of creating a new one.
+	// +optional
+	InstanceID *string `json:"instanceID,omitempty"`
+
+	// The name of the cloud to use from the clouds secret
+	// +optional
+	CloudName string `json:"cloudName,omitempty"`
+
+	// Region is the name of the OpenStack region in which the server is
+	// created. It overrides the region of the cloud.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// IdentityRef is a reference to a identity to be used when reconciling this server.
+	// +optional
+	IdentityRef *OpenStackIdentityReference `json:"identityRef,omitempty"`
+
+	// The flavor reference for the flavor for your server instance.
+	Flavor string `json:"flavor"`
+
+	// The name of the image to use for your server instance.
+	// If the RootVolume is specified, this will be ignored and use rootVolume directly.
+	Image string `json:"image,omitempty"`
+
+	// The uuid of the image to use for your server instance.
+	// if it's empty, Image name will be used
+	ImageUUID string `json:"imageUUID,omitempty"`
+
+	// The ssh key to inject in the instance
+	SSHKeyName string `json:"sshKeyName,omitempty"`
+
+	// Networks to attach the server instance to.
+	Networks []NetworkParam `json:"networks,omitempty"`
+
+	// Ports to be attached to the server instance. They are created if a port with the given name does not already exist.
+	// Unlike the ports of an OpenStackMachine, every port must set its network.
+	Ports []PortOpts `json:"ports,omitempty"`
+
+	// The security groups to assign to the instance, selected by their IDs or by filters.
+	SecurityGroups []SecurityGroupFilter `json:"securityGroups,omitempty"`
+
+	// Whether the server instance is created on a trunk port or not.
+	Trunk bool `json:"trunk,omitempty"`
+
+	// Server tags
+	// Requires Nova api 2.52 minimum!
+	// +listType=set
+	Tags []string `json:"tags,omitempty"`
+
+	// Metadata mapping. Allows you to create a map of key value pairs to add to the server instance.
+	ServerMetadata map[string]string `json:"serverMetadata,omitempty"`
+
+	// Config Drive support
+	ConfigDrive *bool `json:"configDrive,omitempty"`
+
+	// The volume metadata to boot from
+	RootVolume *RootVolume `json:"rootVolume,omitempty"`
+
+	// RetainRootVolume keeps the root volume when the server is deleted
+	// together with its cluster. Volumes of servers deleted on their own are
+	// still deleted.
+	// +optional
+	RetainRootVolume bool `json:"retainRootVolume,omitempty"`
+
+	// AvailabilityZone is the compute availability zone of the server.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// The server group to assign the server to
+	ServerGroupID string `json:"serverGroupID,omitempty"`
+
+	// UserDataRef is a reference to a secret in the namespace of the
+	// OpenStackServer whose "value" key holds the user data of the server.
+	// +optional
+	UserDataRef *corev1.LocalObjectReference `json:"userDataRef,omitempty"`
+}
+
+// OpenStackServerStatus defines the observed state of OpenStackServer.
+type OpenStackServerStatus struct {
+	// Ready is true when the server is active.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// InstanceID is the ID of the server.
+	// +optional
+	InstanceID *string `json:"instanceID,omitempty"`
+
+	// Addresses contains the addresses of the server.
+	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+
+	// InstanceState is the state of the server.
+	// +optional
+	InstanceState *InstanceState `json:"instanceState,omitempty"`
+
+	// AvailabilityZone is the availability zone the server was scheduled to.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// ResourceIDs are the IDs of the OpenStack resources used by the server.
+	// +optional
+	ResourceIDs *InstanceResourceIDs `json:"resourceIDs,omitempty"`
+
+	// AttachedPortNameSuffixes are the name suffixes of the secondary ports of
+	// the spec which are attached to the server, so that ports removed from
+	// the spec are detached.
+	// +listType=set
+	// +optional
+	AttachedPortNameSuffixes []string `json:"attachedPortNameSuffixes,omitempty"`
+
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openstackservers,scope=Namespaced,categories=cluster-api,shortName=oss
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="InstanceState",type="string",JSONPath=".status.instanceState",description="OpenStack instance state"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Server ready status"
+// +kubebuilder:printcolumn:name="InstanceID",type="string",JSONPath=".status.instanceID",description="OpenStack instance ID"
+// +kubebuilder:printcolumn:name="IP",type="string",JSONPath=".status.addresses[?(@.type==\"InternalIP\")].address",description="Internal IP addresses of the instance"
+// +kubebuilder:printcolumn:name="Flavor",type="string",JSONPath=".spec.flavor",description="Flavor of the instance",priority=1
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image",description="Image of the instance",priority=1
+// +kubebuilder:printcolumn:name="AZ",type="string",JSONPath=".status.availabilityZone",description="Availability zone of the instance",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of OpenStackServer"
+
+// OpenStackServer is the Schema for the openstackservers API. It manages the
+// lifecycle of a single OpenStack server, independently of whether it is a
+// node of a cluster.
+type OpenStackServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenStackServerSpec   `json:"spec,omitempty"`
+	Status OpenStackServerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OpenStackServerList contains a list of OpenStackServer.
+type OpenStackServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenStackServer `json:"items"`
+}
+
+// GetConditions returns the observations of the operational state of the OpenStackServer resource.
+func (r *OpenStackServer) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the OpenStackServer to the predescribed clusterv1.Conditions.
+func (r *OpenStackServer) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenStackServer{}, &OpenStackServerList{})
+}
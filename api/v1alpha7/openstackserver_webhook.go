@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const openStackServerImmutableMsg = "cannot be modified, an OpenStackServer is not updated in place. Replace the OpenStackServer instead"
+
+func (r *OpenStackServer) SetupWebhookWithManager(mgr manager.Manager) error {
+	return builder.WebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackserver,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackservers,versions=v1alpha7,name=validation.openstackserver.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackserver,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackservers,versions=v1alpha7,name=default.openstackserver.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+var (
+	_ webhook.Defaulter = &OpenStackServer{}
+	_ webhook.Validator = &OpenStackServer{}
+)
+
+// Default satisfies the defaulting webhook interface.
+func (r *OpenStackServer) Default() {
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind == "" {
+		r.Spec.IdentityRef.Kind = defaultIdentityRefKind
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackServer) ValidateCreate() error {
+	var allErrs field.ErrorList
+
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != defaultIdentityRefKind {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
+	}
+
+	allErrs = append(allErrs, validateOpenStackServerSpec(&r.Spec, field.NewPath("spec"))...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackServer) ValidateUpdate(oldRaw runtime.Object) error {
+	old, ok := oldRaw.(*OpenStackServer)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an OpenStackServer but got a %T", oldRaw))
+	}
+
+	var allErrs field.ErrorList
+
+	if r.Spec.IdentityRef != nil && r.Spec.IdentityRef.Kind != defaultIdentityRefKind {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "identityRef", "kind"), "must be a Secret"))
+	}
+
+	// allow adding and removing secondary ports, which are attached to and
+	// detached from the running instance, and changing the security groups
+	// of ports
+	oldSpec := old.Spec.DeepCopy()
+	newSpec := r.Spec.DeepCopy()
+	oldSpec.Ports = withoutSecurityGroupFilters(withoutHotPluggablePorts(oldSpec.Networks, oldSpec.Ports))
+	newSpec.Ports = withoutSecurityGroupFilters(withoutHotPluggablePorts(newSpec.Networks, newSpec.Ports))
+
+	// allow growing the root volume, which is extended in place
+	allErrs = append(allErrs, allowRootVolumeGrowth(field.NewPath("spec", "rootVolume"), oldSpec.RootVolume, newSpec.RootVolume)...)
+
+	allErrs = append(allErrs, immutableFieldErrors(field.NewPath("spec"), oldSpec, newSpec, openStackServerImmutableMsg)...)
+
+	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *OpenStackServer) ValidateDelete() error {
+	return nil
+}
+
+// validateOpenStackServerSpec validates the combinations of fields of an
+// OpenStackServerSpec which OpenStack would reject or ignore.
+func validateOpenStackServerSpec(spec *OpenStackServerSpec, path *field.Path) field.ErrorList {
+	// The fields shared with OpenStackMachineSpec are validated the same way
+	machineSpec := &OpenStackMachineSpec{
+		Image:          spec.Image,
+		ImageUUID:      spec.ImageUUID,
+		RootVolume:     spec.RootVolume,
+		SecurityGroups: spec.SecurityGroups,
+		Ports:          spec.Ports,
+		Trunk:          spec.Trunk,
+	}
+	allErrs := validateOpenStackMachineSpec(machineSpec, path)
+
+	if len(spec.Networks) == 0 && len(spec.Ports) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("ports"), "either networks or ports must be set"))
+	}
+
+	// There is no cluster network to default the network of a port to
+	for i := range spec.Ports {
+		if network := spec.Ports[i].Network; network == nil || network.IsEmpty() {
+			allErrs = append(allErrs, field.Required(path.Child("ports").Index(i).Child("network"), "must be set"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+)
+
+func TestOpenStackServer_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		name       string
+		spec       OpenStackServerSpec
+		wantFields []string
+	}{
+		{
+			name: "Valid spec with ports",
+			spec: OpenStackServerSpec{
+				Image: "foobar",
+				Ports: []PortOpts{{Network: &NetworkFilter{ID: "foobar"}, Trunk: pointer.Bool(false)}},
+			},
+		},
+		{
+			name: "Valid spec with networks",
+			spec: OpenStackServerSpec{
+				Image:    "foobar",
+				Networks: []NetworkParam{{UUID: "foobar"}},
+			},
+		},
+		{
+			name: "Networks or ports are required",
+			spec: OpenStackServerSpec{
+				Image: "foobar",
+			},
+			wantFields: []string{"spec.ports"},
+		},
+		{
+			name: "Ports must set their network",
+			spec: OpenStackServerSpec{
+				Image: "foobar",
+				Ports: []PortOpts{{Network: &NetworkFilter{Name: "foobar"}}, {NameSuffix: "b"}, {Network: &NetworkFilter{}}},
+			},
+			wantFields: []string{"spec.ports[1].network", "spec.ports[2].network"},
+		},
+		{
+			name: "Fields shared with machines are validated",
+			spec: OpenStackServerSpec{
+				Ports: []PortOpts{{Network: &NetworkFilter{ID: "foobar"}}},
+			},
+			wantFields: []string{"spec.image"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateOpenStackServerSpec(&tt.spec, field.NewPath("spec"))
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(Equal(tt.wantFields))
+
+			err := (&OpenStackServer{Spec: tt.spec}).ValidateCreate()
+			g.Expect(err != nil).To(Equal(len(tt.wantFields) > 0))
+		})
+	}
+}
+
+func TestOpenStackServer_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := OpenStackServerSpec{
+		Image: "foobar",
+		Ports: []PortOpts{{Network: &NetworkFilter{ID: "foobar"}}},
+	}
+
+	changedSpec := *spec.DeepCopy()
+	changedSpec.Flavor = "foobar"
+
+	g.Expect((&OpenStackServer{Spec: spec}).ValidateUpdate(&OpenStackServer{Spec: spec})).To(Succeed())
+	g.Expect((&OpenStackServer{Spec: changedSpec}).ValidateUpdate(&OpenStackServer{Spec: spec})).NotTo(Succeed())
+
+	hotPluggedSpec := *spec.DeepCopy()
+	hotPluggedSpec.Ports = append(hotPluggedSpec.Ports, PortOpts{NameSuffix: "storage", Network: &NetworkFilter{ID: "storage"}})
+	g.Expect((&OpenStackServer{Spec: hotPluggedSpec}).ValidateUpdate(&OpenStackServer{Spec: spec})).To(Succeed())
+	g.Expect((&OpenStackServer{Spec: spec}).ValidateUpdate(&OpenStackServer{Spec: hotPluggedSpec})).To(Succeed())
+
+	securityGroupsSpec := *spec.DeepCopy()
+	securityGroupsSpec.Ports[0].SecurityGroupFilters = []SecurityGroupFilter{{ID: "foobar"}}
+	g.Expect((&OpenStackServer{Spec: securityGroupsSpec}).ValidateUpdate(&OpenStackServer{Spec: spec})).To(Succeed())
+
+	rootVolumeSpec := *spec.DeepCopy()
+	rootVolumeSpec.RootVolume = &RootVolume{Size: 50, VolumeType: "ssd"}
+	grownRootVolumeSpec := *rootVolumeSpec.DeepCopy()
+	grownRootVolumeSpec.RootVolume.Size = 100
+	g.Expect((&OpenStackServer{Spec: grownRootVolumeSpec}).ValidateUpdate(&OpenStackServer{Spec: rootVolumeSpec})).To(Succeed())
+	g.Expect((&OpenStackServer{Spec: rootVolumeSpec}).ValidateUpdate(&OpenStackServer{Spec: grownRootVolumeSpec})).NotTo(Succeed())
+	grownRootVolumeSpec.RootVolume.VolumeType = "hdd"
+	g.Expect((&OpenStackServer{Spec: grownRootVolumeSpec}).ValidateUpdate(&OpenStackServer{Spec: rootVolumeSpec})).NotTo(Succeed())
+}
@@ -0,0 +1,776 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"net"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// OpenStackMachineTemplateResource describes the data needed to create a OpenStackMachine from a template.
+type OpenStackMachineTemplateResource struct {
+	// ObjectMeta holds the labels and annotations which the topology
+	// controller of a ClusterClass propagates to the OpenStackMachines
+	// created from the template.
+	// +optional
+	ObjectMeta clusterv1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the desired behavior of the machine.
+	Spec OpenStackMachineSpec `json:"spec"`
+}
+
+// OpenStackRemediationTemplateResource describes the data needed to create an OpenStackRemediation from a template.
+type OpenStackRemediationTemplateResource struct {
+	// Spec is the specification of the desired behavior of the remediation.
+	Spec OpenStackRemediationSpec `json:"spec"`
+}
+
+type ExternalRouterIPParam struct {
+	// The FixedIP in the corresponding subnet
+	FixedIP string `json:"fixedIP,omitempty"`
+	// The subnet in which the FixedIP is used for the Gateway of this router
+	Subnet SubnetParam `json:"subnet"`
+}
+
+// RouterRoute is a static route of a router.
+type RouterRoute struct {
+	// Destination is the CIDR of the destination of the route.
+	Destination string `json:"destination"`
+	// NextHop is the IP address of the next hop of the route, which must be
+	// in a subnet connected to the router.
+	NextHop string `json:"nextHop"`
+}
+
+// NeutronTag represents a tag on a Neutron resource.
+// It may not be empty and may not contain commas.
+// +kubebuilder:validation:Pattern:="^[^,]+$"
+// +kubebuilder:validation:MinLength:=1
+type NeutronTag string
+
+// FilterByNeutronTags selects Neutron resources by their tags.
+type FilterByNeutronTags struct {
+	// Tags is a list of tags to filter by. If specified, the resource must
+	// have all of the tags specified to be included in the result.
+	// +listType=set
+	// +optional
+	Tags []NeutronTag `json:"tags,omitempty"`
+
+	// TagsAny is a list of tags to filter by. If specified, the resource
+	// must have at least one of the tags specified to be included in the
+	// result.
+	// +listType=set
+	// +optional
+	TagsAny []NeutronTag `json:"tagsAny,omitempty"`
+
+	// NotTags is a list of tags to filter by. If specified, resources which
+	// contain all of the given tags will be excluded from the result.
+	// +listType=set
+	// +optional
+	NotTags []NeutronTag `json:"notTags,omitempty"`
+
+	// NotTagsAny is a list of tags to filter by. If specified, resources
+	// which contain any of the given tags will be excluded from the result.
+	// +listType=set
+	// +optional
+	NotTagsAny []NeutronTag `json:"notTagsAny,omitempty"`
+}
+
+// SecurityGroupFilter selects a security group by its ID, or by a query on
+// its name, description, project and tags which must return exactly one
+// security group.
+type SecurityGroupFilter struct {
+	// ID of the security group. If it is the only field set, the security
+	// group is used without querying OpenStack.
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	ProjectID   string `json:"projectId,omitempty"`
+
+	FilterByNeutronTags `json:",inline"`
+}
+
+type NetworkParam struct {
+	// Optional UUID of the network.
+	// If specified this will not be validated prior to server creation.
+	// Required if `Subnets` specifies a subnet by UUID.
+	UUID string `json:"uuid,omitempty"`
+	// A fixed IPv4 address for the NIC.
+	FixedIP string `json:"fixedIP,omitempty"`
+	// Filters for optional network query
+	Filter NetworkFilter `json:"filter,omitempty"`
+	// Subnet within a network to use
+	Subnets []SubnetParam `json:"subnets,omitempty"`
+}
+
+// NetworkFilter selects a network by a query on its ID, name, description,
+// project and tags.
+type NetworkFilter struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	ProjectID   string `json:"projectId,omitempty"`
+	ID          string `json:"id,omitempty"`
+
+	FilterByNeutronTags `json:",inline"`
+}
+
+type SubnetParam struct {
+	// Optional UUID of the subnet.
+	// If specified this will not be validated prior to server creation.
+	// If specified, the enclosing `NetworkParam` must also be specified by UUID.
+	UUID string `json:"uuid,omitempty"`
+
+	// Filters for optional subnet query
+	Filter SubnetFilter `json:"filter,omitempty"`
+}
+
+// SubnetFilter selects a subnet by a query on its ID, name, description,
+// project, addressing and tags.
+type SubnetFilter struct {
+	Name            string `json:"name,omitempty"`
+	Description     string `json:"description,omitempty"`
+	ProjectID       string `json:"projectId,omitempty"`
+	IPVersion       int    `json:"ipVersion,omitempty"`
+	GatewayIP       string `json:"gateway_ip,omitempty"`
+	CIDR            string `json:"cidr,omitempty"`
+	IPv6AddressMode string `json:"ipv6AddressMode,omitempty"`
+	IPv6RAMode      string `json:"ipv6RaMode,omitempty"`
+	ID              string `json:"id,omitempty"`
+
+	FilterByNeutronTags `json:",inline"`
+}
+
+type PortOpts struct {
+	// Network is a query for an openstack network that the port will be created or discovered on.
+	// This will fail if the query returns more than one network.
+	Network *NetworkFilter `json:"network,omitempty"`
+	// ID is the ID of an existing port which is attached to the server
+	// instead of creating a port, e.g. a port created by an external IPAM
+	// workflow. The port must not be attached to another server. It is used
+	// as it is and is never changed or deleted, so no other options may be
+	// set for it.
+	ID string `json:"id,omitempty"`
+	// Used to make the name of the port unique. If unspecified, instead the 0-based index of the port in the list is used.
+	NameSuffix   string `json:"nameSuffix,omitempty"`
+	Description  string `json:"description,omitempty"`
+	AdminStateUp *bool  `json:"adminStateUp,omitempty"`
+	MACAddress   string `json:"macAddress,omitempty"`
+	// Specify pairs of subnet and/or IP address. These should be subnets of the network with the given NetworkID.
+	FixedIPs []FixedIP `json:"fixedIPs,omitempty"`
+	// FixedIPPerSubnet requests a fixed IP from every subnet of the network which is not
+	// already selected by FixedIPs, instead of letting Neutron choose the subnets.
+	FixedIPPerSubnet bool   `json:"fixedIPPerSubnet,omitempty"`
+	TenantID         string `json:"tenantId,omitempty"`
+	ProjectID        string `json:"projectId,omitempty"`
+	// The security groups to assign to the port, selected by their IDs or by filters
+	SecurityGroupFilters []SecurityGroupFilter `json:"securityGroupFilters,omitempty"`
+	AllowedAddressPairs  []AddressPair         `json:"allowedAddressPairs,omitempty"`
+	// Enables and disables trunk at port level. If not provided, openStackMachine.Spec.Trunk is inherited.
+	Trunk *bool `json:"trunk,omitempty"`
+
+	// The ID of the host where the port is allocated
+	HostID string `json:"hostId,omitempty"`
+
+	// The virtual network interface card (vNIC) type that is bound to the neutron port.
+	VNICType string `json:"vnicType,omitempty"`
+
+	// A dictionary that enables the application running on the specified
+	// host to pass and receive virtual network interface (VIF) port-specific
+	// information to the plug-in.
+	Profile map[string]string `json:"profile,omitempty"`
+
+	// DisablePortSecurity enables or disables the port security when set.
+	// When not set, it takes the value of the corresponding field at the network level.
+	DisablePortSecurity *bool `json:"disablePortSecurity,omitempty"`
+
+	// Tags applied to the port (and corresponding trunk, if a trunk is configured.)
+	// These tags are applied in addition to the instance's tags, which will also be applied to the port.
+	// +listType=set
+	Tags []string `json:"tags,omitempty"`
+
+	// ExtraDHCPOpts are DHCP options which Neutron sends to the port in addition to its own,
+	// for example mtu, ntp-server or bootfile-name.
+	ExtraDHCPOpts []ExtraDHCPOpt `json:"extraDhcpOpts,omitempty"`
+}
+
+// IsHotPluggablePort returns whether the port at index i of ports can be
+// attached to and detached from a running instance: ports with a name suffix,
+// except the primary port. Ports without a name suffix are named by their
+// index, so they cannot be told apart once ports are added or removed.
+// Existing ports are never detached, so they are not hot pluggable either.
+func IsHotPluggablePort(networks []NetworkParam, ports []PortOpts, i int) bool {
+	return ports[i].NameSuffix != "" && ports[i].ID == "" && (i > 0 || len(networks) > 0)
+}
+
+type FixedIP struct {
+	// Subnet is an openstack subnet query that will return the id of a subnet to create
+	// the fixed IP of a port in. This query must not return more than one subnet.
+	Subnet    *SubnetFilter `json:"subnet"`
+	IPAddress string        `json:"ipAddress,omitempty"`
+}
+
+type AddressPair struct {
+	IPAddress  string `json:"ipAddress,omitempty"`
+	MACAddress string `json:"macAddress,omitempty"`
+}
+
+type ExtraDHCPOpt struct {
+	// Name is the name of the DHCP option.
+	Name string `json:"name"`
+	// Value is the value of the DHCP option.
+	Value string `json:"value"`
+	// IPVersion is the IP version of the DHCP server sending the option.
+	// If unspecified, it is sent by the DHCP servers of both IP versions.
+	// +kubebuilder:validation:Enum=4;6
+	IPVersion int `json:"ipVersion,omitempty"`
+}
+
+type Instance struct {
+	ID             string            `json:"id,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Trunk          bool              `json:"trunk,omitempty"`
+	FailureDomain  string            `json:"failureDomain,omitempty"`
+	SecurityGroups *[]string         `json:"securigyGroups,omitempty"`
+	Networks       *[]Network        `json:"networks,omitempty"`
+	Subnet         string            `json:"subnet,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Image          string            `json:"image,omitempty"`
+	ImageUUID      string            `json:"imageUUID,omitempty"`
+	Flavor         string            `json:"flavor,omitempty"`
+	SSHKeyName     string            `json:"sshKeyName,omitempty"`
+	UserData       string            `json:"userData,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	ConfigDrive    *bool             `json:"configDrive,omitempty"`
+	RootVolume     *RootVolume       `json:"rootVolume,omitempty"`
+	ServerGroupID  string            `json:"serverGroupID,omitempty"`
+	State          InstanceState     `json:"state,omitempty"`
+	IP             string            `json:"ip,omitempty"`
+	FloatingIP     string            `json:"floatingIP,omitempty"`
+}
+
+type RootVolume struct {
+	Size             int    `json:"diskSize,omitempty"`
+	VolumeType       string `json:"volumeType,omitempty"`
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+}
+
+// FlavorRequirements are extra specs which the flavor of a machine must set,
+// e.g. for latency sensitive workloads relying on CPU pinning, huge pages or
+// a NUMA topology.
+type FlavorRequirements struct {
+	// CPUPolicy is the required hw:cpu_policy of the flavor. Flavors without
+	// a CPU policy have the shared policy.
+	// +kubebuilder:validation:Enum=shared;dedicated;mixed
+	// +optional
+	CPUPolicy string `json:"cpuPolicy,omitempty"`
+
+	// MemPageSize is the required hw:mem_page_size of the flavor, e.g. large
+	// or 1GB.
+	// +optional
+	MemPageSize string `json:"memPageSize,omitempty"`
+
+	// NUMANodes is the required hw:numa_nodes of the flavor.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NUMANodes int `json:"numaNodes,omitempty"`
+}
+
+// NodeLabels are kubelet node labels which are added to the bootstrap data of
+// a machine, so that its node registers with them.
+type NodeLabels struct {
+	// FailureDomain adds the availability zone of the instance as the
+	// topology.kubernetes.io/zone label.
+	// +optional
+	FailureDomain bool `json:"failureDomain,omitempty"`
+
+	// Flavor adds the flavor of the instance as the
+	// node.kubernetes.io/instance-type label.
+	// +optional
+	Flavor bool `json:"flavor,omitempty"`
+
+	// Metadata are keys of the server metadata of the machine which are
+	// added as labels with the same key and value.
+	// +listType=set
+	// +optional
+	Metadata []string `json:"metadata,omitempty"`
+}
+
+// Network represents basic information about an OpenStack Neutron Network associated with an instance's port.
+type Network struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+
+	//+optional
+	Tags []string `json:"tags,omitempty"`
+
+	Subnet   *Subnet   `json:"subnet,omitempty"`
+	PortOpts *PortOpts `json:"port,omitempty"`
+	Router   *Router   `json:"router,omitempty"`
+
+	// Be careful when using APIServerLoadBalancer, because this field is optional and therefore not
+	// set in all cases
+	APIServerLoadBalancer *LoadBalancer `json:"apiServerLoadBalancer,omitempty"`
+}
+
+// Subnet represents basic information about the associated OpenStack Neutron Subnet.
+type Subnet struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+
+	CIDR string `json:"cidr"`
+
+	//+optional
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Router represents basic information about the associated OpenStack Neutron Router.
+type Router struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	//+optional
+	Tags []string `json:"tags,omitempty"`
+	//+optional
+	IPs []string `json:"ips,omitempty"`
+}
+
+// FirewallGroup represents basic information about the associated OpenStack
+// Neutron FWaaS v2 firewall group.
+type FirewallGroup struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// LoadBalancer represents basic information about the associated OpenStack LoadBalancer.
+type LoadBalancer struct {
+	Name       string `json:"name"`
+	ID         string `json:"id"`
+	IP         string `json:"ip"`
+	InternalIP string `json:"internalIP"`
+	//+optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// SecurityGroup represents the basic information of the associated
+// OpenStack Neutron Security Group.
+type SecurityGroup struct {
+	Name  string              `json:"name"`
+	ID    string              `json:"id"`
+	Rules []SecurityGroupRule `json:"rules"`
+}
+
+// SecurityGroupRule represent the basic information of the associated OpenStack
+// Security Group Role.
+type SecurityGroupRule struct {
+	Description     string `json:"description"`
+	ID              string `json:"name"`
+	Direction       string `json:"direction"`
+	EtherType       string `json:"etherType"`
+	SecurityGroupID string `json:"securityGroupID"`
+	PortRangeMin    int    `json:"portRangeMin"`
+	PortRangeMax    int    `json:"portRangeMax"`
+	Protocol        string `json:"protocol"`
+	RemoteGroupID   string `json:"remoteGroupID"`
+	RemoteIPPrefix  string `json:"remoteIPPrefix"`
+}
+
+// Equal checks if two SecurityGroupRules are the same. Fields populated by
+// Neutron, like the ID, are ignored, and remote IP prefixes are compared as
+// Neutron normalizes them.
+func (r SecurityGroupRule) Equal(x SecurityGroupRule) bool {
+	return (r.Direction == x.Direction &&
+		r.Description == x.Description &&
+		r.EtherType == x.EtherType &&
+		r.PortRangeMin == x.PortRangeMin &&
+		r.PortRangeMax == x.PortRangeMax &&
+		r.Protocol == x.Protocol &&
+		r.RemoteGroupID == x.RemoteGroupID &&
+		normalizeIPPrefix(r.RemoteIPPrefix) == normalizeIPPrefix(x.RemoteIPPrefix))
+}
+
+// normalizeIPPrefix returns the remote IP prefix of a security group rule as
+// Neutron stores it: addresses become prefixes of a single address and the
+// host bits of prefixes are cleared. Prefixes matching any address are
+// returned empty, as they are the same as no prefix.
+func normalizeIPPrefix(prefix string) string {
+	if ip := net.ParseIP(prefix); ip != nil {
+		if ip.To4() != nil {
+			prefix += "/32"
+		} else {
+			prefix += "/128"
+		}
+	}
+	_, ipNet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return prefix
+	}
+	if ones, _ := ipNet.Mask.Size(); ones == 0 {
+		return ""
+	}
+	return ipNet.String()
+}
+
+// InstanceState describes the state of an OpenStack instance.
+type InstanceState string
+
+var (
+	// InstanceStateBuilding is the string representing an instance in a building state.
+	InstanceStateBuilding = InstanceState("BUILDING")
+
+	// InstanceStateActive is the string representing an instance in an active state.
+	InstanceStateActive = InstanceState("ACTIVE")
+
+	// InstanceStateError is the string representing an instance in an error state.
+	InstanceStateError = InstanceState("ERROR")
+
+	// InstanceStateStopped is the string representing an instance in a stopped state.
+	InstanceStateStopped = InstanceState("STOPPED")
+
+	// InstanceStateShutoff is the string representing an instance in a shutoff state.
+	InstanceStateShutoff = InstanceState("SHUTOFF")
+
+	// InstanceStateDeleted is the string representing an instance in a deleted state.
+	InstanceStateDeleted = InstanceState("DELETED")
+)
+
+// Bastion represents basic information about the bastion node.
+type Bastion struct {
+	//+optional
+	Enabled bool `json:"enabled"`
+
+	// Instance for the bastion itself
+	Instance OpenStackMachineSpec `json:"instance,omitempty"`
+
+	//+optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+}
+
+type APIServerLoadBalancer struct {
+	// Enabled defines whether a load balancer should be created.
+	Enabled bool `json:"enabled,omitempty"`
+	// AdditionalPorts adds additional tcp ports to the load balancer.
+	AdditionalPorts []int `json:"additionalPorts,omitempty"`
+	// AllowedCIDRs restrict access to all API-Server listeners to the given address CIDRs.
+	AllowedCIDRs []string `json:"allowedCidrs,omitempty"`
+}
+
+// ManagedShare configures the Manila share of a cluster.
+type ManagedShare struct {
+	// Protocol is the protocol of the share. NFS shares are accessible from
+	// the subnet of the cluster, CephFS shares through a cephx user named
+	// after the cluster.
+	// +kubebuilder:validation:Enum=NFS;CEPHFS
+	// +kubebuilder:default=NFS
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// Size is the size of the share in GiB.
+	// +kubebuilder:validation:Minimum=1
+	Size int `json:"size"`
+
+	// ShareType is the name or ID of the share type. The default share type
+	// of the cloud is used if empty.
+	// +optional
+	ShareType string `json:"shareType,omitempty"`
+
+	// AvailabilityZone is the availability zone of the share.
+	// +optional
+	AvailabilityZone string `json:"availabilityZone,omitempty"`
+
+	// DisableShareNetwork creates the share without a share network bound to
+	// the cluster subnet. This is required for share types which do not
+	// handle share servers.
+	// +optional
+	DisableShareNetwork bool `json:"disableShareNetwork,omitempty"`
+
+	// SecretName is the name of the Secret in the kube-system namespace of
+	// the workload cluster which holds the export location and credentials
+	// of the share. It defaults to manila-share.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// Share represents basic information about the Manila share of a cluster.
+type Share struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Protocol       string `json:"protocol"`
+	ShareNetworkID string `json:"shareNetworkID,omitempty"`
+	AccessRuleID   string `json:"accessRuleID,omitempty"`
+	ExportLocation string `json:"exportLocation,omitempty"`
+}
+
+// Addons configures the cloud provider components installed into the
+// workload cluster.
+type Addons struct {
+	// CloudControllerManager installs the OpenStack cloud controller manager.
+	// +optional
+	CloudControllerManager *Addon `json:"cloudControllerManager,omitempty"`
+
+	// CinderCSI installs the Cinder CSI driver.
+	// +optional
+	CinderCSI *Addon `json:"cinderCSI,omitempty"`
+}
+
+// Addon configures a component of cloud-provider-openstack.
+type Addon struct {
+	// Version is the cloud-provider-openstack release to install, e.g.
+	// v1.25.5. It defaults to the release matching the Kubernetes version of
+	// the workload cluster.
+	// +kubebuilder:validation:Pattern=`^v[0-9]+\.[0-9]+\.[0-9]+$`
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ImageRepository is the repository the images of the component are
+	// pulled from. It defaults to registry.k8s.io/provider-os.
+	// +optional
+	ImageRepository string `json:"imageRepository,omitempty"`
+}
+
+// AddonsStatus contains the versions of the addons installed into the
+// workload cluster.
+type AddonsStatus struct {
+	CloudControllerManagerVersion string `json:"cloudControllerManagerVersion,omitempty"`
+	CinderCSIVersion              string `json:"cinderCSIVersion,omitempty"`
+
+	// CloudConfigChecksum is the checksum of the cloud.conf Secret applied to
+	// the workload cluster. The pods of the addons are restarted when it
+	// changes, e.g. when the credentials of the cluster are rotated.
+	CloudConfigChecksum string `json:"cloudConfigChecksum,omitempty"`
+}
+
+// CNIOverlay is the encapsulation used by the CNI of the workload cluster
+// between nodes.
+// +kubebuilder:validation:Enum=None;VXLAN;Geneve;IPIP;WireGuard
+type CNIOverlay string
+
+const (
+	// CNIOverlayNone routes pod traffic without encapsulation.
+	CNIOverlayNone CNIOverlay = "None"
+	// CNIOverlayVXLAN encapsulates pod traffic in VXLAN, e.g. Flannel or Calico in VXLAN mode.
+	CNIOverlayVXLAN CNIOverlay = "VXLAN"
+	// CNIOverlayGeneve encapsulates pod traffic in Geneve, e.g. Cilium or OVN-Kubernetes.
+	CNIOverlayGeneve CNIOverlay = "Geneve"
+	// CNIOverlayIPIP encapsulates pod traffic in IP-in-IP, e.g. Calico in IPIP mode.
+	CNIOverlayIPIP CNIOverlay = "IPIP"
+	// CNIOverlayWireGuard encrypts pod traffic with WireGuard.
+	CNIOverlayWireGuard CNIOverlay = "WireGuard"
+)
+
+// InstanceHARemediation selects the failures reported by Masakari which
+// mark machines as failed.
+type InstanceHARemediation string
+
+const (
+	// InstanceHARemediationRecoveryFailed marks machines as failed when
+	// Masakari could not recover their instance.
+	InstanceHARemediationRecoveryFailed InstanceHARemediation = "RecoveryFailed"
+	// InstanceHARemediationAlways marks machines as failed on every failure
+	// of their instance reported by Masakari.
+	InstanceHARemediationAlways InstanceHARemediation = "Always"
+	// InstanceHARemediationNever leaves the recovery of instances to Masakari.
+	InstanceHARemediationNever InstanceHARemediation = "Never"
+)
+
+// InstanceHA configures instance high availability with Masakari.
+type InstanceHA struct {
+	// Segment is the name of the Masakari failover segment of the compute
+	// hosts the instances of the cluster run on.
+	// +kubebuilder:validation:MinLength=1
+	Segment string `json:"segment"`
+
+	// Remediation selects the instance failures reported by Masakari which
+	// mark the machine of the instance as failed, so that it is remediated
+	// by a MachineHealthCheck without waiting for its node to become
+	// NotReady.
+	// +kubebuilder:validation:Enum=RecoveryFailed;Always;Never
+	// +kubebuilder:default=RecoveryFailed
+	// +optional
+	Remediation InstanceHARemediation `json:"remediation,omitempty"`
+}
+
+// InstanceHAStatus contains the state of instance high availability.
+type InstanceHAStatus struct {
+	// SegmentID is the ID of the Masakari failover segment.
+	SegmentID string `json:"segmentID"`
+
+	// LastNotificationTime is the generation time of the latest Masakari
+	// notification processed for the cluster.
+	// +optional
+	LastNotificationTime *metav1.Time `json:"lastNotificationTime,omitempty"`
+}
+
+// ServiceNodePort is a node port allocated to a Service of the workload
+// cluster.
+type ServiceNodePort struct {
+	// Port is the node port.
+	Port int `json:"port"`
+
+	// Protocol is the protocol of the port, i.e. tcp, udp or sctp.
+	Protocol string `json:"protocol"`
+}
+
+// HostMaintenance configures how machines on compute hosts under
+// maintenance are handled.
+type HostMaintenance struct {
+	// DisableCordon only sets the OpenStackHostMaintenance condition of the
+	// nodes of affected machines, without marking them unschedulable.
+	// +optional
+	DisableCordon bool `json:"disableCordon,omitempty"`
+
+	// Drain evicts the pods of the cordoned nodes of affected machines,
+	// respecting their PodDisruptionBudgets, and sets the
+	// OpenStackHostMaintenanceDrained condition of the nodes once they are
+	// drained. Nodes of machines with pre-drain delete hooks are only drained
+	// once the hooks are removed. Cannot be set together with disableCordon.
+	// +optional
+	Drain bool `json:"drain,omitempty"`
+}
+
+// InstanceResourceIDs are the IDs of the OpenStack resources used by an
+// instance.
+type InstanceResourceIDs struct {
+	// ServerID is the ID of the server.
+	ServerID string `json:"serverID"`
+
+	// PortIDs are the IDs of the ports attached to the server.
+	// +optional
+	PortIDs []string `json:"portIDs,omitempty"`
+
+	// TrunkIDs are the IDs of the trunks whose parent port is attached to
+	// the server.
+	// +optional
+	TrunkIDs []string `json:"trunkIDs,omitempty"`
+
+	// VolumeIDs are the IDs of the volumes attached to the server, including
+	// its root volume.
+	// +optional
+	VolumeIDs []string `json:"volumeIDs,omitempty"`
+
+	// FloatingIPIDs are the IDs of the floating IPs associated with the
+	// ports of the server.
+	// +optional
+	FloatingIPIDs []string `json:"floatingIPIDs,omitempty"`
+}
+
+// ObjectStorage configures the Swift container of a cluster.
+type ObjectStorage struct {
+	// AuditBootstrapData keeps a copy of the bootstrap data of every machine
+	// of the cluster in the container, also after the machine is deleted.
+	// +optional
+	AuditBootstrapData bool `json:"auditBootstrapData,omitempty"`
+
+	// StoreConsoleLogs stores the console log of machines whose instance
+	// failed in the container.
+	// +optional
+	StoreConsoleLogs bool `json:"storeConsoleLogs,omitempty"`
+}
+
+// ObjectStorageStatus represents basic information about the Swift container
+// of a cluster.
+type ObjectStorageStatus struct {
+	ContainerName string `json:"containerName"`
+}
+
+// MachineDNS configures the Designate records of the machines of a cluster.
+// Every machine gets A and AAAA records named <machine name>.<zone> for the
+// addresses of its management port.
+type MachineDNS struct {
+	// Zone is the name of the existing Designate zone the records are created
+	// in, with a trailing dot, e.g. nodes.example.com.
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)+$`
+	Zone string `json:"zone"`
+
+	// TTL is the time to live of the records in seconds. It defaults to the
+	// TTL of the zone.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL int `json:"ttl,omitempty"`
+}
+
+// DryRunPlan is the set of changes to OpenStack resources computed in dry-run mode.
+type DryRunPlan struct {
+	// GeneratedAt is the time the plan was computed.
+	GeneratedAt metav1.Time `json:"generatedAt"`
+
+	// Changes are the changes the controller would apply, in order.
+	// +optional
+	Changes []ResourceChange `json:"changes,omitempty"`
+
+	// Error is set if computing the plan failed, in which case the plan is
+	// incomplete.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ResourceChange is a change to an OpenStack resource.
+type ResourceChange struct {
+	// Action is either Create, Update or Delete.
+	Action string `json:"action"`
+
+	// Kind is the kind of the OpenStack resource, e.g. network or security_group_rule.
+	Kind string `json:"kind"`
+
+	// Name is the name of the resource, if it has one.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ID is the ID of the resource. Resources which would be created have a
+	// placeholder ID starting with "dry-run-".
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Request is the method and path of the OpenStack API request which would
+	// apply the change, e.g. "POST /v2.0/networks".
+	Request string `json:"request"`
+}
+
+// MachineSpecWithDefaults returns a copy of the spec of a machine of the
+// cluster with the machine defaults of the cluster applied. The spec itself
+// is returned if the cluster has no machine defaults.
+func (spec *OpenStackClusterSpec) MachineSpecWithDefaults(machineSpec *OpenStackMachineSpec) *OpenStackMachineSpec {
+	defaults := spec.MachineDefaults
+	if defaults == nil {
+		return machineSpec
+	}
+
+	out := machineSpec.DeepCopy()
+	if out.SSHKeyName == "" {
+		out.SSHKeyName = defaults.SSHKeyName
+	}
+	if len(defaults.ServerMetadata) > 0 {
+		metadata := make(map[string]string, len(defaults.ServerMetadata)+len(out.ServerMetadata))
+		for k, v := range defaults.ServerMetadata {
+			metadata[k] = v
+		}
+		for k, v := range out.ServerMetadata {
+			metadata[k] = v
+		}
+		out.ServerMetadata = metadata
+	}
+	if out.ServerGroupID == "" {
+		out.ServerGroupID = defaults.ServerGroupID
+	}
+	if len(out.FallbackFailureDomains) == 0 {
+		out.FallbackFailureDomains = defaults.FallbackFailureDomains
+	}
+	if out.IdentityRef == nil && defaults.IdentityRef != nil {
+		out.IdentityRef = defaults.IdentityRef.DeepCopy()
+		out.CloudName = defaults.CloudName
+	}
+	return out
+}
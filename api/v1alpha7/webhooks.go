@@ -0,0 +1,304 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha7
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func aggregateObjErrors(gk schema.GroupKind, name string, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		gk,
+		name,
+		allErrs,
+	)
+}
+
+// defaultOpenStackMachineSpec sets the fields of an OpenStackMachineSpec which
+// are otherwise resolved by the controller, so that the spec shows what is
+// created.
+func defaultOpenStackMachineSpec(spec *OpenStackMachineSpec) {
+	if spec.IdentityRef != nil && spec.IdentityRef.Kind == "" {
+		spec.IdentityRef.Kind = defaultIdentityRefKind
+	}
+
+	// Without networks and ports, a single port on the cluster network is
+	// created
+	if len(spec.Networks) == 0 && len(spec.Ports) == 0 {
+		spec.Ports = []PortOpts{{}}
+	}
+
+	// Ports inherit the trunk setting of the machine
+	for i := range spec.Ports {
+		if spec.Ports[i].Trunk == nil {
+			trunk := spec.Trunk
+			spec.Ports[i].Trunk = &trunk
+		}
+	}
+}
+
+// validateOpenStackMachineSpec validates the combinations of fields of an
+// OpenStackMachineSpec which OpenStack would reject or ignore.
+func validateOpenStackMachineSpec(spec *OpenStackMachineSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Image == "" && spec.ImageUUID == "" {
+		allErrs = append(allErrs, field.Required(path.Child("image"), "either image or imageUUID must be set"))
+	}
+
+	if rootVolume := spec.RootVolume; rootVolume != nil {
+		rootVolumePath := path.Child("rootVolume")
+		if rootVolume.Size < 0 {
+			allErrs = append(allErrs, field.Invalid(rootVolumePath.Child("diskSize"), rootVolume.Size, "must not be negative"))
+		}
+		if rootVolume.Size == 0 && rootVolume.AvailabilityZone != "" {
+			allErrs = append(allErrs, field.Forbidden(rootVolumePath.Child("availabilityZone"), "requires diskSize, without it the server boots from its image and no root volume is created"))
+		}
+		if rootVolume.Size == 0 && rootVolume.VolumeType != "" {
+			allErrs = append(allErrs, field.Forbidden(rootVolumePath.Child("volumeType"), "requires diskSize, without it the server boots from its image and no root volume is created"))
+		}
+	}
+
+	allErrs = append(allErrs, validateSecurityGroupFilters(spec.SecurityGroups, path.Child("securityGroups"))...)
+
+	if spec.FloatingIPPoolRef != nil {
+		if spec.FloatingIP != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("floatingIPPoolRef"), "cannot be set together with floatingIP"))
+		}
+		allErrs = append(allErrs, validateIPAMPoolRef(spec.FloatingIPPoolRef, path.Child("floatingIPPoolRef"))...)
+	}
+
+	if spec.NodeLabels != nil {
+		for i, key := range spec.NodeLabels.Metadata {
+			keyPath := path.Child("nodeLabels", "metadata").Index(i)
+			for _, msg := range validation.IsQualifiedName(key) {
+				allErrs = append(allErrs, field.Invalid(keyPath, key, msg))
+			}
+			value, ok := spec.ServerMetadata[key]
+			if !ok {
+				allErrs = append(allErrs, field.Invalid(keyPath, key, "must be a key of serverMetadata"))
+				continue
+			}
+			for _, msg := range validation.IsValidLabelValue(value) {
+				allErrs = append(allErrs, field.Invalid(path.Child("serverMetadata").Key(key), value, msg))
+			}
+		}
+	}
+
+	nameSuffixes := map[string]bool{}
+	portIDs := map[string]bool{}
+	for i := range spec.Ports {
+		port := &spec.Ports[i]
+		portPath := path.Child("ports").Index(i)
+
+		if port.ID != "" {
+			if portIDs[port.ID] {
+				allErrs = append(allErrs, field.Duplicate(portPath.Child("id"), port.ID))
+			}
+			portIDs[port.ID] = true
+			// The existing port is used as it is
+			if !reflect.DeepEqual(*port, PortOpts{ID: port.ID}) {
+				allErrs = append(allErrs, field.Forbidden(portPath, "no other options can be set for an existing port given by id"))
+			}
+			continue
+		}
+
+		allErrs = append(allErrs, validateSecurityGroupFilters(port.SecurityGroupFilters, portPath.Child("securityGroupFilters"))...)
+
+		if port.NameSuffix != "" {
+			if nameSuffixes[port.NameSuffix] {
+				allErrs = append(allErrs, field.Duplicate(portPath.Child("nameSuffix"), port.NameSuffix))
+			}
+			nameSuffixes[port.NameSuffix] = true
+		}
+
+		trunk := spec.Trunk
+		if port.Trunk != nil {
+			trunk = *port.Trunk
+		}
+		if trunk && port.VNICType != "" && port.VNICType != "normal" {
+			allErrs = append(allErrs, field.Forbidden(portPath.Child("vnicType"), "must be normal for a trunk port, set trunk to false to use another vNIC type"))
+		}
+
+		extraDHCPOpts := map[ExtraDHCPOpt]bool{}
+		for j, opt := range port.ExtraDHCPOpts {
+			// Options only differing in their value would override each other
+			key := ExtraDHCPOpt{Name: opt.Name, IPVersion: opt.IPVersion}
+			if extraDHCPOpts[key] {
+				allErrs = append(allErrs, field.Duplicate(portPath.Child("extraDhcpOpts").Index(j).Child("name"), opt.Name))
+			}
+			extraDHCPOpts[key] = true
+		}
+
+		if port.DisablePortSecurity != nil && *port.DisablePortSecurity {
+			if len(port.SecurityGroupFilters) > 0 {
+				allErrs = append(allErrs, field.Forbidden(portPath.Child("securityGroupFilters"), "cannot be applied to a port with disablePortSecurity"))
+			}
+			if len(port.AllowedAddressPairs) > 0 {
+				allErrs = append(allErrs, field.Forbidden(portPath.Child("allowedAddressPairs"), "cannot be applied to a port with disablePortSecurity"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateIPAMPoolRef validates a reference to the pool of an IPAddressClaim.
+// The IPAddress allocated from the pool requires its API group.
+func validateIPAMPoolRef(poolRef *corev1.TypedLocalObjectReference, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if poolRef.APIGroup == nil || *poolRef.APIGroup == "" {
+		allErrs = append(allErrs, field.Required(path.Child("apiGroup"), "must be set"))
+	}
+	if poolRef.Kind == "" {
+		allErrs = append(allErrs, field.Required(path.Child("kind"), "must be set"))
+	}
+	if poolRef.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("name"), "must be set"))
+	}
+
+	return allErrs
+}
+
+// validateSecurityGroupFilters rejects empty security group filters, which
+// would select every security group of the project.
+func validateSecurityGroupFilters(filters []SecurityGroupFilter, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i := range filters {
+		if filters[i].IsEmpty() {
+			allErrs = append(allErrs, field.Required(path.Index(i), "either id, name, description, projectId or tags must be set"))
+		}
+	}
+	return allErrs
+}
+
+// withoutHotPluggablePorts returns the ports which cannot be attached to or
+// detached from a running instance, so that only changes to them are
+// rejected.
+func withoutHotPluggablePorts(networks []NetworkParam, ports []PortOpts) []PortOpts {
+	var fixed []PortOpts
+	for i := range ports {
+		if !IsHotPluggablePort(networks, ports, i) {
+			fixed = append(fixed, ports[i])
+		}
+	}
+	return fixed
+}
+
+// withoutSecurityGroupFilters returns a copy of the ports without their
+// security group filters, which are applied to the ports of a running
+// instance.
+func withoutSecurityGroupFilters(ports []PortOpts) []PortOpts {
+	var filtered []PortOpts
+	for _, port := range ports {
+		port.SecurityGroupFilters = nil
+		filtered = append(filtered, port)
+	}
+	return filtered
+}
+
+// allowRootVolumeGrowth accepts an increased size of the root volume, which
+// is extended in place, by copying the old size to newRootVolume. Decreasing
+// the size is rejected, as volumes cannot be shrunk.
+func allowRootVolumeGrowth(path *field.Path, oldRootVolume, newRootVolume *RootVolume) field.ErrorList {
+	if oldRootVolume == nil || newRootVolume == nil || oldRootVolume.Size == 0 || newRootVolume.Size == 0 {
+		return nil
+	}
+	if newRootVolume.Size < oldRootVolume.Size {
+		return field.ErrorList{field.Invalid(path.Child("diskSize"), newRootVolume.Size, fmt.Sprintf("cannot be decreased below %d, volumes cannot be shrunk", oldRootVolume.Size))}
+	}
+	newRootVolume.Size = oldRootVolume.Size
+	return nil
+}
+
+// immutableFieldErrors returns an error with the given reason for each field
+// which differs between the old and the new object, so that the error points
+// to the fields which were changed rather than to the whole spec.
+func immutableFieldErrors(path *field.Path, oldObj, newObj interface{}, reason string) field.ErrorList {
+	oldValue, err := runtime.DefaultUnstructuredConverter.ToUnstructured(oldObj)
+	if err != nil {
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+	newValue, err := runtime.DefaultUnstructuredConverter.ToUnstructured(newObj)
+	if err != nil {
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+
+	var allErrs field.ErrorList
+	for _, changed := range changedFields(path, oldValue, newValue) {
+		allErrs = append(allErrs, field.Forbidden(changed, reason))
+	}
+	return allErrs
+}
+
+// changedFields returns the paths of the values which differ between old and
+// new, which are unstructured objects. Objects are compared field by field
+// and lists of the same length item by item.
+func changedFields(path *field.Path, oldValue, newValue interface{}) []*field.Path {
+	if reflect.DeepEqual(oldValue, newValue) {
+		return nil
+	}
+
+	switch oldTyped := oldValue.(type) {
+	case map[string]interface{}:
+		newTyped, ok := newValue.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(oldTyped)+len(newTyped))
+		for key := range oldTyped {
+			keys = append(keys, key)
+		}
+		for key := range newTyped {
+			if _, ok := oldTyped[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		var changed []*field.Path
+		for _, key := range keys {
+			changed = append(changed, changedFields(path.Child(key), oldTyped[key], newTyped[key])...)
+		}
+		return changed
+	case []interface{}:
+		newTyped, ok := newValue.([]interface{})
+		if !ok || len(oldTyped) != len(newTyped) {
+			break
+		}
+		var changed []*field.Path
+		for i := range oldTyped {
+			changed = append(changed, changedFields(path.Index(i), oldTyped[i], newTyped[i])...)
+		}
+		return changed
+	}
+	return []*field.Path{path}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/addons"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/hash"
+)
+
+// reconcileAddons applies the cloud.conf rendered for the cluster and the
+// enabled addons to the workload cluster, once its control plane is
+// initialized. Addons which are disabled later are not removed from the
+// workload cluster.
+func (r *OpenStackClusterReconciler) reconcileAddons(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	addonsSpec := openStackCluster.Spec.Addons
+	if addonsSpec == nil || (addonsSpec.CloudControllerManager == nil && addonsSpec.CinderCSI == nil) {
+		conditions.Delete(openStackCluster, infrav1.AddonsReadyCondition)
+		return nil
+	}
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		conditions.MarkFalse(openStackCluster, infrav1.AddonsReadyCondition, infrav1.WaitingForControlPlaneReason, clusterv1.ConditionSeverityInfo, "")
+		return nil
+	}
+
+	scope.Logger.Info("Reconciling addons")
+
+	restConfig, err := remote.RESTConfig(ctx, "openstackcluster-controller", r.Client, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.AddonsReadyCondition, infrav1.AddonsApplyFailedReason, clusterv1.ConditionSeverityWarning, "Connecting to workload cluster failed: %v", err)
+		return errors.Wrap(err, "failed to get REST config of workload cluster")
+	}
+	remoteClient, err := client.New(restConfig, client.Options{Scheme: r.Client.Scheme()})
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.AddonsReadyCondition, infrav1.AddonsApplyFailedReason, clusterv1.ConditionSeverityWarning, "Connecting to workload cluster failed: %v", err)
+		return errors.Wrap(err, "failed to create client for workload cluster")
+	}
+
+	objects, status, err := r.addonObjects(ctx, restConfig, cluster, openStackCluster)
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.AddonsReadyCondition, infrav1.AddonsApplyFailedReason, clusterv1.ConditionSeverityWarning, "Rendering addons failed: %v", err)
+		return errors.Wrap(err, "failed to render addons")
+	}
+
+	if err := addons.Apply(ctx, remoteClient, objects...); err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.AddonsReadyCondition, infrav1.AddonsApplyFailedReason, clusterv1.ConditionSeverityWarning, "Applying addons failed: %v", err)
+		return errors.Wrap(err, "failed to apply addons")
+	}
+
+	if previous := openStackCluster.Status.Addons; previous != nil && previous.CloudConfigChecksum != "" && previous.CloudConfigChecksum != status.CloudConfigChecksum {
+		record.Eventf(openStackCluster, "CloudConfigRotated", "Updated cloud.conf of the addons in the workload cluster")
+	}
+	openStackCluster.Status.Addons = status
+	conditions.MarkTrue(openStackCluster, infrav1.AddonsReadyCondition)
+	return nil
+}
+
+// addonObjects returns the cloud.conf Secret and the objects of the enabled
+// addons, and the versions of the addons. Addons without a version get the
+// release matching the Kubernetes version of the workload cluster.
+func (r *OpenStackClusterReconciler) addonObjects(ctx context.Context, restConfig *rest.Config, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) ([]client.Object, *infrav1.AddonsStatus, error) {
+	cloud, caCert, err := provider.GetCloudFromCluster(ctx, r.Client, openStackCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	secret, err := addons.CloudConfigSecret(cloud, caCert, openStackCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	checksum, err := hash.ComputeSpewHash(secret.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var defaultVersion string
+	getDefaultVersion := func() (string, error) {
+		if defaultVersion != "" {
+			return defaultVersion, nil
+		}
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+		if err != nil {
+			return "", err
+		}
+		serverVersion, err := discoveryClient.ServerVersion()
+		if err != nil {
+			return "", fmt.Errorf("failed to get Kubernetes version of workload cluster: %w", err)
+		}
+		defaultVersion, err = addons.Release(serverVersion.GitVersion)
+		return defaultVersion, err
+	}
+
+	objects := []client.Object{secret}
+	status := &infrav1.AddonsStatus{CloudConfigChecksum: fmt.Sprint(checksum)}
+	for _, component := range []struct {
+		component addons.Component
+		addon     *infrav1.Addon
+		version   *string
+	}{
+		{addons.CloudControllerManager, openStackCluster.Spec.Addons.CloudControllerManager, &status.CloudControllerManagerVersion},
+		{addons.CinderCSI, openStackCluster.Spec.Addons.CinderCSI, &status.CinderCSIVersion},
+	} {
+		if component.addon == nil {
+			continue
+		}
+
+		data := addons.ManifestData{
+			ImageRepository:     component.addon.ImageRepository,
+			Version:             component.addon.Version,
+			ClusterName:         fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name),
+			CloudConfigChecksum: status.CloudConfigChecksum,
+		}
+		if data.ImageRepository == "" {
+			data.ImageRepository = addons.DefaultImageRepository
+		}
+		if data.Version == "" {
+			if data.Version, err = getDefaultVersion(); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		componentObjects, err := addons.Objects(component.component, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, obj := range componentObjects {
+			objects = append(objects, obj)
+		}
+		*component.version = data.Version
+	}
+	return objects, status, nil
+}
+
+// identitySecretToOpenStackClusters returns a handler.MapFunc enqueueing the
+// OpenStackClusters with addons which use the identity Secret, so that a
+// rotation of the credentials is rolled out to the cloud.conf of the workload
+// cluster without waiting for the next resync.
+func (r *OpenStackClusterReconciler) identitySecretToOpenStackClusters(ctx context.Context) handler.MapFunc {
+	log := ctrl.LoggerFrom(ctx)
+	return func(o client.Object) []ctrl.Request {
+		// Secrets of an identity namespace may be used by clusters in any
+		// namespace, so all clusters are considered
+		clusters := &infrav1.OpenStackClusterList{}
+		if err := r.Client.List(ctx, clusters); err != nil {
+			log.Error(err, "Failed to list OpenStackClusters")
+			return nil
+		}
+
+		secretKey := client.ObjectKeyFromObject(o)
+		var requests []ctrl.Request
+		for i := range clusters.Items {
+			openStackCluster := &clusters.Items[i]
+			if openStackCluster.Spec.Addons == nil || openStackCluster.Spec.IdentityRef == nil {
+				continue
+			}
+			if provider.IdentitySecretKey(openStackCluster.Namespace, openStackCluster.Spec.IdentityRef) == secretKey {
+				requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(openStackCluster)})
+			}
+		}
+		return requests
+	}
+}
+
+// secretDataChanged returns true if the data of the Secret of the update
+// event changed.
+func secretDataChanged(oldObj, newObj client.Object) bool {
+	oldSecret, ok := oldObj.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+	newSecret, ok := newObj.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+	return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_identitySecretToOpenStackClusters(t *testing.T) {
+	g := NewWithT(t)
+
+	testScheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+
+	openStackCluster := func(namespace, name string, identityRef *infrav1.OpenStackIdentityReference, addons *infrav1.Addons) *infrav1.OpenStackCluster {
+		return &infrav1.OpenStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: infrav1.OpenStackClusterSpec{
+				IdentityRef: identityRef,
+				Addons:      addons,
+			},
+		}
+	}
+	addons := &infrav1.Addons{CloudControllerManager: &infrav1.Addon{}}
+	localRef := &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cloud-config"}
+	sharedRef := &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cloud-config", Namespace: "identities"}
+
+	r := &OpenStackClusterReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		openStackCluster("team-a", "local", localRef, addons),
+		openStackCluster("team-a", "shared", sharedRef, addons),
+		openStackCluster("team-b", "shared", sharedRef, addons),
+		openStackCluster("team-b", "without-addons", sharedRef, nil),
+		openStackCluster("team-b", "other-secret", &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "other"}, addons),
+	).Build()}
+	mapFunc := r.identitySecretToOpenStackClusters(context.TODO())
+
+	secret := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cloud-config"}}
+	}
+	g.Expect(mapFunc(secret("team-a"))).To(ConsistOf(
+		ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "team-a", Name: "local"}},
+	))
+	g.Expect(mapFunc(secret("identities"))).To(ConsistOf(
+		ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "team-a", Name: "shared"}},
+		ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "team-b", Name: "shared"}},
+	))
+	g.Expect(mapFunc(secret("team-b"))).To(BeEmpty())
+}
+
+func Test_secretDataChanged(t *testing.T) {
+	g := NewWithT(t)
+
+	oldSecret := &corev1.Secret{Data: map[string][]byte{"clouds.yaml": []byte("old")}}
+	relabelled := oldSecret.DeepCopy()
+	relabelled.Labels = map[string]string{"foo": "bar"}
+	rotated := oldSecret.DeepCopy()
+	rotated.Data["clouds.yaml"] = []byte("new")
+
+	g.Expect(secretDataChanged(oldSecret, relabelled)).To(BeFalse())
+	g.Expect(secretDataChanged(oldSecret, rotated)).To(BeTrue())
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// errBulkServersBuilding is returned while the servers created in bulk for
+// an OpenStackServer are not active yet.
+var errBulkServersBuilding = errors.New("waiting for servers created in bulk to become active")
+
+// bulkCreator serializes the bulk creation and the claiming of servers of
+// OpenStackServers with the same bulk tag, so that concurrent reconciles
+// neither create the same servers twice nor claim the same server.
+type bulkCreator struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+	// creating holds the OpenStackServers whose instance is created on its
+	// own, which must not be counted for bulk creation.
+	creating map[types.NamespacedName]struct{}
+}
+
+func (b *bulkCreator) lock(tag string) func() {
+	b.mu.Lock()
+	if b.locks == nil {
+		b.locks = map[string]*sync.Mutex{}
+	}
+	lock, ok := b.locks[tag]
+	if !ok {
+		lock = &sync.Mutex{}
+		b.locks[tag] = lock
+	}
+	b.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+func (b *bulkCreator) setCreating(name types.NamespacedName, creating bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.creating == nil {
+		b.creating = map[types.NamespacedName]struct{}{}
+	}
+	if creating {
+		b.creating[name] = struct{}{}
+	} else {
+		delete(b.creating, name)
+	}
+}
+
+func (b *bulkCreator) isCreating(name types.NamespacedName) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.creating[name]
+	return ok
+}
+
+// bulkOwner returns the owner of the servers created in bulk for the given
+// OpenStackServer. Servers are only shared within a namespace and cluster.
+func bulkOwner(openStackServer *infrav1.OpenStackServer) string {
+	return openStackServer.Namespace + "/" + openStackServer.Labels[clusterv1.ClusterLabelName]
+}
+
+// getOrCreateInBulk claims a server created in bulk for the OpenStackServer.
+// If there is none, and at least BulkCreateMinServers OpenStackServers wait
+// for identical instances, it creates servers for all of them with a single
+// request. It returns false if the instance of the OpenStackServer has to be
+// created on its own, in which case the OpenStackServer is not counted by
+// other reconciles until createdOnItsOwn is called.
+func (r *OpenStackServerReconciler) getOrCreateInBulk(ctx context.Context, scope *scope.Scope, computeService *compute.Service, openStackServer *infrav1.OpenStackServer, instanceSpec *compute.InstanceSpec) (*compute.InstanceStatus, bool, error) {
+	owner := bulkOwner(openStackServer)
+	tag, err := compute.BulkTag(owner, instanceSpec)
+	if err != nil {
+		return nil, true, err
+	}
+
+	unlock := r.bulk.lock(tag)
+	defer unlock()
+
+	instanceStatus, building, err := computeService.ClaimBulkInstance(openStackServer, instanceSpec, tag)
+	if err != nil {
+		return nil, true, err
+	}
+	if instanceStatus != nil {
+		scope.Logger.Info("Claimed server created in bulk", "instanceID", instanceStatus.ID())
+		pending, err := r.pendingBulkServers(ctx, openStackServer, tag)
+		if err != nil {
+			return nil, true, err
+		}
+		// The other servers were created for OpenStackServers which were deleted in the meantime
+		if len(pending) == 1 && pending[0] == client.ObjectKeyFromObject(openStackServer) {
+			if err := computeService.DeleteBulkInstances(openStackServer, tag); err != nil {
+				return nil, true, err
+			}
+		}
+		return instanceStatus, true, nil
+	}
+	if building {
+		return nil, true, errBulkServersBuilding
+	}
+
+	pending, err := r.pendingBulkServers(ctx, openStackServer, tag)
+	if err != nil {
+		return nil, true, err
+	}
+	if len(pending) < r.BulkCreateMinServers {
+		r.bulk.setCreating(client.ObjectKeyFromObject(openStackServer), true)
+		return nil, false, nil
+	}
+
+	scope.Logger.Info("Creating servers in bulk", "count", len(pending), "tag", tag)
+	if err := computeService.CreateInstancesInBulk(openStackServer, instanceSpec, tag, len(pending)); err != nil {
+		return nil, true, errors.Wrap(err, "error creating OpenStack instances in bulk")
+	}
+	return nil, true, errBulkServersBuilding
+}
+
+// createdOnItsOwn is called once the instance of an OpenStackServer for
+// which getOrCreateInBulk returned false was created.
+func (r *OpenStackServerReconciler) createdOnItsOwn(openStackServer *infrav1.OpenStackServer) {
+	r.bulk.setCreating(client.ObjectKeyFromObject(openStackServer), false)
+}
+
+// pendingBulkServers returns the OpenStackServers sharing the owner of the
+// given OpenStackServer which wait for an instance that can be created in
+// bulk with the given tag.
+func (r *OpenStackServerReconciler) pendingBulkServers(ctx context.Context, openStackServer *infrav1.OpenStackServer, tag string) ([]types.NamespacedName, error) {
+	var pending []types.NamespacedName
+
+	owner := bulkOwner(openStackServer)
+	openStackServerList := &infrav1.OpenStackServerList{}
+	if err := r.Client.List(ctx, openStackServerList, client.InNamespace(openStackServer.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "failed to list OpenStackServers")
+	}
+	for i := range openStackServerList.Items {
+		server := &openStackServerList.Items[i]
+		if bulkOwner(server) != owner || !server.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if server.Spec.InstanceID != nil || server.Status.InstanceID != nil {
+			continue
+		}
+		name := client.ObjectKeyFromObject(server)
+		if r.bulk.isCreating(name) {
+			continue
+		}
+		instanceSpec := serverToInstanceSpec(server, "")
+		if !compute.CanCreateInBulk(instanceSpec) {
+			continue
+		}
+		if serverTag, err := compute.BulkTag(owner, instanceSpec); err != nil || serverTag != tag {
+			continue
+		}
+		pending = append(pending, name)
+	}
+	return pending, nil
+}
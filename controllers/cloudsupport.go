@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// waitForCloudSupportDuration is the time after which the cloud is checked
+// again when it lacked services or extensions required by the cluster.
+const waitForCloudSupportDuration = 5 * time.Minute
+
+// cloudFeatures are the services and API extensions of the cloud which are
+// required by an OpenStackCluster.
+type cloudFeatures struct {
+	// networkExtensions are the aliases of the required Neutron extensions.
+	networkExtensions []string
+	// availabilityZones is whether Nova must list its availability zones.
+	availabilityZones bool
+	// loadBalancer is whether Octavia must be in the service catalog.
+	loadBalancer bool
+}
+
+// unsupportedCloudError is returned when OpenStack resources are not created
+// because the cloud lacks services or extensions required by the cluster.
+type unsupportedCloudError struct {
+	missing []string
+}
+
+func (e *unsupportedCloudError) Error() string {
+	return "the cloud does not support " + strings.Join(e.missing, ", ")
+}
+
+// requiredCloudFeatures returns the cloud features required by the spec of
+// the OpenStackCluster.
+func requiredCloudFeatures(openStackCluster *infrav1.OpenStackCluster) cloudFeatures {
+	spec := &openStackCluster.Spec
+	bastion := spec.Bastion != nil && spec.Bastion.Enabled
+	var required cloudFeatures
+
+	if spec.ManagedSecurityGroups != nil {
+		required.networkExtensions = append(required.networkExtensions, "security-group")
+	}
+	// Routers and floating IPs are both provided by the router extension
+	if (spec.NodeCIDR != "" && spec.ExternalNetworkID != "") || !spec.DisableAPIServerFloatingIP || bastion {
+		required.networkExtensions = append(required.networkExtensions, "router")
+	}
+	if spec.RouterFirewall != nil {
+		required.networkExtensions = append(required.networkExtensions, "fwaas_v2")
+	}
+	if len(spec.RouterRoutes) > 0 {
+		required.networkExtensions = append(required.networkExtensions, "extraroute")
+	}
+	if len(spec.Tags) > 0 || (bastion && len(spec.Bastion.Instance.Tags) > 0) {
+		required.networkExtensions = append(required.networkExtensions, "standard-attr-tag")
+	}
+	if bastion && hasTrunkPort(&spec.Bastion.Instance) {
+		required.networkExtensions = append(required.networkExtensions, "trunk")
+	}
+
+	// Without mappings the failure domains are the availability zones of Nova
+	required.availabilityZones = spec.FailureDomains == nil || len(spec.FailureDomains.Mappings) == 0
+	required.loadBalancer = spec.APIServerLoadBalancer.Enabled
+
+	return required
+}
+
+// hasTrunkPort returns whether any port of the machine spec is a trunk port.
+func hasTrunkPort(machineSpec *infrav1.OpenStackMachineSpec) bool {
+	if machineSpec.Trunk {
+		return true
+	}
+	for _, port := range machineSpec.Ports {
+		if port.Trunk != nil && *port.Trunk {
+			return true
+		}
+	}
+	return false
+}
+
+// getMissingCloudFeatures returns the cloud features required by the
+// OpenStackCluster which the cloud lacks, each described by a message.
+func getMissingCloudFeatures(scope *scope.Scope, computeService *compute.Service, openStackCluster *infrav1.OpenStackCluster) ([]string, error) {
+	required := requiredCloudFeatures(openStackCluster)
+	var missing []string
+
+	if len(required.networkExtensions) > 0 {
+		networkingService, err := networking.NewService(scope)
+		if err != nil {
+			return nil, err
+		}
+		extensions, err := networkingService.GetMissingExtensions(required.networkExtensions)
+		if err != nil {
+			return nil, err
+		}
+		for _, alias := range extensions {
+			missing = append(missing, "Neutron extension "+alias)
+		}
+	}
+
+	if required.availabilityZones {
+		supported, err := computeService.SupportsAvailabilityZones()
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			missing = append(missing, "Nova availability zones")
+		}
+	}
+
+	if required.loadBalancer {
+		if _, err := loadbalancer.NewService(scope); err != nil {
+			if !capoerrors.IsEndpointNotFound(err) {
+				return nil, err
+			}
+			missing = append(missing, "Octavia load balancer service")
+		}
+	}
+
+	return missing, nil
+}
+
+// setUnsupportedCloudCondition adds UnsupportedCloudCondition listing the
+// missing cloud features to obj, or removes it if none is missing.
+func setUnsupportedCloudCondition(obj conditions.Setter, missing []string) {
+	if len(missing) == 0 {
+		conditions.Delete(obj, infrav1.UnsupportedCloudCondition)
+		return
+	}
+	conditions.Set(obj, &clusterv1.Condition{
+		Type:    infrav1.UnsupportedCloudCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.MissingCloudFeaturesReason,
+		Message: (&unsupportedCloudError{missing: missing}).Error(),
+	})
+}
+
+// reconcileCloudSupport checks whether the cloud has the services and
+// extensions required by the OpenStackCluster, so that a cloud lacking them
+// is reported before any OpenStack resource is created rather than by a
+// failing request. If it does not, an unsupportedCloudError is returned.
+// Errors probing the cloud only skip the check.
+func reconcileCloudSupport(scope *scope.Scope, computeService *compute.Service, openStackCluster *infrav1.OpenStackCluster) error {
+	missing, err := getMissingCloudFeatures(scope, computeService, openStackCluster)
+	if err != nil {
+		scope.Logger.Info("Skipping cloud support check", "error", err.Error())
+		return nil
+	}
+
+	setUnsupportedCloudCondition(openStackCluster, missing)
+	if len(missing) == 0 {
+		return nil
+	}
+	return &unsupportedCloudError{missing: missing}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_requiredCloudFeatures(t *testing.T) {
+	tests := []struct {
+		name string
+		spec infrav1.OpenStackClusterSpec
+		want cloudFeatures
+	}{
+		{
+			name: "Cluster without floating IPs on mapped failure domains",
+			spec: infrav1.OpenStackClusterSpec{
+				DisableAPIServerFloatingIP: true,
+				FailureDomains: &infrav1.FailureDomainsConfig{
+					Mappings: []infrav1.FailureDomainMapping{{Name: "fd-1"}},
+				},
+			},
+		},
+		{
+			name: "Cluster with managed security groups, tags and a load balancer",
+			spec: infrav1.OpenStackClusterSpec{
+				NodeCIDR:              "10.6.0.0/24",
+				ExternalNetworkID:     "external-network-id",
+				ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{},
+				Tags:                  []string{"tag"},
+				APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Enabled: true},
+			},
+			want: cloudFeatures{
+				networkExtensions: []string{"security-group", "router", "standard-attr-tag"},
+				availabilityZones: true,
+				loadBalancer:      true,
+			},
+		},
+		{
+			name: "Cluster with a router firewall and routes",
+			spec: infrav1.OpenStackClusterSpec{
+				NodeCIDR:                   "10.6.0.0/24",
+				ExternalNetworkID:          "external-network-id",
+				DisableAPIServerFloatingIP: true,
+				RouterFirewall: &infrav1.RouterFirewall{
+					IngressPolicy: &infrav1.FirewallPolicyFilter{Name: "cluster-ingress"},
+				},
+				RouterRoutes: []infrav1.RouterRoute{
+					{Destination: "192.168.0.0/16", NextHop: "10.6.0.10"},
+				},
+			},
+			want: cloudFeatures{
+				networkExtensions: []string{"router", "fwaas_v2", "extraroute"},
+				availabilityZones: true,
+			},
+		},
+		{
+			name: "Bastion with a trunk port",
+			spec: infrav1.OpenStackClusterSpec{
+				DisableAPIServerFloatingIP: true,
+				Bastion: &infrav1.Bastion{
+					Enabled: true,
+					Instance: infrav1.OpenStackMachineSpec{
+						Ports: []infrav1.PortOpts{{}, {Trunk: pointer.Bool(true)}},
+					},
+				},
+			},
+			want: cloudFeatures{
+				networkExtensions: []string{"router", "trunk"},
+				availabilityZones: true,
+			},
+		},
+		{
+			name: "Disabled bastion is ignored",
+			spec: infrav1.OpenStackClusterSpec{
+				DisableAPIServerFloatingIP: true,
+				Bastion: &infrav1.Bastion{
+					Instance: infrav1.OpenStackMachineSpec{Trunk: true, Tags: []string{"tag"}},
+				},
+			},
+			want: cloudFeatures{
+				availabilityZones: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := requiredCloudFeatures(&infrav1.OpenStackCluster{Spec: tt.spec})
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_setUnsupportedCloudCondition(t *testing.T) {
+	g := NewWithT(t)
+	openStackCluster := &infrav1.OpenStackCluster{}
+
+	setUnsupportedCloudCondition(openStackCluster, []string{"Neutron extension trunk", "Octavia load balancer service"})
+	condition := conditions.Get(openStackCluster, infrav1.UnsupportedCloudCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Reason).To(Equal(infrav1.MissingCloudFeaturesReason))
+	g.Expect(condition.Message).To(Equal("the cloud does not support Neutron extension trunk, Octavia load balancer service"))
+
+	setUnsupportedCloudCondition(openStackCluster, nil)
+	g.Expect(conditions.Has(openStackCluster, infrav1.UnsupportedCloudCondition)).To(BeFalse())
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/clusternetwork"
+)
+
+// getClusterNetworkConflicts returns the problems with the network
+// configuration of the Cluster and the OpenStackCluster: pod and service
+// CIDRs overlapping the subnets of the nodes, and a cluster network whose MTU
+// is too small for the CNI overlay. The MTU is skipped if it cannot be read.
+func getClusterNetworkConflicts(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) []string {
+	problems := clusternetwork.ValidateCIDRs(clusternetwork.ClusterCIDRs(cluster), clusternetwork.NodeCIDRs(openStackCluster))
+
+	overlay := openStackCluster.Spec.CNIOverlay
+	if !clusternetwork.HasOverhead(overlay) || openStackCluster.Status.Network == nil || openStackCluster.Status.Network.ID == "" {
+		return problems
+	}
+	networkingService, err := networking.NewService(scope)
+	if err == nil {
+		var mtu int
+		mtu, err = networkingService.GetNetworkMTU(openStackCluster.Status.Network.ID)
+		if err == nil {
+			if problem := clusternetwork.ValidateMTU(overlay, mtu); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+	}
+	if err != nil {
+		scope.Logger.Info("Skipping MTU check of the cluster network", "error", err.Error())
+	}
+	return problems
+}
+
+// setClusterNetworkConflictCondition adds ClusterNetworkConflictCondition
+// listing the problems to obj, or removes it if there is none. It returns
+// true if the problems changed and are to be reported.
+func setClusterNetworkConflictCondition(obj conditions.Setter, problems []string) bool {
+	if len(problems) == 0 {
+		conditions.Delete(obj, infrav1.ClusterNetworkConflictCondition)
+		return false
+	}
+	message := strings.Join(problems, "; ")
+	if existing := conditions.Get(obj, infrav1.ClusterNetworkConflictCondition); existing != nil && existing.Message == message {
+		return false
+	}
+	conditions.Set(obj, &clusterv1.Condition{
+		Type:    infrav1.ClusterNetworkConflictCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.ClusterNetworkConflictReason,
+		Message: message,
+	})
+	return true
+}
+
+// reconcileClusterNetworkConflicts checks the network configuration of the
+// cluster on every reconcile, so that conflicts introduced by a Cluster
+// created after the OpenStackCluster, or by changes to either, are reported
+// even though the admission check missed them. The conflicts are reported in
+// a condition and an event, they do not stop the reconcile.
+func reconcileClusterNetworkConflicts(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) {
+	problems := getClusterNetworkConflicts(scope, cluster, openStackCluster)
+	if setClusterNetworkConflictCondition(openStackCluster, problems) {
+		record.Warnf(openStackCluster, "ClusterNetworkConflict", "Network configuration of the cluster conflicts: %s", strings.Join(problems, "; "))
+	}
+}
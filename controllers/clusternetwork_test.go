@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_reconcileClusterNetworkConflicts(t *testing.T) {
+	g := NewWithT(t)
+	scope := &scope.Scope{Logger: logr.Discard()}
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{NodeCIDR: "192.168.1.0/24"},
+	}
+
+	// The Cluster may have no pod CIDRs yet
+	cluster := &clusterv1.Cluster{}
+	reconcileClusterNetworkConflicts(scope, cluster, openStackCluster)
+	g.Expect(conditions.Has(openStackCluster, infrav1.ClusterNetworkConflictCondition)).To(BeFalse())
+
+	// Conflicts introduced by changes to the Cluster are reported
+	cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{
+		Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+	}
+	reconcileClusterNetworkConflicts(scope, cluster, openStackCluster)
+	condition := conditions.Get(openStackCluster, infrav1.ClusterNetworkConflictCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Reason).To(Equal(infrav1.ClusterNetworkConflictReason))
+	g.Expect(condition.Message).To(Equal("pod CIDR 192.168.0.0/16 overlaps nodeCidr 192.168.1.0/24"))
+
+	// The condition is removed once the conflict is resolved
+	cluster.Spec.ClusterNetwork.Pods.CIDRBlocks = []string{"172.16.0.0/16"}
+	reconcileClusterNetworkConflicts(scope, cluster, openStackCluster)
+	g.Expect(conditions.Has(openStackCluster, infrav1.ClusterNetworkConflictCondition)).To(BeFalse())
+}
+
+func Test_setClusterNetworkConflictCondition(t *testing.T) {
+	g := NewWithT(t)
+	openStackCluster := &infrav1.OpenStackCluster{}
+
+	g.Expect(setClusterNetworkConflictCondition(openStackCluster, []string{"conflict"})).To(BeTrue())
+	// Unchanged problems are not reported again
+	g.Expect(setClusterNetworkConflictCondition(openStackCluster, []string{"conflict"})).To(BeFalse())
+	g.Expect(setClusterNetworkConflictCondition(openStackCluster, []string{"conflict", "other"})).To(BeTrue())
+	g.Expect(conditions.Get(openStackCluster, infrav1.ClusterNetworkConflictCondition).Message).To(Equal("conflict; other"))
+
+	g.Expect(setClusterNetworkConflictCondition(openStackCluster, nil)).To(BeFalse())
+	g.Expect(conditions.Has(openStackCluster, infrav1.ClusterNetworkConflictCondition)).To(BeFalse())
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const defaultConsoleType = "novnc"
+
+// consoleSecretName returns the name of the Secret holding the console URL
+// of the machine.
+func consoleSecretName(openStackMachine *infrav1.OpenStackMachine) string {
+	return openStackMachine.Name + "-console"
+}
+
+// reconcileConsole publishes the URL of a remote console of the instance of
+// the machine in a Secret when it is requested with the ConsoleAnnotation,
+// so that operators can access the console without credentials of the
+// cloud. The annotation is removed once the request is handled. Failures
+// are reported as events and also remove the annotation, so that they do not
+// block the reconcile of the machine; the console can be requested again by
+// setting the annotation again.
+func (r *OpenStackMachineReconciler) reconcileConsole(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackMachine *infrav1.OpenStackMachine) {
+	consoleType, ok := openStackMachine.Annotations[infrav1.ConsoleAnnotation]
+	if !ok {
+		return
+	}
+	if consoleType == "" {
+		consoleType = defaultConsoleType
+	}
+
+	if !compute.IsSupportedConsoleType(consoleType) {
+		record.Warnf(openStackMachine, "FailedCreateConsole", "Unsupported console type %q, expected novnc, serial or spice-html5", consoleType)
+		delete(openStackMachine.Annotations, infrav1.ConsoleAnnotation)
+		return
+	}
+
+	// The console is created once the instance exists
+	if openStackMachine.Spec.InstanceID == nil {
+		scope.Logger.Info("Waiting for instance to create console", "type", consoleType)
+		return
+	}
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		record.Warnf(openStackMachine, "FailedCreateConsole", "Failed to create %s console: %v", consoleType, err)
+		delete(openStackMachine.Annotations, infrav1.ConsoleAnnotation)
+		return
+	}
+	url, err := computeService.GetConsoleURL(*openStackMachine.Spec.InstanceID, consoleType)
+	if err != nil {
+		record.Warnf(openStackMachine, "FailedCreateConsole", "Failed to create %s console: %v", consoleType, err)
+		delete(openStackMachine.Annotations, infrav1.ConsoleAnnotation)
+		return
+	}
+
+	want := consoleToSecret(cluster, openStackMachine, consoleType, url, time.Now())
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: want.Name, Namespace: want.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Labels = want.Labels
+		secret.Type = want.Type
+		secret.Data = want.Data
+		return controllerutil.SetControllerReference(openStackMachine, secret, r.Client.Scheme())
+	}); err != nil {
+		record.Warnf(openStackMachine, "FailedCreateConsole", "Failed to publish %s console URL in secret %s: %v", consoleType, want.Name, err)
+		delete(openStackMachine.Annotations, infrav1.ConsoleAnnotation)
+		return
+	}
+
+	scope.Logger.Info("Published console URL", "type", consoleType, "secret", want.Name)
+	record.Eventf(openStackMachine, "CreatedConsole", "Published %s console URL in secret %s", consoleType, want.Name)
+	delete(openStackMachine.Annotations, infrav1.ConsoleAnnotation)
+}
+
+// consoleToSecret returns the Secret holding the URL of a remote console of
+// the instance of the machine, created at now.
+func consoleToSecret(cluster *clusterv1.Cluster, openStackMachine *infrav1.OpenStackMachine, consoleType, url string, now time.Time) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      consoleSecretName(openStackMachine),
+			Namespace: openStackMachine.Namespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"type":      []byte(consoleType),
+			"url":       []byte(url),
+			"createdAt": []byte(now.UTC().Format(time.RFC3339)),
+		},
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_consoleToSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}}
+	openStackMachine := &infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine-0", Namespace: "test-namespace"}}
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	secret := consoleToSecret(cluster, openStackMachine, "serial", "ws://nova-serialproxy:6083/?token=abc", now)
+	g.Expect(secret.Name).To(Equal("machine-0-console"))
+	g.Expect(secret.Namespace).To(Equal("test-namespace"))
+	g.Expect(secret.Labels).To(Equal(map[string]string{clusterv1.ClusterLabelName: "test-cluster"}))
+	g.Expect(secret.Data).To(Equal(map[string][]byte{
+		"type":      []byte("serial"),
+		"url":       []byte("ws://nova-serialproxy:6083/?token=abc"),
+		"createdAt": []byte("2022-09-01T12:00:00Z"),
+	}))
+}
+
+func Test_reconcileConsole_withoutOpenStack(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"}}
+	s := &scope.Scope{Logger: logr.Discard()}
+	r := &OpenStackMachineReconciler{}
+
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		wantAnnotation bool
+	}{
+		{
+			name:           "no console requested",
+			annotations:    map[string]string{},
+			wantAnnotation: false,
+		},
+		{
+			name:           "unsupported console type is rejected",
+			annotations:    map[string]string{infrav1.ConsoleAnnotation: "rdp-html5"},
+			wantAnnotation: false,
+		},
+		{
+			name:           "waits for instance",
+			annotations:    map[string]string{infrav1.ConsoleAnnotation: "serial"},
+			wantAnnotation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackMachine := &infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine-0", Annotations: tt.annotations}}
+			r.reconcileConsole(context.TODO(), s, cluster, openStackMachine)
+			_, ok := openStackMachine.Annotations[infrav1.ConsoleAnnotation]
+			g.Expect(ok).To(Equal(tt.wantAnnotation))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// preTerminateHooks returns the pre-terminate hooks set on the objects, i.e.
+// their annotations with the CAPI pre-terminate hook prefix.
+func preTerminateHooks(objs ...metav1.Object) []string {
+	var hooks []string
+	for _, obj := range objs {
+		for annotation := range obj.GetAnnotations() {
+			if strings.HasPrefix(annotation, clusterv1.PreTerminateDeleteHookAnnotationPrefix) {
+				hooks = append(hooks, annotation)
+			}
+		}
+	}
+	sort.Strings(hooks)
+	return hooks
+}
+
+// reconcilePreTerminateHooks returns whether the deletion of the machine must
+// wait for pre-terminate hooks on the Machine or the OpenStackMachine. The
+// controllers which set them, e.g. backup agents or storage operators,
+// remove them once they have released their attachments to the instance.
+// While the deletion waits, the volumes and floating IPs which CAPO did not
+// create and which are still attached to the instance are reported on
+// InstanceReadyCondition.
+func reconcilePreTerminateHooks(scope *scope.Scope, computeService *compute.Service, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (bool, error) {
+	hooks := preTerminateHooks(machine, openStackMachine)
+	if len(hooks) == 0 {
+		return false, nil
+	}
+
+	attachments := &compute.ExternalAttachments{}
+	if openStackMachine.Spec.InstanceID != nil {
+		instanceStatus, err := computeService.GetInstanceStatus(*openStackMachine.Spec.InstanceID)
+		if err != nil {
+			return true, err
+		}
+		if instanceStatus != nil {
+			attachments, err = computeService.GetExternalAttachments(instanceStatus)
+			if err != nil {
+				return true, err
+			}
+		}
+	}
+
+	scope.Logger.Info("Waiting for pre-terminate hooks", "hooks", hooks, "volumes", attachments.VolumeIDs, "floatingIPs", attachments.FloatingIPs)
+	conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForPreTerminateHookReason, clusterv1.ConditionSeverityInfo, "%s", preTerminateHooksMessage(hooks, attachments))
+	return true, nil
+}
+
+// preTerminateHooksMessage describes the hooks the deletion waits for and the
+// attachments which are still to be released.
+func preTerminateHooksMessage(hooks []string, attachments *compute.ExternalAttachments) string {
+	message := fmt.Sprintf("Waiting for pre-terminate hooks %s", strings.Join(hooks, ", "))
+	if attachments.IsEmpty() {
+		return message
+	}
+	var attached []string
+	if len(attachments.VolumeIDs) > 0 {
+		attached = append(attached, fmt.Sprintf("volumes %s", strings.Join(attachments.VolumeIDs, ", ")))
+	}
+	if len(attachments.FloatingIPs) > 0 {
+		attached = append(attached, fmt.Sprintf("floating IPs %s", strings.Join(attachments.FloatingIPs, ", ")))
+	}
+	return fmt.Sprintf("%s; %s not created by CAPO still attached", message, strings.Join(attached, " and "))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_reconcilePreTerminateHooks(t *testing.T) {
+	s := &scope.Scope{Logger: logr.Discard()}
+	backupHook := clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/backup"
+	storageHook := clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/storage"
+
+	tests := []struct {
+		name                        string
+		machineAnnotations          map[string]string
+		openStackMachineAnnotations map[string]string
+		wantWaiting                 bool
+		wantMessage                 string
+	}{
+		{
+			name:               "no hooks",
+			machineAnnotations: map[string]string{clusterv1.PreDrainDeleteHookAnnotationPrefix + "/backup": ""},
+			wantWaiting:        false,
+		},
+		{
+			name:               "hook on the machine",
+			machineAnnotations: map[string]string{backupHook: ""},
+			wantWaiting:        true,
+			wantMessage:        "Waiting for pre-terminate hooks " + backupHook,
+		},
+		{
+			name:                        "hooks on the machine and the OpenStackMachine",
+			machineAnnotations:          map[string]string{storageHook: ""},
+			openStackMachineAnnotations: map[string]string{backupHook: ""},
+			wantWaiting:                 true,
+			wantMessage:                 "Waiting for pre-terminate hooks " + backupHook + ", " + storageHook,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Annotations: tt.machineAnnotations}}
+			openStackMachine := &infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{Annotations: tt.openStackMachineAnnotations}}
+
+			waiting, err := reconcilePreTerminateHooks(s, nil, machine, openStackMachine)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(waiting).To(Equal(tt.wantWaiting))
+			if !tt.wantWaiting {
+				g.Expect(conditions.Has(openStackMachine, infrav1.InstanceReadyCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(conditions.GetReason(openStackMachine, infrav1.InstanceReadyCondition)).To(Equal(infrav1.WaitingForPreTerminateHookReason))
+			g.Expect(conditions.GetMessage(openStackMachine, infrav1.InstanceReadyCondition)).To(Equal(tt.wantMessage))
+		})
+	}
+}
+
+func Test_preTerminateHooksMessage(t *testing.T) {
+	g := NewWithT(t)
+	hooks := []string{clusterv1.PreTerminateDeleteHookAnnotationPrefix + "/backup"}
+
+	g.Expect(preTerminateHooksMessage(hooks, &compute.ExternalAttachments{
+		VolumeIDs:   []string{"data-volume-id"},
+		FloatingIPs: []string{"192.0.2.10"},
+	})).To(Equal("Waiting for pre-terminate hooks pre-terminate.delete.hook.machine.cluster.x-k8s.io/backup; volumes data-volume-id and floating IPs 192.0.2.10 not created by CAPO still attached"))
+	g.Expect(preTerminateHooksMessage(hooks, &compute.ExternalAttachments{
+		VolumeIDs: []string{"data-volume-id"},
+	})).To(Equal("Waiting for pre-terminate hooks pre-terminate.delete.hook.machine.cluster.x-k8s.io/backup; volumes data-volume-id not created by CAPO still attached"))
+}
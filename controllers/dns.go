@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/dns"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// reconcileMachineDNS points the Designate records of the machine to the
+// fixed IPs of its management port.
+func reconcileMachineDNS(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, port *ports.Port, clusterName string) error {
+	dnsService, err := dns.NewService(scope)
+	if err != nil {
+		return err
+	}
+
+	addresses := make([]string, 0, len(port.FixedIPs))
+	for _, fixedIP := range port.FixedIPs {
+		addresses = append(addresses, fixedIP.IPAddress)
+	}
+	if err := dnsService.ReconcileMachineRecords(openStackMachine, openStackCluster.Spec.MachineDNS, clusterName, openStackMachine.Name, addresses); err != nil {
+		conditions.MarkFalse(openStackMachine, infrav1.DNSRecordsReadyCondition, infrav1.DNSRecordsCreateFailedReason, clusterv1.ConditionSeverityWarning, "Reconciling DNS records failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile DNS records")
+	}
+
+	conditions.MarkTrue(openStackMachine, infrav1.DNSRecordsReadyCondition)
+	return nil
+}
+
+// deleteMachineDNS deletes the Designate records of the machine, if the
+// cluster has machine DNS records.
+func deleteMachineDNS(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, clusterName string) error {
+	if openStackCluster.Spec.MachineDNS == nil {
+		return nil
+	}
+
+	dnsService, err := dns.NewService(scope)
+	if err != nil {
+		return err
+	}
+	if err := dnsService.DeleteMachineRecords(openStackMachine, openStackCluster.Spec.MachineDNS, clusterName, openStackMachine.Name); err != nil {
+		conditions.MarkFalse(openStackMachine, infrav1.DNSRecordsReadyCondition, infrav1.DNSRecordsDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting DNS records failed: %v", err)
+		return errors.Wrap(err, "failed to delete DNS records")
+	}
+	return nil
+}
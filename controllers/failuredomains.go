@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// waitForFailureDomainCapacityDuration is the time a worker machine waits
+// before trying all failure domains again once none had capacity for it.
+const waitForFailureDomainCapacityDuration = 5 * time.Minute
+
+// balancesFailureDomain returns whether the failure domain of the machine is
+// assigned by the controller.
+func balancesFailureDomain(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine) bool {
+	return openStackCluster.Spec.FailureDomains != nil && openStackCluster.Spec.FailureDomains.BalanceWorkers &&
+		len(openStackCluster.Status.FailureDomains) > 0 &&
+		!util.IsControlPlaneMachine(machine) && machine.Spec.FailureDomain == nil
+}
+
+// pickWorkerFailureDomain returns the failure domain with the fewest worker
+// machines of the cluster, skipping the failure domains in which no host had
+// capacity for the machine. Ties are broken by the name of the failure
+// domain, so that machines created together are spread round-robin. It
+// returns an empty string if no failure domain is left.
+func (r *OpenStackMachineReconciler) pickWorkerFailureDomain(ctx context.Context, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) (string, error) {
+	unschedulable := sets.NewString(openStackMachine.Status.UnschedulableFailureDomains...)
+	counts := map[string]int{}
+	for name := range openStackCluster.Status.FailureDomains {
+		if !unschedulable.Has(name) {
+			counts[name] = 0
+		}
+	}
+	if len(counts) == 0 {
+		return "", nil
+	}
+
+	// The Machine only reports the failure domain assigned to its
+	// OpenStackMachine once the instance is ready
+	openStackMachines := &infrav1.OpenStackMachineList{}
+	if err := r.Client.List(ctx, openStackMachines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return "", errors.Wrap(err, "failed to list OpenStackMachines")
+	}
+	assigned := map[string]string{}
+	for _, m := range openStackMachines.Items {
+		assigned[m.Name] = m.Spec.FailureDomain
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return "", errors.Wrap(err, "failed to list Machines")
+	}
+	for i := range machines.Items {
+		machine := &machines.Items[i]
+		if util.IsControlPlaneMachine(machine) || machine.Spec.InfrastructureRef.Name == openStackMachine.Name {
+			continue
+		}
+		failureDomain := assigned[machine.Spec.InfrastructureRef.Name]
+		if machine.Spec.FailureDomain != nil {
+			failureDomain = *machine.Spec.FailureDomain
+		}
+		if _, ok := counts[failureDomain]; ok {
+			counts[failureDomain]++
+		}
+	}
+
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	picked := names[0]
+	for _, name := range names[1:] {
+		if counts[name] < counts[picked] {
+			picked = name
+		}
+	}
+	return picked, nil
+}
+
+// reconcileWorkerFailureDomain assigns a failure domain to the machine if it
+// has none. It returns false if no failure domain has capacity for the
+// machine, in which case all of them are tried again later.
+func (r *OpenStackMachineReconciler) reconcileWorkerFailureDomain(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) (bool, error) {
+	if openStackMachine.Spec.FailureDomain != "" {
+		return true, nil
+	}
+
+	failureDomain, err := r.pickWorkerFailureDomain(ctx, cluster, openStackCluster, openStackMachine)
+	if err != nil {
+		return false, err
+	}
+	if failureDomain == "" {
+		scope.Logger.Info("No failure domain has capacity for the instance, retrying later")
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNoValidHostReason, clusterv1.ConditionSeverityWarning, "No failure domain has capacity for the instance")
+		openStackMachine.Status.UnschedulableFailureDomains = nil
+		return false, nil
+	}
+
+	scope.Logger.Info("Assigned failure domain to machine", "failureDomain", failureDomain)
+	openStackMachine.Spec.FailureDomain = failureDomain
+	return true, nil
+}
+
+// machineFailureDomain returns the failure domain the instance of the machine
+// is created in. The failure domain of the OpenStackMachine takes precedence,
+// as it is either assigned by the controller or a fallback failure domain
+// replacing the failure domain of the Machine.
+func machineFailureDomain(machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) string {
+	if openStackMachine.Spec.FailureDomain != "" {
+		return openStackMachine.Spec.FailureDomain
+	}
+	if machine.Spec.FailureDomain != nil {
+		return *machine.Spec.FailureDomain
+	}
+	return ""
+}
+
+// nextFallbackFailureDomain returns the first fallback failure domain of the
+// machine which has not been tried yet, or an empty string if there is none.
+func nextFallbackFailureDomain(openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, tried []string) string {
+	triedSet := sets.NewString(tried...)
+	for _, failureDomain := range openStackCluster.Spec.MachineSpecWithDefaults(&openStackMachine.Spec).FallbackFailureDomains {
+		if !triedSet.Has(failureDomain) {
+			return failureDomain
+		}
+	}
+	return ""
+}
+
+// reconcileUnscheduledServer waits until Nova has scheduled the instance of a
+// machine whose failure domain may still change, so that the instance is only
+// recorded in the machine once its failure domain is final. If no host had
+// capacity in the failure domain or the instance exceeded a quota, the server
+// is deleted so that the machine is created again in another failure domain:
+// the next one assigned by the controller, or the next fallback failure
+// domain. Without a fallback failure domain left, the machine fails with its
+// instance. It returns true while the instance is not scheduled.
+func (r *OpenStackMachineReconciler) reconcileUnscheduledServer(ctx context.Context, scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, openStackServer *infrav1.OpenStackServer) (bool, error) {
+	if !openStackServer.DeletionTimestamp.IsZero() {
+		scope.Logger.Info("Waiting for OpenStackServer to be deleted", "openStackServer", openStackServer.Name)
+		return true, nil
+	}
+
+	failureDomain := machineFailureDomain(machine, openStackMachine)
+	if condition := conditions.Get(openStackServer, infrav1.InstanceReadyCondition); condition != nil && failureDomain != "" &&
+		(condition.Reason == infrav1.InstanceNoValidHostReason || condition.Reason == infrav1.InstanceQuotaExceededReason) {
+		tried := append(openStackMachine.Status.UnschedulableFailureDomains, failureDomain)
+		var next string
+		if !balancesFailureDomain(openStackCluster, machine) {
+			if next = nextFallbackFailureDomain(openStackCluster, openStackMachine, tried); next == "" {
+				return false, nil
+			}
+		}
+
+		scope.Logger.Info("Instance cannot be created in failure domain, creating it in another failure domain", "failureDomain", failureDomain, "reason", condition.Reason)
+		record.Warnf(openStackMachine, condition.Reason, "Instance cannot be created in failure domain %s: %s", failureDomain, condition.Message)
+		if err := r.Client.Delete(ctx, openStackServer); err != nil && !apierrors.IsNotFound(err) {
+			return true, errors.Wrap(err, "failed to delete OpenStackServer")
+		}
+		openStackMachine.Spec.FailureDomain = next
+		openStackMachine.Status.UnschedulableFailureDomains = tried
+		if next != "" {
+			openStackMachine.Status.FallbackFailureDomain = next
+		}
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, condition.Reason, clusterv1.ConditionSeverityWarning, "Instance cannot be created in failure domain %s", failureDomain)
+		return true, nil
+	}
+
+	if openStackServer.Status.InstanceID == nil {
+		return false, nil
+	}
+	if state := openStackServer.Status.InstanceState; state == nil || (*state != infrav1.InstanceStateActive && *state != infrav1.InstanceStateError) {
+		scope.Logger.Info("Waiting for instance to be scheduled", "failureDomain", failureDomain)
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, clusterv1.ConditionSeverityInfo, "Waiting for instance to be scheduled in failure domain %s", failureDomain)
+		return true, nil
+	}
+	return false, nil
+}
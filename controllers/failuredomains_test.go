@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_pickWorkerFailureDomain(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+	openStackCluster := &infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{
+			FailureDomains: clusterv1.FailureDomains{
+				"az1": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"az2": clusterv1.FailureDomainSpec{ControlPlane: true},
+				"az3": clusterv1.FailureDomainSpec{ControlPlane: true},
+			},
+		},
+	}
+	newMachine := func(name string, failureDomain *string, labels map[string]string) *clusterv1.Machine {
+		machineLabels := map[string]string{clusterv1.ClusterLabelName: cluster.Name}
+		for k, v := range labels {
+			machineLabels[k] = v
+		}
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace, Labels: machineLabels},
+			Spec: clusterv1.MachineSpec{
+				ClusterName:       cluster.Name,
+				FailureDomain:     failureDomain,
+				InfrastructureRef: corev1.ObjectReference{Kind: "OpenStackMachine", Name: name},
+			},
+		}
+	}
+	newOpenStackMachine := func(name, failureDomain string) *infrav1.OpenStackMachine {
+		return &infrav1.OpenStackMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace, Labels: map[string]string{clusterv1.ClusterLabelName: cluster.Name}},
+			Spec:       infrav1.OpenStackMachineSpec{FailureDomain: failureDomain},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		objects       []client.Object
+		unschedulable []string
+		want          string
+	}{
+		{
+			name: "No other machines",
+			want: "az1",
+		},
+		{
+			name: "Fewest workers",
+			objects: []client.Object{
+				newMachine("worker-0", pointer.StringPtr("az1"), nil),
+				newMachine("worker-1", nil, nil),
+				newOpenStackMachine("worker-1", "az2"),
+				newMachine("control-plane-0", pointer.StringPtr("az3"), map[string]string{clusterv1.MachineControlPlaneLabelName: ""}),
+			},
+			want: "az3",
+		},
+		{
+			name: "Unschedulable failure domains skipped",
+			objects: []client.Object{
+				newMachine("worker-0", pointer.StringPtr("az1"), nil),
+			},
+			unschedulable: []string{"az2", "az3"},
+			want:          "az1",
+		},
+		{
+			name:          "All failure domains unschedulable",
+			unschedulable: []string{"az1", "az2", "az3"},
+			want:          "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			testScheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(testScheme)).To(Succeed())
+			g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+
+			openStackMachine := newOpenStackMachine("machine", "")
+			openStackMachine.Status.UnschedulableFailureDomains = tt.unschedulable
+			objects := append([]client.Object{newMachine("machine", nil, nil), openStackMachine}, tt.objects...)
+			r := &OpenStackMachineReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objects...).Build()}
+
+			got, err := r.pickWorkerFailureDomain(context.TODO(), cluster, openStackCluster, openStackMachine)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_reconcileUnscheduledServer(t *testing.T) {
+	activeState := infrav1.InstanceStateActive
+	buildState := infrav1.InstanceState("BUILD")
+	errorState := infrav1.InstanceStateError
+
+	balancedCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			FailureDomains: &infrav1.FailureDomainsConfig{BalanceWorkers: true},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			FailureDomains: clusterv1.FailureDomains{"az1": clusterv1.FailureDomainSpec{}, "az2": clusterv1.FailureDomainSpec{}},
+		},
+	}
+	noValidHost := func(s *infrav1.OpenStackServer) {
+		s.Status.InstanceID = pointer.StringPtr("instance-id")
+		s.Status.InstanceState = &errorState
+		conditions.MarkFalse(s, infrav1.InstanceReadyCondition, infrav1.InstanceNoValidHostReason, clusterv1.ConditionSeverityError, "No valid host was found. There are not enough hosts available.")
+	}
+
+	tests := []struct {
+		name                   string
+		openStackCluster       *infrav1.OpenStackCluster
+		machineFailureDomain   *string
+		failureDomain          string
+		fallbackFailureDomains []string
+		unschedulable          []string
+		server                 func(*infrav1.OpenStackServer)
+		wantUnscheduled        bool
+		wantFailureDomain      string
+		wantUnschedulable      []string
+		wantFallback           string
+		wantDeleted            bool
+	}{
+		{
+			name:              "Instance not created yet",
+			openStackCluster:  balancedCluster,
+			failureDomain:     "az1",
+			server:            func(*infrav1.OpenStackServer) {},
+			wantFailureDomain: "az1",
+		},
+		{
+			name:             "Instance building",
+			openStackCluster: balancedCluster,
+			failureDomain:    "az1",
+			server: func(s *infrav1.OpenStackServer) {
+				s.Status.InstanceID = pointer.StringPtr("instance-id")
+				s.Status.InstanceState = &buildState
+			},
+			wantUnscheduled:   true,
+			wantFailureDomain: "az1",
+		},
+		{
+			name:             "Instance active",
+			openStackCluster: balancedCluster,
+			failureDomain:    "az1",
+			server: func(s *infrav1.OpenStackServer) {
+				s.Status.InstanceID = pointer.StringPtr("instance-id")
+				s.Status.InstanceState = &activeState
+			},
+			wantFailureDomain: "az1",
+		},
+		{
+			name:              "No valid host in assigned failure domain",
+			openStackCluster:  balancedCluster,
+			failureDomain:     "az1",
+			server:            noValidHost,
+			wantUnscheduled:   true,
+			wantUnschedulable: []string{"az1"},
+			wantDeleted:       true,
+		},
+		{
+			name:                   "No valid host in failure domain of the Machine",
+			openStackCluster:       &infrav1.OpenStackCluster{},
+			machineFailureDomain:   pointer.StringPtr("az1"),
+			fallbackFailureDomains: []string{"az2", "az3"},
+			server:                 noValidHost,
+			wantUnscheduled:        true,
+			wantFailureDomain:      "az2",
+			wantUnschedulable:      []string{"az1"},
+			wantFallback:           "az2",
+			wantDeleted:            true,
+		},
+		{
+			name:                   "Quota exceeded in fallback failure domain",
+			openStackCluster:       &infrav1.OpenStackCluster{},
+			machineFailureDomain:   pointer.StringPtr("az1"),
+			failureDomain:          "az2",
+			fallbackFailureDomains: []string{"az2", "az3"},
+			unschedulable:          []string{"az1"},
+			server: func(s *infrav1.OpenStackServer) {
+				conditions.MarkFalse(s, infrav1.InstanceReadyCondition, infrav1.InstanceQuotaExceededReason, clusterv1.ConditionSeverityError, "Quota exceeded for cores")
+			},
+			wantUnscheduled:   true,
+			wantFailureDomain: "az3",
+			wantUnschedulable: []string{"az1", "az2"},
+			wantFallback:      "az3",
+			wantDeleted:       true,
+		},
+		{
+			name:                   "No fallback failure domain left",
+			openStackCluster:       &infrav1.OpenStackCluster{},
+			machineFailureDomain:   pointer.StringPtr("az1"),
+			failureDomain:          "az2",
+			fallbackFailureDomains: []string{"az2"},
+			unschedulable:          []string{"az1"},
+			server:                 noValidHost,
+			wantFailureDomain:      "az2",
+			wantUnschedulable:      []string{"az1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			testScheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+
+			openStackServer := &infrav1.OpenStackServer{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"}}
+			tt.server(openStackServer)
+			machine := &clusterv1.Machine{Spec: clusterv1.MachineSpec{FailureDomain: tt.machineFailureDomain}}
+			openStackMachine := &infrav1.OpenStackMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "default"},
+				Spec:       infrav1.OpenStackMachineSpec{FailureDomain: tt.failureDomain, FallbackFailureDomains: tt.fallbackFailureDomains},
+				Status:     infrav1.OpenStackMachineStatus{UnschedulableFailureDomains: tt.unschedulable},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(openStackServer.DeepCopy()).Build()
+			r := &OpenStackMachineReconciler{Client: fakeClient}
+
+			unscheduled, err := r.reconcileUnscheduledServer(context.TODO(), &scope.Scope{Logger: logr.Discard()}, tt.openStackCluster, machine, openStackMachine, openStackServer)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(unscheduled).To(Equal(tt.wantUnscheduled))
+			g.Expect(openStackMachine.Spec.FailureDomain).To(Equal(tt.wantFailureDomain))
+			g.Expect(openStackMachine.Status.UnschedulableFailureDomains).To(Equal(tt.wantUnschedulable))
+			g.Expect(openStackMachine.Status.FallbackFailureDomain).To(Equal(tt.wantFallback))
+
+			err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(openStackServer), &infrav1.OpenStackServer{})
+			g.Expect(apierrors.IsNotFound(err)).To(Equal(tt.wantDeleted))
+		})
+	}
+}
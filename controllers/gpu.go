@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// waitForGPUHostsDuration is the time after which the host aggregates are
+// checked again when none provided the GPUs of a machine.
+const waitForGPUHostsDuration = 5 * time.Minute
+
+// noGPUHostsError is returned when an instance is not created because no
+// host aggregate provides the GPUs requested by its flavor.
+type noGPUHostsError struct {
+	flavor           string
+	availabilityZone string
+}
+
+func (e *noGPUHostsError) Error() string {
+	if e.availabilityZone == "" {
+		return fmt.Sprintf("no host aggregate provides the GPUs of flavor %s", e.flavor)
+	}
+	return fmt.Sprintf("no host aggregate provides the GPUs of flavor %s in availability zone %s", e.flavor, e.availabilityZone)
+}
+
+// reconcileMachineGPUs publishes the capacity of the flavor of the machine
+// and checks that a host aggregate provides the GPUs requested by it. Like
+// the quota check, it is advisory: if the flavor or the host aggregates
+// cannot be queried, the creation of the instance is left to fail on its
+// own.
+func reconcileMachineGPUs(scope *scope.Scope, computeService *compute.Service, openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec) error {
+	capacity, err := computeService.GetFlavorCapacity(instanceSpec.Flavor)
+	if err != nil {
+		scope.Logger.Info("Skipping GPU check", "error", err.Error())
+		return nil
+	}
+	openStackMachine.Status.Capacity = capacity
+	if _, ok := capacity[compute.GPUResourceName]; !ok {
+		return nil
+	}
+
+	hasHosts, err := computeService.HasFlavorGPUHosts(instanceSpec.Flavor, instanceSpec.FailureDomain)
+	if err != nil {
+		scope.Logger.Info("Skipping GPU host check", "error", err.Error())
+		return nil
+	}
+	if !hasHosts {
+		err := &noGPUHostsError{flavor: instanceSpec.Flavor, availabilityZone: instanceSpec.FailureDomain}
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNoGPUHostsReason, clusterv1.ConditionSeverityWarning, "%s", err.Error())
+		return err
+	}
+	return nil
+}
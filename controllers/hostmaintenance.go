@@ -0,0 +1,349 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const (
+	// hostMaintenancePollInterval is the interval at which the compute hosts
+	// of the machines of a cluster with host maintenance handling are checked.
+	hostMaintenancePollInterval = time.Minute
+
+	providerIDPrefix = "openstack:///"
+
+	hostMaintenanceReason   = "HostMaintenance"
+	noHostMaintenanceReason = "NoHostMaintenance"
+	drainedReason           = "Drained"
+	notDrainedReason        = "NotDrained"
+)
+
+// reconcileHostMaintenance cordons the nodes of the machines whose compute
+// host is under maintenance and sets their OpenStackHostMaintenance
+// condition, and reverts this once the maintenance is over. With drain
+// enabled, the cordoned nodes are drained and get the
+// OpenStackHostMaintenanceDrained condition once no pods are left to evict.
+// Clusters with host maintenance handling are requeued to check the hosts
+// again.
+func (r *OpenStackClusterReconciler) reconcileHostMaintenance(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+	hostMaintenance := openStackCluster.Spec.HostMaintenance
+	if hostMaintenance == nil {
+		conditions.Delete(openStackCluster, infrav1.HostMaintenanceReadyCondition)
+		return ctrl.Result{}, nil
+	}
+	// The machines have no nodes before the control plane is initialized
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		return ctrl.Result{}, nil
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list Machines")
+	}
+	var nodeMachines []*clusterv1.Machine
+	var instanceIDs []string
+	for i := range machines.Items {
+		machine := &machines.Items[i]
+		if machine.Status.NodeRef == nil || machine.Spec.ProviderID == nil || !strings.HasPrefix(*machine.Spec.ProviderID, providerIDPrefix) {
+			continue
+		}
+		nodeMachines = append(nodeMachines, machine)
+		instanceIDs = append(instanceIDs, strings.TrimPrefix(*machine.Spec.ProviderID, providerIDPrefix))
+	}
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	maintenance, hostsVisible, err := computeService.GetInstanceMaintenance(instanceIDs)
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.HostMaintenanceReadyCondition, infrav1.HostMaintenanceReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Checking compute hosts failed: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to check compute hosts of machines")
+	}
+
+	if len(nodeMachines) > 0 {
+		remoteClient, err := remote.NewClusterClient(ctx, "openstackcluster-controller", r.Client, client.ObjectKeyFromObject(cluster))
+		if err != nil {
+			conditions.MarkFalse(openStackCluster, infrav1.HostMaintenanceReadyCondition, infrav1.HostMaintenanceReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Connecting to workload cluster failed: %v", err)
+			return ctrl.Result{}, errors.Wrap(err, "failed to create client for workload cluster")
+		}
+
+		drain := hostMaintenance.Drain && !hostMaintenance.DisableCordon
+		var clientset kubernetes.Interface
+		for i, machine := range nodeMachines {
+			nodeName := machine.Status.NodeRef.Name
+			reason, affected := maintenance[instanceIDs[i]]
+			cordoned, uncordoned, err := setNodeHostMaintenance(ctx, remoteClient, nodeName, affected, reason, !hostMaintenance.DisableCordon)
+			if err != nil {
+				conditions.MarkFalse(openStackCluster, infrav1.HostMaintenanceReadyCondition, infrav1.HostMaintenanceReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Updating node %s failed: %v", nodeName, err)
+				return ctrl.Result{}, errors.Wrapf(err, "failed to update node %s", nodeName)
+			}
+			switch {
+			case cordoned:
+				scope.Logger.Info("Cordoned node for host maintenance", "node", nodeName, "reason", reason)
+				record.Eventf(machine, "CordonedNode", "Cordoned node %s: %s", nodeName, reason)
+			case uncordoned:
+				scope.Logger.Info("Uncordoned node after host maintenance", "node", nodeName)
+				record.Eventf(machine, "UncordonedNode", "Uncordoned node %s after host maintenance", nodeName)
+			}
+
+			_, excludeDraining := machine.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]
+			drained := false
+			drainedMessage := ""
+			switch {
+			case !affected || !drain:
+			case excludeDraining:
+				// Machines whose nodes are not drained on deletion can be
+				// replaced right away
+				drained = true
+				drainedMessage = "Draining is excluded for the machine"
+			case annotations.HasWithPrefix(clusterv1.PreDrainDeleteHookAnnotationPrefix, machine.Annotations):
+				// Nodes are only drained once the owners of the pre-drain
+				// hooks removed them, as they are when the machine is deleted
+				scope.Logger.Info("Waiting for pre-drain hooks before draining node for host maintenance", "node", nodeName)
+			default:
+				if clientset == nil {
+					restConfig, err := remote.RESTConfig(ctx, "openstackcluster-controller", r.Client, client.ObjectKeyFromObject(cluster))
+					if err == nil {
+						clientset, err = kubernetes.NewForConfig(restConfig)
+					}
+					if err != nil {
+						conditions.MarkFalse(openStackCluster, infrav1.HostMaintenanceReadyCondition, infrav1.HostMaintenanceReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Connecting to workload cluster failed: %v", err)
+						return ctrl.Result{}, errors.Wrap(err, "failed to create clientset for workload cluster")
+					}
+				}
+				drained, err = drainNode(ctx, clientset, nodeName)
+				if err != nil {
+					record.Warnf(machine, "FailedDrainNode", "Failed to drain node %s: %v", nodeName, err)
+					conditions.MarkFalse(openStackCluster, infrav1.HostMaintenanceReadyCondition, infrav1.HostMaintenanceReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Draining node %s failed: %v", nodeName, err)
+					return ctrl.Result{}, errors.Wrapf(err, "failed to drain node %s", nodeName)
+				}
+				drainedMessage = reason
+			}
+			drainedNow, err := setNodeHostMaintenanceDrained(ctx, remoteClient, nodeName, drained, drainedMessage)
+			if err != nil {
+				conditions.MarkFalse(openStackCluster, infrav1.HostMaintenanceReadyCondition, infrav1.HostMaintenanceReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Updating node %s failed: %v", nodeName, err)
+				return ctrl.Result{}, errors.Wrapf(err, "failed to update node %s", nodeName)
+			}
+			if drainedNow {
+				scope.Logger.Info("Drained node for host maintenance", "node", nodeName)
+				record.Eventf(machine, "DrainedNode", "Drained node %s: %s", nodeName, reason)
+			}
+		}
+	}
+
+	if hostsVisible {
+		conditions.MarkTrue(openStackCluster, infrav1.HostMaintenanceReadyCondition)
+	} else {
+		conditions.MarkFalse(openStackCluster, infrav1.HostMaintenanceReadyCondition, infrav1.ComputeHostsNotVisibleReason, clusterv1.ConditionSeverityInfo, "Compute services are not visible with the credentials of the cluster, only migrations of instances are detected")
+	}
+	return ctrl.Result{RequeueAfter: hostMaintenancePollInterval}, nil
+}
+
+// setNodeHostMaintenance sets the OpenStackHostMaintenance condition of the
+// node, and cordons it while it is affected by host maintenance if cordon is
+// true. Nodes are only uncordoned if they were cordoned for host
+// maintenance. The condition is only added to nodes which are affected.
+func setNodeHostMaintenance(ctx context.Context, c client.Client, nodeName string, affected bool, reason string, cordon bool) (cordoned, uncordoned bool, err error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+
+	condition := corev1.NodeCondition{
+		Type:    infrav1.HostMaintenanceNodeCondition,
+		Status:  corev1.ConditionFalse,
+		Reason:  noHostMaintenanceReason,
+		Message: "",
+	}
+	if affected {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = hostMaintenanceReason
+		condition.Message = reason
+	}
+	if _, err := patchNodeCondition(ctx, c, node, condition); err != nil {
+		return false, false, err
+	}
+
+	_, cordonedForMaintenance := node.Annotations[infrav1.HostMaintenanceCordonAnnotation]
+	switch {
+	case affected && cordon && !node.Spec.Unschedulable:
+		patch := client.StrategicMergeFrom(node.DeepCopy())
+		node.Spec.Unschedulable = true
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[infrav1.HostMaintenanceCordonAnnotation] = ""
+		if err := c.Patch(ctx, node, patch); err != nil {
+			return false, false, err
+		}
+		return true, false, nil
+	case (!affected || !cordon) && cordonedForMaintenance:
+		patch := client.StrategicMergeFrom(node.DeepCopy())
+		node.Spec.Unschedulable = false
+		delete(node.Annotations, infrav1.HostMaintenanceCordonAnnotation)
+		if err := c.Patch(ctx, node, patch); err != nil {
+			return false, false, err
+		}
+		return false, true, nil
+	}
+	return false, false, nil
+}
+
+// setNodeHostMaintenanceDrained sets the OpenStackHostMaintenanceDrained
+// condition of the node. The condition is only added to nodes which are
+// drained. It returns true if the node was not reported as drained before.
+func setNodeHostMaintenanceDrained(ctx context.Context, c client.Client, nodeName string, drained bool, message string) (bool, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	condition := corev1.NodeCondition{
+		Type:    infrav1.HostMaintenanceDrainedNodeCondition,
+		Status:  corev1.ConditionFalse,
+		Reason:  notDrainedReason,
+		Message: "",
+	}
+	if drained {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = drainedReason
+		condition.Message = message
+	}
+	transitioned, err := patchNodeCondition(ctx, c, node, condition)
+	return transitioned && drained, err
+}
+
+// patchNodeCondition sets the condition of the node. Conditions which are
+// not true are only set on nodes which already have the condition. It
+// returns true if the status of the condition changed.
+func patchNodeCondition(ctx context.Context, c client.Client, node *corev1.Node, condition corev1.NodeCondition) (bool, error) {
+	index := -1
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == condition.Type {
+			index = i
+			break
+		}
+	}
+	if index < 0 && condition.Status != corev1.ConditionTrue {
+		return false, nil
+	}
+
+	existing := corev1.NodeCondition{}
+	if index >= 0 {
+		existing = node.Status.Conditions[index]
+	}
+	if existing.Status == condition.Status && existing.Message == condition.Message {
+		return false, nil
+	}
+
+	now := metav1.Now()
+	condition.LastHeartbeatTime = now
+	condition.LastTransitionTime = existing.LastTransitionTime
+	if existing.Status != condition.Status {
+		condition.LastTransitionTime = now
+	}
+
+	patch := client.StrategicMergeFrom(node.DeepCopy())
+	if index >= 0 {
+		node.Status.Conditions[index] = condition
+	} else {
+		node.Status.Conditions = append(node.Status.Conditions, condition)
+	}
+	if err := c.Status().Patch(ctx, node, patch); err != nil {
+		return false, err
+	}
+	return existing.Status != condition.Status, nil
+}
+
+// drainNode evicts the pods of the node, except for mirror pods, pods of
+// DaemonSets and completed pods. The Eviction API respects the
+// PodDisruptionBudgets of the pods, evictions it refuses are retried on the
+// next call. It returns true once no pod to evict is left on the node.
+func drainNode(ctx context.Context, clientset kubernetes.Interface, nodeName string) (bool, error) {
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list pods")
+	}
+
+	drained := true
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName || !isEvictable(pod) {
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			drained = false
+			continue
+		}
+		err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		})
+		switch {
+		case apierrors.IsNotFound(err):
+			continue
+		case err != nil && !apierrors.IsTooManyRequests(err):
+			return false, errors.Wrapf(err, "failed to evict pod %s/%s", pod.Namespace, pod.Name)
+		}
+		drained = false
+	}
+	return drained, nil
+}
+
+// isEvictable returns true if the pod is evicted when its node is drained.
+func isEvictable(pod *corev1.Pod) bool {
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		return false
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_setNodeHostMaintenance(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	manual := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "manual"}, Spec: corev1.NodeSpec{Unschedulable: true}}
+	fakeClient := fake.NewClientBuilder().WithObjects(node, manual).Build()
+
+	getNode := func(name string) *corev1.Node {
+		n := &corev1.Node{}
+		g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: name}, n)).To(Succeed())
+		return n
+	}
+	getCondition := func(n *corev1.Node) *corev1.NodeCondition {
+		for i := range n.Status.Conditions {
+			if n.Status.Conditions[i].Type == infrav1.HostMaintenanceNodeCondition {
+				return &n.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	// Unaffected nodes get no condition
+	cordoned, uncordoned, err := setNodeHostMaintenance(ctx, fakeClient, "node", false, "", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cordoned).To(BeFalse())
+	g.Expect(uncordoned).To(BeFalse())
+	g.Expect(getCondition(getNode("node"))).To(BeNil())
+
+	// Affected nodes are cordoned
+	cordoned, uncordoned, err = setNodeHostMaintenance(ctx, fakeClient, "node", true, "compute host a is disabled: upgrade", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cordoned).To(BeTrue())
+	g.Expect(uncordoned).To(BeFalse())
+	n := getNode("node")
+	g.Expect(n.Spec.Unschedulable).To(BeTrue())
+	g.Expect(n.Annotations).To(HaveKey(infrav1.HostMaintenanceCordonAnnotation))
+	g.Expect(getCondition(n)).NotTo(BeNil())
+	g.Expect(getCondition(n).Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(getCondition(n).Message).To(Equal("compute host a is disabled: upgrade"))
+
+	// Nodes are uncordoned after the maintenance
+	cordoned, uncordoned, err = setNodeHostMaintenance(ctx, fakeClient, "node", false, "", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cordoned).To(BeFalse())
+	g.Expect(uncordoned).To(BeTrue())
+	n = getNode("node")
+	g.Expect(n.Spec.Unschedulable).To(BeFalse())
+	g.Expect(n.Annotations).NotTo(HaveKey(infrav1.HostMaintenanceCordonAnnotation))
+	g.Expect(getCondition(n).Status).To(Equal(corev1.ConditionFalse))
+
+	// Nodes cordoned by others are left alone
+	cordoned, _, err = setNodeHostMaintenance(ctx, fakeClient, "manual", true, "instance is being migrated", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cordoned).To(BeFalse())
+	cordoned, uncordoned, err = setNodeHostMaintenance(ctx, fakeClient, "manual", false, "", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cordoned).To(BeFalse())
+	g.Expect(uncordoned).To(BeFalse())
+	g.Expect(getNode("manual").Spec.Unschedulable).To(BeTrue())
+
+	// Cordoning can be disabled
+	cordoned, _, err = setNodeHostMaintenance(ctx, fakeClient, "node", true, "instance is being migrated", false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cordoned).To(BeFalse())
+	g.Expect(getNode("node").Spec.Unschedulable).To(BeFalse())
+	g.Expect(getCondition(getNode("node")).Status).To(Equal(corev1.ConditionTrue))
+
+	// Missing nodes are ignored
+	_, _, err = setNodeHostMaintenance(ctx, fakeClient, "missing", true, "", true)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func Test_setNodeHostMaintenanceDrained(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+	fakeClient := fake.NewClientBuilder().WithObjects(node).Build()
+
+	getCondition := func() *corev1.NodeCondition {
+		n := &corev1.Node{}
+		g.Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "node"}, n)).To(Succeed())
+		for i := range n.Status.Conditions {
+			if n.Status.Conditions[i].Type == infrav1.HostMaintenanceDrainedNodeCondition {
+				return &n.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	// Nodes which are not drained get no condition
+	drained, err := setNodeHostMaintenanceDrained(ctx, fakeClient, "node", false, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drained).To(BeFalse())
+	g.Expect(getCondition()).To(BeNil())
+
+	// Drained nodes are reported once
+	drained, err = setNodeHostMaintenanceDrained(ctx, fakeClient, "node", true, "instance is being migrated")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drained).To(BeTrue())
+	g.Expect(getCondition().Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(getCondition().Message).To(Equal("instance is being migrated"))
+	drained, err = setNodeHostMaintenanceDrained(ctx, fakeClient, "node", true, "instance is being migrated")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drained).To(BeFalse())
+
+	// The condition is reset after the maintenance
+	drained, err = setNodeHostMaintenanceDrained(ctx, fakeClient, "node", false, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drained).To(BeFalse())
+	g.Expect(getCondition().Status).To(Equal(corev1.ConditionFalse))
+
+	// Missing nodes are ignored
+	_, err = setNodeHostMaintenanceDrained(ctx, fakeClient, "missing", true, "")
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func Test_drainNode(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	pod := func(name, nodeName string, modify func(*corev1.Pod)) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if modify != nil {
+			modify(p)
+		}
+		return p
+	}
+	clientset := kubernetesfake.NewSimpleClientset(
+		pod("app", "node", nil),
+		pod("protected", "node", nil),
+		pod("other-node", "other", nil),
+		pod("mirror", "node", func(p *corev1.Pod) {
+			p.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: ""}
+		}),
+		pod("daemonset", "node", func(p *corev1.Pod) {
+			p.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "ds", Controller: pointer.Bool(true)}}
+		}),
+		pod("completed", "node", func(p *corev1.Pod) {
+			p.Status.Phase = corev1.PodSucceeded
+		}),
+	)
+
+	// Evictions delete the pods, unless a PodDisruptionBudget refuses them
+	protected := true
+	var evicted []string
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if eviction.Name == "protected" && protected {
+			return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+		}
+		evicted = append(evicted, eviction.Name)
+		gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		return true, nil, clientset.Tracker().Delete(gvr, eviction.Namespace, eviction.Name)
+	})
+
+	drained, err := drainNode(ctx, clientset, "node")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drained).To(BeFalse())
+	g.Expect(evicted).To(ConsistOf("app"))
+
+	// Refused evictions are retried
+	protected = false
+	drained, err = drainNode(ctx, clientset, "node")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drained).To(BeFalse())
+	g.Expect(evicted).To(ConsistOf("app", "protected"))
+
+	drained, err = drainNode(ctx, clientset, "node")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(drained).To(BeTrue())
+	g.Expect(evicted).To(ConsistOf("app", "protected"))
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// instanceActionReason is the reason of the events reporting actions on
+// instances which were not requested by the controller.
+const instanceActionReason = "InstanceAction"
+
+// reconcileInstanceActions reports the actions on the instance of the machine
+// which were not requested by the controller as warning events, e.g. live
+// migrations, stops or rebuilds by the cloud operator. Actions are reported
+// once: the first poll reports all actions since the machine was created,
+// later polls the actions after the last reported one.
+func reconcileInstanceActions(scope *scope.Scope, computeService *compute.Service, openStackMachine *infrav1.OpenStackMachine, instanceID string) {
+	since := openStackMachine.CreationTimestamp
+	if openStackMachine.Status.LastInstanceActionTime != nil {
+		since = *openStackMachine.Status.LastInstanceActionTime
+	}
+
+	actions, err := computeService.GetUnrequestedInstanceActions(instanceID, since.Time)
+	if err != nil {
+		scope.Logger.Info("Skipping instance action audit", "reason", err.Error())
+		return
+	}
+	for _, action := range actions {
+		scope.Logger.Info("Instance action not requested by the controller", "action", action.Action, "userID", action.UserID, "requestID", action.RequestID)
+		message := fmt.Sprintf("Instance action %s was not requested by the controller (user %s, request %s)", action.Action, action.UserID, action.RequestID)
+		if action.Message != "" {
+			message += ": " + action.Message
+		}
+		record.Warn(openStackMachine, instanceActionReason, message)
+		openStackMachine.Status.LastInstanceActionTime = &metav1.Time{Time: action.StartTime}
+	}
+}
+
+// withInstanceActionPoll requeues a successfully reconciled machine with an
+// instance after the instance action poll interval, so that its instance
+// actions are polled even if nothing else changes.
+func (r *OpenStackMachineReconciler) withInstanceActionPoll(openStackMachine *infrav1.OpenStackMachine, result ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil && result.IsZero() && r.InstanceActionPollInterval > 0 && openStackMachine.Spec.InstanceID != nil &&
+		openStackMachine.Status.FailureReason == nil && openStackMachine.Status.FailureMessage == nil {
+		result.RequeueAfter = r.InstanceActionPollInterval
+	}
+	return result, err
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/instanceha"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// instanceHAPollInterval is the interval at which the Masakari notifications
+// of a cluster with instance HA are checked.
+const instanceHAPollInterval = time.Minute
+
+// reconcileInstanceHA checks the Masakari segment of the cluster and marks
+// the machines whose instances Masakari reported as failed, according to the
+// remediation policy of the cluster. Clusters with instance HA are requeued
+// to check the notifications again.
+func (r *OpenStackClusterReconciler) reconcileInstanceHA(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+	instanceHA := openStackCluster.Spec.InstanceHA
+	if instanceHA == nil {
+		conditions.Delete(openStackCluster, infrav1.InstanceHAReadyCondition)
+		openStackCluster.Status.InstanceHA = nil
+		return ctrl.Result{}, nil
+	}
+
+	// The cloud may not expose Masakari, which is not an error of the
+	// cluster. It is checked again at the next resync.
+	instanceHAService, err := instanceha.NewService(scope)
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.InstanceHAReadyCondition, infrav1.InstanceHAUnavailableReason, clusterv1.ConditionSeverityWarning, "Masakari is not available: %v", err)
+		return ctrl.Result{}, nil
+	}
+
+	segment, err := instanceHAService.GetSegment(instanceHA.Segment)
+	if err != nil {
+		if code, ok := capoerrors.StatusCode(err); ok && code == http.StatusForbidden {
+			conditions.MarkFalse(openStackCluster, infrav1.InstanceHAReadyCondition, infrav1.InstanceHAUnavailableReason, clusterv1.ConditionSeverityWarning, "Masakari is not available: %v", err)
+			return ctrl.Result{}, nil
+		}
+		conditions.MarkFalse(openStackCluster, infrav1.InstanceHAReadyCondition, infrav1.InstanceHAReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Getting segment failed: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to get instance HA segment")
+	}
+	if segment == nil {
+		conditions.MarkFalse(openStackCluster, infrav1.InstanceHAReadyCondition, infrav1.InstanceHASegmentNotFoundReason, clusterv1.ConditionSeverityWarning, "Segment %s not found", instanceHA.Segment)
+		return ctrl.Result{RequeueAfter: instanceHAPollInterval}, nil
+	}
+
+	status := openStackCluster.Status.InstanceHA
+	if status == nil || status.SegmentID != segment.UUID {
+		status = &infrav1.InstanceHAStatus{SegmentID: segment.UUID}
+	}
+	// Failures reported before instance HA was enabled are not remediated.
+	if status.LastNotificationTime == nil {
+		now := metav1.Now()
+		status.LastNotificationTime = &now
+	}
+	openStackCluster.Status.InstanceHA = status
+
+	failures, err := instanceHAService.ListInstanceFailures(status.LastNotificationTime.Time)
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.InstanceHAReadyCondition, infrav1.InstanceHAReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Listing notifications failed: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to list instance HA notifications")
+	}
+
+	remediate, next := instanceFailuresToRemediate(failures, instanceHA.Remediation, status.LastNotificationTime.Time)
+	if err := r.failInstanceMachines(ctx, scope, cluster, remediate); err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.InstanceHAReadyCondition, infrav1.InstanceHAReconcileFailedReason, clusterv1.ConditionSeverityWarning, "Marking machines as failed failed: %v", err)
+		return ctrl.Result{}, err
+	}
+	status.LastNotificationTime = &metav1.Time{Time: next}
+
+	conditions.MarkTrue(openStackCluster, infrav1.InstanceHAReadyCondition)
+	return ctrl.Result{RequeueAfter: instanceHAPollInterval}, nil
+}
+
+// instanceFailuresToRemediate returns the failures whose machines are marked
+// as failed with the given remediation policy, and the time from which
+// notifications are listed next. Failures which Masakari is still recovering
+// are listed again, as their recovery may fail.
+func instanceFailuresToRemediate(failures []instanceha.InstanceFailure, remediation infrav1.InstanceHARemediation, since time.Time) ([]instanceha.InstanceFailure, time.Time) {
+	var remediate []instanceha.InstanceFailure
+	next := since
+	pending := false
+	for _, failure := range failures {
+		switch remediation {
+		case infrav1.InstanceHARemediationAlways:
+			remediate = append(remediate, failure)
+		case infrav1.InstanceHARemediationNever:
+		default:
+			if failure.RecoveryFailed() {
+				remediate = append(remediate, failure)
+			}
+			if !failure.Processed() {
+				pending = true
+			}
+		}
+		if !pending && failure.GeneratedTime.After(next) {
+			next = failure.GeneratedTime
+		}
+	}
+	return remediate, next
+}
+
+// failInstanceMachines marks the machines of the failed instances as failed,
+// so that they are remediated by a MachineHealthCheck.
+func (r *OpenStackClusterReconciler) failInstanceMachines(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, failures []instanceha.InstanceFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	openStackMachines := &infrav1.OpenStackMachineList{}
+	if err := r.Client.List(ctx, openStackMachines, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return errors.Wrap(err, "failed to list OpenStackMachines")
+	}
+	machinesByInstanceID := make(map[string]*infrav1.OpenStackMachine, len(openStackMachines.Items))
+	for i := range openStackMachines.Items {
+		openStackMachine := &openStackMachines.Items[i]
+		if openStackMachine.Spec.InstanceID != nil {
+			machinesByInstanceID[*openStackMachine.Spec.InstanceID] = openStackMachine
+		}
+	}
+
+	for _, failure := range failures {
+		openStackMachine, ok := machinesByInstanceID[failure.InstanceID]
+		if !ok || !openStackMachine.DeletionTimestamp.IsZero() || openStackMachine.Status.FailureReason != nil {
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(openStackMachine, r.Client)
+		if err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Masakari reported a failure of instance %s: %s (notification %s is %s)", failure.InstanceID, failure.Event, failure.NotificationID, failure.Status)
+		reason := capierrors.UpdateMachineError
+		openStackMachine.Status.FailureReason = &reason
+		openStackMachine.Status.FailureMessage = pointer.StringPtr(message)
+		if err := patchHelper.Patch(ctx, openStackMachine); err != nil {
+			return errors.Wrapf(err, "failed to mark OpenStackMachine %s as failed", openStackMachine.Name)
+		}
+
+		scope.Logger.Info("Marked machine as failed", "openStackMachine", openStackMachine.Name, "instanceID", failure.InstanceID, "notification", failure.NotificationID)
+		record.Warn(openStackMachine, "InstanceFailed", message)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/instanceha"
+)
+
+func Test_instanceFailuresToRemediate(t *testing.T) {
+	since := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	recovered := instanceha.InstanceFailure{InstanceID: "recovered", Status: "finished", GeneratedTime: since.Add(time.Minute)}
+	failed := instanceha.InstanceFailure{InstanceID: "failed", Status: "failed", GeneratedTime: since.Add(2 * time.Minute)}
+	running := instanceha.InstanceFailure{InstanceID: "running", Status: "running", GeneratedTime: since.Add(3 * time.Minute)}
+	errored := instanceha.InstanceFailure{InstanceID: "errored", Status: "error", GeneratedTime: since.Add(4 * time.Minute)}
+	failures := []instanceha.InstanceFailure{recovered, failed, running, errored}
+
+	tests := []struct {
+		name          string
+		remediation   infrav1.InstanceHARemediation
+		wantRemediate []instanceha.InstanceFailure
+		wantNext      time.Time
+	}{
+		{
+			name:          "Recovery failed",
+			remediation:   infrav1.InstanceHARemediationRecoveryFailed,
+			wantRemediate: []instanceha.InstanceFailure{failed, errored},
+			wantNext:      failed.GeneratedTime,
+		},
+		{
+			name:          "Defaults to recovery failed",
+			wantRemediate: []instanceha.InstanceFailure{failed, errored},
+			wantNext:      failed.GeneratedTime,
+		},
+		{
+			name:          "Always",
+			remediation:   infrav1.InstanceHARemediationAlways,
+			wantRemediate: failures,
+			wantNext:      errored.GeneratedTime,
+		},
+		{
+			name:        "Never",
+			remediation: infrav1.InstanceHARemediationNever,
+			wantNext:    errored.GeneratedTime,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			remediate, next := instanceFailuresToRemediate(failures, tt.remediation, since)
+			g.Expect(remediate).To(Equal(tt.wantRemediate))
+			g.Expect(next).To(Equal(tt.wantNext))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// inventoryKey is the key of the inventory in the inventory ConfigMap.
+const inventoryKey = "inventory.json"
+
+// inventory lists the OpenStack resources owned by an OpenStackCluster, as
+// exported in its inventory ConfigMap.
+type inventory struct {
+	Cluster   string              `json:"cluster"`
+	ProjectID string              `json:"projectID,omitempty"`
+	Resources []inventoryResource `json:"resources"`
+}
+
+type inventoryResource struct {
+	Kind string   `json:"kind"`
+	ID   string   `json:"id"`
+	Name string   `json:"name,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	// Owner is the OpenStackServer a server was created for.
+	Owner string `json:"owner,omitempty"`
+}
+
+// inventoryConfigMapName returns the name of the inventory ConfigMap of an
+// OpenStackCluster.
+func inventoryConfigMapName(openStackCluster *infrav1.OpenStackCluster) string {
+	return openStackCluster.Name + "-inventory"
+}
+
+// reconcileInventory exports the inventory of the OpenStack resources of the
+// cluster in its inventory ConfigMap while the InventoryAnnotation is set, and
+// deletes the ConfigMap once the annotation is removed. The ConfigMap is owned
+// by the OpenStackCluster, so that it is deleted together with it.
+func (r *OpenStackClusterReconciler) reconcileInventory(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	key := client.ObjectKey{Namespace: openStackCluster.Namespace, Name: inventoryConfigMapName(openStackCluster)}
+
+	if openStackCluster.Annotations[infrav1.InventoryAnnotation] != "true" {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, key, configMap); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(configMap, openStackCluster) {
+			return nil
+		}
+		if err := r.Client.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete inventory ConfigMap %s", key)
+		}
+		return nil
+	}
+
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+
+	servers := &infrav1.OpenStackServerList{}
+	if err := r.Client.List(ctx, servers, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return errors.Wrap(err, "failed to list OpenStackServers")
+	}
+
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		return err
+	}
+	portList, err := networkingService.ListPortsForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	trunkList, err := networkingService.ListTrunksForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	fipList, err := networkingService.ListFloatingIPsForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	inv := clusterInventory(openStackCluster, servers.Items, portList, trunkList, fipList)
+	inv.Cluster = clusterName
+	inv.ProjectID = scope.ProjectID
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal inventory")
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[clusterv1.ClusterLabelName] = cluster.Name
+		configMap.Data = map[string]string{inventoryKey: string(data)}
+		return controllerutil.SetControllerReference(openStackCluster, configMap, r.Client.Scheme())
+	}); err != nil {
+		return errors.Wrapf(err, "failed to export inventory ConfigMap %s", key)
+	}
+	return nil
+}
+
+// clusterInventory lists the OpenStack resources recorded in the status of the
+// OpenStackCluster, the servers of its OpenStackServers and the ports, trunks
+// and floating IPs created for it. Resources are sorted by kind and ID, so
+// that the inventory only changes with the resources.
+func clusterInventory(openStackCluster *infrav1.OpenStackCluster, servers []infrav1.OpenStackServer, portList []ports.Port, trunkList []trunks.Trunk, fipList []floatingips.FloatingIP) inventory {
+	var resources []inventoryResource
+	add := func(kind, id, name string, tags []string) {
+		if id != "" {
+			resources = append(resources, inventoryResource{Kind: kind, ID: id, Name: name, Tags: tags})
+		}
+	}
+
+	status := openStackCluster.Status
+	// The network, subnet and router are only created if NodeCIDR is set
+	if network := status.Network; network != nil && openStackCluster.Spec.NodeCIDR != "" {
+		add("Network", network.ID, network.Name, network.Tags)
+		if network.Subnet != nil {
+			add("Subnet", network.Subnet.ID, network.Subnet.Name, network.Subnet.Tags)
+		}
+		if network.Router != nil {
+			add("Router", network.Router.ID, network.Router.Name, network.Router.Tags)
+		}
+	}
+	if network := status.Network; network != nil && network.APIServerLoadBalancer != nil {
+		add("LoadBalancer", network.APIServerLoadBalancer.ID, network.APIServerLoadBalancer.Name, nil)
+	}
+	for _, group := range []*infrav1.SecurityGroup{status.ControlPlaneSecurityGroup, status.WorkerSecurityGroup, status.BastionSecurityGroup} {
+		if group != nil {
+			add("SecurityGroup", group.ID, group.Name, openStackCluster.Spec.Tags)
+		}
+	}
+	if status.Bastion != nil {
+		add("Server", status.Bastion.ID, status.Bastion.Name, status.Bastion.Tags)
+	}
+	for i := range servers {
+		server := &servers[i]
+		if server.Status.InstanceID == nil {
+			continue
+		}
+		resources = append(resources, inventoryResource{
+			Kind:  "Server",
+			ID:    *server.Status.InstanceID,
+			Name:  server.Name,
+			Tags:  server.Spec.Tags,
+			Owner: "OpenStackServer/" + server.Name,
+		})
+	}
+	for _, port := range portList {
+		add("Port", port.ID, port.Name, port.Tags)
+	}
+	for _, trunk := range trunkList {
+		add("Trunk", trunk.ID, trunk.Name, trunk.Tags)
+	}
+	for _, fip := range fipList {
+		add("FloatingIP", fip.ID, fip.FloatingIP, fip.Tags)
+	}
+	if share := status.Share; share != nil {
+		add("Share", share.ID, share.Name, nil)
+		add("ShareNetwork", share.ShareNetworkID, "", nil)
+		add("AccessRule", share.AccessRuleID, "", nil)
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		return resources[i].ID < resources[j].ID
+	})
+	return inventory{Resources: resources}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_clusterInventory(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			NodeCIDR: "10.6.0.0/24",
+			Tags:     []string{"billing"},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{
+				ID:                    "network-id",
+				Name:                  "network",
+				Tags:                  []string{"billing"},
+				Subnet:                &infrav1.Subnet{ID: "subnet-id", Name: "subnet"},
+				Router:                &infrav1.Router{ID: "router-id", Name: "router"},
+				APIServerLoadBalancer: &infrav1.LoadBalancer{ID: "lb-id", Name: "lb"},
+			},
+			ControlPlaneSecurityGroup: &infrav1.SecurityGroup{ID: "sg-b", Name: "control-plane"},
+			WorkerSecurityGroup:       &infrav1.SecurityGroup{ID: "sg-a", Name: "worker"},
+			Bastion:                   &infrav1.Instance{ID: "bastion-id", Name: "bastion"},
+			Share:                     &infrav1.Share{ID: "share-id", Name: "share", ShareNetworkID: "share-network-id"},
+		},
+	}
+	servers := []infrav1.OpenStackServer{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine"},
+			Spec:       infrav1.OpenStackServerSpec{Tags: []string{"billing", "machine"}},
+			Status:     infrav1.OpenStackServerStatus{InstanceID: pointer.StringPtr("server-id")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+		},
+	}
+
+	inv := clusterInventory(openStackCluster, servers,
+		[]ports.Port{{ID: "port-id", Name: "port", Tags: []string{"billing"}}},
+		[]trunks.Trunk{{ID: "trunk-id", Name: "trunk"}},
+		[]floatingips.FloatingIP{{ID: "fip-id", FloatingIP: "203.0.113.10"}})
+
+	g.Expect(inv.Resources).To(Equal([]inventoryResource{
+		{Kind: "FloatingIP", ID: "fip-id", Name: "203.0.113.10"},
+		{Kind: "LoadBalancer", ID: "lb-id", Name: "lb"},
+		{Kind: "Network", ID: "network-id", Name: "network", Tags: []string{"billing"}},
+		{Kind: "Port", ID: "port-id", Name: "port", Tags: []string{"billing"}},
+		{Kind: "Router", ID: "router-id", Name: "router"},
+		{Kind: "SecurityGroup", ID: "sg-a", Name: "worker", Tags: []string{"billing"}},
+		{Kind: "SecurityGroup", ID: "sg-b", Name: "control-plane", Tags: []string{"billing"}},
+		{Kind: "Server", ID: "bastion-id", Name: "bastion"},
+		{Kind: "Server", ID: "server-id", Name: "machine", Tags: []string{"billing", "machine"}, Owner: "OpenStackServer/machine"},
+		{Kind: "Share", ID: "share-id", Name: "share"},
+		{Kind: "ShareNetwork", ID: "share-network-id"},
+		{Kind: "Subnet", ID: "subnet-id", Name: "subnet"},
+		{Kind: "Trunk", ID: "trunk-id", Name: "trunk"},
+	}))
+
+	// A network which was not created for the cluster is not owned by it
+	openStackCluster.Spec.NodeCIDR = ""
+	inv = clusterInventory(openStackCluster, nil, nil, nil, nil)
+	for _, resource := range inv.Resources {
+		g.Expect(resource.Kind).NotTo(BeElementOf("Network", "Subnet", "Router"))
+	}
+}
+
+func Test_reconcileInventory_removesConfigMap(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	openStackCluster := &infrav1.OpenStackCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "test-namespace", UID: "cluster-uid"}}
+	inventoryConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      inventoryConfigMapName(openStackCluster),
+			Namespace: openStackCluster.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: infrav1.GroupVersion.String(), Kind: "OpenStackCluster", Name: openStackCluster.Name, UID: openStackCluster.UID, Controller: pointer.BoolPtr(true)},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(inventoryConfigMap).Build()
+	r := &OpenStackClusterReconciler{Client: fakeClient}
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "test-namespace"}}
+
+	// ConfigMaps not controlled by the OpenStackCluster are kept
+	other := openStackCluster.DeepCopy()
+	other.UID = "other-uid"
+	g.Expect(r.reconcileInventory(ctx, nil, cluster, other)).To(Succeed())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(inventoryConfigMap), &corev1.ConfigMap{})).To(Succeed())
+
+	g.Expect(r.reconcileInventory(ctx, nil, cluster, openStackCluster)).To(Succeed())
+	err := fakeClient.Get(ctx, client.ObjectKeyFromObject(inventoryConfigMap), &corev1.ConfigMap{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+	// Nothing to do without a ConfigMap
+	g.Expect(r.reconcileInventory(ctx, nil, cluster, openStackCluster)).To(Succeed())
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// reconcileLoadBalancerStats exposes the statistics of the listeners of the
+// API server load balancer as metrics, and requeues the cluster after the
+// poll interval to refresh them. Failing to get the statistics is logged and
+// does not fail the reconcile: the previous metrics are kept until the next
+// poll.
+func (r *OpenStackClusterReconciler) reconcileLoadBalancerStats(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster) ctrl.Result {
+	if r.LoadBalancerStatsInterval <= 0 || !openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		metrics.DeleteAPIServerLoadBalancerStats(openStackCluster.Namespace, openStackCluster.Name)
+		return ctrl.Result{}
+	}
+
+	loadBalancerService, err := loadbalancer.NewService(scope)
+	if err != nil {
+		scope.Logger.Info("Skipping load balancer statistics", "reason", err.Error())
+		return ctrl.Result{RequeueAfter: r.LoadBalancerStatsInterval}
+	}
+	listenerStats, err := loadBalancerService.GetAPIServerLoadBalancerStats(openStackCluster)
+	if err != nil {
+		scope.Logger.Info("Skipping load balancer statistics", "reason", err.Error())
+		return ctrl.Result{RequeueAfter: r.LoadBalancerStatsInterval}
+	}
+
+	stats := make([]metrics.LoadBalancerListenerStats, 0, len(listenerStats))
+	for _, s := range listenerStats {
+		stats = append(stats, metrics.LoadBalancerListenerStats{
+			Port:              s.Port,
+			ActiveConnections: s.ActiveConnections,
+			TotalConnections:  s.TotalConnections,
+			RequestErrors:     s.RequestErrors,
+			BytesIn:           s.BytesIn,
+			BytesOut:          s.BytesOut,
+		})
+	}
+	metrics.SetAPIServerLoadBalancerStats(openStackCluster.Namespace, openStackCluster.Name, stats)
+	return ctrl.Result{RequeueAfter: r.LoadBalancerStatsInterval}
+}
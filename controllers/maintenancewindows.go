@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+// bastionResource is the resource of the pending changes to the bastion.
+const bastionResource = "Bastion"
+
+// inMaintenanceWindow returns whether now is in one of the maintenance
+// windows, and otherwise how long it is until the next one starts. Without
+// maintenance windows, now is always in a window.
+func inMaintenanceWindow(windows []infrav1.MaintenanceWindow, now time.Time) (bool, time.Duration) {
+	if len(windows) == 0 {
+		return true, 0
+	}
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var next time.Duration
+	for i := range windows {
+		window := &windows[i]
+		start, err := time.Parse("15:04", window.Start)
+		if err != nil {
+			continue
+		}
+		// Windows are at most a day long, so a window which started
+		// yesterday may not be over yet
+		for day := -1; day <= 7; day++ {
+			windowStart := today.AddDate(0, 0, day).Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+			if !startsOn(window, windowStart.Weekday()) {
+				continue
+			}
+			if !now.Before(windowStart) && now.Before(windowStart.Add(window.Duration.Duration)) {
+				return true, 0
+			}
+			if windowStart.After(now) && (next == 0 || windowStart.Sub(now) < next) {
+				next = windowStart.Sub(now)
+			}
+		}
+	}
+	return false, next
+}
+
+// startsOn returns whether the maintenance window starts on the weekday.
+func startsOn(window *infrav1.MaintenanceWindow, weekday time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, day := range window.Days {
+		if string(day) == weekday.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// deferDisruptiveChange returns whether a disruptive change to a resource of
+// the cluster must be deferred because it is outside of the maintenance
+// windows of the cluster. Deferred changes are listed in the status of the
+// cluster until they are applied.
+func deferDisruptiveChange(openStackCluster *infrav1.OpenStackCluster, resource, change string, now time.Time) bool {
+	if ok, _ := inMaintenanceWindow(openStackCluster.Spec.MaintenanceWindows, now); ok {
+		removePendingChange(openStackCluster, resource)
+		return false
+	}
+
+	for i := range openStackCluster.Status.PendingChanges {
+		pending := &openStackCluster.Status.PendingChanges[i]
+		if pending.Resource == resource {
+			pending.Change = change
+			return true
+		}
+	}
+	openStackCluster.Status.PendingChanges = append(openStackCluster.Status.PendingChanges, infrav1.PendingChange{
+		Resource: resource,
+		Change:   change,
+		Since:    metav1.NewTime(now),
+	})
+	record.Eventf(openStackCluster, "DeferredChange", "Deferred %s of %s until the next maintenance window", change, resource)
+	return true
+}
+
+// removePendingChange removes the pending change of a resource of the
+// cluster, once it is applied or no longer needed.
+func removePendingChange(openStackCluster *infrav1.OpenStackCluster, resource string) {
+	pendingChanges := openStackCluster.Status.PendingChanges[:0]
+	for _, pending := range openStackCluster.Status.PendingChanges {
+		if pending.Resource != resource {
+			pendingChanges = append(pendingChanges, pending)
+		}
+	}
+	if len(pendingChanges) == 0 {
+		pendingChanges = nil
+	}
+	openStackCluster.Status.PendingChanges = pendingChanges
+}
+
+// reconcileMaintenanceWindows requeues a cluster with pending changes at the
+// start of its next maintenance window, so that they are applied then.
+func reconcileMaintenanceWindows(openStackCluster *infrav1.OpenStackCluster, now time.Time) ctrl.Result {
+	if len(openStackCluster.Status.PendingChanges) == 0 {
+		return ctrl.Result{}
+	}
+	if ok, next := inMaintenanceWindow(openStackCluster.Spec.MaintenanceWindows, now); !ok && next > 0 {
+		return ctrl.Result{RequeueAfter: next}
+	}
+	return ctrl.Result{}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_inMaintenanceWindow(t *testing.T) {
+	// Saturday nights from 22:00 to 04:00
+	saturdayNights := []infrav1.MaintenanceWindow{
+		{Days: []infrav1.Weekday{"Saturday"}, Start: "22:00", Duration: metav1.Duration{Duration: 6 * time.Hour}},
+	}
+	// 2022-09-03 is a Saturday
+	saturday := time.Date(2022, 9, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		windows  []infrav1.MaintenanceWindow
+		now      time.Time
+		wantIn   bool
+		wantNext time.Duration
+	}{
+		{
+			name:   "no maintenance windows",
+			now:    saturday,
+			wantIn: true,
+		},
+		{
+			name:     "before the window",
+			windows:  saturdayNights,
+			now:      saturday.Add(20 * time.Hour),
+			wantIn:   false,
+			wantNext: 2 * time.Hour,
+		},
+		{
+			name:    "in the window",
+			windows: saturdayNights,
+			now:     saturday.Add(23 * time.Hour),
+			wantIn:  true,
+		},
+		{
+			name:    "in the window after midnight",
+			windows: saturdayNights,
+			now:     saturday.Add(27 * time.Hour),
+			wantIn:  true,
+		},
+		{
+			name:     "after the window",
+			windows:  saturdayNights,
+			now:      saturday.Add(28 * time.Hour),
+			wantIn:   false,
+			wantNext: 7*24*time.Hour - 6*time.Hour,
+		},
+		{
+			name: "next of several windows",
+			windows: append([]infrav1.MaintenanceWindow{
+				{Start: "03:30", Duration: metav1.Duration{Duration: time.Hour}},
+			}, saturdayNights...),
+			now:      saturday.Add(20 * time.Hour),
+			wantIn:   false,
+			wantNext: 2 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			in, next := inMaintenanceWindow(tt.windows, tt.now)
+			g.Expect(in).To(Equal(tt.wantIn))
+			g.Expect(next).To(Equal(tt.wantNext))
+		})
+	}
+}
+
+func Test_deferDisruptiveChange(t *testing.T) {
+	g := NewWithT(t)
+
+	// 2022-09-03 is a Saturday
+	now := time.Date(2022, 9, 3, 12, 0, 0, 0, time.UTC)
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			MaintenanceWindows: []infrav1.MaintenanceWindow{
+				{Days: []infrav1.Weekday{"Sunday"}, Start: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			},
+		},
+	}
+
+	g.Expect(deferDisruptiveChange(openStackCluster, bastionResource, "rebuild", now)).To(BeTrue())
+	g.Expect(deferDisruptiveChange(openStackCluster, bastionResource, "rebuild", now.Add(time.Hour))).To(BeTrue())
+	g.Expect(openStackCluster.Status.PendingChanges).To(Equal([]infrav1.PendingChange{
+		{Resource: bastionResource, Change: "rebuild", Since: metav1.NewTime(now)},
+	}))
+	g.Expect(reconcileMaintenanceWindows(openStackCluster, now).RequeueAfter).To(Equal(14 * time.Hour))
+
+	g.Expect(deferDisruptiveChange(openStackCluster, bastionResource, "rebuild", now.Add(15*time.Hour))).To(BeFalse())
+	g.Expect(openStackCluster.Status.PendingChanges).To(BeEmpty())
+	g.Expect(reconcileMaintenanceWindows(openStackCluster, now)).To(Equal(ctrl.Result{}))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+const (
+	// nodeLabelsDropInName is the name of the kubelet drop-in which adds the
+	// node labels of a machine.
+	nodeLabelsDropInName = "90-capo-node-labels.conf"
+	kubeletUnitName      = "kubelet.service"
+)
+
+// machineNodeLabels returns the kubelet node labels of the machine. Labels
+// whose value is not a valid label value, e.g. a flavor name with spaces,
+// are skipped.
+func machineNodeLabels(openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec) map[string]string {
+	nodeLabels := openStackMachine.Spec.NodeLabels
+	if nodeLabels == nil {
+		return nil
+	}
+
+	labels := map[string]string{}
+	if nodeLabels.FailureDomain && instanceSpec.FailureDomain != "" {
+		labels[corev1.LabelTopologyZone] = instanceSpec.FailureDomain
+	}
+	if nodeLabels.Flavor {
+		labels[corev1.LabelInstanceTypeStable] = instanceSpec.Flavor
+	}
+	for _, key := range nodeLabels.Metadata {
+		if value, ok := openStackMachine.Spec.ServerMetadata[key]; ok {
+			labels[key] = value
+		}
+	}
+	for key, value := range labels {
+		if len(validation.IsValidLabelValue(value)) > 0 {
+			delete(labels, key)
+		}
+	}
+	return labels
+}
+
+// nodeLabelsDropIn returns a kubelet drop-in which adds labels to the
+// --node-labels flag of the kubelet started by kubeadm.
+func nodeLabelsDropIn(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return fmt.Sprintf("[Service]\nEnvironment=\"KUBELET_EXTRA_ARGS=--node-labels=%s\"\n", strings.Join(pairs, ","))
+}
+
+// nodeLabelsEdit adds a kubelet drop-in with the node labels. Cloud-configs
+// get the drop-in as an additional file, Ignition configs as a drop-in of the
+// kubelet unit.
+func nodeLabelsEdit(labels map[string]string) bootstrapDataEdit {
+	dropIn := nodeLabelsDropIn(labels)
+	return bootstrapDataEdit{
+		cloudConfig: func(cloudConfig map[string]interface{}) {
+			writeFiles, _ := cloudConfig["write_files"].([]interface{})
+			cloudConfig["write_files"] = append(writeFiles, map[string]interface{}{
+				"path":        "/etc/systemd/system/" + kubeletUnitName + ".d/" + nodeLabelsDropInName,
+				"owner":       "root:root",
+				"permissions": "0644",
+				"content":     dropIn,
+			})
+		},
+		ignition: func(config map[string]interface{}) {
+			systemd, _ := config["systemd"].(map[string]interface{})
+			if systemd == nil {
+				systemd = map[string]interface{}{}
+			}
+			units, _ := systemd["units"].([]interface{})
+
+			// Ignition rejects duplicate units, so the drop-in is added to an
+			// existing kubelet unit
+			var kubeletUnit map[string]interface{}
+			for _, unit := range units {
+				if u, ok := unit.(map[string]interface{}); ok && u["name"] == kubeletUnitName {
+					kubeletUnit = u
+				}
+			}
+			if kubeletUnit == nil {
+				kubeletUnit = map[string]interface{}{"name": kubeletUnitName}
+				units = append(units, kubeletUnit)
+			}
+			dropIns, _ := kubeletUnit["dropins"].([]interface{})
+			kubeletUnit["dropins"] = append(dropIns, map[string]interface{}{
+				"name":     nodeLabelsDropInName,
+				"contents": dropIn,
+			})
+			systemd["units"] = units
+			config["systemd"] = systemd
+		},
+	}
+}
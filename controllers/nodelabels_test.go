@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+func Test_machineNodeLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackMachine := &infrav1.OpenStackMachine{
+		Spec: infrav1.OpenStackMachineSpec{
+			ServerMetadata: map[string]string{"rack": "r1", "comment": "not a label value"},
+			NodeLabels: &infrav1.NodeLabels{
+				FailureDomain: true,
+				Flavor:        true,
+				Metadata:      []string{"rack", "comment"},
+			},
+		},
+	}
+	instanceSpec := &compute.InstanceSpec{Flavor: "m1.large", FailureDomain: "az1"}
+
+	g.Expect(machineNodeLabels(openStackMachine, instanceSpec)).To(Equal(map[string]string{
+		"topology.kubernetes.io/zone":      "az1",
+		"node.kubernetes.io/instance-type": "m1.large",
+		"rack":                             "r1",
+	}))
+	g.Expect(machineNodeLabels(&infrav1.OpenStackMachine{}, instanceSpec)).To(BeNil())
+}
+
+func Test_nodeLabelsEdit(t *testing.T) {
+	labels := map[string]string{"rack": "r1", "topology.kubernetes.io/zone": "az1"}
+
+	tests := []struct {
+		name          string
+		format        string
+		bootstrapData string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:   "Cloud-config with template header",
+			format: "cloud-config",
+			bootstrapData: `## template: jinja
+#cloud-config
+
+write_files:
+- path: /etc/kubernetes/pki/ca.crt
+  content: ca
+runcmd:
+- kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml
+`,
+			want: `## template: jinja
+#cloud-config
+runcmd:
+- kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml
+write_files:
+- content: ca
+  path: /etc/kubernetes/pki/ca.crt
+- content: |
+    [Service]
+    Environment="KUBELET_EXTRA_ARGS=--node-labels=rack=r1,topology.kubernetes.io/zone=az1"
+  owner: root:root
+  path: /etc/systemd/system/kubelet.service.d/90-capo-node-labels.conf
+  permissions: "0644"
+`,
+		},
+		{
+			name:          "Ignition with kubelet unit",
+			format:        "ignition",
+			bootstrapData: `{"ignition":{"version":"2.3.0"},"systemd":{"units":[{"name":"containerd.service","enabled":true},{"name":"kubelet.service","enabled":true}]}}`,
+			want:          `{"ignition":{"version":"2.3.0"},"systemd":{"units":[{"enabled":true,"name":"containerd.service"},{"dropins":[{"contents":"[Service]\nEnvironment=\"KUBELET_EXTRA_ARGS=--node-labels=rack=r1,topology.kubernetes.io/zone=az1\"\n","name":"90-capo-node-labels.conf"}],"enabled":true,"name":"kubelet.service"}]}}`,
+		},
+		{
+			name:          "Ignition without units",
+			format:        "ignition",
+			bootstrapData: `{"ignition":{"version":"2.3.0"}}`,
+			want:          `{"ignition":{"version":"2.3.0"},"systemd":{"units":[{"dropins":[{"contents":"[Service]\nEnvironment=\"KUBELET_EXTRA_ARGS=--node-labels=rack=r1,topology.kubernetes.io/zone=az1\"\n","name":"90-capo-node-labels.conf"}],"name":"kubelet.service"}]}}`,
+		},
+		{
+			name:          "Shell script",
+			format:        "cloud-config",
+			bootstrapData: "#!/bin/bash\nkubeadm join\n",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := editBootstrapData(tt.format, []byte(tt.bootstrapData), []bootstrapDataEdit{nodeLabelsEdit(labels)})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
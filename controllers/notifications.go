@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+const (
+	// machineInstanceIDField indexes OpenStackMachines by the ID of their
+	// instance.
+	machineInstanceIDField = "spec.instanceID"
+	// clusterBastionIDField indexes OpenStackClusters by the ID of the
+	// instance of their bastion.
+	clusterBastionIDField = "status.bastion.id"
+
+	// instanceEventsBufferSize is the number of reconcile triggers buffered
+	// until the controllers consume them.
+	instanceEventsBufferSize = 1024
+)
+
+// InstanceNotificationTrigger triggers the reconcile of the OpenStackMachine
+// or OpenStackCluster owning an instance when a notification about the
+// instance or one of its ports is received.
+type InstanceNotificationTrigger struct {
+	client        client.Client
+	machineEvents chan event.GenericEvent
+	clusterEvents chan event.GenericEvent
+}
+
+// NewInstanceNotificationTrigger returns an InstanceNotificationTrigger and
+// adds the indexes it needs to the cache of the manager.
+func NewInstanceNotificationTrigger(ctx context.Context, mgr ctrl.Manager) (*InstanceNotificationTrigger, error) {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &infrav1.OpenStackMachine{}, machineInstanceIDField, machineInstanceIDs); err != nil {
+		return nil, errors.Wrap(err, "failed to index OpenStackMachines by instance ID")
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &infrav1.OpenStackCluster{}, clusterBastionIDField, clusterBastionIDs); err != nil {
+		return nil, errors.Wrap(err, "failed to index OpenStackClusters by bastion ID")
+	}
+	return &InstanceNotificationTrigger{
+		client:        mgr.GetClient(),
+		machineEvents: make(chan event.GenericEvent, instanceEventsBufferSize),
+		clusterEvents: make(chan event.GenericEvent, instanceEventsBufferSize),
+	}, nil
+}
+
+// MachineEvents returns the reconcile triggers of the OpenStackMachines.
+func (t *InstanceNotificationTrigger) MachineEvents() <-chan event.GenericEvent {
+	return t.machineEvents
+}
+
+// ClusterEvents returns the reconcile triggers of the OpenStackClusters.
+func (t *InstanceNotificationTrigger) ClusterEvents() <-chan event.GenericEvent {
+	return t.clusterEvents
+}
+
+// HandleInstance triggers the reconcile of the OpenStackMachines and
+// OpenStackClusters owning the instance. Instances not owned by any of them
+// are ignored.
+func (t *InstanceNotificationTrigger) HandleInstance(ctx context.Context, instanceID string) error {
+	machines := &infrav1.OpenStackMachineList{}
+	if err := t.client.List(ctx, machines, client.MatchingFields{machineInstanceIDField: instanceID}); err != nil {
+		return errors.Wrap(err, "failed to list OpenStackMachines")
+	}
+	for i := range machines.Items {
+		if err := sendEvent(ctx, t.machineEvents, &machines.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	clusters := &infrav1.OpenStackClusterList{}
+	if err := t.client.List(ctx, clusters, client.MatchingFields{clusterBastionIDField: instanceID}); err != nil {
+		return errors.Wrap(err, "failed to list OpenStackClusters")
+	}
+	for i := range clusters.Items {
+		if err := sendEvent(ctx, t.clusterEvents, &clusters.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendEvent(ctx context.Context, events chan<- event.GenericEvent, obj client.Object) error {
+	select {
+	case events <- event.GenericEvent{Object: obj}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func machineInstanceIDs(o client.Object) []string {
+	openStackMachine, ok := o.(*infrav1.OpenStackMachine)
+	if !ok || openStackMachine.Spec.InstanceID == nil || *openStackMachine.Spec.InstanceID == "" {
+		return nil
+	}
+	return []string{*openStackMachine.Spec.InstanceID}
+}
+
+func clusterBastionIDs(o client.Object) []string {
+	openStackCluster, ok := o.(*infrav1.OpenStackCluster)
+	if !ok || openStackCluster.Status.Bastion == nil || openStackCluster.Status.Bastion.ID == "" {
+		return nil
+	}
+	return []string{openStackCluster.Status.Bastion.ID}
+}
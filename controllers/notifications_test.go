@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_instanceIndexes(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(machineInstanceIDs(&infrav1.OpenStackMachine{})).To(BeEmpty())
+	g.Expect(machineInstanceIDs(&infrav1.OpenStackMachine{
+		Spec: infrav1.OpenStackMachineSpec{InstanceID: pointer.String("instance-id")},
+	})).To(Equal([]string{"instance-id"}))
+
+	g.Expect(clusterBastionIDs(&infrav1.OpenStackCluster{})).To(BeEmpty())
+	g.Expect(clusterBastionIDs(&infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{Bastion: &infrav1.Instance{ID: "bastion-id"}},
+	})).To(Equal([]string{"bastion-id"}))
+}
+
+func Test_sendEvent(t *testing.T) {
+	g := NewWithT(t)
+
+	events := make(chan event.GenericEvent, 1)
+	openStackMachine := &infrav1.OpenStackMachine{}
+	g.Expect(sendEvent(context.TODO(), events, openStackMachine)).To(Succeed())
+	g.Expect(<-events).To(Equal(event.GenericEvent{Object: openStackMachine}))
+
+	// A full channel does not block requests which are cancelled
+	events <- event.GenericEvent{}
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	g.Expect(sendEvent(ctx, events, openStackMachine)).To(MatchError(context.Canceled))
+}
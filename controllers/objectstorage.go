@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/objectstorage"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const (
+	// maxUserDataSize is the maximum size of the base64 encoded user data
+	// of an instance accepted by Nova.
+	maxUserDataSize = 65535
+
+	// bootstrapTempURLTTL is how long the temporary URL of offloaded
+	// bootstrap data is valid. cloud-init only fetches it on the first boot
+	// of the instance.
+	bootstrapTempURLTTL = 24 * time.Hour
+)
+
+// reconcileObjectStorage ensures that the Swift container of the cluster
+// exists, or deletes it with all its objects if the cluster no longer has
+// one.
+func reconcileObjectStorage(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+
+	if openStackCluster.Spec.ObjectStorage == nil {
+		if openStackCluster.Status.ObjectStorage != nil {
+			if err := deleteObjectStorage(scope, openStackCluster, clusterName); err != nil {
+				return err
+			}
+		}
+		conditions.Delete(openStackCluster, infrav1.ObjectStorageReadyCondition)
+		return nil
+	}
+
+	scope.Logger.Info("Reconciling object storage")
+
+	objectStorageService, err := objectstorage.NewService(scope)
+	if err != nil {
+		return err
+	}
+
+	if err := objectStorageService.ReconcileContainer(openStackCluster, clusterName); err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ObjectStorageReadyCondition, infrav1.ObjectStorageCreateFailedReason, clusterv1.ConditionSeverityWarning, "Reconciling container failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile container")
+	}
+
+	conditions.MarkTrue(openStackCluster, infrav1.ObjectStorageReadyCondition)
+	return nil
+}
+
+func deleteObjectStorage(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	objectStorageService, err := objectstorage.NewService(scope)
+	if err != nil {
+		return err
+	}
+	if err := objectStorageService.DeleteContainer(openStackCluster, clusterName); err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ObjectStorageReadyCondition, infrav1.ObjectStorageDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting container failed: %v", err)
+		return errors.Wrap(err, "failed to delete container")
+	}
+	return nil
+}
+
+// bootstrapObjectName returns the name of the object holding the bootstrap
+// data of the machine in the container of the cluster.
+func bootstrapObjectName(openStackMachine *infrav1.OpenStackMachine) string {
+	return "bootstrap/" + openStackMachine.Name
+}
+
+// consoleLogObjectName returns the name of the object holding the console
+// log of the machine taken at now.
+func consoleLogObjectName(openStackMachine *infrav1.OpenStackMachine, now time.Time) string {
+	return fmt.Sprintf("console/%s-%d.log", openStackMachine.Name, now.Unix())
+}
+
+// userDataSecretName returns the name of the Secret holding the user data
+// of the machine if it differs from its bootstrap data.
+func userDataSecretName(openStackMachine *infrav1.OpenStackMachine) string {
+	return openStackMachine.Name + "-userdata"
+}
+
+// needsOffload returns whether the bootstrap data of a machine exceeds the
+// user data limit of Nova and can be included by cloud-init from a URL.
+// Ignition has no equivalent of the include of cloud-init.
+func needsOffload(bootstrapSecret *corev1.Secret) bool {
+	if string(bootstrapSecret.Data["format"]) == "ignition" {
+		return false
+	}
+	return base64.StdEncoding.EncodedLen(len(bootstrapSecret.Data["value"])) > maxUserDataSize
+}
+
+// reconcileBootstrapObject prepares the user data of the machine from its
+// bootstrap data. The node labels and SSH authorized keys of the machine are
+// added to the bootstrap data, which is stored in the container of the cluster if it is too large
+// to be passed as user data, or if the cluster keeps audit copies of it. Too
+// large bootstrap data is replaced by user data which makes cloud-init
+// include it from a temporary URL. If the user data differs from the
+// bootstrap data, the reference to the Secret holding it is returned. It
+// returns nil if the bootstrap data is passed as is.
+func (r *OpenStackMachineReconciler) reconcileBootstrapObject(ctx context.Context, scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec) (*corev1.LocalObjectReference, error) {
+	if machine.Spec.Bootstrap.DataSecretName == nil {
+		return nil, nil
+	}
+	objectStorageStatus := openStackCluster.Status.ObjectStorage
+	objectStorage := openStackCluster.Spec.ObjectStorage != nil && objectStorageStatus != nil
+	edits := machineBootstrapDataEdits(openStackMachine, instanceSpec)
+	if !objectStorage && len(edits) == 0 {
+		return nil, nil
+	}
+
+	bootstrapSecret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: openStackMachine.Namespace, Name: *machine.Spec.Bootstrap.DataSecretName}
+	if err := r.Client.Get(ctx, key, bootstrapSecret); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve bootstrap data secret %s", key.Name)
+	}
+
+	if len(edits) > 0 {
+		bootstrapData, err := editBootstrapData(string(bootstrapSecret.Data["format"]), bootstrapSecret.Data["value"], edits)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to change bootstrap data")
+		}
+		bootstrapSecret.Data["value"] = bootstrapData
+	}
+	userData := bootstrapSecret.Data["value"]
+
+	offload := objectStorage && needsOffload(bootstrapSecret)
+	if offload || (objectStorage && openStackCluster.Spec.ObjectStorage.AuditBootstrapData) {
+		objectStorageService, err := objectstorage.NewService(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		objectName := bootstrapObjectName(openStackMachine)
+		if err := objectStorageService.StoreObject(openStackMachine, objectStorageStatus.ContainerName, objectName, "text/plain", userData); err != nil {
+			return nil, errors.Wrap(err, "failed to store bootstrap data")
+		}
+
+		if offload {
+			url, err := objectStorageService.GetTempURL(objectStorageStatus.ContainerName, objectName, bootstrapTempURLTTL)
+			if err != nil {
+				return nil, err
+			}
+			scope.Logger.Info("Bootstrap data exceeds the user data limit, including it from object storage", "object", objectName)
+			userData = []byte(fmt.Sprintf("#include\n%s\n", url))
+		}
+	}
+	if !offload && len(edits) == 0 {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      userDataSecretName(openStackMachine),
+			Namespace: openStackMachine.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Labels = map[string]string{
+			clusterv1.ClusterLabelName: machine.Spec.ClusterName,
+		}
+		secret.Type = clusterv1.ClusterSecretType
+		secret.Data = map[string][]byte{
+			"value": userData,
+		}
+		return controllerutil.SetControllerReference(openStackMachine, secret, r.Client.Scheme())
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to create user data secret %s", secret.Name)
+	}
+	return &corev1.LocalObjectReference{Name: secret.Name}, nil
+}
+
+// deleteBootstrapObject deletes the bootstrap data of the machine from the
+// container of the cluster, unless the cluster keeps audit copies of it.
+func deleteBootstrapObject(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) error {
+	objectStorageStatus := openStackCluster.Status.ObjectStorage
+	if objectStorageStatus == nil || (openStackCluster.Spec.ObjectStorage != nil && openStackCluster.Spec.ObjectStorage.AuditBootstrapData) {
+		return nil
+	}
+
+	objectStorageService, err := objectstorage.NewService(scope)
+	if err != nil {
+		return err
+	}
+	return objectStorageService.DeleteObject(openStackMachine, objectStorageStatus.ContainerName, bootstrapObjectName(openStackMachine))
+}
+
+// storeConsoleLog stores the console log of the failed instance of the
+// machine in the container of the cluster, if the cluster keeps console
+// logs. Failures are only logged, as they must not hide the failure of the
+// machine.
+func storeConsoleLog(scope *scope.Scope, computeService *compute.Service, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instanceID string) {
+	objectStorageStatus := openStackCluster.Status.ObjectStorage
+	if openStackCluster.Spec.ObjectStorage == nil || !openStackCluster.Spec.ObjectStorage.StoreConsoleLogs || objectStorageStatus == nil {
+		return
+	}
+
+	output, err := computeService.GetConsoleOutput(instanceID)
+	if err != nil {
+		scope.Logger.Error(err, "Failed to get console log of failed instance")
+		return
+	}
+
+	objectStorageService, err := objectstorage.NewService(scope)
+	if err != nil {
+		scope.Logger.Error(err, "Failed to store console log of failed instance")
+		return
+	}
+	objectName := consoleLogObjectName(openStackMachine, time.Now())
+	if err := objectStorageService.StoreObject(openStackMachine, objectStorageStatus.ContainerName, objectName, "text/plain", []byte(output)); err != nil {
+		scope.Logger.Error(err, "Failed to store console log of failed instance")
+		return
+	}
+	scope.Logger.Info("Stored console log of failed instance", "object", objectName)
+}
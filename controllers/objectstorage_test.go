@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_needsOffload(t *testing.T) {
+	// The base64 encoding of 49152 bytes is 65536 bytes long
+	tooLarge := bytes.Repeat([]byte("a"), 49152)
+	fitting := bytes.Repeat([]byte("a"), 49149)
+
+	tests := []struct {
+		name string
+		data map[string][]byte
+		want bool
+	}{
+		{
+			name: "fitting cloud-config",
+			data: map[string][]byte{"value": fitting, "format": []byte("cloud-config")},
+			want: false,
+		},
+		{
+			name: "too large cloud-config",
+			data: map[string][]byte{"value": tooLarge, "format": []byte("cloud-config")},
+			want: true,
+		},
+		{
+			name: "too large bootstrap data without format",
+			data: map[string][]byte{"value": tooLarge},
+			want: true,
+		},
+		{
+			name: "too large ignition",
+			data: map[string][]byte{"value": tooLarge, "format": []byte("ignition")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(needsOffload(&corev1.Secret{Data: tt.data})).To(Equal(tt.want))
+		})
+	}
+}
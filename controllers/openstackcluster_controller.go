@@ -18,18 +18,27 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -43,30 +52,69 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/objectstorage"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/share"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/dryrun"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/httpdebug"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	caporecord "sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/parallel"
 )
 
 const (
 	BastionInstanceHashAnnotation = "infrastructure.cluster.x-k8s.io/bastion-hash"
+
+	deletionInProgressRequeueDuration = 10 * time.Second
 )
 
+// errDeletionInProgress is returned when OpenStack resources of a cluster
+// cannot be deleted yet, because they are still in use by resources which
+// are being deleted.
+var errDeletionInProgress = errors.New("deletion in progress")
+
 // OpenStackClusterReconciler reconciles a OpenStackCluster object.
 type OpenStackClusterReconciler struct {
 	Client           client.Client
 	Recorder         record.EventRecorder
 	WatchFilterValue string
+	// ResyncPeriod is the interval at which the OpenStack resources of a
+	// cluster are reconciled even if the OpenStackCluster has not changed, to
+	// detect and repair out-of-band changes. Zero disables the resync.
+	ResyncPeriod time.Duration
+	// LoadBalancerStatsInterval is the interval at which the statistics of
+	// the listeners of the API server load balancer are exposed as metrics.
+	// Zero disables the statistics.
+	LoadBalancerStatsInterval time.Duration
+	// ResourceMetricsInterval is the interval at which the number of
+	// OpenStack resources managed for a cluster and the usage of the quotas of
+	// its project are exposed as metrics. Zero disables the metrics.
+	ResourceMetricsInterval time.Duration
+	// InstanceEvents triggers the reconcile of clusters whose bastion
+	// instance changed, e.g. on notifications from the cloud. Nil disables
+	// them.
+	InstanceEvents <-chan event.GenericEvent
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
 func (r *OpenStackClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, span := tracing.StartReconcile(ctx, "OpenStackCluster", req)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	log := ctrl.LoggerFrom(ctx)
 
 	// Fetch the OpenStackCluster instance
@@ -104,13 +152,39 @@ func (r *OpenStackClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	// Always patch the openStackCluster when exiting this function so we can persist any OpenStackCluster changes.
 	defer func() {
-		if err := patchHelper.Patch(ctx, openStackCluster); err != nil {
+		var patchOpts []patch.Option
+		// The spec is only observed once it was reconciled successfully
+		if reterr == nil {
+			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
+		}
+		if err := patchCluster(ctx, patchHelper, openStackCluster, patchOpts...); err != nil {
 			if reterr == nil {
 				reterr = errors.Wrapf(err, "error patching OpenStackCluster %s/%s", openStackCluster.Namespace, openStackCluster.Name)
 			}
 		}
 	}()
 
+	// Externally managed infrastructure is not owned by us, so there is nothing to clean up
+	if annotations.IsExternallyManaged(openStackCluster) && !openStackCluster.DeletionTimestamp.IsZero() {
+		controllerutil.RemoveFinalizer(openStackCluster, infrav1.ClusterFinalizer)
+		return reconcile.Result{}, nil
+	}
+
+	if openStackCluster.Annotations[infrav1.DebugHTTPAnnotation] == "true" {
+		ctx = httpdebug.WithLogging(ctrl.LoggerInto(ctx, log))
+	}
+
+	dryRun := openStackCluster.Annotations[infrav1.DryRunAnnotation] == "true"
+	if dryRun {
+		ctx = dryrun.WithPlan(ctx)
+	} else {
+		openStackCluster.Status.DryRunPlan = nil
+	}
+
+	if err := ensureIdentitySecretIsMoved(ctx, r.Client, openStackCluster.Namespace, openStackCluster.Spec.IdentityRef); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	osProviderClient, clientOpts, projectID, err := provider.NewClientFromCluster(ctx, r.Client, openStackCluster)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -121,73 +195,316 @@ func (r *OpenStackClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		ProviderClientOpts: clientOpts,
 		ProjectID:          projectID,
 		Logger:             log,
+		Timeouts:           reconcileTimeouts(openStackCluster),
+	}
+
+	// Re-adopt the OpenStack resources of a cluster recreated by clusterctl move
+	if isMoved(openStackCluster) {
+		if err := reconcileMovedCluster(scope, openStackCluster); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Compute the changes to OpenStack resources without applying them
+	if dryRun {
+		return r.withResync(reconcileDryRun(ctx, scope, cluster, openStackCluster))
+	}
+
+	// Handle externally managed clusters
+	if annotations.IsExternallyManaged(openStackCluster) {
+		return r.withResync(reconcileExternallyManaged(scope, openStackCluster))
 	}
 
 	// Handle deleted clusters
 	if !openStackCluster.DeletionTimestamp.IsZero() {
+		metrics.DeleteAPIServerLoadBalancerStats(openStackCluster.Namespace, openStackCluster.Name)
+		metrics.DeleteClusterResources(openStackCluster.Namespace, openStackCluster.Name)
 		return reconcileDelete(ctx, scope, patchHelper, cluster, openStackCluster)
 	}
 
 	// Handle non-deleted clusters
-	return reconcileNormal(ctx, scope, patchHelper, cluster, openStackCluster)
+	floatingIPs, err := r.reconcileFloatingIPClaims(ctx, cluster, openStackCluster)
+	if err != nil || floatingIPs == nil {
+		return r.withResync(ctrl.Result{}, err)
+	}
+	// The node ports of the Services are opened by reconcileNormal
+	serviceNodePortsResult, serviceNodePortsErr := r.reconcileServiceNodePorts(ctx, scope, cluster, openStackCluster)
+	result, err := reconcileNormal(ctx, scope, patchHelper, cluster, openStackCluster, floatingIPs)
+	result = util.LowestNonZeroResult(result, serviceNodePortsResult)
+	result = util.LowestNonZeroResult(result, reconcileMaintenanceWindows(openStackCluster, time.Now()))
+	if err == nil {
+		err = serviceNodePortsErr
+	}
+	if err == nil {
+		err = r.reconcileShareSecret(ctx, scope, cluster, openStackCluster)
+	}
+	if err == nil {
+		err = r.reconcileAddons(ctx, scope, cluster, openStackCluster)
+	}
+	if err == nil {
+		var instanceHAResult ctrl.Result
+		instanceHAResult, err = r.reconcileInstanceHA(ctx, scope, cluster, openStackCluster)
+		result = util.LowestNonZeroResult(result, instanceHAResult)
+	}
+	if err == nil {
+		var hostMaintenanceResult ctrl.Result
+		hostMaintenanceResult, err = r.reconcileHostMaintenance(ctx, scope, cluster, openStackCluster)
+		result = util.LowestNonZeroResult(result, hostMaintenanceResult)
+	}
+	if err == nil {
+		err = r.reconcileInventory(ctx, scope, cluster, openStackCluster)
+	}
+	if err == nil {
+		result = util.LowestNonZeroResult(result, r.reconcileLoadBalancerStats(scope, openStackCluster))
+		result = util.LowestNonZeroResult(result, r.reconcileResourceMetrics(scope, cluster, openStackCluster))
+		r.reconcileOrphanedVolumes(ctx, scope, cluster, openStackCluster)
+	}
+	return r.withResync(result, ignorePermanentError(log, err))
+}
+
+func patchCluster(ctx context.Context, patchHelper *patch.Helper, openStackCluster *infrav1.OpenStackCluster, options ...patch.Option) error {
+	// Always update the readyCondition by summarizing the state of other conditions.
+	// Conditions of disabled components are removed, so they are not considered.
+	conditions.SetSummary(openStackCluster,
+		conditions.WithConditions(
+			infrav1.NetworkReadyCondition,
+			infrav1.SecurityGroupsReadyCondition,
+			infrav1.LoadBalancerReadyCondition,
+			infrav1.BastionReadyCondition,
+			infrav1.ShareReadyCondition,
+			infrav1.ObjectStorageReadyCondition,
+		),
+	)
+
+	// Patch the object, ignoring conflicts on the conditions owned by this controller.
+	options = append(options,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.NetworkReadyCondition,
+			infrav1.SecurityGroupsReadyCondition,
+			infrav1.LoadBalancerReadyCondition,
+			infrav1.BastionReadyCondition,
+			infrav1.ShareReadyCondition,
+			infrav1.ObjectStorageReadyCondition,
+			infrav1.AddonsReadyCondition,
+			infrav1.InstanceHAReadyCondition,
+			infrav1.HostMaintenanceReadyCondition,
+			infrav1.ServiceNodePortsReadyCondition,
+			infrav1.QuotaExceededCondition,
+			infrav1.ClusterNetworkConflictCondition,
+		}},
+	)
+	ctx, cancel := patchContext(ctx)
+	defer cancel()
+	return patchHelper.Patch(ctx, openStackCluster, options...)
+}
+
+// withResync requeues a successfully reconciled cluster after the resync
+// period. Status-only updates are filtered out by the event filter, so
+// without this out-of-band changes to OpenStack resources would only be
+// noticed when the OpenStackCluster is changed.
+func (r *OpenStackClusterReconciler) withResync(result ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil && result.IsZero() && r.ResyncPeriod > 0 {
+		result.RequeueAfter = r.ResyncPeriod
+	}
+	return result, err
 }
 
 func reconcileDelete(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
 	scope.Logger.Info("Reconciling Cluster delete")
 
-	if err := deleteBastion(scope, cluster, openStackCluster); err != nil {
+	if err := deleteClusterResources(scope, cluster, openStackCluster); err != nil {
+		if errors.Is(err, errDeletionInProgress) {
+			scope.Logger.Info("Waiting for OpenStack resources to be released", "reason", err.Error())
+			return reconcile.Result{RequeueAfter: deletionInProgressRequeueDuration}, nil
+		}
 		return reconcile.Result{}, err
 	}
 
+	// Cluster is deleted so remove the finalizer.
+	controllerutil.RemoveFinalizer(openStackCluster, infrav1.ClusterFinalizer)
+	scope.Logger.Info("Reconciled Cluster delete successfully")
+	if err := patchHelper.Patch(ctx, openStackCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// clusterDeletionStep deletes the OpenStack resources of one kind of a cluster.
+type clusterDeletionStep struct {
+	resources string
+	condition clusterv1.ConditionType
+	reason    string
+	delete    func() error
+}
+
+// deleteClusterResources deletes the OpenStack resources of the cluster in the
+// order of their dependencies: the bastion, the share and the load balancer
+// release their ports, the ports release the floating IPs and security
+// groups, and the router interfaces and ports release the network. A step
+// only starts once the previous steps are complete. If OpenStack reports a
+// resource as still in use, the resources using it are still being released
+// and errDeletionInProgress is returned, so that the deletion is retried
+// later.
+func deleteClusterResources(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	if err := deleteBastion(scope, cluster, openStackCluster); err != nil {
+		return err
+	}
+
 	networkingService, err := networking.NewService(scope)
 	if err != nil {
-		return reconcile.Result{}, err
+		return err
 	}
 
 	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
 
-	if err = networkingService.DeletePorts(openStackCluster); err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete ports: %v", err))
-		return reconcile.Result{}, errors.Wrap(err, "failed to delete ports")
+	var steps []clusterDeletionStep
+	// The share network has ports on the cluster subnet
+	if openStackCluster.Spec.Share != nil || openStackCluster.Status.Share != nil {
+		steps = append(steps, clusterDeletionStep{
+			resources: "share",
+			condition: infrav1.ShareReadyCondition,
+			reason:    infrav1.ShareDeleteFailedReason,
+			delete: func() error {
+				shareService, err := share.NewService(scope)
+				if err != nil {
+					return err
+				}
+				return shareService.DeleteShare(openStackCluster, clusterName)
+			},
+		})
+	}
+	if openStackCluster.Spec.ObjectStorage != nil || openStackCluster.Status.ObjectStorage != nil {
+		steps = append(steps, clusterDeletionStep{
+			resources: "object storage",
+			condition: infrav1.ObjectStorageReadyCondition,
+			reason:    infrav1.ObjectStorageDeleteFailedReason,
+			delete: func() error {
+				objectStorageService, err := objectstorage.NewService(scope)
+				if err != nil {
+					return err
+				}
+				return objectStorageService.DeleteContainer(openStackCluster, clusterName)
+			},
+		})
 	}
-
 	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
 		loadBalancerService, err := loadbalancer.NewService(scope)
 		if err != nil {
-			return reconcile.Result{}, err
-		}
-
-		if err = loadBalancerService.DeleteLoadBalancer(openStackCluster, clusterName); err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete load balancer: %v", err))
-			return reconcile.Result{}, errors.Errorf("failed to delete load balancer: %v", err)
+			return err
 		}
+		steps = append(steps, clusterDeletionStep{
+			resources: "load balancer",
+			condition: infrav1.LoadBalancerReadyCondition,
+			reason:    infrav1.LoadBalancerDeleteFailedReason,
+			delete: func() error {
+				return loadBalancerService.DeleteLoadBalancer(openStackCluster, clusterName)
+			},
+		})
 	}
 
-	if err = networkingService.DeleteSecurityGroups(openStackCluster, clusterName); err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete security groups: %v", err))
-		return reconcile.Result{}, errors.Errorf("failed to delete security groups: %v", err)
+	steps = append(steps, clusterDeletionStep{
+		resources: "ports",
+		condition: infrav1.NetworkReadyCondition,
+		reason:    infrav1.NetworkDeleteFailedReason,
+		delete: func() error {
+			if err := networkingService.DeletePorts(openStackCluster); err != nil {
+				return err
+			}
+			// Ports left behind by interrupted creations or deletions
+			// of servers are not referenced anywhere
+			return networkingService.DeleteOrphanedPorts(openStackCluster, clusterName)
+		},
+	})
+	if !openStackCluster.RetainsFloatingIPs() {
+		steps = append(steps, clusterDeletionStep{
+			resources: "floating IPs",
+			condition: infrav1.NetworkReadyCondition,
+			reason:    infrav1.FloatingIPDeleteFailedReason,
+			delete: func() error {
+				return networkingService.DeleteOrphanedFloatingIPs(openStackCluster, clusterName, openStackCluster.Spec.APIServerFloatingIP)
+			},
+		})
 	}
+	steps = append(steps,
+		clusterDeletionStep{
+			resources: "security groups",
+			condition: infrav1.SecurityGroupsReadyCondition,
+			reason:    infrav1.SecurityGroupDeleteFailedReason,
+			delete: func() error {
+				return networkingService.DeleteSecurityGroups(openStackCluster, clusterName)
+			},
+		},
+	)
 
 	// if NodeCIDR was not set, no network was created.
-	if openStackCluster.Spec.NodeCIDR != "" {
-		if err = networkingService.DeleteRouter(openStackCluster, clusterName); err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete router: %v", err))
-			return ctrl.Result{}, errors.Errorf("failed to delete router: %v", err)
+	if openStackCluster.Spec.NodeCIDR != "" && !openStackCluster.RetainsNetwork() {
+		steps = append(steps,
+			clusterDeletionStep{
+				resources: "router firewall group",
+				condition: infrav1.NetworkReadyCondition,
+				reason:    infrav1.NetworkDeleteFailedReason,
+				delete: func() error {
+					return networkingService.DeleteRouterFirewall(openStackCluster, clusterName)
+				},
+			},
+			clusterDeletionStep{
+				resources: "router",
+				condition: infrav1.NetworkReadyCondition,
+				reason:    infrav1.NetworkDeleteFailedReason,
+				delete: func() error {
+					return networkingService.DeleteRouter(openStackCluster, clusterName)
+				},
+			},
+			clusterDeletionStep{
+				resources: "network",
+				condition: infrav1.NetworkReadyCondition,
+				reason:    infrav1.NetworkDeleteFailedReason,
+				delete: func() error {
+					return networkingService.DeleteNetwork(openStackCluster, clusterName)
+				},
+			},
+		)
+	}
+
+	for _, step := range steps {
+		err := step.delete()
+		if err == nil {
+			continue
+		}
+		if capoerrors.IsConflict(err) {
+			conditions.MarkFalse(openStackCluster, step.condition, infrav1.DeletionInProgressReason, clusterv1.ConditionSeverityInfo, "Waiting for the %s to be released: %v", step.resources, err)
+			return errors.Wrapf(errDeletionInProgress, "%s still in use", step.resources)
 		}
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete %s: %v", step.resources, err))
+		conditions.MarkFalse(openStackCluster, step.condition, step.reason, clusterv1.ConditionSeverityWarning, "Deleting %s failed: %v", step.resources, err)
+		return errors.Wrapf(err, "failed to delete %s", step.resources)
+	}
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return err
+	}
 
-		if err = networkingService.DeleteNetwork(openStackCluster, clusterName); err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete network: %v", err))
-			return ctrl.Result{}, errors.Errorf("failed to delete network: %v", err)
+	// Root volumes left behind by failed creations of servers are not
+	// referenced anywhere. The servers of the cluster are gone by now.
+	if !openStackCluster.RetainsVolumes() {
+		noServers := func(string) bool { return false }
+		if err := computeService.DeleteOrphanedVolumes(openStackCluster, cluster.Namespace, cluster.Name, noServers); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete orphaned root volumes: %v", err))
+			return errors.Wrap(err, "failed to delete orphaned root volumes")
 		}
 	}
 
-	// Cluster is deleted so remove the finalizer.
-	controllerutil.RemoveFinalizer(openStackCluster, infrav1.ClusterFinalizer)
-	scope.Logger.Info("Reconciled Cluster delete successfully")
-	if err := patchHelper.Patch(ctx, openStackCluster); err != nil {
-		return ctrl.Result{}, err
+	// Nova keeps the server groups when their servers are deleted
+	if openStackCluster.Spec.ServerGroups != nil {
+		if err := computeService.DeleteServerGroups(openStackCluster); err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete server groups: %v", err))
+			return errors.Wrap(err, "failed to delete server groups")
+		}
 	}
-	return ctrl.Result{}, nil
+
+	return nil
 }
 
 func contains(arr []string, target string) bool {
@@ -222,18 +539,39 @@ func deleteBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackClus
 		}
 		addresses := instanceNS.Addresses()
 
+		instanceSpec := bastionToInstanceSpec(openStackCluster, cluster.Name)
+		deleteErr := computeService.DeleteInstance(openStackCluster, instanceSpec, instanceStatus)
+		if deleteErr != nil && !errors.Is(deleteErr, compute.ErrDeletionInProgress) {
+			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete bastion: %v", deleteErr))
+			conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.BastionDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting bastion failed: %v", deleteErr)
+			return errors.Errorf("failed to delete bastion: %v", deleteErr)
+		}
+
+		// The floating IPs were released together with the ports of the bastion,
+		// unless they belong to a floating IP pool or are retained with the
+		// deleted cluster
+		retainFloatingIPs := !openStackCluster.DeletionTimestamp.IsZero() && openStackCluster.RetainsFloatingIPs()
 		for _, address := range addresses {
-			if address.Type == corev1.NodeExternalIP {
+			if address.Type == corev1.NodeExternalIP && !hasBastionFloatingIPPool(openStackCluster) && !retainFloatingIPs {
 				if err = networkingService.DeleteFloatingIP(openStackCluster, address.Address); err != nil {
 					handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete floating IP: %v", err))
+					conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.FloatingIPDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting floating IP of bastion failed: %v", err)
 					return errors.Errorf("failed to delete floating IP: %v", err)
 				}
 			}
 		}
-
+		if deleteErr != nil {
+			return errors.Wrapf(errDeletionInProgress, "bastion: %v", deleteErr)
+		}
+	} else if openStackCluster.Spec.Bastion != nil {
+		// The root volume of a deleted bastion may still be being deleted
 		instanceSpec := bastionToInstanceSpec(openStackCluster, cluster.Name)
-		if err = computeService.DeleteInstance(openStackCluster, instanceSpec, instanceStatus); err != nil {
+		if err := computeService.DeleteInstance(openStackCluster, instanceSpec, nil); err != nil {
+			if errors.Is(err, compute.ErrDeletionInProgress) {
+				return errors.Wrapf(errDeletionInProgress, "bastion: %v", err)
+			}
 			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete bastion: %v", err))
+			conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.BastionDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting bastion failed: %v", err)
 			return errors.Errorf("failed to delete bastion: %v", err)
 		}
 	}
@@ -242,6 +580,7 @@ func deleteBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackClus
 
 	if err = networkingService.DeleteBastionSecurityGroup(openStackCluster, fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)); err != nil {
 		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to delete bastion security group: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.BastionDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting bastion security group failed: %v", err)
 		return errors.Errorf("failed to delete bastion security group: %v", err)
 	}
 	openStackCluster.Status.BastionSecurityGroup = nil
@@ -251,7 +590,7 @@ func deleteBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackClus
 	return nil
 }
 
-func reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+func reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, floatingIPs *clusterFloatingIPs) (ctrl.Result, error) {
 	scope.Logger.Info("Reconciling Cluster")
 
 	// If the OpenStackCluster doesn't have our finalizer, add it.
@@ -266,47 +605,429 @@ func reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch
 		return reconcile.Result{}, err
 	}
 
-	err = reconcileNetworkComponents(scope, cluster, openStackCluster)
+	// Check that the cloud supports the cluster before creating OpenStack resources
+	if err := reconcileCloudSupport(scope, computeService, openStackCluster); err != nil {
+		scope.Logger.Info("Waiting for the cloud to support the cluster", "reason", err.Error())
+		return reconcile.Result{RequeueAfter: waitForCloudSupportDuration}, nil
+	}
+
+	// Check the quotas before creating the OpenStack resources which do not exist yet
+	if err := reconcileClusterQuota(scope, computeService, cluster, openStackCluster); err != nil {
+		scope.Logger.Info("Waiting for quota to create OpenStack resources", "reason", err.Error())
+		return reconcile.Result{RequeueAfter: waitForQuotaDuration}, nil
+	}
+
+	err = reconcileNetworkComponents(scope, cluster, openStackCluster, floatingIPs.apiServer)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	if err = reconcileBastion(scope, cluster, openStackCluster); err != nil {
+	reconcileClusterNetworkConflicts(scope, cluster, openStackCluster)
+
+	if err = reconcileBastion(scope, cluster, openStackCluster, floatingIPs.bastion); err != nil {
 		return reconcile.Result{}, err
 	}
 
-	availabilityZones, err := computeService.GetAvailabilityZones()
+	shareReady, err := reconcileShare(scope, cluster, openStackCluster)
 	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = reconcileObjectStorage(scope, cluster, openStackCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = reconcileFailureDomains(scope, computeService, openStackCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = computeService.ReconcileServerGroups(openStackCluster, fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = setResourceIDsAnnotation(openStackCluster); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	openStackCluster.Status.Ready = true
+	openStackCluster.Status.FailureMessage = nil
+	openStackCluster.Status.FailureReason = nil
+	scope.Logger.Info("Reconciled Cluster create successfully")
+
+	// The share is not required by the machines, so the cluster is ready
+	// while it is being created
+	if !shareReady {
+		return reconcile.Result{RequeueAfter: waitForShareDuration}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// clusterResourceIDs are the IDs of the OpenStack resources of an
+// OpenStackCluster, as recorded in its ResourceIDsAnnotation.
+type clusterResourceIDs struct {
+	Network                   string `json:"network,omitempty"`
+	Subnet                    string `json:"subnet,omitempty"`
+	Router                    string `json:"router,omitempty"`
+	ControlPlaneSecurityGroup string `json:"controlPlaneSecurityGroup,omitempty"`
+	WorkerSecurityGroup       string `json:"workerSecurityGroup,omitempty"`
+	BastionSecurityGroup      string `json:"bastionSecurityGroup,omitempty"`
+	LoadBalancer              string `json:"loadBalancer,omitempty"`
+	Bastion                   string `json:"bastion,omitempty"`
+	BastionFloatingIP         string `json:"bastionFloatingIP,omitempty"`
+}
+
+// setResourceIDsAnnotation records the IDs of the OpenStack resources in the
+// status of the OpenStackCluster in its ResourceIDsAnnotation.
+func setResourceIDsAnnotation(openStackCluster *infrav1.OpenStackCluster) error {
+	ids := clusterResourceIDs{}
+	if network := openStackCluster.Status.Network; network != nil {
+		ids.Network = network.ID
+		if network.Subnet != nil {
+			ids.Subnet = network.Subnet.ID
+		}
+		if network.Router != nil {
+			ids.Router = network.Router.ID
+		}
+		if network.APIServerLoadBalancer != nil {
+			ids.LoadBalancer = network.APIServerLoadBalancer.ID
+		}
+	}
+	if openStackCluster.Status.ControlPlaneSecurityGroup != nil {
+		ids.ControlPlaneSecurityGroup = openStackCluster.Status.ControlPlaneSecurityGroup.ID
+	}
+	if openStackCluster.Status.WorkerSecurityGroup != nil {
+		ids.WorkerSecurityGroup = openStackCluster.Status.WorkerSecurityGroup.ID
+	}
+	if openStackCluster.Status.BastionSecurityGroup != nil {
+		ids.BastionSecurityGroup = openStackCluster.Status.BastionSecurityGroup.ID
+	}
+	if openStackCluster.Status.Bastion != nil {
+		ids.Bastion = openStackCluster.Status.Bastion.ID
+		ids.BastionFloatingIP = openStackCluster.Status.Bastion.FloatingIP
+	}
+
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal resource IDs")
+	}
+	annotations.AddAnnotations(openStackCluster, map[string]string{infrav1.ResourceIDsAnnotation: string(data)})
+	return nil
+}
+
+// isMoved returns true if the OpenStackCluster was recreated by clusterctl
+// move. Its resources were reconciled before, as it has our finalizer and
+// their IDs, but clusterctl move does not preserve the status.
+func isMoved(openStackCluster *infrav1.OpenStackCluster) bool {
+	_, ok := openStackCluster.Annotations[infrav1.ResourceIDsAnnotation]
+	return ok && controllerutil.ContainsFinalizer(openStackCluster, infrav1.ClusterFinalizer) &&
+		!openStackCluster.Status.Ready && openStackCluster.Status.Network == nil
+}
+
+// reconcileMovedCluster restores the status of an OpenStackCluster recreated
+// by clusterctl move from the OpenStack resources with the IDs recorded in
+// its ResourceIDsAnnotation. No resources are created or changed, so that the
+// following reconcile adopts the existing resources instead of creating new
+// ones. Resources which no longer exist are left to that reconcile.
+func reconcileMovedCluster(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster) error {
+	var ids clusterResourceIDs
+	if err := json.Unmarshal([]byte(openStackCluster.Annotations[infrav1.ResourceIDsAnnotation]), &ids); err != nil {
+		scope.Logger.Info("Ignoring invalid resource IDs annotation", "error", err.Error())
+		return nil
+	}
+	scope.Logger.Info("Re-adopting OpenStack resources of moved Cluster")
+
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		return err
+	}
+	if err := networkingService.AdoptNetwork(openStackCluster, ids.Network, ids.Subnet, ids.Router); err != nil {
+		return errors.Wrap(err, "failed to re-adopt network")
+	}
+
+	// The security groups are looked up by these IDs first when they are reconciled
+	if ids.ControlPlaneSecurityGroup != "" {
+		openStackCluster.Status.ControlPlaneSecurityGroup = &infrav1.SecurityGroup{ID: ids.ControlPlaneSecurityGroup}
+	}
+	if ids.WorkerSecurityGroup != "" {
+		openStackCluster.Status.WorkerSecurityGroup = &infrav1.SecurityGroup{ID: ids.WorkerSecurityGroup}
+	}
+	if ids.BastionSecurityGroup != "" {
+		openStackCluster.Status.BastionSecurityGroup = &infrav1.SecurityGroup{ID: ids.BastionSecurityGroup}
+	}
+
+	if ids.LoadBalancer != "" && openStackCluster.Status.Network != nil {
+		loadBalancerService, err := loadbalancer.NewService(scope)
+		if err != nil {
+			return err
+		}
+		if err := loadBalancerService.AdoptLoadBalancer(openStackCluster, ids.LoadBalancer); err != nil {
+			return errors.Wrap(err, "failed to re-adopt load balancer")
+		}
+	}
+
+	if ids.Bastion != "" {
+		computeService, err := compute.NewService(scope)
+		if err != nil {
+			return err
+		}
+		instanceStatus, err := computeService.GetInstanceStatus(ids.Bastion)
+		if err != nil {
+			return errors.Wrap(err, "failed to re-adopt bastion")
+		}
+		if instanceStatus != nil {
+			bastion, err := instanceStatus.APIInstance(openStackCluster)
+			if err != nil {
+				return err
+			}
+			openStackCluster.Status.Bastion = bastion
+		}
+	}
+	// The floating IP is reused even if it is no longer associated with the bastion
+	if ids.BastionFloatingIP != "" && openStackCluster.Status.Bastion != nil && openStackCluster.Status.Bastion.FloatingIP == "" {
+		openStackCluster.Status.Bastion.FloatingIP = ids.BastionFloatingIP
+	}
+
+	caporecord.Eventf(openStackCluster, "Adopted", "Re-adopted OpenStack resources of moved cluster")
+	return nil
+}
+
+// ensureIdentitySecretIsMoved labels the secret referenced by identityRef, so
+// that clusterctl move moves it together with the cluster. Secrets shared from
+// another namespace are not moved with the cluster and are left alone.
+func ensureIdentitySecretIsMoved(ctx context.Context, ctrlClient client.Client, namespace string, identityRef *infrav1.OpenStackIdentityReference) error {
+	if identityRef == nil {
+		return nil
+	}
+	key := provider.IdentitySecretKey(namespace, identityRef)
+	if key.Namespace != namespace {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := ctrlClient.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if _, ok := secret.Labels[clusterctlv1.ClusterctlMoveLabelName]; ok {
+		return nil
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[clusterctlv1.ClusterctlMoveLabelName] = "true"
+	if err := ctrlClient.Patch(ctx, secret, patch); err != nil {
+		return errors.Wrapf(err, "failed to label identity secret %s for clusterctl move", key)
+	}
+	return nil
+}
+
+// reconcileDryRun computes the changes to OpenStack resources reconciling the
+// OpenStackCluster would apply, and publishes them in its status. The changes
+// are recorded by the provider client of the dry-run context instead of being
+// sent. The reconcile works on a copy of the OpenStackCluster, so that the
+// status of resources which would be created is not stored.
+func reconcileDryRun(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+	scope.Logger.Info("Computing dry-run plan for Cluster")
+
+	planned := openStackCluster.DeepCopy()
+	var err error
+	switch {
+	case annotations.IsExternallyManaged(planned):
+		// No OpenStack resources are changed
+	case !planned.DeletionTimestamp.IsZero():
+		err = deleteClusterResources(scope, cluster, planned)
+	default:
+		// Floating IPs which are not allocated from a pool yet are planned
+		// to be created
+		var bastionFloatingIP string
+		if planned.Spec.Bastion != nil {
+			bastionFloatingIP = planned.Spec.Bastion.Instance.FloatingIP
+		}
+		err = reconcileNetworkComponents(scope, cluster, planned, planned.Spec.APIServerFloatingIP)
+		if err == nil {
+			err = reconcileBastion(scope, cluster, planned, bastionFloatingIP)
+		}
+		if err == nil {
+			_, err = reconcileShare(scope, cluster, planned)
+		}
+	}
+
+	plan := &infrav1.DryRunPlan{
+		GeneratedAt: metav1.Now(),
+		Changes:     dryrun.Changes(ctx),
+	}
+	if err != nil {
+		plan.Error = err.Error()
+	}
+	openStackCluster.Status.DryRunPlan = plan
+	scope.Logger.Info("Computed dry-run plan for Cluster", "changes", len(plan.Changes), "error", plan.Error)
+	return ctrl.Result{}, nil
+}
+
+// reconcileExternallyManaged populates the status of an OpenStackCluster whose
+// infrastructure is managed outside of Cluster API from the resources it
+// references. No OpenStack resources are created, updated or deleted.
+func reconcileExternallyManaged(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+	scope.Logger.Info("Reconciling externally managed Cluster")
+
+	if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
+		scope.Logger.Info("Waiting for the control plane endpoint to be set")
+		return reconcile.Result{}, nil
+	}
+
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = networkingService.ReconcileExternalNetwork(openStackCluster)
+	if err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile external network: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.ExternalNetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "Reconciling external network failed: %v", err)
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile external network")
+	}
+
+	if err = reconcileReferencedNetwork(networkingService, openStackCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+	conditions.MarkTrue(openStackCluster, infrav1.NetworkReadyCondition)
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = reconcileFailureDomains(scope, computeService, openStackCluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	openStackCluster.Status.Ready = true
+	openStackCluster.Status.FailureMessage = nil
+	openStackCluster.Status.FailureReason = nil
+	scope.Logger.Info("Reconciled externally managed Cluster successfully")
+	return reconcile.Result{}, nil
+}
+
+func reconcileFailureDomains(scope *scope.Scope, computeService *compute.Service, openStackCluster *infrav1.OpenStackCluster) error {
+	config := openStackCluster.Spec.FailureDomains
+	if config != nil && len(config.Mappings) > 0 {
+		networkingService, err := networking.NewService(scope)
+		if err != nil {
+			return err
+		}
+		return reconcileMappedFailureDomains(networkingService, openStackCluster)
+	}
+
+	availabilityZones, err := computeService.GetAvailabilityZones()
+	if err != nil {
+		return err
+	}
+
 	// Create a new list in case any AZs have been removed from OpenStack
 	openStackCluster.Status.FailureDomains = make(clusterv1.FailureDomains)
 	for _, az := range availabilityZones {
-		// By default, the AZ is used or not used for control plane nodes depending on the flag
-		found := !openStackCluster.Spec.ControlPlaneOmitAvailabilityZone
-		// If explicit AZs for control plane nodes are given, they override the value
-		if len(openStackCluster.Spec.ControlPlaneAvailabilityZones) > 0 {
-			found = contains(openStackCluster.Spec.ControlPlaneAvailabilityZones, az.ZoneName)
+		if config != nil {
+			if len(config.AvailabilityZones) > 0 && !contains(config.AvailabilityZones, az.ZoneName) {
+				continue
+			}
+			if contains(config.ExcludedAvailabilityZones, az.ZoneName) {
+				continue
+			}
 		}
+
 		// Add the AZ object to the failure domains for the cluster
 		openStackCluster.Status.FailureDomains[az.ZoneName] = clusterv1.FailureDomainSpec{
-			ControlPlane: found,
+			ControlPlane: isControlPlaneFailureDomain(openStackCluster, az.ZoneName),
+			Attributes: map[string]string{
+				infrav1.FailureDomainComputeAvailabilityZoneAttribute: az.ZoneName,
+			},
 		}
 	}
+	return nil
+}
 
-	openStackCluster.Status.Ready = true
-	openStackCluster.Status.FailureMessage = nil
-	openStackCluster.Status.FailureReason = nil
-	scope.Logger.Info("Reconciled Cluster create successfully")
-	return reconcile.Result{}, nil
+// reconcileMappedFailureDomains sets the failure domains of the cluster to the
+// explicit mappings of the spec, resolving the subnet of each mapping.
+func reconcileMappedFailureDomains(networkingService *networking.Service, openStackCluster *infrav1.OpenStackCluster) error {
+	failureDomains := make(clusterv1.FailureDomains)
+	for _, mapping := range openStackCluster.Spec.FailureDomains.Mappings {
+		attributes := map[string]string{
+			infrav1.FailureDomainComputeAvailabilityZoneAttribute: mapping.ComputeAvailabilityZone,
+		}
+		if mapping.StorageAvailabilityZone != "" {
+			attributes[infrav1.FailureDomainStorageAvailabilityZoneAttribute] = mapping.StorageAvailabilityZone
+		}
+		if mapping.ServerGroupID != "" {
+			attributes[infrav1.FailureDomainServerGroupIDAttribute] = mapping.ServerGroupID
+		}
+
+		var listOpts subnets.ListOptsBuilder
+		switch {
+		case mapping.NodeCIDR != "":
+			// The subnet was created in the network of the cluster
+			if openStackCluster.Status.Network == nil {
+				return errors.Errorf("network of the subnet of failure domain %s does not exist yet", mapping.Name)
+			}
+			listOpts = subnets.ListOpts{
+				NetworkID: openStackCluster.Status.Network.ID,
+				CIDR:      mapping.NodeCIDR,
+			}
+		case mapping.Subnet != nil:
+			listOpts = mapping.Subnet.ToListOpt()
+		}
+
+		if listOpts != nil {
+			subnetList, err := networkingService.GetSubnetsByFilter(listOpts)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get subnet of failure domain %s", mapping.Name)
+			}
+			if len(subnetList) != 1 {
+				return errors.Errorf("subnet filter of failure domain %s returns %d subnets, expected exactly one", mapping.Name, len(subnetList))
+			}
+			attributes[infrav1.FailureDomainNetworkIDAttribute] = subnetList[0].NetworkID
+			attributes[infrav1.FailureDomainSubnetIDAttribute] = subnetList[0].ID
+		}
+
+		failureDomains[mapping.Name] = clusterv1.FailureDomainSpec{
+			ControlPlane: isControlPlaneFailureDomain(openStackCluster, mapping.Name),
+			Attributes:   attributes,
+		}
+	}
+
+	openStackCluster.Status.FailureDomains = failureDomains
+	return nil
+}
+
+// isControlPlaneFailureDomain returns whether control plane machines may be
+// placed in the failure domain with the given name.
+func isControlPlaneFailureDomain(openStackCluster *infrav1.OpenStackCluster, name string) bool {
+	// If explicit AZs for control plane nodes are given, they override the flag
+	if len(openStackCluster.Spec.ControlPlaneAvailabilityZones) > 0 {
+		return contains(openStackCluster.Spec.ControlPlaneAvailabilityZones, name)
+	}
+	// By default, the AZ is used or not used for control plane nodes depending on the flag
+	return !openStackCluster.Spec.ControlPlaneOmitAvailabilityZone
 }
 
-func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, floatingIP string) error {
 	scope.Logger.Info("Reconciling Bastion")
 
+	if _, ok := openStackCluster.Annotations[infrav1.BastionPausedAnnotation]; ok {
+		scope.Logger.Info("Bastion is marked as paused. Won't reconcile")
+		return nil
+	}
+
 	if openStackCluster.Spec.Bastion == nil || !openStackCluster.Spec.Bastion.Enabled {
-		return deleteBastion(scope, cluster, openStackCluster)
+		if err := deleteBastion(scope, cluster, openStackCluster); err != nil {
+			return err
+		}
+		conditions.Delete(openStackCluster, infrav1.BastionReadyCondition)
+		removePendingChange(openStackCluster, bastionResource)
+		return nil
 	}
 
 	computeService, err := compute.NewService(scope)
@@ -325,7 +1046,13 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 		return err
 	}
 	if instanceStatus != nil {
-		if !bastionHashHasChanged(bastionHash, openStackCluster.ObjectMeta.Annotations) {
+		hashChanged := bastionHashHasChanged(bastionHash, openStackCluster.ObjectMeta.Annotations)
+		if !hashChanged {
+			removePendingChange(openStackCluster, bastionResource)
+		}
+		// Rebuilding the bastion interrupts access through it, so it is only
+		// done in a maintenance window
+		if !hashChanged || deferDisruptiveChange(openStackCluster, bastionResource, "rebuild for the changed bastion spec", time.Now()) {
 			bastion, err := instanceStatus.APIInstance(openStackCluster)
 			if err != nil {
 				return err
@@ -335,7 +1062,7 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 				annotations.AddAnnotations(openStackCluster, map[string]string{BastionInstanceHashAnnotation: bastionHash})
 			}
 			openStackCluster.Status.Bastion = bastion
-			return nil
+			return reconcileBastionFloatingIP(scope, cluster, openStackCluster, instanceSpec, instanceStatus, floatingIP)
 		}
 
 		if err := deleteBastion(scope, cluster, openStackCluster); err != nil {
@@ -345,65 +1072,171 @@ func reconcileBastion(scope *scope.Scope, cluster *clusterv1.Cluster, openStackC
 
 	instanceStatus, err = computeService.CreateInstance(openStackCluster, openStackCluster, instanceSpec, cluster.Name)
 	if err != nil {
-		return errors.Errorf("failed to reconcile bastion: %v", err)
+		conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.BastionCreateFailedReason, clusterv1.ConditionSeverityError, "Creating bastion failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile bastion")
 	}
 
-	networkingService, err := networking.NewService(scope)
+	bastion, err := instanceStatus.APIInstance(openStackCluster)
 	if err != nil {
 		return err
 	}
-	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
-	fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, openStackCluster.Spec.Bastion.Instance.FloatingIP)
+	openStackCluster.Status.Bastion = bastion
+	annotations.AddAnnotations(openStackCluster, map[string]string{BastionInstanceHashAnnotation: bastionHash})
+	return reconcileBastionFloatingIP(scope, cluster, openStackCluster, instanceSpec, instanceStatus, floatingIP)
+}
+
+// reconcileBastionFloatingIP ensures that a floating IP is associated with the
+// primary port of the bastion, replacing it if it was disassociated or
+// deleted out of band. If floatingIP is empty, any floating IP is used.
+func reconcileBastionFloatingIP(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, instanceSpec *compute.InstanceSpec, instanceStatus *compute.InstanceStatus, floatingIP string) error {
+	computeService, err := compute.NewService(scope)
 	if err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to get or create floating IP for bastion: %v", err))
-		return errors.Errorf("failed to get or create floating IP for bastion: %v", err)
+		return err
 	}
-	port, err := computeService.GetManagementPort(openStackCluster, instanceStatus)
+	networkingService, err := networking.NewService(scope)
 	if err != nil {
-		err = errors.Errorf("getting management port for bastion: %v", err)
+		return err
+	}
+
+	// The bastion is reached through its first network, which is not the
+	// cluster network if the bastion lives on a management network
+	port, err := computeService.GetPrimaryPort(instanceSpec, instanceStatus.ID())
+	if err != nil {
+		err = errors.Wrap(err, "getting management port for bastion")
 		handleUpdateOSCError(openStackCluster, err)
+		conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Obtaining management port for bastion failed: %v", err)
 		return err
 	}
-	err = networkingService.AssociateFloatingIP(openStackCluster, fp, port.ID)
+	if openStackCluster.Status.Bastion.IP == "" && len(port.FixedIPs) > 0 {
+		openStackCluster.Status.Bastion.IP = port.FixedIPs[0].IPAddress
+	}
+
+	fp, err := networkingService.GetFloatingIPByPortID(port.ID)
 	if err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to associate floating IP with bastion: %v", err))
-		return errors.Errorf("failed to associate floating IP with bastion: %v", err)
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to get floating IP for bastion: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Getting floating IP of bastion failed: %v", err)
+		return errors.Wrap(err, "failed to get floating IP for bastion")
+	}
+	if fp != nil {
+		openStackCluster.Status.Bastion.FloatingIP = fp.FloatingIP
+		conditions.MarkTrue(openStackCluster, infrav1.BastionReadyCondition)
+		return nil
+	}
+	if openStackCluster.Status.Bastion.FloatingIP != "" {
+		caporecord.Warnf(openStackCluster, "MissingBastionFloatingIP", "Floating IP %s is no longer associated with the bastion, reassociating it", openStackCluster.Status.Bastion.FloatingIP)
 	}
 
-	bastion, err := instanceStatus.APIInstance(openStackCluster)
+	if floatingIP == "" && openStackCluster.Status.Bastion.FloatingIP != "" {
+		// Reuse the previous floating IP if it still exists and is unused, rather than leaking it
+		fp, err = networkingService.GetFloatingIP(openStackCluster.Status.Bastion.FloatingIP)
+		if err != nil {
+			handleUpdateOSCError(openStackCluster, errors.Wrap(err, "failed to get floating IP for bastion"))
+			conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Getting floating IP of bastion failed: %v", err)
+			return errors.Wrap(err, "failed to get floating IP for bastion")
+		}
+		if fp != nil && fp.PortID == "" {
+			floatingIP = fp.FloatingIP
+		}
+	}
+
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+	fp, err = networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, floatingIP)
 	if err != nil {
-		return err
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to get or create floating IP for bastion: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, reasonFor(err, infrav1.FloatingIPCreateFailedReason), clusterv1.ConditionSeverityError, "Floating IP of bastion cannot be obtained or created: %v", err)
+		return errors.Wrap(err, "failed to get or create floating IP for bastion")
 	}
-	bastion.FloatingIP = fp.FloatingIP
-	openStackCluster.Status.Bastion = bastion
-	annotations.AddAnnotations(openStackCluster, map[string]string{BastionInstanceHashAnnotation: bastionHash})
+	err = networkingService.AssociateFloatingIP(openStackCluster, fp, port.ID)
+	if err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to associate floating IP with bastion: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.BastionReadyCondition, infrav1.FloatingIPAssociateFailedReason, clusterv1.ConditionSeverityError, "Associating floating IP with bastion failed: %v", err)
+		return errors.Wrap(err, "failed to associate floating IP with bastion")
+	}
+	openStackCluster.Status.Bastion.FloatingIP = fp.FloatingIP
+	conditions.MarkTrue(openStackCluster, infrav1.BastionReadyCondition)
 	return nil
 }
 
+// clusterFloatingIPs are the floating IPs of the API server and the bastion
+// of a cluster. They are empty if the cluster does not request a specific
+// floating IP.
+type clusterFloatingIPs struct {
+	apiServer string
+	bastion   string
+}
+
+// reconcileFloatingIPClaims claims the floating IPs of the API server and the
+// bastion from their floating IP pools, and releases the claims which are no
+// longer needed. It returns nil while a floating IP has not been allocated yet.
+func (r *OpenStackClusterReconciler) reconcileFloatingIPClaims(ctx context.Context, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (*clusterFloatingIPs, error) {
+	floatingIPs := &clusterFloatingIPs{
+		apiServer: openStackCluster.Spec.APIServerFloatingIP,
+	}
+	if openStackCluster.Spec.Bastion != nil {
+		floatingIPs.bastion = openStackCluster.Spec.Bastion.Instance.FloatingIP
+	}
+	gvk := infrav1.GroupVersion.WithKind("OpenStackCluster")
+
+	pending := []string{}
+	if poolRef := openStackCluster.Spec.APIServerFloatingIPPoolRef; poolRef != nil {
+		address, err := getOrCreateIPAddressClaim(ctx, r.Client, openStackCluster, gvk, fmt.Sprintf("%s-apiserver", cluster.Name), poolRef)
+		if err != nil {
+			return nil, err
+		}
+		if address == nil {
+			pending = append(pending, "API server")
+		} else {
+			floatingIPs.apiServer = address.Spec.Address
+		}
+	}
+
+	bastionClaimName := fmt.Sprintf("%s-bastion", cluster.Name)
+	if hasBastionFloatingIPPool(openStackCluster) && openStackCluster.Spec.Bastion.Enabled {
+		address, err := getOrCreateIPAddressClaim(ctx, r.Client, openStackCluster, gvk, bastionClaimName, openStackCluster.Spec.Bastion.Instance.FloatingIPPoolRef)
+		if err != nil {
+			return nil, err
+		}
+		if address == nil {
+			pending = append(pending, "bastion")
+		} else {
+			floatingIPs.bastion = address.Spec.Address
+		}
+	} else if err := deleteIPAddressClaim(ctx, r.Client, openStackCluster.Namespace, bastionClaimName); err != nil {
+		return nil, err
+	}
+
+	if len(pending) > 0 {
+		ctrl.LoggerFrom(ctx).Info("Waiting for floating IPs to be allocated", "for", pending)
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.WaitingForFloatingIPReason, clusterv1.ConditionSeverityInfo, "Waiting for floating IPs of %s", strings.Join(pending, " and "))
+		return nil, nil
+	}
+	return floatingIPs, nil
+}
+
+func hasBastionFloatingIPPool(openStackCluster *infrav1.OpenStackCluster) bool {
+	return openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Instance.FloatingIPPoolRef != nil
+}
+
+// bastionToInstanceSpec returns the instance spec of the bastion. Like a
+// machine, the bastion can be connected to several networks, e.g. to a
+// management network besides the cluster network.
 func bastionToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, clusterName string) *compute.InstanceSpec {
-	name := fmt.Sprintf("%s-bastion", clusterName)
-	instanceSpec := &compute.InstanceSpec{
-		Name:          name,
-		Flavor:        openStackCluster.Spec.Bastion.Instance.Flavor,
-		SSHKeyName:    openStackCluster.Spec.Bastion.Instance.SSHKeyName,
-		Image:         openStackCluster.Spec.Bastion.Instance.Image,
-		ImageUUID:     openStackCluster.Spec.Bastion.Instance.ImageUUID,
-		FailureDomain: openStackCluster.Spec.Bastion.AvailabilityZone,
-		RootVolume:    openStackCluster.Spec.Bastion.Instance.RootVolume,
-	}
-
-	instanceSpec.SecurityGroups = openStackCluster.Spec.Bastion.Instance.SecurityGroups
-	if openStackCluster.Spec.ManagedSecurityGroups {
+	bastion := openStackCluster.Spec.Bastion
+	instanceSpec := machineSpecToInstanceSpec(fmt.Sprintf("%s-bastion", clusterName), &bastion.Instance)
+	instanceSpec.FailureDomain = bastion.AvailabilityZone
+	instanceSpec.Tags = bastion.Instance.Tags
+	if keys := bastion.Instance.SSHAuthorizedKeys; len(keys) > 0 {
+		instanceSpec.UserData = sshAuthorizedKeysUserData(keys)
+	}
+
+	if openStackCluster.Spec.ManagedSecurityGroups != nil {
 		if openStackCluster.Status.BastionSecurityGroup != nil {
-			instanceSpec.SecurityGroups = append(instanceSpec.SecurityGroups, infrav1.SecurityGroupParam{
-				UUID: openStackCluster.Status.BastionSecurityGroup.ID,
+			instanceSpec.SecurityGroups = append(instanceSpec.SecurityGroups, infrav1.SecurityGroupFilter{
+				ID: openStackCluster.Status.BastionSecurityGroup.ID,
 			})
 		}
 	}
 
-	instanceSpec.Networks = openStackCluster.Spec.Bastion.Instance.Networks
-	instanceSpec.Ports = openStackCluster.Spec.Bastion.Instance.Ports
-
 	return instanceSpec
 }
 
@@ -416,7 +1249,49 @@ func bastionHashHasChanged(computeHash string, clusterAnnotations map[string]str
 	return latestHash != computeHash
 }
 
-func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+// reconcileNetwork reconciles the network, subnet and router of the cluster,
+// or looks up the referenced network and subnet if the cluster does not create
+// its own network.
+func reconcileNetwork(scope *scope.Scope, networkingService *networking.Service, openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	if openStackCluster.Spec.NodeCIDR == "" {
+		scope.Logger.V(logging.LevelDebug).Info("No need to reconcile network, searching network and subnet instead")
+		return reconcileReferencedNetwork(networkingService, openStackCluster)
+	}
+
+	err := networkingService.ReconcileNetwork(openStackCluster, clusterName)
+	if err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile network: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "Reconciling network failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile network")
+	}
+	err = networkingService.ReconcileSubnet(openStackCluster, clusterName)
+	if err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile subnets: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "Reconciling subnet failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile subnets")
+	}
+	failureDomainSubnetIDs, err := networkingService.ReconcileFailureDomainSubnets(openStackCluster, clusterName)
+	if err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile subnets of failure domains: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "Reconciling subnets of failure domains failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile subnets of failure domains")
+	}
+	err = networkingService.ReconcileRouter(openStackCluster, clusterName, failureDomainSubnetIDs)
+	if err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile router: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "Reconciling router failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile router")
+	}
+	err = networkingService.ReconcileRouterFirewall(openStackCluster, clusterName)
+	if err != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile router firewall: %v", err))
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "Reconciling router firewall failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile router firewall")
+	}
+	return nil
+}
+
+func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, apiServerFloatingIP string) error {
 	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
 
 	networkingService, err := networking.NewService(scope)
@@ -429,72 +1304,42 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 	err = networkingService.ReconcileExternalNetwork(openStackCluster)
 	if err != nil {
 		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile external network: %v", err))
-		return errors.Errorf("failed to reconcile external network: %v", err)
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.ExternalNetworkReconcileFailedReason, clusterv1.ConditionSeverityError, "Reconciling external network failed: %v", err)
+		return errors.Wrap(err, "failed to reconcile external network")
 	}
 
-	if openStackCluster.Spec.NodeCIDR == "" {
-		scope.Logger.V(4).Info("No need to reconcile network, searching network and subnet instead")
-
-		netOpts := openStackCluster.Spec.Network.ToListOpt()
-		networkList, err := networkingService.GetNetworksByFilter(&netOpts)
-		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find network: %v", err))
-			return errors.Errorf("failed to find network: %v", err)
-		}
-		if len(networkList) == 0 {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find any network: %v", err))
-			return errors.Errorf("failed to find any network: %v", err)
-		}
-		if len(networkList) > 1 {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find only one network (result: %v): %v", networkList, err))
-			return errors.Errorf("failed to find only one network (result: %v): %v", networkList, err)
-		}
-		if openStackCluster.Status.Network == nil {
-			openStackCluster.Status.Network = &infrav1.Network{}
-		}
-		openStackCluster.Status.Network.ID = networkList[0].ID
-		openStackCluster.Status.Network.Name = networkList[0].Name
-		openStackCluster.Status.Network.Tags = networkList[0].Tags
-
-		subnetOpts := openStackCluster.Spec.Subnet.ToListOpt()
-		subnetOpts.NetworkID = networkList[0].ID
-		subnetList, err := networkingService.GetSubnetsByFilter(&subnetOpts)
-		if err != nil || len(subnetList) == 0 {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find subnet: %v", err))
-			return errors.Errorf("failed to find subnet: %v", err)
-		}
-		if len(subnetList) > 1 {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to find only one subnet (result: %v): %v", subnetList, err))
-			return errors.Errorf("failed to find only one subnet (result: %v): %v", subnetList, err)
-		}
-		openStackCluster.Status.Network.Subnet = &infrav1.Subnet{
-			ID:   subnetList[0].ID,
-			Name: subnetList[0].Name,
-			CIDR: subnetList[0].CIDR,
-			Tags: subnetList[0].Tags,
-		}
-	} else {
-		err := networkingService.ReconcileNetwork(openStackCluster, clusterName)
-		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile network: %v", err))
-			return errors.Errorf("failed to reconcile network: %v", err)
-		}
-		err = networkingService.ReconcileSubnet(openStackCluster, clusterName)
-		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile subnets: %v", err))
-			return errors.Errorf("failed to reconcile subnets: %v", err)
-		}
-		err = networkingService.ReconcileRouter(openStackCluster, clusterName)
-		if err != nil {
-			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile router: %v", err))
-			return errors.Errorf("failed to reconcile router: %v", err)
-		}
+	// The security groups do not depend on the network, so they are
+	// reconciled while the network, subnet and router are created one after
+	// the other. Only the network branch updates the conditions until both
+	// are done. The security groups are reconciled with their own service,
+	// so that the events of both name the IDs of their own requests.
+	var securityGroupsErr error
+	err = parallel.Run(networkingService.Parallelism(),
+		func() error {
+			return reconcileNetwork(scope, networkingService, openStackCluster, clusterName)
+		},
+		func() error {
+			securityGroupsService, err := networkingService.WithOwnRequestIDs()
+			if err != nil {
+				securityGroupsErr = err
+				return nil
+			}
+			securityGroupsErr = securityGroupsService.ReconcileSecurityGroups(openStackCluster, clusterName)
+			return nil
+		},
+	)
+	if securityGroupsErr != nil {
+		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile security groups: %v", securityGroupsErr))
+		conditions.MarkFalse(openStackCluster, infrav1.SecurityGroupsReadyCondition, reasonFor(securityGroupsErr, infrav1.SecurityGroupReconcileFailedReason), clusterv1.ConditionSeverityError, "Reconciling security groups failed: %v", securityGroupsErr)
+		err = kerrors.NewAggregate([]error{err, errors.Wrap(securityGroupsErr, "failed to reconcile security groups")})
 	}
-
-	err = networkingService.ReconcileSecurityGroups(openStackCluster, clusterName)
 	if err != nil {
-		handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile security groups: %v", err))
-		return errors.Errorf("failed to reconcile security groups: %v", err)
+		return err
+	}
+	if openStackCluster.Spec.ManagedSecurityGroups != nil {
+		conditions.MarkTrue(openStackCluster, infrav1.SecurityGroupsReadyCondition)
+	} else {
+		conditions.Delete(openStackCluster, infrav1.SecurityGroupsReadyCondition)
 	}
 
 	// Calculate the port that we will use for the API server
@@ -517,14 +1362,22 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 		err = loadBalancerService.ReconcileLoadBalancer(openStackCluster, clusterName, apiServerPort)
 		if err != nil {
 			handleUpdateOSCError(openStackCluster, errors.Errorf("failed to reconcile load balancer: %v", err))
-			return errors.Errorf("failed to reconcile load balancer: %v", err)
+			conditions.MarkFalse(openStackCluster, infrav1.LoadBalancerReadyCondition, reasonFor(err, infrav1.LoadBalancerReconcileFailedReason), clusterv1.ConditionSeverityError, "Reconciling load balancer failed: %v", err)
+			return errors.Wrap(err, "failed to reconcile load balancer")
 		}
+		conditions.MarkTrue(openStackCluster, infrav1.LoadBalancerReadyCondition)
+	} else {
+		conditions.Delete(openStackCluster, infrav1.LoadBalancerReadyCondition)
 	}
 
 	if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() {
 		var host string
 		// If there is a load balancer use the floating IP for it if set, falling back to the internal IP
 		switch {
+		case openStackCluster.Spec.ExternalAPIServerEndpoint != nil:
+			// The endpoint is provided outside of OpenStack and cannot be determined
+			conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.APIServerEndpointErrorReason, clusterv1.ConditionSeverityError, "controlPlaneEndpoint must be set for an external API server endpoint")
+			return errors.New("controlPlaneEndpoint must be set for an external API server endpoint")
 		case openStackCluster.Spec.APIServerLoadBalancer.Enabled:
 			if openStackCluster.Status.Network.APIServerLoadBalancer.IP != "" {
 				host = openStackCluster.Status.Network.APIServerLoadBalancer.IP
@@ -533,10 +1386,11 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 			}
 		case !openStackCluster.Spec.DisableAPIServerFloatingIP:
 			// If floating IPs are not disabled, get one to use as the VIP for the control plane
-			fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, openStackCluster.Spec.APIServerFloatingIP)
+			fp, err := networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, apiServerFloatingIP)
 			if err != nil {
 				handleUpdateOSCError(openStackCluster, errors.Errorf("Floating IP cannot be got or created: %v", err))
-				return errors.Errorf("Floating IP cannot be got or created: %v", err)
+				conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, reasonFor(err, infrav1.FloatingIPCreateFailedReason), clusterv1.ConditionSeverityError, "API server floating IP cannot be obtained or created: %v", err)
+				return errors.Wrap(err, "Floating IP cannot be got or created")
 			}
 			host = fp.FloatingIP
 		case openStackCluster.Spec.APIServerFixedIP != "":
@@ -549,6 +1403,7 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 			// accordingly when creating control plane machines
 			// However this would require us to deploy software on the control plane hosts to manage the
 			// VIP (e.g. keepalived/kube-vip)
+			conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.APIServerEndpointErrorReason, clusterv1.ConditionSeverityError, "Unable to determine VIP for API server")
 			return errors.New("unable to determine VIP for API server")
 		}
 
@@ -559,14 +1414,72 @@ func reconcileNetworkComponents(scope *scope.Scope, cluster *clusterv1.Cluster,
 		}
 	}
 
+	conditions.MarkTrue(openStackCluster, infrav1.NetworkReadyCondition)
+	return nil
+}
+
+// reconcileReferencedNetwork sets the network and subnet in the status of the
+// OpenStackCluster to the existing ones matching its network and subnet filters.
+func reconcileReferencedNetwork(networkingService *networking.Service, openStackCluster *infrav1.OpenStackCluster) error {
+	netOpts := openStackCluster.Spec.Network.ToListOpt()
+	networkList, err := networkingService.GetNetworksByFilter(&netOpts)
+	if err != nil {
+		err = errors.Errorf("failed to find network: %v", err)
+		handleUpdateOSCError(openStackCluster, err)
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+	if len(networkList) == 0 {
+		err = errors.Errorf("failed to find any network: %v", err)
+		handleUpdateOSCError(openStackCluster, err)
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+	if len(networkList) > 1 {
+		err = errors.Errorf("failed to find only one network (result: %v): %v", networkList, err)
+		handleUpdateOSCError(openStackCluster, err)
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+	if openStackCluster.Status.Network == nil {
+		openStackCluster.Status.Network = &infrav1.Network{}
+	}
+	openStackCluster.Status.Network.ID = networkList[0].ID
+	openStackCluster.Status.Network.Name = networkList[0].Name
+	openStackCluster.Status.Network.Tags = networkList[0].Tags
+
+	subnetOpts := openStackCluster.Spec.Subnet.ToListOpt()
+	subnetOpts.NetworkID = networkList[0].ID
+	subnetList, err := networkingService.GetSubnetsByFilter(&subnetOpts)
+	if err != nil || len(subnetList) == 0 {
+		err = errors.Errorf("failed to find subnet: %v", err)
+		handleUpdateOSCError(openStackCluster, err)
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+	if len(subnetList) > 1 {
+		err = errors.Errorf("failed to find only one subnet (result: %v): %v", subnetList, err)
+		handleUpdateOSCError(openStackCluster, err)
+		conditions.MarkFalse(openStackCluster, infrav1.NetworkReadyCondition, infrav1.NetworkNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+	openStackCluster.Status.Network.Subnet = &infrav1.Subnet{
+		ID:   subnetList[0].ID,
+		Name: subnetList[0].Name,
+		CIDR: subnetList[0].CIDR,
+		Tags: subnetList[0].Tags,
+	}
 	return nil
 }
 
 func (r *OpenStackClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	clusterToInfraFn := util.ClusterToInfrastructureMapFunc(ctx, infrav1.GroupVersion.WithKind("OpenStackCluster"), mgr.GetClient(), &infrav1.OpenStackCluster{})
-	log := ctrl.LoggerFrom(ctx)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr)
+	if r.InstanceEvents != nil {
+		b = b.Watches(&source.Channel{Source: r.InstanceEvents}, &handler.EnqueueRequestForObject{})
+	}
+	return b.
 		WithOptions(options).
 		For(&infrav1.OpenStackCluster{},
 			builder.WithPredicates(
@@ -586,28 +1499,24 @@ func (r *OpenStackClusterReconciler) SetupWithManager(ctx context.Context, mgr c
 		).
 		Watches(
 			&source.Kind{Type: &clusterv1.Cluster{}},
-			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
-				requests := clusterToInfraFn(o)
-				if len(requests) < 1 {
-					return nil
-				}
-
-				c := &infrav1.OpenStackCluster{}
-				if err := r.Client.Get(ctx, requests[0].NamespacedName, c); err != nil {
-					log.V(4).Error(err, "Failed to get OpenStack cluster")
-					return nil
-				}
-
-				if annotations.IsExternallyManaged(c) {
-					log.V(4).Info("OpenStackCluster is externally managed, skipping mapping.")
-					return nil
-				}
-				return requests
-			}),
+			handler.EnqueueRequestsFromMapFunc(clusterToInfraFn),
 			builder.WithPredicates(predicates.ClusterUnpaused(ctrl.LoggerFrom(ctx))),
 		).
+		Owns(&ipamv1.IPAddressClaim{}).
+		// Roll out rotated credentials to the cloud.conf of the addons
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.identitySecretToOpenStackClusters(ctx)),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return secretDataChanged(e.ObjectOld, e.ObjectNew)
+				},
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
-		WithEventFilter(predicates.ResourceIsNotExternallyManaged(ctrl.LoggerFrom(ctx))).
 		Complete(r)
 }
 
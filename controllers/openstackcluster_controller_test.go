@@ -19,21 +19,32 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"testing"
 
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/test/framework"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
 )
 
@@ -222,3 +233,422 @@ func createRequestFromOSCluster(openStackCluster *infrav1.OpenStackCluster) reco
 		},
 	}
 }
+
+func Test_reconcileReferencedNetwork(t *testing.T) {
+	tests := []struct {
+		name       string
+		expect     func(m *mock_networking.MockNetworkClientMockRecorder)
+		wantErr    bool
+		wantReason string
+	}{
+		{
+			name: "network not found",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any()).Return([]networks.Network{}, nil)
+			},
+			wantErr:    true,
+			wantReason: infrav1.NetworkNotFoundReason,
+		},
+		{
+			name: "subnet not found",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any()).Return([]networks.Network{{ID: "network-id"}}, nil)
+				m.ListSubnet(gomock.Any()).Return([]subnets.Subnet{}, nil)
+			},
+			wantErr:    true,
+			wantReason: infrav1.NetworkNotFoundReason,
+		},
+		{
+			name: "network and subnet found",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(gomock.Any()).Return([]networks.Network{{ID: "network-id"}}, nil)
+				m.ListSubnet(gomock.Any()).Return([]subnets.Subnet{{ID: "subnet-id"}}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			networkingService := networking.NewTestService("", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{}
+			err := reconcileReferencedNetwork(networkingService, openStackCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(conditions.IsFalse(openStackCluster, infrav1.NetworkReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(openStackCluster, infrav1.NetworkReadyCondition)).To(Equal(tt.wantReason))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.ID).To(Equal("network-id"))
+			g.Expect(openStackCluster.Status.Network.Subnet.ID).To(Equal("subnet-id"))
+		})
+	}
+}
+
+func Test_reconcileMappedFailureDomains(t *testing.T) {
+	tests := []struct {
+		name               string
+		spec               infrav1.OpenStackClusterSpec
+		network            *infrav1.Network
+		expect             func(m *mock_networking.MockNetworkClientMockRecorder)
+		wantFailureDomains clusterv1.FailureDomains
+		wantErr            bool
+	}{
+		{
+			name: "availability zones",
+			spec: infrav1.OpenStackClusterSpec{
+				ControlPlaneAvailabilityZones: []string{"fd-1"},
+				FailureDomains: &infrav1.FailureDomainsConfig{
+					Mappings: []infrav1.FailureDomainMapping{
+						{Name: "fd-1", ComputeAvailabilityZone: "nova-1", StorageAvailabilityZone: "cinder-1"},
+						{Name: "fd-2"},
+					},
+				},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {},
+			wantFailureDomains: clusterv1.FailureDomains{
+				"fd-1": clusterv1.FailureDomainSpec{
+					ControlPlane: true,
+					Attributes: map[string]string{
+						infrav1.FailureDomainComputeAvailabilityZoneAttribute: "nova-1",
+						infrav1.FailureDomainStorageAvailabilityZoneAttribute: "cinder-1",
+					},
+				},
+				"fd-2": clusterv1.FailureDomainSpec{
+					ControlPlane: false,
+					Attributes: map[string]string{
+						infrav1.FailureDomainComputeAvailabilityZoneAttribute: "",
+					},
+				},
+			},
+		},
+		{
+			name: "server group",
+			spec: infrav1.OpenStackClusterSpec{
+				FailureDomains: &infrav1.FailureDomainsConfig{
+					Mappings: []infrav1.FailureDomainMapping{
+						{Name: "fd-1", ComputeAvailabilityZone: "nova-1", ServerGroupID: "server-group-id"},
+					},
+				},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {},
+			wantFailureDomains: clusterv1.FailureDomains{
+				"fd-1": clusterv1.FailureDomainSpec{
+					ControlPlane: true,
+					Attributes: map[string]string{
+						infrav1.FailureDomainComputeAvailabilityZoneAttribute: "nova-1",
+						infrav1.FailureDomainServerGroupIDAttribute:           "server-group-id",
+					},
+				},
+			},
+		},
+		{
+			name: "subnet found",
+			spec: infrav1.OpenStackClusterSpec{
+				FailureDomains: &infrav1.FailureDomainsConfig{
+					Mappings: []infrav1.FailureDomainMapping{
+						{Name: "fd-1", ComputeAvailabilityZone: "nova-1", Subnet: &infrav1.SubnetFilter{Name: "subnet-1"}},
+					},
+				},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListSubnet(subnets.ListOpts{Name: "subnet-1"}).Return([]subnets.Subnet{{ID: "subnet-id", NetworkID: "network-id"}}, nil)
+			},
+			wantFailureDomains: clusterv1.FailureDomains{
+				"fd-1": clusterv1.FailureDomainSpec{
+					ControlPlane: true,
+					Attributes: map[string]string{
+						infrav1.FailureDomainComputeAvailabilityZoneAttribute: "nova-1",
+						infrav1.FailureDomainNetworkIDAttribute:               "network-id",
+						infrav1.FailureDomainSubnetIDAttribute:                "subnet-id",
+					},
+				},
+			},
+		},
+		{
+			name: "subnet created in the cluster network",
+			spec: infrav1.OpenStackClusterSpec{
+				NodeCIDR: "10.0.0.0/24",
+				FailureDomains: &infrav1.FailureDomainsConfig{
+					Mappings: []infrav1.FailureDomainMapping{
+						{Name: "fd-1", ComputeAvailabilityZone: "nova-1", NodeCIDR: "10.0.1.0/24"},
+					},
+				},
+			},
+			network: &infrav1.Network{ID: "network-id"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.1.0/24"}).Return([]subnets.Subnet{{ID: "subnet-id", NetworkID: "network-id"}}, nil)
+			},
+			wantFailureDomains: clusterv1.FailureDomains{
+				"fd-1": clusterv1.FailureDomainSpec{
+					ControlPlane: true,
+					Attributes: map[string]string{
+						infrav1.FailureDomainComputeAvailabilityZoneAttribute: "nova-1",
+						infrav1.FailureDomainNetworkIDAttribute:               "network-id",
+						infrav1.FailureDomainSubnetIDAttribute:                "subnet-id",
+					},
+				},
+			},
+		},
+		{
+			name: "subnet not unique",
+			spec: infrav1.OpenStackClusterSpec{
+				FailureDomains: &infrav1.FailureDomainsConfig{
+					Mappings: []infrav1.FailureDomainMapping{
+						{Name: "fd-1", Subnet: &infrav1.SubnetFilter{Name: "subnet-1"}},
+					},
+				},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListSubnet(subnets.ListOpts{Name: "subnet-1"}).Return([]subnets.Subnet{{ID: "subnet-id"}, {ID: "other-subnet-id"}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			networkingService := networking.NewTestService("", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec:   tt.spec,
+				Status: infrav1.OpenStackClusterStatus{Network: tt.network},
+			}
+			err := reconcileMappedFailureDomains(networkingService, openStackCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.FailureDomains).To(Equal(tt.wantFailureDomains))
+		})
+	}
+}
+
+func Test_setResourceIDsAnnotation(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Finalizers: []string{infrav1.ClusterFinalizer},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Ready: true,
+			Network: &infrav1.Network{
+				ID:                    "network-id",
+				Subnet:                &infrav1.Subnet{ID: "subnet-id"},
+				Router:                &infrav1.Router{ID: "router-id"},
+				APIServerLoadBalancer: &infrav1.LoadBalancer{ID: "lb-id"},
+			},
+			ControlPlaneSecurityGroup: &infrav1.SecurityGroup{ID: "control-plane-id"},
+			WorkerSecurityGroup:       &infrav1.SecurityGroup{ID: "worker-id"},
+			Bastion:                   &infrav1.Instance{ID: "bastion-id", FloatingIP: "203.0.113.10"},
+		},
+	}
+	g.Expect(isMoved(openStackCluster)).To(BeFalse())
+
+	g.Expect(setResourceIDsAnnotation(openStackCluster)).To(Succeed())
+	g.Expect(openStackCluster.Annotations[infrav1.ResourceIDsAnnotation]).To(MatchJSON(`{
+		"network": "network-id",
+		"subnet": "subnet-id",
+		"router": "router-id",
+		"controlPlaneSecurityGroup": "control-plane-id",
+		"workerSecurityGroup": "worker-id",
+		"loadBalancer": "lb-id",
+		"bastion": "bastion-id",
+		"bastionFloatingIP": "203.0.113.10"
+	}`))
+	g.Expect(isMoved(openStackCluster)).To(BeFalse())
+
+	// clusterctl move preserves the metadata but not the status
+	openStackCluster.Status = infrav1.OpenStackClusterStatus{}
+	g.Expect(isMoved(openStackCluster)).To(BeTrue())
+}
+
+func Test_ensureIdentitySecretIsMoved(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cloud-config",
+			Namespace: "test-namespace",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	identityRef := &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cloud-config"}
+
+	g.Expect(ensureIdentitySecretIsMoved(context.TODO(), fakeClient, "test-namespace", nil)).To(Succeed())
+	g.Expect(ensureIdentitySecretIsMoved(context.TODO(), fakeClient, "other-namespace", identityRef)).To(Succeed())
+	g.Expect(ensureIdentitySecretIsMoved(context.TODO(), fakeClient, "test-namespace", identityRef)).To(Succeed())
+
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+	g.Expect(secret.Labels).To(HaveKeyWithValue(clusterctlv1.ClusterctlMoveLabelName, "true"))
+
+	sharedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cloud-config",
+			Namespace: "identities",
+		},
+	}
+	g.Expect(fakeClient.Create(context.TODO(), sharedSecret)).To(Succeed())
+	sharedIdentityRef := &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cloud-config", Namespace: "identities"}
+	g.Expect(ensureIdentitySecretIsMoved(context.TODO(), fakeClient, "test-namespace", sharedIdentityRef)).To(Succeed())
+	g.Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(sharedSecret), sharedSecret)).To(Succeed())
+	g.Expect(sharedSecret.Labels).NotTo(HaveKey(clusterctlv1.ClusterctlMoveLabelName))
+}
+
+func Test_getClusterRequiredResources(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         infrav1.OpenStackClusterStatus
+		wantRequired   requiredResources
+		wantComponents []clusterv1.ConditionType
+	}{
+		{
+			name:   "New cluster",
+			status: infrav1.OpenStackClusterStatus{},
+			wantRequired: requiredResources{
+				network: networking.NetworkResources{
+					Networks:       1,
+					Subnets:        1,
+					Routers:        1,
+					Ports:          2,
+					FloatingIPs:    1,
+					SecurityGroups: 2,
+				},
+				loadBalancers: 1,
+			},
+			wantComponents: []clusterv1.ConditionType{
+				infrav1.NetworkReadyCondition,
+				infrav1.SecurityGroupsReadyCondition,
+				infrav1.LoadBalancerReadyCondition,
+			},
+		},
+		{
+			name: "Only the load balancer is missing",
+			status: infrav1.OpenStackClusterStatus{
+				Network: &infrav1.Network{
+					ID:     "network-id",
+					Subnet: &infrav1.Subnet{ID: "subnet-id"},
+					Router: &infrav1.Router{ID: "router-id"},
+				},
+				ControlPlaneSecurityGroup: &infrav1.SecurityGroup{ID: "control-plane-id"},
+				WorkerSecurityGroup:       &infrav1.SecurityGroup{ID: "worker-id"},
+			},
+			wantRequired: requiredResources{
+				network:       networking.NetworkResources{Ports: 1, FloatingIPs: 1},
+				loadBalancers: 1,
+			},
+			wantComponents: []clusterv1.ConditionType{
+				infrav1.LoadBalancerReadyCondition,
+			},
+		},
+		{
+			name: "All resources exist",
+			status: infrav1.OpenStackClusterStatus{
+				Network: &infrav1.Network{
+					ID:                    "network-id",
+					Subnet:                &infrav1.Subnet{ID: "subnet-id"},
+					Router:                &infrav1.Router{ID: "router-id"},
+					APIServerLoadBalancer: &infrav1.LoadBalancer{ID: "lb-id"},
+				},
+				ControlPlaneSecurityGroup: &infrav1.SecurityGroup{ID: "control-plane-id"},
+				WorkerSecurityGroup:       &infrav1.SecurityGroup{ID: "worker-id"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					NodeCIDR:              "10.6.0.0/24",
+					ExternalNetworkID:     "external-network-id",
+					ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{},
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Enabled: true},
+				},
+				Status: tt.status,
+			}
+
+			// The compute service is only used for the bastion, which is disabled
+			required, components, err := getClusterRequiredResources(nil, openStackCluster, "cluster")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(required).To(Equal(tt.wantRequired))
+			g.Expect(components).To(Equal(tt.wantComponents))
+		})
+	}
+}
+
+func Test_bastionToInstanceSpec(t *testing.T) {
+	g := NewWithT(t)
+
+	managementNetwork := infrav1.NetworkFilter{ID: "management-network-id"}
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{},
+			Bastion: &infrav1.Bastion{
+				Enabled: true,
+				Instance: infrav1.OpenStackMachineSpec{
+					Flavor:         "m1.small",
+					Image:          "ubuntu",
+					ServerMetadata: map[string]string{"role": "bastion"},
+					ServerGroupID:  "server-group-id",
+					Tags:           []string{"bastion"},
+					SecurityGroups: []infrav1.SecurityGroupFilter{{Name: "management-ssh"}},
+					Ports: []infrav1.PortOpts{
+						{Network: &managementNetwork},
+						{NameSuffix: "cluster"},
+					},
+				},
+				AvailabilityZone: "az1",
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			BastionSecurityGroup: &infrav1.SecurityGroup{ID: "bastion-security-group-id"},
+		},
+	}
+
+	instanceSpec := bastionToInstanceSpec(openStackCluster, "cluster")
+	g.Expect(instanceSpec.Name).To(Equal("cluster-bastion"))
+	g.Expect(instanceSpec.FailureDomain).To(Equal("az1"))
+	g.Expect(instanceSpec.Metadata).To(Equal(map[string]string{"role": "bastion"}))
+	g.Expect(instanceSpec.ServerGroupID).To(Equal("server-group-id"))
+	g.Expect(instanceSpec.Tags).To(Equal([]string{"bastion"}))
+	g.Expect(instanceSpec.SecurityGroups).To(Equal([]infrav1.SecurityGroupFilter{{Name: "management-ssh"}, {ID: "bastion-security-group-id"}}))
+	g.Expect(instanceSpec.Ports).To(Equal(openStackCluster.Spec.Bastion.Instance.Ports))
+}
+
+func Test_reconcileBastion_paused(t *testing.T) {
+	g := NewWithT(t)
+
+	// A disabled bastion would be deleted if it was not paused
+	openStackCluster := &infrav1.OpenStackCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{infrav1.BastionPausedAnnotation: ""},
+		},
+		Spec: infrav1.OpenStackClusterSpec{
+			Bastion: &infrav1.Bastion{Enabled: false},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Bastion: &infrav1.Instance{ID: "bastion-id"},
+		},
+	}
+	conditions.MarkTrue(openStackCluster, infrav1.BastionReadyCondition)
+
+	err := reconcileBastion(&scope.Scope{Logger: logr.Discard()}, &clusterv1.Cluster{}, openStackCluster, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(openStackCluster.Status.Bastion).To(Equal(&infrav1.Instance{ID: "bastion-id"}))
+	g.Expect(conditions.IsTrue(openStackCluster, infrav1.BastionReadyCondition)).To(BeTrue())
+}
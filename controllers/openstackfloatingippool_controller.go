@@ -0,0 +1,599 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+)
+
+// OpenStackFloatingIPPoolReconciler reconciles a OpenStackFloatingIPPool object.
+type OpenStackFloatingIPPoolReconciler struct {
+	Client           client.Client
+	Recorder         record.EventRecorder
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackfloatingippools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackfloatingippools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch;create;update;patch;delete
+
+func (r *OpenStackFloatingIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, span := tracing.StartReconcile(ctx, "OpenStackFloatingIPPool", req)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the OpenStackFloatingIPPool instance.
+	openStackFloatingIPPool := &infrav1.OpenStackFloatingIPPool{}
+	err := r.Client.Get(ctx, req.NamespacedName, openStackFloatingIPPool)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("openStackFloatingIPPool", openStackFloatingIPPool.Name)
+
+	if annotations.HasPaused(openStackFloatingIPPool) {
+		log.Info("OpenStackFloatingIPPool is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(openStackFloatingIPPool, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the openStackFloatingIPPool when exiting this function so we can persist any OpenStackFloatingIPPool changes.
+	defer func() {
+		conditions.SetSummary(openStackFloatingIPPool,
+			conditions.WithConditions(infrav1.FloatingIPPoolReadyCondition),
+		)
+		if err := patchHelper.Patch(ctx, openStackFloatingIPPool, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.FloatingIPPoolReadyCondition,
+		}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if err := ensureIdentitySecretIsMoved(ctx, r.Client, openStackFloatingIPPool.Namespace, openStackFloatingIPPool.Spec.IdentityRef); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromFloatingIPPool(ctx, r.Client, openStackFloatingIPPool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scope := &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+	}
+
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Handle deleted pools
+	if !openStackFloatingIPPool.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, scope, networkingService, patchHelper, openStackFloatingIPPool)
+	}
+
+	// Handle non-deleted pools
+	return r.reconcileNormal(ctx, scope, networkingService, patchHelper, openStackFloatingIPPool)
+}
+
+func (r *OpenStackFloatingIPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.OpenStackFloatingIPPool{}).
+		Watches(
+			&source.Kind{Type: &ipamv1.IPAddressClaim{}},
+			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []ctrl.Request {
+				return requestsForFloatingIPPool(o.GetNamespace(), o.(*ipamv1.IPAddressClaim).Spec.PoolRef)
+			}),
+		).
+		Watches(
+			&source.Kind{Type: &ipamv1.IPAddress{}},
+			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []ctrl.Request {
+				return requestsForFloatingIPPool(o.GetNamespace(), o.(*ipamv1.IPAddress).Spec.PoolRef)
+			}),
+		).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+}
+
+// requestsForFloatingIPPool returns the request for the
+// OpenStackFloatingIPPool referenced by poolRef, if it references one.
+func requestsForFloatingIPPool(namespace string, poolRef corev1.TypedLocalObjectReference) []ctrl.Request {
+	if !isFloatingIPPoolRef(poolRef) {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: namespace, Name: poolRef.Name}}}
+}
+
+func isFloatingIPPoolRef(poolRef corev1.TypedLocalObjectReference) bool {
+	return poolRef.APIGroup != nil && *poolRef.APIGroup == infrav1.GroupVersion.Group && poolRef.Kind == "OpenStackFloatingIPPool"
+}
+
+// floatingIPPoolState is the state of an OpenStackFloatingIPPool, collected
+// from its IPAddresses and the floating IPs created for it.
+type floatingIPPoolState struct {
+	// addresses are the IPAddresses allocated by the pool
+	addresses []ipamv1.IPAddress
+	// preAllocated are the pre-allocated floating IPs
+	preAllocated map[string]bool
+	// created are the floating IPs created for the pool
+	created map[string]*floatingips.FloatingIP
+	// claimed are the floating IPs allocated to an IPAddress
+	claimed map[string]bool
+}
+
+func (r *OpenStackFloatingIPPoolReconciler) getPoolState(ctx context.Context, networkingService *networking.Service, openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool) (*floatingIPPoolState, error) {
+	state := &floatingIPPoolState{
+		preAllocated: map[string]bool{},
+		created:      map[string]*floatingips.FloatingIP{},
+		claimed:      map[string]bool{},
+	}
+
+	addressList := &ipamv1.IPAddressList{}
+	if err := r.Client.List(ctx, addressList, client.InNamespace(openStackFloatingIPPool.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, address := range addressList.Items {
+		if isFloatingIPPoolRef(address.Spec.PoolRef) && address.Spec.PoolRef.Name == openStackFloatingIPPool.Name {
+			state.addresses = append(state.addresses, address)
+			state.claimed[address.Spec.Address] = true
+		}
+	}
+
+	for _, ip := range openStackFloatingIPPool.Spec.PreAllocatedFloatingIPs {
+		state.preAllocated[ip] = true
+	}
+
+	fipList, err := networkingService.ListFloatingIPsForPool(openStackFloatingIPPool)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fipList {
+		state.created[fipList[i].FloatingIP] = &fipList[i]
+	}
+
+	return state, nil
+}
+
+// releaseAddresses returns the floating IPs of the deleted IPAddresses to the
+// pool, or deletes them if the pool created them and does not retain them.
+func (r *OpenStackFloatingIPPoolReconciler) releaseAddresses(ctx context.Context, scope *scope.Scope, networkingService *networking.Service, openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool, state *floatingIPPoolState) error {
+	remaining := state.addresses[:0]
+	for i := range state.addresses {
+		address := &state.addresses[i]
+		if address.DeletionTimestamp.IsZero() || !controllerutil.ContainsFinalizer(address, infrav1.FloatingIPAddressFinalizer) {
+			remaining = append(remaining, *address)
+			continue
+		}
+
+		ip := address.Spec.Address
+		scope.Logger.Info("Releasing floating IP", "ip", ip, "claim", address.Spec.ClaimRef.Name)
+		if err := networkingService.DisassociateFloatingIP(openStackFloatingIPPool, ip); err != nil {
+			return err
+		}
+		if _, ok := state.created[ip]; ok && !state.preAllocated[ip] && openStackFloatingIPPool.Spec.ReclaimPolicy != infrav1.ReclaimRetain {
+			if err := networkingService.DeleteFloatingIP(openStackFloatingIPPool, ip); err != nil {
+				return err
+			}
+			delete(state.created, ip)
+		}
+
+		addressPatch := client.MergeFrom(address.DeepCopy())
+		controllerutil.RemoveFinalizer(address, infrav1.FloatingIPAddressFinalizer)
+		if err := r.Client.Patch(ctx, address, addressPatch); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		delete(state.claimed, ip)
+	}
+	state.addresses = remaining
+	return nil
+}
+
+func (r *OpenStackFloatingIPPoolReconciler) reconcileDelete(ctx context.Context, scope *scope.Scope, networkingService *networking.Service, patchHelper *patch.Helper, openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool) (ctrl.Result, error) {
+	scope.Logger.Info("Reconciling FloatingIPPool delete")
+
+	state, err := r.getPoolState(ctx, networkingService, openStackFloatingIPPool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.releaseAddresses(ctx, scope, networkingService, openStackFloatingIPPool, state); err != nil {
+		conditions.MarkFalse(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition, infrav1.FloatingIPDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Releasing floating IPs failed: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	// The pool is deleted once all its floating IPs are released, which
+	// triggers another reconcile through the deleted IPAddresses
+	if len(state.addresses) > 0 {
+		scope.Logger.Info("Waiting for the claimed floating IPs to be released", "claimed", len(state.addresses))
+		conditions.MarkFalse(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition, infrav1.DeletionInProgressReason, clusterv1.ConditionSeverityInfo, "Waiting for %d claimed floating IPs to be released", len(state.addresses))
+		return ctrl.Result{}, nil
+	}
+
+	if openStackFloatingIPPool.Spec.ReclaimPolicy != infrav1.ReclaimRetain {
+		for ip := range state.created {
+			if state.preAllocated[ip] {
+				continue
+			}
+			if err := networkingService.DeleteFloatingIP(openStackFloatingIPPool, ip); err != nil {
+				conditions.MarkFalse(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition, infrav1.FloatingIPDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting floating IP %s failed: %v", ip, err)
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(openStackFloatingIPPool, infrav1.FloatingIPPoolFinalizer)
+	scope.Logger.Info("Reconciled FloatingIPPool delete successfully")
+	if err := patchHelper.Patch(ctx, openStackFloatingIPPool); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *OpenStackFloatingIPPoolReconciler) reconcileNormal(ctx context.Context, scope *scope.Scope, networkingService *networking.Service, patchHelper *patch.Helper, openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool) (ctrl.Result, error) {
+	// If the OpenStackFloatingIPPool doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(openStackFloatingIPPool, infrav1.FloatingIPPoolFinalizer)
+	// Register the finalizer immediately to avoid orphaning OpenStack resources on delete
+	if err := patchHelper.Patch(ctx, openStackFloatingIPPool); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scope.Logger.Info("Reconciling FloatingIPPool")
+
+	networkID, subnetID, externalSubnets, err := resolveFloatingIPNetwork(networkingService, openStackFloatingIPPool)
+	if err != nil {
+		conditions.MarkFalse(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition, infrav1.FloatingIPNetworkNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, err
+	}
+	openStackFloatingIPPool.Status.FloatingIPNetworkID = networkID
+
+	state, err := r.getPoolState(ctx, networkingService, openStackFloatingIPPool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.releaseAddresses(ctx, scope, networkingService, openStackFloatingIPPool, state); err != nil {
+		conditions.MarkFalse(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition, infrav1.FloatingIPDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Releasing floating IPs failed: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	claimList := &ipamv1.IPAddressClaimList{}
+	if err := r.Client.List(ctx, claimList, client.InNamespace(openStackFloatingIPPool.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Allocate floating IPs to the claims in a stable order
+	sort.Slice(claimList.Items, func(i, j int) bool {
+		return claimList.Items[i].Name < claimList.Items[j].Name
+	})
+
+	var pending int
+	for i := range claimList.Items {
+		claim := &claimList.Items[i]
+		if !isFloatingIPPoolRef(claim.Spec.PoolRef) || claim.Spec.PoolRef.Name != openStackFloatingIPPool.Name || !claim.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		allocated, err := r.reconcileClaim(ctx, scope, networkingService, openStackFloatingIPPool, state, claim, networkID, subnetID, externalSubnets)
+		if err != nil {
+			conditions.MarkFalse(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition, reasonFor(err, infrav1.FloatingIPAllocateFailedReason), clusterv1.ConditionSeverityWarning, "Allocating a floating IP to claim %s failed: %v", claim.Name, err)
+			return ctrl.Result{}, err
+		}
+		if !allocated {
+			pending++
+		}
+	}
+
+	setFloatingIPPoolStatus(openStackFloatingIPPool, state)
+
+	if pending > 0 {
+		scope.Logger.Info("Floating IP pool is exhausted", "pending", pending)
+		conditions.MarkFalse(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition, infrav1.FloatingIPPoolExhaustedReason, clusterv1.ConditionSeverityWarning, "%d claims are waiting for a floating IP", pending)
+		return ctrl.Result{}, nil
+	}
+
+	conditions.MarkTrue(openStackFloatingIPPool, infrav1.FloatingIPPoolReadyCondition)
+	scope.Logger.Info("Reconciled FloatingIPPool successfully")
+	return ctrl.Result{}, nil
+}
+
+// reconcileClaim allocates a floating IP to the claim, unless it already has
+// one. It returns false if the pool is exhausted.
+func (r *OpenStackFloatingIPPoolReconciler) reconcileClaim(ctx context.Context, scope *scope.Scope, networkingService *networking.Service, openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool, state *floatingIPPoolState, claim *ipamv1.IPAddressClaim, networkID, subnetID string, externalSubnets []subnets.Subnet) (bool, error) {
+	// The IPAddress of a claim has the name of the claim
+	address := &ipamv1.IPAddress{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Name}, address)
+	switch {
+	case err == nil:
+		return true, setClaimAddress(ctx, r.Client, claim, address)
+	case !apierrors.IsNotFound(err):
+		return false, err
+	}
+
+	ip := nextAvailableFloatingIP(openStackFloatingIPPool, state)
+	if ip == "" {
+		if maxIPs := openStackFloatingIPPool.Spec.MaxIPs; maxIPs != nil && len(availableOrClaimedIPs(state)) >= *maxIPs {
+			return false, nil
+		}
+
+		fp, err := networkingService.CreateFloatingIPForPool(openStackFloatingIPPool, networkID, subnetID)
+		if err != nil {
+			return false, err
+		}
+		state.created[fp.FloatingIP] = fp
+		ip = fp.FloatingIP
+	}
+
+	prefix, gateway, err := floatingIPPrefixAndGateway(ip, externalSubnets)
+	if err != nil {
+		return false, err
+	}
+
+	address = &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claim.Name,
+			Namespace: claim.Namespace,
+			Labels:    claim.Labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(claim, ipamv1.GroupVersion.WithKind("IPAddressClaim")),
+			},
+			Finalizers: []string{infrav1.FloatingIPAddressFinalizer},
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+			PoolRef:  claim.Spec.PoolRef,
+			Address:  ip,
+			Prefix:   prefix,
+			Gateway:  gateway,
+		},
+	}
+	scope.Logger.Info("Allocating floating IP", "ip", ip, "claim", claim.Name)
+	if err := r.Client.Create(ctx, address); err != nil {
+		return false, errors.Wrapf(err, "failed to create IPAddress for claim %s", claim.Name)
+	}
+	state.addresses = append(state.addresses, *address)
+	state.claimed[ip] = true
+
+	return true, setClaimAddress(ctx, r.Client, claim, address)
+}
+
+func setClaimAddress(ctx context.Context, ctrlClient client.Client, claim *ipamv1.IPAddressClaim, address *ipamv1.IPAddress) error {
+	if claim.Status.AddressRef.Name == address.Name {
+		return nil
+	}
+	claimPatch := client.MergeFrom(claim.DeepCopy())
+	claim.Status.AddressRef.Name = address.Name
+	return ctrlClient.Status().Patch(ctx, claim, claimPatch)
+}
+
+// nextAvailableFloatingIP returns the first floating IP of the pool which is
+// not claimed, preferring the pre-allocated floating IPs. It returns an empty
+// string if all floating IPs are claimed.
+func nextAvailableFloatingIP(openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool, state *floatingIPPoolState) string {
+	for _, ip := range openStackFloatingIPPool.Spec.PreAllocatedFloatingIPs {
+		if !state.claimed[ip] {
+			return ip
+		}
+	}
+
+	created := make([]string, 0, len(state.created))
+	for ip, fp := range state.created {
+		// Floating IPs still associated with a port are in use out of band
+		if !state.claimed[ip] && fp.PortID == "" {
+			created = append(created, ip)
+		}
+	}
+	sort.Strings(created)
+	if len(created) > 0 {
+		return created[0]
+	}
+	return ""
+}
+
+// availableOrClaimedIPs returns all floating IPs of the pool.
+func availableOrClaimedIPs(state *floatingIPPoolState) map[string]bool {
+	ips := map[string]bool{}
+	for ip := range state.preAllocated {
+		ips[ip] = true
+	}
+	for ip := range state.created {
+		ips[ip] = true
+	}
+	for ip := range state.claimed {
+		ips[ip] = true
+	}
+	return ips
+}
+
+func setFloatingIPPoolStatus(openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool, state *floatingIPPoolState) {
+	var claimed, available []string
+	for ip := range availableOrClaimedIPs(state) {
+		switch {
+		case state.claimed[ip]:
+			claimed = append(claimed, ip)
+		case state.created[ip] == nil || state.created[ip].PortID == "":
+			available = append(available, ip)
+		}
+	}
+	sort.Strings(claimed)
+	sort.Strings(available)
+	openStackFloatingIPPool.Status.ClaimedIPs = claimed
+	openStackFloatingIPPool.Status.AvailableIPs = available
+}
+
+// resolveFloatingIPNetwork returns the IDs of the external network and, if the
+// pool selects one, subnet of the pool, and the subnets of the external network.
+func resolveFloatingIPNetwork(networkingService *networking.Service, openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool) (string, string, []subnets.Subnet, error) {
+	networkIDs, err := networkingService.GetNetworkIDsByFilter(openStackFloatingIPPool.Spec.FloatingIPNetwork.ToListOpt())
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to find external network: %v", err)
+	}
+	if len(networkIDs) != 1 {
+		return "", "", nil, fmt.Errorf("floatingIPNetwork must select exactly one network, but found %d", len(networkIDs))
+	}
+	networkID := networkIDs[0]
+
+	externalSubnets, err := networkingService.GetSubnetsByFilter(subnets.ListOpts{NetworkID: networkID})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to list subnets of external network %s: %v", networkID, err)
+	}
+
+	var subnetID string
+	if openStackFloatingIPPool.Spec.FloatingIPSubnet != nil {
+		listOpts := openStackFloatingIPPool.Spec.FloatingIPSubnet.ToListOpt()
+		listOpts.NetworkID = networkID
+		subnetList, err := networkingService.GetSubnetsByFilter(listOpts)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("failed to find external subnet: %v", err)
+		}
+		if len(subnetList) != 1 {
+			return "", "", nil, fmt.Errorf("floatingIPSubnet must select exactly one subnet of network %s, but found %d", networkID, len(subnetList))
+		}
+		subnetID = subnetList[0].ID
+	}
+
+	return networkID, subnetID, externalSubnets, nil
+}
+
+// floatingIPPrefixAndGateway returns the prefix length and gateway of the
+// external subnet containing the floating IP.
+func floatingIPPrefixAndGateway(ip string, externalSubnets []subnets.Subnet) (int, string, error) {
+	addr := net.ParseIP(ip)
+	for _, subnet := range externalSubnets {
+		_, cidr, err := net.ParseCIDR(subnet.CIDR)
+		if err != nil || !cidr.Contains(addr) {
+			continue
+		}
+		if subnet.GatewayIP == "" {
+			return 0, "", fmt.Errorf("subnet %s of floating IP %s has no gateway", subnet.ID, ip)
+		}
+		prefix, _ := cidr.Mask.Size()
+		return prefix, subnet.GatewayIP, nil
+	}
+	return 0, "", fmt.Errorf("floating IP %s is not in a subnet of the external network", ip)
+}
+
+// getOrCreateIPAddressClaim returns the IPAddress allocated to the
+// IPAddressClaim with the given name, creating the claim for owner if it does
+// not exist yet. The IPAddress is nil until the pool allocated it.
+func getOrCreateIPAddressClaim(ctx context.Context, ctrlClient client.Client, owner client.Object, ownerGVK schema.GroupVersionKind, name string, poolRef *corev1.TypedLocalObjectReference) (*ipamv1.IPAddress, error) {
+	claim := &ipamv1.IPAddressClaim{}
+	err := ctrlClient.Get(ctx, client.ObjectKey{Namespace: owner.GetNamespace(), Name: name}, claim)
+	if apierrors.IsNotFound(err) {
+		claim = &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: owner.GetNamespace(),
+				Labels:    map[string]string{},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(owner, ownerGVK),
+				},
+			},
+			Spec: ipamv1.IPAddressClaimSpec{
+				PoolRef: *poolRef,
+			},
+		}
+		for _, label := range []string{clusterv1.ClusterLabelName, clusterv1.WatchLabel} {
+			if value, ok := owner.GetLabels()[label]; ok {
+				claim.Labels[label] = value
+			}
+		}
+		if err := ctrlClient.Create(ctx, claim); err != nil {
+			return nil, errors.Wrapf(err, "failed to create IPAddressClaim %s", name)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if claim.Status.AddressRef.Name == "" {
+		return nil, nil
+	}
+
+	address := &ipamv1.IPAddress{}
+	if err := ctrlClient.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}, address); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return address, nil
+}
+
+// deleteIPAddressClaim deletes the IPAddressClaim with the given name, which
+// releases its IP address.
+func deleteIPAddressClaim(ctx context.Context, ctrlClient client.Client, namespace, name string) error {
+	claim := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	if err := ctrlClient.Delete(ctx, claim); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete IPAddressClaim %s", name)
+	}
+	return nil
+}
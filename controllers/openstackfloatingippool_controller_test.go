@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_floatingIPPrefixAndGateway(t *testing.T) {
+	externalSubnets := []subnets.Subnet{
+		{ID: "v6", CIDR: "2001:db8::/64", GatewayIP: "2001:db8::1"},
+		{ID: "v4", CIDR: "192.0.2.0/24", GatewayIP: "192.0.2.1"},
+		{ID: "no-gateway", CIDR: "198.51.100.0/24"},
+	}
+
+	tests := []struct {
+		name        string
+		ip          string
+		wantPrefix  int
+		wantGateway string
+		wantErr     bool
+	}{
+		{
+			name:        "IPv4 subnet",
+			ip:          "192.0.2.10",
+			wantPrefix:  24,
+			wantGateway: "192.0.2.1",
+		},
+		{
+			name:        "IPv6 subnet",
+			ip:          "2001:db8::10",
+			wantPrefix:  64,
+			wantGateway: "2001:db8::1",
+		},
+		{
+			name:    "Subnet without gateway",
+			ip:      "198.51.100.10",
+			wantErr: true,
+		},
+		{
+			name:    "No matching subnet",
+			ip:      "203.0.113.10",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			prefix, gateway, err := floatingIPPrefixAndGateway(tt.ip, externalSubnets)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(prefix).To(Equal(tt.wantPrefix))
+			g.Expect(gateway).To(Equal(tt.wantGateway))
+		})
+	}
+}
+
+func Test_nextAvailableFloatingIP(t *testing.T) {
+	g := NewWithT(t)
+
+	pool := &infrav1.OpenStackFloatingIPPool{
+		Spec: infrav1.OpenStackFloatingIPPoolSpec{
+			PreAllocatedFloatingIPs: []string{"192.0.2.10", "192.0.2.11"},
+		},
+	}
+	state := &floatingIPPoolState{
+		preAllocated: map[string]bool{"192.0.2.10": true, "192.0.2.11": true},
+		created: map[string]*floatingips.FloatingIP{
+			"192.0.2.21": {FloatingIP: "192.0.2.21"},
+			"192.0.2.20": {FloatingIP: "192.0.2.20", PortID: "foo"},
+			"192.0.2.22": {FloatingIP: "192.0.2.22"},
+		},
+		claimed: map[string]bool{"192.0.2.10": true},
+	}
+
+	// Pre-allocated floating IPs are used first
+	g.Expect(nextAvailableFloatingIP(pool, state)).To(Equal("192.0.2.11"))
+
+	// Floating IPs associated out of band are skipped
+	state.claimed["192.0.2.11"] = true
+	g.Expect(nextAvailableFloatingIP(pool, state)).To(Equal("192.0.2.21"))
+
+	state.claimed["192.0.2.21"] = true
+	state.claimed["192.0.2.22"] = true
+	g.Expect(nextAvailableFloatingIP(pool, state)).To(BeEmpty())
+
+	setFloatingIPPoolStatus(pool, state)
+	g.Expect(pool.Status.ClaimedIPs).To(Equal([]string{"192.0.2.10", "192.0.2.11", "192.0.2.21", "192.0.2.22"}))
+	g.Expect(pool.Status.AvailableIPs).To(BeEmpty())
+}
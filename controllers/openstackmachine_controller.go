@@ -18,21 +18,23 @@ package controllers
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -49,12 +51,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/approval"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/instanceha"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/httpdebug"
+	caporecord "sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
 )
 
 // OpenStackMachineReconciler reconciles a OpenStackMachine object.
@@ -62,6 +71,13 @@ type OpenStackMachineReconciler struct {
 	Client           client.Client
 	Recorder         record.EventRecorder
 	WatchFilterValue string
+	// InstanceActionPollInterval is the interval at which the actions on the
+	// instances of machines are polled to report the ones not requested by
+	// the controller as events. Zero disables the poll.
+	InstanceActionPollInterval time.Duration
+	// InstanceEvents triggers the reconcile of machines whose instance
+	// changed, e.g. on notifications from the cloud. Nil disables them.
+	InstanceEvents <-chan event.GenericEvent
 }
 
 const (
@@ -71,11 +87,18 @@ const (
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackservers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinetemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
 func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, span := tracing.StartReconcile(ctx, "OpenStackMachine", req)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
 	log := ctrl.LoggerFrom(ctx)
 
 	// Fetch the OpenStackMachine instance.
@@ -106,7 +129,7 @@ func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
 	if err != nil {
 		log.Info("Machine is missing cluster label or cluster does not exist")
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.waitForClusterInfrastructure(ctx, openStackMachine, machine, "Cluster %s does not exist", machine.Spec.ClusterName)
 	}
 
 	log = log.WithValues("cluster", cluster.Name)
@@ -122,11 +145,15 @@ func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 	if infraCluster == nil {
 		log.Info("OpenStackCluster is not ready yet")
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.waitForClusterInfrastructure(ctx, openStackMachine, machine, "OpenStackCluster of Cluster %s does not exist", cluster.Name)
 	}
 
 	log = log.WithValues("openStackCluster", infraCluster.Name)
 
+	if infraCluster.Annotations[infrav1.DebugHTTPAnnotation] == "true" {
+		ctx = httpdebug.WithLogging(ctrl.LoggerInto(ctx, log))
+	}
+
 	// Initialize the patch helper
 	patchHelper, err := patch.NewHelper(openStackMachine, r.Client)
 	if err != nil {
@@ -135,12 +162,21 @@ func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	// Always patch the openStackMachine when exiting this function so we can persist any OpenStackMachine changes.
 	defer func() {
-		if err := patchMachine(ctx, patchHelper, openStackMachine, machine); err != nil {
+		var patchOpts []patch.Option
+		// The spec is only observed once it was reconciled successfully
+		if reterr == nil {
+			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
+		}
+		if err := patchMachine(ctx, patchHelper, openStackMachine, machine, patchOpts...); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, err})
 		}
 	}()
 
-	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachine(ctx, r.Client, openStackMachine)
+	if err := ensureIdentitySecretIsMoved(ctx, r.Client, openStackMachine.Namespace, infraCluster.Spec.MachineSpecWithDefaults(&openStackMachine.Spec).IdentityRef); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachine(ctx, r.Client, infraCluster, openStackMachine)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -150,6 +186,7 @@ func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		ProviderClientOpts: clientOpts,
 		ProjectID:          projectID,
 		Logger:             log,
+		Timeouts:           reconcileTimeouts(infraCluster),
 	}
 
 	// Handle deleted machines
@@ -157,8 +194,13 @@ func (r *OpenStackMachineReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return r.reconcileDelete(ctx, scope, patchHelper, cluster, infraCluster, machine, openStackMachine)
 	}
 
+	// Consoles are also published for failed machines, which are not reconciled otherwise
+	r.reconcileConsole(ctx, scope, cluster, openStackMachine)
+	r.requestNetworkingReconcile(ctx, scope, openStackMachine)
+
 	// Handle non-deleted clusters
-	return r.reconcileNormal(ctx, scope, patchHelper, cluster, infraCluster, machine, openStackMachine)
+	result, err := r.reconcileNormal(ctx, scope, patchHelper, cluster, infraCluster, machine, openStackMachine)
+	return r.withInstanceActionPoll(openStackMachine, result, err)
 }
 
 func patchMachine(ctx context.Context, patchHelper *patch.Helper, openStackMachine *infrav1.OpenStackMachine, machine *clusterv1.Machine, options ...patch.Option) error {
@@ -168,7 +210,10 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, openStackMachi
 	}
 
 	if util.IsControlPlaneMachine(machine) {
-		applicableConditions = append(applicableConditions, infrav1.APIServerIngressReadyCondition)
+		applicableConditions = append(applicableConditions, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPReadyCondition)
+	}
+	if !util.IsControlPlaneMachine(machine) && openStackMachine.Spec.FloatingIPPoolRef != nil {
+		applicableConditions = append(applicableConditions, infrav1.FloatingIPReadyCondition)
 	}
 
 	conditions.SetSummary(openStackMachine,
@@ -183,13 +228,22 @@ func patchMachine(ctx context.Context, patchHelper *patch.Helper, openStackMachi
 			clusterv1.ReadyCondition,
 			infrav1.InstanceReadyCondition,
 			infrav1.APIServerIngressReadyCondition,
+			infrav1.FloatingIPReadyCondition,
+			infrav1.QuotaExceededCondition,
+			infrav1.ProviderIDMismatchCondition,
 		}},
 	)
+	ctx, cancel := patchContext(ctx)
+	defer cancel()
 	return patchHelper.Patch(ctx, openStackMachine, options...)
 }
 
 func (r *OpenStackMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr)
+	if r.InstanceEvents != nil {
+		b = b.Watches(&source.Channel{Source: r.InstanceEvents}, &handler.EnqueueRequestForObject{})
+	}
+	return b.
 		WithOptions(options).
 		For(
 			&infrav1.OpenStackMachine{},
@@ -208,6 +262,8 @@ func (r *OpenStackMachineReconciler) SetupWithManager(ctx context.Context, mgr c
 				},
 			),
 		).
+		Owns(&infrav1.OpenStackServer{}).
+		Owns(&ipamv1.IPAddressClaim{}).
 		Watches(
 			&source.Kind{Type: &clusterv1.Machine{}},
 			handler.EnqueueRequestsFromMapFunc(util.MachineToInfrastructureMapFunc(infrav1.GroupVersion.WithKind("OpenStackMachine"))),
@@ -240,6 +296,10 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 		return ctrl.Result{}, err
 	}
 
+	if waiting, err := reconcilePreTerminateHooks(scope, computeService, machine, openStackMachine); err != nil || waiting {
+		return ctrl.Result{}, err
+	}
+
 	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
 		loadBalancerService, err := loadbalancer.NewService(scope)
 		if err != nil {
@@ -253,31 +313,58 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 		}
 	}
 
-	instanceStatus, err := computeService.GetInstanceStatusByName(openStackMachine, openStackMachine.Name)
+	// Release the floating IP of the machine to its pool
+	if openStackMachine.Spec.FloatingIPPoolRef != nil {
+		if err := deleteIPAddressClaim(ctx, r.Client, openStackMachine.Namespace, openStackMachine.Name); err != nil {
+			conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Releasing floating IP failed: %v", err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The instance is deleted by the OpenStackServer of the machine
+	openStackServer := &infrav1.OpenStackServer{}
+	err = r.Client.Get(ctx, client.ObjectKey{Namespace: openStackMachine.Namespace, Name: openStackMachine.Name}, openStackServer)
+	switch {
+	case err == nil:
+		if openStackServer.DeletionTimestamp.IsZero() {
+			if err := r.Client.Delete(ctx, openStackServer); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, errors.Wrap(err, "error deleting OpenStackServer")
+			}
+		}
+		scope.Logger.Info("Waiting for OpenStackServer to be deleted", "openStackServer", openStackServer.Name)
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{}, nil
+	case !apierrors.IsNotFound(err):
+		return ctrl.Result{}, err
+	}
+
+	// Machines created before they were backed by an OpenStackServer still
+	// own their instance
+	var instanceStatus *compute.InstanceStatus
+	if openStackMachine.Spec.InstanceID != nil {
+		instanceStatus, err = computeService.GetInstanceStatus(*openStackMachine.Spec.InstanceID)
+	} else {
+		instanceStatus, err = computeService.GetInstanceStatusByName(openStackMachine, openStackMachine.Name)
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if !openStackCluster.Spec.APIServerLoadBalancer.Enabled && util.IsControlPlaneMachine(machine) && openStackCluster.Spec.APIServerFloatingIP == "" {
-		if instanceStatus != nil {
-			instanceNS, err := instanceStatus.NetworkStatus()
-			if err != nil {
-				handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("error getting network status for OpenStack instance %s with ID %s: %v", instanceStatus.Name(), instanceStatus.ID(), err))
-				return ctrl.Result{}, nil
-			}
 
-			addresses := instanceNS.Addresses()
-			for _, address := range addresses {
-				if address.Type == corev1.NodeExternalIP {
-					if err = networkingService.DeleteFloatingIP(openStackMachine, address.Address); err != nil {
-						handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("error deleting Openstack floating IP: %v", err))
-						conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Deleting floating IP failed: %v", err)
-						return ctrl.Result{}, nil
-					}
-				}
+	// The floating IP of the API server is deleted once the server released
+	// its port, unless it is retained with the deleted cluster
+	var floatingIPs []string
+	retainFloatingIPs := !cluster.DeletionTimestamp.IsZero() && openStackCluster.RetainsFloatingIPs()
+	if !openStackCluster.Spec.APIServerLoadBalancer.Enabled && util.IsControlPlaneMachine(machine) && openStackCluster.Spec.APIServerFloatingIP == "" &&
+		openStackCluster.Spec.APIServerFloatingIPPoolRef == nil && openStackMachine.Spec.FloatingIPPoolRef == nil && !retainFloatingIPs {
+		for _, address := range openStackMachine.Status.Addresses {
+			if address.Type == corev1.NodeExternalIP {
+				floatingIPs = append(floatingIPs, address.Address)
 			}
 		}
 	}
 
+	// DeleteInstance is also called without an instance, so that a dangling
+	// root volume is deleted
 	instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, "")
 	if err != nil {
 		err = errors.Errorf("machine spec is invalid: %v", err)
@@ -287,11 +374,36 @@ func (r *OpenStackMachineReconciler) reconcileDelete(ctx context.Context, scope
 	}
 
 	if err := computeService.DeleteInstance(openStackMachine, instanceSpec, instanceStatus); err != nil {
-		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("error deleting OpenStack instance %s with ID %s: %v", instanceStatus.Name(), instanceStatus.ID(), err))
+		if errors.Is(err, compute.ErrDeletionInProgress) {
+			scope.Logger.Info("Waiting for OpenStack resources to be released", "reason", err.Error())
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.DeletionInProgressReason, clusterv1.ConditionSeverityInfo, "Waiting for the resources of the instance to be released: %v", err)
+			return ctrl.Result{RequeueAfter: deletionInProgressRequeueDuration}, nil
+		}
+		if instanceStatus != nil {
+			err = errors.Wrapf(err, "error deleting OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+		}
+		handleUpdateMachineError(scope.Logger, openStackMachine, err)
 		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceDeleteFailedReason, clusterv1.ConditionSeverityError, "Deleting instance failed: %v", err)
 		return ctrl.Result{}, nil
 	}
 
+	for _, floatingIP := range floatingIPs {
+		if err = networkingService.DeleteFloatingIP(openStackMachine, floatingIP); err != nil {
+			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Wrap(err, "error deleting Openstack floating IP"))
+			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Deleting floating IP failed: %v", err)
+			conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPDeleteFailedReason, clusterv1.ConditionSeverityError, "Deleting floating IP failed: %v", err)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if err := deleteMachineDNS(scope, openStackCluster, openStackMachine, clusterName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := deleteBootstrapObject(scope, openStackCluster, openStackMachine); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to delete bootstrap data")
+	}
+
 	controllerutil.RemoveFinalizer(openStackMachine, infrav1.MachineFinalizer)
 	scope.Logger.Info("Reconciled Machine delete successfully")
 	if err := patchHelper.Patch(ctx, openStackMachine); err != nil {
@@ -316,20 +428,23 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 
 	if !cluster.Status.InfrastructureReady {
 		scope.Logger.Info("Cluster infrastructure is not ready yet, requeuing machine")
-		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForClusterInfrastructureReason, clusterv1.ConditionSeverityInfo, "")
+		markWaiting(openStackMachine, infrav1.WaitingForClusterInfrastructureReason, "Infrastructure of Cluster %s is not ready", cluster.Name)
+		return ctrl.Result{RequeueAfter: waitForClusterInfrastructureReadyDuration}, nil
+	}
+
+	// The status of an OpenStackCluster recreated by clusterctl move is only restored by its next reconcile
+	if !openStackCluster.Status.Ready {
+		scope.Logger.Info("OpenStackCluster is not ready yet, requeuing machine")
+		markWaiting(openStackMachine, infrav1.WaitingForClusterInfrastructureReason, "OpenStackCluster %s is not ready", openStackCluster.Name)
 		return ctrl.Result{RequeueAfter: waitForClusterInfrastructureReadyDuration}, nil
 	}
 
 	// Make sure bootstrap data is available and populated.
 	if machine.Spec.Bootstrap.DataSecretName == nil {
 		scope.Logger.Info("Bootstrap data secret reference is not yet available")
-		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+		markWaiting(openStackMachine, infrav1.WaitingForBootstrapDataReason, "Bootstrap data of Machine %s is not available", machine.Name)
 		return ctrl.Result{}, nil
 	}
-	userData, err := r.getBootstrapData(ctx, machine, openStackMachine)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
 	scope.Logger.Info("Reconciling Machine")
 
 	clusterName := fmt.Sprintf("%s-%s", cluster.ObjectMeta.Namespace, cluster.Name)
@@ -338,51 +453,92 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	useTemplateLookups(ctx, r.Client, scope, openStackMachine, computeService)
 
 	networkingService, err := networking.NewService(scope)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	instanceStatus, err := r.getOrCreate(scope.Logger, cluster, openStackCluster, machine, openStackMachine, computeService, userData)
+	balanced := balancesFailureDomain(openStackCluster, machine)
+	if balanced {
+		ok, err := r.reconcileWorkerFailureDomain(ctx, scope, cluster, openStackCluster, openStackMachine)
+		if err != nil || !ok {
+			return ctrl.Result{RequeueAfter: waitForFailureDomainCapacityDuration}, err
+		}
+	}
+
+	openStackServer, err := r.getOrCreateServer(ctx, scope, computeService, cluster, openStackCluster, machine, openStackMachine)
+	var quotaErr *quotaExceededError
+	if errors.As(err, &quotaErr) {
+		// Not a failure of the machine: it is created once the quotas leave enough for it
+		scope.Logger.Info("Waiting for quota to create OpenStack instance", "reason", quotaErr.Error())
+		return ctrl.Result{RequeueAfter: waitForQuotaDuration}, nil
+	}
+	var gpuErr *noGPUHostsError
+	if errors.As(err, &gpuErr) {
+		// Not a failure of the machine either: hosts may still be added to the host aggregates
+		scope.Logger.Info("Waiting for GPU hosts to create OpenStack instance", "reason", gpuErr.Error())
+		return ctrl.Result{RequeueAfter: waitForGPUHostsDuration}, nil
+	}
 	if err != nil {
-		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("OpenStack instance cannot be created: %v", err))
-		// Conditions set in getOrCreate
-		return ctrl.Result{}, err
+		// Conditions set in getOrCreateServer
+		return ctrl.Result{}, ignorePermanentError(scope.Logger, err)
 	}
 
-	// Set an error message if we couldn't find the instance.
-	if instanceStatus == nil {
-		handleUpdateMachineError(scope.Logger, openStackMachine, errors.New("OpenStack instance cannot be found"))
-		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotFoundReason, clusterv1.ConditionSeverityError, "")
+	if balanced || len(openStackCluster.Spec.MachineSpecWithDefaults(&openStackMachine.Spec).FallbackFailureDomains) > 0 {
+		unscheduled, err := r.reconcileUnscheduledServer(ctx, scope, openStackCluster, machine, openStackMachine, openStackServer)
+		if err != nil || unscheduled {
+			return ctrl.Result{RequeueAfter: waitForInstanceBecomeActiveToReconcile}, err
+		}
+	}
+
+	if openStackServer.Status.InstanceID == nil {
+		// The server reports why it could not create the instance. Errors
+		// which it does not retry are failures of the machine.
+		if condition := conditions.Get(openStackServer, infrav1.InstanceReadyCondition); condition != nil && condition.Status == corev1.ConditionFalse {
+			if condition.Severity == clusterv1.ConditionSeverityError {
+				handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("OpenStack instance cannot be created: %s", condition.Message))
+			}
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, condition.Reason, condition.Severity, "%s", condition.Message)
+			return ctrl.Result{}, nil
+		}
+
+		scope.Logger.Info("Waiting for OpenStackServer to create the instance", "openStackServer", openStackServer.Name)
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, clusterv1.ConditionSeverityInfo, "Waiting for OpenStackServer %s", openStackServer.Name)
 		return ctrl.Result{}, nil
 	}
 
 	// TODO(sbueringer) From CAPA: TODO(ncdc): move this validation logic into a validating webhook (for us: create validation logic in webhook)
 
-	openStackMachine.Spec.ProviderID = pointer.StringPtr(fmt.Sprintf("openstack:///%s", instanceStatus.ID()))
-	openStackMachine.Spec.InstanceID = pointer.StringPtr(instanceStatus.ID())
+	instanceID := *openStackServer.Status.InstanceID
+	scope.Logger = scope.Logger.WithValues("instanceID", instanceID)
+	openStackMachine.Spec.ProviderID = pointer.StringPtr(fmt.Sprintf("openstack:///%s", instanceID))
+	openStackMachine.Spec.InstanceID = pointer.StringPtr(instanceID)
 
-	state := instanceStatus.State()
-	openStackMachine.Status.InstanceState = &state
+	openStackMachine.Status.InstanceState = openStackServer.Status.InstanceState
+	openStackMachine.Status.Addresses = openStackServer.Status.Addresses
+	openStackMachine.Status.AvailabilityZone = openStackServer.Status.AvailabilityZone
+	openStackMachine.Status.ResourceIDs = openStackServer.Status.ResourceIDs
 
-	instanceNS, err := instanceStatus.NetworkStatus()
-	if err != nil {
-		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("Unable to get network status for OpenStack instance %s with ID %s: %v", instanceStatus.Name(), instanceStatus.ID(), err))
-		return ctrl.Result{}, nil
+	if r.InstanceActionPollInterval > 0 {
+		reconcileInstanceActions(scope, computeService, openStackMachine, instanceID)
 	}
 
-	addresses := instanceNS.Addresses()
-	openStackMachine.Status.Addresses = addresses
+	var state infrav1.InstanceState
+	if openStackServer.Status.InstanceState != nil {
+		state = *openStackServer.Status.InstanceState
+	}
 
-	switch instanceStatus.State() {
+	switch state {
 	case infrav1.InstanceStateActive:
-		scope.Logger.Info("Machine instance is ACTIVE", "instance-id", instanceStatus.ID())
+		scope.Logger.Info("Machine instance is ACTIVE")
 		conditions.MarkTrue(openStackMachine, infrav1.InstanceReadyCondition)
 		openStackMachine.Status.Ready = true
 	case infrav1.InstanceStateError:
 		// Error is unexpected, thus we report error and never retry
-		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("OpenStack instance state %q is unexpected", instanceStatus.State()))
+		storeConsoleLog(scope, computeService, openStackCluster, openStackMachine, instanceID)
+		handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("OpenStack instance state %q is unexpected", state))
 		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceStateErrorReason, clusterv1.ConditionSeverityError, "")
 		return ctrl.Result{}, nil
 	case infrav1.InstanceStateDeleted:
@@ -393,52 +549,98 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 	default:
 		// The other state is normal (for example, migrating, shutoff) but we don't want to proceed until it's ACTIVE
 		// due to potential conflict or unexpected actions
-		scope.Logger.Info("Waiting for instance to become ACTIVE", "instance-id", instanceStatus.ID(), "status", instanceStatus.State())
-		conditions.MarkUnknown(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, "Instance state is not handled: %s", instanceStatus.State())
+		scope.Logger.Info("Waiting for instance to become ACTIVE", "status", state)
+		conditions.MarkUnknown(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, "Instance state is not handled: %s", state)
 		return ctrl.Result{RequeueAfter: waitForInstanceBecomeActiveToReconcile}, nil
 	}
 
-	if !util.IsControlPlaneMachine(machine) {
+	// The cloud controller manager and CAPO may disagree on the instance after it was rebuilt out of band
+	r.reconcileNodeProviderID(ctx, scope, cluster, machine, openStackMachine)
+
+	if !util.IsControlPlaneMachine(machine) && openStackMachine.Spec.FloatingIPPoolRef == nil && openStackCluster.Spec.MachineDNS == nil {
 		scope.Logger.Info("Not a Control plane machine, no floating ip reconcile needed, Reconciled Machine create successfully")
 		return ctrl.Result{}, nil
 	}
 
+	// The API server ingress, floating IP and DNS records need the ports of
+	// the instance, which are not part of the status of the server
+	instanceStatus, err := computeService.GetInstanceStatus(instanceID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if instanceStatus == nil {
+		handleUpdateMachineError(scope.Logger, openStackMachine, errors.New("OpenStack instance cannot be found"))
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotFoundReason, clusterv1.ConditionSeverityError, "")
+		return ctrl.Result{}, nil
+	}
+
+	// The management port is selected among the ports of the server, which
+	// may have several ports on the cluster network
+	instanceSpec := serverToInstanceSpec(openStackServer, "")
+
+	if openStackCluster.Spec.MachineDNS != nil {
+		port, err := computeService.GetManagementPort(openStackCluster, instanceSpec, instanceStatus)
+		if err != nil {
+			conditions.MarkFalse(openStackMachine, infrav1.DNSRecordsReadyCondition, infrav1.DNSRecordsCreateFailedReason, clusterv1.ConditionSeverityWarning, "Obtaining management port failed: %v", err)
+			return ctrl.Result{}, err
+		}
+		if err := reconcileMachineDNS(scope, openStackCluster, openStackMachine, port, clusterName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if openStackMachine.Spec.FloatingIPPoolRef != nil {
+		allocated, err := r.reconcileFloatingIPFromPool(ctx, scope, computeService, networkingService, openStackCluster, openStackMachine, instanceSpec, instanceStatus, clusterName)
+		if err != nil || !allocated {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !util.IsControlPlaneMachine(machine) {
+		scope.Logger.Info("Not a Control plane machine, no API server ingress reconcile needed, Reconciled Machine create successfully")
+		return ctrl.Result{}, nil
+	}
+
 	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
-		err = r.reconcileLoadBalancerMember(scope, openStackCluster, machine, openStackMachine, instanceNS, clusterName)
+		err = r.reconcileLoadBalancerMember(scope, computeService, openStackCluster, machine, openStackMachine, instanceSpec, instanceStatus, clusterName)
 		if err != nil {
-			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("LoadBalancerMember cannot be reconciled: %v", err))
+			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Wrap(err, "LoadBalancerMember cannot be reconciled"))
 			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.LoadBalancerMemberErrorReason, clusterv1.ConditionSeverityError, "Reconciling load balancer member failed: %v", err)
 			return ctrl.Result{}, nil
 		}
-	} else if !openStackCluster.Spec.DisableAPIServerFloatingIP {
+	} else if openStackCluster.Spec.ExternalAPIServerEndpoint == nil && !openStackCluster.Spec.DisableAPIServerFloatingIP {
 		floatingIPAddress := openStackCluster.Spec.ControlPlaneEndpoint.Host
 		if openStackCluster.Spec.APIServerFloatingIP != "" {
 			floatingIPAddress = openStackCluster.Spec.APIServerFloatingIP
 		}
 		fp, err := networkingService.GetOrCreateFloatingIP(openStackMachine, openStackCluster, clusterName, floatingIPAddress)
 		if err != nil {
-			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("Floating IP cannot be got or created: %v", err))
-			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Floating IP cannot be obtained or created: %v", err)
+			handleUpdateMachineError(scope.Logger, openStackMachine, errors.Wrap(err, "Floating IP cannot be got or created"))
+			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, reasonFor(err, infrav1.FloatingIPErrorReason), clusterv1.ConditionSeverityError, "Floating IP cannot be obtained or created: %v", err)
+			conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, reasonFor(err, infrav1.FloatingIPCreateFailedReason), clusterv1.ConditionSeverityError, "Floating IP cannot be obtained or created: %v", err)
 			return ctrl.Result{}, nil
 		}
-		port, err := computeService.GetManagementPort(openStackCluster, instanceStatus)
+		port, err := computeService.GetManagementPort(openStackCluster, instanceSpec, instanceStatus)
 		if err != nil {
 			err = errors.Errorf("getting management port for control plane machine %s: %v", machine.Name, err)
 			handleUpdateMachineError(scope.Logger, openStackMachine, err)
 			conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Obtaining management port for control plane machine failed: %v", err)
+			conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPAssociateFailedReason, clusterv1.ConditionSeverityError, "Obtaining management port for control plane machine failed: %v", err)
 			return ctrl.Result{}, nil
 		}
 
 		if len(fp.PortID) != 0 {
-			scope.Logger.Info("Floating IP already associated to a port:", "id", fp.ID, "fixed ip", fp.FixedIP, "portID", port.ID)
+			scope.Logger.V(logging.LevelDebug).Info("Floating IP already associated to a port", "id", fp.ID, "fixedIP", fp.FixedIP, "portID", port.ID)
 		} else {
 			err = networkingService.AssociateFloatingIP(openStackMachine, fp, port.ID)
 			if err != nil {
-				handleUpdateMachineError(scope.Logger, openStackMachine, errors.Errorf("Floating IP cannot be associated: %v", err))
+				handleUpdateMachineError(scope.Logger, openStackMachine, errors.Wrap(err, "Floating IP cannot be associated"))
 				conditions.MarkFalse(openStackMachine, infrav1.APIServerIngressReadyCondition, infrav1.FloatingIPErrorReason, clusterv1.ConditionSeverityError, "Associating floating IP failed: %v", err)
+				conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPAssociateFailedReason, clusterv1.ConditionSeverityError, "Associating floating IP failed: %v", err)
 				return ctrl.Result{}, nil
 			}
 		}
+		conditions.MarkTrue(openStackMachine, infrav1.FloatingIPReadyCondition)
 	}
 	conditions.MarkTrue(openStackMachine, infrav1.APIServerIngressReadyCondition)
 
@@ -446,30 +648,294 @@ func (r *OpenStackMachineReconciler) reconcileNormal(ctx context.Context, scope
 	return ctrl.Result{}, nil
 }
 
-func (r *OpenStackMachineReconciler) getOrCreate(logger logr.Logger, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, computeService *compute.Service, userData string) (*compute.InstanceStatus, error) {
-	instanceStatus, err := computeService.GetInstanceStatusByName(openStackMachine, openStackMachine.Name)
+// reconcileFloatingIPFromPool associates the floating IP allocated to the
+// machine by its floating IP pool. It returns false while the pool has not
+// allocated the floating IP yet.
+func (r *OpenStackMachineReconciler) reconcileFloatingIPFromPool(ctx context.Context, scope *scope.Scope, computeService *compute.Service, networkingService *networking.Service, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec, instanceStatus *compute.InstanceStatus, clusterName string) (bool, error) {
+	address, err := getOrCreateIPAddressClaim(ctx, r.Client, openStackMachine, infrav1.GroupVersion.WithKind("OpenStackMachine"), openStackMachine.Name, openStackMachine.Spec.FloatingIPPoolRef)
+	if err != nil {
+		conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPCreateFailedReason, clusterv1.ConditionSeverityWarning, "Claiming floating IP failed: %v", err)
+		return false, err
+	}
+	if address == nil {
+		scope.Logger.Info("Waiting for floating IP to be allocated", "pool", openStackMachine.Spec.FloatingIPPoolRef.Name)
+		conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.WaitingForFloatingIPReason, clusterv1.ConditionSeverityInfo, "Waiting for floating IP from %s %s", openStackMachine.Spec.FloatingIPPoolRef.Kind, openStackMachine.Spec.FloatingIPPoolRef.Name)
+		return false, nil
+	}
+
+	fp, err := networkingService.GetOrCreateFloatingIP(openStackMachine, openStackCluster, clusterName, address.Spec.Address)
+	if err != nil {
+		conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, reasonFor(err, infrav1.FloatingIPCreateFailedReason), clusterv1.ConditionSeverityWarning, "Floating IP cannot be obtained: %v", err)
+		return false, err
+	}
+	port, err := computeService.GetManagementPort(openStackCluster, instanceSpec, instanceStatus)
+	if err != nil {
+		conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPAssociateFailedReason, clusterv1.ConditionSeverityWarning, "Obtaining management port failed: %v", err)
+		return false, err
+	}
+	if fp.PortID != port.ID {
+		if err := networkingService.AssociateFloatingIP(openStackMachine, fp, port.ID); err != nil {
+			conditions.MarkFalse(openStackMachine, infrav1.FloatingIPReadyCondition, infrav1.FloatingIPAssociateFailedReason, clusterv1.ConditionSeverityWarning, "Associating floating IP failed: %v", err)
+			return false, err
+		}
+	}
+	conditions.MarkTrue(openStackMachine, infrav1.FloatingIPReadyCondition)
+	return true, nil
+}
+
+// getOrCreateServer returns the OpenStackServer which provides the instance
+// of the machine, creating it if it does not exist yet.
+func (r *OpenStackMachineReconciler) getOrCreateServer(ctx context.Context, scope *scope.Scope, computeService *compute.Service, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (*infrav1.OpenStackServer, error) {
+	openStackServer := &infrav1.OpenStackServer{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: openStackMachine.Namespace, Name: openStackMachine.Name}, openStackServer)
+	if err == nil {
+		if err := r.reconcileServerPorts(ctx, scope, openStackCluster, machine, openStackMachine, openStackServer); err != nil {
+			return nil, err
+		}
+		if err := r.reconcileServerRootVolume(ctx, scope, openStackCluster, openStackMachine, openStackServer); err != nil {
+			return nil, err
+		}
+		return openStackServer, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, "")
 	if err != nil {
+		err = errors.Errorf("machine spec is invalid: %v", err)
+		handleUpdateMachineError(scope.Logger, openStackMachine, err)
+		conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InvalidMachineSpecReason, clusterv1.ConditionSeverityError, err.Error())
 		return nil, err
 	}
 
-	if instanceStatus == nil {
-		logger.Info("Machine not exist, Creating Machine", "Machine", openStackMachine.Name)
-		instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, userData)
+	// Check the flavor, quotas and GPU hosts first, so that a machine which cannot be created reports why.
+	// Adopted instances already exist and need neither quota nor hosts.
+	if openStackMachine.Spec.InstanceID == nil {
+		unmet, err := computeService.GetUnmetFlavorRequirements(instanceSpec.Flavor, openStackMachine.Spec.FlavorRequirements)
 		if err != nil {
-			err = errors.Errorf("machine spec is invalid: %v", err)
-			handleUpdateMachineError(logger, openStackMachine, err)
-			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InvalidMachineSpecReason, clusterv1.ConditionSeverityError, err.Error())
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityWarning, "Checking flavor requirements failed: %v", err)
+			return nil, err
+		}
+		if len(unmet) > 0 {
+			err = errors.Errorf("flavor %s does not meet the flavor requirements: %s", instanceSpec.Flavor, strings.Join(unmet, ", "))
+			handleUpdateMachineError(scope.Logger, openStackMachine, err)
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.FlavorRequirementsNotMetReason, clusterv1.ConditionSeverityError, err.Error())
 			return nil, err
 		}
+		if err := reconcileMachineGPUs(scope, computeService, openStackMachine, instanceSpec); err != nil {
+			return nil, err
+		}
+		if err := reconcileMachineQuota(scope, computeService, openStackMachine, instanceSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	openStackServer = machineToServer(openStackCluster, machine, openStackMachine, instanceSpec)
+	openStackServer.Labels[clusterv1.ClusterLabelName] = cluster.Name
 
-		instanceStatus, err = computeService.CreateInstance(openStackMachine, openStackCluster, instanceSpec, cluster.Name)
+	if openStackMachine.Spec.InstanceID == nil {
+		userDataRef, err := r.reconcileBootstrapObject(ctx, scope, openStackCluster, machine, openStackMachine, instanceSpec)
 		if err != nil {
-			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityError, err.Error())
-			return nil, errors.Errorf("error creating Openstack instance: %v", err)
+			conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InstanceCreateFailedReason, clusterv1.ConditionSeverityWarning, "Preparing bootstrap data failed: %v", err)
+			return nil, err
+		}
+		if userDataRef != nil {
+			openStackServer.Spec.UserDataRef = userDataRef
+		}
+	}
+
+	scope.Logger.Info("Creating OpenStackServer", "openStackServer", openStackServer.Name)
+	if err := r.Client.Create(ctx, openStackServer); err != nil {
+		return nil, errors.Wrap(err, "error creating OpenStackServer")
+	}
+	return openStackServer, nil
+}
+
+// reconcileServerPorts passes the secondary ports added to or removed from
+// the machine and the security groups of its ports on to its
+// OpenStackServer, which updates the running instance. The other fields of
+// the ports of the server are kept.
+func (r *OpenStackMachineReconciler) reconcileServerPorts(ctx context.Context, scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, openStackServer *infrav1.OpenStackServer) error {
+	instanceSpec, err := machineToInstanceSpec(openStackCluster, machine, openStackMachine, "")
+	if err != nil {
+		return err
+	}
+	desired := machineToServer(openStackCluster, machine, openStackMachine, instanceSpec)
+
+	// The other ports of the machine cannot change, except for their
+	// security groups, which are updated on the running instance
+	var desiredFixed []infrav1.PortOpts
+	for i := range desired.Spec.Ports {
+		if !infrav1.IsHotPluggablePort(desired.Spec.Networks, desired.Spec.Ports, i) {
+			desiredFixed = append(desiredFixed, desired.Spec.Ports[i])
+		}
+	}
+	var ports []infrav1.PortOpts
+	for i := range openStackServer.Spec.Ports {
+		if !infrav1.IsHotPluggablePort(openStackServer.Spec.Networks, openStackServer.Spec.Ports, i) {
+			port := openStackServer.Spec.Ports[i]
+			if j := len(ports); j < len(desiredFixed) {
+				port.SecurityGroupFilters = desiredFixed[j].SecurityGroupFilters
+			}
+			ports = append(ports, port)
 		}
 	}
+	for i := range desired.Spec.Ports {
+		if infrav1.IsHotPluggablePort(desired.Spec.Networks, desired.Spec.Ports, i) {
+			ports = append(ports, desired.Spec.Ports[i])
+		}
+	}
+	if equality.Semantic.DeepEqual(ports, openStackServer.Spec.Ports) {
+		return nil
+	}
+
+	scope.Logger.Info("Updating ports of OpenStackServer", "openStackServer", openStackServer.Name)
+	patch := client.MergeFrom(openStackServer.DeepCopy())
+	openStackServer.Spec.Ports = ports
+	if err := r.Client.Patch(ctx, openStackServer, patch); err != nil {
+		return errors.Wrap(err, "failed to update ports of OpenStackServer")
+	}
+	return nil
+}
+
+// reconcileServerRootVolume passes an increased size of the root volume of
+// the machine on to its OpenStackServer, which extends the volume of the
+// running instance.
+func (r *OpenStackMachineReconciler) reconcileServerRootVolume(ctx context.Context, scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine, openStackServer *infrav1.OpenStackServer) error {
+	rootVolume := openStackCluster.Spec.MachineSpecWithDefaults(&openStackMachine.Spec).RootVolume
+	if rootVolume == nil || openStackServer.Spec.RootVolume == nil || rootVolume.Size <= openStackServer.Spec.RootVolume.Size {
+		return nil
+	}
 
-	return instanceStatus, nil
+	scope.Logger.Info("Extending root volume of OpenStackServer", "openStackServer", openStackServer.Name, "size", rootVolume.Size)
+	patch := client.MergeFrom(openStackServer.DeepCopy())
+	openStackServer.Spec.RootVolume.Size = rootVolume.Size
+	if err := r.Client.Patch(ctx, openStackServer, patch); err != nil {
+		return errors.Wrap(err, "failed to update root volume of OpenStackServer")
+	}
+	return nil
+}
+
+// machineToServer returns the OpenStackServer which provides the instance
+// described by instanceSpec. The server resolves everything it would
+// otherwise take from the cluster: its identity and the ports on the
+// cluster network.
+func machineToServer(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec) *infrav1.OpenStackServer {
+	openStackServer := &infrav1.OpenStackServer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      openStackMachine.Name,
+			Namespace: openStackMachine.Namespace,
+			Labels:    map[string]string{},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(openStackMachine, infrav1.GroupVersion.WithKind("OpenStackMachine")),
+			},
+		},
+		Spec: infrav1.OpenStackServerSpec{
+			// Machines which are already provisioned adopt their instance
+			InstanceID:       openStackMachine.Spec.InstanceID,
+			Region:           openStackMachine.Spec.Region,
+			Flavor:           instanceSpec.Flavor,
+			Image:            instanceSpec.Image,
+			ImageUUID:        instanceSpec.ImageUUID,
+			SSHKeyName:       instanceSpec.SSHKeyName,
+			Networks:         instanceSpec.Networks,
+			SecurityGroups:   instanceSpec.SecurityGroups,
+			Trunk:            instanceSpec.Trunk,
+			Tags:             instanceSpec.Tags,
+			ServerMetadata:   instanceSpec.Metadata,
+			ConfigDrive:      openStackMachine.Spec.ConfigDrive,
+			AvailabilityZone: instanceSpec.FailureDomain,
+			ServerGroupID:    instanceSpec.ServerGroupID,
+		},
+	}
+
+	if watchFilterValue, ok := machine.Labels[clusterv1.WatchLabel]; ok {
+		openStackServer.Labels[clusterv1.WatchLabel] = watchFilterValue
+	}
+
+	// The server is created from the same template as the machine, so that
+	// it reuses the images and flavors resolved for the template
+	for _, annotation := range []string{clusterv1.TemplateClonedFromNameAnnotation, clusterv1.TemplateClonedFromGroupKindAnnotation} {
+		if value, ok := openStackMachine.Annotations[annotation]; ok {
+			if openStackServer.Annotations == nil {
+				openStackServer.Annotations = map[string]string{}
+			}
+			openStackServer.Annotations[annotation] = value
+		}
+	}
+
+	if machineSpec := openStackCluster.Spec.MachineSpecWithDefaults(&openStackMachine.Spec); machineSpec.IdentityRef != nil {
+		openStackServer.Spec.IdentityRef = machineSpec.IdentityRef
+		openStackServer.Spec.CloudName = machineSpec.CloudName
+	} else {
+		openStackServer.Spec.IdentityRef = openStackCluster.Spec.IdentityRef
+		openStackServer.Spec.CloudName = openStackCluster.Spec.CloudName
+	}
+
+	if machine.Spec.Bootstrap.DataSecretName != nil {
+		openStackServer.Spec.UserDataRef = &corev1.LocalObjectReference{Name: *machine.Spec.Bootstrap.DataSecretName}
+	}
+
+	// The storage availability zone of the failure domain is only known to
+	// the machine, so it is passed on with the root volume
+	if instanceSpec.RootVolume != nil {
+		rootVolume := *instanceSpec.RootVolume
+		if rootVolume.AvailabilityZone == "" {
+			rootVolume.AvailabilityZone = instanceSpec.StorageAZ
+		}
+		openStackServer.Spec.RootVolume = &rootVolume
+		openStackServer.Spec.RetainRootVolume = openStackCluster.RetainsVolumes()
+	}
+
+	// Ports without a network are created on the cluster network, existing
+	// ports are used as they are
+	clusterNetwork := openStackCluster.Status.Network
+	for _, port := range instanceSpec.Ports {
+		if port.Network == nil && port.ID == "" {
+			port.Network = &infrav1.NetworkFilter{ID: clusterNetwork.ID}
+			if len(port.FixedIPs) > 0 {
+				fixedIPs := append([]infrav1.FixedIP{}, port.FixedIPs...)
+				port.FixedIPs = append(fixedIPs, infrav1.FixedIP{
+					Subnet: &infrav1.SubnetFilter{ID: clusterNetwork.Subnet.ID},
+				})
+			}
+		}
+		openStackServer.Spec.Ports = append(openStackServer.Spec.Ports, port)
+	}
+
+	// Machines without networks or ports get a single port on the cluster network
+	if len(instanceSpec.Networks) == 0 && len(instanceSpec.Ports) == 0 {
+		openStackServer.Spec.Ports = []infrav1.PortOpts{{
+			Network: &infrav1.NetworkFilter{ID: clusterNetwork.ID},
+			Trunk:   pointer.BoolPtr(instanceSpec.Trunk),
+		}}
+	}
+
+	return openStackServer
+}
+
+// machineSpecToInstanceSpec returns the instance spec of the server, networks
+// and ports described by an OpenStackMachineSpec. It is shared by machines
+// and the bastion, which add their own failure domain, tags and managed
+// security group.
+func machineSpecToInstanceSpec(name string, spec *infrav1.OpenStackMachineSpec) *compute.InstanceSpec {
+	return &compute.InstanceSpec{
+		Name:           name,
+		Image:          spec.Image,
+		ImageUUID:      spec.ImageUUID,
+		Flavor:         spec.Flavor,
+		SSHKeyName:     spec.SSHKeyName,
+		Metadata:       spec.ServerMetadata,
+		ConfigDrive:    spec.ConfigDrive != nil && *spec.ConfigDrive,
+		RootVolume:     spec.RootVolume,
+		Subnet:         spec.Subnet,
+		ServerGroupID:  spec.ServerGroupID,
+		Trunk:          spec.Trunk,
+		SecurityGroups: spec.SecurityGroups,
+		Networks:       spec.Networks,
+		Ports:          spec.Ports,
+	}
 }
 
 func machineToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, userData string) (*compute.InstanceSpec, error) {
@@ -477,24 +943,30 @@ func machineToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, machine *
 		return nil, fmt.Errorf("create Options need be specified to create instace")
 	}
 
-	instanceSpec := compute.InstanceSpec{
-		Name:          openStackMachine.Name,
-		Image:         openStackMachine.Spec.Image,
-		ImageUUID:     openStackMachine.Spec.ImageUUID,
-		Flavor:        openStackMachine.Spec.Flavor,
-		SSHKeyName:    openStackMachine.Spec.SSHKeyName,
-		UserData:      userData,
-		Metadata:      openStackMachine.Spec.ServerMetadata,
-		ConfigDrive:   openStackMachine.Spec.ConfigDrive != nil && *openStackMachine.Spec.ConfigDrive,
-		RootVolume:    openStackMachine.Spec.RootVolume,
-		Subnet:        openStackMachine.Spec.Subnet,
-		ServerGroupID: openStackMachine.Spec.ServerGroupID,
-		Trunk:         openStackMachine.Spec.Trunk,
-	}
+	// Fields the machine does not set are taken from the machine defaults of
+	// the cluster
+	machineSpec := openStackCluster.Spec.MachineSpecWithDefaults(&openStackMachine.Spec)
+	instanceSpec := *machineSpecToInstanceSpec(openStackMachine.Name, machineSpec)
+	instanceSpec.UserData = userData
 
 	// Add the failure domain only if specified
-	if machine.Spec.FailureDomain != nil {
-		instanceSpec.FailureDomain = *machine.Spec.FailureDomain
+	var failureDomainAttributes map[string]string
+	if failureDomain := machineFailureDomain(machine, openStackMachine); failureDomain != "" {
+		instanceSpec.FailureDomain = failureDomain
+
+		// The failure domain is mapped to availability zones which may be
+		// named differently
+		failureDomainAttributes = openStackCluster.Status.FailureDomains[failureDomain].Attributes
+		if computeAZ, ok := failureDomainAttributes[infrav1.FailureDomainComputeAvailabilityZoneAttribute]; ok {
+			instanceSpec.FailureDomain = computeAZ
+		}
+		instanceSpec.StorageAZ = failureDomainAttributes[infrav1.FailureDomainStorageAvailabilityZoneAttribute]
+
+		// Machines without a server group join the managed server group of
+		// their failure domain
+		if instanceSpec.ServerGroupID == "" {
+			instanceSpec.ServerGroupID = failureDomainAttributes[infrav1.FailureDomainServerGroupIDAttribute]
+		}
 	}
 
 	machineTags := []string{}
@@ -521,8 +993,7 @@ func machineToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, machine *
 
 	instanceSpec.Tags = machineTags
 
-	instanceSpec.SecurityGroups = openStackMachine.Spec.SecurityGroups
-	if openStackCluster.Spec.ManagedSecurityGroups {
+	if openStackCluster.Spec.ManagedSecurityGroups != nil {
 		var managedSecurityGroup string
 		if util.IsControlPlaneMachine(machine) {
 			managedSecurityGroup = openStackCluster.Status.ControlPlaneSecurityGroup.ID
@@ -530,13 +1001,36 @@ func machineToInstanceSpec(openStackCluster *infrav1.OpenStackCluster, machine *
 			managedSecurityGroup = openStackCluster.Status.WorkerSecurityGroup.ID
 		}
 
-		instanceSpec.SecurityGroups = append(instanceSpec.SecurityGroups, infrav1.SecurityGroupParam{
-			UUID: managedSecurityGroup,
+		instanceSpec.SecurityGroups = append(instanceSpec.SecurityGroups, infrav1.SecurityGroupFilter{
+			ID: managedSecurityGroup,
 		})
 	}
 
-	instanceSpec.Networks = openStackMachine.Spec.Networks
-	instanceSpec.Ports = openStackMachine.Spec.Ports
+	// Masakari only recovers instances with the HA_Enabled metadata
+	if openStackCluster.Spec.InstanceHA != nil {
+		if _, ok := instanceSpec.Metadata[instanceha.MetadataKey]; !ok {
+			metadata := make(map[string]string, len(instanceSpec.Metadata)+1)
+			for k, v := range instanceSpec.Metadata {
+				metadata[k] = v
+			}
+			metadata[instanceha.MetadataKey] = instanceha.MetadataValue
+			instanceSpec.Metadata = metadata
+		}
+	}
+
+	// Machines without networks or ports get their default port in the
+	// subnet of the failure domain, if it has one
+	subnetID := failureDomainAttributes[infrav1.FailureDomainSubnetIDAttribute]
+	if len(instanceSpec.Networks) == 0 && len(instanceSpec.Ports) == 0 && subnetID != "" {
+		instanceSpec.Ports = []infrav1.PortOpts{{
+			Network: &infrav1.NetworkFilter{
+				ID: failureDomainAttributes[infrav1.FailureDomainNetworkIDAttribute],
+			},
+			FixedIPs: []infrav1.FixedIP{{
+				Subnet: &infrav1.SubnetFilter{ID: subnetID},
+			}},
+		}}
+	}
 
 	return &instanceSpec, nil
 }
@@ -546,11 +1040,38 @@ func handleUpdateMachineError(logger logr.Logger, openstackMachine *infrav1.Open
 	openstackMachine.Status.FailureReason = &err
 	openstackMachine.Status.FailureMessage = pointer.StringPtr(message.Error())
 	// TODO remove if this error is logged redundantly
-	logger.Error(fmt.Errorf(string(err)), message.Error())
+	logger.Error(fmt.Errorf(string(err)), message.Error(), "requestID", capoerrors.RequestID(message))
 }
 
-func (r *OpenStackMachineReconciler) reconcileLoadBalancerMember(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceNS *compute.InstanceNetworkStatus, clusterName string) error {
-	ip := instanceNS.IP(openStackCluster.Status.Network.Name)
+// ignorePermanentError returns nil if err is caused by an OpenStack API
+// request which was invalid, forbidden or referred to a missing resource.
+// Requeueing would only repeat the same request, so these errors are left in
+// the failure message and conditions until the object is changed.
+func ignorePermanentError(logger logr.Logger, err error) error {
+	if err != nil && capoerrors.IsPermanent(err) {
+		logger.Info("Not retrying permanent OpenStack API error until the object is changed", "error", err.Error(), "requestID", capoerrors.RequestID(err))
+		return nil
+	}
+	return err
+}
+
+// reasonFor returns ApprovalDeniedReason if err was caused by the approval
+// webhook denying the creation of a resource, and reason otherwise.
+func reasonFor(err error, reason string) string {
+	if approval.IsDenied(err) {
+		return infrav1.ApprovalDeniedReason
+	}
+	return reason
+}
+
+// reconcileLoadBalancerMember adds the address of the management port of the
+// machine to the API server load balancer.
+func (r *OpenStackMachineReconciler) reconcileLoadBalancerMember(scope *scope.Scope, computeService *compute.Service, openStackCluster *infrav1.OpenStackCluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec, instanceStatus *compute.InstanceStatus, clusterName string) error {
+	port, err := computeService.GetManagementPort(openStackCluster, instanceSpec, instanceStatus)
+	if err != nil {
+		return err
+	}
+	ip := compute.PortIP(port)
 	loadbalancerService, err := loadbalancer.NewService(scope)
 	if err != nil {
 		return err
@@ -573,17 +1094,17 @@ func (r *OpenStackMachineReconciler) OpenStackClusterToOpenStackMachines(ctx con
 
 		// Don't handle deleted OpenStackClusters
 		if !c.ObjectMeta.DeletionTimestamp.IsZero() {
-			log.V(4).Info("OpenStackClusters has a deletion timestamp, skipping mapping.")
+			log.V(logging.LevelDebug).Info("OpenStackCluster has a deletion timestamp, skipping mapping")
 			return nil
 		}
 
 		cluster, err := util.GetOwnerCluster(ctx, r.Client, c.ObjectMeta)
 		switch {
 		case apierrors.IsNotFound(err) || cluster == nil:
-			log.V(4).Info("Cluster for OpenStackCluster not found, skipping mapping.")
+			log.V(logging.LevelDebug).Info("Cluster for OpenStackCluster not found, skipping mapping")
 			return nil
 		case err != nil:
-			log.Error(err, "Failed to get owning cluster, skipping mapping.")
+			log.Error(err, "Failed to get owning cluster, skipping mapping")
 			return nil
 		}
 
@@ -591,25 +1112,6 @@ func (r *OpenStackMachineReconciler) OpenStackClusterToOpenStackMachines(ctx con
 	}
 }
 
-func (r *OpenStackMachineReconciler) getBootstrapData(ctx context.Context, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) (string, error) {
-	if machine.Spec.Bootstrap.DataSecretName == nil {
-		return "", errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
-	}
-
-	secret := &corev1.Secret{}
-	key := types.NamespacedName{Namespace: machine.Namespace, Name: *machine.Spec.Bootstrap.DataSecretName}
-	if err := r.Client.Get(ctx, key, secret); err != nil {
-		return "", errors.Wrapf(err, "failed to retrieve bootstrap data secret for Openstack Machine %s/%s", machine.Namespace, openStackMachine.Name)
-	}
-
-	value, ok := secret.Data["value"]
-	if !ok {
-		return "", errors.New("error retrieving bootstrap data: secret value key is missing")
-	}
-
-	return base64.StdEncoding.EncodeToString(value), nil
-}
-
 func (r *OpenStackMachineReconciler) requeueOpenStackMachinesForUnpausedCluster(ctx context.Context) handler.MapFunc {
 	log := ctrl.LoggerFrom(ctx)
 	return func(o client.Object) []ctrl.Request {
@@ -622,7 +1124,7 @@ func (r *OpenStackMachineReconciler) requeueOpenStackMachinesForUnpausedCluster(
 
 		// Don't handle deleted clusters
 		if !c.ObjectMeta.DeletionTimestamp.IsZero() {
-			log.V(4).Info("Cluster has a deletion timestamp, skipping mapping.")
+			log.V(logging.LevelDebug).Info("Cluster has a deletion timestamp, skipping mapping")
 			return nil
 		}
 
@@ -634,7 +1136,7 @@ func (r *OpenStackMachineReconciler) requestsForCluster(ctx context.Context, log
 	labels := map[string]string{clusterv1.ClusterLabelName: name}
 	machineList := &clusterv1.MachineList{}
 	if err := r.Client.List(ctx, machineList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
-		log.Error(err, "Failed to get owned Machines, skipping mapping.")
+		log.Error(err, "Failed to get owned Machines, skipping mapping")
 		return nil
 	}
 
@@ -654,7 +1156,33 @@ func (r *OpenStackMachineReconciler) getInfraCluster(ctx context.Context, cluste
 		Name:      cluster.Spec.InfrastructureRef.Name,
 	}
 	if err := r.Client.Get(ctx, openStackClusterName, openStackCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return openStackCluster, nil
 }
+
+// waitForClusterInfrastructure records that the machine waits for its
+// Cluster or OpenStackCluster to be created, which happens before the
+// reconcile of the machine starts.
+func (r *OpenStackMachineReconciler) waitForClusterInfrastructure(ctx context.Context, openStackMachine *infrav1.OpenStackMachine, machine *clusterv1.Machine, messageFormat string, messageArgs ...interface{}) error {
+	patchHelper, err := patch.NewHelper(openStackMachine, r.Client)
+	if err != nil {
+		return err
+	}
+	markWaiting(openStackMachine, infrav1.WaitingForClusterInfrastructureReason, messageFormat, messageArgs...)
+	return patchMachine(ctx, patchHelper, openStackMachine, machine)
+}
+
+// markWaiting marks the instance of the machine as not ready because the
+// machine waits for a dependency, with severity Info to tell it apart from a
+// failure. An event is recorded when the machine starts waiting for the
+// dependency, the condition keeps the time since which it is not ready.
+func markWaiting(openStackMachine *infrav1.OpenStackMachine, reason, messageFormat string, messageArgs ...interface{}) {
+	if !conditions.IsFalse(openStackMachine, infrav1.InstanceReadyCondition) || conditions.GetReason(openStackMachine, infrav1.InstanceReadyCondition) != reason {
+		caporecord.Eventf(openStackMachine, reason, messageFormat, messageArgs...)
+	}
+	conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, reason, clusterv1.ConditionSeverityInfo, messageFormat, messageArgs...)
+}
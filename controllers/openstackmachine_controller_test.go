@@ -17,14 +17,21 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
 )
 
@@ -133,7 +140,7 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			name: "Control plane security group",
 			openStackCluster: func() *infrav1.OpenStackCluster {
 				c := getDefaultOpenStackCluster()
-				c.Spec.ManagedSecurityGroups = true
+				c.Spec.ManagedSecurityGroups = &infrav1.ManagedSecurityGroups{}
 				return c
 			},
 			machine: func() *clusterv1.Machine {
@@ -146,7 +153,7 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			openStackMachine: getDefaultOpenStackMachine,
 			wantInstanceSpec: func() *compute.InstanceSpec {
 				i := getDefaultInstanceSpec()
-				i.SecurityGroups = []infrav1.SecurityGroupParam{{UUID: controlPlaneSecurityGroupUUID}}
+				i.SecurityGroups = []infrav1.SecurityGroupFilter{{ID: controlPlaneSecurityGroupUUID}}
 				return i
 			},
 			wantErr: false,
@@ -155,14 +162,14 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			name: "Worker security group",
 			openStackCluster: func() *infrav1.OpenStackCluster {
 				c := getDefaultOpenStackCluster()
-				c.Spec.ManagedSecurityGroups = true
+				c.Spec.ManagedSecurityGroups = &infrav1.ManagedSecurityGroups{}
 				return c
 			},
 			machine:          getDefaultMachine,
 			openStackMachine: getDefaultOpenStackMachine,
 			wantInstanceSpec: func() *compute.InstanceSpec {
 				i := getDefaultInstanceSpec()
-				i.SecurityGroups = []infrav1.SecurityGroupParam{{UUID: workerSecurityGroupUUID}}
+				i.SecurityGroups = []infrav1.SecurityGroupFilter{{ID: workerSecurityGroupUUID}}
 				return i
 			},
 			wantErr: false,
@@ -171,20 +178,20 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			name: "Extra security group",
 			openStackCluster: func() *infrav1.OpenStackCluster {
 				c := getDefaultOpenStackCluster()
-				c.Spec.ManagedSecurityGroups = true
+				c.Spec.ManagedSecurityGroups = &infrav1.ManagedSecurityGroups{}
 				return c
 			},
 			machine: getDefaultMachine,
 			openStackMachine: func() *infrav1.OpenStackMachine {
 				m := getDefaultOpenStackMachine()
-				m.Spec.SecurityGroups = []infrav1.SecurityGroupParam{{UUID: extraSecurityGroupUUID}}
+				m.Spec.SecurityGroups = []infrav1.SecurityGroupFilter{{ID: extraSecurityGroupUUID}}
 				return m
 			},
 			wantInstanceSpec: func() *compute.InstanceSpec {
 				i := getDefaultInstanceSpec()
-				i.SecurityGroups = []infrav1.SecurityGroupParam{
-					{UUID: extraSecurityGroupUUID},
-					{UUID: workerSecurityGroupUUID},
+				i.SecurityGroups = []infrav1.SecurityGroupFilter{
+					{ID: extraSecurityGroupUUID},
+					{ID: workerSecurityGroupUUID},
 				}
 				return i
 			},
@@ -210,6 +217,150 @@ func Test_machineToInstanceSpec(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Instance HA",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.InstanceHA = &infrav1.InstanceHA{Segment: "segment"}
+				return c
+			},
+			machine:          getDefaultMachine,
+			openStackMachine: getDefaultOpenStackMachine,
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.Metadata["HA_Enabled"] = "True"
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Mapped failure domain",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Status.FailureDomains = clusterv1.FailureDomains{
+					failureDomain: clusterv1.FailureDomainSpec{
+						Attributes: map[string]string{
+							infrav1.FailureDomainComputeAvailabilityZoneAttribute: "nova-1",
+							infrav1.FailureDomainStorageAvailabilityZoneAttribute: "cinder-1",
+							infrav1.FailureDomainNetworkIDAttribute:               "failure-domain-network-id",
+							infrav1.FailureDomainSubnetIDAttribute:                "failure-domain-subnet-id",
+						},
+					},
+				}
+				return c
+			},
+			machine:          getDefaultMachine,
+			openStackMachine: getDefaultOpenStackMachine,
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.FailureDomain = "nova-1"
+				i.StorageAZ = "cinder-1"
+				i.Ports = []infrav1.PortOpts{{
+					Network:  &infrav1.NetworkFilter{ID: "failure-domain-network-id"},
+					FixedIPs: []infrav1.FixedIP{{Subnet: &infrav1.SubnetFilter{ID: "failure-domain-subnet-id"}}},
+				}}
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Mapped failure domain with machine ports",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Status.FailureDomains = clusterv1.FailureDomains{
+					failureDomain: clusterv1.FailureDomainSpec{
+						Attributes: map[string]string{
+							infrav1.FailureDomainComputeAvailabilityZoneAttribute: "",
+							infrav1.FailureDomainNetworkIDAttribute:               "failure-domain-network-id",
+							infrav1.FailureDomainSubnetIDAttribute:                "failure-domain-subnet-id",
+						},
+					},
+				}
+				return c
+			},
+			machine: getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.Ports = []infrav1.PortOpts{{Description: "machine-port"}}
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.FailureDomain = ""
+				i.Ports = []infrav1.PortOpts{{Description: "machine-port"}}
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Server group of failure domain",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Status.FailureDomains = clusterv1.FailureDomains{
+					failureDomain: clusterv1.FailureDomainSpec{
+						Attributes: map[string]string{
+							infrav1.FailureDomainServerGroupIDAttribute: "failure-domain-server-group",
+						},
+					},
+				}
+				return c
+			},
+			machine: getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.ServerGroupID = ""
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.ServerGroupID = "failure-domain-server-group"
+				return i
+			},
+			wantErr: false,
+		},
+		{
+			name: "Server group of machine takes precedence over failure domain",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Status.FailureDomains = clusterv1.FailureDomains{
+					failureDomain: clusterv1.FailureDomainSpec{
+						Attributes: map[string]string{
+							infrav1.FailureDomainServerGroupIDAttribute: "failure-domain-server-group",
+						},
+					},
+				}
+				return c
+			},
+			machine:          getDefaultMachine,
+			openStackMachine: getDefaultOpenStackMachine,
+			wantInstanceSpec: getDefaultInstanceSpec,
+			wantErr:          false,
+		},
+		{
+			name: "Machine defaults",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.MachineDefaults = &infrav1.MachineDefaults{
+					SSHKeyName:     "default-key",
+					ServerMetadata: map[string]string{"test-metadata": "default-value", "owner": "team-a"},
+					ServerGroupID:  "default-server-group",
+				}
+				return c
+			},
+			machine: getDefaultMachine,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.SSHKeyName = ""
+				return m
+			},
+			wantInstanceSpec: func() *compute.InstanceSpec {
+				i := getDefaultInstanceSpec()
+				i.SSHKeyName = "default-key"
+				i.Metadata = map[string]string{"test-metadata": "test-value", "owner": "team-a"}
+				return i
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -224,3 +375,195 @@ func Test_machineToInstanceSpec(t *testing.T) {
 		})
 	}
 }
+
+func Test_machineToServer(t *testing.T) {
+	RegisterTestingT(t)
+
+	getDefaultServerSpec := func() infrav1.OpenStackServerSpec {
+		return infrav1.OpenStackServerSpec{
+			Flavor:     flavorName,
+			Image:      imageName,
+			SSHKeyName: sshKeyName,
+			Tags:       []string{"test-tag"},
+			ServerMetadata: map[string]string{
+				"test-metadata": "test-value",
+			},
+			ConfigDrive:      pointer.BoolPtr(true),
+			AvailabilityZone: failureDomain,
+			ServerGroupID:    serverGroupUUID,
+			UserDataRef:      &corev1.LocalObjectReference{Name: "bootstrap-data"},
+			Ports: []infrav1.PortOpts{{
+				Network: &infrav1.NetworkFilter{ID: networkUUID},
+				Trunk:   pointer.BoolPtr(false),
+			}},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		openStackCluster func() *infrav1.OpenStackCluster
+		openStackMachine func() *infrav1.OpenStackMachine
+		wantServerSpec   func() infrav1.OpenStackServerSpec
+	}{
+		{
+			name:             "Defaults",
+			openStackCluster: getDefaultOpenStackCluster,
+			openStackMachine: getDefaultOpenStackMachine,
+			wantServerSpec:   getDefaultServerSpec,
+		},
+		{
+			name: "Identity of the cluster",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.IdentityRef = &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cluster-identity"}
+				c.Spec.CloudName = "cluster-cloud"
+				return c
+			},
+			openStackMachine: getDefaultOpenStackMachine,
+			wantServerSpec: func() infrav1.OpenStackServerSpec {
+				s := getDefaultServerSpec()
+				s.IdentityRef = &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cluster-identity"}
+				s.CloudName = "cluster-cloud"
+				return s
+			},
+		},
+		{
+			name: "Identity of the machine",
+			openStackCluster: func() *infrav1.OpenStackCluster {
+				c := getDefaultOpenStackCluster()
+				c.Spec.IdentityRef = &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cluster-identity"}
+				return c
+			},
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.IdentityRef = &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "machine-identity"}
+				m.Spec.Region = "test-region"
+				return m
+			},
+			wantServerSpec: func() infrav1.OpenStackServerSpec {
+				s := getDefaultServerSpec()
+				s.IdentityRef = &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "machine-identity"}
+				s.CloudName = "test-cloud"
+				s.Region = "test-region"
+				return s
+			},
+		},
+		{
+			name:             "Ports without a network are created on the cluster network",
+			openStackCluster: getDefaultOpenStackCluster,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.Ports = []infrav1.PortOpts{
+					{NameSuffix: "a"},
+					{NameSuffix: "b", FixedIPs: []infrav1.FixedIP{{IPAddress: "192.168.0.10"}}},
+					{NameSuffix: "c", Network: &infrav1.NetworkFilter{Name: "other-network"}},
+				}
+				return m
+			},
+			wantServerSpec: func() infrav1.OpenStackServerSpec {
+				s := getDefaultServerSpec()
+				s.Ports = []infrav1.PortOpts{
+					{NameSuffix: "a", Network: &infrav1.NetworkFilter{ID: networkUUID}},
+					{NameSuffix: "b", Network: &infrav1.NetworkFilter{ID: networkUUID}, FixedIPs: []infrav1.FixedIP{
+						{IPAddress: "192.168.0.10"},
+						{Subnet: &infrav1.SubnetFilter{ID: subnetUUID}},
+					}},
+					{NameSuffix: "c", Network: &infrav1.NetworkFilter{Name: "other-network"}},
+				}
+				return s
+			},
+		},
+		{
+			name:             "Root volume in the storage availability zone of the failure domain",
+			openStackCluster: getDefaultOpenStackCluster,
+			openStackMachine: func() *infrav1.OpenStackMachine {
+				m := getDefaultOpenStackMachine()
+				m.Spec.RootVolume = &infrav1.RootVolume{Size: 50}
+				return m
+			},
+			wantServerSpec: func() infrav1.OpenStackServerSpec {
+				s := getDefaultServerSpec()
+				s.RootVolume = &infrav1.RootVolume{Size: 50, AvailabilityZone: "storage-az"}
+				return s
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machine := getDefaultMachine()
+			machine.Spec.Bootstrap.DataSecretName = pointer.StringPtr("bootstrap-data")
+			openStackMachine := tt.openStackMachine()
+
+			instanceSpec, err := machineToInstanceSpec(tt.openStackCluster(), machine, openStackMachine, "")
+			Expect(err).NotTo(HaveOccurred())
+			instanceSpec.StorageAZ = "storage-az"
+
+			got := machineToServer(tt.openStackCluster(), machine, openStackMachine, instanceSpec)
+			Expect(got.Name).To(Equal(openStackMachineName))
+			Expect(got.Namespace).To(Equal(namespace))
+			Expect(got.OwnerReferences).To(HaveLen(1))
+			Expect(got.OwnerReferences[0].Kind).To(Equal("OpenStackMachine"))
+			Expect(got.Spec).To(Equal(tt.wantServerSpec()))
+
+			// The ports of the machine are left untouched
+			Expect(openStackMachine.Spec).To(Equal(tt.openStackMachine().Spec))
+		})
+	}
+
+	t.Run("Template the machine was cloned from", func(t *testing.T) {
+		openStackMachine := getDefaultOpenStackMachine()
+		openStackMachine.Annotations = map[string]string{
+			clusterv1.TemplateClonedFromNameAnnotation:      "test-template",
+			clusterv1.TemplateClonedFromGroupKindAnnotation: openStackMachineTemplateGroupKind,
+			"other": "annotation",
+		}
+		instanceSpec, err := machineToInstanceSpec(getDefaultOpenStackCluster(), getDefaultMachine(), openStackMachine, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		got := machineToServer(getDefaultOpenStackCluster(), getDefaultMachine(), openStackMachine, instanceSpec)
+		Expect(got.Annotations).To(Equal(map[string]string{
+			clusterv1.TemplateClonedFromNameAnnotation:      "test-template",
+			clusterv1.TemplateClonedFromGroupKindAnnotation: "OpenStackMachineTemplate.infrastructure.cluster.x-k8s.io",
+		}))
+	})
+}
+
+func Test_markWaiting(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackMachine := &infrav1.OpenStackMachine{}
+	markWaiting(openStackMachine, infrav1.WaitingForBootstrapDataReason, "Bootstrap data of Machine %s is not available", "machine")
+	condition := conditions.Get(openStackMachine, infrav1.InstanceReadyCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(infrav1.WaitingForBootstrapDataReason))
+	g.Expect(condition.Severity).To(Equal(clusterv1.ConditionSeverityInfo))
+	g.Expect(condition.Message).To(Equal("Bootstrap data of Machine machine is not available"))
+
+	// The time since which the machine waits is kept
+	since := metav1.NewTime(condition.LastTransitionTime.Add(-time.Hour))
+	condition.LastTransitionTime = since
+	openStackMachine.Status.Conditions = clusterv1.Conditions{*condition}
+	markWaiting(openStackMachine, infrav1.WaitingForBootstrapDataReason, "Bootstrap data of Machine %s is not available", "machine")
+	g.Expect(conditions.Get(openStackMachine, infrav1.InstanceReadyCondition).LastTransitionTime).To(Equal(since))
+}
+
+func Test_waitForClusterInfrastructure(t *testing.T) {
+	g := NewWithT(t)
+
+	testScheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+	openStackMachine := &infrav1.OpenStackMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: openStackMachineName, Namespace: namespace},
+	}
+	r := &OpenStackMachineReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(openStackMachine.DeepCopy()).Build()}
+
+	err := r.waitForClusterInfrastructure(context.TODO(), openStackMachine, getDefaultMachine(), "OpenStackCluster of Cluster %s does not exist", "cluster")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The condition is persisted although the reconcile of the machine did not start
+	got := &infrav1.OpenStackMachine{}
+	g.Expect(r.Client.Get(context.TODO(), client.ObjectKeyFromObject(openStackMachine), got)).To(Succeed())
+	g.Expect(conditions.GetReason(got, infrav1.InstanceReadyCondition)).To(Equal(infrav1.WaitingForClusterInfrastructureReason))
+	g.Expect(conditions.GetMessage(got, infrav1.InstanceReadyCondition)).To(Equal("OpenStackCluster of Cluster cluster does not exist"))
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
+)
+
+// OpenStackMachineTemplateReconciler reconciles a OpenStackMachineTemplate
+// object. It publishes the capacity of the flavor referenced by the template,
+// which allows the cluster autoscaler to scale node groups from zero.
+type OpenStackMachineTemplateReconciler struct {
+	Client           client.Client
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinetemplates,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinetemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachines,verbs=get;list;watch;patch
+
+func (r *OpenStackMachineTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, span := tracing.StartReconcile(ctx, "OpenStackMachineTemplate", req)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the OpenStackMachineTemplate instance.
+	openStackMachineTemplate := &infrav1.OpenStackMachineTemplate{}
+	err := r.Client.Get(ctx, req.NamespacedName, openStackMachineTemplate)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Nothing to publish for templates which are being deleted
+	if !openStackMachineTemplate.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the Cluster. Templates created for a ClusterClass are owned by
+	// the Cluster instead of carrying the cluster label.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, openStackMachineTemplate.ObjectMeta)
+	if err != nil {
+		cluster, err = util.GetOwnerCluster(ctx, r.Client, openStackMachineTemplate.ObjectMeta)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if cluster == nil {
+		log.Info("OpenStackMachineTemplate is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("cluster", cluster.Name)
+
+	if annotations.IsPaused(cluster, openStackMachineTemplate) {
+		log.Info("OpenStackMachineTemplate or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	if cluster.Spec.InfrastructureRef == nil {
+		log.Info("Cluster infrastructure is not set yet")
+		return ctrl.Result{}, nil
+	}
+
+	infraCluster := &infrav1.OpenStackCluster{}
+	infraClusterName := client.ObjectKey{
+		Namespace: openStackMachineTemplate.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, infraClusterName, infraCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Info("OpenStackCluster is not ready yet")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("openStackCluster", infraCluster.Name)
+
+	if err := r.reconcileMachineRootVolumes(ctx, log, cluster, openStackMachineTemplate); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(openStackMachineTemplate, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the openStackMachineTemplate when exiting this function so we can persist the capacity.
+	defer func() {
+		if err := patchHelper.Patch(ctx, openStackMachineTemplate); err != nil {
+			if reterr == nil {
+				reterr = errors.Wrapf(err, "error patching OpenStackMachineTemplate %s/%s", openStackMachineTemplate.Namespace, openStackMachineTemplate.Name)
+			}
+		}
+	}()
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachineTemplate(ctx, r.Client, infraCluster, openStackMachineTemplate)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scope := &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+	}
+
+	return ctrl.Result{}, r.reconcileNormal(scope, openStackMachineTemplate)
+}
+
+func (r *OpenStackMachineTemplateReconciler) reconcileNormal(scope *scope.Scope, openStackMachineTemplate *infrav1.OpenStackMachineTemplate) error {
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return err
+	}
+
+	capacity, err := computeService.GetFlavorCapacity(openStackMachineTemplate.Spec.Template.Spec.Flavor)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get capacity of flavor %s", openStackMachineTemplate.Spec.Template.Spec.Flavor)
+	}
+	openStackMachineTemplate.Status.Capacity = capacity
+
+	// The node group of the template cannot be scaled up if no host in any
+	// availability zone can run its instances
+	if _, ok := capacity[compute.GPUResourceName]; ok {
+		flavor := openStackMachineTemplate.Spec.Template.Spec.Flavor
+		hasHosts, err := computeService.HasFlavorGPUHosts(flavor, "")
+		if err != nil {
+			scope.Logger.Info("Skipping GPU host check", "error", err.Error())
+		} else if !hasHosts {
+			record.Warnf(openStackMachineTemplate, infrav1.InstanceNoGPUHostsReason, "%s", (&noGPUHostsError{flavor: flavor}).Error())
+		}
+	}
+
+	return nil
+}
+
+// reconcileMachineRootVolumes passes an increased size of the root volume of
+// a template with the ResizeRootVolumesAnnotation on to the machines created
+// from it, which extend their root volumes in place. Without the annotation
+// the size of the root volume cannot be changed.
+func (r *OpenStackMachineTemplateReconciler) reconcileMachineRootVolumes(ctx context.Context, log logr.Logger, cluster *clusterv1.Cluster, openStackMachineTemplate *infrav1.OpenStackMachineTemplate) error {
+	rootVolume := openStackMachineTemplate.Spec.Template.Spec.RootVolume
+	if openStackMachineTemplate.Annotations[infrav1.ResizeRootVolumesAnnotation] != "true" || rootVolume == nil || rootVolume.Size == 0 {
+		return nil
+	}
+
+	machineList := &infrav1.OpenStackMachineList{}
+	if err := r.Client.List(ctx, machineList, client.InNamespace(openStackMachineTemplate.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return err
+	}
+
+	for i := range machineList.Items {
+		openStackMachine := &machineList.Items[i]
+		if openStackMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation] != openStackMachineTemplate.Name ||
+			openStackMachine.Annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] != openStackMachineTemplateGroupKind {
+			continue
+		}
+		if openStackMachine.Spec.RootVolume == nil || openStackMachine.Spec.RootVolume.Size >= rootVolume.Size {
+			continue
+		}
+
+		log.Info("Extending root volume of OpenStackMachine", "openStackMachine", openStackMachine.Name, "size", rootVolume.Size)
+		patch := client.MergeFrom(openStackMachine.DeepCopy())
+		openStackMachine.Spec.RootVolume.Size = rootVolume.Size
+		if err := r.Client.Patch(ctx, openStackMachine, patch); err != nil {
+			return errors.Wrapf(err, "failed to update root volume of OpenStackMachine %s", openStackMachine.Name)
+		}
+	}
+	return nil
+}
+
+func (r *OpenStackMachineTemplateReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(
+			&infrav1.OpenStackMachineTemplate{},
+			builder.WithPredicates(
+				predicate.Funcs{
+					// Avoid reconciling if the event triggering the reconciliation is related to status updates
+					UpdateFunc: func(e event.UpdateEvent) bool {
+						oldTemplate := e.ObjectOld.(*infrav1.OpenStackMachineTemplate).DeepCopy()
+						newTemplate := e.ObjectNew.(*infrav1.OpenStackMachineTemplate).DeepCopy()
+						oldTemplate.Status = infrav1.OpenStackMachineTemplateStatus{}
+						newTemplate.Status = infrav1.OpenStackMachineTemplateStatus{}
+						oldTemplate.ObjectMeta.ResourceVersion = ""
+						newTemplate.ObjectMeta.ResourceVersion = ""
+						return !reflect.DeepEqual(oldTemplate, newTemplate)
+					},
+				},
+			),
+		).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Watches(
+			&source.Kind{Type: &clusterv1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.requeueOpenStackMachineTemplatesForUnpausedCluster(ctx)),
+			builder.WithPredicates(predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx))),
+		).
+		Complete(r)
+}
+
+func (r *OpenStackMachineTemplateReconciler) requeueOpenStackMachineTemplatesForUnpausedCluster(ctx context.Context) handler.MapFunc {
+	log := ctrl.LoggerFrom(ctx)
+	return func(o client.Object) []ctrl.Request {
+		c, ok := o.(*clusterv1.Cluster)
+		if !ok {
+			panic(fmt.Sprintf("Expected a Cluster but got a %T", o))
+		}
+
+		log := log.WithValues("objectMapper", "clusterToOpenStackMachineTemplate", "namespace", c.Namespace, "cluster", c.Name)
+
+		// Don't handle deleted clusters
+		if !c.ObjectMeta.DeletionTimestamp.IsZero() {
+			log.V(logging.LevelDebug).Info("Cluster has a deletion timestamp, skipping mapping")
+			return nil
+		}
+
+		templateList := &infrav1.OpenStackMachineTemplateList{}
+		if err := r.Client.List(ctx, templateList, client.InNamespace(c.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: c.Name}); err != nil {
+			log.Error(err, "Failed to list OpenStackMachineTemplates, skipping mapping")
+			return nil
+		}
+
+		result := make([]ctrl.Request, 0, len(templateList.Items))
+		for _, t := range templateList.Items {
+			result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: t.Namespace, Name: t.Name}})
+		}
+		return result
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_reconcileMachineRootVolumes(t *testing.T) {
+	g := NewWithT(t)
+
+	testScheme := runtime.NewScheme()
+	g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "test-cluster"}}
+	openStackMachine := func(name, template string, size int) *infrav1.OpenStackMachine {
+		return &infrav1.OpenStackMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test",
+				Name:      name,
+				Labels:    map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+				Annotations: map[string]string{
+					clusterv1.TemplateClonedFromNameAnnotation:      template,
+					clusterv1.TemplateClonedFromGroupKindAnnotation: openStackMachineTemplateGroupKind,
+				},
+			},
+			Spec: infrav1.OpenStackMachineSpec{RootVolume: &infrav1.RootVolume{Size: size}},
+		}
+	}
+	openStackMachineTemplate := func(resize bool) *infrav1.OpenStackMachineTemplate {
+		template := &infrav1.OpenStackMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "workers"},
+			Spec: infrav1.OpenStackMachineTemplateSpec{
+				Template: infrav1.OpenStackMachineTemplateResource{
+					Spec: infrav1.OpenStackMachineSpec{RootVolume: &infrav1.RootVolume{Size: 100}},
+				},
+			},
+		}
+		if resize {
+			template.Annotations = map[string]string{infrav1.ResizeRootVolumesAnnotation: "true"}
+		}
+		return template
+	}
+	rootVolumeSizes := func(c client.Client) map[string]int {
+		machineList := &infrav1.OpenStackMachineList{}
+		g.Expect(c.List(context.TODO(), machineList)).To(Succeed())
+		sizes := map[string]int{}
+		for _, m := range machineList.Items {
+			sizes[m.Name] = m.Spec.RootVolume.Size
+		}
+		return sizes
+	}
+
+	for _, resize := range []bool{false, true} {
+		r := &OpenStackMachineTemplateReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			openStackMachine("worker-0", "workers", 50),
+			openStackMachine("worker-1", "workers", 150),
+			openStackMachine("other-0", "other", 50),
+		).Build()}
+		g.Expect(r.reconcileMachineRootVolumes(context.TODO(), logr.Discard(), cluster, openStackMachineTemplate(resize))).To(Succeed())
+
+		want := map[string]int{"worker-0": 50, "worker-1": 150, "other-0": 50}
+		if resize {
+			// Only smaller root volumes of machines created from the template grow
+			want["worker-0"] = 100
+		}
+		g.Expect(rootVolumeSizes(r.Client)).To(Equal(want))
+	}
+}
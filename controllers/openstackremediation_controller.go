@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// defaultRemediationTimeout is how long a machine is given to become healthy
+// again after a reboot, if the OpenStackRemediation sets no timeout.
+const defaultRemediationTimeout = 5 * time.Minute
+
+// OpenStackRemediationReconciler reconciles a OpenStackRemediation object.
+type OpenStackRemediationReconciler struct {
+	Client           client.Client
+	Recorder         record.EventRecorder
+	WatchFilterValue string
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackremediations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackremediations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackremediationtemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines/status,verbs=get;update;patch
+
+// Reconcile remediates the machine of an OpenStackRemediation created by a
+// MachineHealthCheck. The server of the machine is soft rebooted, then hard
+// rebooted, each time giving the machine the timeout of the remediation to
+// become healthy again, in which case the MachineHealthCheck deletes the
+// OpenStackRemediation. Otherwise the owner of the machine is asked to
+// delete and recreate it.
+func (r *OpenStackRemediationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, span := tracing.StartReconcile(ctx, "OpenStackRemediation", req)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the OpenStackRemediation instance.
+	remediation := &infrav1.OpenStackRemediation{}
+	err := r.Client.Get(ctx, req.NamespacedName, remediation)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("openStackRemediation", remediation.Name)
+
+	// Nothing to clean up for deleted remediations
+	if !remediation.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Fetch the Machine.
+	machine, err := util.GetOwnerMachine(ctx, r.Client, remediation.ObjectMeta)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machine == nil {
+		log.Info("MachineHealthCheck has not yet set OwnerRef")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("machine", machine.Name)
+
+	// Fetch the Cluster.
+	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
+	if err != nil {
+		log.Info("Machine is missing cluster label or cluster does not exist")
+		return ctrl.Result{}, nil
+	}
+
+	log = log.WithValues("cluster", cluster.Name)
+
+	if annotations.IsPaused(cluster, remediation) {
+		log.Info("OpenStackRemediation or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	if !machine.DeletionTimestamp.IsZero() {
+		log.Info("Machine is being deleted, nothing to remediate")
+		return ctrl.Result{}, nil
+	}
+
+	infraRef := machine.Spec.InfrastructureRef
+	if infraRef.Kind != "OpenStackMachine" {
+		log.Info("Machine is not an OpenStackMachine, cannot remediate", "kind", infraRef.Kind)
+		return ctrl.Result{}, nil
+	}
+	openStackMachine := &infrav1.OpenStackMachine{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: infraRef.Name}, openStackMachine); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}, openStackCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(remediation, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the remediation when exiting this function so we can persist any OpenStackRemediation changes.
+	defer func() {
+		if err := patchHelper.Patch(ctx, remediation); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachine(ctx, r.Client, openStackCluster, openStackMachine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scope := &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+	}
+
+	return r.reconcileNormal(ctx, scope, machine, openStackMachine, remediation)
+}
+
+func (r *OpenStackRemediationReconciler) reconcileNormal(ctx context.Context, scope *scope.Scope, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine, remediation *infrav1.OpenStackRemediation) (ctrl.Result, error) {
+	next, wait := nextRemediationPhase(remediation, time.Now())
+	if next == "" {
+		return ctrl.Result{}, nil
+	}
+	if wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	if next != infrav1.RemediationPhaseWaitingForMachineDeletion && openStackMachine.Spec.InstanceID != nil {
+		computeService, err := compute.NewService(scope)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		hard := next == infrav1.RemediationPhaseHardRebooting
+		scope.Logger.Info("Rebooting server of unhealthy machine", "instanceID", *openStackMachine.Spec.InstanceID, "hard", hard)
+		instance := &compute.InstanceIdentifier{ID: *openStackMachine.Spec.InstanceID, Name: openStackMachine.Name}
+		err = computeService.RebootInstance(openStackMachine, instance, hard)
+		if err == nil {
+			setRemediationPhase(remediation, next)
+			return ctrl.Result{RequeueAfter: remediationTimeout(remediation)}, nil
+		}
+		if !capoerrors.IsNotFound(err) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to reboot server of machine")
+		}
+		// A server which no longer exists cannot be rebooted
+	}
+
+	scope.Logger.Info("Rebooting did not remediate machine, requesting its deletion")
+	machinePatchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	conditions.MarkFalse(machine, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "Rebooting the server did not remediate the machine")
+	if err := machinePatchHelper.Patch(ctx, machine, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{clusterv1.MachineOwnerRemediatedCondition}}); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to request deletion of machine")
+	}
+	setRemediationPhase(remediation, infrav1.RemediationPhaseWaitingForMachineDeletion)
+	return ctrl.Result{}, nil
+}
+
+// nextRemediationPhase returns the next phase of the remediation and how long
+// to wait before it is entered. The next phase is empty once the deletion of
+// the machine was requested.
+func nextRemediationPhase(remediation *infrav1.OpenStackRemediation, now time.Time) (infrav1.RemediationPhase, time.Duration) {
+	var next infrav1.RemediationPhase
+	switch remediation.Status.Phase {
+	case "":
+		next = infrav1.RemediationPhaseSoftRebooting
+		if remediation.Spec.SkipSoftReboot {
+			next = infrav1.RemediationPhaseHardRebooting
+		}
+		return next, 0
+	case infrav1.RemediationPhaseSoftRebooting:
+		next = infrav1.RemediationPhaseHardRebooting
+	case infrav1.RemediationPhaseHardRebooting:
+		next = infrav1.RemediationPhaseWaitingForMachineDeletion
+	default:
+		return "", 0
+	}
+
+	if lastRemediated := remediation.Status.LastRemediated; lastRemediated != nil {
+		if wait := lastRemediated.Add(remediationTimeout(remediation)).Sub(now); wait > 0 {
+			return next, wait
+		}
+	}
+	return next, 0
+}
+
+func remediationTimeout(remediation *infrav1.OpenStackRemediation) time.Duration {
+	if remediation.Spec.Timeout != nil {
+		return remediation.Spec.Timeout.Duration
+	}
+	return defaultRemediationTimeout
+}
+
+func setRemediationPhase(remediation *infrav1.OpenStackRemediation, phase infrav1.RemediationPhase) {
+	now := metav1.Now()
+	remediation.Status.Phase = phase
+	remediation.Status.LastRemediated = &now
+}
+
+func (r *OpenStackRemediationReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&infrav1.OpenStackRemediation{}).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_nextRemediationPhase(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	remediatedAt := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(-d))
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		spec      infrav1.OpenStackRemediationSpec
+		status    infrav1.OpenStackRemediationStatus
+		wantPhase infrav1.RemediationPhase
+		wantWait  time.Duration
+	}{
+		{
+			name:      "Soft reboot first",
+			wantPhase: infrav1.RemediationPhaseSoftRebooting,
+		},
+		{
+			name:      "Skip soft reboot",
+			spec:      infrav1.OpenStackRemediationSpec{SkipSoftReboot: true},
+			wantPhase: infrav1.RemediationPhaseHardRebooting,
+		},
+		{
+			name:      "Wait for machine after soft reboot",
+			status:    infrav1.OpenStackRemediationStatus{Phase: infrav1.RemediationPhaseSoftRebooting, LastRemediated: remediatedAt(time.Minute)},
+			wantPhase: infrav1.RemediationPhaseHardRebooting,
+			wantWait:  4 * time.Minute,
+		},
+		{
+			name:      "Hard reboot after timeout",
+			status:    infrav1.OpenStackRemediationStatus{Phase: infrav1.RemediationPhaseSoftRebooting, LastRemediated: remediatedAt(5 * time.Minute)},
+			wantPhase: infrav1.RemediationPhaseHardRebooting,
+		},
+		{
+			name:      "Custom timeout",
+			spec:      infrav1.OpenStackRemediationSpec{Timeout: &metav1.Duration{Duration: 10 * time.Minute}},
+			status:    infrav1.OpenStackRemediationStatus{Phase: infrav1.RemediationPhaseHardRebooting, LastRemediated: remediatedAt(5 * time.Minute)},
+			wantPhase: infrav1.RemediationPhaseWaitingForMachineDeletion,
+			wantWait:  5 * time.Minute,
+		},
+		{
+			name:      "Request deletion after hard reboot",
+			status:    infrav1.OpenStackRemediationStatus{Phase: infrav1.RemediationPhaseHardRebooting, LastRemediated: remediatedAt(time.Hour)},
+			wantPhase: infrav1.RemediationPhaseWaitingForMachineDeletion,
+		},
+		{
+			name:   "Done",
+			status: infrav1.OpenStackRemediationStatus{Phase: infrav1.RemediationPhaseWaitingForMachineDeletion, LastRemediated: remediatedAt(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			remediation := &infrav1.OpenStackRemediation{Spec: tt.spec, Status: tt.status}
+			phase, wait := nextRemediationPhase(remediation, now)
+			g.Expect(phase).To(Equal(tt.wantPhase))
+			g.Expect(wait).To(Equal(tt.wantWait))
+		})
+	}
+}
+
+func TestOpenStackRemediationReconciler_requestsMachineDeletion(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	testScheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(testScheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test-namespace"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(machine).Build()
+	r := &OpenStackRemediationReconciler{Client: fakeClient}
+
+	// The server of a machine without instance cannot be rebooted
+	remediation := &infrav1.OpenStackRemediation{}
+	openStackMachine := &infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test-namespace"}}
+	result, err := r.reconcileNormal(ctx, &scope.Scope{Logger: logr.Discard()}, machine, openStackMachine, remediation)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.RequeueAfter).To(BeZero())
+	g.Expect(remediation.Status.Phase).To(Equal(infrav1.RemediationPhaseWaitingForMachineDeletion))
+	g.Expect(remediation.Status.LastRemediated).NotTo(BeNil())
+
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(machine), machine)).To(Succeed())
+	g.Expect(conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(machine, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(clusterv1.WaitingForRemediationReason))
+}
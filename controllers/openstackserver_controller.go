@@ -0,0 +1,533 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/httpdebug"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// waitForBulkServersDuration is how long an OpenStackServer waits before
+// it checks again for an active server created in bulk.
+const waitForBulkServersDuration = 15 * time.Second
+
+// OpenStackServerReconciler reconciles a OpenStackServer object.
+type OpenStackServerReconciler struct {
+	Client           client.Client
+	Recorder         record.EventRecorder
+	WatchFilterValue string
+	// BulkCreateMinServers is the minimum number of OpenStackServers waiting
+	// for identical instances which are created with a single request. Bulk
+	// creation is disabled if it is less than 2.
+	BulkCreateMinServers int
+
+	bulk bulkCreator
+}
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackservers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackservers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinetemplates,verbs=get;list;watch
+
+func (r *OpenStackServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx, span := tracing.StartReconcile(ctx, "OpenStackServer", req)
+	defer func() {
+		tracing.EndSpan(span, reterr)
+	}()
+
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the OpenStackServer instance.
+	openStackServer := &infrav1.OpenStackServer{}
+	err := r.Client.Get(ctx, req.NamespacedName, openStackServer)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues("openStackServer", openStackServer.Name)
+
+	// A server which belongs to a cluster is paused together with the cluster, e.g. while it is moved
+	paused := annotations.HasPaused(openStackServer)
+	debugHTTP := false
+	clusterDeleted := false
+	var timeouts scope.Timeouts
+	if _, ok := openStackServer.Labels[clusterv1.ClusterLabelName]; ok {
+		cluster, err := util.GetClusterFromMetadata(ctx, r.Client, openStackServer.ObjectMeta)
+		if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			return ctrl.Result{}, err
+		}
+		if cluster != nil {
+			log = log.WithValues("cluster", cluster.Name)
+			paused = annotations.IsPaused(cluster, openStackServer)
+			clusterDeleted = !cluster.DeletionTimestamp.IsZero()
+			if debugHTTP, err = r.isHTTPDebugEnabled(ctx, cluster); err != nil {
+				return ctrl.Result{}, err
+			}
+			if timeouts, err = r.getClusterTimeouts(ctx, cluster); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+	// The instance of a paused machine is frozen together with the machine
+	if !paused {
+		if paused, err = r.isOwnerPaused(ctx, openStackServer); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if paused {
+		log.Info("OpenStackServer, its OpenStackMachine or linked Cluster is marked as paused. Won't reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	if debugHTTP {
+		ctx = httpdebug.WithLogging(ctrl.LoggerInto(ctx, log))
+	}
+
+	// Initialize the patch helper
+	patchHelper, err := patch.NewHelper(openStackServer, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always patch the openStackServer when exiting this function so we can persist any OpenStackServer changes.
+	defer func() {
+		if err := patchServer(ctx, patchHelper, openStackServer); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	if err := ensureIdentitySecretIsMoved(ctx, r.Client, openStackServer.Namespace, openStackServer.Spec.IdentityRef); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromServer(ctx, r.Client, openStackServer)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scope := &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+		Timeouts:           timeouts,
+	}
+
+	// Handle deleted servers
+	if !openStackServer.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, scope, patchHelper, openStackServer, clusterDeleted)
+	}
+
+	// Handle non-deleted servers
+	return r.reconcileNormal(ctx, scope, patchHelper, openStackServer)
+}
+
+func patchServer(ctx context.Context, patchHelper *patch.Helper, openStackServer *infrav1.OpenStackServer, options ...patch.Option) error {
+	// Always update the readyCondition by summarizing the state of other conditions.
+	conditions.SetSummary(openStackServer,
+		conditions.WithConditions(infrav1.InstanceReadyCondition),
+	)
+
+	// Patch the object, ignoring conflicts on the conditions owned by this controller.
+	options = append(options,
+		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			infrav1.InstanceReadyCondition,
+		}},
+	)
+	ctx, cancel := patchContext(ctx)
+	defer cancel()
+	return patchHelper.Patch(ctx, openStackServer, options...)
+}
+
+// isHTTPDebugEnabled returns whether the OpenStackCluster of the cluster
+// enables the logging of the requests to OpenStack.
+func (r *OpenStackServerReconciler) isHTTPDebugEnabled(ctx context.Context, cluster *clusterv1.Cluster) (bool, error) {
+	openStackCluster, err := r.getOpenStackCluster(ctx, cluster)
+	if err != nil || openStackCluster == nil {
+		return false, err
+	}
+	return openStackCluster.Annotations[infrav1.DebugHTTPAnnotation] == "true", nil
+}
+
+// getClusterTimeouts returns the timeouts configured for the servers of the
+// cluster.
+func (r *OpenStackServerReconciler) getClusterTimeouts(ctx context.Context, cluster *clusterv1.Cluster) (scope.Timeouts, error) {
+	openStackCluster, err := r.getOpenStackCluster(ctx, cluster)
+	if err != nil || openStackCluster == nil {
+		return scope.Timeouts{}, err
+	}
+	return reconcileTimeouts(openStackCluster), nil
+}
+
+// getOpenStackCluster returns the OpenStackCluster of the cluster, or nil if
+// it does not exist.
+func (r *OpenStackServerReconciler) getOpenStackCluster(ctx context.Context, cluster *clusterv1.Cluster) (*infrav1.OpenStackCluster, error) {
+	if cluster.Spec.InfrastructureRef == nil {
+		return nil, nil
+	}
+	openStackCluster := &infrav1.OpenStackCluster{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}, openStackCluster)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return openStackCluster, nil
+}
+
+// isOwnerPaused returns whether the server is owned by an OpenStackMachine
+// with the paused annotation.
+func (r *OpenStackServerReconciler) isOwnerPaused(ctx context.Context, openStackServer *infrav1.OpenStackServer) (bool, error) {
+	owner := metav1.GetControllerOf(openStackServer)
+	if owner == nil || owner.Kind != "OpenStackMachine" || owner.APIVersion != infrav1.GroupVersion.String() {
+		return false, nil
+	}
+
+	openStackMachine := &infrav1.OpenStackMachine{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: openStackServer.Namespace, Name: owner.Name}, openStackMachine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return annotations.HasPaused(openStackMachine), nil
+}
+
+func (r *OpenStackServerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(
+			&infrav1.OpenStackServer{},
+			builder.WithPredicates(
+				predicate.Funcs{
+					// Avoid reconciling if the event triggering the reconciliation is related to incremental status updates
+					UpdateFunc: func(e event.UpdateEvent) bool {
+						oldServer := e.ObjectOld.(*infrav1.OpenStackServer).DeepCopy()
+						newServer := e.ObjectNew.(*infrav1.OpenStackServer).DeepCopy()
+						oldServer.Status = infrav1.OpenStackServerStatus{}
+						newServer.Status = infrav1.OpenStackServerStatus{}
+						oldServer.ObjectMeta.ResourceVersion = ""
+						newServer.ObjectMeta.ResourceVersion = ""
+						return !reflect.DeepEqual(oldServer, newServer)
+					},
+				},
+			),
+		).
+		// The server of an OpenStackMachine has the name of the machine
+		Watches(
+			&source.Kind{Type: &infrav1.OpenStackMachine{}},
+			handler.EnqueueRequestsFromMapFunc(func(o client.Object) []ctrl.Request {
+				return []ctrl.Request{{NamespacedName: client.ObjectKeyFromObject(o)}}
+			}),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return annotations.HasPaused(e.ObjectOld) != annotations.HasPaused(e.ObjectNew)
+				},
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+}
+
+func (r *OpenStackServerReconciler) reconcileDelete(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, openStackServer *infrav1.OpenStackServer, clusterDeleted bool) (ctrl.Result, error) {
+	scope.Logger.Info("Reconciling Server delete")
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	instanceID := openStackServer.Status.InstanceID
+	if instanceID == nil {
+		instanceID = openStackServer.Spec.InstanceID
+	}
+
+	var instanceStatus *compute.InstanceStatus
+	if instanceID != nil {
+		instanceStatus, err = computeService.GetInstanceStatus(*instanceID)
+	} else {
+		instanceStatus, err = computeService.GetInstanceStatusByName(openStackServer, openStackServer.Name)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if instanceStatus != nil {
+		scope.Logger = scope.Logger.WithValues("instanceID", instanceStatus.ID())
+	}
+
+	// The root volume is only retained together with the cluster, the
+	// volumes of servers deleted on their own are still deleted
+	instanceSpec := serverToInstanceSpec(openStackServer, "")
+	instanceSpec.RetainRootVolume = openStackServer.Spec.RetainRootVolume && clusterDeleted
+	if err := computeService.DeleteInstance(openStackServer, instanceSpec, instanceStatus); err != nil {
+		if errors.Is(err, compute.ErrDeletionInProgress) {
+			scope.Logger.Info("Waiting for OpenStack resources to be released", "reason", err.Error())
+			conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.DeletionInProgressReason, clusterv1.ConditionSeverityInfo, "Waiting for the resources of the instance to be released: %v", err)
+			return ctrl.Result{RequeueAfter: deletionInProgressRequeueDuration}, nil
+		}
+		conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceDeleteFailedReason, clusterv1.ConditionSeverityError, "Deleting instance failed: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "error deleting OpenStack instance")
+	}
+
+	controllerutil.RemoveFinalizer(openStackServer, infrav1.ServerFinalizer)
+	scope.Logger.Info("Reconciled Server delete successfully")
+	if err := patchHelper.Patch(ctx, openStackServer); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *OpenStackServerReconciler) reconcileNormal(ctx context.Context, scope *scope.Scope, patchHelper *patch.Helper, openStackServer *infrav1.OpenStackServer) (_ ctrl.Result, reterr error) {
+	// If the OpenStackServer doesn't have our finalizer, add it.
+	controllerutil.AddFinalizer(openStackServer, infrav1.ServerFinalizer)
+	// Register the finalizer immediately to avoid orphaning OpenStack resources on delete
+	if err := patchHelper.Patch(ctx, openStackServer); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	scope.Logger.Info("Reconciling Server")
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	useTemplateLookups(ctx, r.Client, scope, openStackServer, computeService)
+
+	// Networking requested to be reconciled is repaired without changing the server
+	if handled, err := r.reconcileNetworking(scope, computeService, openStackServer); handled || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	instanceStatus, err := r.getOrCreate(ctx, scope, computeService, openStackServer)
+	if errors.Is(err, errBulkServersBuilding) {
+		scope.Logger.Info("Waiting for servers created in bulk to become ACTIVE")
+		conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, clusterv1.ConditionSeverityInfo, "Waiting for servers created in bulk")
+		return ctrl.Result{RequeueAfter: waitForBulkServersDuration}, nil
+	}
+	if err != nil {
+		// Transient errors are retried, while the server is not created
+		// again for errors which would only repeat themselves
+		severity := clusterv1.ConditionSeverityWarning
+		if capoerrors.IsPermanent(err) {
+			severity = clusterv1.ConditionSeverityError
+		}
+		reason := infrav1.InstanceCreateFailedReason
+		if capoerrors.IsQuotaExceeded(err) {
+			reason = infrav1.InstanceQuotaExceededReason
+		}
+		conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, reason, severity, err.Error())
+		return ctrl.Result{}, ignorePermanentError(scope.Logger, err)
+	}
+
+	if instanceStatus == nil {
+		conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceNotFoundReason, clusterv1.ConditionSeverityError, "OpenStack instance cannot be found")
+		return ctrl.Result{}, nil
+	}
+
+	instanceID := instanceStatus.ID()
+	scope.Logger = scope.Logger.WithValues("instanceID", instanceID)
+	openStackServer.Status.InstanceID = &instanceID
+
+	state := instanceStatus.State()
+	openStackServer.Status.InstanceState = &state
+	openStackServer.Status.AvailabilityZone = instanceStatus.AvailabilityZone()
+
+	instanceNS, err := instanceStatus.NetworkStatus()
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "unable to get network status for OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+	}
+	openStackServer.Status.Addresses = instanceNS.Addresses()
+
+	// Secondary ports added to or removed from the spec are attached to or
+	// detached from the running instance
+	if instanceStatus.State() == infrav1.InstanceStateActive {
+		attached, err := computeService.ReconcileInstancePorts(openStackServer, serverToInstanceSpec(openStackServer, ""), instanceID, openStackServer.Labels[clusterv1.ClusterLabelName], openStackServer.Status.AttachedPortNameSuffixes)
+		openStackServer.Status.AttachedPortNameSuffixes = attached
+		if err != nil {
+			conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstancePortsUpdateFailedReason, clusterv1.ConditionSeverityWarning, "Updating the ports of the instance failed: %v", err)
+			return ctrl.Result{}, err
+		}
+
+		// A root volume whose size was increased is extended in place
+		if err := computeService.ReconcileRootVolumeSize(openStackServer, serverToInstanceSpec(openStackServer, "")); err != nil {
+			conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceRootVolumeResizeFailedReason, clusterv1.ConditionSeverityWarning, "Extending the root volume of the instance failed: %v", err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	resourceIDs, err := computeService.GetInstanceResourceIDs(instanceStatus, openStackServer.Spec.Trunk)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "unable to get resources of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceStatus.ID())
+	}
+	openStackServer.Status.ResourceIDs = resourceIDs
+
+	switch instanceStatus.State() {
+	case infrav1.InstanceStateActive:
+		scope.Logger.Info("Server instance is ACTIVE")
+		conditions.MarkTrue(openStackServer, infrav1.InstanceReadyCondition)
+		openStackServer.Status.Ready = true
+	case infrav1.InstanceStateError:
+		// Error is unexpected, thus we report error and never retry
+		if instanceStatus.IsUnschedulable() {
+			conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceNoValidHostReason, clusterv1.ConditionSeverityError, "%s", instanceStatus.FaultMessage())
+		} else {
+			conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceStateErrorReason, clusterv1.ConditionSeverityError, "OpenStack instance state %q is unexpected", instanceStatus.State())
+		}
+		openStackServer.Status.Ready = false
+	case infrav1.InstanceStateDeleted:
+		// we should avoid further actions for DELETED VM
+		scope.Logger.Info("Instance state is DELETED, no actions")
+		conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceDeletedReason, clusterv1.ConditionSeverityError, "")
+		openStackServer.Status.Ready = false
+	default:
+		// The other state is normal (for example, migrating, shutoff) but we don't want to proceed until it's ACTIVE
+		// due to potential conflict or unexpected actions
+		scope.Logger.Info("Waiting for instance to become ACTIVE", "status", instanceStatus.State())
+		conditions.MarkUnknown(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstanceNotReadyReason, "Instance state is not handled: %s", instanceStatus.State())
+		openStackServer.Status.Ready = false
+		return ctrl.Result{RequeueAfter: waitForInstanceBecomeActiveToReconcile}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *OpenStackServerReconciler) getOrCreate(ctx context.Context, scope *scope.Scope, computeService *compute.Service, openStackServer *infrav1.OpenStackServer) (*compute.InstanceStatus, error) {
+	if openStackServer.Status.InstanceID != nil {
+		return computeService.GetInstanceStatus(*openStackServer.Status.InstanceID)
+	}
+
+	userData, err := r.getUserData(ctx, openStackServer)
+	if err != nil {
+		return nil, err
+	}
+	instanceSpec := serverToInstanceSpec(openStackServer, userData)
+
+	if openStackServer.Spec.InstanceID != nil {
+		scope.Logger.Info("Adopting existing instance", "instanceID", *openStackServer.Spec.InstanceID)
+		instanceStatus, err := computeService.AdoptInstance(openStackServer, instanceSpec, *openStackServer.Spec.InstanceID)
+		if err != nil {
+			return nil, errors.Wrap(err, "error adopting OpenStack instance")
+		}
+		return instanceStatus, nil
+	}
+
+	instanceStatus, err := computeService.GetInstanceStatusByName(openStackServer, openStackServer.Name)
+	if err != nil {
+		return nil, err
+	}
+	if instanceStatus != nil {
+		return instanceStatus, nil
+	}
+
+	if r.BulkCreateMinServers > 1 && compute.CanCreateInBulk(instanceSpec) {
+		instanceStatus, inBulk, err := r.getOrCreateInBulk(ctx, scope, computeService, openStackServer, instanceSpec)
+		if inBulk {
+			return instanceStatus, err
+		}
+		defer r.createdOnItsOwn(openStackServer)
+	}
+
+	scope.Logger.Info("Server does not exist, creating Server")
+	// Servers which are not part of a cluster have no cluster network, their ports name their networks
+	instanceStatus, err = computeService.CreateInstance(openStackServer, nil, instanceSpec, openStackServer.Labels[clusterv1.ClusterLabelName])
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Openstack instance")
+	}
+	return instanceStatus, nil
+}
+
+// getUserData returns the base64 encoded user data of the server.
+func (r *OpenStackServerReconciler) getUserData(ctx context.Context, openStackServer *infrav1.OpenStackServer) (string, error) {
+	if openStackServer.Spec.UserDataRef == nil {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: openStackServer.Namespace, Name: openStackServer.Spec.UserDataRef.Name}
+	if err := r.Client.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve user data secret for OpenStackServer %s/%s", openStackServer.Namespace, openStackServer.Name)
+	}
+
+	value, ok := secret.Data["value"]
+	if !ok {
+		return "", errors.New("error retrieving user data: secret value key is missing")
+	}
+
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+func serverToInstanceSpec(openStackServer *infrav1.OpenStackServer, userData string) *compute.InstanceSpec {
+	return &compute.InstanceSpec{
+		Name:             openStackServer.Name,
+		Image:            openStackServer.Spec.Image,
+		ImageUUID:        openStackServer.Spec.ImageUUID,
+		Flavor:           openStackServer.Spec.Flavor,
+		SSHKeyName:       openStackServer.Spec.SSHKeyName,
+		UserData:         userData,
+		Metadata:         openStackServer.Spec.ServerMetadata,
+		ConfigDrive:      openStackServer.Spec.ConfigDrive != nil && *openStackServer.Spec.ConfigDrive,
+		FailureDomain:    openStackServer.Spec.AvailabilityZone,
+		RootVolume:       openStackServer.Spec.RootVolume,
+		RetainRootVolume: openStackServer.Spec.RetainRootVolume,
+		ServerGroupID:    openStackServer.Spec.ServerGroupID,
+		Trunk:            openStackServer.Spec.Trunk,
+		Tags:             openStackServer.Spec.Tags,
+		SecurityGroups:   openStackServer.Spec.SecurityGroups,
+		Networks:         openStackServer.Spec.Networks,
+		Ports:            openStackServer.Spec.Ports,
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_isOwnerPaused(t *testing.T) {
+	openStackMachine := func(annotations map[string]string) *infrav1.OpenStackMachine {
+		return &infrav1.OpenStackMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test-namespace", Annotations: annotations},
+		}
+	}
+	ownedBy := func(openStackMachine *infrav1.OpenStackMachine) *infrav1.OpenStackServer {
+		openStackServer := &infrav1.OpenStackServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "test-namespace"},
+		}
+		if openStackMachine != nil {
+			openStackServer.OwnerReferences = []metav1.OwnerReference{
+				*metav1.NewControllerRef(openStackMachine, infrav1.GroupVersion.WithKind("OpenStackMachine")),
+			}
+		}
+		return openStackServer
+	}
+
+	tests := []struct {
+		name             string
+		openStackMachine *infrav1.OpenStackMachine
+		openStackServer  *infrav1.OpenStackServer
+		want             bool
+	}{
+		{
+			name:            "Standalone server",
+			openStackServer: ownedBy(nil),
+			want:            false,
+		},
+		{
+			name:             "Machine not paused",
+			openStackMachine: openStackMachine(nil),
+			openStackServer:  ownedBy(openStackMachine(nil)),
+			want:             false,
+		},
+		{
+			name:             "Machine paused",
+			openStackMachine: openStackMachine(map[string]string{clusterv1.PausedAnnotation: ""}),
+			openStackServer:  ownedBy(openStackMachine(nil)),
+			want:             true,
+		},
+		{
+			name:            "Machine deleted",
+			openStackServer: ownedBy(openStackMachine(nil)),
+			want:            false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			testScheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+			objects := []client.Object{}
+			if tt.openStackMachine != nil {
+				objects = append(objects, tt.openStackMachine)
+			}
+			r := &OpenStackServerReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objects...).Build()}
+
+			paused, err := r.isOwnerPaused(context.TODO(), tt.openStackServer)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(paused).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_isHTTPDebugEnabled(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "test-namespace"},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{Name: "openstack-cluster"},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		openStackCluster *infrav1.OpenStackCluster
+		want             bool
+	}{
+		{
+			name: "Annotation set",
+			openStackCluster: &infrav1.OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "openstack-cluster", Namespace: "test-namespace", Annotations: map[string]string{infrav1.DebugHTTPAnnotation: "true"}},
+			},
+			want: true,
+		},
+		{
+			name: "Annotation not set",
+			openStackCluster: &infrav1.OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "openstack-cluster", Namespace: "test-namespace"},
+			},
+			want: false,
+		},
+		{
+			name: "OpenStackCluster not found",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			testScheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+			objects := []client.Object{}
+			if tt.openStackCluster != nil {
+				objects = append(objects, tt.openStackCluster)
+			}
+			r := &OpenStackServerReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objects...).Build()}
+
+			enabled, err := r.isHTTPDebugEnabled(context.TODO(), cluster)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(enabled).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// reconcileOrphanedVolumes deletes the root volumes of the cluster whose
+// server is neither an OpenStackServer nor the bastion of a cluster in the
+// namespace of the cluster. A server deletes its root volume before its
+// OpenStackServer is gone, so the root volumes left are orphaned. Failing to
+// delete them is logged and does not fail the reconcile: they are retried
+// with the next reconcile.
+func (r *OpenStackClusterReconciler) reconcileOrphanedVolumes(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) {
+	managed, err := r.managedServerNames(ctx, cluster.Namespace)
+	if err != nil {
+		scope.Logger.Info("Skipping orphaned root volumes", "reason", err.Error())
+		return
+	}
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		scope.Logger.Info("Skipping orphaned root volumes", "reason", err.Error())
+		return
+	}
+	isManaged := func(serverName string) bool {
+		_, ok := managed[serverName]
+		return ok
+	}
+	if err := computeService.DeleteOrphanedVolumes(openStackCluster, cluster.Namespace, cluster.Name, isManaged); err != nil {
+		scope.Logger.Info("Failed to delete orphaned root volumes", "reason", err.Error())
+	}
+}
+
+// managedServerNames returns the names of the servers managed in the given
+// namespace: the OpenStackServers and the bastions of the Clusters.
+func (r *OpenStackClusterReconciler) managedServerNames(ctx context.Context, namespace string) (map[string]struct{}, error) {
+	serverList := &infrav1.OpenStackServerList{}
+	if err := r.Client.List(ctx, serverList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	clusterList := &clusterv1.ClusterList{}
+	if err := r.Client.List(ctx, clusterList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	managed := make(map[string]struct{}, len(serverList.Items)+len(clusterList.Items))
+	for _, server := range serverList.Items {
+		managed[server.Name] = struct{}{}
+	}
+	for _, c := range clusterList.Items {
+		managed[fmt.Sprintf("%s-bastion", c.Name)] = struct{}{}
+	}
+	return managed, nil
+}
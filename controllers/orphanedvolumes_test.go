@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_managedServerNames(t *testing.T) {
+	g := NewWithT(t)
+
+	testScheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(testScheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+
+	r := &OpenStackClusterReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cluster"}},
+		&clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "other-cluster"}},
+		&infrav1.OpenStackServer{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "cluster-control-plane-abcde"}},
+		&infrav1.OpenStackServer{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "other-cluster-md-0-fghij"}},
+	).Build()}
+
+	managed, err := r.managedServerNames(context.TODO(), "team-a")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(managed).To(HaveLen(2))
+	g.Expect(managed).To(HaveKey("cluster-control-plane-abcde"))
+	g.Expect(managed).To(HaveKey("cluster-bastion"))
+}
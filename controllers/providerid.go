@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// instanceIDFromProviderID returns the instance ID of a providerID set by
+// the OpenStack cloud controller manager, which has the form
+// openstack:///<ID> or openstack://<region>/<ID>. It returns an empty
+// string for providerIDs of other clouds.
+func instanceIDFromProviderID(providerID string) string {
+	if !strings.HasPrefix(providerID, "openstack://") {
+		return ""
+	}
+	path := strings.TrimPrefix(providerID, "openstack://")
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// nodeProviderIDMismatch returns why the providerID of the node does not
+// refer to the instance, or an empty string if it does. Nodes whose
+// providerID is not set yet are not checked.
+func nodeProviderIDMismatch(node *corev1.Node, instanceID string) string {
+	if node.Spec.ProviderID == "" {
+		return ""
+	}
+	nodeInstanceID := instanceIDFromProviderID(node.Spec.ProviderID)
+	if nodeInstanceID == instanceID {
+		return ""
+	}
+	return fmt.Sprintf("providerID %s of node %s does not refer to instance %s of the machine", node.Spec.ProviderID, node.Name, instanceID)
+}
+
+// reconcileNodeProviderID checks that the providerID of the Node of the
+// machine refers to the instance of the machine, and adds
+// ProviderIDMismatchCondition to the machine while it does not. The check is
+// advisory: if the workload cluster cannot be reached, it is skipped and the
+// condition is kept as it is.
+func (r *OpenStackMachineReconciler) reconcileNodeProviderID(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, machine *clusterv1.Machine, openStackMachine *infrav1.OpenStackMachine) {
+	if machine.Status.NodeRef == nil || openStackMachine.Spec.InstanceID == nil {
+		conditions.Delete(openStackMachine, infrav1.ProviderIDMismatchCondition)
+		return
+	}
+	nodeName := machine.Status.NodeRef.Name
+
+	remoteClient, err := remote.NewClusterClient(ctx, "openstackmachine-controller", r.Client, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		scope.Logger.Info("Skipping providerID check, failed to create client for workload cluster", "error", err.Error())
+		return
+	}
+	node := &corev1.Node{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			conditions.Delete(openStackMachine, infrav1.ProviderIDMismatchCondition)
+			return
+		}
+		scope.Logger.Info("Skipping providerID check, failed to get node", "node", nodeName, "error", err.Error())
+		return
+	}
+
+	setProviderIDMismatchCondition(openStackMachine, nodeProviderIDMismatch(node, *openStackMachine.Spec.InstanceID))
+}
+
+// setProviderIDMismatchCondition adds ProviderIDMismatchCondition with the
+// mismatch to the machine, or removes it if there is no mismatch. An event
+// is recorded when a mismatch is found.
+func setProviderIDMismatchCondition(openStackMachine *infrav1.OpenStackMachine, mismatch string) {
+	if mismatch == "" {
+		conditions.Delete(openStackMachine, infrav1.ProviderIDMismatchCondition)
+		return
+	}
+	if existing := conditions.Get(openStackMachine, infrav1.ProviderIDMismatchCondition); existing == nil || existing.Message != mismatch {
+		record.Warnf(openStackMachine, "ProviderIDMismatch", "The node of the machine belongs to another instance: %s", mismatch)
+	}
+	conditions.Set(openStackMachine, &clusterv1.Condition{
+		Type:    infrav1.ProviderIDMismatchCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.NodeProviderIDMismatchReason,
+		Message: mismatch,
+	})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_nodeProviderIDMismatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		providerID   string
+		wantMismatch bool
+	}{
+		{
+			name:         "providerID not set yet",
+			providerID:   "",
+			wantMismatch: false,
+		},
+		{
+			name:         "providerID without region",
+			providerID:   "openstack:///instance-id",
+			wantMismatch: false,
+		},
+		{
+			name:         "providerID with region",
+			providerID:   "openstack://RegionOne/instance-id",
+			wantMismatch: false,
+		},
+		{
+			name:         "providerID of a rebuilt instance",
+			providerID:   "openstack:///old-instance-id",
+			wantMismatch: true,
+		},
+		{
+			name:         "providerID of another cloud",
+			providerID:   "aws:///eu-west-1a/instance-id",
+			wantMismatch: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}, Spec: corev1.NodeSpec{ProviderID: tt.providerID}}
+			mismatch := nodeProviderIDMismatch(node, "instance-id")
+			g.Expect(mismatch != "").To(Equal(tt.wantMismatch))
+		})
+	}
+}
+
+func Test_setProviderIDMismatchCondition(t *testing.T) {
+	g := NewWithT(t)
+	openStackMachine := &infrav1.OpenStackMachine{}
+
+	setProviderIDMismatchCondition(openStackMachine, "providerID openstack:///old-instance-id of node node-0 does not refer to instance instance-id of the machine")
+	condition := conditions.Get(openStackMachine, infrav1.ProviderIDMismatchCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+	g.Expect(condition.Reason).To(Equal(infrav1.NodeProviderIDMismatchReason))
+
+	setProviderIDMismatchCondition(openStackMachine, "")
+	g.Expect(conditions.Has(openStackMachine, infrav1.ProviderIDMismatchCondition)).To(BeFalse())
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/quota"
+)
+
+// waitForQuotaDuration is the time after which the quotas are checked again
+// when they did not leave enough for the OpenStack resources to be created.
+const waitForQuotaDuration = 60 * time.Second
+
+// requiredResources are the OpenStack resources which are about to be
+// created by a reconcile.
+type requiredResources struct {
+	instances     compute.InstanceResources
+	network       networking.NetworkResources
+	loadBalancers int
+}
+
+// quotaExceededError is returned when OpenStack resources are not created
+// because the quotas of the project do not leave enough for them.
+type quotaExceededError struct {
+	exhausted []quota.Usage
+}
+
+func (e *quotaExceededError) Error() string {
+	return quota.Message(e.exhausted)
+}
+
+// getExhaustedQuotas returns the quotas of the project which do not leave
+// enough for the required resources. Quotas are only queried for the services
+// of which resources are required. The check is advisory: if a quota cannot
+// be queried, e.g. because the cloud does not report its usage, it is skipped
+// and the creation of the resources is left to fail on its own.
+func getExhaustedQuotas(scope *scope.Scope, required requiredResources) []quota.Usage {
+	var exhausted []quota.Usage
+
+	if required.instances != (compute.InstanceResources{}) {
+		computeService, err := compute.NewService(scope)
+		if err == nil {
+			var usages []quota.Usage
+			usages, err = computeService.GetExhaustedQuotas(required.instances)
+			exhausted = append(exhausted, usages...)
+		}
+		if err != nil {
+			scope.Logger.Info("Skipping compute quota check", "error", err.Error())
+		}
+	}
+
+	if required.network != (networking.NetworkResources{}) {
+		networkingService, err := networking.NewService(scope)
+		if err == nil {
+			var usages []quota.Usage
+			usages, err = networkingService.GetExhaustedQuotas(required.network)
+			exhausted = append(exhausted, usages...)
+		}
+		if err != nil {
+			scope.Logger.Info("Skipping network quota check", "error", err.Error())
+		}
+	}
+
+	if required.loadBalancers > 0 {
+		loadBalancerService, err := loadbalancer.NewService(scope)
+		if err == nil {
+			var usages []quota.Usage
+			usages, err = loadBalancerService.GetExhaustedQuotas(required.loadBalancers)
+			exhausted = append(exhausted, usages...)
+		}
+		if err != nil {
+			scope.Logger.Info("Skipping load balancer quota check", "error", err.Error())
+		}
+	}
+
+	return exhausted
+}
+
+// setQuotaExceededCondition adds QuotaExceededCondition listing the
+// exhausted resources to obj, or removes it if no resource is exhausted.
+func setQuotaExceededCondition(obj conditions.Setter, exhausted []quota.Usage) {
+	if len(exhausted) == 0 {
+		conditions.Delete(obj, infrav1.QuotaExceededCondition)
+		return
+	}
+	conditions.Set(obj, &clusterv1.Condition{
+		Type:    infrav1.QuotaExceededCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  infrav1.InsufficientQuotaReason,
+		Message: quota.Message(exhausted),
+	})
+}
+
+// getInstanceRequiredResources returns the resources required to create an
+// instance with instanceSpec.
+func getInstanceRequiredResources(computeService *compute.Service, instanceSpec *compute.InstanceSpec) (requiredResources, error) {
+	instances, err := computeService.GetInstanceResources(instanceSpec)
+	if err != nil {
+		return requiredResources{}, err
+	}
+
+	// A port is created for every subnet of a network, or for the network
+	// itself if no subnet is given, and for every port which does not exist
+	// yet. Without networks and ports the instance gets a port on the cluster
+	// network.
+	ports := 0
+	for _, port := range instanceSpec.Ports {
+		if port.ID == "" {
+			ports++
+		}
+	}
+	for _, network := range instanceSpec.Networks {
+		if len(network.Subnets) > 0 {
+			ports += len(network.Subnets)
+		} else {
+			ports++
+		}
+	}
+	if len(instanceSpec.Networks) == 0 && len(instanceSpec.Ports) == 0 {
+		ports = 1
+	}
+
+	return requiredResources{
+		instances: instances,
+		network:   networking.NetworkResources{Ports: ports},
+	}, nil
+}
+
+// getClusterRequiredResources returns the resources of the OpenStackCluster
+// which do not exist yet, together with the conditions of the components
+// they belong to.
+func getClusterRequiredResources(computeService *compute.Service, openStackCluster *infrav1.OpenStackCluster, clusterName string) (requiredResources, []clusterv1.ConditionType, error) {
+	var required requiredResources
+	var components []clusterv1.ConditionType
+	status := openStackCluster.Status
+
+	if openStackCluster.Spec.NodeCIDR != "" {
+		var network networking.NetworkResources
+		if status.Network == nil || status.Network.ID == "" {
+			network.Networks = 1
+		}
+		if status.Network == nil || status.Network.Subnet == nil {
+			network.Subnets = 1
+		}
+		if openStackCluster.Spec.ExternalNetworkID != "" && (status.Network == nil || status.Network.Router == nil) {
+			// The router interface on the cluster subnet is a port of the project
+			network.Routers = 1
+			network.Ports = 1
+		}
+		if network != (networking.NetworkResources{}) {
+			required.network.Add(network)
+			components = append(components, infrav1.NetworkReadyCondition)
+		}
+	}
+
+	if openStackCluster.Spec.ManagedSecurityGroups != nil && status.ControlPlaneSecurityGroup == nil {
+		required.network.SecurityGroups += 2
+		components = append(components, infrav1.SecurityGroupsReadyCondition)
+	}
+
+	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		if status.Network == nil || status.Network.APIServerLoadBalancer == nil {
+			required.loadBalancers = 1
+			required.network.Ports++
+			if !openStackCluster.Spec.DisableAPIServerFloatingIP {
+				required.network.FloatingIPs++
+			}
+			components = append(components, infrav1.LoadBalancerReadyCondition)
+		}
+	} else if !openStackCluster.Spec.ControlPlaneEndpoint.IsValid() && !openStackCluster.Spec.DisableAPIServerFloatingIP && openStackCluster.Spec.APIServerFloatingIP == "" {
+		required.network.FloatingIPs++
+		components = append(components, infrav1.NetworkReadyCondition)
+	}
+
+	if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled && status.Bastion == nil {
+		bastion, err := getInstanceRequiredResources(computeService, bastionToInstanceSpec(openStackCluster, clusterName))
+		if err != nil {
+			return requiredResources{}, nil, err
+		}
+		required.instances.Add(bastion.instances)
+		required.network.Add(bastion.network)
+		required.network.FloatingIPs++
+		if openStackCluster.Spec.ManagedSecurityGroups != nil && status.BastionSecurityGroup == nil {
+			required.network.SecurityGroups++
+		}
+		components = append(components, infrav1.BastionReadyCondition)
+	}
+
+	return required, components, nil
+}
+
+// reconcileClusterQuota checks whether the quotas of the project leave enough
+// for the resources of the OpenStackCluster which do not exist yet. If they
+// do not, the conditions of the components waiting for them are marked false
+// and a quotaExceededError is returned. Errors getting the required resources
+// only skip the check.
+func reconcileClusterQuota(scope *scope.Scope, computeService *compute.Service, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	required, components, err := getClusterRequiredResources(computeService, openStackCluster, cluster.Name)
+	if err != nil {
+		scope.Logger.Info("Skipping quota check", "error", err.Error())
+		return nil
+	}
+
+	var exhausted []quota.Usage
+	if required != (requiredResources{}) {
+		exhausted = getExhaustedQuotas(scope, required)
+	}
+	setQuotaExceededCondition(openStackCluster, exhausted)
+	if len(exhausted) == 0 {
+		return nil
+	}
+
+	quotaErr := &quotaExceededError{exhausted: exhausted}
+	for _, component := range components {
+		conditions.MarkFalse(openStackCluster, component, infrav1.InsufficientQuotaReason, clusterv1.ConditionSeverityWarning, "%s", quotaErr.Error())
+	}
+	return quotaErr
+}
+
+// reconcileMachineQuota checks whether the quotas of the project leave enough
+// for the instance of the OpenStackMachine. If they do not, InstanceReady is
+// marked false and a quotaExceededError is returned. Errors getting the
+// required resources only skip the check.
+func reconcileMachineQuota(scope *scope.Scope, computeService *compute.Service, openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec) error {
+	required, err := getInstanceRequiredResources(computeService, instanceSpec)
+	if err != nil {
+		scope.Logger.Info("Skipping quota check", "instance", instanceSpec.Name, "error", err.Error())
+		return nil
+	}
+
+	exhausted := getExhaustedQuotas(scope, required)
+	setQuotaExceededCondition(openStackMachine, exhausted)
+	if len(exhausted) == 0 {
+		return nil
+	}
+
+	quotaErr := &quotaExceededError{exhausted: exhausted}
+	conditions.MarkFalse(openStackMachine, infrav1.InstanceReadyCondition, infrav1.InsufficientQuotaReason, clusterv1.ConditionSeverityWarning, "%s", quotaErr.Error())
+	return quotaErr
+}
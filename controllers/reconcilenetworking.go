@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// requestNetworkingReconcile passes the ReconcileNetworkingAnnotation of the
+// machine on to its OpenStackServer, which reconciles the ports and security
+// groups of the instance, and removes it from the machine. The floating IP
+// is reconciled by the reconcile of the machine which the annotation
+// triggered, and again once the server reports its repaired ports. Failures
+// are reported as events and also remove the annotation, like for consoles.
+func (r *OpenStackMachineReconciler) requestNetworkingReconcile(ctx context.Context, scope *scope.Scope, openStackMachine *infrav1.OpenStackMachine) {
+	if _, ok := openStackMachine.Annotations[infrav1.ReconcileNetworkingAnnotation]; !ok {
+		return
+	}
+	defer delete(openStackMachine.Annotations, infrav1.ReconcileNetworkingAnnotation)
+
+	openStackServer := &infrav1.OpenStackServer{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: openStackMachine.Namespace, Name: openStackMachine.Name}, openStackServer); err != nil {
+		// A server which is created gets all of its networking anyway
+		if !apierrors.IsNotFound(err) {
+			record.Warnf(openStackMachine, "FailedReconcileNetworking", "Failed to request the reconcile of the networking: %v", err)
+		}
+		return
+	}
+	if _, ok := openStackServer.Annotations[infrav1.ReconcileNetworkingAnnotation]; ok {
+		return
+	}
+
+	patch := client.MergeFrom(openStackServer.DeepCopy())
+	if openStackServer.Annotations == nil {
+		openStackServer.Annotations = map[string]string{}
+	}
+	openStackServer.Annotations[infrav1.ReconcileNetworkingAnnotation] = ""
+	if err := r.Client.Patch(ctx, openStackServer, patch); err != nil {
+		record.Warnf(openStackMachine, "FailedReconcileNetworking", "Failed to request the reconcile of the networking: %v", err)
+		return
+	}
+	scope.Logger.Info("Requested reconcile of the networking", "openStackServer", openStackServer.Name)
+}
+
+// reconcileNetworking reconciles only the ports and security groups of the
+// instance of a server with the ReconcileNetworkingAnnotation: ports which
+// were deleted out of band are created and attached again, and the security
+// groups of the ports are set again. The annotation is removed once the
+// networking is reconciled, and kept on errors, so that they are retried.
+// It returns false if the instance is not ACTIVE, in which case the
+// annotation is dropped and the server is reconciled as usual.
+func (r *OpenStackServerReconciler) reconcileNetworking(scope *scope.Scope, computeService *compute.Service, openStackServer *infrav1.OpenStackServer) (bool, error) {
+	if _, ok := openStackServer.Annotations[infrav1.ReconcileNetworkingAnnotation]; !ok || openStackServer.Status.InstanceID == nil {
+		return false, nil
+	}
+
+	instanceID := *openStackServer.Status.InstanceID
+	instanceStatus, err := computeService.GetInstanceStatus(instanceID)
+	if err != nil {
+		return true, err
+	}
+	if instanceStatus == nil || instanceStatus.State() != infrav1.InstanceStateActive {
+		scope.Logger.Info("Not reconciling networking of instance which is not ACTIVE", "instanceID", instanceID)
+		delete(openStackServer.Annotations, infrav1.ReconcileNetworkingAnnotation)
+		return false, nil
+	}
+
+	scope.Logger.Info("Reconciling networking of instance", "instanceID", instanceID)
+	attached, err := computeService.ReconcileInstancePorts(openStackServer, serverToInstanceSpec(openStackServer, ""), instanceID, openStackServer.Labels[clusterv1.ClusterLabelName], openStackServer.Status.AttachedPortNameSuffixes)
+	openStackServer.Status.AttachedPortNameSuffixes = attached
+	if err != nil {
+		conditions.MarkFalse(openStackServer, infrav1.InstanceReadyCondition, infrav1.InstancePortsUpdateFailedReason, clusterv1.ConditionSeverityWarning, "Updating the ports of the instance failed: %v", err)
+		return true, err
+	}
+
+	// The instance is fetched again for the addresses of the attached ports
+	instanceStatus, err = computeService.GetInstanceStatus(instanceID)
+	if err != nil {
+		return true, err
+	}
+	if instanceStatus != nil {
+		instanceNS, err := instanceStatus.NetworkStatus()
+		if err != nil {
+			return true, errors.Wrapf(err, "unable to get network status for OpenStack instance %s with ID %s", instanceStatus.Name(), instanceID)
+		}
+		openStackServer.Status.Addresses = instanceNS.Addresses()
+
+		resourceIDs, err := computeService.GetInstanceResourceIDs(instanceStatus, openStackServer.Spec.Trunk)
+		if err != nil {
+			return true, errors.Wrapf(err, "unable to get resources of OpenStack instance %s with ID %s", instanceStatus.Name(), instanceID)
+		}
+		openStackServer.Status.ResourceIDs = resourceIDs
+	}
+
+	delete(openStackServer.Annotations, infrav1.ReconcileNetworkingAnnotation)
+	record.Eventf(openStackServer, "SuccessfulReconcileNetworking", "Reconciled networking of instance %s", instanceID)
+	return true, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_requestNetworkingReconcile(t *testing.T) {
+	s := &scope.Scope{Logger: logr.Discard()}
+
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		servers           []client.Object
+		wantServerRequest bool
+	}{
+		{
+			name:        "no reconcile requested",
+			annotations: map[string]string{},
+			servers: []client.Object{
+				&infrav1.OpenStackServer{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "machine-0"}},
+			},
+			wantServerRequest: false,
+		},
+		{
+			name:        "request is passed on to the server",
+			annotations: map[string]string{infrav1.ReconcileNetworkingAnnotation: ""},
+			servers: []client.Object{
+				&infrav1.OpenStackServer{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "machine-0"}},
+			},
+			wantServerRequest: true,
+		},
+		{
+			name:        "request without server is dropped",
+			annotations: map[string]string{infrav1.ReconcileNetworkingAnnotation: ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			testScheme := runtime.NewScheme()
+			g.Expect(infrav1.AddToScheme(testScheme)).To(Succeed())
+			r := &OpenStackMachineReconciler{Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(tt.servers...).Build()}
+
+			openStackMachine := &infrav1.OpenStackMachine{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "machine-0", Annotations: tt.annotations}}
+			r.requestNetworkingReconcile(context.TODO(), s, openStackMachine)
+			g.Expect(openStackMachine.Annotations).NotTo(HaveKey(infrav1.ReconcileNetworkingAnnotation))
+
+			if len(tt.servers) == 0 {
+				return
+			}
+			openStackServer := &infrav1.OpenStackServer{}
+			g.Expect(r.Client.Get(context.TODO(), client.ObjectKey{Namespace: "test-namespace", Name: "machine-0"}, openStackServer)).To(Succeed())
+			_, ok := openStackServer.Annotations[infrav1.ReconcileNetworkingAnnotation]
+			g.Expect(ok).To(Equal(tt.wantServerRequest))
+		})
+	}
+}
+
+func Test_reconcileNetworking_notRequested(t *testing.T) {
+	s := &scope.Scope{Logger: logr.Discard()}
+	r := &OpenStackServerReconciler{}
+
+	tests := []struct {
+		name            string
+		openStackServer *infrav1.OpenStackServer
+	}{
+		{
+			name:            "no reconcile requested",
+			openStackServer: &infrav1.OpenStackServer{Status: infrav1.OpenStackServerStatus{InstanceID: pointer.String("instance-id")}},
+		},
+		{
+			name: "instance is not created yet",
+			openStackServer: &infrav1.OpenStackServer{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{infrav1.ReconcileNetworkingAnnotation: ""},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			handled, err := r.reconcileNetworking(s, nil, tt.openStackServer)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(handled).To(BeFalse())
+		})
+	}
+}
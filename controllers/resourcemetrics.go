@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// reconcileResourceMetrics exposes the number of ports, trunks, floating IPs
+// and security group rules managed for the cluster and the usage of the
+// network quotas of its project as metrics, and requeues the cluster after
+// the poll interval to refresh them. Failing to get them is logged and does
+// not fail the reconcile: the previous metrics are kept until the next poll.
+func (r *OpenStackClusterReconciler) reconcileResourceMetrics(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) ctrl.Result {
+	if r.ResourceMetricsInterval <= 0 {
+		metrics.DeleteClusterResources(openStackCluster.Namespace, openStackCluster.Name)
+		return ctrl.Result{}
+	}
+	result := ctrl.Result{RequeueAfter: r.ResourceMetricsInterval}
+
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		scope.Logger.Info("Skipping resource metrics", "reason", err.Error())
+		return result
+	}
+
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+	portList, err := networkingService.ListPortsForCluster(clusterName)
+	if err != nil {
+		scope.Logger.Info("Skipping resource metrics", "reason", err.Error())
+		return result
+	}
+	trunkList, err := networkingService.ListTrunksForCluster(clusterName)
+	if err != nil {
+		scope.Logger.Info("Skipping resource metrics", "reason", err.Error())
+		return result
+	}
+	fipList, err := networkingService.ListFloatingIPsForCluster(clusterName)
+	if err != nil {
+		scope.Logger.Info("Skipping resource metrics", "reason", err.Error())
+		return result
+	}
+	metrics.SetClusterResources(openStackCluster.Namespace, openStackCluster.Name, map[string]int{
+		metrics.ResourcePort:              len(portList),
+		metrics.ResourceTrunk:             len(trunkList),
+		metrics.ResourceFloatingIP:        len(fipList),
+		metrics.ResourceSecurityGroupRule: securityGroupRuleCount(openStackCluster),
+	})
+
+	usages, err := networkingService.GetQuotaUsages()
+	if err != nil {
+		scope.Logger.Info("Skipping quota metrics", "reason", err.Error())
+		return result
+	}
+	metrics.SetClusterQuotaUsages(openStackCluster.Namespace, openStackCluster.Name, usages)
+	return result
+}
+
+// securityGroupRuleCount returns the number of rules of the managed security
+// groups of the cluster.
+func securityGroupRuleCount(openStackCluster *infrav1.OpenStackCluster) int {
+	count := 0
+	status := openStackCluster.Status
+	for _, group := range []*infrav1.SecurityGroup{status.ControlPlaneSecurityGroup, status.WorkerSecurityGroup, status.BastionSecurityGroup} {
+		if group != nil {
+			count += len(group.Rules)
+		}
+	}
+	return count
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// serviceNodePortsPollInterval is the interval at which the Services of the
+// workload cluster are checked for changed node ports.
+const serviceNodePortsPollInterval = time.Minute
+
+// reconcileServiceNodePorts updates the node ports of the Services of the
+// workload cluster in the status of clusters whose worker security group
+// only opens these node ports, so that the security groups reconciled
+// afterwards open exactly these. The node ports are kept if the Services
+// cannot be listed. Such clusters are requeued to check the Services again.
+func (r *OpenStackClusterReconciler) reconcileServiceNodePorts(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (ctrl.Result, error) {
+	managedSecurityGroups := openStackCluster.Spec.ManagedSecurityGroups
+	if managedSecurityGroups == nil || !managedSecurityGroups.NodePortsFromServices {
+		openStackCluster.Status.ServiceNodePorts = nil
+		conditions.Delete(openStackCluster, infrav1.ServiceNodePortsReadyCondition)
+		return ctrl.Result{}, nil
+	}
+	// There are no Services before the control plane is initialized
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		return ctrl.Result{}, nil
+	}
+
+	remoteClient, err := remote.NewClusterClient(ctx, "openstackcluster-controller", r.Client, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ServiceNodePortsReadyCondition, infrav1.ServiceNodePortsListFailedReason, clusterv1.ConditionSeverityWarning, "Connecting to workload cluster failed: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to create client for workload cluster")
+	}
+	services := &corev1.ServiceList{}
+	if err := remoteClient.List(ctx, services); err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ServiceNodePortsReadyCondition, infrav1.ServiceNodePortsListFailedReason, clusterv1.ConditionSeverityWarning, "Listing Services failed: %v", err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to list Services of workload cluster")
+	}
+
+	nodePorts := serviceNodePorts(services.Items)
+	if !equalServiceNodePorts(openStackCluster.Status.ServiceNodePorts, nodePorts) {
+		scope.Logger.Info("Node ports of Services changed", "nodePorts", nodePorts)
+	}
+	openStackCluster.Status.ServiceNodePorts = nodePorts
+	conditions.MarkTrue(openStackCluster, infrav1.ServiceNodePortsReadyCondition)
+	return ctrl.Result{RequeueAfter: serviceNodePortsPollInterval}, nil
+}
+
+// serviceNodePorts returns the node ports allocated to the Services, sorted
+// by protocol and port. This includes the health check node ports of
+// LoadBalancer Services with the Local external traffic policy, which are
+// probed by the load balancers.
+func serviceNodePorts(services []corev1.Service) []infrav1.ServiceNodePort {
+	seen := map[infrav1.ServiceNodePort]bool{}
+	var nodePorts []infrav1.ServiceNodePort
+	add := func(port int32, protocol corev1.Protocol) {
+		if port == 0 {
+			return
+		}
+		nodePort := infrav1.ServiceNodePort{Port: int(port), Protocol: strings.ToLower(string(protocol))}
+		if nodePort.Protocol == "" {
+			nodePort.Protocol = "tcp"
+		}
+		if seen[nodePort] {
+			return
+		}
+		seen[nodePort] = true
+		nodePorts = append(nodePorts, nodePort)
+	}
+
+	for i := range services {
+		service := &services[i]
+		if service.Spec.Type != corev1.ServiceTypeNodePort && service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		for _, port := range service.Spec.Ports {
+			add(port.NodePort, port.Protocol)
+		}
+		add(service.Spec.HealthCheckNodePort, corev1.ProtocolTCP)
+	}
+
+	sort.Slice(nodePorts, func(i, j int) bool {
+		if nodePorts[i].Protocol != nodePorts[j].Protocol {
+			return nodePorts[i].Protocol < nodePorts[j].Protocol
+		}
+		return nodePorts[i].Port < nodePorts[j].Port
+	})
+	return nodePorts
+}
+
+func equalServiceNodePorts(a, b []infrav1.ServiceNodePort) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_serviceNodePorts(t *testing.T) {
+	g := NewWithT(t)
+
+	services := []corev1.Service{
+		{
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeClusterIP,
+				Ports: []corev1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		{
+			Spec: corev1.ServiceSpec{
+				Type: corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{
+					{Port: 53, NodePort: 31053, Protocol: corev1.ProtocolUDP},
+					{Port: 53, NodePort: 31053, Protocol: corev1.ProtocolTCP},
+				},
+			},
+		},
+		{
+			Spec: corev1.ServiceSpec{
+				Type:                corev1.ServiceTypeLoadBalancer,
+				Ports:               []corev1.ServicePort{{Port: 443, NodePort: 30443, Protocol: corev1.ProtocolTCP}},
+				HealthCheckNodePort: 32000,
+			},
+		},
+		{
+			// Without allocated node ports
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 8080, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+	}
+
+	g.Expect(serviceNodePorts(services)).To(Equal([]infrav1.ServiceNodePort{
+		{Port: 30443, Protocol: "tcp"},
+		{Port: 31053, Protocol: "tcp"},
+		{Port: 32000, Protocol: "tcp"},
+		{Port: 31053, Protocol: "udp"},
+	}))
+	g.Expect(serviceNodePorts(nil)).To(BeEmpty())
+}
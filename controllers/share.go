@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/share"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+const (
+	waitForShareDuration = 15 * time.Second
+
+	defaultShareSecretName = "manila-share"
+	shareSecretNamespace   = metav1.NamespaceSystem
+)
+
+// reconcileShare ensures that the Manila share of the cluster exists and is
+// accessible from the cluster, or deletes it if the cluster no longer has a
+// share. It returns false while the share is not available yet.
+func reconcileShare(scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) (bool, error) {
+	clusterName := fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name)
+
+	if openStackCluster.Spec.Share == nil {
+		if openStackCluster.Status.Share != nil {
+			if err := deleteShare(scope, openStackCluster, clusterName); err != nil {
+				return false, err
+			}
+		}
+		conditions.Delete(openStackCluster, infrav1.ShareReadyCondition)
+		return true, nil
+	}
+
+	scope.Logger.Info("Reconciling Share")
+
+	shareService, err := share.NewService(scope)
+	if err != nil {
+		return false, err
+	}
+
+	ready, err := shareService.ReconcileShare(openStackCluster, clusterName)
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ShareReadyCondition, infrav1.ShareCreateFailedReason, clusterv1.ConditionSeverityWarning, "Reconciling share failed: %v", err)
+		return false, errors.Wrap(err, "failed to reconcile share")
+	}
+	if !ready {
+		conditions.MarkFalse(openStackCluster, infrav1.ShareReadyCondition, infrav1.ShareNotReadyReason, clusterv1.ConditionSeverityInfo, "Waiting for share to become available")
+		return false, nil
+	}
+
+	conditions.MarkTrue(openStackCluster, infrav1.ShareReadyCondition)
+	return true, nil
+}
+
+func deleteShare(scope *scope.Scope, openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	shareService, err := share.NewService(scope)
+	if err != nil {
+		return err
+	}
+	if err := shareService.DeleteShare(openStackCluster, clusterName); err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ShareReadyCondition, infrav1.ShareDeleteFailedReason, clusterv1.ConditionSeverityWarning, "Deleting share failed: %v", err)
+		return errors.Wrap(err, "failed to delete share")
+	}
+	return nil
+}
+
+// reconcileShareSecret publishes the export location and credentials of the
+// share of the cluster in a Secret of the workload cluster, for the Manila
+// CSI driver. The Secret is published once the share is available and the
+// control plane of the workload cluster is initialized.
+func (r *OpenStackClusterReconciler) reconcileShareSecret(ctx context.Context, scope *scope.Scope, cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster) error {
+	shareStatus := openStackCluster.Status.Share
+	if openStackCluster.Spec.Share == nil || shareStatus == nil || !conditions.IsTrue(openStackCluster, infrav1.ShareReadyCondition) {
+		return nil
+	}
+	if !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		scope.Logger.Info("Waiting for the control plane to publish the share secret")
+		return nil
+	}
+
+	var accessKey string
+	if shareStatus.Protocol == share.ProtocolCephFS {
+		shareService, err := share.NewService(scope)
+		if err != nil {
+			return err
+		}
+		accessKey, err = shareService.GetAccessKey(shareStatus.ID, shareStatus.AccessRuleID)
+		if err != nil {
+			conditions.MarkFalse(openStackCluster, infrav1.ShareReadyCondition, infrav1.ShareSecretFailedReason, clusterv1.ConditionSeverityWarning, "Getting access key of share failed: %v", err)
+			return err
+		}
+	}
+
+	remoteClient, err := remote.NewClusterClient(ctx, "openstackcluster-controller", r.Client, client.ObjectKeyFromObject(cluster))
+	if err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ShareReadyCondition, infrav1.ShareSecretFailedReason, clusterv1.ConditionSeverityWarning, "Connecting to workload cluster failed: %v", err)
+		return errors.Wrap(err, "failed to create client for workload cluster")
+	}
+
+	want := shareToSecret(cluster, openStackCluster, accessKey)
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: want.Name, Namespace: want.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, remoteClient, secret, func() error {
+		secret.Labels = want.Labels
+		secret.Type = want.Type
+		secret.Data = want.Data
+		return nil
+	}); err != nil {
+		conditions.MarkFalse(openStackCluster, infrav1.ShareReadyCondition, infrav1.ShareSecretFailedReason, clusterv1.ConditionSeverityWarning, "Publishing share secret failed: %v", err)
+		return errors.Wrapf(err, "failed to publish share secret %s/%s", want.Namespace, want.Name)
+	}
+	return nil
+}
+
+// shareToSecret returns the Secret of the workload cluster describing the
+// share of the cluster. The share is referenced by the shareID and
+// shareAccessID volume attributes of the Manila CSI driver.
+func shareToSecret(cluster *clusterv1.Cluster, openStackCluster *infrav1.OpenStackCluster, accessKey string) *corev1.Secret {
+	name := openStackCluster.Spec.Share.SecretName
+	if name == "" {
+		name = defaultShareSecretName
+	}
+	shareStatus := openStackCluster.Status.Share
+
+	data := map[string][]byte{
+		"shareID":        []byte(shareStatus.ID),
+		"shareAccessID":  []byte(shareStatus.AccessRuleID),
+		"protocol":       []byte(shareStatus.Protocol),
+		"exportLocation": []byte(shareStatus.ExportLocation),
+	}
+	if shareStatus.Protocol == share.ProtocolCephFS {
+		data["accessTo"] = []byte(fmt.Sprintf("%s-%s", cluster.Namespace, cluster.Name))
+		data["accessKey"] = []byte(accessKey)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: shareSecretNamespace,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: cluster.Name,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
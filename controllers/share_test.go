@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_shareToSecret(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		spec      infrav1.ManagedShare
+		status    infrav1.Share
+		accessKey string
+		want      *corev1.Secret
+	}{
+		{
+			name: "NFS share",
+			spec: infrav1.ManagedShare{Protocol: "NFS", Size: 10},
+			status: infrav1.Share{
+				ID:             "share-id",
+				Protocol:       "NFS",
+				AccessRuleID:   "access-id",
+				ExportLocation: "10.0.0.1:/shares/share-id",
+			},
+			want: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "manila-share",
+					Namespace: "kube-system",
+					Labels:    map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: map[string][]byte{
+					"shareID":        []byte("share-id"),
+					"shareAccessID":  []byte("access-id"),
+					"protocol":       []byte("NFS"),
+					"exportLocation": []byte("10.0.0.1:/shares/share-id"),
+				},
+			},
+		},
+		{
+			name: "CephFS share with secret name",
+			spec: infrav1.ManagedShare{Protocol: "CEPHFS", Size: 10, SecretName: "cephfs"},
+			status: infrav1.Share{
+				ID:             "share-id",
+				Protocol:       "CEPHFS",
+				AccessRuleID:   "access-id",
+				ExportLocation: "10.0.0.1:6789:/volumes/a",
+			},
+			accessKey: "secret-key",
+			want: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cephfs",
+					Namespace: "kube-system",
+					Labels:    map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: map[string][]byte{
+					"shareID":        []byte("share-id"),
+					"shareAccessID":  []byte("access-id"),
+					"protocol":       []byte("CEPHFS"),
+					"exportLocation": []byte("10.0.0.1:6789:/volumes/a"),
+					"accessTo":       []byte("test-namespace-test-cluster"),
+					"accessKey":      []byte("secret-key"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec:   infrav1.OpenStackClusterSpec{Share: &tt.spec},
+				Status: infrav1.OpenStackClusterStatus{Share: &tt.status},
+			}
+			g.Expect(shareToSecret(cluster, openStackCluster, tt.accessKey)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// shutdownPatchTimeout is the time given to the final patch of a reconcile
+// whose context was cancelled, e.g. because the controller received SIGTERM.
+const shutdownPatchTimeout = 10 * time.Second
+
+// patchContext returns the context used to patch an object at the end of a
+// reconcile. When the controller shuts down the context of in-flight
+// reconciles is cancelled, which would discard the resources they created
+// and recorded in the status so far. The next leader would then create them
+// again, e.g. allocate a second floating IP. The status is therefore still
+// patched for a short time after the reconcile context was cancelled.
+func patchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Err() == nil {
+		return ctx, func() {}
+	}
+	log := ctrl.LoggerFrom(ctx)
+	log.Info("Reconcile was interrupted, saving its progress")
+	return context.WithTimeout(ctrl.LoggerInto(context.Background(), log), shutdownPatchTimeout)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func Test_patchContext(t *testing.T) {
+	g := NewWithT(t)
+
+	// The reconcile context is used while it is not cancelled
+	ctx := context.TODO()
+	patchCtx, cancel := patchContext(ctx)
+	g.Expect(patchCtx).To(BeIdenticalTo(ctx))
+	cancel()
+
+	// A cancelled reconcile context is replaced, keeping its logger
+	log := logr.Discard().WithName("test")
+	ctx, cancelReconcile := context.WithCancel(ctrl.LoggerInto(context.TODO(), log))
+	cancelReconcile()
+	patchCtx, cancel = patchContext(ctx)
+	defer cancel()
+	g.Expect(patchCtx.Err()).NotTo(HaveOccurred())
+	_, hasDeadline := patchCtx.Deadline()
+	g.Expect(hasDeadline).To(BeTrue())
+	g.Expect(ctrl.LoggerFrom(patchCtx)).To(Equal(log))
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// openStackMachineTemplateGroupKind is the value of the cloned-from-groupkind
+// annotation of objects created from an OpenStackMachineTemplate.
+var openStackMachineTemplateGroupKind = schema.GroupKind{Group: infrav1.GroupVersion.Group, Kind: "OpenStackMachineTemplate"}.String()
+
+// useTemplateLookups makes computeService reuse the images and flavors
+// resolved for the OpenStackMachineTemplate obj was cloned from, so that a
+// MachineDeployment scaling up or rolling out resolves them once per
+// generation of its template instead of once per machine. Objects which were
+// not cloned from an OpenStackMachineTemplate resolve them themselves.
+func useTemplateLookups(ctx context.Context, ctrlClient client.Client, scope *scope.Scope, obj metav1.Object, computeService *compute.Service) {
+	annotations := obj.GetAnnotations()
+	name := annotations[clusterv1.TemplateClonedFromNameAnnotation]
+	if name == "" || annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] != openStackMachineTemplateGroupKind {
+		return
+	}
+
+	template := &infrav1.OpenStackMachineTemplate{}
+	if err := ctrlClient.Get(ctx, client.ObjectKey{Namespace: obj.GetNamespace(), Name: name}, template); err != nil {
+		scope.Logger.V(4).Info("Not reusing the lookups of the OpenStackMachineTemplate", "openStackMachineTemplate", name, "reason", err.Error())
+		return
+	}
+	computeService.UseGenerationCache(template.UID, template.Generation)
+}
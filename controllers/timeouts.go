@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// reconcileTimeouts returns the timeouts of the services configured for the
+// cluster. Unset timeouts are left to the defaults of the services.
+func reconcileTimeouts(openStackCluster *infrav1.OpenStackCluster) scope.Timeouts {
+	timeouts := scope.Timeouts{}
+	spec := openStackCluster.Spec.ReconcileTimeouts
+	if spec == nil {
+		return timeouts
+	}
+	if spec.InstanceCreate != nil {
+		timeouts.InstanceCreate = spec.InstanceCreate.Duration
+	}
+	if spec.LoadBalancerCreate != nil {
+		timeouts.LoadBalancerCreate = spec.LoadBalancerCreate.Duration
+	}
+	if spec.FloatingIPAssociate != nil {
+		timeouts.FloatingIPAssociate = spec.FloatingIPAssociate.Duration
+	}
+	if spec.Deletion != nil {
+		timeouts.Deletion = spec.Deletion.Duration
+	}
+	return timeouts
+}
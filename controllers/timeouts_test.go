@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_reconcileTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts *infrav1.ReconcileTimeouts
+		want     scope.Timeouts
+	}{
+		{
+			name: "Not set",
+			want: scope.Timeouts{},
+		},
+		{
+			name: "Some set",
+			timeouts: &infrav1.ReconcileTimeouts{
+				InstanceCreate: &metav1.Duration{Duration: 10 * time.Minute},
+				Deletion:       &metav1.Duration{Duration: time.Minute},
+			},
+			want: scope.Timeouts{
+				InstanceCreate: 10 * time.Minute,
+				Deletion:       time.Minute,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{ReconcileTimeouts: tt.timeouts},
+			}
+			g.Expect(reconcileTimeouts(openStackCluster)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+)
+
+const (
+	cloudConfigHeader = "#cloud-config"
+
+	// ignitionDefaultUser is the default user of the distributions using
+	// Ignition, e.g. Flatcar and Fedora CoreOS.
+	ignitionDefaultUser = "core"
+)
+
+// bootstrapDataEdit is a change to the bootstrap data of a machine, which
+// is either a cloud-config or an Ignition config.
+type bootstrapDataEdit struct {
+	cloudConfig func(cloudConfig map[string]interface{})
+	ignition    func(config map[string]interface{})
+}
+
+// machineBootstrapDataEdits returns the changes to the bootstrap data of the
+// machine.
+func machineBootstrapDataEdits(openStackMachine *infrav1.OpenStackMachine, instanceSpec *compute.InstanceSpec) []bootstrapDataEdit {
+	var edits []bootstrapDataEdit
+	if labels := machineNodeLabels(openStackMachine, instanceSpec); len(labels) > 0 {
+		edits = append(edits, nodeLabelsEdit(labels))
+	}
+	if keys := openStackMachine.Spec.SSHAuthorizedKeys; len(keys) > 0 {
+		edits = append(edits, sshAuthorizedKeysEdit(keys))
+	}
+	return edits
+}
+
+// editBootstrapData applies the edits to the bootstrap data.
+func editBootstrapData(format string, bootstrapData []byte, edits []bootstrapDataEdit) ([]byte, error) {
+	if format == "ignition" {
+		config := map[string]interface{}{}
+		if err := json.Unmarshal(bootstrapData, &config); err != nil {
+			return nil, errors.Wrap(err, "failed to parse ignition config")
+		}
+		for _, edit := range edits {
+			edit.ignition(config)
+		}
+		return json.Marshal(config)
+	}
+
+	// Keep the leading comments, e.g. the jinja template header of kubeadm
+	// before the cloud-config header
+	var header [][]byte
+	body := bootstrapData
+	for bytes.HasPrefix(body, []byte("#")) {
+		line := body
+		body = nil
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line, body = line[:i], line[i+1:]
+		}
+		header = append(header, line)
+	}
+	isCloudConfig := false
+	for _, line := range header {
+		if strings.TrimSpace(string(line)) == cloudConfigHeader {
+			isCloudConfig = true
+		}
+	}
+	if !isCloudConfig {
+		return nil, errors.New("only cloud-config or ignition bootstrap data can be changed")
+	}
+
+	cloudConfig := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &cloudConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to parse cloud-config")
+	}
+	for _, edit := range edits {
+		edit.cloudConfig(cloudConfig)
+	}
+	body, err := yaml.Marshal(cloudConfig)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(bytes.Join(header, []byte("\n")), '\n'), body...), nil
+}
+
+// sshAuthorizedKeysEdit adds SSH public keys to the default user of the
+// instance, as an alternative to a Nova keypair.
+func sshAuthorizedKeysEdit(keys []string) bootstrapDataEdit {
+	return bootstrapDataEdit{
+		cloudConfig: func(cloudConfig map[string]interface{}) {
+			existing, _ := cloudConfig["ssh_authorized_keys"].([]interface{})
+			cloudConfig["ssh_authorized_keys"] = appendStrings(existing, keys)
+		},
+		ignition: func(config map[string]interface{}) {
+			passwd, _ := config["passwd"].(map[string]interface{})
+			if passwd == nil {
+				passwd = map[string]interface{}{}
+			}
+			users, _ := passwd["users"].([]interface{})
+			var user map[string]interface{}
+			for _, u := range users {
+				if u, ok := u.(map[string]interface{}); ok && u["name"] == ignitionDefaultUser {
+					user = u
+				}
+			}
+			if user == nil {
+				user = map[string]interface{}{"name": ignitionDefaultUser}
+				users = append(users, user)
+			}
+			existing, _ := user["sshAuthorizedKeys"].([]interface{})
+			user["sshAuthorizedKeys"] = appendStrings(existing, keys)
+			passwd["users"] = users
+			config["passwd"] = passwd
+		},
+	}
+}
+
+// sshAuthorizedKeysUserData returns base64 encoded cloud-config user data
+// which only adds the SSH authorized keys, for instances without bootstrap
+// data like the bastion.
+func sshAuthorizedKeysUserData(keys []string) string {
+	var userData strings.Builder
+	userData.WriteString(cloudConfigHeader + "\nssh_authorized_keys:\n")
+	for _, key := range keys {
+		// Double-quoted YAML scalars accept the escapes of Go string literals
+		userData.WriteString("- " + strconv.Quote(key) + "\n")
+	}
+	return base64.StdEncoding.EncodeToString([]byte(userData.String()))
+}
+
+func appendStrings(list []interface{}, values []string) []interface{} {
+	for _, value := range values {
+		list = append(list, value)
+	}
+	return list
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_sshAuthorizedKeysEdit(t *testing.T) {
+	keys := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGk user@example.com"}
+
+	tests := []struct {
+		name          string
+		format        string
+		bootstrapData string
+		want          string
+	}{
+		{
+			name:   "Cloud-config with existing keys",
+			format: "cloud-config",
+			bootstrapData: `#cloud-config
+ssh_authorized_keys:
+- ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ admin
+runcmd:
+- kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml
+`,
+			want: `#cloud-config
+runcmd:
+- kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml
+ssh_authorized_keys:
+- ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQ admin
+- ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGk user@example.com
+`,
+		},
+		{
+			name:          "Ignition with core user",
+			format:        "ignition",
+			bootstrapData: `{"ignition":{"version":"2.3.0"},"passwd":{"users":[{"name":"core","groups":["sudo"]}]}}`,
+			want:          `{"ignition":{"version":"2.3.0"},"passwd":{"users":[{"groups":["sudo"],"name":"core","sshAuthorizedKeys":["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGk user@example.com"]}]}}`,
+		},
+		{
+			name:          "Ignition without users",
+			format:        "ignition",
+			bootstrapData: `{"ignition":{"version":"2.3.0"}}`,
+			want:          `{"ignition":{"version":"2.3.0"},"passwd":{"users":[{"name":"core","sshAuthorizedKeys":["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGk user@example.com"]}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := editBootstrapData(tt.format, []byte(tt.bootstrapData), []bootstrapDataEdit{sshAuthorizedKeysEdit(keys)})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_sshAuthorizedKeysUserData(t *testing.T) {
+	g := NewWithT(t)
+
+	keys := []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGk user@example.com", `ssh-rsa AAAAB3NzaC1yc2E "quoted comment"`}
+	userData, err := base64.StdEncoding.DecodeString(sshAuthorizedKeysUserData(keys))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(userData)).To(HavePrefix("#cloud-config\n"))
+
+	cloudConfig := struct {
+		SSHAuthorizedKeys []string `json:"ssh_authorized_keys"`
+	}{}
+	g.Expect(yaml.Unmarshal(userData, &cloudConfig)).To(Succeed())
+	g.Expect(cloudConfig.SSHAuthorizedKeys).To(Equal(keys))
+}
@@ -17,11 +17,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -33,18 +35,29 @@ import (
 	_ "k8s.io/component-base/logs/json/register"
 	"k8s.io/klog/v2"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	infrav1alpha3 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha3"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha4"
 	infrav1alpha5 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha5"
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1alpha6 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/controllers"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/approval"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/notifications"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/cache"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/webhooks"
 	"sigs.k8s.io/cluster-api-provider-openstack/version"
 )
 
@@ -53,34 +66,79 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 
 	// flags.
-	metricsBindAddr             string
-	enableLeaderElection        bool
-	leaderElectionLeaseDuration time.Duration
-	leaderElectionRenewDeadline time.Duration
-	leaderElectionRetryPeriod   time.Duration
-	watchNamespace              string
-	watchFilterValue            string
-	profilerAddress             string
-	openStackClusterConcurrency int
-	openStackMachineConcurrency int
-	syncPeriod                  time.Duration
-	webhookPort                 int
-	webhookCertDir              string
-	healthAddr                  string
-	lbProvider                  string
-	logOptions                  = logs.NewOptions()
+	metricsBindAddr                     string
+	enableLeaderElection                bool
+	leaderElectionLeaseDuration         time.Duration
+	leaderElectionRenewDeadline         time.Duration
+	leaderElectionRetryPeriod           time.Duration
+	leaderElectionNamespace             string
+	leaderElectionReleaseOnCancel       bool
+	gracefulShutdownTimeout             time.Duration
+	watchNamespace                      string
+	watchFilterValue                    string
+	profilerAddress                     string
+	openStackClusterConcurrency         int
+	openStackMachineConcurrency         int
+	openStackMachineTemplateConcurrency int
+	openStackServerConcurrency          int
+	openStackServerBulkCreateMinServers int
+	openStackFloatingIPPoolConcurrency  int
+	openStackRemediationConcurrency     int
+	openStackAPIQPS                     float32
+	openStackAPIBurst                   int
+	openStackCreateConcurrency          int
+	openStackCreateQPS                  float32
+	openStackCreateBurst                int
+	openStackAPICacheTTL                time.Duration
+	templateLookupTTL                   time.Duration
+	openStackAPIMaxRetries              int
+	openStackAPIRetryBaseDelay          time.Duration
+	openStackAPIRetryMaxDelay           time.Duration
+	openStackResourceParallelism        int
+	openStackAPIMaxIdleConnsPerHost     int
+	openStackAPIIdleConnTimeout         time.Duration
+	openStackAPIEnableHTTP2             bool
+	approvalWebhookURL                  string
+	approvalWebhookCAFile               string
+	approvalWebhookTimeout              time.Duration
+	identitySecretNamespaces            []string
+	otlpEndpoint                        string
+	otlpInsecure                        bool
+	otlpSamplingRatio                   float64
+	syncPeriod                          time.Duration
+	clusterResyncPeriod                 time.Duration
+	instanceActionPollInterval          time.Duration
+	loadBalancerStatsInterval           time.Duration
+	resourceMetricsInterval             time.Duration
+	notificationListenerAddr            string
+	notificationListenerTokenFile       string
+	webhookPort                         int
+	webhookCertDir                      string
+	healthAddr                          string
+	cloudHealthCheckIdentities          []string
+	cloudHealthCheckCloudName           string
+	cloudHealthCheckInterval            time.Duration
+	lbProvider                          string
+	credentialValidationMode            string
+	networkValidationMode               string
+	machineResourceValidationMode       string
+	logOptions                          = logs.NewOptions()
 )
 
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = clusterv1.AddToScheme(scheme)
+	_ = ipamv1.AddToScheme(scheme)
 	_ = infrav1.AddToScheme(scheme)
 	_ = infrav1alpha3.AddToScheme(scheme)
 	_ = infrav1alpha4.AddToScheme(scheme)
 	_ = infrav1alpha5.AddToScheme(scheme)
+	_ = infrav1alpha6.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 
 	metrics.RegisterAPIPrometheusMetrics()
+	metrics.RegisterLoadBalancerPrometheusMetrics()
+	metrics.RegisterClusterResourcePrometheusMetrics()
 }
 
 // InitFlags initializes the flags.
@@ -103,6 +161,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&leaderElectionRetryPeriod, "leader-elect-retry-period", 2*time.Second,
 		"Duration the LeaderElector clients should wait between tries of actions (duration string)")
 
+	fs.StringVar(&leaderElectionNamespace, "leader-elect-resource-namespace", "",
+		"Namespace of the lease used for leader election. Defaults to the namespace the controller manager runs in.")
+
+	fs.BoolVar(&leaderElectionReleaseOnCancel, "leader-elect-release-on-cancel", false,
+		"Release the leader election lease when the controller manager stops, so that a standby replica takes over without waiting for the lease to expire.")
+
+	fs.DurationVar(&gracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second,
+		"Time the controller manager waits for in-flight reconciles to finish when it stops (duration string)")
+
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
@@ -118,9 +185,105 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&openStackMachineConcurrency, "openstackmachine-concurrency", 10,
 		"Number of OpenStackMachines to process simultaneously")
 
+	fs.IntVar(&openStackMachineTemplateConcurrency, "openstackmachinetemplate-concurrency", 5,
+		"Number of OpenStackMachineTemplates to process simultaneously")
+
+	fs.IntVar(&openStackServerConcurrency, "openstackserver-concurrency", 10,
+		"Number of OpenStackServers to process simultaneously")
+
+	fs.IntVar(&openStackServerBulkCreateMinServers, "openstackserver-bulk-create-min-servers", 0,
+		"Minimum number of OpenStackServers waiting for identical instances, e.g. of a scaled up MachineDeployment, which are created with a single Nova multi-create request. Set to 0 to create every instance on its own.")
+
+	fs.IntVar(&openStackFloatingIPPoolConcurrency, "openstackfloatingippool-concurrency", 5,
+		"Number of OpenStackFloatingIPPools to process simultaneously")
+
+	fs.IntVar(&openStackRemediationConcurrency, "openstackremediation-concurrency", 5,
+		"Number of OpenStackRemediations to process simultaneously")
+
+	fs.Float32Var(&openStackAPIQPS, "openstack-api-qps", 0,
+		"Maximum number of requests per second sent to the OpenStack APIs, shared by all controllers. Set to 0 for no limit.")
+
+	fs.IntVar(&openStackAPIBurst, "openstack-api-burst", 10,
+		"Maximum burst of requests sent to the OpenStack APIs when --openstack-api-qps is set.")
+
+	fs.IntVar(&openStackCreateConcurrency, "openstack-create-concurrency", 0,
+		"Maximum number of concurrent requests creating servers, volumes or ports, per cloud and kind of resource. Set to 0 for no limit.")
+
+	fs.Float32Var(&openStackCreateQPS, "openstack-create-qps", 0,
+		"Maximum number of requests per second creating servers, volumes or ports, per cloud and kind of resource. Set to 0 for no limit.")
+
+	fs.IntVar(&openStackCreateBurst, "openstack-create-burst", 5,
+		"Maximum burst of requests creating servers, volumes or ports when --openstack-create-qps is set.")
+
+	fs.DurationVar(&openStackAPICacheTTL, "openstack-api-cache-ttl", 30*time.Second,
+		"How long flavors, images, availability zones, networks and security groups read from the OpenStack APIs are cached (e.g. 1m). Set to 0 to disable the cache.")
+
+	fs.DurationVar(&templateLookupTTL, "template-lookup-ttl", 10*time.Minute,
+		"How long the images and flavors resolved for a generation of an OpenStackMachineTemplate are reused after machines last used them. Set to 0 to resolve them for every machine.")
+
+	fs.IntVar(&openStackAPIMaxRetries, "openstack-api-max-retries", 5,
+		"Maximum number of times a request to the OpenStack APIs is retried when it is rate limited or an idempotent request fails with a server error. Set to 0 to disable retries.")
+
+	fs.DurationVar(&openStackAPIRetryBaseDelay, "openstack-api-retry-base-delay", time.Second,
+		"The delay before the first retry of a failed request to the OpenStack APIs. It doubles with every retry unless OpenStack returns a Retry-After header.")
+
+	fs.DurationVar(&openStackAPIRetryMaxDelay, "openstack-api-retry-max-delay", 30*time.Second,
+		"The maximum delay before a retry of a failed request to the OpenStack APIs, including delays requested with a Retry-After header.")
+
+	fs.IntVar(&openStackResourceParallelism, "openstack-resource-parallelism", 4,
+		"Maximum number of independent networking resources, e.g. security group rules, created or deleted concurrently by a reconcile. Set to 1 to create them one after the other.")
+
+	fs.IntVar(&openStackAPIMaxIdleConnsPerHost, "openstack-api-max-idle-conns-per-host", 10,
+		"Maximum number of idle connections kept open to each OpenStack API endpoint. Connections are shared by all controllers using the same CA certificate.")
+
+	fs.DurationVar(&openStackAPIIdleConnTimeout, "openstack-api-idle-conn-timeout", 90*time.Second,
+		"How long an idle connection to an OpenStack API endpoint is kept open (e.g. 5m). Set to 0 to keep idle connections open indefinitely.")
+
+	fs.BoolVar(&openStackAPIEnableHTTP2, "openstack-api-enable-http2", false,
+		"Use HTTP/2 for requests to OpenStack API endpoints which support it.")
+
+	fs.StringVar(&approvalWebhookURL, "approval-webhook-url", "",
+		"The URL of a webhook which is asked to approve each security group rule and floating IP before it is created, e.g. to route them through a firewall approval process. Denied resources are not created and are reported with the ApprovalDenied reason in the conditions. Nothing is asked if unset.")
+
+	fs.StringVar(&approvalWebhookCAFile, "approval-webhook-ca-file", "",
+		"A file with the CA certificates used to verify the certificate of the approval webhook. The system certificates are used if unset.")
+
+	fs.DurationVar(&approvalWebhookTimeout, "approval-webhook-timeout", 10*time.Second,
+		"The timeout of the requests to the approval webhook (e.g. 30s).")
+
+	fs.StringSliceVar(&identitySecretNamespaces, "identity-secret-namespaces", nil,
+		"Namespaces whose identity secrets may be referenced by the identityRefs of resources in other namespaces. Each secret must also list the namespaces allowed to use it in its infrastructure.cluster.x-k8s.io/allowed-namespaces annotation. Identity secrets can only be referenced from their own namespace if unset.")
+
+	fs.StringVar(&otlpEndpoint, "otlp-endpoint", "",
+		"The OTLP gRPC endpoint (host:port) to export traces of reconciles and OpenStack API requests to. Tracing is disabled if unset.")
+
+	fs.BoolVar(&otlpInsecure, "otlp-insecure", false,
+		"Export traces to the OTLP endpoint without TLS.")
+
+	fs.Float64Var(&otlpSamplingRatio, "otlp-sampling-ratio", 1,
+		"The ratio of reconciles which are traced, between 0 and 1.")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
+	fs.DurationVar(&clusterResyncPeriod, "openstackcluster-resync-period", 10*time.Minute,
+		"The interval at which the OpenStack resources of an OpenStackCluster are checked for out-of-band changes and repaired (e.g. 15m). Set to 0 to disable.")
+
+	fs.DurationVar(&instanceActionPollInterval, "instance-action-poll-interval", 10*time.Minute,
+		"The interval at which the actions on the instances of OpenStackMachines are polled to report the ones not requested by the controller, e.g. live migrations, as events (e.g. 15m). Set to 0 to disable.")
+
+	fs.DurationVar(&loadBalancerStatsInterval, "apiserver-loadbalancer-stats-interval", time.Minute,
+		"The interval at which the statistics of the listeners of the API server load balancers, e.g. active connections and bytes, are fetched from Octavia and exposed as metrics (e.g. 5m). Set to 0 to disable.")
+
+	fs.DurationVar(&resourceMetricsInterval, "cluster-resource-metrics-interval", 5*time.Minute,
+		"The interval at which the number of ports, trunks, floating IPs and security group rules managed for each OpenStackCluster and the usage of the network quotas of its project are exposed as metrics (e.g. 10m). Set to 0 to disable.")
+
+	fs.StringVar(&notificationListenerAddr, "notification-listener-bind-address", "",
+		"The address the receiver of the Nova and Neutron notifications binds to, e.g. :9445. The notifications about instances and their ports are posted to it one per request by a bridge consuming the notification queues, and trigger the reconcile of their machines and bastions. Disabled if unset.")
+
+	fs.StringVar(&notificationListenerTokenFile, "notification-listener-token-file", "",
+		"A file with the bearer token the requests to the notification receiver must carry. Requests are not authenticated if unset.")
+
 	fs.IntVar(&webhookPort, "webhook-port", 9443,
 		"Webhook Server port")
 
@@ -130,8 +293,26 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
 
+	fs.StringSliceVar(&cloudHealthCheckIdentities, "cloud-health-check-identities", nil,
+		"Identity secrets (<namespace>/<name>) whose clouds are checked by the readiness probe. The controller is ready if at least one cloud is reachable and accepts its credentials. No cloud is checked if unset.")
+
+	fs.StringVar(&cloudHealthCheckCloudName, "cloud-health-check-cloud-name", "openstack",
+		"The name of the cloud in the clouds.yaml of the identity secrets checked by the readiness probe.")
+
+	fs.DurationVar(&cloudHealthCheckInterval, "cloud-health-check-interval", time.Minute,
+		"The minimum interval between two checks of the clouds by the readiness probe (e.g. 5m). Probes in between return the result of the last check.")
+
 	fs.StringVar(&lbProvider, "lb-provider", "amphora",
 		"The name of the load balancer provider (amphora or ovn) to use (defaults to amphora).")
+
+	fs.StringVar(&credentialValidationMode, "cluster-credential-validation", string(webhooks.ValidationDisabled),
+		"Whether to authenticate and probe the cloud of an OpenStackCluster on admission, and how to report problems (disabled, warn or reject).")
+
+	fs.StringVar(&networkValidationMode, "cluster-network-validation", string(webhooks.ValidationDisabled),
+		"Whether to check on admission that the pod and service CIDRs of a Cluster do not overlap the subnets of its nodes, and how to report problems (disabled, warn or reject).")
+
+	fs.StringVar(&machineResourceValidationMode, "machine-template-resource-validation", string(webhooks.ValidationDisabled),
+		"Whether to check on admission that the flavor, image, networks and security groups of an OpenStackMachineTemplate exist, and how to report problems (disabled, warn or reject).")
 }
 
 func main() {
@@ -155,21 +336,37 @@ func main() {
 		}()
 	}
 
+	if otlpEndpoint != "" {
+		shutdownTracing, err := tracing.Setup(context.Background(), otlpEndpoint, otlpInsecure, otlpSamplingRatio)
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				setupLog.Error(err, "unable to shut down tracing")
+			}
+		}()
+	}
+
 	cfg, err := config.GetConfigWithContext(os.Getenv("KUBECONTEXT"))
 	if err != nil {
 		setupLog.Error(err, "unable to get kubeconfig")
 	}
 
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
-		Scheme:             scheme,
-		MetricsBindAddress: metricsBindAddr,
-		LeaderElection:     enableLeaderElection,
-		LeaderElectionID:   "controller-leader-election-capo",
-		LeaseDuration:      &leaderElectionLeaseDuration,
-		RenewDeadline:      &leaderElectionRenewDeadline,
-		RetryPeriod:        &leaderElectionRetryPeriod,
-		Namespace:          watchNamespace,
-		SyncPeriod:         &syncPeriod,
+		Scheme:                        scheme,
+		MetricsBindAddress:            metricsBindAddr,
+		LeaderElection:                enableLeaderElection,
+		LeaderElectionID:              "controller-leader-election-capo",
+		LeaderElectionNamespace:       leaderElectionNamespace,
+		LeaderElectionReleaseOnCancel: leaderElectionReleaseOnCancel,
+		LeaseDuration:                 &leaderElectionLeaseDuration,
+		RenewDeadline:                 &leaderElectionRenewDeadline,
+		RetryPeriod:                   &leaderElectionRetryPeriod,
+		GracefulShutdownTimeout:       &gracefulShutdownTimeout,
+		Namespace:                     watchNamespace,
+		SyncPeriod:                    &syncPeriod,
 		ClientDisableCacheFor: []client.Object{
 			&corev1.ConfigMap{},
 			&corev1.Secret{},
@@ -189,7 +386,26 @@ func main() {
 	// Initialize event recorder.
 	record.InitFromRecorder(mgr.GetEventRecorderFor("openstack-controller"))
 
+	// Limit and retry the requests sent to OpenStack by all controllers and webhooks.
+	provider.SetRateLimit(openStackAPIQPS, openStackAPIBurst)
+	provider.SetCreationLimits(openStackCreateConcurrency, openStackCreateQPS, openStackCreateBurst)
+	provider.SetRetryPolicy(openStackAPIMaxRetries, openStackAPIRetryBaseDelay, openStackAPIRetryMaxDelay)
+	provider.SetTransportOptions(openStackAPIMaxIdleConnsPerHost, openStackAPIIdleConnTimeout, openStackAPIEnableHTTP2)
+	cache.SetDefaultTTL(openStackAPICacheTTL)
+	cache.SetGenerationTTL(templateLookupTTL)
+	networking.SetParallelism(openStackResourceParallelism)
+	provider.SetIdentityNamespaces(identitySecretNamespaces)
+	if approvalWebhookURL != "" {
+		approvalWebhook, err := approval.NewWebhook(approvalWebhookURL, approvalWebhookCAFile, approvalWebhookTimeout)
+		if err != nil {
+			setupLog.Error(err, "unable to set up approval webhook")
+			os.Exit(1)
+		}
+		approval.SetWebhook(approvalWebhook)
+	}
+
 	setupChecks(mgr)
+	setupLeaderElection(mgr)
 	setupReconcilers(ctx, mgr)
 	setupWebhooks(mgr)
 
@@ -211,25 +427,146 @@ func setupChecks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create health check")
 		os.Exit(1)
 	}
+
+	if len(cloudHealthCheckIdentities) > 0 {
+		cloudChecker, err := provider.NewCloudChecker(mgr.GetClient(), cloudHealthCheckIdentities, cloudHealthCheckCloudName, cloudHealthCheckInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to create cloud ready check")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("cloud", cloudChecker.Check); err != nil {
+			setupLog.Error(err, "unable to create ready check")
+			os.Exit(1)
+		}
+	}
+}
+
+// setupLeaderElection prepares a replica to take over the reconciles once it
+// is elected. Standby replicas only serve webhooks, which cache responses of
+// OpenStack without seeing the writes of the leader, so the cache is purged
+// when the replica is elected.
+func setupLeaderElection(mgr ctrl.Manager) {
+	err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		cache.Purge()
+		setupLog.Info("elected as leader, starting controllers")
+		<-ctx.Done()
+		return nil
+	}))
+	if err != nil {
+		setupLog.Error(err, "unable to set up leader election")
+		os.Exit(1)
+	}
+}
+
+// setupNotificationListener starts the receiver of the notifications of the
+// cloud on the leader, and returns the trigger of the reconciles for them, or
+// nil if it is disabled.
+func setupNotificationListener(ctx context.Context, mgr ctrl.Manager) *controllers.InstanceNotificationTrigger {
+	if notificationListenerAddr == "" {
+		return nil
+	}
+
+	var token string
+	if notificationListenerTokenFile != "" {
+		data, err := os.ReadFile(notificationListenerTokenFile)
+		if err != nil {
+			setupLog.Error(err, "unable to read notification listener token")
+			os.Exit(1)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	trigger, err := controllers.NewInstanceNotificationTrigger(ctx, mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to set up notification listener")
+		os.Exit(1)
+	}
+	server := &http.Server{
+		Addr:              notificationListenerAddr,
+		Handler:           notifications.NewReceiver(trigger.HandleInstance, token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+		setupLog.Info("notification listener listening for requests", "address", notificationListenerAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}))
+	if err != nil {
+		setupLog.Error(err, "unable to set up notification listener")
+		os.Exit(1)
+	}
+	return trigger
 }
 
 func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
+	var machineInstanceEvents, clusterInstanceEvents <-chan event.GenericEvent
+	if trigger := setupNotificationListener(ctx, mgr); trigger != nil {
+		machineInstanceEvents = trigger.MachineEvents()
+		clusterInstanceEvents = trigger.ClusterEvents()
+	}
+
 	if err := (&controllers.OpenStackClusterReconciler{
-		Client:           mgr.GetClient(),
-		Recorder:         mgr.GetEventRecorderFor("openstackcluster-controller"),
-		WatchFilterValue: watchFilterValue,
+		Client:                    mgr.GetClient(),
+		Recorder:                  mgr.GetEventRecorderFor("openstackcluster-controller"),
+		WatchFilterValue:          watchFilterValue,
+		ResyncPeriod:              clusterResyncPeriod,
+		LoadBalancerStatsInterval: loadBalancerStatsInterval,
+		ResourceMetricsInterval:   resourceMetricsInterval,
+		InstanceEvents:            clusterInstanceEvents,
 	}).SetupWithManager(ctx, mgr, concurrency(openStackClusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenStackCluster")
 		os.Exit(1)
 	}
 	if err := (&controllers.OpenStackMachineReconciler{
-		Client:           mgr.GetClient(),
-		Recorder:         mgr.GetEventRecorderFor("openstackmachine-controller"),
-		WatchFilterValue: watchFilterValue,
+		Client:                     mgr.GetClient(),
+		Recorder:                   mgr.GetEventRecorderFor("openstackmachine-controller"),
+		WatchFilterValue:           watchFilterValue,
+		InstanceActionPollInterval: instanceActionPollInterval,
+		InstanceEvents:             machineInstanceEvents,
 	}).SetupWithManager(ctx, mgr, concurrency(openStackMachineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenStackMachine")
 		os.Exit(1)
 	}
+	if err := (&controllers.OpenStackMachineTemplateReconciler{
+		Client:           mgr.GetClient(),
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, concurrency(openStackMachineTemplateConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenStackMachineTemplate")
+		os.Exit(1)
+	}
+	if err := (&controllers.OpenStackServerReconciler{
+		Client:               mgr.GetClient(),
+		Recorder:             mgr.GetEventRecorderFor("openstackserver-controller"),
+		WatchFilterValue:     watchFilterValue,
+		BulkCreateMinServers: openStackServerBulkCreateMinServers,
+	}).SetupWithManager(ctx, mgr, concurrency(openStackServerConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenStackServer")
+		os.Exit(1)
+	}
+	if err := (&controllers.OpenStackFloatingIPPoolReconciler{
+		Client:           mgr.GetClient(),
+		Recorder:         mgr.GetEventRecorderFor("openstackfloatingippool-controller"),
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, concurrency(openStackFloatingIPPoolConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenStackFloatingIPPool")
+		os.Exit(1)
+	}
+	if err := (&controllers.OpenStackRemediationReconciler{
+		Client:           mgr.GetClient(),
+		Recorder:         mgr.GetEventRecorderFor("openstackremediation-controller"),
+		WatchFilterValue: watchFilterValue,
+	}).SetupWithManager(ctx, mgr, concurrency(openStackRemediationConcurrency)); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenStackRemediation")
+		os.Exit(1)
+	}
 }
 
 func setupWebhooks(mgr ctrl.Manager) {
@@ -261,6 +598,65 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackClusterList")
 		os.Exit(1)
 	}
+	if err := (&infrav1.OpenStackServer{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackServer")
+		os.Exit(1)
+	}
+	if err := (&infrav1.OpenStackFloatingIPPool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackFloatingIPPool")
+		os.Exit(1)
+	}
+
+	mode, err := webhooks.ParseValidationMode(credentialValidationMode)
+	if err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackClusterCredentials")
+		os.Exit(1)
+	}
+	if err := (&webhooks.OpenStackClusterCredentialValidator{
+		Client: mgr.GetClient(),
+		Mode:   mode,
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackClusterCredentials")
+		os.Exit(1)
+	}
+
+	mode, err = webhooks.ParseValidationMode(networkValidationMode)
+	if err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackClusterNetwork")
+		os.Exit(1)
+	}
+	if err := (&webhooks.OpenStackClusterNetworkValidator{
+		Client: mgr.GetClient(),
+		Mode:   mode,
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackClusterNetwork")
+		os.Exit(1)
+	}
+
+	mode, err = webhooks.ParseValidationMode(machineResourceValidationMode)
+	if err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackMachineTemplateResources")
+		os.Exit(1)
+	}
+	if err := (&webhooks.OpenStackMachineTemplateResourceValidator{
+		Client: mgr.GetClient(),
+		Mode:   mode,
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "OpenStackMachineTemplateResources")
+		os.Exit(1)
+	}
+
+	if err := (&webhooks.ClusterDeletionProtectionValidator{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ClusterDeletionProtection")
+		os.Exit(1)
+	}
+
+	if err := (&webhooks.DeprecatedFieldsValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DeprecatedFields")
+		os.Exit(1)
+	}
 }
 
 func concurrency(c int) controller.Options {
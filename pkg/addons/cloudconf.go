@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+const (
+	// CloudConfigSecretName is the name of the Secret in the kube-system
+	// namespace of the workload cluster holding the cloud.conf of the addons.
+	CloudConfigSecretName = "cloud-config"
+
+	cloudConfKey    = "cloud.conf"
+	caCertKey       = "ca.crt"
+	configMountPath = "/etc/config"
+)
+
+// CloudConfigSecret returns the Secret holding the cloud.conf rendered for
+// the cluster, and the CA certificate of the cloud if there is one.
+func CloudConfigSecret(cloud clientconfig.Cloud, caCert []byte, openStackCluster *infrav1.OpenStackCluster) (*corev1.Secret, error) {
+	cloudConf, err := CloudConf(cloud, len(caCert) > 0, openStackCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{
+		cloudConfKey: []byte(cloudConf),
+	}
+	if len(caCert) > 0 {
+		data[caCertKey] = caCert
+	}
+
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CloudConfigSecretName,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
+
+// CloudConf renders the cloud.conf of the OpenStack cloud controller manager
+// and the Cinder CSI driver. The credentials are taken from the identity of
+// the cluster, and the load balancer and networking sections from the network
+// reconciled for the cluster.
+func CloudConf(cloud clientconfig.Cloud, hasCACert bool, openStackCluster *infrav1.OpenStackCluster) (string, error) {
+	auth := cloud.AuthInfo
+	if auth == nil || auth.AuthURL == "" {
+		return "", fmt.Errorf("cloud %q has no auth_url", openStackCluster.Spec.CloudName)
+	}
+	network := openStackCluster.Status.Network
+	if network == nil || network.Subnet == nil {
+		return "", fmt.Errorf("network of the cluster is not reconciled yet")
+	}
+
+	w := &iniWriter{}
+	w.section("Global")
+	w.set("auth-url", auth.AuthURL)
+	w.set("region", cloud.RegionName)
+	w.set("interface", cloud.Interface)
+	w.set("application-credential-id", auth.ApplicationCredentialID)
+	w.set("application-credential-name", auth.ApplicationCredentialName)
+	w.set("application-credential-secret", auth.ApplicationCredentialSecret)
+	w.set("username", auth.Username)
+	w.set("user-id", auth.UserID)
+	w.set("password", auth.Password)
+	w.set("tenant-id", auth.ProjectID)
+	w.set("tenant-name", auth.ProjectName)
+	w.set("tenant-domain-id", auth.ProjectDomainID)
+	w.set("tenant-domain-name", auth.ProjectDomainName)
+	w.set("user-domain-id", auth.UserDomainID)
+	w.set("user-domain-name", auth.UserDomainName)
+	w.set("domain-id", auth.DomainID)
+	w.set("domain-name", auth.DomainName)
+	if hasCACert {
+		w.set("ca-file", configMountPath+"/"+caCertKey)
+	}
+	if cloud.Verify != nil && !*cloud.Verify {
+		w.set("tls-insecure", "true")
+	}
+
+	w.section("Networking")
+	w.set("internal-network-name", network.Name)
+	if externalNetwork := openStackCluster.Status.ExternalNetwork; externalNetwork != nil {
+		w.set("public-network-name", externalNetwork.Name)
+	}
+
+	// The cluster only relies on Octavia if it has an API server load
+	// balancer, so Services of type LoadBalancer are only enabled then.
+	w.section("LoadBalancer")
+	if !openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		w.set("enabled", "false")
+		return w.String(), nil
+	}
+	w.set("network-id", network.ID)
+	w.set("subnet-id", network.Subnet.ID)
+	if externalNetwork := openStackCluster.Status.ExternalNetwork; externalNetwork != nil {
+		w.set("floating-network-id", externalNetwork.ID)
+	}
+	if openStackCluster.Spec.ManagedSecurityGroups != nil {
+		w.set("manage-security-groups", "true")
+	}
+
+	return w.String(), nil
+}
+
+// iniWriter writes the gcfg format of cloud.conf, omitting empty values.
+type iniWriter struct {
+	b strings.Builder
+}
+
+func (w *iniWriter) section(name string) {
+	if w.b.Len() > 0 {
+		w.b.WriteString("\n")
+	}
+	fmt.Fprintf(&w.b, "[%s]\n", name)
+}
+
+func (w *iniWriter) set(key, value string) {
+	if value == "" {
+		return
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	fmt.Fprintf(&w.b, "%s=\"%s\"\n", key, value)
+}
+
+func (w *iniWriter) String() string {
+	return w.b.String()
+}
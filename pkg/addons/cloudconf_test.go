@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"testing"
+
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestCloudConf(t *testing.T) {
+	cloud := clientconfig.Cloud{
+		RegionName: "RegionOne",
+		AuthInfo: &clientconfig.AuthInfo{
+			AuthURL:                     "https://keystone.example.com:5000/v3",
+			ApplicationCredentialID:     "app-cred-id",
+			ApplicationCredentialSecret: `se"cret`,
+		},
+	}
+	network := &infrav1.Network{
+		Name:   "k8s-clusterapi-cluster-default-foo",
+		ID:     "network-id",
+		Subnet: &infrav1.Subnet{ID: "subnet-id"},
+	}
+	externalNetwork := &infrav1.Network{
+		Name: "public",
+		ID:   "external-network-id",
+	}
+
+	tests := []struct {
+		name             string
+		cloud            clientconfig.Cloud
+		hasCACert        bool
+		openStackCluster *infrav1.OpenStackCluster
+		want             string
+		wantErr          bool
+	}{
+		{
+			name:      "Load balancer enabled",
+			cloud:     cloud,
+			hasCACert: true,
+			openStackCluster: &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					APIServerLoadBalancer: infrav1.APIServerLoadBalancer{Enabled: true},
+					ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network:         network,
+					ExternalNetwork: externalNetwork,
+				},
+			},
+			want: `[Global]
+auth-url="https://keystone.example.com:5000/v3"
+region="RegionOne"
+application-credential-id="app-cred-id"
+application-credential-secret="se\"cret"
+ca-file="/etc/config/ca.crt"
+
+[Networking]
+internal-network-name="k8s-clusterapi-cluster-default-foo"
+public-network-name="public"
+
+[LoadBalancer]
+network-id="network-id"
+subnet-id="subnet-id"
+floating-network-id="external-network-id"
+manage-security-groups="true"
+`,
+		},
+		{
+			name: "Load balancer disabled",
+			cloud: clientconfig.Cloud{
+				AuthInfo: &clientconfig.AuthInfo{
+					AuthURL:        "https://keystone.example.com:5000/v3",
+					Username:       "user",
+					Password:       "password",
+					ProjectName:    "project",
+					UserDomainName: "Default",
+				},
+				Verify: pointer.Bool(false),
+			},
+			openStackCluster: &infrav1.OpenStackCluster{
+				Status: infrav1.OpenStackClusterStatus{
+					Network: network,
+				},
+			},
+			want: `[Global]
+auth-url="https://keystone.example.com:5000/v3"
+username="user"
+password="password"
+tenant-name="project"
+user-domain-name="Default"
+tls-insecure="true"
+
+[Networking]
+internal-network-name="k8s-clusterapi-cluster-default-foo"
+
+[LoadBalancer]
+enabled="false"
+`,
+		},
+		{
+			name:  "No auth URL",
+			cloud: clientconfig.Cloud{},
+			openStackCluster: &infrav1.OpenStackCluster{
+				Status: infrav1.OpenStackClusterStatus{
+					Network: network,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:             "Network not reconciled",
+			cloud:            cloud,
+			openStackCluster: &infrav1.OpenStackCluster{},
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := CloudConf(tt.cloud, tt.hasCACert, tt.openStackCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
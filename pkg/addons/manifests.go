@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"bytes"
+	// Import embed to embed the manifests of the addons.
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Component is a component of cloud-provider-openstack which can be installed
+// into the workload cluster.
+type Component string
+
+const (
+	// CloudControllerManager is the OpenStack cloud controller manager.
+	CloudControllerManager Component = "openstack-cloud-controller-manager"
+	// CinderCSI is the Cinder CSI driver.
+	CinderCSI Component = "cinder-csi"
+)
+
+var (
+	//go:embed manifests/openstack-cloud-controller-manager.yaml
+	cloudControllerManagerManifest string
+	//go:embed manifests/cinder-csi.yaml
+	cinderCSIManifest string
+
+	manifests = map[Component]*template.Template{
+		CloudControllerManager: template.Must(template.New(string(CloudControllerManager)).Parse(cloudControllerManagerManifest)),
+		CinderCSI:              template.Must(template.New(string(CinderCSI)).Parse(cinderCSIManifest)),
+	}
+)
+
+// ManifestData are the values the manifests of the components are rendered
+// with.
+type ManifestData struct {
+	// ImageRepository is the repository of the cloud-provider-openstack images.
+	ImageRepository string
+	// Version is the cloud-provider-openstack release.
+	Version string
+	// ClusterName identifies the cluster in the metadata of the volumes.
+	ClusterName string
+	// CloudConfigChecksum is set as an annotation of the pods, so that they
+	// are restarted when the cloud.conf changes.
+	CloudConfigChecksum string
+}
+
+// Objects renders the manifest of the component and returns its objects.
+func Objects(component Component, data ManifestData) ([]*unstructured.Unstructured, error) {
+	tmpl, ok := manifests[component]
+	if !ok {
+		return nil, fmt.Errorf("unknown component %q", component)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render manifest of %s: %w", component, err)
+	}
+
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(&buf, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode manifest of %s: %w", component, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestObjects(t *testing.T) {
+	data := ManifestData{
+		ImageRepository:     "registry.example.com/provider-os",
+		Version:             "v1.24.6",
+		ClusterName:         "default-foo",
+		CloudConfigChecksum: "12345",
+	}
+
+	tests := []struct {
+		component Component
+		workload  string
+		images    []string
+	}{
+		{
+			component: CloudControllerManager,
+			workload:  "openstack-cloud-controller-manager",
+			images:    []string{"registry.example.com/provider-os/openstack-cloud-controller-manager:v1.24.6"},
+		},
+		{
+			component: CinderCSI,
+			workload:  "csi-cinder-controllerplugin",
+			images: []string{
+				"registry.k8s.io/sig-storage/csi-attacher:v3.4.0",
+				"registry.k8s.io/sig-storage/csi-provisioner:v3.1.0",
+				"registry.k8s.io/sig-storage/csi-snapshotter:v6.0.1",
+				"registry.k8s.io/sig-storage/csi-resizer:v1.4.0",
+				"registry.k8s.io/sig-storage/livenessprobe:v2.7.0",
+				"registry.example.com/provider-os/cinder-csi-plugin:v1.24.6",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.component), func(t *testing.T) {
+			g := NewWithT(t)
+			objects, err := Objects(tt.component, data)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			var workload *unstructured.Unstructured
+			for _, obj := range objects {
+				g.Expect(obj.GetKind()).NotTo(BeEmpty())
+				g.Expect(obj.GetName()).NotTo(BeEmpty())
+				if obj.GetName() == tt.workload {
+					workload = obj
+				}
+			}
+			g.Expect(workload).NotTo(BeNil())
+
+			annotations, _, _ := unstructured.NestedStringMap(workload.Object, "spec", "template", "metadata", "annotations")
+			g.Expect(annotations).To(HaveKeyWithValue("checksum/cloud-config", "12345"))
+
+			containers, _, _ := unstructured.NestedSlice(workload.Object, "spec", "template", "spec", "containers")
+			var images []string
+			for _, container := range containers {
+				images = append(images, container.(map[string]interface{})["image"].(string))
+			}
+			g.Expect(images).To(Equal(tt.images))
+		})
+	}
+}
+
+func TestObjectsUnknownComponent(t *testing.T) {
+	g := NewWithT(t)
+	_, err := Objects("foo", ManifestData{})
+	g.Expect(err).To(HaveOccurred())
+}
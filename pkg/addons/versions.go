@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+)
+
+// DefaultImageRepository is the repository the images of cloud-provider-openstack
+// are pulled from unless the addon sets another one.
+const DefaultImageRepository = "registry.k8s.io/provider-os"
+
+// releases are the cloud-provider-openstack releases installed by default,
+// one per supported Kubernetes minor version in ascending order.
+var releases = []string{
+	"v1.22.2",
+	"v1.23.4",
+	"v1.24.6",
+	"v1.25.5",
+}
+
+// Release returns the cloud-provider-openstack release matching the given
+// Kubernetes version. Kubernetes versions newer than the newest known
+// release use the newest release.
+func Release(kubernetesVersion string) (string, error) {
+	version, err := semver.ParseTolerant(kubernetesVersion)
+	if err != nil {
+		return "", fmt.Errorf("invalid Kubernetes version %q: %w", kubernetesVersion, err)
+	}
+
+	for i := len(releases) - 1; i >= 0; i-- {
+		release := semver.MustParse(releases[i][1:])
+		if version.Major == release.Major && version.Minor >= release.Minor {
+			return releases[i], nil
+		}
+	}
+	oldest := semver.MustParse(releases[0][1:])
+	return "", fmt.Errorf("kubernetes version %s is not supported, the oldest supported version is %d.%d", kubernetesVersion, oldest.Major, oldest.Minor)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRelease(t *testing.T) {
+	tests := []struct {
+		kubernetesVersion string
+		want              string
+		wantErr           bool
+	}{
+		{kubernetesVersion: "v1.24.2", want: "v1.24.6"},
+		{kubernetesVersion: "v1.22.0", want: "v1.22.2"},
+		{kubernetesVersion: "v1.25.3+k3s1", want: "v1.25.5"},
+		{kubernetesVersion: "v1.27.1", want: "v1.25.5"},
+		{kubernetesVersion: "v1.21.9", wantErr: true},
+		{kubernetesVersion: "latest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kubernetesVersion, func(t *testing.T) {
+			g := NewWithT(t)
+			got, err := Release(tt.kubernetesVersion)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package approval asks an external system to approve security group rules
+// and floating IPs before they are created, so that changes exposing the
+// machines can be routed through a firewall approval process.
+package approval
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+const (
+	// KindSecurityGroupRule is the kind of requests for security group rules.
+	KindSecurityGroupRule = "SecurityGroupRule"
+	// KindFloatingIP is the kind of requests for floating IPs.
+	KindFloatingIP = "FloatingIP"
+)
+
+// maxResponseSize is the maximum size of a response of the webhook read.
+const maxResponseSize = 64 * 1024
+
+// Request is the body of the request sent to the webhook.
+type Request struct {
+	// Kind is the kind of the OpenStack resource about to be created, i.e.
+	// SecurityGroupRule or FloatingIP.
+	Kind string `json:"kind"`
+
+	// Object is the object the resource is created for.
+	Object ObjectReference `json:"object"`
+
+	// SecurityGroupRule is the rule about to be created, for requests of
+	// kind SecurityGroupRule.
+	SecurityGroupRule *infrav1.SecurityGroupRule `json:"securityGroupRule,omitempty"`
+
+	// FloatingIP is the floating IP about to be created, for requests of
+	// kind FloatingIP.
+	FloatingIP *FloatingIP `json:"floatingIP,omitempty"`
+}
+
+// ObjectReference references the object an OpenStack resource is created for.
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// FloatingIP describes a floating IP about to be created.
+type FloatingIP struct {
+	// NetworkID is the ID of the external network of the floating IP.
+	NetworkID string `json:"networkID"`
+
+	// Address is the requested address, if any.
+	Address string `json:"address,omitempty"`
+}
+
+// Response is the body of the response of the webhook.
+type Response struct {
+	// Allowed is true if the resource may be created.
+	Allowed bool `json:"allowed"`
+
+	// Reason explains why the resource may not be created.
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeniedError is returned for resources whose creation was denied.
+type DeniedError struct {
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	if e.Reason == "" {
+		return "creation denied by approval webhook"
+	}
+	return fmt.Sprintf("creation denied by approval webhook: %s", e.Reason)
+}
+
+// IsDenied returns true if err was caused by a denied approval request.
+func IsDenied(err error) bool {
+	var denied *DeniedError
+	return errors.As(err, &denied)
+}
+
+// Webhook sends approval requests to an HTTP endpoint.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook returns a webhook sending its requests to url. The certificate
+// of an HTTPS endpoint is verified with the certificates in caFile, or with
+// the system certificates if caFile is empty.
+func NewWebhook(url, caFile string, timeout time.Duration) (*Webhook, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file of approval webhook: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA file %s of approval webhook", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &Webhook{
+		url:    url,
+		client: &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+// Review sends the request to the webhook and returns a DeniedError if the
+// resource may not be created. Other errors mean the request could not be
+// decided and should be retried.
+func (w *Webhook) Review(ctx context.Context, request Request) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := w.client.Do(httpRequest)
+	if err != nil {
+		return fmt.Errorf("approval webhook: %w", err)
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("approval webhook returned status %d", httpResponse.StatusCode)
+	}
+
+	response := Response{}
+	if err := json.NewDecoder(io.LimitReader(httpResponse.Body, maxResponseSize)).Decode(&response); err != nil {
+		return fmt.Errorf("decode response of approval webhook: %w", err)
+	}
+	if !response.Allowed {
+		return &DeniedError{Reason: response.Reason}
+	}
+	return nil
+}
+
+var defaultWebhook *Webhook
+
+// SetWebhook sets the webhook asked by Review. Requests are not reviewed if
+// webhook is nil.
+func SetWebhook(webhook *Webhook) {
+	defaultWebhook = webhook
+}
+
+// Review asks the webhook set with SetWebhook whether the resource of the
+// request may be created. All resources may be created if no webhook is set.
+func Review(ctx context.Context, request Request) error {
+	if defaultWebhook == nil {
+		return nil
+	}
+	return defaultWebhook.Review(ctx, request)
+}
+
+// ObjectReferenceFor returns a reference to obj for a request.
+func ObjectReferenceFor(obj runtime.Object) ObjectReference {
+	ref := ObjectReference{}
+	if obj == nil {
+		return ref
+	}
+	ref.Kind = obj.GetObjectKind().GroupVersionKind().Kind
+	if ref.Kind == "" {
+		ref.Kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		ref.Namespace = accessor.GetNamespace()
+		ref.Name = accessor.GetName()
+	}
+	return ref
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestWebhookReview(t *testing.T) {
+	g := NewWithT(t)
+
+	var received Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = Request{}
+		g.Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+		switch {
+		case received.FloatingIP != nil:
+			_ = json.NewEncoder(w).Encode(Response{Allowed: true})
+		case received.SecurityGroupRule.RemoteIPPrefix == "":
+			_ = json.NewEncoder(w).Encode(Response{Allowed: false, Reason: "ingress from anywhere"})
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL, "", time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	ctx := context.TODO()
+
+	cluster := &infrav1.OpenStackCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cluster"}}
+	err = webhook.Review(ctx, Request{Kind: KindFloatingIP, Object: ObjectReferenceFor(cluster), FloatingIP: &FloatingIP{NetworkID: "external"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(received.Object).To(Equal(ObjectReference{Kind: "OpenStackCluster", Namespace: "default", Name: "cluster"}))
+
+	err = webhook.Review(ctx, Request{Kind: KindSecurityGroupRule, SecurityGroupRule: &infrav1.SecurityGroupRule{Protocol: "tcp"}})
+	g.Expect(IsDenied(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("ingress from anywhere"))
+
+	err = webhook.Review(ctx, Request{Kind: KindSecurityGroupRule, SecurityGroupRule: &infrav1.SecurityGroupRule{Protocol: "tcp", RemoteIPPrefix: "10.0.0.0/8"}})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(IsDenied(err)).To(BeFalse())
+}
+
+func TestReviewWithoutWebhook(t *testing.T) {
+	g := NewWithT(t)
+
+	SetWebhook(nil)
+	g.Expect(Review(context.TODO(), Request{Kind: KindFloatingIP})).To(Succeed())
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// ExternalAttachments are the volumes and floating IPs attached to an
+// instance which CAPO did not create for it.
+type ExternalAttachments struct {
+	VolumeIDs   []string
+	FloatingIPs []string
+}
+
+// IsEmpty returns whether no external volumes or floating IPs are attached.
+func (a *ExternalAttachments) IsEmpty() bool {
+	return len(a.VolumeIDs) == 0 && len(a.FloatingIPs) == 0
+}
+
+// GetExternalAttachments returns the volumes and floating IPs attached to the
+// instance by other controllers or by hand: all volumes but the root volume
+// of the instance, and the floating IPs on its ports which CAPO did not
+// create.
+func (s *Service) GetExternalAttachments(instanceStatus *InstanceStatus) (*ExternalAttachments, error) {
+	attachments := &ExternalAttachments{}
+
+	for _, volumeID := range instanceStatus.AttachedVolumeIDs() {
+		volume, err := s.computeService.GetVolume(volumeID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("get volume %s: %w", volumeID, err)
+		}
+		if isRootVolumeOf(volume, instanceStatus.Name()) {
+			continue
+		}
+		attachments.VolumeIDs = append(attachments.VolumeIDs, volumeID)
+	}
+
+	portList, err := s.networkingService.ListPortsForInstance(instanceStatus.ID())
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range portList {
+		fip, err := s.networkingService.GetFloatingIPByPortID(port.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get floating IP of port %s: %w", port.ID, err)
+		}
+		if fip == nil || names.IsCreatedByCAPO(fip.Description) {
+			continue
+		}
+		attachments.FloatingIPs = append(attachments.FloatingIPs, fip.FloatingIP)
+	}
+
+	return attachments, nil
+}
+
+// isRootVolumeOf returns whether the volume is the root volume CAPO created
+// for the server. Root volumes created before they had metadata are
+// recognised by their name.
+func isRootVolumeOf(volume *volumes.Volume, serverName string) bool {
+	if server, ok := volume.Metadata[volumeServerMetadataKey]; ok {
+		return server == serverName
+	}
+	return volume.Name == rootVolumeName(serverName)
+}
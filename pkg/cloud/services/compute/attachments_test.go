@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+func TestService_GetExternalAttachments(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockComputeClient := NewMockClient(mockCtrl)
+	mockNetworkClient := mock_networking.NewMockNetworkClient(mockCtrl)
+
+	computeRecorder := mockComputeClient.EXPECT()
+	computeRecorder.GetVolume("root-volume-id").Return(&volumes.Volume{ID: "root-volume-id", Name: "machine-0-root", Metadata: map[string]string{volumeServerMetadataKey: "machine-0"}}, nil)
+	computeRecorder.GetVolume("legacy-root-volume-id").Return(&volumes.Volume{ID: "legacy-root-volume-id", Name: "machine-0-root"}, nil)
+	computeRecorder.GetVolume("data-volume-id").Return(&volumes.Volume{ID: "data-volume-id", Name: "data"}, nil)
+
+	networkRecorder := mockNetworkClient.EXPECT()
+	networkRecorder.ListPort(ports.ListOpts{DeviceID: "instance-id"}).Return([]ports.Port{{ID: "primary-port-id"}, {ID: "storage-port-id"}, {ID: "public-port-id"}}, nil)
+	networkRecorder.ListFloatingIP(floatingips.ListOpts{PortID: "primary-port-id"}).Return([]floatingips.FloatingIP{{FloatingIP: "192.0.2.1", Description: names.GetDescription("cluster")}}, nil)
+	networkRecorder.ListFloatingIP(floatingips.ListOpts{PortID: "storage-port-id"}).Return(nil, nil)
+	networkRecorder.ListFloatingIP(floatingips.ListOpts{PortID: "public-port-id"}).Return([]floatingips.FloatingIP{{FloatingIP: "192.0.2.10"}}, nil)
+
+	s := Service{
+		scope:             &scope.Scope{Logger: logr.Discard()},
+		computeService:    mockComputeClient,
+		networkingService: networking.NewTestService("", mockNetworkClient, logr.Discard()),
+	}
+	server := &ServerExt{Server: servers.Server{
+		ID:   "instance-id",
+		Name: "machine-0",
+		AttachedVolumes: []servers.AttachedVolume{
+			{ID: "root-volume-id"}, {ID: "legacy-root-volume-id"}, {ID: "data-volume-id"},
+		},
+	}}
+
+	attachments, err := s.GetExternalAttachments(NewInstanceStatusFromServer(server, logr.Discard()))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(attachments).To(Equal(&ExternalAttachments{
+		VolumeIDs:   []string{"data-volume-id"},
+		FloatingIPs: []string{"192.0.2.10"},
+	}))
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 )
 
 func (s *Service) GetAvailabilityZones() ([]availabilityzones.AvailabilityZone, error) {
@@ -30,3 +32,17 @@ func (s *Service) GetAvailabilityZones() ([]availabilityzones.AvailabilityZone,
 
 	return availabilityZoneList, nil
 }
+
+// SupportsAvailabilityZones returns whether the compute service lists its
+// availability zones. Clouds without the os-availability-zone API answer
+// with 404.
+func (s *Service) SupportsAvailabilityZones() (bool, error) {
+	_, err := s.computeService.ListAvailabilityZones()
+	if capoerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("list availability zones: %v", err)
+	}
+	return true, nil
+}
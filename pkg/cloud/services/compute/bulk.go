@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// bulkTagPrefix is the prefix of the tag of servers created in bulk. Servers
+// keep the tag as their name until they are claimed by an instance.
+const bulkTagPrefix = "capo-bulk-"
+
+// CanCreateInBulk returns whether instances with the given spec can be
+// created by a single multi-create request. Nova creates the ports of these
+// instances itself, so they must not need pre-created root volumes, trunks
+// or ports with any options besides their network.
+func CanCreateInBulk(instanceSpec *InstanceSpec) bool {
+	if instanceSpec.RootVolume != nil || instanceSpec.Trunk || len(instanceSpec.Networks) > 0 || len(instanceSpec.Ports) == 0 {
+		return false
+	}
+	for _, port := range instanceSpec.Ports {
+		if port.Network == nil || (port.Trunk != nil && *port.Trunk) {
+			return false
+		}
+		if !reflect.DeepEqual(infrav1.PortOpts{Network: port.Network, Trunk: port.Trunk}, port) {
+			return false
+		}
+	}
+	return true
+}
+
+// BulkTag returns the tag of the servers created in bulk for instances with
+// the given spec belonging to owner. Instances with the same tag only differ
+// in their name and user data, which are set when a server is claimed.
+func BulkTag(owner string, instanceSpec *InstanceSpec) (string, error) {
+	spec := *instanceSpec
+	spec.Name = ""
+	spec.UserData = ""
+	// Ports inherit the trunk setting of the instance once they were created
+	spec.Ports = make([]infrav1.PortOpts, len(instanceSpec.Ports))
+	for i, port := range instanceSpec.Ports {
+		if port.Trunk != nil && !*port.Trunk {
+			port.Trunk = nil
+		}
+		spec.Ports[i] = port
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal instance spec for bulk tag: %v", err)
+	}
+	hasher := sha256.New()
+	hasher.Write([]byte(owner))
+	hasher.Write(data)
+	return bulkTagPrefix + hex.EncodeToString(hasher.Sum(nil))[:16], nil
+}
+
+// CreateInstancesInBulk creates count servers with the given spec using a
+// single multi-create request. The servers are named and tagged with tag
+// until they are claimed with ClaimBulkInstance.
+func (s *Service) CreateInstancesInBulk(eventObject runtime.Object, instanceSpec *InstanceSpec, tag string, count int) error {
+	imageID, err := s.getImageID(instanceSpec.ImageUUID, instanceSpec.Image)
+	if err != nil {
+		return fmt.Errorf("error getting image ID: %v", err)
+	}
+
+	flavorID, err := s.computeService.GetFlavorIDFromName(instanceSpec.Flavor)
+	if err != nil {
+		return fmt.Errorf("error getting flavor id from flavor name %s: %v", instanceSpec.Flavor, err)
+	}
+
+	nets, err := s.constructNetworks(nil, instanceSpec)
+	if err != nil {
+		return err
+	}
+	networks := make([]servers.Network, 0, len(nets))
+	for _, net := range nets {
+		networks = append(networks, servers.Network{UUID: net.ID})
+	}
+
+	securityGroups, err := s.networkingService.GetSecurityGroups(instanceSpec.SecurityGroups)
+	if err != nil {
+		return fmt.Errorf("error getting security groups: %v", err)
+	}
+
+	var serverCreateOpts servers.CreateOptsBuilder = servers.CreateOpts{
+		Name:             tag,
+		ImageRef:         imageID,
+		FlavorRef:        flavorID,
+		AvailabilityZone: instanceSpec.FailureDomain,
+		Networks:         networks,
+		SecurityGroups:   securityGroups,
+		Tags:             append(append([]string{}, instanceSpec.Tags...), tag),
+		Metadata:         instanceSpec.Metadata,
+		ConfigDrive:      &instanceSpec.ConfigDrive,
+		Min:              count,
+		Max:              count,
+	}
+	serverCreateOpts = applyServerGroupID(serverCreateOpts, instanceSpec.ServerGroupID)
+
+	serverEvent := record.Resource{Kind: record.Server, Name: fmt.Sprintf("%s (%d servers)", tag, count)}
+	_, err = s.computeService.CreateServer(keypairs.CreateOptsExt{
+		CreateOptsBuilder: serverCreateOpts,
+		KeyName:           instanceSpec.SSHKeyName,
+	})
+	serverEvent.RequestID = s.scope.LastRequestID()
+	if err != nil {
+		record.Failed(eventObject, record.Create, serverEvent, err)
+		return fmt.Errorf("error creating Openstack instances in bulk: %w", capoerrors.WithRequestID(err, serverEvent.RequestID))
+	}
+	record.Succeeded(eventObject, record.Create, serverEvent)
+	return nil
+}
+
+// ClaimBulkInstance claims an active server created in bulk with the given
+// tag for the instance with the given spec. The server is rebuilt with the
+// name and user data of the instance, and loses the tag. It returns nil if
+// there is no server to claim, and building is set if servers with the tag
+// are not active yet. Servers which failed to build are deleted.
+func (s *Service) ClaimBulkInstance(eventObject runtime.Object, instanceSpec *InstanceSpec, tag string) (instance *InstanceStatus, building bool, err error) {
+	serverList, err := s.computeService.ListServers(servers.ListOpts{Tags: tag})
+	if err != nil {
+		return nil, false, fmt.Errorf("list servers created in bulk: %v", err)
+	}
+
+	var claimable *ServerExt
+	for i := range serverList {
+		server := &serverList[i]
+		// Servers which were renamed are already claimed
+		if server.Name != tag {
+			continue
+		}
+		switch infrav1.InstanceState(server.Status) {
+		case infrav1.InstanceStateActive:
+			if claimable == nil {
+				claimable = server
+			}
+		case infrav1.InstanceStateError:
+			if err := s.deleteBulkInstance(eventObject, server); err != nil {
+				return nil, false, err
+			}
+		default:
+			building = true
+		}
+	}
+	if claimable == nil {
+		return nil, building, nil
+	}
+
+	imageID, err := s.getImageID(instanceSpec.ImageUUID, instanceSpec.Image)
+	if err != nil {
+		return nil, false, fmt.Errorf("error getting image ID: %v", err)
+	}
+
+	serverEvent := record.Resource{Kind: record.Server, Name: instanceSpec.Name, ID: claimable.ID}
+	server, err := s.computeService.RebuildServer(claimable.ID, RebuildOpts{
+		RebuildOpts: servers.RebuildOpts{
+			ImageRef: imageID,
+			Name:     instanceSpec.Name,
+			Metadata: instanceSpec.Metadata,
+		},
+		UserData: instanceSpec.UserData,
+	})
+	serverEvent.RequestID = s.scope.LastRequestID()
+	if err != nil {
+		record.Failed(eventObject, record.Adopt, serverEvent, err)
+		return nil, false, fmt.Errorf("error rebuilding server %s created in bulk: %w", claimable.ID, capoerrors.WithRequestID(err, serverEvent.RequestID))
+	}
+	// The server is found by its name if removing the tag fails
+	if err := s.computeService.DeleteServerTag(claimable.ID, tag); err != nil {
+		return nil, false, fmt.Errorf("error removing tag %s of server %s: %v", tag, claimable.ID, err)
+	}
+	record.Succeeded(eventObject, record.Adopt, serverEvent)
+
+	return &InstanceStatus{server, s.scope.Logger}, false, nil
+}
+
+// DeleteBulkInstances deletes the servers created in bulk with the given tag
+// which were not claimed.
+func (s *Service) DeleteBulkInstances(eventObject runtime.Object, tag string) error {
+	serverList, err := s.computeService.ListServers(servers.ListOpts{Tags: tag})
+	if err != nil {
+		return fmt.Errorf("list servers created in bulk: %v", err)
+	}
+	for i := range serverList {
+		if serverList[i].Name != tag {
+			continue
+		}
+		if err := s.deleteBulkInstance(eventObject, &serverList[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteBulkInstance(eventObject runtime.Object, server *ServerExt) error {
+	serverEvent := record.Resource{Kind: record.Server, Name: server.Name, ID: server.ID}
+	if err := s.computeService.DeleteServer(server.ID); err != nil {
+		serverEvent.RequestID = s.scope.LastRequestID()
+		record.Failed(eventObject, record.Delete, serverEvent, err)
+		return fmt.Errorf("error deleting server %s created in bulk: %v", server.ID, err)
+	}
+	record.Succeeded(eventObject, record.Delete, serverEvent)
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestCanCreateInBulk(t *testing.T) {
+	network := &infrav1.NetworkFilter{ID: networkUUID}
+
+	tests := []struct {
+		name         string
+		instanceSpec InstanceSpec
+		want         bool
+	}{
+		{
+			name:         "ports with a network",
+			instanceSpec: InstanceSpec{Ports: []infrav1.PortOpts{{Network: network, Trunk: pointer.Bool(false)}}},
+			want:         true,
+		},
+		{
+			name:         "port with fixed IPs",
+			instanceSpec: InstanceSpec{Ports: []infrav1.PortOpts{{Network: network, FixedIPs: []infrav1.FixedIP{{IPAddress: "10.0.0.10"}}}}},
+			want:         false,
+		},
+		{
+			name:         "port with a trunk",
+			instanceSpec: InstanceSpec{Ports: []infrav1.PortOpts{{Network: network, Trunk: pointer.Bool(true)}}},
+			want:         false,
+		},
+		{
+			name:         "root volume",
+			instanceSpec: InstanceSpec{RootVolume: &infrav1.RootVolume{Size: 50}, Ports: []infrav1.PortOpts{{Network: network}}},
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(CanCreateInBulk(&tt.instanceSpec)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestBulkTag(t *testing.T) {
+	g := NewWithT(t)
+
+	spec := func(name, userData string, trunk *bool) *InstanceSpec {
+		return &InstanceSpec{
+			Name:     name,
+			UserData: userData,
+			Flavor:   flavorName,
+			Ports:    []infrav1.PortOpts{{Network: &infrav1.NetworkFilter{ID: networkUUID}, Trunk: trunk}},
+		}
+	}
+
+	tag, err := BulkTag("ns/cluster", spec("machine-1", "user-data-1", nil))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tag).To(HavePrefix(bulkTagPrefix))
+
+	// Instances only differing in their name and user data share the tag
+	g.Expect(BulkTag("ns/cluster", spec("machine-2", "user-data-2", pointer.Bool(false)))).To(Equal(tag))
+	g.Expect(BulkTag("ns/other-cluster", spec("machine-1", "user-data-1", nil))).NotTo(Equal(tag))
+}
+
+func TestService_ClaimBulkInstance(t *testing.T) {
+	const tag = "capo-bulk-0123456789abcdef"
+
+	bulkServer := func(id, name, status string) ServerExt {
+		return ServerExt{Server: servers.Server{ID: id, Name: name, Status: status}}
+	}
+	instanceSpec := &InstanceSpec{
+		Name:      openStackMachineName,
+		ImageUUID: imageUUID,
+		UserData:  "dXNlci1kYXRh",
+	}
+
+	tests := []struct {
+		name         string
+		expect       func(m *MockClientMockRecorder)
+		wantInstance bool
+		wantBuilding bool
+	}{
+		{
+			name: "rebuilds an active unclaimed server",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListServers(servers.ListOpts{Tags: tag}).Return([]ServerExt{
+					bulkServer("claimed", "other-machine", "ACTIVE"),
+					bulkServer("building", tag, "BUILD"),
+					bulkServer(instanceUUID, tag, "ACTIVE"),
+				}, nil)
+				m.RebuildServer(instanceUUID, RebuildOpts{
+					RebuildOpts: servers.RebuildOpts{ImageRef: imageUUID, Name: openStackMachineName},
+					UserData:    "dXNlci1kYXRh",
+				}).Return(&ServerExt{Server: servers.Server{ID: instanceUUID, Name: openStackMachineName, Status: "REBUILD"}}, nil)
+				m.DeleteServerTag(instanceUUID, tag).Return(nil)
+			},
+			wantInstance: true,
+		},
+		{
+			name: "waits for building servers and deletes failed ones",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListServers(servers.ListOpts{Tags: tag}).Return([]ServerExt{
+					bulkServer("failed", tag, "ERROR"),
+					bulkServer("building", tag, "BUILD"),
+				}, nil)
+				m.DeleteServer("failed").Return(nil)
+			},
+			wantBuilding: true,
+		},
+		{
+			name: "returns nothing without servers",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListServers(servers.ListOpts{Tags: tag}).Return(nil, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := NewMockClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				computeService: mockComputeClient,
+			}
+			instance, building, err := s.ClaimBulkInstance(&infrav1.OpenStackServer{}, instanceSpec, tag)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(instance != nil).To(Equal(tt.wantInstance))
+			g.Expect(building).To(Equal(tt.wantBuilding))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/cache"
+)
+
+const (
+	availabilityZoneResource = "availability_zone"
+	imageResource            = "image"
+	flavorResource           = "flavor"
+)
+
+// cachedClient serves availability zones, images and flavors from the
+// shared response cache. None of them are written by CAPO, so the cache
+// entries are only invalidated by expiry.
+type cachedClient struct {
+	Client
+	cache cache.Scoped
+}
+
+func (c cachedClient) ListAvailabilityZones() ([]availabilityzones.AvailabilityZone, error) {
+	if cached, ok := c.cache.Get(availabilityZoneResource, ""); ok {
+		return append([]availabilityzones.AvailabilityZone(nil), cached.([]availabilityzones.AvailabilityZone)...), nil
+	}
+	azs, err := c.Client.ListAvailabilityZones()
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(availabilityZoneResource, "", azs)
+	return append([]availabilityzones.AvailabilityZone(nil), azs...), nil
+}
+
+func (c cachedClient) ListImages(listOpts images.ListOptsBuilder) ([]images.Image, error) {
+	query, err := listOpts.ToImageListQuery()
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := c.cache.Get(imageResource, query); ok {
+		return append([]images.Image(nil), cached.([]images.Image)...), nil
+	}
+	imageList, err := c.Client.ListImages(listOpts)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(imageResource, query, imageList)
+	return append([]images.Image(nil), imageList...), nil
+}
+
+func (c cachedClient) GetFlavorIDFromName(flavor string) (string, error) {
+	if cached, ok := c.cache.Get(flavorResource, flavor); ok {
+		return cached.(string), nil
+	}
+	flavorID, err := c.Client.GetFlavorIDFromName(flavor)
+	if err != nil {
+		return "", err
+	}
+	c.cache.Set(flavorResource, flavor, flavorID)
+	return flavorID, nil
+}
+
+// generationClient serves images and flavors from the lookups cached for a
+// generation of a resource, so that all instances created from it resolve
+// their image and flavor only once.
+type generationClient struct {
+	Client
+	cache cache.Scoped
+}
+
+func (c generationClient) ListImages(listOpts images.ListOptsBuilder) ([]images.Image, error) {
+	return cachedClient(c).ListImages(listOpts)
+}
+
+func (c generationClient) GetFlavorIDFromName(flavor string) (string, error) {
+	return cachedClient(c).GetFlavorIDFromName(flavor)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/aggregates"
+	computeflavors "github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const (
+	// GPUResourceName is the resource name under which the GPUs of a flavor
+	// are published. It is the name the cluster autoscaler uses for GPUs.
+	GPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+	// vgpuExtraSpec requests virtual GPUs from placement.
+	vgpuExtraSpec = "resources:VGPU"
+	// pciPassthroughExtraSpec requests PCI devices by alias, as a comma
+	// separated list of alias:count.
+	pciPassthroughExtraSpec = "pci_passthrough:alias"
+	// aggregateExtraSpecPrefix prefixes the extra specs which restrict the
+	// flavor to hosts of aggregates with matching metadata.
+	aggregateExtraSpecPrefix = "aggregate_instance_extra_specs:"
+)
+
+// GetFlavorCapacity returns the resource capacity of an instance of the
+// flavor with the given name.
+func (s *Service) GetFlavorCapacity(flavorName string) (corev1.ResourceList, error) {
+	flavor, extraSpecs, err := s.getFlavorWithExtraSpecs(flavorName)
+	if err != nil {
+		return nil, err
+	}
+
+	return flavorCapacity(flavor, extraSpecs), nil
+}
+
+// HasFlavorGPUHosts returns whether a host aggregate provides hosts for the
+// GPU instances of the flavor with the given name in the availability zone,
+// or in any availability zone if it is empty. Only flavors with GPUs which
+// are restricted to host aggregates by their extra specs are checked, all
+// other flavors are reported to have hosts. Host aggregates are only visible
+// to administrators, without them the flavor is reported to have hosts too.
+func (s *Service) HasFlavorGPUHosts(flavorName, availabilityZone string) (bool, error) {
+	flavor, extraSpecs, err := s.getFlavorWithExtraSpecs(flavorName)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := flavorCapacity(flavor, extraSpecs)[GPUResourceName]; !ok {
+		return true, nil
+	}
+	required := flavorAggregateMetadata(extraSpecs)
+	if len(required) == 0 {
+		return true, nil
+	}
+
+	hostAggregates, err := s.computeService.ListAggregates()
+	if err != nil {
+		if code, ok := capoerrors.StatusCode(err); ok && code == http.StatusForbidden {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to list host aggregates: %w", err)
+	}
+	return hasAggregateHosts(hostAggregates, required, availabilityZone), nil
+}
+
+func (s *Service) getFlavorWithExtraSpecs(flavorName string) (*computeflavors.Flavor, map[string]string, error) {
+	flavorID, err := s.computeService.GetFlavorIDFromName(flavorName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting flavor id from flavor name %s: %v", flavorName, err)
+	}
+	flavor, err := s.computeService.GetFlavor(flavorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting flavor %s: %v", flavorName, err)
+	}
+	extraSpecs, err := s.computeService.ListFlavorExtraSpecs(flavorID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting extra specs of flavor %s: %v", flavorName, err)
+	}
+	return flavor, extraSpecs, nil
+}
+
+// flavorCapacity returns the cpu, memory and gpu capacity of flavor. GPUs are
+// counted from the virtual GPUs requested by the flavor and from PCI
+// passthrough aliases whose name contains "gpu".
+func flavorCapacity(flavor *computeflavors.Flavor, extraSpecs map[string]string) corev1.ResourceList {
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewQuantity(int64(flavor.VCPUs), resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(int64(flavor.RAM)*1024*1024, resource.BinarySI),
+	}
+
+	gpus := 0
+	if count, err := strconv.Atoi(extraSpecs[vgpuExtraSpec]); err == nil {
+		gpus += count
+	}
+	if aliases, ok := extraSpecs[pciPassthroughExtraSpec]; ok {
+		for _, alias := range strings.Split(aliases, ",") {
+			// An alias without a count requests a single device
+			nameCount := strings.SplitN(strings.TrimSpace(alias), ":", 2)
+			if !strings.Contains(strings.ToLower(nameCount[0]), "gpu") {
+				continue
+			}
+			if len(nameCount) == 1 {
+				gpus++
+			} else if n, err := strconv.Atoi(nameCount[1]); err == nil {
+				gpus += n
+			}
+		}
+	}
+	if gpus > 0 {
+		capacity[GPUResourceName] = *resource.NewQuantity(int64(gpus), resource.DecimalSI)
+	}
+
+	return capacity
+}
+
+// flavorAggregateMetadata returns the aggregate metadata required by the
+// extra specs of a flavor. Values with an operator, e.g. "<in> a100", cannot
+// be compared and are skipped.
+func flavorAggregateMetadata(extraSpecs map[string]string) map[string]string {
+	metadata := map[string]string{}
+	for key, value := range extraSpecs {
+		if !strings.HasPrefix(key, aggregateExtraSpecPrefix) || strings.HasPrefix(value, "<") {
+			continue
+		}
+		metadata[strings.TrimPrefix(key, aggregateExtraSpecPrefix)] = value
+	}
+	return metadata
+}
+
+// hasAggregateHosts returns whether a host in the availability zone carries
+// the required metadata. The metadata of a host is the union of the metadata
+// of all aggregates it belongs to, as evaluated by the Nova scheduler.
+func hasAggregateHosts(hostAggregates []aggregates.Aggregate, required map[string]string, availabilityZone string) bool {
+	hostMetadata := map[string]map[string]sets.String{}
+	hostZones := map[string]sets.String{}
+	for _, aggregate := range hostAggregates {
+		for _, host := range aggregate.Hosts {
+			if hostMetadata[host] == nil {
+				hostMetadata[host] = map[string]sets.String{}
+				hostZones[host] = sets.NewString()
+			}
+			for key, value := range aggregate.Metadata {
+				if hostMetadata[host][key] == nil {
+					hostMetadata[host][key] = sets.NewString()
+				}
+				hostMetadata[host][key].Insert(value)
+			}
+			if aggregate.AvailabilityZone != "" {
+				hostZones[host].Insert(aggregate.AvailabilityZone)
+			}
+		}
+	}
+
+	for host, metadata := range hostMetadata {
+		if availabilityZone != "" && !hostZones[host].Has(availabilityZone) {
+			continue
+		}
+		matches := true
+		for key, value := range required {
+			if !metadata[key].Has(value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
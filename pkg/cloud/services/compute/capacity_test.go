@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/aggregates"
+	computeflavors "github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_flavorCapacity(t *testing.T) {
+	flavor := &computeflavors.Flavor{VCPUs: 4, RAM: 8192}
+
+	tests := []struct {
+		name       string
+		extraSpecs map[string]string
+		want       corev1.ResourceList
+	}{
+		{
+			name: "Flavor without GPUs",
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name:       "Flavor with virtual GPUs",
+			extraSpecs: map[string]string{"resources:VGPU": "2"},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				GPUResourceName:       resource.MustParse("2"),
+			},
+		},
+		{
+			name:       "Flavor with PCI passthrough GPUs",
+			extraSpecs: map[string]string{"pci_passthrough:alias": "a100-gpu:2, nic:1,t4-GPU"},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				GPUResourceName:       resource.MustParse("3"),
+			},
+		},
+		{
+			name:       "Flavor with PCI passthrough devices which are not GPUs",
+			extraSpecs: map[string]string{"pci_passthrough:alias": "nic:2"},
+			want: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := flavorCapacity(flavor, tt.extraSpecs)
+			g.Expect(got).To(HaveLen(len(tt.want)))
+			for name, quantity := range tt.want {
+				g.Expect(got).To(HaveKey(name))
+				gotQuantity := got[name]
+				g.Expect(gotQuantity.Cmp(quantity)).To(Equal(0), "resource %s: got %s, want %s", name, gotQuantity.String(), quantity.String())
+			}
+		})
+	}
+}
+
+func Test_HasFlavorGPUHosts(t *testing.T) {
+	gpuAggregate := aggregates.Aggregate{Name: "gpu", Hosts: []string{"host-1"}, Metadata: map[string]string{"gpu": "a100"}}
+	zoneAggregate := aggregates.Aggregate{Name: "az1", AvailabilityZone: "az1", Hosts: []string{"host-1", "host-2"}}
+
+	tests := []struct {
+		name             string
+		extraSpecs       map[string]string
+		availabilityZone string
+		expect           func(m *MockClientMockRecorder)
+		want             bool
+		wantErr          bool
+	}{
+		{
+			name:       "Flavor without GPUs",
+			extraSpecs: map[string]string{"aggregate_instance_extra_specs:gpu": "a100"},
+			want:       true,
+		},
+		{
+			name:       "GPU flavor not restricted to aggregates",
+			extraSpecs: map[string]string{"resources:VGPU": "1"},
+			want:       true,
+		},
+		{
+			name:             "Aggregate provides hosts in the availability zone",
+			extraSpecs:       map[string]string{"resources:VGPU": "1", "aggregate_instance_extra_specs:gpu": "a100"},
+			availabilityZone: "az1",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListAggregates().Return([]aggregates.Aggregate{gpuAggregate, zoneAggregate}, nil)
+			},
+			want: true,
+		},
+		{
+			name:             "Aggregate provides no hosts in the availability zone",
+			extraSpecs:       map[string]string{"resources:VGPU": "1", "aggregate_instance_extra_specs:gpu": "a100"},
+			availabilityZone: "az2",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListAggregates().Return([]aggregates.Aggregate{gpuAggregate, zoneAggregate}, nil)
+			},
+			want: false,
+		},
+		{
+			name:       "No aggregate with matching metadata",
+			extraSpecs: map[string]string{"pci_passthrough:alias": "gpu:1", "aggregate_instance_extra_specs:gpu": "t4"},
+			expect: func(m *MockClientMockRecorder) {
+				m.ListAggregates().Return([]aggregates.Aggregate{gpuAggregate, zoneAggregate}, nil)
+			},
+			want: false,
+		},
+		{
+			name:       "Aggregates are not visible",
+			extraSpecs: map[string]string{"resources:VGPU": "1", "aggregate_instance_extra_specs:gpu": "t4"},
+			expect: func(m *MockClientMockRecorder) {
+				m.ListAggregates().Return(nil, gophercloud.ErrDefault403{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 403}})
+			},
+			want: true,
+		},
+		{
+			name:       "Listing aggregates fails",
+			extraSpecs: map[string]string{"resources:VGPU": "1", "aggregate_instance_extra_specs:gpu": "t4"},
+			expect: func(m *MockClientMockRecorder) {
+				m.ListAggregates().Return(nil, gophercloud.ErrDefault500{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 500}})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockComputeClient := NewMockClient(mockCtrl)
+			mockComputeClient.EXPECT().GetFlavorIDFromName("gpu-flavor").Return("flavor-id", nil)
+			mockComputeClient.EXPECT().GetFlavor("flavor-id").Return(&computeflavors.Flavor{VCPUs: 4, RAM: 8192}, nil)
+			mockComputeClient.EXPECT().ListFlavorExtraSpecs("flavor-id").Return(tt.extraSpecs, nil)
+			if tt.expect != nil {
+				tt.expect(mockComputeClient.EXPECT())
+			}
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				computeService: mockComputeClient,
+			}
+			got, err := s.HasFlavorGPUHosts("gpu-flavor", tt.availabilityZone)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -18,11 +18,23 @@ package compute
 
 import (
 	"github.com/gophercloud/gophercloud"
+	volumequotasets "github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/quotasets"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/volumeactions"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/aggregates"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedserverattributes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/quotasets"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/remoteconsoles"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	computeservices "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/services"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/tags"
+	computeflavors "github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/pagination"
 	"github.com/gophercloud/utils/openstack/compute/v2/flavors"
 
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
@@ -32,9 +44,11 @@ import (
 //go:generate /usr/bin/env bash -c "cat ../../../../hack/boilerplate/boilerplate.generatego.txt client_mock.go > _client_mock.go && mv _client_mock.go client_mock.go"
 
 // ServerExt is the base gophercloud Server with extensions used by InstanceStatus.
+// The extended server attributes are only returned to administrators.
 type ServerExt struct {
 	servers.Server
 	availabilityzones.ServerAvailabilityZoneExt
+	extendedserverattributes.ServerAttributesExt
 }
 
 type Client interface {
@@ -43,18 +57,67 @@ type Client interface {
 	ListImages(listOpts images.ListOptsBuilder) ([]images.Image, error)
 
 	GetFlavorIDFromName(flavor string) (string, error)
+	GetFlavor(flavorID string) (*computeflavors.Flavor, error)
+	ListFlavorExtraSpecs(flavorID string) (map[string]string, error)
 	CreateServer(createOpts servers.CreateOptsBuilder) (*ServerExt, error)
 	DeleteServer(serverID string) error
 	GetServer(serverID string) (*ServerExt, error)
 	ListServers(listOpts servers.ListOptsBuilder) ([]ServerExt, error)
+	EachServerPage(listOpts servers.ListOptsBuilder, handler func([]ServerExt) (bool, error)) error
+	RebootServer(serverID string, opts servers.RebootOptsBuilder) error
+	RebuildServer(serverID string, opts servers.RebuildOptsBuilder) (*ServerExt, error)
+	DeleteServerTag(serverID, tag string) error
+	ShowConsoleOutput(serverID string, opts servers.ShowConsoleOutputOptsBuilder) (string, error)
+	CreateRemoteConsole(serverID string, opts remoteconsoles.CreateOptsBuilder) (*remoteconsoles.RemoteConsole, error)
+	ListInstanceActions(serverID string) ([]instanceactions.InstanceAction, error)
+
+	ListComputeServices(listOpts computeservices.ListOptsBuilder) ([]computeservices.Service, error)
+	ListAggregates() ([]aggregates.Aggregate, error)
+
+	ListServerGroups() ([]servergroups.ServerGroup, error)
+	CreateServerGroup(opts servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error)
+	DeleteServerGroup(serverGroupID string) error
 
 	ListAttachedInterfaces(serverID string) ([]attachinterfaces.Interface, error)
+	CreateAttachedInterface(serverID string, opts attachinterfaces.CreateOptsBuilder) (*attachinterfaces.Interface, error)
 	DeleteAttachedInterface(serverID, portID string) error
 
 	ListVolumes(opts volumes.ListOptsBuilder) ([]volumes.Volume, error)
 	CreateVolume(opts volumes.CreateOptsBuilder) (*volumes.Volume, error)
 	DeleteVolume(volumeID string, opts volumes.DeleteOptsBuilder) error
 	GetVolume(volumeID string) (*volumes.Volume, error)
+	ExtendVolume(volumeID string, newSize int) error
+
+	GetQuotaSet(projectID string) (*quotasets.QuotaSet, error)
+	GetQuotaDetailSet(projectID string) (*quotasets.QuotaDetailSet, error)
+	GetVolumeQuotaUsageSet(projectID string) (*volumequotasets.QuotaUsageSet, error)
+}
+
+// NovaRebuildUserDataMicroversion is the Nova microversion which allows
+// replacing the user data of a server when it is rebuilt.
+const NovaRebuildUserDataMicroversion = "2.57"
+
+// CinderExtendInUseMicroversion is the Cinder microversion which allows
+// extending volumes attached to a server.
+const CinderExtendInUseMicroversion = "3.42"
+
+// RebuildOpts are the options to rebuild a server, extended by the user data
+// which can be replaced since NovaRebuildUserDataMicroversion.
+type RebuildOpts struct {
+	servers.RebuildOpts
+	// UserData is the base64 encoded user data of the rebuilt server.
+	UserData string
+}
+
+func (opts RebuildOpts) ToServerRebuildMap() (map[string]interface{}, error) {
+	b, err := opts.RebuildOpts.ToServerRebuildMap()
+	if err != nil {
+		return nil, err
+	}
+	if opts.UserData != "" {
+		b["rebuild"].(map[string]interface{})["user_data"] = opts.UserData
+	}
+	return b, nil
 }
 
 type serviceClient struct {
@@ -72,13 +135,29 @@ func (s serviceClient) ListAvailabilityZones() ([]availabilityzones.Availability
 	return availabilityzones.ExtractAvailabilityZones(allPages)
 }
 
+// ListImages returns the images matching listOpts. If listOpts is an
+// images.ListOpts with a Limit, no further pages are read once Limit images
+// were returned, so that lookups do not read all images of large clouds.
 func (s serviceClient) ListImages(listOpts images.ListOptsBuilder) ([]images.Image, error) {
+	var maxImages int
+	if opts, ok := listOpts.(images.ListOpts); ok {
+		maxImages = opts.Limit
+	}
+
+	var imageList []images.Image
 	mc := metrics.NewMetricPrometheusContext("image", "list")
-	pages, err := images.List(s.images, listOpts).AllPages()
+	err := images.List(s.images, listOpts).EachPage(func(page pagination.Page) (bool, error) {
+		pageImages, err := images.ExtractImages(page)
+		if err != nil {
+			return false, err
+		}
+		imageList = append(imageList, pageImages...)
+		return maxImages == 0 || len(imageList) < maxImages, nil
+	})
 	if mc.ObserveRequest(err) != nil {
 		return nil, err
 	}
-	return images.ExtractImages(pages)
+	return imageList, nil
 }
 
 func (s serviceClient) GetFlavorIDFromName(flavor string) (string, error) {
@@ -87,6 +166,24 @@ func (s serviceClient) GetFlavorIDFromName(flavor string) (string, error) {
 	return flavorID, mc.ObserveRequest(err)
 }
 
+func (s serviceClient) GetFlavor(flavorID string) (*computeflavors.Flavor, error) {
+	mc := metrics.NewMetricPrometheusContext("flavor", "get")
+	flavor, err := computeflavors.Get(s.compute, flavorID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return flavor, nil
+}
+
+func (s serviceClient) ListFlavorExtraSpecs(flavorID string) (map[string]string, error) {
+	mc := metrics.NewMetricPrometheusContext("flavor_extra_specs", "list")
+	extraSpecs, err := computeflavors.ListExtraSpecs(s.compute, flavorID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return extraSpecs, nil
+}
+
 func (s serviceClient) CreateServer(createOpts servers.CreateOptsBuilder) (*ServerExt, error) {
 	var server ServerExt
 	mc := metrics.NewMetricPrometheusContext("server", "create")
@@ -124,6 +221,116 @@ func (s serviceClient) ListServers(listOpts servers.ListOptsBuilder) ([]ServerEx
 	return serverList, err
 }
 
+// EachServerPage calls handler with the servers of each page matching
+// listOpts, until handler returns false or an error.
+func (s serviceClient) EachServerPage(listOpts servers.ListOptsBuilder, handler func([]ServerExt) (bool, error)) error {
+	mc := metrics.NewMetricPrometheusContext("server", "list")
+	err := servers.List(s.compute, listOpts).EachPage(func(page pagination.Page) (bool, error) {
+		var serverList []ServerExt
+		if err := servers.ExtractServersInto(page, &serverList); err != nil {
+			return false, err
+		}
+		return handler(serverList)
+	})
+	return mc.ObserveRequest(err)
+}
+
+func (s serviceClient) RebootServer(serverID string, opts servers.RebootOptsBuilder) error {
+	mc := metrics.NewMetricPrometheusContext("server", "reboot")
+	err := servers.Reboot(s.compute, serverID, opts).ExtractErr()
+	return mc.ObserveRequest(err)
+}
+
+func (s serviceClient) RebuildServer(serverID string, opts servers.RebuildOptsBuilder) (*ServerExt, error) {
+	compute := *s.compute
+	if _, ok := opts.(RebuildOpts); ok {
+		compute.Microversion = NovaRebuildUserDataMicroversion
+	}
+
+	var server ServerExt
+	mc := metrics.NewMetricPrometheusContext("server", "rebuild")
+	err := servers.Rebuild(&compute, serverID, opts).ExtractInto(&server)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (s serviceClient) DeleteServerTag(serverID, tag string) error {
+	mc := metrics.NewMetricPrometheusContext("server_tag", "delete")
+	err := tags.Delete(s.compute, serverID, tag).ExtractErr()
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
+
+func (s serviceClient) ShowConsoleOutput(serverID string, opts servers.ShowConsoleOutputOptsBuilder) (string, error) {
+	mc := metrics.NewMetricPrometheusContext("server", "console_output")
+	output, err := servers.ShowConsoleOutput(s.compute, serverID, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func (s serviceClient) CreateRemoteConsole(serverID string, opts remoteconsoles.CreateOptsBuilder) (*remoteconsoles.RemoteConsole, error) {
+	mc := metrics.NewMetricPrometheusContext("server_remote_console", "create")
+	console, err := remoteconsoles.Create(s.compute, serverID, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return console, nil
+}
+
+func (s serviceClient) ListInstanceActions(serverID string) ([]instanceactions.InstanceAction, error) {
+	mc := metrics.NewMetricPrometheusContext("instance_action", "list")
+	allPages, err := instanceactions.List(s.compute, serverID, nil).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return instanceactions.ExtractInstanceActions(allPages)
+}
+
+func (s serviceClient) ListComputeServices(listOpts computeservices.ListOptsBuilder) ([]computeservices.Service, error) {
+	mc := metrics.NewMetricPrometheusContext("compute_service", "list")
+	allPages, err := computeservices.List(s.compute, listOpts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return computeservices.ExtractServices(allPages)
+}
+
+func (s serviceClient) ListAggregates() ([]aggregates.Aggregate, error) {
+	mc := metrics.NewMetricPrometheusContext("aggregate", "list")
+	allPages, err := aggregates.List(s.compute).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return aggregates.ExtractAggregates(allPages)
+}
+
+func (s serviceClient) ListServerGroups() ([]servergroups.ServerGroup, error) {
+	mc := metrics.NewMetricPrometheusContext("server_group", "list")
+	allPages, err := servergroups.List(s.compute, servergroups.ListOpts{}).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return servergroups.ExtractServerGroups(allPages)
+}
+
+func (s serviceClient) CreateServerGroup(opts servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error) {
+	mc := metrics.NewMetricPrometheusContext("server_group", "create")
+	serverGroup, err := servergroups.Create(s.compute, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return serverGroup, nil
+}
+
+func (s serviceClient) DeleteServerGroup(serverGroupID string) error {
+	mc := metrics.NewMetricPrometheusContext("server_group", "delete")
+	err := servergroups.Delete(s.compute, serverGroupID).ExtractErr()
+	return mc.ObserveRequestIgnoreNotFound(err)
+}
+
 func (s serviceClient) ListAttachedInterfaces(serverID string) ([]attachinterfaces.Interface, error) {
 	mc := metrics.NewMetricPrometheusContext("server_os_interface", "list")
 	interfaces, err := attachinterfaces.List(s.compute, serverID).AllPages()
@@ -133,6 +340,15 @@ func (s serviceClient) ListAttachedInterfaces(serverID string) ([]attachinterfac
 	return attachinterfaces.ExtractInterfaces(interfaces)
 }
 
+func (s serviceClient) CreateAttachedInterface(serverID string, opts attachinterfaces.CreateOptsBuilder) (*attachinterfaces.Interface, error) {
+	mc := metrics.NewMetricPrometheusContext("server_os_interface", "create")
+	iface, err := attachinterfaces.Create(s.compute, serverID, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return iface, nil
+}
+
 func (s serviceClient) DeleteAttachedInterface(serverID, portID string) error {
 	mc := metrics.NewMetricPrometheusContext("server_os_interface", "delete")
 	err := attachinterfaces.Delete(s.compute, serverID, portID).ExtractErr()
@@ -165,3 +381,39 @@ func (s serviceClient) GetVolume(volumeID string) (*volumes.Volume, error) {
 	volume, err := volumes.Get(s.volume, volumeID).Extract()
 	return volume, mc.ObserveRequestIgnoreNotFound(err)
 }
+
+func (s serviceClient) ExtendVolume(volumeID string, newSize int) error {
+	volume := *s.volume
+	volume.Microversion = CinderExtendInUseMicroversion
+
+	mc := metrics.NewMetricPrometheusContext("volume", "extend")
+	err := volumeactions.ExtendSize(&volume, volumeID, volumeactions.ExtendSizeOpts{NewSize: newSize}).ExtractErr()
+	return mc.ObserveRequest(err)
+}
+
+func (s serviceClient) GetQuotaSet(projectID string) (*quotasets.QuotaSet, error) {
+	mc := metrics.NewMetricPrometheusContext("quota_set", "get")
+	quotaSet, err := quotasets.Get(s.compute, projectID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return quotaSet, nil
+}
+
+func (s serviceClient) GetQuotaDetailSet(projectID string) (*quotasets.QuotaDetailSet, error) {
+	mc := metrics.NewMetricPrometheusContext("quota_set_detail", "get")
+	quotaDetailSet, err := quotasets.GetDetail(s.compute, projectID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return &quotaDetailSet, nil
+}
+
+func (s serviceClient) GetVolumeQuotaUsageSet(projectID string) (*volumequotasets.QuotaUsageSet, error) {
+	mc := metrics.NewMetricPrometheusContext("volume_quota_usage", "get")
+	quotaUsageSet, err := volumequotasets.GetUsage(s.volume, projectID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return &quotaUsageSet, nil
+}
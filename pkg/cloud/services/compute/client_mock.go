@@ -24,9 +24,17 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	quotasets "github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/quotasets"
 	volumes "github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	aggregates "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/aggregates"
 	attachinterfaces "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
 	availabilityzones "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/availabilityzones"
+	instanceactions "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	quotasets0 "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/quotasets"
+	remoteconsoles "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/remoteconsoles"
+	servergroups "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	services "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/services"
+	flavors "github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
 	servers "github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	images "github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 )
@@ -54,6 +62,36 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CreateAttachedInterface mocks base method.
+func (m *MockClient) CreateAttachedInterface(arg0 string, arg1 attachinterfaces.CreateOptsBuilder) (*attachinterfaces.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachedInterface", arg0, arg1)
+	ret0, _ := ret[0].(*attachinterfaces.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAttachedInterface indicates an expected call of CreateAttachedInterface.
+func (mr *MockClientMockRecorder) CreateAttachedInterface(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachedInterface", reflect.TypeOf((*MockClient)(nil).CreateAttachedInterface), arg0, arg1)
+}
+
+// CreateRemoteConsole mocks base method.
+func (m *MockClient) CreateRemoteConsole(arg0 string, arg1 remoteconsoles.CreateOptsBuilder) (*remoteconsoles.RemoteConsole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRemoteConsole", arg0, arg1)
+	ret0, _ := ret[0].(*remoteconsoles.RemoteConsole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRemoteConsole indicates an expected call of CreateRemoteConsole.
+func (mr *MockClientMockRecorder) CreateRemoteConsole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteConsole", reflect.TypeOf((*MockClient)(nil).CreateRemoteConsole), arg0, arg1)
+}
+
 // CreateServer mocks base method.
 func (m *MockClient) CreateServer(arg0 servers.CreateOptsBuilder) (*ServerExt, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +107,21 @@ func (mr *MockClientMockRecorder) CreateServer(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServer", reflect.TypeOf((*MockClient)(nil).CreateServer), arg0)
 }
 
+// CreateServerGroup mocks base method.
+func (m *MockClient) CreateServerGroup(arg0 servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServerGroup", arg0)
+	ret0, _ := ret[0].(*servergroups.ServerGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServerGroup indicates an expected call of CreateServerGroup.
+func (mr *MockClientMockRecorder) CreateServerGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServerGroup", reflect.TypeOf((*MockClient)(nil).CreateServerGroup), arg0)
+}
+
 // CreateVolume mocks base method.
 func (m *MockClient) CreateVolume(arg0 volumes.CreateOptsBuilder) (*volumes.Volume, error) {
 	m.ctrl.T.Helper()
@@ -112,6 +165,34 @@ func (mr *MockClientMockRecorder) DeleteServer(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServer", reflect.TypeOf((*MockClient)(nil).DeleteServer), arg0)
 }
 
+// DeleteServerGroup mocks base method.
+func (m *MockClient) DeleteServerGroup(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServerGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServerGroup indicates an expected call of DeleteServerGroup.
+func (mr *MockClientMockRecorder) DeleteServerGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServerGroup", reflect.TypeOf((*MockClient)(nil).DeleteServerGroup), arg0)
+}
+
+// DeleteServerTag mocks base method.
+func (m *MockClient) DeleteServerTag(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServerTag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServerTag indicates an expected call of DeleteServerTag.
+func (mr *MockClientMockRecorder) DeleteServerTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServerTag", reflect.TypeOf((*MockClient)(nil).DeleteServerTag), arg0, arg1)
+}
+
 // DeleteVolume mocks base method.
 func (m *MockClient) DeleteVolume(arg0 string, arg1 volumes.DeleteOptsBuilder) error {
 	m.ctrl.T.Helper()
@@ -126,6 +207,49 @@ func (mr *MockClientMockRecorder) DeleteVolume(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockClient)(nil).DeleteVolume), arg0, arg1)
 }
 
+// EachServerPage mocks base method.
+func (m *MockClient) EachServerPage(arg0 servers.ListOptsBuilder, arg1 func([]ServerExt) (bool, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachServerPage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachServerPage indicates an expected call of EachServerPage.
+func (mr *MockClientMockRecorder) EachServerPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachServerPage", reflect.TypeOf((*MockClient)(nil).EachServerPage), arg0, arg1)
+}
+
+// ExtendVolume mocks base method.
+func (m *MockClient) ExtendVolume(arg0 string, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendVolume", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendVolume indicates an expected call of ExtendVolume.
+func (mr *MockClientMockRecorder) ExtendVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendVolume", reflect.TypeOf((*MockClient)(nil).ExtendVolume), arg0, arg1)
+}
+
+// GetFlavor mocks base method.
+func (m *MockClient) GetFlavor(arg0 string) (*flavors.Flavor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFlavor", arg0)
+	ret0, _ := ret[0].(*flavors.Flavor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFlavor indicates an expected call of GetFlavor.
+func (mr *MockClientMockRecorder) GetFlavor(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlavor", reflect.TypeOf((*MockClient)(nil).GetFlavor), arg0)
+}
+
 // GetFlavorIDFromName mocks base method.
 func (m *MockClient) GetFlavorIDFromName(arg0 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -141,6 +265,36 @@ func (mr *MockClientMockRecorder) GetFlavorIDFromName(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlavorIDFromName", reflect.TypeOf((*MockClient)(nil).GetFlavorIDFromName), arg0)
 }
 
+// GetQuotaDetailSet mocks base method.
+func (m *MockClient) GetQuotaDetailSet(arg0 string) (*quotasets0.QuotaDetailSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaDetailSet", arg0)
+	ret0, _ := ret[0].(*quotasets0.QuotaDetailSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaDetailSet indicates an expected call of GetQuotaDetailSet.
+func (mr *MockClientMockRecorder) GetQuotaDetailSet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaDetailSet", reflect.TypeOf((*MockClient)(nil).GetQuotaDetailSet), arg0)
+}
+
+// GetQuotaSet mocks base method.
+func (m *MockClient) GetQuotaSet(arg0 string) (*quotasets0.QuotaSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaSet", arg0)
+	ret0, _ := ret[0].(*quotasets0.QuotaSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaSet indicates an expected call of GetQuotaSet.
+func (mr *MockClientMockRecorder) GetQuotaSet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaSet", reflect.TypeOf((*MockClient)(nil).GetQuotaSet), arg0)
+}
+
 // GetServer mocks base method.
 func (m *MockClient) GetServer(arg0 string) (*ServerExt, error) {
 	m.ctrl.T.Helper()
@@ -171,6 +325,36 @@ func (mr *MockClientMockRecorder) GetVolume(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolume", reflect.TypeOf((*MockClient)(nil).GetVolume), arg0)
 }
 
+// GetVolumeQuotaUsageSet mocks base method.
+func (m *MockClient) GetVolumeQuotaUsageSet(arg0 string) (*quotasets.QuotaUsageSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeQuotaUsageSet", arg0)
+	ret0, _ := ret[0].(*quotasets.QuotaUsageSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeQuotaUsageSet indicates an expected call of GetVolumeQuotaUsageSet.
+func (mr *MockClientMockRecorder) GetVolumeQuotaUsageSet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeQuotaUsageSet", reflect.TypeOf((*MockClient)(nil).GetVolumeQuotaUsageSet), arg0)
+}
+
+// ListAggregates mocks base method.
+func (m *MockClient) ListAggregates() ([]aggregates.Aggregate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAggregates")
+	ret0, _ := ret[0].([]aggregates.Aggregate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAggregates indicates an expected call of ListAggregates.
+func (mr *MockClientMockRecorder) ListAggregates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAggregates", reflect.TypeOf((*MockClient)(nil).ListAggregates))
+}
+
 // ListAttachedInterfaces mocks base method.
 func (m *MockClient) ListAttachedInterfaces(arg0 string) ([]attachinterfaces.Interface, error) {
 	m.ctrl.T.Helper()
@@ -201,6 +385,36 @@ func (mr *MockClientMockRecorder) ListAvailabilityZones() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockClient)(nil).ListAvailabilityZones))
 }
 
+// ListComputeServices mocks base method.
+func (m *MockClient) ListComputeServices(arg0 services.ListOptsBuilder) ([]services.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComputeServices", arg0)
+	ret0, _ := ret[0].([]services.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComputeServices indicates an expected call of ListComputeServices.
+func (mr *MockClientMockRecorder) ListComputeServices(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComputeServices", reflect.TypeOf((*MockClient)(nil).ListComputeServices), arg0)
+}
+
+// ListFlavorExtraSpecs mocks base method.
+func (m *MockClient) ListFlavorExtraSpecs(arg0 string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFlavorExtraSpecs", arg0)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFlavorExtraSpecs indicates an expected call of ListFlavorExtraSpecs.
+func (mr *MockClientMockRecorder) ListFlavorExtraSpecs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFlavorExtraSpecs", reflect.TypeOf((*MockClient)(nil).ListFlavorExtraSpecs), arg0)
+}
+
 // ListImages mocks base method.
 func (m *MockClient) ListImages(arg0 images.ListOptsBuilder) ([]images.Image, error) {
 	m.ctrl.T.Helper()
@@ -216,6 +430,36 @@ func (mr *MockClientMockRecorder) ListImages(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImages", reflect.TypeOf((*MockClient)(nil).ListImages), arg0)
 }
 
+// ListInstanceActions mocks base method.
+func (m *MockClient) ListInstanceActions(arg0 string) ([]instanceactions.InstanceAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstanceActions", arg0)
+	ret0, _ := ret[0].([]instanceactions.InstanceAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstanceActions indicates an expected call of ListInstanceActions.
+func (mr *MockClientMockRecorder) ListInstanceActions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstanceActions", reflect.TypeOf((*MockClient)(nil).ListInstanceActions), arg0)
+}
+
+// ListServerGroups mocks base method.
+func (m *MockClient) ListServerGroups() ([]servergroups.ServerGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServerGroups")
+	ret0, _ := ret[0].([]servergroups.ServerGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServerGroups indicates an expected call of ListServerGroups.
+func (mr *MockClientMockRecorder) ListServerGroups() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServerGroups", reflect.TypeOf((*MockClient)(nil).ListServerGroups))
+}
+
 // ListServers mocks base method.
 func (m *MockClient) ListServers(arg0 servers.ListOptsBuilder) ([]ServerExt, error) {
 	m.ctrl.T.Helper()
@@ -245,3 +489,47 @@ func (mr *MockClientMockRecorder) ListVolumes(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumes", reflect.TypeOf((*MockClient)(nil).ListVolumes), arg0)
 }
+
+// RebootServer mocks base method.
+func (m *MockClient) RebootServer(arg0 string, arg1 servers.RebootOptsBuilder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebootServer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebootServer indicates an expected call of RebootServer.
+func (mr *MockClientMockRecorder) RebootServer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebootServer", reflect.TypeOf((*MockClient)(nil).RebootServer), arg0, arg1)
+}
+
+// RebuildServer mocks base method.
+func (m *MockClient) RebuildServer(arg0 string, arg1 servers.RebuildOptsBuilder) (*ServerExt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildServer", arg0, arg1)
+	ret0, _ := ret[0].(*ServerExt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildServer indicates an expected call of RebuildServer.
+func (mr *MockClientMockRecorder) RebuildServer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildServer", reflect.TypeOf((*MockClient)(nil).RebuildServer), arg0, arg1)
+}
+
+// ShowConsoleOutput mocks base method.
+func (m *MockClient) ShowConsoleOutput(arg0 string, arg1 servers.ShowConsoleOutputOptsBuilder) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowConsoleOutput", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowConsoleOutput indicates an expected call of ShowConsoleOutput.
+func (mr *MockClientMockRecorder) ShowConsoleOutput(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowConsoleOutput", reflect.TypeOf((*MockClient)(nil).ShowConsoleOutput), arg0, arg1)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+const (
+	cpuPolicyExtraSpec   = "hw:cpu_policy"
+	memPageSizeExtraSpec = "hw:mem_page_size"
+	numaNodesExtraSpec   = "hw:numa_nodes"
+
+	// cpuPolicyShared is the CPU policy of flavors which do not set one.
+	cpuPolicyShared = "shared"
+)
+
+// GetUnmetFlavorRequirements returns the requirements which the flavor with
+// the given name does not meet, each described by a message.
+func (s *Service) GetUnmetFlavorRequirements(flavorName string, requirements *infrav1.FlavorRequirements) ([]string, error) {
+	if requirements == nil {
+		return nil, nil
+	}
+
+	_, extraSpecs, err := s.getFlavorWithExtraSpecs(flavorName)
+	if err != nil {
+		return nil, err
+	}
+	return unmetFlavorRequirements(extraSpecs, requirements), nil
+}
+
+func unmetFlavorRequirements(extraSpecs map[string]string, requirements *infrav1.FlavorRequirements) []string {
+	var unmet []string
+
+	if requirements.CPUPolicy != "" {
+		cpuPolicy := extraSpecs[cpuPolicyExtraSpec]
+		if cpuPolicy == "" {
+			cpuPolicy = cpuPolicyShared
+		}
+		if cpuPolicy != requirements.CPUPolicy {
+			unmet = append(unmet, fmt.Sprintf("%s is %s, required %s", cpuPolicyExtraSpec, cpuPolicy, requirements.CPUPolicy))
+		}
+	}
+
+	if requirements.MemPageSize != "" {
+		memPageSize, ok := extraSpecs[memPageSizeExtraSpec]
+		if !ok {
+			unmet = append(unmet, fmt.Sprintf("%s is not set, required %s", memPageSizeExtraSpec, requirements.MemPageSize))
+		} else if !strings.EqualFold(memPageSize, requirements.MemPageSize) {
+			unmet = append(unmet, fmt.Sprintf("%s is %s, required %s", memPageSizeExtraSpec, memPageSize, requirements.MemPageSize))
+		}
+	}
+
+	if requirements.NUMANodes > 0 {
+		numaNodes, ok := extraSpecs[numaNodesExtraSpec]
+		if !ok {
+			unmet = append(unmet, fmt.Sprintf("%s is not set, required %d", numaNodesExtraSpec, requirements.NUMANodes))
+		} else if n, err := strconv.Atoi(numaNodes); err != nil || n != requirements.NUMANodes {
+			unmet = append(unmet, fmt.Sprintf("%s is %s, required %d", numaNodesExtraSpec, numaNodes, requirements.NUMANodes))
+		}
+	}
+
+	return unmet
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_unmetFlavorRequirements(t *testing.T) {
+	pinned := map[string]string{
+		"hw:cpu_policy":    "dedicated",
+		"hw:mem_page_size": "1GB",
+		"hw:numa_nodes":    "2",
+	}
+
+	tests := []struct {
+		name         string
+		extraSpecs   map[string]string
+		requirements infrav1.FlavorRequirements
+		want         []string
+	}{
+		{
+			name:         "All requirements met",
+			extraSpecs:   pinned,
+			requirements: infrav1.FlavorRequirements{CPUPolicy: "dedicated", MemPageSize: "1gb", NUMANodes: 2},
+		},
+		{
+			name:         "Shared CPU policy is the default",
+			requirements: infrav1.FlavorRequirements{CPUPolicy: "shared"},
+		},
+		{
+			name:         "Extra specs missing",
+			requirements: infrav1.FlavorRequirements{CPUPolicy: "dedicated", MemPageSize: "large", NUMANodes: 1},
+			want: []string{
+				"hw:cpu_policy is shared, required dedicated",
+				"hw:mem_page_size is not set, required large",
+				"hw:numa_nodes is not set, required 1",
+			},
+		},
+		{
+			name:         "Extra specs differ",
+			extraSpecs:   pinned,
+			requirements: infrav1.FlavorRequirements{CPUPolicy: "mixed", MemPageSize: "2MB", NUMANodes: 1},
+			want: []string{
+				"hw:cpu_policy is dedicated, required mixed",
+				"hw:mem_page_size is 1GB, required 2MB",
+				"hw:numa_nodes is 2, required 1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(unmetFlavorRequirements(tt.extraSpecs, &tt.requirements)).To(Equal(tt.want))
+		})
+	}
+}
@@ -17,35 +17,53 @@ limitations under the License.
 package compute
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/remoteconsoles"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/schedulerhints"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/cluster-api/util"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/hash"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
 const (
 	retryIntervalInstanceStatus = 10 * time.Second
 	timeoutInstanceCreate       = 5
 	timeoutInstanceDelete       = 5 * time.Minute
+
+	// serverPageSize is the number of servers read per request when servers
+	// are processed page by page.
+	serverPageSize = 500
 )
 
+// ErrDeletionInProgress is returned by DeleteInstance while resources of the
+// deleted server, e.g. its root volume, are still being deleted. The deletion
+// is to be retried later.
+var ErrDeletionInProgress = errors.New("deletion in progress")
+
 // constructNetworks builds an array of networks from the network, subnet and ports items in the instance spec.
 // If no networks or ports are in the spec, returns a single network item for a network connection to the default cluster network.
+// openStackCluster is nil for servers which are not part of a cluster, which have no default network.
 func (s *Service) constructNetworks(openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec) ([]infrav1.Network, error) {
 	trunkRequired := false
 
@@ -56,6 +74,11 @@ func (s *Service) constructNetworks(openStackCluster *infrav1.OpenStackCluster,
 
 	for i := range instanceSpec.Ports {
 		port := &instanceSpec.Ports[i]
+		// Existing ports are attached as they are, without a trunk
+		if port.ID != "" {
+			nets = append(nets, infrav1.Network{PortOpts: port})
+			continue
+		}
 		// No Trunk field specified for the port, inherit openStackMachine.Spec.Trunk.
 		if port.Trunk == nil {
 			port.Trunk = &instanceSpec.Trunk
@@ -63,38 +86,18 @@ func (s *Service) constructNetworks(openStackCluster *infrav1.OpenStackCluster,
 		if *port.Trunk {
 			trunkRequired = true
 		}
-		if port.Network != nil {
-			netID := port.Network.ID
-			if netID == "" {
-				netIDs, err := s.networkingService.GetNetworkIDsByFilter(port.Network.ToListOpt())
-				if err != nil {
-					return nil, err
-				}
-				if len(netIDs) > 1 {
-					return nil, fmt.Errorf("network filter for port %s returns more than one result", port.NameSuffix)
-				} else if len(netIDs) == 0 {
-					return nil, fmt.Errorf("network filter for port %s returns no networks", port.NameSuffix)
-				}
-				netID = netIDs[0]
-			}
-			nets = append(nets, infrav1.Network{
-				ID:       netID,
-				Subnet:   &infrav1.Subnet{},
-				PortOpts: port,
-			})
-		} else {
-			nets = append(nets, infrav1.Network{
-				ID: openStackCluster.Status.Network.ID,
-				Subnet: &infrav1.Subnet{
-					ID: openStackCluster.Status.Network.Subnet.ID,
-				},
-				PortOpts: port,
-			})
+		net, err := s.getPortNetwork(openStackCluster, port)
+		if err != nil {
+			return nil, err
 		}
+		nets = append(nets, net)
 	}
 
 	// no networks or ports found in the spec, so create a port on the cluster network
 	if len(nets) == 0 {
+		if openStackCluster == nil {
+			return nil, fmt.Errorf("no networks or ports are specified and there is no cluster network")
+		}
 		nets = []infrav1.Network{{
 			ID: openStackCluster.Status.Network.ID,
 			Subnet: &infrav1.Subnet{
@@ -120,6 +123,42 @@ func (s *Service) constructNetworks(openStackCluster *infrav1.OpenStackCluster,
 	return nets, nil
 }
 
+// getPortNetwork returns the network the port is created on: the network
+// selected by its network filter, or the cluster network.
+func (s *Service) getPortNetwork(openStackCluster *infrav1.OpenStackCluster, port *infrav1.PortOpts) (infrav1.Network, error) {
+	if port.Network == nil {
+		if openStackCluster == nil {
+			return infrav1.Network{}, fmt.Errorf("port %s has no network and there is no cluster network", port.NameSuffix)
+		}
+		return infrav1.Network{
+			ID: openStackCluster.Status.Network.ID,
+			Subnet: &infrav1.Subnet{
+				ID: openStackCluster.Status.Network.Subnet.ID,
+			},
+			PortOpts: port,
+		}, nil
+	}
+
+	netID := port.Network.ID
+	if netID == "" {
+		netIDs, err := s.networkingService.GetNetworkIDsByFilter(port.Network.ToListOpt())
+		if err != nil {
+			return infrav1.Network{}, err
+		}
+		if len(netIDs) > 1 {
+			return infrav1.Network{}, fmt.Errorf("network filter for port %s returns more than one result", port.NameSuffix)
+		} else if len(netIDs) == 0 {
+			return infrav1.Network{}, fmt.Errorf("network filter for port %s returns no networks", port.NameSuffix)
+		}
+		netID = netIDs[0]
+	}
+	return infrav1.Network{
+		ID:       netID,
+		Subnet:   &infrav1.Subnet{},
+		PortOpts: port,
+	}, nil
+}
+
 func (s *Service) CreateInstance(eventObject runtime.Object, openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec, clusterName string) (*InstanceStatus, error) {
 	return s.createInstanceImpl(eventObject, openStackCluster, instanceSpec, clusterName, retryIntervalInstanceStatus)
 }
@@ -128,6 +167,8 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 	var server *ServerExt
 	accessIPv4 := ""
 	portList := []servers.Network{}
+	// The ports created for the server, without the existing ports
+	createdPorts := []servers.Network{}
 
 	if instanceSpec.Subnet != "" && accessIPv4 == "" {
 		return nil, fmt.Errorf("no ports with fixed IPs found on Subnet %q", instanceSpec.Subnet)
@@ -149,8 +190,8 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 			return
 		}
 
-		if err := s.deletePorts(eventObject, portList); err != nil {
-			s.scope.Logger.V(4).Error(err, "Failed to clean up ports after failure")
+		if err := s.deletePorts(eventObject, createdPorts); err != nil {
+			s.scope.Logger.Error(err, "Failed to clean up ports after failure")
 		}
 	}()
 
@@ -165,7 +206,8 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 	}
 
 	for i, network := range nets {
-		if network.ID == "" {
+		existingPort := network.PortOpts != nil && network.PortOpts.ID != ""
+		if network.ID == "" && !existingPort {
 			return nil, fmt.Errorf("no network was found or provided. Please check your machine configuration and try again")
 		}
 		iTags := []string{}
@@ -173,7 +215,7 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 			iTags = instanceSpec.Tags
 		}
 		portName := getPortName(instanceSpec.Name, network.PortOpts, i)
-		port, err := s.networkingService.GetOrCreatePort(eventObject, clusterName, portName, network, &securityGroups, iTags)
+		port, err := s.networkingService.GetOrCreatePort(eventObject, clusterName, instanceSpec.Name, portName, network, &securityGroups, iTags)
 		if err != nil {
 			return nil, err
 		}
@@ -187,15 +229,19 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		portList = append(portList, servers.Network{
 			Port: port.ID,
 		})
+		if !existingPort {
+			createdPorts = append(createdPorts, servers.Network{
+				Port: port.ID,
+			})
+		}
 	}
 
-	volume, err := s.getOrCreateRootVolume(eventObject, instanceSpec, imageID)
+	volume, err := s.getOrCreateRootVolume(eventObject, clusterName, instanceSpec, imageID)
 	if err != nil {
 		return nil, fmt.Errorf("error in get or create root volume: %w", err)
 	}
 
-	instanceCreateTimeout := getTimeout("CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT", timeoutInstanceCreate)
-	instanceCreateTimeout *= time.Minute
+	instanceCreateTimeout := s.instanceCreateTimeout()
 
 	// Wait for volume to become available
 	if volume != nil {
@@ -241,7 +287,7 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		AccessIPv4:       accessIPv4,
 	}
 
-	serverCreateOpts = applyRootVolume(serverCreateOpts, volume)
+	serverCreateOpts = applyRootVolume(serverCreateOpts, volume, !instanceSpec.RetainRootVolume)
 
 	serverCreateOpts = applyServerGroupID(serverCreateOpts, instanceSpec.ServerGroupID)
 
@@ -250,32 +296,81 @@ func (s *Service) createInstanceImpl(eventObject runtime.Object, openStackCluste
 		KeyName:           instanceSpec.SSHKeyName,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error creating Openstack instance: %v", err)
+		record.Failed(eventObject, record.Create, record.Resource{Kind: record.Server, Name: instanceSpec.Name, RequestID: s.scope.LastRequestID()}, err)
+		return nil, fmt.Errorf("error creating Openstack instance: %w", capoerrors.WithRequestID(err, s.scope.LastRequestID()))
 	}
+	serverEvent := record.Resource{Kind: record.Server, Name: instanceSpec.Name, ID: server.ID, RequestID: s.scope.LastRequestID()}
+
+	// The servers created by concurrent reconciles are polled together
+	watcher := serverWatcherFor(s.scope)
+	watcher.watch(server.ID)
+	defer watcher.unwatch(server.ID)
 
 	var createdInstance *InstanceStatus
 	err = util.PollImmediate(retryInterval, instanceCreateTimeout, func() (bool, error) {
-		createdInstance, err = s.GetInstanceStatus(server.ID)
+		watchedServer, err := watcher.get(s.computeService, server.ID, retryInterval)
 		if err != nil {
 			if capoerrors.IsRetryable(err) {
 				return false, nil
 			}
-			return false, err
+			return false, fmt.Errorf("list servers: %v", err)
+		}
+		if watchedServer == nil {
+			return false, nil
 		}
-		if createdInstance.State() == infrav1.InstanceStateError {
+		createdInstance = &InstanceStatus{watchedServer, s.scope.Logger}
+		switch createdInstance.State() {
+		case infrav1.InstanceStateError:
 			return false, fmt.Errorf("error creating OpenStack instance %s, status changed to error", createdInstance.ID())
+		case infrav1.InstanceStateDeleted:
+			return false, fmt.Errorf("error creating OpenStack instance %s, instance was deleted", createdInstance.ID())
 		}
 		return createdInstance.State() == infrav1.InstanceStateActive, nil
 	})
 	if err != nil {
-		record.Warnf(eventObject, "FailedCreateServer", "Failed to create server %s: %v", createdInstance.Name(), err)
-		return nil, err
+		record.Failed(eventObject, record.Create, serverEvent, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
-	record.Eventf(eventObject, "SuccessfulCreateServer", "Created server %s with id %s", createdInstance.Name(), createdInstance.ID())
+	record.Succeeded(eventObject, record.Create, serverEvent)
 	return createdInstance, nil
 }
 
+// AdoptInstance adopts the existing server with the given ID instead of
+// creating a new one. The security groups of the instance are added to all
+// ports attached to the server.
+func (s *Service) AdoptInstance(eventObject runtime.Object, instanceSpec *InstanceSpec, instanceID string) (*InstanceStatus, error) {
+	instanceStatus, err := s.GetInstanceStatus(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instanceStatus == nil {
+		record.Failed(eventObject, record.Adopt, record.Resource{Kind: record.Server, ID: instanceID, RequestID: s.scope.LastRequestID()}, fmt.Errorf("server does not exist"))
+		return nil, fmt.Errorf("server with id %s to adopt does not exist", instanceID)
+	}
+
+	securityGroups, err := s.networkingService.GetSecurityGroups(instanceSpec.SecurityGroups)
+	if err != nil {
+		return nil, fmt.Errorf("error getting security groups: %v", err)
+	}
+
+	if len(securityGroups) > 0 {
+		instanceInterfaces, err := s.computeService.ListAttachedInterfaces(instanceID)
+		if err != nil {
+			return nil, fmt.Errorf("error listing interfaces of server %s: %v", instanceID, err)
+		}
+		for _, iface := range instanceInterfaces {
+			if err := s.networkingService.EnsurePortSecurityGroups(eventObject, iface.PortID, securityGroups); err != nil {
+				record.Failed(eventObject, record.Adopt, record.Resource{Kind: record.Server, Name: instanceStatus.Name(), ID: instanceID}, err)
+				return nil, err
+			}
+		}
+	}
+
+	record.Succeeded(eventObject, record.Adopt, record.Resource{Kind: record.Server, Name: instanceStatus.Name(), ID: instanceStatus.ID()})
+	return instanceStatus, nil
+}
+
 // getPortName appends a suffix to an instance name in order to try and get a unique name per port.
 func getPortName(instanceName string, opts *infrav1.PortOpts, netIndex int) string {
 	if opts != nil && opts.NameSuffix != "" {
@@ -284,10 +379,50 @@ func getPortName(instanceName string, opts *infrav1.PortOpts, netIndex int) stri
 	return fmt.Sprintf("%s-%d", instanceName, netIndex)
 }
 
+// primaryPortName returns the name of the port created for the first network
+// or port of the instance spec. Networks are created before ports.
+func primaryPortName(instanceSpec *InstanceSpec) string {
+	if len(instanceSpec.Networks) == 0 && len(instanceSpec.Ports) > 0 {
+		return getPortName(instanceSpec.Name, &instanceSpec.Ports[0], 0)
+	}
+	return getPortName(instanceSpec.Name, nil, 0)
+}
+
+// isPrimaryPort returns whether the port is the port of the first network or
+// port of the instance spec. An existing port keeps its own name.
+func isPrimaryPort(instanceSpec *InstanceSpec, port *ports.Port) bool {
+	if len(instanceSpec.Networks) == 0 && len(instanceSpec.Ports) > 0 && instanceSpec.Ports[0].ID != "" {
+		return port.ID == instanceSpec.Ports[0].ID
+	}
+	return port.Name == primaryPortName(instanceSpec)
+}
+
 func rootVolumeName(instanceName string) string {
 	return fmt.Sprintf("%s-root", instanceName)
 }
 
+// The root volumes created for a server are identified by their metadata, as
+// volumes have no tags. It records the namespace of the object the server was
+// created for, the cluster and the server.
+const (
+	volumeNamespaceMetadataKey = "capo-namespace"
+	volumeClusterMetadataKey   = "capo-cluster"
+	volumeServerMetadataKey    = "capo-server"
+)
+
+// rootVolumeMetadata returns the metadata of the root volume created for the
+// server with the given name.
+func rootVolumeMetadata(eventObject runtime.Object, clusterName, serverName string) map[string]string {
+	metadata := map[string]string{
+		volumeClusterMetadataKey: clusterName,
+		volumeServerMetadataKey:  serverName,
+	}
+	if obj, ok := eventObject.(metav1.Object); ok {
+		metadata[volumeNamespaceMetadataKey] = obj.GetNamespace()
+	}
+	return metadata
+}
+
 func hasRootVolume(rootVolume *infrav1.RootVolume) bool {
 	return rootVolume != nil && rootVolume.Size > 0
 }
@@ -311,7 +446,7 @@ func (s *Service) getVolumeByName(name string) (*volumes.Volume, error) {
 	return &volumeList[0], nil
 }
 
-func (s *Service) getOrCreateRootVolume(eventObject runtime.Object, instanceSpec *InstanceSpec, imageID string) (*volumes.Volume, error) {
+func (s *Service) getOrCreateRootVolume(eventObject runtime.Object, clusterName string, instanceSpec *InstanceSpec, imageID string) (*volumes.Volume, error) {
 	rootVolume := instanceSpec.RootVolume
 	if !hasRootVolume(rootVolume) {
 		return nil, nil
@@ -329,19 +464,23 @@ func (s *Service) getOrCreateRootVolume(eventObject runtime.Object, instanceSpec
 			return nil, fmt.Errorf("exected to find volume %s with size %d; found size %d", name, size, volume.Size)
 		}
 
-		s.scope.Logger.Info("using existing root volume %s", name)
+		s.scope.Logger.V(logging.LevelDebug).Info("Reusing existing root volume", "name", name, "id", volume.ID)
 		return volume, nil
 	}
 
 	availabilityZone := instanceSpec.FailureDomain
+	if instanceSpec.StorageAZ != "" {
+		availabilityZone = instanceSpec.StorageAZ
+	}
 	if rootVolume.AvailabilityZone != "" {
 		availabilityZone = rootVolume.AvailabilityZone
 	}
 
 	createOpts := volumes.CreateOpts{
 		Size:             rootVolume.Size,
-		Description:      fmt.Sprintf("Root volume for %s", instanceSpec.Name),
+		Description:      names.GetVolumeDescription(clusterName, instanceSpec.Name),
 		Name:             rootVolumeName(instanceSpec.Name),
+		Metadata:         rootVolumeMetadata(eventObject, clusterName, instanceSpec.Name),
 		ImageID:          imageID,
 		Multiattach:      false,
 		AvailabilityZone: availabilityZone,
@@ -356,8 +495,76 @@ func (s *Service) getOrCreateRootVolume(eventObject runtime.Object, instanceSpec
 	return volume, err
 }
 
+// DeleteOrphanedVolumes deletes the available root volumes created for the
+// servers of the cluster in the given namespace which are no longer managed,
+// as told by isManaged. These are left behind when the creation of a server
+// failed after its root volume was created, and the server was not deleted
+// by CAPO, e.g. because its finalizer was removed. Root volumes created
+// before their metadata was set are not found.
+func (s *Service) DeleteOrphanedVolumes(eventObject runtime.Object, namespace, clusterName string, isManaged func(serverName string) bool) error {
+	volumeList, err := s.computeService.ListVolumes(volumes.ListOpts{
+		TenantID: s.scope.ProjectID,
+		Metadata: map[string]string{volumeNamespaceMetadataKey: namespace},
+	})
+	if err != nil {
+		return fmt.Errorf("list volumes of cluster %s: %v", clusterName, err)
+	}
+
+	for _, volume := range volumeList {
+		// The servers of machines are created with the name of the cluster,
+		// the bastion with its namespaced name
+		if cluster := volume.Metadata[volumeClusterMetadataKey]; cluster != clusterName && cluster != namespace+"-"+clusterName {
+			continue
+		}
+		if len(volume.Attachments) > 0 || (volume.Status != "available" && volume.Status != "error") {
+			continue
+		}
+		serverName := volume.Metadata[volumeServerMetadataKey]
+		if isManaged(serverName) {
+			continue
+		}
+
+		s.scope.Logger.Info("Deleting orphaned root volume", "name", volume.Name, "id", volume.ID, "server", serverName)
+		if err := s.computeService.DeleteVolume(volume.ID, volumes.DeleteOpts{}); err != nil && !capoerrors.IsNotFound(err) {
+			record.Warnf(eventObject, "FailedDeleteVolume", "Failed to delete orphaned root volume %s of server %s: %v", volume.ID, serverName, err)
+			return fmt.Errorf("delete volume %s failed: %v", volume.ID, err)
+		}
+		record.Eventf(eventObject, "SuccessfulDeleteVolume", "Deleted orphaned root volume %s of server %s", volume.ID, serverName)
+	}
+	return nil
+}
+
+// ReconcileRootVolumeSize extends the root volume of a running instance whose
+// size was increased in instanceSpec. Cinder extends the volume attached to
+// the instance, and the guest has to grow its partition and filesystem.
+func (s *Service) ReconcileRootVolumeSize(eventObject runtime.Object, instanceSpec *InstanceSpec) error {
+	if !hasRootVolume(instanceSpec.RootVolume) {
+		return nil
+	}
+
+	name := rootVolumeName(instanceSpec.Name)
+	size := instanceSpec.RootVolume.Size
+
+	volume, err := s.getVolumeByName(name)
+	if err != nil {
+		return err
+	}
+	// Volumes which were already extended, or retained from a previous
+	// instance with a larger size, are left alone
+	if volume == nil || volume.Size >= size {
+		return nil
+	}
+
+	if err := s.computeService.ExtendVolume(volume.ID, size); err != nil {
+		record.Warnf(eventObject, "FailedExtendVolume", "Failed to extend root volume %s from %d to %d GiB: %v", volume.ID, volume.Size, size, err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulExtendVolume", "Extended root volume %s from %d to %d GiB", volume.ID, volume.Size, size)
+	return nil
+}
+
 // applyRootVolume sets a root volume if the root volume Size is not 0.
-func applyRootVolume(opts servers.CreateOptsBuilder, volume *volumes.Volume) servers.CreateOptsBuilder {
+func applyRootVolume(opts servers.CreateOptsBuilder, volume *volumes.Volume, deleteOnTermination bool) servers.CreateOptsBuilder {
 	if volume == nil {
 		return opts
 	}
@@ -366,7 +573,7 @@ func applyRootVolume(opts servers.CreateOptsBuilder, volume *volumes.Volume) ser
 		SourceType:          bootfromvolume.SourceVolume,
 		BootIndex:           0,
 		UUID:                volume.ID,
-		DeleteOnTermination: true,
+		DeleteOnTermination: deleteOnTermination,
 		DestinationType:     bootfromvolume.DestinationVolume,
 	}
 	return bootfromvolume.CreateOptsExt{
@@ -427,6 +634,14 @@ func (s *Service) getServerNetworks(networkParams []infrav1.NetworkParam) ([]inf
 				if err != nil {
 					return err
 				}
+				// Neutron does not guarantee the order of its results, keep
+				// the order of the NICs of recreated servers stable.
+				sort.SliceStable(subnetsByFilter, func(i, j int) bool {
+					if subnetsByFilter[i].Name != subnetsByFilter[j].Name {
+						return subnetsByFilter[i].Name < subnetsByFilter[j].Name
+					}
+					return subnetsByFilter[i].ID < subnetsByFilter[j].ID
+				})
 				for _, subnetByFilter := range subnetsByFilter {
 					addSubnet(subnetByFilter.NetworkID, subnetByFilter.ID)
 				}
@@ -442,19 +657,25 @@ func (s *Service) getServerNetworks(networkParams []infrav1.NetworkParam) ([]inf
 		// If there is no explicit network UUID and no network filter,
 		// we will look for subnets matching any given subnet filters in
 		// all networks.
-		if networkParam.UUID != "" || networkParam.Filter == (infrav1.NetworkFilter{}) {
+		if networkParam.UUID != "" || networkParam.Filter.IsEmpty() {
 			if err := addSubnets(networkParam, networkParam.UUID); err != nil {
 				return nil, err
 			}
 			continue
 		}
 		opts := networkParam.Filter.ToListOpt()
-		ids, err := s.networkingService.GetNetworkIDsByFilter(&opts)
+		netsByFilter, err := s.networkingService.GetNetworksByFilter(&opts)
 		if err != nil {
 			return nil, err
 		}
-		for _, netID := range ids {
-			if err := addSubnets(networkParam, netID); err != nil {
+		sort.SliceStable(netsByFilter, func(i, j int) bool {
+			if netsByFilter[i].Name != netsByFilter[j].Name {
+				return netsByFilter[i].Name < netsByFilter[j].Name
+			}
+			return netsByFilter[i].ID < netsByFilter[j].ID
+		})
+		for _, netByFilter := range netsByFilter {
+			if err := addSubnets(networkParam, netByFilter.ID); err != nil {
 				return nil, err
 			}
 		}
@@ -464,9 +685,11 @@ func (s *Service) getServerNetworks(networkParams []infrav1.NetworkParam) ([]inf
 
 // Helper function for getting image id from name.
 func (s *Service) getImageIDFromName(imageName string) (string, error) {
-	var opts images.ListOpts
-
-	opts.Name = imageName
+	// Two images are enough to tell whether the name is ambiguous
+	opts := images.ListOpts{
+		Name:  imageName,
+		Limit: 2,
+	}
 
 	allImages, err := s.computeService.ListImages(opts)
 	if err != nil {
@@ -498,21 +721,81 @@ func (s *Service) getImageID(imageUUID, imageName string) (string, error) {
 
 // GetManagementPort returns the port which is used for management and external
 // traffic. Cluster floating IPs must be associated with this port.
-func (s *Service) GetManagementPort(openStackCluster *infrav1.OpenStackCluster, instanceStatus *InstanceStatus) (*ports.Port, error) {
+func (s *Service) GetManagementPort(openStackCluster *infrav1.OpenStackCluster, instanceSpec *InstanceSpec, instanceStatus *InstanceStatus) (*ports.Port, error) {
 	ns, err := instanceStatus.NetworkStatus()
 	if err != nil {
 		return nil, err
 	}
-	allPorts, err := s.networkingService.GetPortFromInstanceIP(instanceStatus.ID(), ns.IP(openStackCluster.Status.Network.Name))
+	portList, err := s.networkingService.ListPortsForInstance(instanceStatus.ID())
 	if err != nil {
 		return nil, fmt.Errorf("lookup management port for server %s: %w", instanceStatus.ID(), err)
 	}
-	if len(allPorts) < 1 {
+	port := managementPort(instanceSpec, portList, openStackCluster.Status.Network.ID, ns.IP(openStackCluster.Status.Network.Name))
+	if port == nil {
 		return nil, fmt.Errorf("did not find management port for server %s", instanceStatus.ID())
 	}
-	return &allPorts[0], nil
+	return port, nil
 }
 
+// managementPort returns the port of the instance on the cluster network.
+// An instance may have several ports on the cluster network, for example to
+// separate storage and management traffic on different vNIC types. The
+// primary port is used if it is on the cluster network, otherwise the port
+// with the first address of the instance on the cluster network.
+func managementPort(instanceSpec *InstanceSpec, portList []ports.Port, networkID, ip string) *ports.Port {
+	var clusterPorts []*ports.Port
+	for i := range portList {
+		if portList[i].NetworkID == networkID {
+			clusterPorts = append(clusterPorts, &portList[i])
+		}
+	}
+	if len(clusterPorts) == 0 {
+		return nil
+	}
+
+	for _, port := range clusterPorts {
+		if isPrimaryPort(instanceSpec, port) {
+			return port
+		}
+	}
+	for _, port := range clusterPorts {
+		for _, fixedIP := range port.FixedIPs {
+			if fixedIP.IPAddress == ip {
+				return port
+			}
+		}
+	}
+	return clusterPorts[0]
+}
+
+// PortIP returns the first IPv4 fixed IP of the port.
+func PortIP(port *ports.Port) string {
+	for _, fixedIP := range port.FixedIPs {
+		if ip := net.ParseIP(fixedIP.IPAddress); ip != nil && ip.To4() != nil {
+			return fixedIP.IPAddress
+		}
+	}
+	return ""
+}
+
+// GetPrimaryPort returns the port of the first network or port of the
+// instance spec.
+func (s *Service) GetPrimaryPort(instanceSpec *InstanceSpec, instanceID string) (*ports.Port, error) {
+	portList, err := s.networkingService.ListPortsForInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("lookup primary port for server %s: %w", instanceID, err)
+	}
+	for i := range portList {
+		if isPrimaryPort(instanceSpec, &portList[i]) {
+			return &portList[i], nil
+		}
+	}
+	return nil, fmt.Errorf("did not find primary port %s for server %s", primaryPortName(instanceSpec), instanceID)
+}
+
+// DeleteInstance deletes the server of instanceStatus together with its ports,
+// trunks and root volume, or the dangling root volume of instanceSpec if there
+// is no server. It returns ErrDeletionInProgress until the root volume is gone.
 func (s *Service) DeleteInstance(eventObject runtime.Object, instanceSpec *InstanceSpec, instanceStatus *InstanceStatus) error {
 	if instanceStatus == nil {
 		/*
@@ -528,29 +811,19 @@ func (s *Service) DeleteInstance(eventObject runtime.Object, instanceSpec *Insta
 			associated with the instance, including a root volume, have been deleted. To achieve this:
 			* We always call DeleteInstance when reconciling a delete, regardless of
 			  whether the instance exists or not.
-			* If the instance was already deleted we check that the volume is also gone.
+			* If the instance was already deleted we check that the volume is also gone, and
+			  return ErrDeletionInProgress until it is.
 
 			Note that we don't need to separately delete the root volume when deleting the instance because
 			DeleteOnTermination will ensure it is deleted in that case.
 		*/
-		rootVolume := instanceSpec.RootVolume
-		if hasRootVolume(rootVolume) {
-			name := rootVolumeName(instanceSpec.Name)
-			volume, err := s.getVolumeByName(name)
-			if err != nil {
-				return err
-			}
-			if volume == nil {
-				return nil
-			}
-
-			s.scope.Logger.Info("deleting dangling root volume %s(%s)", volume.Name, volume.ID)
-			return s.computeService.DeleteVolume(volume.ID, volumes.DeleteOpts{})
-		}
-
-		return nil
+		return s.deleteRootVolume(instanceSpec)
 	}
 
+	// The resources of the server are deleted in the order of their
+	// dependencies: its interfaces are detached and their trunks and ports
+	// deleted before the server, so that they cannot be leaked by a failure
+	// after the server is gone. Deleting the server releases its root volume.
 	instanceInterfaces, err := s.computeService.ListAttachedInterfaces(instanceStatus.ID())
 	if err != nil {
 		return err
@@ -561,18 +834,28 @@ func (s *Service) DeleteInstance(eventObject runtime.Object, instanceSpec *Insta
 		return fmt.Errorf("obtaining network extensions: %v", err)
 	}
 
-	// get and delete trunks
+	existingPorts := existingPortIDs(instanceSpec)
 	for _, port := range instanceInterfaces {
-		if err = s.deleteAttachInterface(eventObject, instanceStatus.InstanceIdentifier(), port.PortID); err != nil {
+		detached, err := s.deleteAttachInterface(eventObject, instanceStatus.InstanceIdentifier(), port.PortID)
+		if err != nil {
 			return err
 		}
 
+		// Existing ports are kept once detached
+		if existingPorts.Has(port.PortID) {
+			continue
+		}
+		if detached {
+			if err := s.networkingService.WaitForPortRelease(port.PortID); err != nil {
+				return fmt.Errorf("port %s was not released by server %s: %w", port.PortID, instanceStatus.ID(), err)
+			}
+		}
 		if trunkSupported {
 			if err = s.networkingService.DeleteTrunk(eventObject, port.PortID); err != nil {
 				return err
 			}
 		}
-		if err = s.networkingService.DeletePort(eventObject, port.PortID); err != nil {
+		if err = s.networkingService.DeletePort(eventObject, port.PortID); err != nil && !capoerrors.IsNotFound(err) {
 			return err
 		}
 	}
@@ -584,7 +867,52 @@ func (s *Service) DeleteInstance(eventObject runtime.Object, instanceSpec *Insta
 		}
 	}
 
-	return s.deleteInstance(eventObject, instanceStatus.InstanceIdentifier())
+	if err := s.deleteInstance(eventObject, instanceStatus.InstanceIdentifier()); err != nil {
+		return err
+	}
+
+	return s.deleteRootVolume(instanceSpec)
+}
+
+// existingPortIDs returns the IDs of the existing ports of the instance spec,
+// which were not created for the instance.
+func existingPortIDs(instanceSpec *InstanceSpec) sets.String {
+	ids := sets.NewString()
+	for _, port := range instanceSpec.Ports {
+		if port.ID != "" {
+			ids.Insert(port.ID)
+		}
+	}
+	return ids
+}
+
+// deleteRootVolume deletes the root volume of a deleted server, unless it is
+// retained. Nova deletes it together with the server, volumes which were
+// detached without being deleted are deleted here. ErrDeletionInProgress is
+// returned until the volume is gone, so that the deletion is retried later
+// instead of blocking the reconcile.
+func (s *Service) deleteRootVolume(instanceSpec *InstanceSpec) error {
+	if !hasRootVolume(instanceSpec.RootVolume) || instanceSpec.RetainRootVolume {
+		return nil
+	}
+
+	name := rootVolumeName(instanceSpec.Name)
+	volume, err := s.getVolumeByName(name)
+	if err != nil {
+		return err
+	}
+	if volume == nil {
+		return nil
+	}
+
+	switch volume.Status {
+	case "available", "error":
+		s.scope.Logger.Info("Deleting detached root volume", "name", volume.Name, "id", volume.ID)
+		if err := s.computeService.DeleteVolume(volume.ID, volumes.DeleteOpts{}); err != nil && !capoerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("root volume %s is being deleted: %w", name, ErrDeletionInProgress)
 }
 
 func (s *Service) deletePorts(eventObject runtime.Object, nets []servers.Network) error {
@@ -606,38 +934,43 @@ func (s *Service) deletePorts(eventObject runtime.Object, nets []servers.Network
 	return nil
 }
 
-func (s *Service) deleteAttachInterface(eventObject runtime.Object, instance *InstanceIdentifier, portID string) error {
+// deleteAttachInterface detaches the port from the instance. It returns false
+// if the port was not detached because it did not exist or the instance was
+// in a state which does not allow detaching interfaces.
+func (s *Service) deleteAttachInterface(eventObject runtime.Object, instance *InstanceIdentifier, portID string) (bool, error) {
 	err := s.computeService.DeleteAttachedInterface(instance.ID, portID)
 	if err != nil {
 		if capoerrors.IsNotFound(err) {
 			record.Eventf(eventObject, "SuccessfulDeleteAttachInterface", "Attach interface did not exist: instance %s, port %s", instance.ID, portID)
-			return nil
+			return false, nil
 		}
 		if capoerrors.IsConflict(err) {
 			// we don't want to block deletion because of Conflict
 			// due to instance must be paused/active/shutoff in order to detach interface
-			return nil
+			return false, nil
 		}
 		record.Warnf(eventObject, "FailedDeleteAttachInterface", "Failed to delete attach interface: instance %s, port %s: %v", instance.ID, portID, err)
-		return err
+		return false, err
 	}
 
 	record.Eventf(eventObject, "SuccessfulDeleteAttachInterface", "Deleted attach interface: instance %s, port %s", instance.ID, portID)
-	return nil
+	return true, nil
 }
 
 func (s *Service) deleteInstance(eventObject runtime.Object, instance *InstanceIdentifier) error {
 	err := s.computeService.DeleteServer(instance.ID)
+	serverEvent := record.Resource{Kind: record.Server, Name: instance.Name, ID: instance.ID, RequestID: s.scope.LastRequestID()}
 	if err != nil {
 		if capoerrors.IsNotFound(err) {
-			record.Eventf(eventObject, "SuccessfulDeleteServer", "Server %s with id %s did not exist", instance.Name, instance.ID)
+			serverEvent.Detail = "server did not exist"
+			record.Succeeded(eventObject, record.Delete, serverEvent)
 			return nil
 		}
-		record.Warnf(eventObject, "FailedDeleteServer", "Failed to delete server %s with id %s: %v", instance.Name, instance.ID, err)
-		return err
+		record.Failed(eventObject, record.Delete, serverEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
-	err = util.PollImmediate(retryIntervalInstanceStatus, timeoutInstanceDelete, func() (bool, error) {
+	err = util.PollImmediate(retryIntervalInstanceStatus, s.instanceDeleteTimeout(), func() (bool, error) {
 		i, err := s.GetInstanceStatus(instance.ID)
 		if err != nil {
 			return false, err
@@ -648,14 +981,117 @@ func (s *Service) deleteInstance(eventObject runtime.Object, instance *InstanceI
 		return true, nil
 	})
 	if err != nil {
-		record.Warnf(eventObject, "FailedDeleteServer", "Failed to delete server %s with id %s: %v", instance.Name, instance.ID, err)
-		return err
+		record.Failed(eventObject, record.Delete, serverEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+
+	record.Succeeded(eventObject, record.Delete, serverEvent)
+	return nil
+}
+
+// RebootInstance soft reboots the instance, or hard reboots it if hard is true.
+func (s *Service) RebootInstance(eventObject runtime.Object, instance *InstanceIdentifier, hard bool) error {
+	opts := servers.RebootOpts{Type: servers.SoftReboot}
+	serverEvent := record.Resource{Kind: record.Server, Name: instance.Name, ID: instance.ID, Detail: "soft reboot"}
+	if hard {
+		opts.Type = servers.HardReboot
+		serverEvent.Detail = "hard reboot"
+	}
+
+	err := s.computeService.RebootServer(instance.ID, opts)
+	serverEvent.RequestID = s.scope.LastRequestID()
+	if err != nil {
+		record.Failed(eventObject, record.Reboot, serverEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
-	record.Eventf(eventObject, "SuccessfulDeleteServer", "Deleted server %s with id %s", instance.Name, instance.ID)
+	record.Succeeded(eventObject, record.Reboot, serverEvent)
 	return nil
 }
 
+// GetInstanceResourceIDs returns the IDs of the OpenStack resources used by the
+// instance: its server, the ports attached to it with their trunks and
+// floating IPs, and the attached volumes. Trunks are only looked up if the
+// instance has trunk ports.
+func (s *Service) GetInstanceResourceIDs(instanceStatus *InstanceStatus, trunk bool) (*infrav1.InstanceResourceIDs, error) {
+	resources := &infrav1.InstanceResourceIDs{
+		ServerID:  instanceStatus.ID(),
+		VolumeIDs: instanceStatus.AttachedVolumeIDs(),
+	}
+
+	portList, err := s.networkingService.ListPortsForInstance(instanceStatus.ID())
+	if err != nil {
+		return nil, err
+	}
+	for _, port := range portList {
+		resources.PortIDs = append(resources.PortIDs, port.ID)
+
+		if trunk {
+			portTrunk, err := s.networkingService.GetTrunkByPortID(port.ID)
+			if err != nil {
+				return nil, fmt.Errorf("get trunk of port %s: %v", port.ID, err)
+			}
+			if portTrunk != nil {
+				resources.TrunkIDs = append(resources.TrunkIDs, portTrunk.ID)
+			}
+		}
+
+		fip, err := s.networkingService.GetFloatingIPByPortID(port.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get floating IP of port %s: %v", port.ID, err)
+		}
+		if fip != nil {
+			resources.FloatingIPIDs = append(resources.FloatingIPIDs, fip.ID)
+		}
+	}
+
+	// Sorted, so that the status only changes with the resources
+	sort.Strings(resources.PortIDs)
+	sort.Strings(resources.TrunkIDs)
+	sort.Strings(resources.FloatingIPIDs)
+	sort.Strings(resources.VolumeIDs)
+	return resources, nil
+}
+
+// GetConsoleOutput returns the console log of the instance.
+func (s *Service) GetConsoleOutput(instanceID string) (string, error) {
+	output, err := s.computeService.ShowConsoleOutput(instanceID, servers.ShowConsoleOutputOpts{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get console output of server %s: %v", instanceID, err)
+	}
+	return output, nil
+}
+
+// consoleProtocols are the protocols of the supported remote console types.
+var consoleProtocols = map[remoteconsoles.ConsoleType]remoteconsoles.ConsoleProtocol{
+	remoteconsoles.ConsoleTypeNoVNC:      remoteconsoles.ConsoleProtocolVNC,
+	remoteconsoles.ConsoleTypeSerial:     remoteconsoles.ConsoleProtocolSerial,
+	remoteconsoles.ConsoleTypeSPICEHTML5: remoteconsoles.ConsoleProtocolSPICE,
+}
+
+// IsSupportedConsoleType returns whether consoleType is a supported type of
+// remote console.
+func IsSupportedConsoleType(consoleType string) bool {
+	_, ok := consoleProtocols[remoteconsoles.ConsoleType(consoleType)]
+	return ok
+}
+
+// GetConsoleURL creates a remote console of the instance and returns its URL.
+func (s *Service) GetConsoleURL(instanceID, consoleType string) (string, error) {
+	protocol, ok := consoleProtocols[remoteconsoles.ConsoleType(consoleType)]
+	if !ok {
+		return "", fmt.Errorf("unsupported console type %q", consoleType)
+	}
+	console, err := s.computeService.CreateRemoteConsole(instanceID, remoteconsoles.CreateOpts{
+		Protocol: protocol,
+		Type:     remoteconsoles.ConsoleType(consoleType),
+	})
+	if err != nil {
+		return "", capoerrors.WithRequestID(fmt.Errorf("failed to create %s console of server %s: %v", consoleType, instanceID, err), s.scope.LastRequestID())
+	}
+	return console.URL, nil
+}
+
 func (s *Service) GetInstanceStatus(resourceID string) (instance *InstanceStatus, err error) {
 	if resourceID == "" {
 		return nil, fmt.Errorf("resourceId should be specified to get detail")
@@ -685,20 +1121,48 @@ func (s *Service) GetInstanceStatusByName(eventObject runtime.Object, name strin
 		listOpts = servers.ListOpts{}
 	}
 
-	serverList, err := s.computeService.ListServers(listOpts)
+	listOpts.Limit = serverPageSize
+
+	// Only the first server is kept, the others are only counted
+	var server *ServerExt
+	var count int
+	err = s.computeService.EachServerPage(listOpts, func(serverList []ServerExt) (bool, error) {
+		if server == nil && len(serverList) > 0 {
+			server = &serverList[0]
+		}
+		count += len(serverList)
+		return true, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("get server list: %v", err)
 	}
 
-	if len(serverList) > 1 {
-		record.Warnf(eventObject, "DuplicateServerNames", "Found %d servers with name '%s'. This is likely to cause errors.", len(serverList), name)
+	if count > 1 {
+		record.Warnf(eventObject, "DuplicateServerNames", "Found %d servers with name '%s'. This is likely to cause errors.", count, name)
+	}
+
+	if server == nil {
+		return nil, nil
 	}
+	return &InstanceStatus{server, s.scope.Logger}, nil
+}
+
+// instanceCreateTimeout returns how long to wait for a server and its root
+// volume to become active.
+func (s *Service) instanceCreateTimeout() time.Duration {
+	if s.scope.Timeouts.InstanceCreate > 0 {
+		return s.scope.Timeouts.InstanceCreate
+	}
+	return getTimeout("CLUSTER_API_OPENSTACK_INSTANCE_CREATE_TIMEOUT", timeoutInstanceCreate) * time.Minute
+}
 
-	// Return the first returned server, if any
-	for i := range serverList {
-		return &InstanceStatus{&serverList[i], s.scope.Logger}, nil
+// instanceDeleteTimeout returns how long to wait for a deleted server and its
+// root volume to be gone.
+func (s *Service) instanceDeleteTimeout() time.Duration {
+	if s.scope.Timeouts.Deletion > 0 {
+		return s.scope.Timeouts.Deletion
 	}
-	return nil, nil
+	return timeoutInstanceDelete
 }
 
 func getTimeout(name string, timeout int) time.Duration {
@@ -18,6 +18,7 @@ package compute
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
@@ -43,7 +45,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
@@ -115,6 +117,143 @@ func Test_getPortName(t *testing.T) {
 	}
 }
 
+func Test_primaryPortName(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceSpec InstanceSpec
+		want         string
+	}{
+		{
+			name:         "Default port",
+			instanceSpec: InstanceSpec{Name: "bastion"},
+			want:         "bastion-0",
+		},
+		{
+			name: "Networks before ports",
+			instanceSpec: InstanceSpec{
+				Name:     "bastion",
+				Networks: []infrav1.NetworkParam{{Filter: infrav1.NetworkFilter{ID: "network-id"}}},
+				Ports:    []infrav1.PortOpts{{NameSuffix: "management"}},
+			},
+			want: "bastion-0",
+		},
+		{
+			name: "First port with name suffix",
+			instanceSpec: InstanceSpec{
+				Name:  "bastion",
+				Ports: []infrav1.PortOpts{{NameSuffix: "management"}, {NameSuffix: "cluster"}},
+			},
+			want: "bastion-management",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			instanceSpec := tt.instanceSpec
+			g.Expect(primaryPortName(&instanceSpec)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_managementPort(t *testing.T) {
+	const (
+		clusterNetworkID = "cluster-network-id"
+		otherNetworkID   = "other-network-id"
+	)
+	port := func(name, networkID, ip string) ports.Port {
+		return ports.Port{
+			ID:        name + "-id",
+			Name:      name,
+			NetworkID: networkID,
+			FixedIPs:  []ports.IP{{IPAddress: ip}},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		instanceSpec InstanceSpec
+		portList     []ports.Port
+		ip           string
+		wantPortID   string
+	}{
+		{
+			name:         "Single port on the cluster network",
+			instanceSpec: InstanceSpec{Name: "machine"},
+			portList: []ports.Port{
+				port("machine-0", clusterNetworkID, "10.0.0.10"),
+				port("machine-1", otherNetworkID, "192.168.0.10"),
+			},
+			ip:         "10.0.0.10",
+			wantPortID: "machine-0-id",
+		},
+		{
+			name: "Primary port on the cluster network",
+			instanceSpec: InstanceSpec{
+				Name:  "machine",
+				Ports: []infrav1.PortOpts{{NameSuffix: "management"}, {NameSuffix: "storage", VNICType: "direct"}},
+			},
+			portList: []ports.Port{
+				port("machine-storage", clusterNetworkID, "10.0.0.11"),
+				port("machine-management", clusterNetworkID, "10.0.0.10"),
+			},
+			ip:         "10.0.0.11",
+			wantPortID: "machine-management-id",
+		},
+		{
+			name: "Primary port on another network",
+			instanceSpec: InstanceSpec{
+				Name:  "machine",
+				Ports: []infrav1.PortOpts{{NameSuffix: "external"}, {NameSuffix: "management"}, {NameSuffix: "storage"}},
+			},
+			portList: []ports.Port{
+				port("machine-external", otherNetworkID, "192.168.0.10"),
+				port("machine-storage", clusterNetworkID, "10.0.0.11"),
+				port("machine-management", clusterNetworkID, "10.0.0.10"),
+			},
+			ip:         "10.0.0.10",
+			wantPortID: "machine-management-id",
+		},
+		{
+			name: "Existing primary port on the cluster network",
+			instanceSpec: InstanceSpec{
+				Name:  "machine",
+				Ports: []infrav1.PortOpts{{ID: "byo-port-id"}, {NameSuffix: "storage", VNICType: "direct"}},
+			},
+			portList: []ports.Port{
+				port("machine-storage", clusterNetworkID, "10.0.0.11"),
+				port("byo-port", clusterNetworkID, "10.0.0.10"),
+			},
+			ip:         "10.0.0.11",
+			wantPortID: "byo-port-id",
+		},
+		{
+			name:         "No port on the cluster network",
+			instanceSpec: InstanceSpec{Name: "machine"},
+			portList: []ports.Port{
+				port("machine-0", otherNetworkID, "192.168.0.10"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			got := managementPort(&tt.instanceSpec, tt.portList, clusterNetworkID, tt.ip)
+			if tt.wantPortID == "" {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).NotTo(BeNil())
+			g.Expect(got.ID).To(Equal(tt.wantPortID))
+		})
+	}
+}
+
+func TestPortIP(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(PortIP(&ports.Port{FixedIPs: []ports.IP{{IPAddress: "2001:db8::10"}, {IPAddress: "10.0.0.10"}}})).To(Equal("10.0.0.10"))
+	g.Expect(PortIP(&ports.Port{FixedIPs: []ports.IP{{IPAddress: "2001:db8::10"}}})).To(BeEmpty())
+}
+
 func TestService_getServerNetworks(t *testing.T) {
 	const testClusterTag = "cluster=mycluster"
 
@@ -173,9 +312,9 @@ func TestService_getServerNetworks(t *testing.T) {
 
 	// Define arbitrary test network and subnet filters for use in multiple tests,
 	// the gophercloud ListOpts they should translate to, and the arbitrary returned networks/subnets.
-	testNetworkFilter := infrav1.NetworkFilter{Tags: testClusterTag}
+	testNetworkFilter := infrav1.NetworkFilter{FilterByNeutronTags: infrav1.FilterByNeutronTags{Tags: []infrav1.NeutronTag{testClusterTag}}}
 	testNetworkListOpts := networks.ListOpts{Tags: testClusterTag}
-	testSubnetFilter := infrav1.SubnetFilter{Tags: testClusterTag}
+	testSubnetFilter := infrav1.SubnetFilter{FilterByNeutronTags: infrav1.FilterByNeutronTags{Tags: []infrav1.NeutronTag{testClusterTag}}}
 	testSubnetListOpts := subnets.ListOpts{Tags: testClusterTag}
 
 	tests := []struct {
@@ -213,6 +352,37 @@ func TestService_getServerNetworks(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Network and subnet filter results are ordered by name",
+			networkParams: []infrav1.NetworkParam{
+				{
+					Filter: testNetworkFilter,
+					Subnets: []infrav1.SubnetParam{
+						{Filter: testSubnetFilter},
+					},
+				},
+			},
+			want: []infrav1.Network{
+				{ID: networkAUUID, Subnet: &infrav1.Subnet{ID: subnetA1UUID}},
+				{ID: networkAUUID, Subnet: &infrav1.Subnet{ID: subnetA2UUID}},
+				{ID: networkBUUID, Subnet: &infrav1.Subnet{ID: subnetB1UUID}},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(&testNetworkListOpts).
+					Return([]networks.Network{testNetworkB, testNetworkA}, nil)
+
+				networkAFilter := testSubnetListOpts
+				networkAFilter.NetworkID = networkAUUID
+				m.ListSubnet(&networkAFilter).
+					Return([]subnets.Subnet{testSubnetA2, testSubnetA1}, nil)
+
+				networkBFilter := testSubnetListOpts
+				networkBFilter.NetworkID = networkBUUID
+				m.ListSubnet(&networkBFilter).
+					Return([]subnets.Subnet{testSubnetB1}, nil)
+			},
+			wantErr: false,
+		},
 		{
 			name: "Subnet by filter without network",
 			networkParams: []infrav1.NetworkParam{
@@ -444,7 +614,7 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "Return image ID",
 			imageName: "test-image",
 			expect: func(m *MockClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(images.ListOpts{Name: "test-image", Limit: 2}).Return(
 					[]images.Image{{ID: imageIDA, Name: "test-image"}},
 					nil)
 			},
@@ -455,7 +625,7 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "Return no results",
 			imageName: "test-image",
 			expect: func(m *MockClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(images.ListOpts{Name: "test-image", Limit: 2}).Return(
 					[]images.Image{},
 					nil)
 			},
@@ -466,7 +636,7 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "Return multiple results",
 			imageName: "test-image",
 			expect: func(m *MockClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(images.ListOpts{Name: "test-image", Limit: 2}).Return(
 					[]images.Image{
 						{ID: imageIDA, Name: "test-image"},
 						{ID: imageIDB, Name: "test-image"},
@@ -479,7 +649,7 @@ func TestService_getImageID(t *testing.T) {
 			testName:  "OpenStack returns error",
 			imageName: "test-image",
 			expect: func(m *MockClientMockRecorder) {
-				m.ListImages(images.ListOpts{Name: "test-image"}).Return(
+				m.ListImages(images.ListOpts{Name: "test-image", Limit: 2}).Return(
 					nil,
 					fmt.Errorf("test error"))
 			},
@@ -565,7 +735,7 @@ func getDefaultInstanceSpec() *InstanceSpec {
 		FailureDomain:  *pointer.StringPtr(failureDomain),
 		ServerGroupID:  serverGroupUUID,
 		Tags:           []string{"test-tag"},
-		SecurityGroups: []infrav1.SecurityGroupParam{{UUID: workerSecurityGroupUUID}},
+		SecurityGroups: []infrav1.SecurityGroupFilter{{ID: workerSecurityGroupUUID}},
 	}
 }
 
@@ -661,7 +831,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 
 	// Expected calls when using default image and flavor
 	expectDefaultImageAndFlavor := func(computeRecorder *MockClientMockRecorder) {
-		computeRecorder.ListImages(images.ListOpts{Name: imageName}).Return([]images.Image{{ID: imageUUID}}, nil)
+		computeRecorder.ListImages(images.ListOpts{Name: imageName, Limit: 2}).Return([]images.Image{{ID: imageUUID}}, nil)
 		computeRecorder.GetFlavorIDFromName(flavorName).Return(flavorUUID, nil)
 	}
 
@@ -689,7 +859,7 @@ func TestService_ReconcileInstance(t *testing.T) {
 	// Expected calls when polling for server creation
 	expectServerPoll := func(computeRecorder *MockClientMockRecorder, states []string) {
 		for _, state := range states {
-			computeRecorder.GetServer(instanceUUID).Return(returnedServer(state), nil)
+			computeRecorder.ListServers(gomock.Any()).Return([]ServerExt{*returnedServer(state)}, nil)
 		}
 	}
 
@@ -751,6 +921,24 @@ func TestService_ReconcileInstance(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Keep existing ports on server create error",
+			getInstanceSpec: func() *InstanceSpec {
+				s := getDefaultInstanceSpec()
+				s.Ports = []infrav1.PortOpts{{ID: portUUID}}
+				return s
+			},
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.GetPort(portUUID).Return(&ports.Port{ID: portUUID, NetworkID: networkUUID}, nil)
+				expectDefaultImageAndFlavor(computeRecorder)
+
+				expectCreateServer(computeRecorder, getDefaultServerMap(), true)
+
+				// The existing port is not deleted
+				networkRecorder.ListExtensions()
+			},
+			wantErr: true,
+		},
 		{
 			name: "Delete previously created ports on port creation error",
 			getInstanceSpec: func() *InstanceSpec {
@@ -820,10 +1008,15 @@ func TestService_ReconcileInstance(t *testing.T) {
 				computeRecorder.CreateVolume(volumes.CreateOpts{
 					Size:             50,
 					AvailabilityZone: failureDomain,
-					Description:      fmt.Sprintf("Root volume for %s", openStackMachineName),
+					Description:      fmt.Sprintf("Root volume for server %s created by cluster-api-provider-openstack cluster cluster-name", openStackMachineName),
 					Name:             fmt.Sprintf("%s-root", openStackMachineName),
-					ImageID:          imageUUID,
-					Multiattach:      false,
+					Metadata: map[string]string{
+						"capo-namespace": "",
+						"capo-cluster":   "cluster-name",
+						"capo-server":    openStackMachineName,
+					},
+					ImageID:     imageUUID,
+					Multiattach: false,
 				}).Return(&volumes.Volume{ID: volumeUUID}, nil)
 				expectVolumePollSuccess(computeRecorder)
 
@@ -867,10 +1060,15 @@ func TestService_ReconcileInstance(t *testing.T) {
 					Size:             50,
 					AvailabilityZone: "test-alternate-az",
 					VolumeType:       "test-volume-type",
-					Description:      fmt.Sprintf("Root volume for %s", openStackMachineName),
+					Description:      fmt.Sprintf("Root volume for server %s created by cluster-api-provider-openstack cluster cluster-name", openStackMachineName),
 					Name:             fmt.Sprintf("%s-root", openStackMachineName),
-					ImageID:          imageUUID,
-					Multiattach:      false,
+					Metadata: map[string]string{
+						"capo-namespace": "",
+						"capo-cluster":   "cluster-name",
+						"capo-server":    openStackMachineName,
+					},
+					ImageID:     imageUUID,
+					Multiattach: false,
 				}).Return(&volumes.Volume{ID: volumeUUID}, nil)
 				expectVolumePollSuccess(computeRecorder)
 
@@ -911,10 +1109,15 @@ func TestService_ReconcileInstance(t *testing.T) {
 				computeRecorder.CreateVolume(volumes.CreateOpts{
 					Size:             50,
 					AvailabilityZone: failureDomain,
-					Description:      fmt.Sprintf("Root volume for %s", openStackMachineName),
+					Description:      fmt.Sprintf("Root volume for server %s created by cluster-api-provider-openstack cluster cluster-name", openStackMachineName),
 					Name:             fmt.Sprintf("%s-root", openStackMachineName),
-					ImageID:          imageUUID,
-					Multiattach:      false,
+					Metadata: map[string]string{
+						"capo-namespace": "",
+						"capo-cluster":   "cluster-name",
+						"capo-server":    openStackMachineName,
+					},
+					ImageID:     imageUUID,
+					Multiattach: false,
 				}).Return(&volumes.Volume{ID: volumeUUID}, nil)
 				expectVolumePoll(computeRecorder, []string{"creating", "error"})
 
@@ -944,7 +1147,6 @@ func TestService_ReconcileInstance(t *testing.T) {
 				// Check for existing trunk
 				networkRecorder.ListTrunk(newGomegaMockMatcher(
 					MatchFields(IgnoreExtras, Fields{
-						"Name":   Equal(portName),
 						"PortID": Equal(portUUID),
 					}),
 				)).Return([]trunks.Trunk{}, nil)
@@ -1052,16 +1254,68 @@ func TestService_DeleteInstance(t *testing.T) {
 						Alias: "trunk",
 					},
 				}}, nil)
+				// The port is detached and deleted before the server, so that
+				// it is not leaked if deleting the server fails
+				gomock.InOrder(
+					computeRecorder.DeleteAttachedInterface(instanceUUID, portUUID).Return(nil),
+					networkRecorder.GetPort(portUUID).Return(&ports.Port{ID: portUUID}, nil),
+					// FIXME: Why we are looking for a trunk when we know the port is not trunked?
+					networkRecorder.ListTrunk(trunks.ListOpts{PortID: portUUID}).Return([]trunks.Trunk{}, nil),
+					networkRecorder.DeletePort(portUUID).Return(nil),
+					computeRecorder.DeleteServer(instanceUUID).Return(nil),
+					computeRecorder.GetServer(instanceUUID).Return(nil, gophercloud.ErrDefault404{}),
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name:        "Existing port is kept",
+			eventObject: &infrav1.OpenStackMachine{},
+			instanceSpec: func() *InstanceSpec {
+				spec := getDefaultInstanceSpec()
+				spec.Ports = []infrav1.PortOpts{{ID: portUUID}}
+				return spec
+			},
+			instanceStatus: getDefaultInstanceStatus,
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				computeRecorder.ListAttachedInterfaces(instanceUUID).Return([]attachinterfaces.Interface{
+					{
+						PortID: portUUID,
+					},
+				}, nil)
+				networkRecorder.ListExtensions().Return([]extensions.Extension{}, nil)
 				computeRecorder.DeleteAttachedInterface(instanceUUID, portUUID).Return(nil)
-				// FIXME: Why we are looking for a trunk when we know the port is not trunked?
-				networkRecorder.ListTrunk(trunks.ListOpts{PortID: portUUID}).Return([]trunks.Trunk{}, nil)
-				networkRecorder.DeletePort(portUUID).Return(nil)
-
 				computeRecorder.DeleteServer(instanceUUID).Return(nil)
 				computeRecorder.GetServer(instanceUUID).Return(nil, gophercloud.ErrDefault404{})
 			},
 			wantErr: false,
 		},
+		{
+			name:        "Root volume",
+			eventObject: &infrav1.OpenStackMachine{},
+			instanceSpec: func() *InstanceSpec {
+				spec := getDefaultInstanceSpec()
+				spec.RootVolume = &infrav1.RootVolume{
+					Size: 50,
+				}
+				return spec
+			},
+			instanceStatus: getDefaultInstanceStatus,
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				computeRecorder.ListAttachedInterfaces(instanceUUID).Return([]attachinterfaces.Interface{}, nil)
+				networkRecorder.ListExtensions().Return([]extensions.Extension{}, nil)
+				computeRecorder.DeleteServer(instanceUUID).Return(nil)
+				computeRecorder.GetServer(instanceUUID).Return(nil, gophercloud.ErrDefault404{})
+
+				// The root volume was deleted with the server
+				computeRecorder.ListVolumes(volumes.ListOpts{
+					AllTenants: false,
+					Name:       fmt.Sprintf("%s-root", openStackMachineName),
+					TenantID:   "",
+				}).Return([]volumes.Volume{}, nil)
+			},
+			wantErr: false,
+		},
 		{
 			name:        "Dangling volume",
 			eventObject: &infrav1.OpenStackMachine{},
@@ -1081,13 +1335,66 @@ func TestService_DeleteInstance(t *testing.T) {
 					Name:       volumeName,
 					TenantID:   "",
 				}).Return([]volumes.Volume{{
-					ID:   volumeUUID,
-					Name: volumeName,
+					ID:     volumeUUID,
+					Name:   volumeName,
+					Status: "available",
 				}}, nil)
 
 				// Delete volume
 				computeRecorder.DeleteVolume(volumeUUID, volumes.DeleteOpts{}).Return(nil)
 			},
+			// The deletion is retried until the volume is gone
+			wantErr: true,
+		},
+		{
+			name:        "Root volume being deleted",
+			eventObject: &infrav1.OpenStackMachine{},
+			instanceSpec: func() *InstanceSpec {
+				spec := getDefaultInstanceSpec()
+				spec.RootVolume = &infrav1.RootVolume{
+					Size: 50,
+				}
+				return spec
+			},
+			instanceStatus: getDefaultInstanceStatus,
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				computeRecorder.ListAttachedInterfaces(instanceUUID).Return([]attachinterfaces.Interface{}, nil)
+				networkRecorder.ListExtensions().Return([]extensions.Extension{}, nil)
+				computeRecorder.DeleteServer(instanceUUID).Return(nil)
+				computeRecorder.GetServer(instanceUUID).Return(nil, gophercloud.ErrDefault404{})
+
+				// The volume is not waited for
+				volumeName := fmt.Sprintf("%s-root", openStackMachineName)
+				computeRecorder.ListVolumes(volumes.ListOpts{
+					AllTenants: false,
+					Name:       volumeName,
+					TenantID:   "",
+				}).Return([]volumes.Volume{{
+					ID:     volumeUUID,
+					Name:   volumeName,
+					Status: "deleting",
+				}}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:        "Retained root volume",
+			eventObject: &infrav1.OpenStackMachine{},
+			instanceSpec: func() *InstanceSpec {
+				spec := getDefaultInstanceSpec()
+				spec.RootVolume = &infrav1.RootVolume{
+					Size: 50,
+				}
+				spec.RetainRootVolume = true
+				return spec
+			},
+			instanceStatus: getDefaultInstanceStatus,
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				computeRecorder.ListAttachedInterfaces(instanceUUID).Return([]attachinterfaces.Interface{}, nil)
+				networkRecorder.ListExtensions().Return([]extensions.Extension{}, nil)
+				computeRecorder.DeleteServer(instanceUUID).Return(nil)
+				computeRecorder.GetServer(instanceUUID).Return(nil, gophercloud.ErrDefault404{})
+			},
 			wantErr: false,
 		},
 	}
@@ -1112,9 +1419,376 @@ func TestService_DeleteInstance(t *testing.T) {
 					"", mockNetworkClient, logr.Discard(),
 				),
 			}
-			if err := s.DeleteInstance(tt.eventObject, tt.instanceSpec(), tt.instanceStatus()); (err != nil) != tt.wantErr {
+			err := s.DeleteInstance(tt.eventObject, tt.instanceSpec(), tt.instanceStatus())
+			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.DeleteInstance() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if err != nil && !errors.Is(err, ErrDeletionInProgress) {
+				t.Errorf("Service.DeleteInstance() error = %v, want %v", err, ErrDeletionInProgress)
+			}
+		})
+	}
+}
+
+func TestService_AdoptInstance(t *testing.T) {
+	RegisterTestingT(t)
+
+	tests := []struct {
+		name    string
+		expect  func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "Adds missing security groups to attached ports",
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				computeRecorder.GetServer(instanceUUID).Return(&ServerExt{
+					Server: servers.Server{
+						ID:     instanceUUID,
+						Name:   "existing-server",
+						Status: "ACTIVE",
+					},
+				}, nil)
+				computeRecorder.ListAttachedInterfaces(instanceUUID).Return([]attachinterfaces.Interface{
+					{PortID: portUUID},
+				}, nil)
+				networkRecorder.GetPort(portUUID).Return(&ports.Port{
+					ID:             portUUID,
+					SecurityGroups: []string{"default"},
+				}, nil)
+				networkRecorder.UpdatePort(portUUID, ports.UpdateOpts{
+					SecurityGroups: &[]string{"default", workerSecurityGroupUUID},
+				}).Return(&ports.Port{}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Port already has security groups",
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				computeRecorder.GetServer(instanceUUID).Return(&ServerExt{
+					Server: servers.Server{
+						ID:     instanceUUID,
+						Status: "ACTIVE",
+					},
+				}, nil)
+				computeRecorder.ListAttachedInterfaces(instanceUUID).Return([]attachinterfaces.Interface{
+					{PortID: portUUID},
+				}, nil)
+				networkRecorder.GetPort(portUUID).Return(&ports.Port{
+					ID:             portUUID,
+					SecurityGroups: []string{workerSecurityGroupUUID},
+				}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "Server does not exist",
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				computeRecorder.GetServer(instanceUUID).Return(nil, gophercloud.ErrDefault404{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := NewMockClient(mockCtrl)
+			mockNetworkClient := mock_networking.NewMockNetworkClient(mockCtrl)
+
+			tt.expect(mockComputeClient.EXPECT(), mockNetworkClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{
+					ProjectID: "",
+					Logger:    logr.Discard(),
+				},
+				computeService: mockComputeClient,
+				networkingService: networking.NewTestService(
+					"", mockNetworkClient, logr.Discard(),
+				),
+			}
+			instanceStatus, err := s.AdoptInstance(&infrav1.OpenStackMachine{}, getDefaultInstanceSpec(), instanceUUID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.AdoptInstance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				Expect(instanceStatus.ID()).To(Equal(instanceUUID))
+			}
+		})
+	}
+}
+
+func TestService_RebootInstance(t *testing.T) {
+	tests := []struct {
+		name    string
+		hard    bool
+		expect  func(computeRecorder *MockClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "Soft reboot",
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.RebootServer(instanceUUID, servers.RebootOpts{Type: servers.SoftReboot}).Return(nil)
+			},
+		},
+		{
+			name: "Hard reboot",
+			hard: true,
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.RebootServer(instanceUUID, servers.RebootOpts{Type: servers.HardReboot}).Return(nil)
+			},
+		},
+		{
+			name: "Server not found",
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.RebootServer(instanceUUID, servers.RebootOpts{Type: servers.SoftReboot}).Return(gophercloud.ErrDefault404{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := NewMockClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				computeService: mockComputeClient,
+			}
+			instance := &InstanceIdentifier{ID: instanceUUID, Name: openStackMachineName}
+			if err := s.RebootInstance(&infrav1.OpenStackMachine{}, instance, tt.hard); (err != nil) != tt.wantErr {
+				t.Errorf("Service.RebootInstance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ReconcileRootVolumeSize(t *testing.T) {
+	listOpts := volumes.ListOpts{Name: openStackMachineName + "-root", TenantID: "test-project"}
+
+	tests := []struct {
+		name       string
+		rootVolume *infrav1.RootVolume
+		expect     func(computeRecorder *MockClientMockRecorder)
+		wantErr    bool
+	}{
+		{
+			name:   "No root volume",
+			expect: func(computeRecorder *MockClientMockRecorder) {},
+		},
+		{
+			name:       "Root volume has the size of the spec",
+			rootVolume: &infrav1.RootVolume{Size: 50},
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.ListVolumes(listOpts).Return([]volumes.Volume{{ID: volumeUUID, Size: 50}}, nil)
+			},
+		},
+		{
+			name:       "Root volume is extended",
+			rootVolume: &infrav1.RootVolume{Size: 100},
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.ListVolumes(listOpts).Return([]volumes.Volume{{ID: volumeUUID, Size: 50}}, nil)
+				computeRecorder.ExtendVolume(volumeUUID, 100).Return(nil)
+			},
+		},
+		{
+			name:       "Larger root volume is not shrunk",
+			rootVolume: &infrav1.RootVolume{Size: 50},
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.ListVolumes(listOpts).Return([]volumes.Volume{{ID: volumeUUID, Size: 100}}, nil)
+			},
+		},
+		{
+			name:       "Extending the root volume fails",
+			rootVolume: &infrav1.RootVolume{Size: 100},
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.ListVolumes(listOpts).Return([]volumes.Volume{{ID: volumeUUID, Size: 50}}, nil)
+				computeRecorder.ExtendVolume(volumeUUID, 100).Return(gophercloud.ErrDefault400{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := NewMockClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard(), ProjectID: "test-project"},
+				computeService: mockComputeClient,
+			}
+			instanceSpec := &InstanceSpec{Name: openStackMachineName, RootVolume: tt.rootVolume}
+			if err := s.ReconcileRootVolumeSize(&infrav1.OpenStackServer{}, instanceSpec); (err != nil) != tt.wantErr {
+				t.Errorf("Service.ReconcileRootVolumeSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_DeleteOrphanedVolumes(t *testing.T) {
+	listOpts := volumes.ListOpts{
+		TenantID: "test-project",
+		Metadata: map[string]string{"capo-namespace": "test-namespace"},
+	}
+	orphanedVolume := func(id, cluster, server string) volumes.Volume {
+		return volumes.Volume{
+			ID:     id,
+			Status: "available",
+			Metadata: map[string]string{
+				"capo-namespace": "test-namespace",
+				"capo-cluster":   cluster,
+				"capo-server":    server,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		expect  func(computeRecorder *MockClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "Orphaned volumes of the cluster and its bastion are deleted",
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.ListVolumes(listOpts).Return([]volumes.Volume{
+					orphanedVolume("machine-volume", "cluster-name", "orphaned"),
+					orphanedVolume("bastion-volume", "test-namespace-cluster-name", "cluster-name-bastion"),
+				}, nil)
+				computeRecorder.DeleteVolume("machine-volume", volumes.DeleteOpts{}).Return(nil)
+				computeRecorder.DeleteVolume("bastion-volume", volumes.DeleteOpts{}).Return(nil)
+			},
+		},
+		{
+			name: "Volumes of other clusters, managed servers and attached volumes are kept",
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				attached := orphanedVolume("attached-volume", "cluster-name", "orphaned")
+				attached.Attachments = []volumes.Attachment{{ServerID: instanceUUID}}
+				creating := orphanedVolume("creating-volume", "cluster-name", "orphaned")
+				creating.Status = "creating"
+				computeRecorder.ListVolumes(listOpts).Return([]volumes.Volume{
+					orphanedVolume("other-volume", "other-cluster", "orphaned"),
+					orphanedVolume("managed-volume", "cluster-name", "managed"),
+					attached,
+					creating,
+				}, nil)
+			},
+		},
+		{
+			name: "Deleting a volume fails",
+			expect: func(computeRecorder *MockClientMockRecorder) {
+				computeRecorder.ListVolumes(listOpts).Return([]volumes.Volume{
+					orphanedVolume("machine-volume", "cluster-name", "orphaned"),
+				}, nil)
+				computeRecorder.DeleteVolume("machine-volume", volumes.DeleteOpts{}).Return(gophercloud.ErrDefault400{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := NewMockClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard(), ProjectID: "test-project"},
+				computeService: mockComputeClient,
+			}
+			isManaged := func(serverName string) bool { return serverName == "managed" }
+			if err := s.DeleteOrphanedVolumes(&infrav1.OpenStackCluster{}, "test-namespace", "cluster-name", isManaged); (err != nil) != tt.wantErr {
+				t.Errorf("Service.DeleteOrphanedVolumes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_GetInstanceResourceIDs(t *testing.T) {
+	const (
+		secondPortUUID = "a1b2c3d4-0000-4000-8000-000000000002"
+		trunkUUID      = "a1b2c3d4-0000-4000-8000-000000000003"
+		fipUUID        = "a1b2c3d4-0000-4000-8000-000000000004"
+	)
+
+	instanceStatus := &InstanceStatus{
+		server: &ServerExt{
+			Server: servers.Server{
+				ID: instanceUUID,
+				AttachedVolumes: []servers.AttachedVolume{
+					{ID: volumeUUID},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		trunk   bool
+		expect  func(networkRecorder *mock_networking.MockNetworkClientMockRecorder)
+		want    *infrav1.InstanceResourceIDs
+		wantErr bool
+	}{
+		{
+			name: "Ports with floating IP",
+			expect: func(networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceUUID}).Return([]ports.Port{
+					{ID: secondPortUUID},
+					{ID: portUUID},
+				}, nil)
+				networkRecorder.ListFloatingIP(floatingips.ListOpts{PortID: secondPortUUID}).Return([]floatingips.FloatingIP{}, nil)
+				networkRecorder.ListFloatingIP(floatingips.ListOpts{PortID: portUUID}).Return([]floatingips.FloatingIP{{ID: fipUUID}}, nil)
+			},
+			want: &infrav1.InstanceResourceIDs{
+				ServerID:      instanceUUID,
+				PortIDs:       []string{secondPortUUID, portUUID},
+				FloatingIPIDs: []string{fipUUID},
+				VolumeIDs:     []string{volumeUUID},
+			},
+		},
+		{
+			name:  "Trunk port",
+			trunk: true,
+			expect: func(networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceUUID}).Return([]ports.Port{{ID: portUUID}}, nil)
+				networkRecorder.ListTrunk(trunks.ListOpts{PortID: portUUID}).Return([]trunks.Trunk{{ID: trunkUUID}}, nil)
+				networkRecorder.ListFloatingIP(floatingips.ListOpts{PortID: portUUID}).Return([]floatingips.FloatingIP{}, nil)
+			},
+			want: &infrav1.InstanceResourceIDs{
+				ServerID:  instanceUUID,
+				PortIDs:   []string{portUUID},
+				TrunkIDs:  []string{trunkUUID},
+				VolumeIDs: []string{volumeUUID},
+			},
+		},
+		{
+			name: "Listing ports fails",
+			expect: func(networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceUUID}).Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockNetworkClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockNetworkClient.EXPECT())
+
+			s := Service{
+				scope: &scope.Scope{Logger: logr.Discard()},
+				networkingService: networking.NewTestService(
+					"", mockNetworkClient, logr.Discard(),
+				),
+			}
+			got, err := s.GetInstanceResourceIDs(instanceStatus, tt.trunk)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
 		})
 	}
 }
@@ -20,11 +20,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
 )
 
 // InstanceSpec defines the fields which can be set on a new OpenStack instance.
@@ -32,23 +34,26 @@ import (
 // InstanceSpec does not contain all of the fields of infrav1.Instance, as not
 // all of them can be set on a new instance.
 type InstanceSpec struct {
-	Name           string
-	Image          string
-	ImageUUID      string
-	Flavor         string
-	SSHKeyName     string
-	UserData       string
-	Metadata       map[string]string
-	ConfigDrive    bool
-	FailureDomain  string
-	RootVolume     *infrav1.RootVolume
-	Subnet         string
-	ServerGroupID  string
-	Trunk          bool
-	Tags           []string
-	SecurityGroups []infrav1.SecurityGroupParam
-	Networks       []infrav1.NetworkParam
-	Ports          []infrav1.PortOpts
+	Name          string
+	Image         string
+	ImageUUID     string
+	Flavor        string
+	SSHKeyName    string
+	UserData      string
+	Metadata      map[string]string
+	ConfigDrive   bool
+	FailureDomain string
+	StorageAZ     string
+	RootVolume    *infrav1.RootVolume
+	// RetainRootVolume keeps the root volume when the instance is deleted.
+	RetainRootVolume bool
+	Subnet           string
+	ServerGroupID    string
+	Trunk            bool
+	Tags             []string
+	SecurityGroups   []infrav1.SecurityGroupFilter
+	Networks         []infrav1.NetworkParam
+	Ports            []infrav1.PortOpts
 }
 
 // InstanceIdentifier describes an instance which has not necessarily been fetched.
@@ -92,6 +97,17 @@ func (is *InstanceStatus) State() infrav1.InstanceState {
 	return infrav1.InstanceState(is.server.Status)
 }
 
+// IsUnschedulable returns whether the instance is in error because the
+// scheduler of Nova found no host with capacity for it.
+func (is *InstanceStatus) IsUnschedulable() bool {
+	return is.State() == infrav1.InstanceStateError && strings.Contains(is.server.Fault.Message, "No valid host was found")
+}
+
+// FaultMessage returns the message of the fault of an instance in error.
+func (is *InstanceStatus) FaultMessage() string {
+	return is.server.Fault.Message
+}
+
 func (is *InstanceStatus) SSHKeyName() string {
 	return is.server.KeyName
 }
@@ -100,6 +116,15 @@ func (is *InstanceStatus) AvailabilityZone() string {
 	return is.server.AvailabilityZone
 }
 
+// AttachedVolumeIDs returns the IDs of the volumes attached to the instance.
+func (is *InstanceStatus) AttachedVolumeIDs() []string {
+	var ids []string
+	for _, volume := range is.server.AttachedVolumes {
+		ids = append(ids, volume.ID)
+	}
+	return ids
+}
+
 // APIInstance returns an infrav1.Instance object for use by the API.
 func (is *InstanceStatus) APIInstance(openStackCluster *infrav1.OpenStackCluster) (*infrav1.Instance, error) {
 	i := infrav1.Instance{
@@ -157,7 +182,7 @@ func (is *InstanceStatus) NetworkStatus() (*InstanceNetworkStatus, error) {
 
 			// Only consider IPv4
 			if address.Version != 4 {
-				is.logger.V(6).Info("Ignoring IP address: only IPv4 is supported", "version", address.Version, "address", address.Address)
+				is.logger.V(logging.LevelTrace).Info("Ignoring IP address: only IPv4 is supported", "version", address.Version, "address", address.Address)
 				continue
 			}
 
@@ -168,7 +193,7 @@ func (is *InstanceStatus) NetworkStatus() (*InstanceNetworkStatus, error) {
 			case "fixed":
 				addressType = corev1.NodeInternalIP
 			default:
-				is.logger.V(6).Info("Ignoring address with unknown type", "address", address.Address, "type", address.Type)
+				is.logger.V(logging.LevelTrace).Info("Ignoring address with unknown type", "address", address.Address, "type", address.Type)
 				continue
 			}
 
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// requestedInstanceActions are the instance actions which the controllers
+// perform themselves: creating, rebuilding, rebooting and deleting servers
+// and changing their interfaces.
+var requestedInstanceActions = sets.NewString("create", "rebuild", "reboot", "delete", "attach_interface", "detach_interface")
+
+// GetUnrequestedInstanceActions returns the actions performed on the instance
+// after since which were not requested by the controllers, ordered by their
+// start time, e.g. live migrations by the cloud operator or a stop by another
+// user of the project.
+func (s *Service) GetUnrequestedInstanceActions(instanceID string, since time.Time) ([]instanceactions.InstanceAction, error) {
+	actions, err := s.computeService.ListInstanceActions(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actions of instance %q: %w", instanceID, err)
+	}
+	return unrequestedInstanceActions(actions, since, s.scope.UserID()), nil
+}
+
+// unrequestedInstanceActions filters the actions which started after since and
+// were not requested by the controllers. Actions the controllers perform
+// themselves are only theirs if they were started by their user, or if that
+// user is not known.
+func unrequestedInstanceActions(actions []instanceactions.InstanceAction, since time.Time, userID string) []instanceactions.InstanceAction {
+	var unrequested []instanceactions.InstanceAction
+	for _, action := range actions {
+		// Start times are only kept with second precision in the status of
+		// the machine
+		if !action.StartTime.Truncate(time.Second).After(since) {
+			continue
+		}
+		if requestedInstanceActions.Has(action.Action) && (userID == "" || action.UserID == userID) {
+			continue
+		}
+		unrequested = append(unrequested, action)
+	}
+	sort.SliceStable(unrequested, func(i, j int) bool {
+		return unrequested[i].StartTime.Before(unrequested[j].StartTime)
+	})
+	return unrequested
+}
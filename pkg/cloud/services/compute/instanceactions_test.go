@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/instanceactions"
+	. "github.com/onsi/gomega"
+)
+
+func Test_unrequestedInstanceActions(t *testing.T) {
+	since := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	action := func(name, userID string, minutes int) instanceactions.InstanceAction {
+		return instanceactions.InstanceAction{Action: name, UserID: userID, RequestID: "req-" + name, StartTime: since.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	// Nova lists the most recent action first
+	actions := []instanceactions.InstanceAction{
+		action("stop", "capo", 40),
+		action("reboot", "operator", 30),
+		action("reboot", "capo", 20),
+		action("live-migration", "admin", 10),
+		action("create", "capo", -10),
+	}
+
+	tests := []struct {
+		name   string
+		userID string
+		want   []instanceactions.InstanceAction
+	}{
+		{
+			name:   "User of the controllers known",
+			userID: "capo",
+			want:   []instanceactions.InstanceAction{actions[3], actions[1], actions[0]},
+		},
+		{
+			name:   "User of the controllers unknown",
+			userID: "",
+			want:   []instanceactions.InstanceAction{actions[3], actions[0]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(unrequestedInstanceActions(actions, since, tt.userID)).To(Equal(tt.want))
+		})
+	}
+}
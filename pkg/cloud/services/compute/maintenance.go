@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"net/http"
+
+	computeservices "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/services"
+
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const (
+	computeServiceBinary         = "nova-compute"
+	computeServiceStatusDisabled = "disabled"
+
+	// serverStatusMigrating is the status of servers which are live migrated.
+	serverStatusMigrating = "MIGRATING"
+)
+
+// GetInstanceMaintenance returns why the given instances are affected by
+// host maintenance, keyed by instance ID. Instances are affected while they
+// are live migrated, or while the compute service of their host is disabled.
+// Compute services and the hosts of instances are only visible to
+// administrators, which is reported by hostsVisible. Without them only
+// migrations are detected.
+func (s *Service) GetInstanceMaintenance(instanceIDs []string) (maintenance map[string]string, hostsVisible bool, err error) {
+	disabledHosts := map[string]string{}
+	computeServices, err := s.computeService.ListComputeServices(computeservices.ListOpts{Binary: computeServiceBinary})
+	if err != nil {
+		if code, ok := capoerrors.StatusCode(err); !ok || code != http.StatusForbidden {
+			return nil, false, fmt.Errorf("failed to list compute services: %w", err)
+		}
+	} else {
+		hostsVisible = true
+		for _, computeService := range computeServices {
+			if computeService.Status == computeServiceStatusDisabled {
+				disabledHosts[computeService.Host] = computeService.DisabledReason
+			}
+		}
+	}
+
+	maintenance = map[string]string{}
+	for _, instanceID := range instanceIDs {
+		server, err := s.computeService.GetServer(instanceID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, hostsVisible, fmt.Errorf("get server %q detail failed: %w", instanceID, err)
+		}
+
+		if server.Status == serverStatusMigrating {
+			maintenance[instanceID] = "instance is being migrated"
+			continue
+		}
+		if reason, ok := disabledHosts[server.Host]; ok && server.Host != "" {
+			if reason == "" {
+				reason = "no reason given"
+			}
+			maintenance[instanceID] = fmt.Sprintf("compute host %s is disabled: %s", server.Host, reason)
+		}
+	}
+	return maintenance, hostsVisible, nil
+}
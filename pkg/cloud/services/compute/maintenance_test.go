@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/extendedserverattributes"
+	computeservices "github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/services"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_GetInstanceMaintenance(t *testing.T) {
+	serverOn := func(id, status, host string) *ServerExt {
+		return &ServerExt{
+			Server:              servers.Server{ID: id, Status: status},
+			ServerAttributesExt: extendedserverattributes.ServerAttributesExt{Host: host},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		expect           func(m *MockClientMockRecorder)
+		wantMaintenance  map[string]string
+		wantHostsVisible bool
+		wantErr          bool
+	}{
+		{
+			name: "Disabled hosts and migrations",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListComputeServices(computeservices.ListOpts{Binary: "nova-compute"}).Return([]computeservices.Service{
+					{Host: "host-1", Status: "enabled"},
+					{Host: "host-2", Status: "disabled", DisabledReason: "kernel update"},
+					{Host: "host-3", Status: "disabled"},
+				}, nil)
+				m.GetServer("instance-1").Return(serverOn("instance-1", "ACTIVE", "host-1"), nil)
+				m.GetServer("instance-2").Return(serverOn("instance-2", "ACTIVE", "host-2"), nil)
+				m.GetServer("instance-3").Return(serverOn("instance-3", "ACTIVE", "host-3"), nil)
+				m.GetServer("instance-4").Return(serverOn("instance-4", "MIGRATING", "host-1"), nil)
+				m.GetServer("instance-5").Return(nil, gophercloud.ErrDefault404{})
+			},
+			wantMaintenance: map[string]string{
+				"instance-2": "compute host host-2 is disabled: kernel update",
+				"instance-3": "compute host host-3 is disabled: no reason given",
+				"instance-4": "instance is being migrated",
+			},
+			wantHostsVisible: true,
+		},
+		{
+			name: "Compute services are forbidden",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListComputeServices(computeservices.ListOpts{Binary: "nova-compute"}).Return(nil, gophercloud.ErrDefault403{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 403}})
+				m.GetServer("instance-1").Return(serverOn("instance-1", "ACTIVE", ""), nil)
+				m.GetServer("instance-2").Return(serverOn("instance-2", "ACTIVE", ""), nil)
+				m.GetServer("instance-3").Return(serverOn("instance-3", "ACTIVE", ""), nil)
+				m.GetServer("instance-4").Return(serverOn("instance-4", "MIGRATING", ""), nil)
+				m.GetServer("instance-5").Return(serverOn("instance-5", "ACTIVE", ""), nil)
+			},
+			wantMaintenance: map[string]string{
+				"instance-4": "instance is being migrated",
+			},
+			wantHostsVisible: false,
+		},
+		{
+			name: "Listing compute services fails",
+			expect: func(m *MockClientMockRecorder) {
+				m.ListComputeServices(computeservices.ListOpts{Binary: "nova-compute"}).Return(nil, gophercloud.ErrDefault500{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 500}})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockComputeClient := NewMockClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				computeService: mockComputeClient,
+			}
+			maintenance, hostsVisible, err := s.GetInstanceMaintenance([]string{"instance-1", "instance-2", "instance-3", "instance-4", "instance-5"})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(maintenance).To(Equal(tt.wantMaintenance))
+			g.Expect(hostsVisible).To(Equal(tt.wantHostsVisible))
+		})
+	}
+}
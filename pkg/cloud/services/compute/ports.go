@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// hotPluggablePorts returns the ports of the instance spec which can be
+// attached to and detached from a running instance.
+func hotPluggablePorts(instanceSpec *InstanceSpec) []infrav1.PortOpts {
+	var hotPluggable []infrav1.PortOpts
+	for i := range instanceSpec.Ports {
+		if infrav1.IsHotPluggablePort(instanceSpec.Networks, instanceSpec.Ports, i) {
+			hotPluggable = append(hotPluggable, instanceSpec.Ports[i])
+		}
+	}
+	return hotPluggable
+}
+
+// ReconcileInstancePorts attaches the hot pluggable ports of the instance
+// spec which are not attached to the instance yet, creating them if needed.
+// The ports with the name suffixes in attachedSuffixes which were removed from
+// the spec are detached and deleted, and the security groups of the other
+// ports are updated to match the spec. It returns the name suffixes of the hot
+// pluggable ports attached to the instance, also if it fails to attach or
+// detach some of them.
+func (s *Service) ReconcileInstancePorts(eventObject runtime.Object, instanceSpec *InstanceSpec, instanceID, clusterName string, attachedSuffixes []string) ([]string, error) {
+	attached := sets.NewString(attachedSuffixes...)
+
+	portList, err := s.networkingService.ListPortsForInstance(instanceID)
+	if err != nil {
+		return attached.List(), err
+	}
+	instancePorts := map[string]ports.Port{}
+	for _, port := range portList {
+		instancePorts[port.Name] = port
+	}
+
+	desired := sets.NewString()
+	for _, port := range hotPluggablePorts(instanceSpec) {
+		desired.Insert(port.NameSuffix)
+	}
+
+	for _, suffix := range attached.List() {
+		if desired.Has(suffix) {
+			continue
+		}
+		if port, ok := instancePorts[getPortName(instanceSpec.Name, &infrav1.PortOpts{NameSuffix: suffix}, 0)]; ok {
+			if err := s.detachPort(eventObject, instanceID, port); err != nil {
+				return attached.List(), err
+			}
+		}
+		attached.Delete(suffix)
+	}
+
+	var securityGroups []string
+	securityGroupsResolved := false
+	hotPluggable := hotPluggablePorts(instanceSpec)
+	for i := range hotPluggable {
+		port := &hotPluggable[i]
+		portName := getPortName(instanceSpec.Name, port, 0)
+		if _, ok := instancePorts[portName]; ok {
+			attached.Insert(port.NameSuffix)
+			continue
+		}
+
+		if !securityGroupsResolved {
+			securityGroups, err = s.networkingService.GetSecurityGroups(instanceSpec.SecurityGroups)
+			if err != nil {
+				return attached.List(), fmt.Errorf("error getting security groups: %v", err)
+			}
+			securityGroupsResolved = true
+		}
+		if port.Trunk == nil {
+			port.Trunk = &instanceSpec.Trunk
+		}
+		if *port.Trunk {
+			trunkSupported, err := s.isTrunkExtSupported()
+			if err != nil {
+				return attached.List(), err
+			}
+			if !trunkSupported {
+				return attached.List(), fmt.Errorf("there is no trunk support. please ensure that the trunk extension is enabled in your OpenStack deployment")
+			}
+		}
+		net, err := s.getPortNetwork(nil, port)
+		if err != nil {
+			return attached.List(), err
+		}
+		if err := s.attachPort(eventObject, instanceID, clusterName, instanceSpec.Name, portName, net, securityGroups, instanceSpec.Tags); err != nil {
+			return attached.List(), err
+		}
+		attached.Insert(port.NameSuffix)
+	}
+
+	if err := s.reconcilePortSecurityGroups(eventObject, instanceSpec, instancePorts); err != nil {
+		return attached.List(), err
+	}
+	return attached.List(), nil
+}
+
+// reconcilePortSecurityGroups sets the security groups of the ports of the
+// instance spec which already exist on the instance, so that changes to the
+// securityGroupFilters of a port are applied to the running instance. Like on
+// creation, ports without security group filters get the security groups of
+// the instance. Existing ports are left alone.
+func (s *Service) reconcilePortSecurityGroups(eventObject runtime.Object, instanceSpec *InstanceSpec, instancePorts map[string]ports.Port) error {
+	var instanceSecurityGroups []string
+	instanceSecurityGroupsResolved := false
+	networkCount := -1
+	for i := range instanceSpec.Ports {
+		portOpts := &instanceSpec.Ports[i]
+		if portOpts.ID != "" || (portOpts.DisablePortSecurity != nil && *portOpts.DisablePortSecurity) {
+			continue
+		}
+
+		// Ports without a name suffix are named by their index, which
+		// counts the networks of the instance first
+		netIndex := 0
+		if portOpts.NameSuffix == "" {
+			if networkCount < 0 {
+				nets, err := s.getServerNetworks(instanceSpec.Networks)
+				if err != nil {
+					return err
+				}
+				networkCount = len(nets)
+			}
+			netIndex = networkCount + i
+		}
+		port, ok := instancePorts[getPortName(instanceSpec.Name, portOpts, netIndex)]
+		if !ok {
+			continue
+		}
+
+		securityGroups, err := s.networkingService.GetSecurityGroups(portOpts.SecurityGroupFilters)
+		if err != nil {
+			return fmt.Errorf("error getting security groups of port %s: %v", port.Name, err)
+		}
+		if len(securityGroups) == 0 {
+			if !instanceSecurityGroupsResolved {
+				instanceSecurityGroups, err = s.networkingService.GetSecurityGroups(instanceSpec.SecurityGroups)
+				if err != nil {
+					return fmt.Errorf("error getting security groups: %v", err)
+				}
+				instanceSecurityGroupsResolved = true
+			}
+			securityGroups = instanceSecurityGroups
+		}
+		// Neutron gives ports without security groups the default security
+		// group, which is left alone
+		if len(securityGroups) == 0 {
+			continue
+		}
+
+		if err := s.networkingService.UpdatePortSecurityGroups(eventObject, &port, securityGroups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) attachPort(eventObject runtime.Object, instanceID, clusterName, serverName, portName string, net infrav1.Network, securityGroups, tags []string) error {
+	port, err := s.networkingService.GetOrCreatePort(eventObject, clusterName, serverName, portName, net, &securityGroups, tags)
+	if err != nil {
+		return err
+	}
+
+	portEvent := record.Resource{Kind: record.Port, Name: portName, ID: port.ID}
+	_, err = s.computeService.CreateAttachedInterface(instanceID, attachinterfaces.CreateOpts{PortID: port.ID})
+	portEvent.RequestID = s.scope.LastRequestID()
+	if err != nil {
+		record.Failed(eventObject, record.Attach, portEvent, err)
+		return capoerrors.WithRequestID(fmt.Errorf("failed to attach port %s to server %s: %w", port.ID, instanceID, err), s.scope.LastRequestID())
+	}
+	record.Succeeded(eventObject, record.Attach, portEvent)
+	return nil
+}
+
+func (s *Service) detachPort(eventObject runtime.Object, instanceID string, port ports.Port) error {
+	portEvent := record.Resource{Kind: record.Port, Name: port.Name, ID: port.ID}
+	err := s.computeService.DeleteAttachedInterface(instanceID, port.ID)
+	portEvent.RequestID = s.scope.LastRequestID()
+	if err != nil {
+		record.Failed(eventObject, record.Detach, portEvent, err)
+		return capoerrors.WithRequestID(fmt.Errorf("failed to detach port %s from server %s: %w", port.ID, instanceID, err), s.scope.LastRequestID())
+	}
+	record.Succeeded(eventObject, record.Detach, portEvent)
+
+	if err := s.networkingService.WaitForPortRelease(port.ID); err != nil {
+		return fmt.Errorf("port %s was not released by server %s: %w", port.ID, instanceID, err)
+	}
+	trunkSupported, err := s.isTrunkExtSupported()
+	if err != nil {
+		return err
+	}
+	if trunkSupported {
+		if err := s.networkingService.DeleteTrunk(eventObject, port.ID); err != nil {
+			return err
+		}
+	}
+	if err := s.networkingService.DeletePort(eventObject, port.ID); err != nil && !capoerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
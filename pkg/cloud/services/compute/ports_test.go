@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/attachinterfaces"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_ReconcileInstancePorts(t *testing.T) {
+	const (
+		instanceID    = "instance-id"
+		storageNetID  = "storage-network-id"
+		storagePortID = "storage-port-id"
+		storagePort   = "machine-storage"
+		primaryPort   = "machine-0"
+		primaryPortID = "primary-port-id"
+		clusterNetID  = "cluster-network-id"
+		storageSuffix = "storage"
+		instanceName  = "machine"
+		clusterName   = "cluster"
+	)
+	storagePortOpts := infrav1.PortOpts{NameSuffix: storageSuffix, Network: &infrav1.NetworkFilter{ID: storageNetID}}
+	primaryPortOpts := infrav1.PortOpts{Network: &infrav1.NetworkFilter{ID: clusterNetID}}
+
+	tests := []struct {
+		name             string
+		ports            []infrav1.PortOpts
+		attachedSuffixes []string
+		expect           func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder)
+		want             []string
+		wantErr          bool
+	}{
+		{
+			name:  "Attach added port",
+			ports: []infrav1.PortOpts{primaryPortOpts, storagePortOpts},
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceID}).Return([]ports.Port{{ID: primaryPortID, Name: primaryPort}}, nil)
+				networkRecorder.ListPort(ports.ListOpts{Name: storagePort, NetworkID: storageNetID}).Return([]ports.Port{{ID: storagePortID, Name: storagePort}}, nil)
+				computeRecorder.CreateAttachedInterface(instanceID, attachinterfaces.CreateOpts{PortID: storagePortID}).Return(&attachinterfaces.Interface{PortID: storagePortID}, nil)
+			},
+			want: []string{storageSuffix},
+		},
+		{
+			name:  "Port created with the instance",
+			ports: []infrav1.PortOpts{primaryPortOpts, storagePortOpts},
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceID}).Return([]ports.Port{{ID: primaryPortID, Name: primaryPort}, {ID: storagePortID, Name: storagePort}}, nil)
+			},
+			want: []string{storageSuffix},
+		},
+		{
+			name: "Update security groups of port",
+			ports: []infrav1.PortOpts{primaryPortOpts, {
+				NameSuffix:           storageSuffix,
+				Network:              &infrav1.NetworkFilter{ID: storageNetID},
+				SecurityGroupFilters: []infrav1.SecurityGroupFilter{{ID: "storage-secgroup"}},
+			}},
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceID}).Return([]ports.Port{
+					{ID: primaryPortID, Name: primaryPort},
+					{ID: storagePortID, Name: storagePort, SecurityGroups: []string{"old-secgroup"}},
+				}, nil)
+				networkRecorder.UpdatePort(storagePortID, ports.UpdateOpts{SecurityGroups: &[]string{"storage-secgroup"}}).Return(&ports.Port{ID: storagePortID}, nil)
+			},
+			want: []string{storageSuffix},
+		},
+		{
+			name: "Security groups of port unchanged",
+			ports: []infrav1.PortOpts{primaryPortOpts, {
+				NameSuffix:           storageSuffix,
+				Network:              &infrav1.NetworkFilter{ID: storageNetID},
+				SecurityGroupFilters: []infrav1.SecurityGroupFilter{{ID: "storage-secgroup"}},
+			}},
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceID}).Return([]ports.Port{
+					{ID: primaryPortID, Name: primaryPort},
+					{ID: storagePortID, Name: storagePort, SecurityGroups: []string{"storage-secgroup"}},
+				}, nil)
+			},
+			want: []string{storageSuffix},
+		},
+		{
+			name:             "Detach removed port",
+			ports:            []infrav1.PortOpts{primaryPortOpts},
+			attachedSuffixes: []string{storageSuffix},
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceID}).Return([]ports.Port{{ID: primaryPortID, Name: primaryPort}, {ID: storagePortID, Name: storagePort}}, nil)
+				computeRecorder.DeleteAttachedInterface(instanceID, storagePortID).Return(nil)
+				networkRecorder.GetPort(storagePortID).Return(&ports.Port{ID: storagePortID}, nil)
+				networkRecorder.ListExtensions().Return(nil, nil)
+				networkRecorder.DeletePort(storagePortID).Return(nil)
+			},
+			want: []string{},
+		},
+		{
+			name:  "Attach fails",
+			ports: []infrav1.PortOpts{primaryPortOpts, storagePortOpts},
+			expect: func(computeRecorder *MockClientMockRecorder, networkRecorder *mock_networking.MockNetworkClientMockRecorder) {
+				networkRecorder.ListPort(ports.ListOpts{DeviceID: instanceID}).Return([]ports.Port{{ID: primaryPortID, Name: primaryPort}}, nil)
+				networkRecorder.ListPort(ports.ListOpts{Name: storagePort, NetworkID: storageNetID}).Return([]ports.Port{{ID: storagePortID, Name: storagePort}}, nil)
+				computeRecorder.CreateAttachedInterface(instanceID, gomock.Any()).Return(nil, fmt.Errorf("test error"))
+			},
+			want:    []string{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockComputeClient := NewMockClient(mockCtrl)
+			mockNetworkClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT(), mockNetworkClient.EXPECT())
+
+			s := Service{
+				scope:             &scope.Scope{Logger: logr.Discard()},
+				computeService:    mockComputeClient,
+				networkingService: networking.NewTestService("", mockNetworkClient, logr.Discard()),
+			}
+			instanceSpec := &InstanceSpec{Name: instanceName, Ports: tt.ports}
+
+			got, err := s.ReconcileInstancePorts(&infrav1.OpenStackServer{}, instanceSpec, instanceID, clusterName, tt.attachedSuffixes)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
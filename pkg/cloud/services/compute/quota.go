@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/quotasets"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/quota"
+)
+
+// GetQuotaSet returns the compute quota of the project.
+func (s *Service) GetQuotaSet() (*quotasets.QuotaSet, error) {
+	quotaSet, err := s.computeService.GetQuotaSet(s.scope.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting compute quota for project %s: %v", s.scope.ProjectID, err)
+	}
+
+	return quotaSet, nil
+}
+
+// InstanceResources are the compute and block storage resources required to
+// create instances.
+type InstanceResources struct {
+	Instances       int
+	Cores           int
+	RAM             int
+	Volumes         int
+	VolumeGigabytes int
+}
+
+// Add adds the resources of other to r.
+func (r *InstanceResources) Add(other InstanceResources) {
+	r.Instances += other.Instances
+	r.Cores += other.Cores
+	r.RAM += other.RAM
+	r.Volumes += other.Volumes
+	r.VolumeGigabytes += other.VolumeGigabytes
+}
+
+// GetInstanceResources returns the resources required to create an instance
+// with instanceSpec.
+func (s *Service) GetInstanceResources(instanceSpec *InstanceSpec) (InstanceResources, error) {
+	flavorID, err := s.computeService.GetFlavorIDFromName(instanceSpec.Flavor)
+	if err != nil {
+		return InstanceResources{}, fmt.Errorf("error getting flavor id from flavor name %s: %v", instanceSpec.Flavor, err)
+	}
+	flavor, err := s.computeService.GetFlavor(flavorID)
+	if err != nil {
+		return InstanceResources{}, fmt.Errorf("error getting flavor %s: %v", instanceSpec.Flavor, err)
+	}
+
+	resources := InstanceResources{
+		Instances: 1,
+		Cores:     flavor.VCPUs,
+		RAM:       flavor.RAM,
+	}
+	if hasRootVolume(instanceSpec.RootVolume) {
+		resources.Volumes = 1
+		resources.VolumeGigabytes = instanceSpec.RootVolume.Size
+	}
+	return resources, nil
+}
+
+// GetExhaustedQuotas returns the compute and block storage quotas of the
+// project which do not leave enough for the required resources.
+func (s *Service) GetExhaustedQuotas(required InstanceResources) ([]quota.Usage, error) {
+	quotaDetailSet, err := s.computeService.GetQuotaDetailSet(s.scope.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting compute quota usage for project %s: %v", s.scope.ProjectID, err)
+	}
+	usages := []quota.Usage{
+		computeUsage("compute.instances", quotaDetailSet.Instances, required.Instances),
+		computeUsage("compute.cores", quotaDetailSet.Cores, required.Cores),
+		computeUsage("compute.ram", quotaDetailSet.RAM, required.RAM),
+	}
+
+	// Only query the block storage quota if volumes are required, as the
+	// block storage service is otherwise not used
+	if required.Volumes > 0 {
+		quotaUsageSet, err := s.computeService.GetVolumeQuotaUsageSet(s.scope.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting volume quota usage for project %s: %v", s.scope.ProjectID, err)
+		}
+		usages = append(usages,
+			quota.Usage{
+				Resource: "volume.volumes",
+				Limit:    quotaUsageSet.Volumes.Limit,
+				InUse:    quotaUsageSet.Volumes.InUse + quotaUsageSet.Volumes.Reserved,
+				Required: required.Volumes,
+			},
+			quota.Usage{
+				Resource: "volume.gigabytes",
+				Limit:    quotaUsageSet.Gigabytes.Limit,
+				InUse:    quotaUsageSet.Gigabytes.InUse + quotaUsageSet.Gigabytes.Reserved,
+				Required: required.VolumeGigabytes,
+			},
+		)
+	}
+
+	return quota.Exhausted(usages...), nil
+}
+
+func computeUsage(resource string, detail quotasets.QuotaDetail, required int) quota.Usage {
+	return quota.Usage{
+		Resource: resource,
+		Limit:    detail.Limit,
+		InUse:    detail.InUse + detail.Reserved,
+		Required: required,
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// GetMissingResources returns the flavor, image, networks and security groups
+// referenced by the machine spec which do not exist, each described by a
+// message. The lookups are the ones used to create the instance, so their
+// results are shared with the reconciles through the cache of the OpenStack
+// APIs. An error is returned if a resource could not be looked up.
+func (s *Service) GetMissingResources(machineSpec *infrav1.OpenStackMachineSpec) ([]string, error) {
+	var missing []string
+
+	if machineSpec.Flavor != "" {
+		_, err := s.computeService.GetFlavorIDFromName(machineSpec.Flavor)
+		var notFound *gophercloud.ErrResourceNotFound
+		var multipleFound *gophercloud.ErrMultipleResourcesFound
+		switch {
+		case errors.As(err, &notFound):
+			missing = append(missing, fmt.Sprintf("flavor %s does not exist", machineSpec.Flavor))
+		case err != nil && !errors.As(err, &multipleFound):
+			return nil, err
+		}
+	}
+
+	imageMissing, err := s.isImageMissing(machineSpec.ImageUUID, machineSpec.Image)
+	if err != nil {
+		return nil, err
+	}
+	if imageMissing {
+		image := machineSpec.Image
+		if machineSpec.ImageUUID != "" {
+			image = machineSpec.ImageUUID
+		}
+		missing = append(missing, fmt.Sprintf("image %s does not exist", image))
+	}
+
+	var networkFilters []infrav1.NetworkFilter
+	for _, network := range machineSpec.Networks {
+		filter := network.Filter
+		if network.UUID != "" {
+			filter.ID = network.UUID
+		}
+		networkFilters = append(networkFilters, filter)
+	}
+	securityGroupFilters := append([]infrav1.SecurityGroupFilter(nil), machineSpec.SecurityGroups...)
+	for _, port := range machineSpec.Ports {
+		if port.Network != nil {
+			networkFilters = append(networkFilters, *port.Network)
+		}
+		securityGroupFilters = append(securityGroupFilters, port.SecurityGroupFilters...)
+	}
+
+	for _, filter := range networkFilters {
+		// An empty filter selects the cluster network
+		if filter.IsEmpty() {
+			continue
+		}
+		exists, err := s.networkingService.NetworkExists(filter)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			missing = append(missing, fmt.Sprintf("no network matches %s", describeNetworkFilter(filter)))
+		}
+	}
+
+	for _, filter := range securityGroupFilters {
+		if filter.IsEmpty() {
+			continue
+		}
+		exists, err := s.networkingService.SecurityGroupExists(filter)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			missing = append(missing, fmt.Sprintf("no security group matches %s", describeSecurityGroupFilter(filter)))
+		}
+	}
+
+	return missing, nil
+}
+
+// isImageMissing returns whether the image with the given ID or, if the ID is
+// empty, the given name does not exist.
+func (s *Service) isImageMissing(imageUUID, imageName string) (bool, error) {
+	var listOpts images.ListOpts
+	switch {
+	case imageUUID != "":
+		listOpts = images.ListOpts{ID: imageUUID}
+	case imageName != "":
+		// The same query as getImageIDFromName, which is cached
+		listOpts = images.ListOpts{Name: imageName, Limit: 2}
+	default:
+		return false, nil
+	}
+	imageList, err := s.computeService.ListImages(listOpts)
+	if err != nil {
+		return false, err
+	}
+	return len(imageList) == 0, nil
+}
+
+func describeNetworkFilter(filter infrav1.NetworkFilter) string {
+	switch {
+	case filter.ID != "":
+		return "id " + filter.ID
+	case filter.Name != "":
+		return "name " + filter.Name
+	}
+	return fmt.Sprintf("filter %+v", filter)
+}
+
+func describeSecurityGroupFilter(filter infrav1.SecurityGroupFilter) string {
+	switch {
+	case filter.ID != "":
+		return "id " + filter.ID
+	case filter.Name != "":
+		return "name " + filter.Name
+	}
+	return fmt.Sprintf("filter %+v", filter)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func TestService_GetMissingResources(t *testing.T) {
+	machineSpec := &infrav1.OpenStackMachineSpec{
+		Flavor: "m1.medium",
+		Image:  "ubuntu-2204",
+		Networks: []infrav1.NetworkParam{
+			{Filter: infrav1.NetworkFilter{Name: "private"}},
+			{UUID: "network-id"},
+			{},
+		},
+		Ports: []infrav1.PortOpts{
+			{
+				Network:              &infrav1.NetworkFilter{Name: "storage"},
+				SecurityGroupFilters: []infrav1.SecurityGroupFilter{{ID: "port-sg-id"}},
+			},
+		},
+		SecurityGroups: []infrav1.SecurityGroupFilter{{Name: "allow-ssh"}},
+	}
+	allExist := func(m *MockClientMockRecorder, n *mock_networking.MockNetworkClientMockRecorder) {
+		m.GetFlavorIDFromName("m1.medium").Return("flavor-id", nil)
+		m.ListImages(images.ListOpts{Name: "ubuntu-2204", Limit: 2}).Return([]images.Image{{ID: "image-id"}}, nil)
+		n.ListNetwork(&networks.ListOpts{Name: "private"}).Return([]networks.Network{{ID: "private-id"}}, nil)
+		n.ListNetwork(&networks.ListOpts{ID: "network-id"}).Return([]networks.Network{{ID: "network-id"}}, nil)
+		n.ListNetwork(&networks.ListOpts{Name: "storage"}).Return([]networks.Network{{ID: "storage-id"}}, nil)
+		n.ListSecGroup(groups.ListOpts{Name: "allow-ssh"}).Return([]groups.SecGroup{{ID: "allow-ssh-id"}}, nil)
+		n.ListSecGroup(groups.ListOpts{ID: "port-sg-id"}).Return([]groups.SecGroup{{ID: "port-sg-id"}}, nil)
+	}
+
+	tests := []struct {
+		name    string
+		expect  func(m *MockClientMockRecorder, n *mock_networking.MockNetworkClientMockRecorder)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "All resources exist",
+			expect: allExist,
+		},
+		{
+			name: "Missing resources",
+			expect: func(m *MockClientMockRecorder, n *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetFlavorIDFromName("m1.medium").Return("", &gophercloud.ErrResourceNotFound{Name: "m1.medium", ResourceType: "flavor"})
+				m.ListImages(images.ListOpts{Name: "ubuntu-2204", Limit: 2}).Return(nil, nil)
+				n.ListNetwork(&networks.ListOpts{Name: "private"}).Return([]networks.Network{{ID: "private-id"}}, nil)
+				n.ListNetwork(&networks.ListOpts{ID: "network-id"}).Return(nil, nil)
+				n.ListNetwork(&networks.ListOpts{Name: "storage"}).Return([]networks.Network{{ID: "storage-id"}}, nil)
+				n.ListSecGroup(groups.ListOpts{Name: "allow-ssh"}).Return(nil, nil)
+				n.ListSecGroup(groups.ListOpts{ID: "port-sg-id"}).Return([]groups.SecGroup{{ID: "port-sg-id"}}, nil)
+			},
+			want: []string{
+				"flavor m1.medium does not exist",
+				"image ubuntu-2204 does not exist",
+				"no network matches id network-id",
+				"no security group matches name allow-ssh",
+			},
+		},
+		{
+			name: "Flavor lookup fails",
+			expect: func(m *MockClientMockRecorder, n *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetFlavorIDFromName("m1.medium").Return("", gophercloud.ErrDefault500{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockComputeClient := NewMockClient(mockCtrl)
+			mockNetworkClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT(), mockNetworkClient.EXPECT())
+
+			s := Service{
+				scope:             &scope.Scope{Logger: logr.Discard()},
+				computeService:    mockComputeClient,
+				networkingService: networking.NewTestService("", mockNetworkClient, logr.Discard()),
+			}
+			got, err := s.GetMissingResources(machineSpec)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// serverWatchClockSkew is subtracted from the changes-since time of server
+// listings, so that changes are not missed if the clocks of CAPO and Nova
+// differ.
+const serverWatchClockSkew = time.Minute
+
+// serverWatcher tracks the servers of a project which are being waited for.
+// Instead of getting every server on its own, the servers changed since the
+// previous poll are listed, so that a single request per poll interval
+// updates all servers created by concurrent reconciles.
+type serverWatcher struct {
+	mu sync.Mutex
+	// servers holds the last known state of the watched servers. It is nil
+	// for servers which were not listed yet.
+	servers  map[string]*ServerExt
+	since    time.Time
+	polledAt time.Time
+	now      func() time.Time
+}
+
+func newServerWatcher() *serverWatcher {
+	return &serverWatcher{
+		servers: make(map[string]*ServerExt),
+		now:     time.Now,
+	}
+}
+
+var serverWatchers = struct {
+	sync.Mutex
+	watchers map[string]*serverWatcher
+}{watchers: make(map[string]*serverWatcher)}
+
+// serverWatcherFor returns the server watcher for the cloud, project and
+// region of the given scope.
+func serverWatcherFor(scope *scope.Scope) *serverWatcher {
+	var identityBase, region string
+	if scope.ProviderClient != nil {
+		identityBase = scope.ProviderClient.IdentityBase
+	}
+	if scope.ProviderClientOpts != nil {
+		region = scope.ProviderClientOpts.RegionName
+	}
+	key := identityBase + "|" + scope.ProjectID + "|" + region
+
+	serverWatchers.Lock()
+	defer serverWatchers.Unlock()
+
+	watcher, ok := serverWatchers.watchers[key]
+	if !ok {
+		watcher = newServerWatcher()
+		serverWatchers.watchers[key] = watcher
+	}
+	return watcher
+}
+
+// watch starts tracking the server with the given ID, which must have been
+// created before watch is called.
+func (w *serverWatcher) watch(serverID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	since := w.now().Add(-serverWatchClockSkew)
+	if len(w.servers) == 0 || since.Before(w.since) {
+		w.since = since
+	}
+	w.servers[serverID] = nil
+}
+
+// unwatch stops tracking the server with the given ID.
+func (w *serverWatcher) unwatch(serverID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.servers, serverID)
+}
+
+// get returns the last known state of the watched server with the given ID,
+// listing the servers changed since the previous poll if it is older than
+// interval. It returns nil if the server was not listed yet.
+func (w *serverWatcher) get(client Client, serverID string, interval time.Duration) (*ServerExt, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if now := w.now(); now.Sub(w.polledAt) >= interval {
+		serverList, err := client.ListServers(servers.ListOpts{
+			ChangesSince: w.since.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range serverList {
+			if _, ok := w.servers[serverList[i].ID]; ok {
+				w.servers[serverList[i].ID] = &serverList[i]
+			}
+		}
+		w.since = now.Add(-serverWatchClockSkew)
+		w.polledAt = now
+	}
+
+	return w.servers[serverID], nil
+}
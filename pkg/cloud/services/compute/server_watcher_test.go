@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/gomega"
+)
+
+func Test_serverWatcher(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockClient := NewMockClient(mockCtrl)
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	w := newServerWatcher()
+	w.now = func() time.Time { return now }
+
+	serverWithStatus := func(id, status string) ServerExt {
+		return ServerExt{Server: servers.Server{ID: id, Status: status}}
+	}
+
+	w.watch("server-1")
+	w.watch("server-2")
+
+	// A single listing updates all watched servers and ignores the others
+	mockClient.EXPECT().ListServers(servers.ListOpts{ChangesSince: "2022-06-01T11:59:00Z"}).Return([]ServerExt{
+		serverWithStatus("server-1", "BUILD"),
+		serverWithStatus("server-3", "ACTIVE"),
+	}, nil)
+	server, err := w.get(mockClient, "server-1", 10*time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(server.Status).To(Equal("BUILD"))
+	server, err = w.get(mockClient, "server-2", 10*time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(server).To(BeNil())
+	g.Expect(w.servers).NotTo(HaveKey("server-3"))
+
+	// The next listing only returns changes since the previous one
+	now = now.Add(10 * time.Second)
+	mockClient.EXPECT().ListServers(servers.ListOpts{ChangesSince: "2022-06-01T11:59:00Z"}).Return([]ServerExt{
+		serverWithStatus("server-2", "ACTIVE"),
+	}, nil)
+	server, err = w.get(mockClient, "server-2", 10*time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(server.Status).To(Equal("ACTIVE"))
+	server, err = w.get(mockClient, "server-1", 10*time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(server.Status).To(Equal("BUILD"))
+
+	w.unwatch("server-1")
+	w.unwatch("server-2")
+	g.Expect(w.servers).To(BeEmpty())
+}
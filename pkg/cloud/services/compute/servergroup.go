@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+)
+
+func getServerGroupName(clusterName, failureDomain string) string {
+	return fmt.Sprintf("k8s-clusterapi-cluster-%s-%s", clusterName, failureDomain)
+}
+
+// ReconcileServerGroups creates the server group of every failure domain of
+// the cluster which does not have one yet, and records its id in the
+// attributes of the failure domain. Failure domains mapped to an existing
+// server group are skipped. Nova does not tag server groups, so the existing
+// ones are found by their name.
+func (s *Service) ReconcileServerGroups(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	if openStackCluster.Spec.ServerGroups == nil || len(openStackCluster.Status.FailureDomains) == 0 {
+		return nil
+	}
+
+	serverGroupList, err := s.computeService.ListServerGroups()
+	if err != nil {
+		return fmt.Errorf("error listing server groups: %v", err)
+	}
+	serverGroupIDs := make(map[string]string, len(serverGroupList))
+	for _, serverGroup := range serverGroupList {
+		serverGroupIDs[serverGroup.Name] = serverGroup.ID
+	}
+
+	names := make([]string, 0, len(openStackCluster.Status.FailureDomains))
+	for name := range openStackCluster.Status.FailureDomains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if isMappedServerGroup(openStackCluster, openStackCluster.Status.FailureDomains[name].Attributes[infrav1.FailureDomainServerGroupIDAttribute]) {
+			continue
+		}
+
+		serverGroupName := getServerGroupName(clusterName, name)
+		serverGroupID, ok := serverGroupIDs[serverGroupName]
+		if !ok {
+			policy := openStackCluster.Spec.ServerGroups.Policy
+			serverGroup, err := s.computeService.CreateServerGroup(servergroups.CreateOpts{
+				Name:     serverGroupName,
+				Policies: []string{policy},
+			})
+			if err != nil {
+				record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.ServerGroup, Name: serverGroupName}, err)
+				return fmt.Errorf("error creating server group %s: %v", serverGroupName, err)
+			}
+			record.Succeeded(openStackCluster, record.Create, record.Resource{Kind: record.ServerGroup, Name: serverGroupName, ID: serverGroup.ID, Detail: "policy " + policy})
+			serverGroupID = serverGroup.ID
+		}
+
+		failureDomain := openStackCluster.Status.FailureDomains[name]
+		attributes := make(map[string]string, len(failureDomain.Attributes)+1)
+		for k, v := range failureDomain.Attributes {
+			attributes[k] = v
+		}
+		attributes[infrav1.FailureDomainServerGroupIDAttribute] = serverGroupID
+		failureDomain.Attributes = attributes
+		openStackCluster.Status.FailureDomains[name] = failureDomain
+	}
+	return nil
+}
+
+// DeleteServerGroups deletes the server groups recorded in the failure
+// domains of the cluster, except for those the failure domains are mapped to.
+func (s *Service) DeleteServerGroups(openStackCluster *infrav1.OpenStackCluster) error {
+	for _, failureDomain := range openStackCluster.Status.FailureDomains {
+		serverGroupID := failureDomain.Attributes[infrav1.FailureDomainServerGroupIDAttribute]
+		if serverGroupID == "" || isMappedServerGroup(openStackCluster, serverGroupID) {
+			continue
+		}
+		if err := s.computeService.DeleteServerGroup(serverGroupID); err != nil {
+			record.Failed(openStackCluster, record.Delete, record.Resource{Kind: record.ServerGroup, ID: serverGroupID}, err)
+			return fmt.Errorf("error deleting server group %s: %v", serverGroupID, err)
+		}
+		record.Succeeded(openStackCluster, record.Delete, record.Resource{Kind: record.ServerGroup, ID: serverGroupID})
+	}
+	return nil
+}
+
+// isMappedServerGroup returns whether a failure domain mapping of the cluster
+// refers to the existing server group with the given id, which is not managed
+// by the cluster.
+func isMappedServerGroup(openStackCluster *infrav1.OpenStackCluster, serverGroupID string) bool {
+	if serverGroupID == "" || openStackCluster.Spec.FailureDomains == nil {
+		return false
+	}
+	for _, mapping := range openStackCluster.Spec.FailureDomains.Mappings {
+		if mapping.ServerGroupID == serverGroupID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+func Test_ReconcileServerGroups(t *testing.T) {
+	failureDomains := func() clusterv1.FailureDomains {
+		return clusterv1.FailureDomains{
+			"az-1": clusterv1.FailureDomainSpec{
+				Attributes: map[string]string{infrav1.FailureDomainComputeAvailabilityZoneAttribute: "az-1"},
+			},
+			"az-2": clusterv1.FailureDomainSpec{
+				Attributes: map[string]string{infrav1.FailureDomainComputeAvailabilityZoneAttribute: "az-2"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		serverGroups   *infrav1.ManagedServerGroups
+		failureDomains *infrav1.FailureDomainsConfig
+		expect         func(m *MockClientMockRecorder)
+		want           map[string]string
+		wantErr        bool
+	}{
+		{
+			name:   "Server groups not enabled",
+			expect: func(m *MockClientMockRecorder) {},
+			want:   map[string]string{"az-1": "", "az-2": ""},
+		},
+		{
+			name:         "Creates missing server groups",
+			serverGroups: &infrav1.ManagedServerGroups{Policy: "soft-anti-affinity"},
+			expect: func(m *MockClientMockRecorder) {
+				m.ListServerGroups().Return([]servergroups.ServerGroup{
+					{ID: "server-group-1", Name: "k8s-clusterapi-cluster-test-cluster-az-1"},
+					{ID: "other-server-group", Name: "k8s-clusterapi-cluster-other-cluster-az-2"},
+				}, nil)
+				m.CreateServerGroup(servergroups.CreateOpts{
+					Name:     "k8s-clusterapi-cluster-test-cluster-az-2",
+					Policies: []string{"soft-anti-affinity"},
+				}).Return(&servergroups.ServerGroup{ID: "server-group-2", Name: "k8s-clusterapi-cluster-test-cluster-az-2"}, nil)
+			},
+			want: map[string]string{"az-1": "server-group-1", "az-2": "server-group-2"},
+		},
+		{
+			name:         "Skips failure domains mapped to a server group",
+			serverGroups: &infrav1.ManagedServerGroups{Policy: "soft-anti-affinity"},
+			failureDomains: &infrav1.FailureDomainsConfig{
+				Mappings: []infrav1.FailureDomainMapping{{Name: "az-1", ServerGroupID: "existing-server-group"}},
+			},
+			expect: func(m *MockClientMockRecorder) {
+				m.ListServerGroups().Return([]servergroups.ServerGroup{
+					{ID: "server-group-2", Name: "k8s-clusterapi-cluster-test-cluster-az-2"},
+				}, nil)
+			},
+			want: map[string]string{"az-1": "existing-server-group", "az-2": "server-group-2"},
+		},
+		{
+			name:         "Creating a server group fails",
+			serverGroups: &infrav1.ManagedServerGroups{Policy: "anti-affinity"},
+			expect: func(m *MockClientMockRecorder) {
+				m.ListServerGroups().Return(nil, nil)
+				m.CreateServerGroup(gomock.Any()).Return(nil, gophercloud.ErrDefault403{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockComputeClient := NewMockClient(mockCtrl)
+			tt.expect(mockComputeClient.EXPECT())
+
+			s := Service{
+				scope:          &scope.Scope{Logger: logr.Discard()},
+				computeService: mockComputeClient,
+			}
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec:   infrav1.OpenStackClusterSpec{ServerGroups: tt.serverGroups, FailureDomains: tt.failureDomains},
+				Status: infrav1.OpenStackClusterStatus{FailureDomains: failureDomains()},
+			}
+			if tt.failureDomains != nil {
+				for _, mapping := range tt.failureDomains.Mappings {
+					openStackCluster.Status.FailureDomains[mapping.Name].Attributes[infrav1.FailureDomainServerGroupIDAttribute] = mapping.ServerGroupID
+				}
+			}
+			err := s.ReconcileServerGroups(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			for name, serverGroupID := range tt.want {
+				attributes := openStackCluster.Status.FailureDomains[name].Attributes
+				g.Expect(attributes[infrav1.FailureDomainServerGroupIDAttribute]).To(Equal(serverGroupID))
+				g.Expect(attributes[infrav1.FailureDomainComputeAvailabilityZoneAttribute]).To(Equal(name))
+			}
+		})
+	}
+}
+
+func Test_DeleteServerGroups(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockComputeClient := NewMockClient(mockCtrl)
+	mockComputeClient.EXPECT().DeleteServerGroup("server-group-1").Return(nil)
+
+	s := Service{
+		scope:          &scope.Scope{Logger: logr.Discard()},
+		computeService: mockComputeClient,
+	}
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			FailureDomains: &infrav1.FailureDomainsConfig{
+				Mappings: []infrav1.FailureDomainMapping{{Name: "az-3", ServerGroupID: "existing-server-group"}},
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			FailureDomains: clusterv1.FailureDomains{
+				"az-1": clusterv1.FailureDomainSpec{
+					Attributes: map[string]string{infrav1.FailureDomainServerGroupIDAttribute: "server-group-1"},
+				},
+				"az-2": clusterv1.FailureDomainSpec{},
+				"az-3": clusterv1.FailureDomainSpec{
+					Attributes: map[string]string{infrav1.FailureDomainServerGroupIDAttribute: "existing-server-group"},
+				},
+			},
+		},
+	}
+	g.Expect(s.DeleteServerGroups(openStackCluster)).To(Succeed())
+}
@@ -21,9 +21,11 @@ import (
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/cache"
 )
 
 type Service struct {
@@ -67,7 +69,10 @@ func NewService(scope *scope.Scope) (*Service, error) {
 		return nil, fmt.Errorf("failed to create volume service client: %v", err)
 	}
 
-	computeService := serviceClient{computeClient, imagesClient, volumeClient}
+	computeService := cachedClient{
+		Client: serviceClient{computeClient, imagesClient, volumeClient},
+		cache:  cache.ForScope(scope),
+	}
 
 	if scope.ProviderClientOpts.AuthInfo == nil {
 		return nil, fmt.Errorf("authInfo must be set")
@@ -84,3 +89,14 @@ func NewService(scope *scope.Scope) (*Service, error) {
 		networkingService: networkingService,
 	}, nil
 }
+
+// UseGenerationCache makes the service reuse the images and flavors resolved
+// for the given generation of a resource, e.g. the OpenStackMachineTemplate
+// the instances are created from, instead of resolving them for every
+// instance.
+func (s *Service) UseGenerationCache(uid types.UID, generation int64) {
+	s.computeService = generationClient{
+		Client: s.computeService,
+		cache:  cache.ForGeneration(s.scope, uid, generation),
+	}
+}
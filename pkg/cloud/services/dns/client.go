@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package dns
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+type DNSClient interface {
+	ListZones(opts zones.ListOptsBuilder) ([]zones.Zone, error)
+	ListRecordSets(zoneID string, opts recordsets.ListOptsBuilder) ([]recordsets.RecordSet, error)
+	CreateRecordSet(zoneID string, opts recordsets.CreateOptsBuilder) (*recordsets.RecordSet, error)
+	UpdateRecordSet(zoneID, recordSetID string, opts recordsets.UpdateOptsBuilder) (*recordsets.RecordSet, error)
+	DeleteRecordSet(zoneID, recordSetID string) error
+}
+
+type dnsClient struct {
+	serviceClient *gophercloud.ServiceClient
+}
+
+func (c dnsClient) ListZones(opts zones.ListOptsBuilder) ([]zones.Zone, error) {
+	mc := metrics.NewMetricPrometheusContext("zone", "list")
+	allPages, err := zones.List(c.serviceClient, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return zones.ExtractZones(allPages)
+}
+
+func (c dnsClient) ListRecordSets(zoneID string, opts recordsets.ListOptsBuilder) ([]recordsets.RecordSet, error) {
+	mc := metrics.NewMetricPrometheusContext("recordset", "list")
+	allPages, err := recordsets.ListByZone(c.serviceClient, zoneID, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return recordsets.ExtractRecordSets(allPages)
+}
+
+func (c dnsClient) CreateRecordSet(zoneID string, opts recordsets.CreateOptsBuilder) (*recordsets.RecordSet, error) {
+	mc := metrics.NewMetricPrometheusContext("recordset", "create")
+	recordSet, err := recordsets.Create(c.serviceClient, zoneID, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return recordSet, nil
+}
+
+func (c dnsClient) UpdateRecordSet(zoneID, recordSetID string, opts recordsets.UpdateOptsBuilder) (*recordsets.RecordSet, error) {
+	mc := metrics.NewMetricPrometheusContext("recordset", "update")
+	recordSet, err := recordsets.Update(c.serviceClient, zoneID, recordSetID, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return recordSet, nil
+}
+
+func (c dnsClient) DeleteRecordSet(zoneID, recordSetID string) error {
+	mc := metrics.NewMetricPrometheusContext("recordset", "delete")
+	err := recordsets.Delete(c.serviceClient, zoneID, recordSetID).ExtractErr()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+const (
+	recordTypeA    = "A"
+	recordTypeAAAA = "AAAA"
+)
+
+// RecordName returns the name of the records of the machine in the zone.
+func RecordName(zone, machineName string) string {
+	return fmt.Sprintf("%s.%s", machineName, zone)
+}
+
+// ReconcileMachineRecords ensures that the A and AAAA records of the machine
+// in the zone of dnsSpec point to the given addresses. Record sets of the
+// machine name which were not created for the cluster are left alone and
+// reported as an error.
+func (s *Service) ReconcileMachineRecords(eventObject runtime.Object, dnsSpec *infrav1.MachineDNS, clusterName, machineName string, addresses []string) error {
+	zone, err := s.getZone(dnsSpec.Zone)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		return fmt.Errorf("dns zone %s not found", dnsSpec.Zone)
+	}
+
+	desired := map[string][]string{}
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+		if ip.To4() != nil {
+			desired[recordTypeA] = append(desired[recordTypeA], address)
+		} else {
+			desired[recordTypeAAAA] = append(desired[recordTypeAAAA], address)
+		}
+	}
+
+	name := RecordName(zone.Name, machineName)
+	for _, recordType := range []string{recordTypeA, recordTypeAAAA} {
+		records := desired[recordType]
+		sort.Strings(records)
+
+		existing, err := s.client.ListRecordSets(zone.ID, recordsets.ListOpts{Name: name, Type: recordType})
+		if err != nil {
+			return fmt.Errorf("list %s records %s: %v", recordType, name, err)
+		}
+		if len(existing) > 1 {
+			return fmt.Errorf("multiple %s record sets found with name %s", recordType, name)
+		}
+
+		if len(existing) == 0 {
+			if len(records) == 0 {
+				continue
+			}
+			recordSet, err := s.client.CreateRecordSet(zone.ID, recordsets.CreateOpts{
+				Name:        name,
+				Description: names.GetDescription(clusterName),
+				Records:     records,
+				TTL:         dnsSpec.TTL,
+				Type:        recordType,
+			})
+			if err != nil {
+				record.Warnf(eventObject, "FailedCreateDNSRecord", "Failed to create %s record %s: %v", recordType, name, err)
+				return err
+			}
+			record.Eventf(eventObject, "SuccessfulCreateDNSRecord", "Created %s record %s with id %s", recordType, name, recordSet.ID)
+			continue
+		}
+
+		recordSet := &existing[0]
+		if !names.HasClusterDescription(recordSet.Description, clusterName) {
+			return fmt.Errorf("%s record %s with id %s was not created for cluster %s", recordType, name, recordSet.ID, clusterName)
+		}
+
+		if len(records) == 0 {
+			if err := s.deleteRecordSet(eventObject, recordSet); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if equalRecords(recordSet.Records, records) && (dnsSpec.TTL == 0 || recordSet.TTL == dnsSpec.TTL) {
+			continue
+		}
+		updateOpts := recordsets.UpdateOpts{Records: records}
+		if dnsSpec.TTL != 0 {
+			updateOpts.TTL = &dnsSpec.TTL
+		}
+		if _, err := s.client.UpdateRecordSet(zone.ID, recordSet.ID, updateOpts); err != nil {
+			record.Warnf(eventObject, "FailedUpdateDNSRecord", "Failed to update %s record %s with id %s: %v", recordType, name, recordSet.ID, err)
+			return err
+		}
+		record.Eventf(eventObject, "SuccessfulUpdateDNSRecord", "Updated %s record %s with id %s", recordType, name, recordSet.ID)
+	}
+
+	return nil
+}
+
+// DeleteMachineRecords deletes the records of the machine in the zone of
+// dnsSpec which were created for the cluster.
+func (s *Service) DeleteMachineRecords(eventObject runtime.Object, dnsSpec *infrav1.MachineDNS, clusterName, machineName string) error {
+	zone, err := s.getZone(dnsSpec.Zone)
+	if err != nil || zone == nil {
+		return err
+	}
+
+	name := RecordName(zone.Name, machineName)
+	existing, err := s.client.ListRecordSets(zone.ID, recordsets.ListOpts{Name: name})
+	if err != nil {
+		return fmt.Errorf("list records %s: %v", name, err)
+	}
+	for i := range existing {
+		recordSet := &existing[i]
+		if recordSet.Type != recordTypeA && recordSet.Type != recordTypeAAAA {
+			continue
+		}
+		if !names.HasClusterDescription(recordSet.Description, clusterName) {
+			continue
+		}
+		if err := s.deleteRecordSet(eventObject, recordSet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteRecordSet(eventObject runtime.Object, recordSet *recordsets.RecordSet) error {
+	if err := s.client.DeleteRecordSet(recordSet.ZoneID, recordSet.ID); err != nil {
+		record.Warnf(eventObject, "FailedDeleteDNSRecord", "Failed to delete %s record %s with id %s: %v", recordSet.Type, recordSet.Name, recordSet.ID, err)
+		return err
+	}
+	record.Eventf(eventObject, "SuccessfulDeleteDNSRecord", "Deleted %s record %s with id %s", recordSet.Type, recordSet.Name, recordSet.ID)
+	return nil
+}
+
+// getZone returns the zone with the given name, or nil if it does not exist.
+func (s *Service) getZone(name string) (*zones.Zone, error) {
+	zoneList, err := s.client.ListZones(zones.ListOpts{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("list dns zones: %v", err)
+	}
+	switch len(zoneList) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &zoneList[0], nil
+	default:
+		return nil, fmt.Errorf("multiple dns zones found with name %s", name)
+	}
+}
+
+// equalRecords returns true if both lists contain the same records,
+// regardless of their order.
+func equalRecords(observed, desired []string) bool {
+	if len(observed) != len(desired) {
+		return false
+	}
+	sorted := append([]string{}, observed...)
+	sort.Strings(sorted)
+	for i := range sorted {
+		if sorted[i] != desired[i] {
+			return false
+		}
+	}
+	return true
+}
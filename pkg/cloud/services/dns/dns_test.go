@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/dns/mock_dns"
+)
+
+const (
+	zoneID      = "zone-id"
+	zoneName    = "nodes.example.com."
+	recordName  = "machine.nodes.example.com."
+	description = "Created by cluster-api-provider-openstack cluster test-cluster"
+)
+
+func Test_ReconcileMachineRecords(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	listZones := func(m *mock_dns.MockDNSClientMockRecorder) {
+		m.ListZones(zones.ListOpts{Name: zoneName}).Return([]zones.Zone{{ID: zoneID, Name: zoneName}}, nil)
+	}
+	listRecordSets := func(m *mock_dns.MockDNSClientMockRecorder, recordType string, recordSets ...recordsets.RecordSet) {
+		m.ListRecordSets(zoneID, recordsets.ListOpts{Name: recordName, Type: recordType}).Return(recordSets, nil)
+	}
+
+	tests := []struct {
+		name      string
+		ttl       int
+		addresses []string
+		expect    func(m *mock_dns.MockDNSClientMockRecorder)
+		wantErr   bool
+	}{
+		{
+			name:      "creates A and AAAA records",
+			ttl:       300,
+			addresses: []string{"10.0.0.11", "2001:db8::11", "10.0.0.10"},
+			expect: func(m *mock_dns.MockDNSClientMockRecorder) {
+				listZones(m)
+				listRecordSets(m, "A")
+				m.CreateRecordSet(zoneID, recordsets.CreateOpts{
+					Name: recordName, Description: description, Records: []string{"10.0.0.10", "10.0.0.11"}, TTL: 300, Type: "A",
+				}).Return(&recordsets.RecordSet{ID: "a-id"}, nil)
+				listRecordSets(m, "AAAA")
+				m.CreateRecordSet(zoneID, recordsets.CreateOpts{
+					Name: recordName, Description: description, Records: []string{"2001:db8::11"}, TTL: 300, Type: "AAAA",
+				}).Return(&recordsets.RecordSet{ID: "aaaa-id"}, nil)
+			},
+		},
+		{
+			name:      "keeps up to date records",
+			addresses: []string{"10.0.0.10"},
+			expect: func(m *mock_dns.MockDNSClientMockRecorder) {
+				listZones(m)
+				listRecordSets(m, "A", recordsets.RecordSet{ID: "a-id", ZoneID: zoneID, Description: description, Records: []string{"10.0.0.10"}, TTL: 3600})
+				listRecordSets(m, "AAAA")
+			},
+		},
+		{
+			name:      "updates changed records and deletes records without addresses",
+			addresses: []string{"10.0.0.20"},
+			expect: func(m *mock_dns.MockDNSClientMockRecorder) {
+				listZones(m)
+				listRecordSets(m, "A", recordsets.RecordSet{ID: "a-id", ZoneID: zoneID, Description: description, Records: []string{"10.0.0.10"}})
+				m.UpdateRecordSet(zoneID, "a-id", recordsets.UpdateOpts{Records: []string{"10.0.0.20"}}).Return(&recordsets.RecordSet{ID: "a-id"}, nil)
+				listRecordSets(m, "AAAA", recordsets.RecordSet{ID: "aaaa-id", ZoneID: zoneID, Type: "AAAA", Description: description, Records: []string{"2001:db8::10"}})
+				m.DeleteRecordSet(zoneID, "aaaa-id").Return(nil)
+			},
+		},
+		{
+			name:      "does not update records of another owner",
+			addresses: []string{"10.0.0.10"},
+			expect: func(m *mock_dns.MockDNSClientMockRecorder) {
+				listZones(m)
+				listRecordSets(m, "A", recordsets.RecordSet{ID: "a-id", ZoneID: zoneID, Records: []string{"192.168.0.10"}})
+			},
+			wantErr: true,
+		},
+		{
+			name:      "zone not found",
+			addresses: []string{"10.0.0.10"},
+			expect: func(m *mock_dns.MockDNSClientMockRecorder) {
+				m.ListZones(zones.ListOpts{Name: zoneName}).Return(nil, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_dns.NewMockDNSClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService(mockClient, logr.Discard())
+
+			err := s.ReconcileMachineRecords(&infrav1.OpenStackMachine{}, &infrav1.MachineDNS{Zone: zoneName, TTL: tt.ttl}, "test-cluster", "machine", tt.addresses)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_DeleteMachineRecords(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name   string
+		expect func(m *mock_dns.MockDNSClientMockRecorder)
+	}{
+		{
+			name: "deletes records of the cluster",
+			expect: func(m *mock_dns.MockDNSClientMockRecorder) {
+				m.ListZones(zones.ListOpts{Name: zoneName}).Return([]zones.Zone{{ID: zoneID, Name: zoneName}}, nil)
+				m.ListRecordSets(zoneID, recordsets.ListOpts{Name: recordName}).Return([]recordsets.RecordSet{
+					{ID: "a-id", ZoneID: zoneID, Type: "A", Description: description},
+					{ID: "aaaa-id", ZoneID: zoneID, Type: "AAAA", Description: "Created by someone else"},
+					{ID: "txt-id", ZoneID: zoneID, Type: "TXT", Description: description},
+				}, nil)
+				m.DeleteRecordSet(zoneID, "a-id").Return(nil)
+			},
+		},
+		{
+			name: "zone not found",
+			expect: func(m *mock_dns.MockDNSClientMockRecorder) {
+				m.ListZones(zones.ListOpts{Name: zoneName}).Return(nil, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_dns.NewMockDNSClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService(mockClient, logr.Discard())
+
+			err := s.DeleteMachineRecords(&infrav1.OpenStackMachine{}, &infrav1.MachineDNS{Zone: zoneName}, "test-cluster", "machine")
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
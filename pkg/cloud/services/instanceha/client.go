@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instanceha
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+)
+
+// Segment is a Masakari failover segment.
+type Segment struct {
+	UUID           string `json:"uuid"`
+	Name           string `json:"name"`
+	RecoveryMethod string `json:"recovery_method"`
+	ServiceType    string `json:"service_type"`
+}
+
+// Notification is a failure notification received by Masakari.
+type Notification struct {
+	NotificationUUID string              `json:"notification_uuid"`
+	Type             string              `json:"type"`
+	Hostname         string              `json:"hostname"`
+	Status           string              `json:"status"`
+	GeneratedTime    string              `json:"generated_time"`
+	Payload          NotificationPayload `json:"payload"`
+}
+
+// NotificationPayload describes the failure of a notification. InstanceUUID
+// is only set for notifications of type VM.
+type NotificationPayload struct {
+	Event          string `json:"event"`
+	InstanceUUID   string `json:"instance_uuid,omitempty"`
+	VirDomainEvent string `json:"vir_domain_event,omitempty"`
+}
+
+// ListNotificationsOpts filters the notifications listed by
+// ListNotifications.
+type ListNotificationsOpts struct {
+	Type           string
+	GeneratedSince time.Time
+}
+
+// InstanceHAClient is a client for the Masakari API. Masakari is not
+// supported by gophercloud, so its requests are sent with the generic
+// service client.
+type InstanceHAClient interface {
+	ListSegments() ([]Segment, error)
+	ListNotifications(opts ListNotificationsOpts) ([]Notification, error)
+}
+
+type instanceHAClient struct {
+	serviceClient *gophercloud.ServiceClient
+}
+
+func (c instanceHAClient) ListSegments() ([]Segment, error) {
+	mc := metrics.NewMetricPrometheusContext("segment", "list")
+	var body struct {
+		Segments []Segment `json:"segments"`
+	}
+	_, err := c.serviceClient.Get(c.serviceClient.ServiceURL("segments"), &body, nil)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return body.Segments, nil
+}
+
+func (c instanceHAClient) ListNotifications(opts ListNotificationsOpts) ([]Notification, error) {
+	mc := metrics.NewMetricPrometheusContext("notification", "list")
+	query := url.Values{}
+	if opts.Type != "" {
+		query.Set("type", opts.Type)
+	}
+	if !opts.GeneratedSince.IsZero() {
+		query.Set("generated-since", opts.GeneratedSince.UTC().Format(time.RFC3339))
+	}
+	query.Set("sort_key", "generated_time")
+	query.Set("sort_dir", "asc")
+
+	var body struct {
+		Notifications []Notification `json:"notifications"`
+	}
+	_, err := c.serviceClient.Get(c.serviceClient.ServiceURL("notifications")+"?"+query.Encode(), &body, nil)
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return body.Notifications, nil
+}
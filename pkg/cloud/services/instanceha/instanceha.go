@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instanceha
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// MetadataKey is the instance metadata which enables the recovery of an
+	// instance by Masakari.
+	MetadataKey = "HA_Enabled"
+	// MetadataValue enables the recovery of an instance by Masakari.
+	MetadataValue = "True"
+
+	notificationTypeVM = "VM"
+
+	notificationStatusNew     = "new"
+	notificationStatusRunning = "running"
+	notificationStatusError   = "error"
+	notificationStatusFailed  = "failed"
+)
+
+// generatedTimeLayouts are the formats of the generation time of
+// notifications. Masakari returns it without a time zone, in UTC.
+var generatedTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999",
+}
+
+// InstanceFailure is a failure of an instance reported by Masakari.
+type InstanceFailure struct {
+	InstanceID     string
+	NotificationID string
+	Event          string
+	Status         string
+	GeneratedTime  time.Time
+}
+
+// RecoveryFailed returns true if Masakari could not recover the instance.
+func (f InstanceFailure) RecoveryFailed() bool {
+	return f.Status == notificationStatusError || f.Status == notificationStatusFailed
+}
+
+// Processed returns true if Masakari finished processing the failure, so
+// its status will not change any more.
+func (f InstanceFailure) Processed() bool {
+	switch f.Status {
+	case notificationStatusNew, notificationStatusRunning:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetSegment returns the failover segment with the given name, or nil if
+// there is none.
+func (s *Service) GetSegment(name string) (*Segment, error) {
+	segments, err := s.client.ListSegments()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list segments: %w", err)
+	}
+	for i := range segments {
+		if segments[i].Name == name {
+			return &segments[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ListInstanceFailures returns the instance failures reported to Masakari
+// since the given time, ordered by their generation time.
+func (s *Service) ListInstanceFailures(since time.Time) ([]InstanceFailure, error) {
+	notifications, err := s.client.ListNotifications(ListNotificationsOpts{
+		Type:           notificationTypeVM,
+		GeneratedSince: since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	failures := make([]InstanceFailure, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.Type != notificationTypeVM || notification.Payload.InstanceUUID == "" {
+			continue
+		}
+		generatedTime, err := parseGeneratedTime(notification.GeneratedTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid generation time of notification %s: %w", notification.NotificationUUID, err)
+		}
+		failures = append(failures, InstanceFailure{
+			InstanceID:     notification.Payload.InstanceUUID,
+			NotificationID: notification.NotificationUUID,
+			Event:          notification.Payload.Event,
+			Status:         notification.Status,
+			GeneratedTime:  generatedTime,
+		})
+	}
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].GeneratedTime.Before(failures[j].GeneratedTime)
+	})
+	return failures, nil
+}
+
+func parseGeneratedTime(value string) (time.Time, error) {
+	var err error
+	for _, layout := range generatedTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
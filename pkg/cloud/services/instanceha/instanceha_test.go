@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instanceha_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/instanceha"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/instanceha/mock_instanceha"
+)
+
+func Test_GetSegment(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	mockClient := mock_instanceha.NewMockInstanceHAClient(mockCtrl)
+	mockClient.EXPECT().ListSegments().Return([]instanceha.Segment{
+		{UUID: "segment-1", Name: "segment-1"},
+		{UUID: "segment-2", Name: "segment-2"},
+	}, nil).Times(2)
+
+	s := instanceha.NewTestService(mockClient, logr.Discard())
+	segment, err := s.GetSegment("segment-2")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(segment).To(Equal(&instanceha.Segment{UUID: "segment-2", Name: "segment-2"}))
+
+	segment, err = s.GetSegment("segment-3")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(segment).To(BeNil())
+}
+
+func Test_ListInstanceFailures(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	g := NewWithT(t)
+	since := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockClient := mock_instanceha.NewMockInstanceHAClient(mockCtrl)
+	mockClient.EXPECT().ListNotifications(instanceha.ListNotificationsOpts{Type: "VM", GeneratedSince: since}).Return([]instanceha.Notification{
+		{
+			NotificationUUID: "notification-2",
+			Type:             "VM",
+			Status:           "running",
+			GeneratedTime:    "2022-10-01T12:05:00.000000",
+			Payload:          instanceha.NotificationPayload{Event: "QEMU_GUEST_AGENT_ERROR", InstanceUUID: "instance-2"},
+		},
+		{
+			NotificationUUID: "notification-1",
+			Type:             "VM",
+			Status:           "failed",
+			GeneratedTime:    "2022-10-01T12:01:00Z",
+			Payload:          instanceha.NotificationPayload{Event: "LIFECYCLE", InstanceUUID: "instance-1"},
+		},
+		{
+			NotificationUUID: "notification-3",
+			Type:             "COMPUTE_HOST",
+			Status:           "finished",
+			GeneratedTime:    "2022-10-01T12:02:00.000000",
+			Payload:          instanceha.NotificationPayload{Event: "STOPPED"},
+		},
+	}, nil)
+
+	s := instanceha.NewTestService(mockClient, logr.Discard())
+	failures, err := s.ListInstanceFailures(since)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(failures).To(Equal([]instanceha.InstanceFailure{
+		{
+			InstanceID:     "instance-1",
+			NotificationID: "notification-1",
+			Event:          "LIFECYCLE",
+			Status:         "failed",
+			GeneratedTime:  time.Date(2022, 10, 1, 12, 1, 0, 0, time.UTC),
+		},
+		{
+			InstanceID:     "instance-2",
+			NotificationID: "notification-2",
+			Event:          "QEMU_GUEST_AGENT_ERROR",
+			Status:         "running",
+			GeneratedTime:  time.Date(2022, 10, 1, 12, 5, 0, 0, time.UTC),
+		},
+	}))
+	g.Expect(failures[0].RecoveryFailed()).To(BeTrue())
+	g.Expect(failures[0].Processed()).To(BeTrue())
+	g.Expect(failures[1].RecoveryFailed()).To(BeFalse())
+	g.Expect(failures[1].Processed()).To(BeFalse())
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instanceha
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// serviceType is the type of Masakari in the service catalog.
+const serviceType = "instance-ha"
+
+// Service interfaces with the OpenStack instance HA (Masakari) API.
+type Service struct {
+	scope  *scope.Scope
+	client InstanceHAClient
+}
+
+// NewService returns an instance of the instance HA service. It returns an
+// error wrapping gophercloud.ErrEndpointNotFound if the cloud does not
+// expose Masakari.
+func NewService(scope *scope.Scope) (*Service, error) {
+	eo := gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	}
+	eo.ApplyDefaults(serviceType)
+	endpoint, err := scope.ProviderClient.EndpointLocator(eo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create instance HA service client: %w", err)
+	}
+
+	return &Service{
+		scope: scope,
+		client: instanceHAClient{&gophercloud.ServiceClient{
+			ProviderClient: scope.ProviderClient,
+			Endpoint:       endpoint,
+			Type:           serviceType,
+		}},
+	}, nil
+}
+
+// NewTestService returns a Service with no initialisation. It should only be used by tests.
+func NewTestService(client InstanceHAClient, logger logr.Logger) *Service {
+	return &Service{
+		scope: &scope.Scope{
+			Logger: logger,
+		},
+		client: client,
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/providers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/quotas"
 
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
@@ -40,6 +41,7 @@ type LbClient interface {
 	ListListeners(opts listeners.ListOptsBuilder) ([]listeners.Listener, error)
 	UpdateListener(id string, opts listeners.UpdateOpts) (*listeners.Listener, error)
 	GetListener(id string) (*listeners.Listener, error)
+	GetListenerStats(id string) (*listeners.Stats, error)
 	DeleteListener(id string) error
 	CreatePool(opts pools.CreateOptsBuilder) (*pools.Pool, error)
 	ListPools(opts pools.ListOptsBuilder) ([]pools.Pool, error)
@@ -53,6 +55,7 @@ type LbClient interface {
 	DeleteMonitor(id string) error
 	ListLoadBalancerProviders() ([]providers.Provider, error)
 	ListOctaviaVersions() ([]apiversions.APIVersion, error)
+	GetQuota(projectID string) (*quotas.Quota, error)
 }
 
 type lbClient struct {
@@ -131,6 +134,15 @@ func (l lbClient) GetListener(id string) (*listeners.Listener, error) {
 	return listener, nil
 }
 
+func (l lbClient) GetListenerStats(id string) (*listeners.Stats, error) {
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_listener", "get_stats")
+	stats, err := listeners.GetStats(l.serviceClient, id).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 func (l lbClient) DeleteListener(id string) error {
 	mc := metrics.NewMetricPrometheusContext("loadbalancer_listener", "delete")
 	err := listeners.Delete(l.serviceClient, id).ExtractErr()
@@ -250,3 +262,12 @@ func (l lbClient) ListOctaviaVersions() ([]apiversions.APIVersion, error) {
 	}
 	return apiversions.ExtractAPIVersions(allPages)
 }
+
+func (l lbClient) GetQuota(projectID string) (*quotas.Quota, error) {
+	mc := metrics.NewMetricPrometheusContext("loadbalancer_quota", "get")
+	quota, err := quotas.Get(l.serviceClient, projectID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return quota, nil
+}
@@ -19,21 +19,22 @@ package loadbalancer
 import (
 	"errors"
 	"fmt"
-	"reflect"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/providers"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/net"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 	openstackutil "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/openstack"
 	capostrings "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/strings"
@@ -47,6 +48,12 @@ const (
 
 const loadBalancerProvisioningStatusActive = "ACTIVE"
 
+const (
+	retryIntervalLoadBalancer = 2 * time.Second
+	timeoutLoadBalancerCreate = 10 * time.Minute
+	timeoutLoadBalancerDelete = 5 * time.Minute
+)
+
 func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string, apiServerPort int) error {
 	loadBalancerName := getLoadBalancerName(clusterName)
 	s.scope.Logger.Info("Reconciling load balancer", "name", loadBalancerName)
@@ -82,6 +89,18 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 		return fmt.Errorf("load balancer %q with id %s is not active after timeout: %v", loadBalancerName, lb.ID, err)
 	}
 
+	// Record the progress in the status, so that a reconcile interrupted by
+	// a restart of the controller resumes with the load balancer and its
+	// floating IP instead of allocating another floating IP.
+	lbStatus := openStackCluster.Status.Network.APIServerLoadBalancer
+	if lbStatus == nil || lbStatus.ID != lb.ID {
+		lbStatus = &infrav1.LoadBalancer{}
+	}
+	lbStatus.Name = lb.Name
+	lbStatus.ID = lb.ID
+	lbStatus.InternalIP = lb.VipAddress
+	openStackCluster.Status.Network.APIServerLoadBalancer = lbStatus
+
 	var lbFloatingIP string
 	if !openStackCluster.Spec.DisableAPIServerFloatingIP {
 		var floatingIPAddress string
@@ -90,11 +109,17 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 			floatingIPAddress = openStackCluster.Spec.APIServerFloatingIP
 		case openStackCluster.Spec.ControlPlaneEndpoint.IsValid():
 			floatingIPAddress = openStackCluster.Spec.ControlPlaneEndpoint.Host
+		default:
+			floatingIPAddress, err = s.getPreviousFloatingIP(lbStatus.IP, lb.VipPortID)
+			if err != nil {
+				return err
+			}
 		}
 		fp, err := s.networkingService.GetOrCreateFloatingIP(openStackCluster, openStackCluster, clusterName, floatingIPAddress)
 		if err != nil {
 			return err
 		}
+		lbStatus.IP = fp.FloatingIP
 		if err = s.networkingService.AssociateFloatingIP(openStackCluster, fp, lb.VipPortID); err != nil {
 			return err
 		}
@@ -154,6 +179,30 @@ func (s *Service) ReconcileLoadBalancer(openStackCluster *infrav1.OpenStackClust
 	return nil
 }
 
+// getPreviousFloatingIP returns the floating IP which was allocated for the
+// load balancer by a previous reconcile, if it still exists and is not used
+// by another port. It is empty if a new floating IP has to be allocated.
+func (s *Service) getPreviousFloatingIP(ip, vipPortID string) (string, error) {
+	if ip == "" {
+		if vipPortID == "" {
+			return "", nil
+		}
+		fp, err := s.networkingService.GetFloatingIPByPortID(vipPortID)
+		if err != nil || fp == nil {
+			return "", err
+		}
+		return fp.FloatingIP, nil
+	}
+	fp, err := s.networkingService.GetFloatingIP(ip)
+	if err != nil {
+		return "", err
+	}
+	if fp == nil || (fp.PortID != "" && fp.PortID != vipPortID) {
+		return "", nil
+	}
+	return fp.FloatingIP, nil
+}
+
 func (s *Service) getOrCreateLoadBalancer(openStackCluster *infrav1.OpenStackCluster, loadBalancerName, subnetID, clusterName, vipAddress, provider string) (*loadbalancers.LoadBalancer, error) {
 	lb, err := s.checkIfLbExists(loadBalancerName)
 	if err != nil {
@@ -164,7 +213,7 @@ func (s *Service) getOrCreateLoadBalancer(openStackCluster *infrav1.OpenStackClu
 		return lb, nil
 	}
 
-	s.scope.Logger.Info(fmt.Sprintf("Creating load balancer in subnet: %q", subnetID), "name", loadBalancerName)
+	s.scope.Logger.Info("Creating load balancer", "name", loadBalancerName, "subnetID", subnetID)
 
 	lbCreateOpts := loadbalancers.CreateOpts{
 		Name:        loadBalancerName,
@@ -175,11 +224,11 @@ func (s *Service) getOrCreateLoadBalancer(openStackCluster *infrav1.OpenStackClu
 	}
 	lb, err = s.loadbalancerClient.CreateLoadBalancer(lbCreateOpts)
 	if err != nil {
-		record.Warnf(openStackCluster, "FailedCreateLoadBalancer", "Failed to create load balancer %s: %v", loadBalancerName, err)
-		return nil, err
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.LoadBalancer, Name: loadBalancerName, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
-	record.Eventf(openStackCluster, "SuccessfulCreateLoadBalancer", "Created load balancer %s with id %s", loadBalancerName, lb.ID)
+	record.Succeeded(openStackCluster, record.Create, record.Resource{Kind: record.LoadBalancer, Name: loadBalancerName, ID: lb.ID, RequestID: s.scope.LastRequestID()})
 	return lb, nil
 }
 
@@ -193,7 +242,7 @@ func (s *Service) getOrCreateListener(openStackCluster *infrav1.OpenStackCluster
 		return listener, nil
 	}
 
-	s.scope.Logger.Info("Creating load balancer listener", "name", listenerName, "lb-id", lbID)
+	s.scope.Logger.Info("Creating load balancer listener", "name", listenerName, "loadBalancerID", lbID)
 
 	listenerCreateOpts := listeners.CreateOpts{
 		Name:           listenerName,
@@ -203,21 +252,24 @@ func (s *Service) getOrCreateListener(openStackCluster *infrav1.OpenStackCluster
 	}
 	listener, err = s.loadbalancerClient.CreateListener(listenerCreateOpts)
 	if err != nil {
-		record.Warnf(openStackCluster, "FailedCreateListener", "Failed to create listener %s: %v", listenerName, err)
-		return nil, err
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.Listener, Name: listenerName, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
+	listenerEvent := record.Resource{Kind: record.Listener, Name: listenerName, ID: listener.ID, RequestID: s.scope.LastRequestID()}
 
 	if err := s.waitForLoadBalancerActive(lbID); err != nil {
-		record.Warnf(openStackCluster, "FailedCreateListener", "Failed to create listener %s with id %s: wait for load balancer active %s: %v", listenerName, listener.ID, lbID, err)
+		listenerEvent.Detail = "wait for load balancer active " + lbID
+		record.Failed(openStackCluster, record.Create, listenerEvent, err)
 		return nil, err
 	}
 
 	if err := s.waitForListener(listener.ID, "ACTIVE"); err != nil {
-		record.Warnf(openStackCluster, "FailedCreateListener", "Failed to create listener %s with id %s: wait for listener active: %v", listenerName, listener.ID, err)
+		listenerEvent.Detail = "wait for listener active"
+		record.Failed(openStackCluster, record.Create, listenerEvent, err)
 		return nil, err
 	}
 
-	record.Eventf(openStackCluster, "SuccessfulCreateListener", "Created listener %s with id %s", listenerName, listener.ID)
+	record.Succeeded(openStackCluster, record.Create, listenerEvent)
 	return listener, nil
 }
 
@@ -247,23 +299,27 @@ func (s *Service) getOrUpdateAllowedCIDRS(openStackCluster *infrav1.OpenStackClu
 	allowedCIDRs = capostrings.Unique(allowedCIDRs)
 	listener.AllowedCIDRs = capostrings.Unique(listener.AllowedCIDRs)
 
-	if !reflect.DeepEqual(allowedCIDRs, listener.AllowedCIDRs) {
+	// Octavia does not keep the order of the allowed CIDRs
+	if !sets.NewString(allowedCIDRs...).Equal(sets.NewString(listener.AllowedCIDRs...)) {
 		listenerUpdateOpts := listeners.UpdateOpts{
 			AllowedCIDRs: &allowedCIDRs,
 		}
 
-		listener, err := s.loadbalancerClient.UpdateListener(listener.ID, listenerUpdateOpts)
+		listenerEvent := record.Resource{Kind: record.Listener, Name: listener.Name, ID: listener.ID, Detail: fmt.Sprintf("allowed_cidrs %s", allowedCIDRs)}
+		_, err := s.loadbalancerClient.UpdateListener(listener.ID, listenerUpdateOpts)
+		listenerEvent.RequestID = s.scope.LastRequestID()
 		if err != nil {
-			record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update listener %s: %v", listener.Name, err)
-			return err
+			record.Failed(openStackCluster, record.Update, listenerEvent, err)
+			return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 		}
 
 		if err := s.waitForListener(listener.ID, "ACTIVE"); err != nil {
-			record.Warnf(openStackCluster, "FailedUpdateListener", "Failed to update listener %s with id %s: wait for listener active: %v", listener.Name, listener.ID, err)
+			listenerEvent.Detail += ": wait for listener active"
+			record.Failed(openStackCluster, record.Update, listenerEvent, err)
 			return err
 		}
 
-		record.Eventf(openStackCluster, "SuccessfulUpdateListener", "Updated allowed_cidrs %s for listener %s with id %s", listener.AllowedCIDRs, listener.Name, listener.ID)
+		record.Succeeded(openStackCluster, record.Update, listenerEvent)
 	}
 	return nil
 }
@@ -296,7 +352,7 @@ func (s *Service) getOrCreatePool(openStackCluster *infrav1.OpenStackCluster, po
 		return pool, nil
 	}
 
-	s.scope.Logger.Info(fmt.Sprintf("Creating load balancer pool for listener %q", listenerID), "name", poolName, "lb-id", lbID)
+	s.scope.Logger.Info("Creating load balancer pool", "name", poolName, "loadBalancerID", lbID, "listenerID", listenerID)
 
 	poolCreateOpts := pools.CreateOpts{
 		Name:       poolName,
@@ -306,16 +362,18 @@ func (s *Service) getOrCreatePool(openStackCluster *infrav1.OpenStackCluster, po
 	}
 	pool, err = s.loadbalancerClient.CreatePool(poolCreateOpts)
 	if err != nil {
-		record.Warnf(openStackCluster, "FailedCreatePool", "Failed to create pool %s: %v", poolName, err)
-		return nil, err
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.Pool, Name: poolName, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
+	poolEvent := record.Resource{Kind: record.Pool, Name: poolName, ID: pool.ID, RequestID: s.scope.LastRequestID()}
 
 	if err := s.waitForLoadBalancerActive(lbID); err != nil {
-		record.Warnf(openStackCluster, "FailedCreatePool", "Failed to create pool %s with id %s: wait for load balancer active %s: %v", poolName, pool.ID, lbID, err)
+		poolEvent.Detail = "wait for load balancer active " + lbID
+		record.Failed(openStackCluster, record.Create, poolEvent, err)
 		return nil, err
 	}
 
-	record.Eventf(openStackCluster, "SuccessfulCreatePool", "Created pool %s with id %s", poolName, pool.ID)
+	record.Succeeded(openStackCluster, record.Create, poolEvent)
 	return pool, nil
 }
 
@@ -329,7 +387,7 @@ func (s *Service) getOrCreateMonitor(openStackCluster *infrav1.OpenStackCluster,
 		return nil
 	}
 
-	s.scope.Logger.Info(fmt.Sprintf("Creating load balancer monitor for pool %q", poolID), "name", monitorName, "lb-id", lbID)
+	s.scope.Logger.Info("Creating load balancer monitor", "name", monitorName, "loadBalancerID", lbID, "poolID", poolID)
 
 	monitorCreateOpts := monitors.CreateOpts{
 		Name:       monitorName,
@@ -341,16 +399,18 @@ func (s *Service) getOrCreateMonitor(openStackCluster *infrav1.OpenStackCluster,
 	}
 	monitor, err = s.loadbalancerClient.CreateMonitor(monitorCreateOpts)
 	if err != nil {
-		record.Warnf(openStackCluster, "FailedCreateMonitor", "Failed to create monitor %s: %v", monitorName, err)
-		return err
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.Monitor, Name: monitorName, RequestID: s.scope.LastRequestID()}, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
+	monitorEvent := record.Resource{Kind: record.Monitor, Name: monitorName, ID: monitor.ID, RequestID: s.scope.LastRequestID()}
 
 	if err = s.waitForLoadBalancerActive(lbID); err != nil {
-		record.Warnf(openStackCluster, "FailedCreateMonitor", "Failed to create monitor %s with id %s: wait for load balancer active %s: %v", monitorName, monitor.ID, lbID, err)
+		monitorEvent.Detail = "wait for load balancer active " + lbID
+		record.Failed(openStackCluster, record.Create, monitorEvent, err)
 		return err
 	}
 
-	record.Eventf(openStackCluster, "SuccessfulCreateMonitor", "Created monitor %s with id %s", monitorName, monitor.ID)
+	record.Succeeded(openStackCluster, record.Create, monitorEvent)
 	return nil
 }
 
@@ -402,7 +462,7 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 			if err != nil {
 				return err
 			}
-			if err := s.loadbalancerClient.DeletePoolMember(pool.ID, lbMember.ID); err != nil {
+			if err := s.deletePoolMember(openStackMachine, pool.ID, lbMember); err != nil {
 				return err
 			}
 			err = s.waitForLoadBalancerActive(lbID)
@@ -424,9 +484,12 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 			return err
 		}
 
-		if _, err := s.loadbalancerClient.CreatePoolMember(pool.ID, lbMemberOpts); err != nil {
-			return err
+		member, err := s.loadbalancerClient.CreatePoolMember(pool.ID, lbMemberOpts)
+		if err != nil {
+			record.Failed(openStackMachine, record.Create, record.Resource{Kind: record.PoolMember, Name: name, RequestID: s.scope.LastRequestID()}, err)
+			return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 		}
+		record.Succeeded(openStackMachine, record.Create, record.Resource{Kind: record.PoolMember, Name: name, ID: member.ID, RequestID: s.scope.LastRequestID(), Detail: "address " + ip})
 
 		if err := s.waitForLoadBalancerActive(lbID); err != nil {
 			return err
@@ -435,6 +498,39 @@ func (s *Service) ReconcileLoadBalancerMember(openStackCluster *infrav1.OpenStac
 	return nil
 }
 
+// AdoptLoadBalancer sets the API server load balancer in the network status
+// of the OpenStackCluster to the existing load balancer with the given ID,
+// without changing it. A load balancer which no longer exists is skipped.
+func (s *Service) AdoptLoadBalancer(openStackCluster *infrav1.OpenStackCluster, loadBalancerID string) error {
+	lb, err := s.loadbalancerClient.GetLoadBalancer(loadBalancerID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			s.scope.Logger.Info("Load balancer to adopt no longer exists", "id", loadBalancerID)
+			return nil
+		}
+		return err
+	}
+
+	var lbFloatingIP string
+	if lb.VipPortID != "" {
+		fip, err := s.networkingService.GetFloatingIPByPortID(lb.VipPortID)
+		if err != nil {
+			return err
+		}
+		if fip != nil {
+			lbFloatingIP = fip.FloatingIP
+		}
+	}
+
+	openStackCluster.Status.Network.APIServerLoadBalancer = &infrav1.LoadBalancer{
+		Name:       lb.Name,
+		ID:         lb.ID,
+		InternalIP: lb.VipAddress,
+		IP:         lbFloatingIP,
+	}
+	return nil
+}
+
 func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
 	loadBalancerName := getLoadBalancerName(clusterName)
 	lb, err := s.checkIfLbExists(loadBalancerName)
@@ -456,8 +552,10 @@ func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster,
 			if err = s.networkingService.DisassociateFloatingIP(openStackCluster, fip.FloatingIP); err != nil {
 				return err
 			}
-			if err = s.networkingService.DeleteFloatingIP(openStackCluster, fip.FloatingIP); err != nil {
-				return err
+			if !openStackCluster.RetainsFloatingIPs() {
+				if err = s.networkingService.DeleteFloatingIP(openStackCluster, fip.FloatingIP); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -467,12 +565,19 @@ func (s *Service) DeleteLoadBalancer(openStackCluster *infrav1.OpenStackCluster,
 	}
 	s.scope.Logger.Info("Deleting load balancer", "name", loadBalancerName, "cascade", deleteOpts.Cascade)
 	err = s.loadbalancerClient.DeleteLoadBalancer(lb.ID, deleteOpts)
+	lbEvent := record.Resource{Kind: record.LoadBalancer, Name: lb.Name, ID: lb.ID, RequestID: s.scope.LastRequestID()}
 	if err != nil && !capoerrors.IsNotFound(err) {
-		record.Warnf(openStackCluster, "FailedDeleteLoadBalancer", "Failed to delete load balancer %s with id %s: %v", lb.Name, lb.ID, err)
-		return err
+		record.Failed(openStackCluster, record.Delete, lbEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
-	record.Eventf(openStackCluster, "SuccessfulDeleteLoadBalancer", "Deleted load balancer %s with id %s", lb.Name, lb.ID)
+	// The VIP port of the load balancer is only released once it is gone
+	if err := s.waitForLoadBalancerDeleted(lb.ID); err != nil {
+		record.Failed(openStackCluster, record.Delete, lbEvent, err)
+		return fmt.Errorf("load balancer %q with id %s was not deleted after timeout: %w", loadBalancerName, lb.ID, err)
+	}
+
+	record.Succeeded(openStackCluster, record.Delete, lbEvent)
 	return nil
 }
 
@@ -504,7 +609,7 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 			return err
 		}
 		if pool == nil {
-			s.scope.Logger.Info("Load balancer pool does not exist", "name", lbPortObjectsName)
+			s.scope.Logger.V(logging.LevelDebug).Info("Load balancer pool does not exist", "name", lbPortObjectsName)
 			continue
 		}
 
@@ -519,7 +624,7 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 			if err != nil {
 				return err
 			}
-			if err := s.loadbalancerClient.DeletePoolMember(pool.ID, lbMember.ID); err != nil {
+			if err := s.deletePoolMember(openStackMachine, pool.ID, lbMember); err != nil {
 				return err
 			}
 			err = s.waitForLoadBalancerActive(lbID)
@@ -531,10 +636,26 @@ func (s *Service) DeleteLoadBalancerMember(openStackCluster *infrav1.OpenStackCl
 	return nil
 }
 
+func (s *Service) deletePoolMember(openStackMachine *infrav1.OpenStackMachine, poolID string, member *pools.Member) error {
+	err := s.loadbalancerClient.DeletePoolMember(poolID, member.ID)
+	memberEvent := record.Resource{Kind: record.PoolMember, Name: member.Name, ID: member.ID, RequestID: s.scope.LastRequestID()}
+	if err != nil {
+		record.Failed(openStackMachine, record.Delete, memberEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	record.Succeeded(openStackMachine, record.Delete, memberEvent)
+	return nil
+}
+
 func getLoadBalancerName(clusterName string) string {
 	return fmt.Sprintf("%s-cluster-%s-%s", networkPrefix, clusterName, kubeapiLBSuffix)
 }
 
+// GetLoadBalancerProviders returns the load balancer providers available in the cloud.
+func (s *Service) GetLoadBalancerProviders() ([]providers.Provider, error) {
+	return s.loadbalancerClient.ListLoadBalancerProviders()
+}
+
 func (s *Service) checkIfLbExists(name string) (*loadbalancers.LoadBalancer, error) {
 	lbList, err := s.loadbalancerClient.ListLoadBalancers(loadbalancers.ListOpts{Name: name})
 	if err != nil {
@@ -590,17 +711,28 @@ func (s *Service) checkIfLbMemberExists(poolID, name string) (*pools.Member, err
 	return &lbMemberList[0], nil
 }
 
-var backoff = wait.Backoff{
-	Steps:    20,
-	Duration: time.Second,
-	Factor:   1.25,
-	Jitter:   0.1,
+// createTimeout returns how long to wait for the load balancer and its
+// listeners to become active after each of their changes.
+func (s *Service) createTimeout() time.Duration {
+	if s.scope.Timeouts.LoadBalancerCreate > 0 {
+		return s.scope.Timeouts.LoadBalancerCreate
+	}
+	return timeoutLoadBalancerCreate
+}
+
+// deleteTimeout returns how long to wait for a deleted load balancer to be
+// gone.
+func (s *Service) deleteTimeout() time.Duration {
+	if s.scope.Timeouts.Deletion > 0 {
+		return s.scope.Timeouts.Deletion
+	}
+	return timeoutLoadBalancerDelete
 }
 
 // Possible LoadBalancer states are documented here: https://docs.openstack.org/api-ref/load-balancer/v2/index.html#prov-status
 func (s *Service) waitForLoadBalancerActive(id string) error {
 	s.scope.Logger.Info("Waiting for load balancer", "id", id, "targetStatus", "ACTIVE")
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+	return util.PollImmediate(retryIntervalLoadBalancer, s.createTimeout(), func() (bool, error) {
 		lb, err := s.loadbalancerClient.GetLoadBalancer(id)
 		if err != nil {
 			return false, err
@@ -609,9 +741,23 @@ func (s *Service) waitForLoadBalancerActive(id string) error {
 	})
 }
 
+func (s *Service) waitForLoadBalancerDeleted(id string) error {
+	s.scope.Logger.Info("Waiting for load balancer", "id", id, "targetStatus", "DELETED")
+	return util.PollImmediate(retryIntervalLoadBalancer, s.deleteTimeout(), func() (bool, error) {
+		_, err := s.loadbalancerClient.GetLoadBalancer(id)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		return false, nil
+	})
+}
+
 func (s *Service) waitForListener(id, target string) error {
 	s.scope.Logger.Info("Waiting for load balancer listener", "id", id, "targetStatus", target)
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+	return util.PollImmediate(retryIntervalLoadBalancer, s.createTimeout(), func() (bool, error) {
 		_, err := s.loadbalancerClient.GetListener(id)
 		if err != nil {
 			return false, err
@@ -17,6 +17,7 @@ limitations under the License.
 package loadbalancer
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -27,9 +28,10 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/providers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	. "github.com/onsi/gomega"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer/mock_loadbalancer"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
@@ -151,3 +153,123 @@ func Test_ReconcileLoadBalancer(t *testing.T) {
 		})
 	}
 }
+
+func Test_ReconcileLoadBalancerResumesWithFloatingIP(t *testing.T) {
+	const (
+		lbID       = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+		vipPortID  = "aaaaaaaa-bbbb-cccc-dddd-777777777777"
+		floatingIP = "203.0.113.10"
+	)
+	activeLB := loadbalancers.LoadBalancer{
+		ID:                 lbID,
+		Name:               "k8s-clusterapi-cluster-AAAAA-kubeapi",
+		VipAddress:         "10.0.0.10",
+		VipPortID:          vipPortID,
+		ProvisioningStatus: "ACTIVE",
+	}
+	fip := floatingips.FloatingIP{ID: "aaaaaaaa-bbbb-cccc-dddd-888888888888", FloatingIP: floatingIP, PortID: vipPortID}
+
+	tests := []struct {
+		name          string
+		lbStatus      *infrav1.LoadBalancer
+		expectNetwork func(m *mock_networking.MockNetworkClientMockRecorder)
+	}{
+		{
+			name:     "floating IP recorded in the status",
+			lbStatus: &infrav1.LoadBalancer{ID: lbID, IP: floatingIP},
+			expectNetwork: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(floatingips.ListOpts{FloatingIP: floatingIP}).Return([]floatingips.FloatingIP{fip}, nil).Times(2)
+			},
+		},
+		{
+			name: "floating IP associated with the VIP port",
+			expectNetwork: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(floatingips.ListOpts{PortID: vipPortID}).Return([]floatingips.FloatingIP{fip}, nil)
+				m.ListFloatingIP(floatingips.ListOpts{FloatingIP: floatingIP}).Return([]floatingips.FloatingIP{fip}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Status: infrav1.OpenStackClusterStatus{
+					ExternalNetwork: &infrav1.Network{
+						ID: "aaaaaaaa-bbbb-cccc-dddd-111111111111",
+					},
+					Network: &infrav1.Network{
+						Subnet: &infrav1.Subnet{
+							ID: "aaaaaaaa-bbbb-cccc-dddd-222222222222",
+						},
+						APIServerLoadBalancer: tt.lbStatus,
+					},
+				},
+			}
+
+			networkingClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			loadbalancerClient := mock_loadbalancer.NewMockLbClient(mockCtrl)
+			tt.expectNetwork(networkingClient.EXPECT())
+
+			m := loadbalancerClient.EXPECT()
+			m.ListLoadBalancerProviders().Return([]providers.Provider{{Name: "amphora"}}, nil)
+			m.ListLoadBalancers(loadbalancers.ListOpts{Name: activeLB.Name}).Return([]loadbalancers.LoadBalancer{activeLB}, nil)
+			m.GetLoadBalancer(lbID).Return(&activeLB, nil)
+			m.ListOctaviaVersions().Return([]apiversions.APIVersion{{ID: "2.24"}}, nil)
+			m.ListListeners(listeners.ListOpts{Name: "k8s-clusterapi-cluster-AAAAA-kubeapi-6443"}).Return(nil, nil)
+			m.CreateListener(gomock.Any()).Return(nil, errors.New("controller restarted"))
+
+			networkingService := networking.NewTestService("", networkingClient, logr.Discard())
+			lbs := NewLoadBalancerTestService("", loadbalancerClient, networkingService, logr.Discard())
+			err := lbs.ReconcileLoadBalancer(openStackCluster, "AAAAA", 6443)
+			g.Expect(err).To(HaveOccurred())
+
+			// The floating IP is reused and the progress is recorded although the listener is missing
+			g.Expect(openStackCluster.Status.Network.APIServerLoadBalancer).To(Equal(&infrav1.LoadBalancer{
+				Name:       activeLB.Name,
+				ID:         lbID,
+				IP:         floatingIP,
+				InternalIP: activeLB.VipAddress,
+			}))
+		})
+	}
+}
+
+func Test_GetAPIServerLoadBalancerStats(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	g := NewWithT(t)
+
+	const lbID = "aaaaaaaa-bbbb-cccc-dddd-333333333333"
+	openStackCluster := &infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{
+				APIServerLoadBalancer: &infrav1.LoadBalancer{ID: lbID},
+			},
+		},
+	}
+
+	loadbalancerClient := mock_loadbalancer.NewMockLbClient(mockCtrl)
+	m := loadbalancerClient.EXPECT()
+	m.ListListeners(listeners.ListOpts{LoadbalancerID: lbID}).Return([]listeners.Listener{
+		{ID: "listener-8443", ProtocolPort: 8443},
+		{ID: "listener-6443", ProtocolPort: 6443},
+	}, nil)
+	m.GetListenerStats("listener-8443").Return(&listeners.Stats{ActiveConnections: 1}, nil)
+	m.GetListenerStats("listener-6443").Return(&listeners.Stats{ActiveConnections: 5, BytesIn: 100, BytesOut: 200, TotalConnections: 10}, nil)
+
+	lbs := NewLoadBalancerTestService("", loadbalancerClient, nil, logr.Discard())
+	stats, err := lbs.GetAPIServerLoadBalancerStats(openStackCluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(stats).To(Equal([]ListenerStats{
+		{Port: 6443, Stats: listeners.Stats{ActiveConnections: 5, BytesIn: 100, BytesOut: 200, TotalConnections: 10}},
+		{Port: 8443, Stats: listeners.Stats{ActiveConnections: 1}},
+	}))
+
+	// Clusters without a load balancer have no statistics
+	stats, err = lbs.GetAPIServerLoadBalancerStats(&infrav1.OpenStackCluster{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(stats).To(BeEmpty())
+}
@@ -30,6 +30,7 @@ import (
 	monitors "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/monitors"
 	pools "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	providers "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/providers"
+	quotas "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/quotas"
 )
 
 // MockLbClient is a mock of LbClient interface.
@@ -215,6 +216,21 @@ func (mr *MockLbClientMockRecorder) GetListener(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListener", reflect.TypeOf((*MockLbClient)(nil).GetListener), arg0)
 }
 
+// GetListenerStats mocks base method.
+func (m *MockLbClient) GetListenerStats(arg0 string) (*listeners.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListenerStats", arg0)
+	ret0, _ := ret[0].(*listeners.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListenerStats indicates an expected call of GetListenerStats.
+func (mr *MockLbClientMockRecorder) GetListenerStats(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListenerStats", reflect.TypeOf((*MockLbClient)(nil).GetListenerStats), arg0)
+}
+
 // GetLoadBalancer mocks base method.
 func (m *MockLbClient) GetLoadBalancer(arg0 string) (*loadbalancers.LoadBalancer, error) {
 	m.ctrl.T.Helper()
@@ -245,6 +261,21 @@ func (mr *MockLbClientMockRecorder) GetPool(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPool", reflect.TypeOf((*MockLbClient)(nil).GetPool), arg0)
 }
 
+// GetQuota mocks base method.
+func (m *MockLbClient) GetQuota(arg0 string) (*quotas.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuota", arg0)
+	ret0, _ := ret[0].(*quotas.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuota indicates an expected call of GetQuota.
+func (mr *MockLbClientMockRecorder) GetQuota(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuota", reflect.TypeOf((*MockLbClient)(nil).GetQuota), arg0)
+}
+
 // ListListeners mocks base method.
 func (m *MockLbClient) ListListeners(arg0 listeners.ListOptsBuilder) ([]listeners.Listener, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/quota"
+)
+
+// GetExhaustedQuotas returns the load balancer quota of the project if it does
+// not leave enough for the required load balancers. Octavia does not report
+// the usage of its quotas, so the load balancers of the project are counted.
+func (s *Service) GetExhaustedQuotas(requiredLoadBalancers int) ([]quota.Usage, error) {
+	if requiredLoadBalancers <= 0 {
+		return nil, nil
+	}
+
+	lbQuota, err := s.loadbalancerClient.GetQuota(s.scope.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting load balancer quota for project %s: %v", s.scope.ProjectID, err)
+	}
+	if lbQuota.Loadbalancer < 0 {
+		return nil, nil
+	}
+
+	lbList, err := s.loadbalancerClient.ListLoadBalancers(loadbalancers.ListOpts{ProjectID: s.scope.ProjectID})
+	if err != nil {
+		return nil, fmt.Errorf("error listing load balancers of project %s: %v", s.scope.ProjectID, err)
+	}
+
+	return quota.Exhausted(quota.Usage{
+		Resource: "loadbalancer.loadbalancers",
+		Limit:    lbQuota.Loadbalancer,
+		InUse:    len(lbList),
+		Required: requiredLoadBalancers,
+	}), nil
+}
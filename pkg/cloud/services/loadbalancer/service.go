@@ -41,7 +41,7 @@ func NewService(scope *scope.Scope) (*Service, error) {
 		Region: scope.ProviderClientOpts.RegionName,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create load balancer service client: %v", err)
+		return nil, fmt.Errorf("failed to create load balancer service client: %w", err)
 	}
 
 	networkingService, err := networking.NewService(scope)
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// ListenerStats are the statistics of a listener of the API server load
+// balancer.
+type ListenerStats struct {
+	Port int
+	listeners.Stats
+}
+
+// GetAPIServerLoadBalancerStats returns the statistics of the listeners of the
+// API server load balancer of the cluster, sorted by port.
+func (s *Service) GetAPIServerLoadBalancerStats(openStackCluster *infrav1.OpenStackCluster) ([]ListenerStats, error) {
+	network := openStackCluster.Status.Network
+	if network == nil || network.APIServerLoadBalancer == nil || network.APIServerLoadBalancer.ID == "" {
+		return nil, nil
+	}
+	lb := network.APIServerLoadBalancer
+
+	listenerList, err := s.loadbalancerClient.ListListeners(listeners.ListOpts{LoadbalancerID: lb.ID})
+	if err != nil {
+		return nil, fmt.Errorf("list listeners of load balancer %s: %v", lb.ID, err)
+	}
+
+	stats := make([]ListenerStats, 0, len(listenerList))
+	for _, listener := range listenerList {
+		listenerStats, err := s.loadbalancerClient.GetListenerStats(listener.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get statistics of listener %s: %v", listener.ID, err)
+		}
+		stats = append(stats, ListenerStats{Port: listener.ProtocolPort, Stats: *listenerStats})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Port < stats[j].Port })
+	return stats, nil
+}
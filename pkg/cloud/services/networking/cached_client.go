@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/cache"
+)
+
+const (
+	networkResource       = "network"
+	securityGroupResource = "security_group"
+	extensionResource     = "extension"
+)
+
+// cachedNetworkClient serves networks, security groups and extensions from the
+// shared response cache. The cached responses are invalidated whenever CAPO
+// writes to a resource of the same type. Extensions are never written by
+// CAPO, so they are only invalidated by expiry.
+type cachedNetworkClient struct {
+	NetworkClient
+	cache cache.Scoped
+}
+
+func (c cachedNetworkClient) ListNetwork(opts networks.ListOptsBuilder) ([]networks.Network, error) {
+	query, err := opts.ToNetworkListQuery()
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := c.cache.Get(networkResource, query); ok {
+		return append([]networks.Network(nil), cached.([]networks.Network)...), nil
+	}
+	networkList, err := c.NetworkClient.ListNetwork(opts)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(networkResource, query, networkList)
+	return append([]networks.Network(nil), networkList...), nil
+}
+
+func (c cachedNetworkClient) CreateNetwork(opts networks.CreateOptsBuilder) (*networks.Network, error) {
+	defer c.cache.Invalidate(networkResource)
+	return c.NetworkClient.CreateNetwork(opts)
+}
+
+func (c cachedNetworkClient) DeleteNetwork(id string) error {
+	defer c.cache.Invalidate(networkResource)
+	return c.NetworkClient.DeleteNetwork(id)
+}
+
+func (c cachedNetworkClient) UpdateNetwork(id string, opts networks.UpdateOptsBuilder) (*networks.Network, error) {
+	defer c.cache.Invalidate(networkResource)
+	return c.NetworkClient.UpdateNetwork(id, opts)
+}
+
+// Networks list the IDs of their subnets.
+func (c cachedNetworkClient) CreateSubnet(opts subnets.CreateOptsBuilder) (*subnets.Subnet, error) {
+	defer c.cache.Invalidate(networkResource)
+	return c.NetworkClient.CreateSubnet(opts)
+}
+
+func (c cachedNetworkClient) DeleteSubnet(id string) error {
+	defer c.cache.Invalidate(networkResource)
+	return c.NetworkClient.DeleteSubnet(id)
+}
+
+func (c cachedNetworkClient) ListSecGroup(opts groups.ListOpts) ([]groups.SecGroup, error) {
+	query, err := gophercloud.BuildQueryString(&opts)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := c.cache.Get(securityGroupResource, query.String()); ok {
+		return append([]groups.SecGroup(nil), cached.([]groups.SecGroup)...), nil
+	}
+	secGroups, err := c.NetworkClient.ListSecGroup(opts)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(securityGroupResource, query.String(), secGroups)
+	return append([]groups.SecGroup(nil), secGroups...), nil
+}
+
+func (c cachedNetworkClient) CreateSecGroup(opts groups.CreateOptsBuilder) (*groups.SecGroup, error) {
+	defer c.cache.Invalidate(securityGroupResource)
+	return c.NetworkClient.CreateSecGroup(opts)
+}
+
+func (c cachedNetworkClient) DeleteSecGroup(id string) error {
+	defer c.cache.Invalidate(securityGroupResource)
+	return c.NetworkClient.DeleteSecGroup(id)
+}
+
+func (c cachedNetworkClient) UpdateSecGroup(id string, opts groups.UpdateOptsBuilder) (*groups.SecGroup, error) {
+	defer c.cache.Invalidate(securityGroupResource)
+	return c.NetworkClient.UpdateSecGroup(id, opts)
+}
+
+// Security groups include their rules.
+func (c cachedNetworkClient) CreateSecGroupRule(opts rules.CreateOptsBuilder) (*rules.SecGroupRule, error) {
+	defer c.cache.Invalidate(securityGroupResource)
+	return c.NetworkClient.CreateSecGroupRule(opts)
+}
+
+func (c cachedNetworkClient) DeleteSecGroupRule(id string) error {
+	defer c.cache.Invalidate(securityGroupResource)
+	return c.NetworkClient.DeleteSecGroupRule(id)
+}
+
+func (c cachedNetworkClient) ReplaceAllAttributesTags(resourceType string, resourceID string, opts attributestags.ReplaceAllOptsBuilder) ([]string, error) {
+	switch resourceType {
+	case "networks":
+		defer c.cache.Invalidate(networkResource)
+	case "security-groups":
+		defer c.cache.Invalidate(securityGroupResource)
+	}
+	return c.NetworkClient.ReplaceAllAttributesTags(resourceType, resourceID, opts)
+}
+
+func (c cachedNetworkClient) ListExtensions() ([]extensions.Extension, error) {
+	if cached, ok := c.cache.Get(extensionResource, ""); ok {
+		return append([]extensions.Extension(nil), cached.([]extensions.Extension)...), nil
+	}
+	extensionList, err := c.NetworkClient.ListExtensions()
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(extensionResource, "", extensionList)
+	return append([]extensions.Extension(nil), extensionList...), nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	common "github.com/gophercloud/gophercloud/openstack/common/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/cache"
+)
+
+func Test_cachedNetworkClient_ListNetwork(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cache.SetDefaultTTL(time.Minute)
+	defer cache.SetDefaultTTL(0)
+
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+	c := cachedNetworkClient{
+		NetworkClient: mockClient,
+		cache:         cache.ForScope(&scope.Scope{ProjectID: "project"}),
+	}
+
+	opts := networks.ListOpts{Name: "foo"}
+	mockClient.EXPECT().ListNetwork(opts).Return([]networks.Network{{ID: "network-id"}}, nil).Times(2)
+	mockClient.EXPECT().DeleteNetwork("other-network-id").Return(nil)
+
+	// The second list is served from the cache
+	for i := 0; i < 2; i++ {
+		networkList, err := c.ListNetwork(opts)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(networkList).To(Equal([]networks.Network{{ID: "network-id"}}))
+	}
+
+	// Writes invalidate the cache
+	g.Expect(c.DeleteNetwork("other-network-id")).To(Succeed())
+	networkList, err := c.ListNetwork(opts)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(networkList).To(Equal([]networks.Network{{ID: "network-id"}}))
+}
+
+func Test_cachedNetworkClient_ListExtensions(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cache.SetDefaultTTL(time.Minute)
+	defer cache.SetDefaultTTL(0)
+
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+	c := cachedNetworkClient{
+		NetworkClient: mockClient,
+		cache:         cache.ForScope(&scope.Scope{ProjectID: "extensions-project"}),
+	}
+
+	trunk := extensions.Extension{Extension: common.Extension{Alias: "trunk"}}
+	mockClient.EXPECT().ListExtensions().Return([]extensions.Extension{trunk}, nil)
+
+	// The second list is served from the cache
+	for i := 0; i < 2; i++ {
+		extensionList, err := c.ListExtensions()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(extensionList).To(Equal([]extensions.Extension{trunk}))
+	}
+}
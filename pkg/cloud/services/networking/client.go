@@ -20,14 +20,19 @@ import (
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	fwgroups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/policies"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/mtu"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"github.com/gophercloud/gophercloud/pagination"
 
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 )
@@ -40,6 +45,7 @@ type NetworkClient interface {
 	UpdateFloatingIP(id string, opts floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error)
 
 	ListPort(opts ports.ListOptsBuilder) ([]ports.Port, error)
+	EachPortPage(opts ports.ListOptsBuilder, handler func([]ports.Port) (bool, error)) error
 	CreatePort(opts ports.CreateOptsBuilder) (*ports.Port, error)
 	DeletePort(id string) error
 	GetPort(id string) (*ports.Port, error)
@@ -48,6 +54,7 @@ type NetworkClient interface {
 	ListTrunk(opts trunks.ListOptsBuilder) ([]trunks.Trunk, error)
 	CreateTrunk(opts trunks.CreateOptsBuilder) (*trunks.Trunk, error)
 	DeleteTrunk(id string) error
+	RemoveSubports(id string, opts trunks.RemoveSubportsOptsBuilder) error
 
 	ListRouter(opts routers.ListOpts) ([]routers.Router, error)
 	CreateRouter(opts routers.CreateOptsBuilder) (*routers.Router, error)
@@ -57,6 +64,12 @@ type NetworkClient interface {
 	AddRouterInterface(id string, opts routers.AddInterfaceOptsBuilder) (*routers.InterfaceInfo, error)
 	RemoveRouterInterface(id string, opts routers.RemoveInterfaceOptsBuilder) (*routers.InterfaceInfo, error)
 
+	ListFirewallGroup(opts fwgroups.ListOptsBuilder) ([]fwgroups.Group, error)
+	CreateFirewallGroup(opts fwgroups.CreateOptsBuilder) (*fwgroups.Group, error)
+	DeleteFirewallGroup(id string) error
+	UpdateFirewallGroup(id string, opts fwgroups.UpdateOptsBuilder) (*fwgroups.Group, error)
+	ListFirewallPolicy(opts policies.ListOptsBuilder) ([]policies.Policy, error)
+
 	ListSecGroup(opts groups.ListOpts) ([]groups.SecGroup, error)
 	CreateSecGroup(opts groups.CreateOptsBuilder) (*groups.SecGroup, error)
 	DeleteSecGroup(id string) error
@@ -72,6 +85,7 @@ type NetworkClient interface {
 	CreateNetwork(opts networks.CreateOptsBuilder) (*networks.Network, error)
 	DeleteNetwork(id string) error
 	GetNetwork(id string) (*networks.Network, error)
+	GetNetworkMTU(id string) (int, error)
 	UpdateNetwork(id string, opts networks.UpdateOptsBuilder) (*networks.Network, error)
 
 	ListSubnet(opts subnets.ListOptsBuilder) ([]subnets.Subnet, error)
@@ -82,6 +96,9 @@ type NetworkClient interface {
 
 	ListExtensions() ([]extensions.Extension, error)
 
+	GetQuota(projectID string) (*quotas.Quota, error)
+	GetQuotaDetail(projectID string) (*quotas.QuotaDetailSet, error)
+
 	ReplaceAllAttributesTags(resourceType string, resourceID string, opts attributestags.ReplaceAllOptsBuilder) ([]string, error)
 }
 
@@ -175,6 +192,20 @@ func (c networkClient) ListPort(opts ports.ListOptsBuilder) ([]ports.Port, error
 	return ports.ExtractPorts(allPages)
 }
 
+// EachPortPage calls handler with the ports of each page matching opts, until
+// handler returns false or an error.
+func (c networkClient) EachPortPage(opts ports.ListOptsBuilder, handler func([]ports.Port) (bool, error)) error {
+	mc := metrics.NewMetricPrometheusContext("port", "list")
+	err := ports.List(c.serviceClient, opts).EachPage(func(page pagination.Page) (bool, error) {
+		portList, err := ports.ExtractPorts(page)
+		if err != nil {
+			return false, err
+		}
+		return handler(portList)
+	})
+	return mc.ObserveRequest(err)
+}
+
 func (c networkClient) CreatePort(opts ports.CreateOptsBuilder) (*ports.Port, error) {
 	mc := metrics.NewMetricPrometheusContext("port", "create")
 	port, err := ports.Create(c.serviceClient, opts).Extract()
@@ -221,6 +252,12 @@ func (c networkClient) DeleteTrunk(id string) error {
 	return mc.ObserveRequestIgnoreNotFound(trunks.Delete(c.serviceClient, id).ExtractErr())
 }
 
+func (c networkClient) RemoveSubports(id string, opts trunks.RemoveSubportsOptsBuilder) error {
+	mc := metrics.NewMetricPrometheusContext("trunk_subports", "delete")
+	_, err := trunks.RemoveSubports(c.serviceClient, id, opts).Extract()
+	return mc.ObserveRequest(err)
+}
+
 func (c networkClient) ListTrunk(opts trunks.ListOptsBuilder) ([]trunks.Trunk, error) {
 	mc := metrics.NewMetricPrometheusContext("trunk", "list")
 	allPages, err := trunks.List(c.serviceClient, opts).AllPages()
@@ -262,6 +299,47 @@ func (c networkClient) UpdateRouter(id string, opts routers.UpdateOptsBuilder) (
 	return router, nil
 }
 
+func (c networkClient) ListFirewallGroup(opts fwgroups.ListOptsBuilder) ([]fwgroups.Group, error) {
+	mc := metrics.NewMetricPrometheusContext("firewall_group", "list")
+	allPages, err := fwgroups.List(c.serviceClient, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return fwgroups.ExtractGroups(allPages)
+}
+
+func (c networkClient) CreateFirewallGroup(opts fwgroups.CreateOptsBuilder) (*fwgroups.Group, error) {
+	mc := metrics.NewMetricPrometheusContext("firewall_group", "create")
+	group, err := fwgroups.Create(c.serviceClient, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (c networkClient) DeleteFirewallGroup(id string) error {
+	mc := metrics.NewMetricPrometheusContext("firewall_group", "delete")
+	return mc.ObserveRequestIgnoreNotFound(fwgroups.Delete(c.serviceClient, id).ExtractErr())
+}
+
+func (c networkClient) UpdateFirewallGroup(id string, opts fwgroups.UpdateOptsBuilder) (*fwgroups.Group, error) {
+	mc := metrics.NewMetricPrometheusContext("firewall_group", "update")
+	group, err := fwgroups.Update(c.serviceClient, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (c networkClient) ListFirewallPolicy(opts policies.ListOptsBuilder) ([]policies.Policy, error) {
+	mc := metrics.NewMetricPrometheusContext("firewall_policy", "list")
+	allPages, err := policies.List(c.serviceClient, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return policies.ExtractPolicies(allPages)
+}
+
 func (c networkClient) ListSecGroup(opts groups.ListOpts) ([]groups.SecGroup, error) {
 	mc := metrics.NewMetricPrometheusContext("group", "list")
 	allPages, err := groups.List(c.serviceClient, opts).AllPages()
@@ -367,6 +445,19 @@ func (c networkClient) GetNetwork(id string) (*networks.Network, error) {
 	return net, nil
 }
 
+func (c networkClient) GetNetworkMTU(id string) (int, error) {
+	var net struct {
+		networks.Network
+		mtu.NetworkMTUExt
+	}
+	mc := metrics.NewMetricPrometheusContext("network", "get")
+	err := networks.Get(c.serviceClient, id).ExtractInto(&net)
+	if mc.ObserveRequestIgnoreNotFound(err) != nil {
+		return 0, err
+	}
+	return net.MTU, nil
+}
+
 func (c networkClient) UpdateNetwork(id string, opts networks.UpdateOptsBuilder) (*networks.Network, error) {
 	mc := metrics.NewMetricPrometheusContext("network", "update")
 	net, err := networks.Update(c.serviceClient, id, opts).Extract()
@@ -425,3 +516,21 @@ func (c networkClient) ListExtensions() ([]extensions.Extension, error) {
 	}
 	return extensions.ExtractExtensions(allPages)
 }
+
+func (c networkClient) GetQuota(projectID string) (*quotas.Quota, error) {
+	mc := metrics.NewMetricPrometheusContext("network_quota", "get")
+	quota, err := quotas.Get(c.serviceClient, projectID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+func (c networkClient) GetQuotaDetail(projectID string) (*quotas.QuotaDetailSet, error) {
+	mc := metrics.NewMetricPrometheusContext("network_quota_detail", "get")
+	quotaDetailSet, err := quotas.GetDetail(c.serviceClient, projectID).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return quotaDetailSet, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// GetMissingExtensions returns the aliases of the given Neutron extensions
+// which are not enabled in the cloud, in the given order.
+func (s *Service) GetMissingExtensions(aliases []string) ([]string, error) {
+	extensionList, err := s.client.ListExtensions()
+	if err != nil {
+		return nil, fmt.Errorf("list network extensions: %v", err)
+	}
+	enabled := sets.NewString()
+	for _, extension := range extensionList {
+		enabled.Insert(extension.Alias)
+	}
+
+	var missing []string
+	for _, alias := range aliases {
+		if !enabled.Has(alias) {
+			missing = append(missing, alias)
+		}
+	}
+	return missing, nil
+}
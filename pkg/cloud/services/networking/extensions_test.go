@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	common "github.com/gophercloud/gophercloud/openstack/common/extensions"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+)
+
+func Test_GetMissingExtensions(t *testing.T) {
+	extension := func(alias string) extensions.Extension {
+		return extensions.Extension{Extension: common.Extension{Alias: alias}}
+	}
+
+	tests := []struct {
+		name        string
+		aliases     []string
+		expect      func(m *mock_networking.MockNetworkClientMockRecorder)
+		wantMissing []string
+		wantErr     bool
+	}{
+		{
+			name:    "All extensions are enabled",
+			aliases: []string{"security-group", "router"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListExtensions().Return([]extensions.Extension{extension("router"), extension("trunk"), extension("security-group")}, nil)
+			},
+		},
+		{
+			name:    "Missing extensions are returned in order",
+			aliases: []string{"trunk", "security-group", "standard-attr-tag"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListExtensions().Return([]extensions.Extension{extension("security-group")}, nil)
+			},
+			wantMissing: []string{"trunk", "standard-attr-tag"},
+		},
+		{
+			name:    "Listing the extensions fails",
+			aliases: []string{"trunk"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListExtensions().Return(nil, fmt.Errorf("test error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+
+			s := NewTestService("", mockClient, logr.Discard())
+			missing, err := s.GetMissingExtensions(tt.aliases)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(missing).To(Equal(tt.wantMissing))
+		})
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+	"sort"
+
+	fwgroups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/policies"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+// ReconcileRouterFirewall creates the FWaaS v2 firewall group of the router of
+// the cluster network with the policies of spec.routerFirewall, and keeps its
+// policies and ports in sync with the spec and the interfaces of the router.
+// The firewall group is deleted when spec.routerFirewall is unset.
+func (s *Service) ReconcileRouterFirewall(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	firewall := openStackCluster.Spec.RouterFirewall
+	if firewall == nil {
+		if openStackCluster.Status.RouterFirewallGroup == nil {
+			return nil
+		}
+		if err := s.DeleteRouterFirewall(openStackCluster, clusterName); err != nil {
+			return err
+		}
+		openStackCluster.Status.RouterFirewallGroup = nil
+		return nil
+	}
+
+	network := openStackCluster.Status.Network
+	if network == nil || network.Router == nil || network.Router.ID == "" {
+		s.scope.Logger.V(logging.LevelDebug).Info("No need to reconcile router firewall since no router exists")
+		return nil
+	}
+
+	groupName := getRouterFirewallGroupName(clusterName)
+	s.scope.Logger.Info("Reconciling router firewall", "name", groupName)
+
+	ingressPolicyID, err := s.getFirewallPolicyID(firewall.IngressPolicy)
+	if err != nil {
+		return err
+	}
+	egressPolicyID, err := s.getFirewallPolicyID(firewall.EgressPolicy)
+	if err != nil {
+		return err
+	}
+
+	routerPorts, err := s.getRouterInterfaces(network.Router.ID)
+	if err != nil {
+		return err
+	}
+	var portIDs []string
+	for _, port := range routerPorts {
+		if routerInterfaceDeviceOwners[port.DeviceOwner] {
+			portIDs = append(portIDs, port.ID)
+		}
+	}
+	sort.Strings(portIDs)
+
+	group, err := s.getFirewallGroupByName(groupName)
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		group, err = s.createFirewallGroup(openStackCluster, clusterName, groupName, ingressPolicyID, egressPolicyID, portIDs)
+		if err != nil {
+			return err
+		}
+	} else if !firewallGroupUpToDate(group, ingressPolicyID, egressPolicyID, portIDs) {
+		group, err = s.updateFirewallGroup(openStackCluster, group, ingressPolicyID, egressPolicyID, portIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	openStackCluster.Status.RouterFirewallGroup = &infrav1.FirewallGroup{
+		Name: group.Name,
+		ID:   group.ID,
+	}
+	return nil
+}
+
+// DeleteRouterFirewall deletes the firewall group of the router of the
+// cluster network, if there is one.
+func (s *Service) DeleteRouterFirewall(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	group, err := s.getFirewallGroupByName(getRouterFirewallGroupName(clusterName))
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		return nil
+	}
+
+	err = s.client.DeleteFirewallGroup(group.ID)
+	groupEvent := record.Resource{Kind: record.FirewallGroup, Name: group.Name, ID: group.ID, RequestID: s.scope.LastRequestID()}
+	if err != nil {
+		record.Failed(openStackCluster, record.Delete, groupEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	record.Succeeded(openStackCluster, record.Delete, groupEvent)
+	return nil
+}
+
+func (s *Service) createFirewallGroup(openStackCluster *infrav1.OpenStackCluster, clusterName, groupName, ingressPolicyID, egressPolicyID string, portIDs []string) (*fwgroups.Group, error) {
+	s.scope.Logger.Info("Creating firewall group", "name", groupName)
+	group, err := s.client.CreateFirewallGroup(fwgroups.CreateOpts{
+		Name:                    groupName,
+		Description:             names.GetDescription(clusterName),
+		IngressFirewallPolicyID: ingressPolicyID,
+		EgressFirewallPolicyID:  egressPolicyID,
+		Ports:                   portIDs,
+	})
+	if err != nil {
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.FirewallGroup, Name: groupName, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	record.Succeeded(openStackCluster, record.Create, record.Resource{Kind: record.FirewallGroup, Name: groupName, ID: group.ID, RequestID: s.scope.LastRequestID()})
+	return group, nil
+}
+
+func (s *Service) updateFirewallGroup(openStackCluster *infrav1.OpenStackCluster, group *fwgroups.Group, ingressPolicyID, egressPolicyID string, portIDs []string) (*fwgroups.Group, error) {
+	s.scope.Logger.Info("Updating firewall group", "name", group.Name, "id", group.ID)
+	updated, err := s.client.UpdateFirewallGroup(group.ID, firewallGroupUpdateOpts{
+		ingressPolicyID: ingressPolicyID,
+		egressPolicyID:  egressPolicyID,
+		portIDs:         portIDs,
+	})
+	groupEvent := record.Resource{Kind: record.FirewallGroup, Name: group.Name, ID: group.ID, RequestID: s.scope.LastRequestID()}
+	if err != nil {
+		record.Failed(openStackCluster, record.Update, groupEvent, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	record.Succeeded(openStackCluster, record.Update, groupEvent)
+	return updated, nil
+}
+
+// firewallGroupUpdateOpts sets the policies and ports of a firewall group.
+// Unlike fwgroups.UpdateOpts, it removes the policy of a direction without
+// policy.
+type firewallGroupUpdateOpts struct {
+	ingressPolicyID string
+	egressPolicyID  string
+	portIDs         []string
+}
+
+func (opts firewallGroupUpdateOpts) ToFirewallGroupUpdateMap() (map[string]interface{}, error) {
+	policyID := func(id string) interface{} {
+		if id == "" {
+			return nil
+		}
+		return id
+	}
+	portIDs := opts.portIDs
+	if portIDs == nil {
+		portIDs = []string{}
+	}
+	return map[string]interface{}{
+		"firewall_group": map[string]interface{}{
+			"ingress_firewall_policy_id": policyID(opts.ingressPolicyID),
+			"egress_firewall_policy_id":  policyID(opts.egressPolicyID),
+			"ports":                      portIDs,
+		},
+	}, nil
+}
+
+// firewallGroupUpToDate returns whether the firewall group has the given
+// policies and ports.
+func firewallGroupUpToDate(group *fwgroups.Group, ingressPolicyID, egressPolicyID string, portIDs []string) bool {
+	if group.IngressFirewallPolicyID != ingressPolicyID || group.EgressFirewallPolicyID != egressPolicyID {
+		return false
+	}
+	if len(group.Ports) != len(portIDs) {
+		return false
+	}
+	groupPorts := append([]string{}, group.Ports...)
+	sort.Strings(groupPorts)
+	for i := range groupPorts {
+		if groupPorts[i] != portIDs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getFirewallPolicyID returns the ID of the firewall policy selected by the
+// filter, which must match exactly one firewall policy. It returns an empty
+// string without filter.
+func (s *Service) getFirewallPolicyID(filter *infrav1.FirewallPolicyFilter) (string, error) {
+	if filter == nil {
+		return "", nil
+	}
+	policyList, err := s.client.ListFirewallPolicy(policies.ListOpts{ID: filter.ID, Name: filter.Name})
+	if err != nil {
+		return "", err
+	}
+
+	policyName := filter.Name
+	if policyName == "" {
+		policyName = filter.ID
+	}
+	switch len(policyList) {
+	case 0:
+		return "", fmt.Errorf("firewall policy %s not found", policyName)
+	case 1:
+		return policyList[0].ID, nil
+	}
+	return "", fmt.Errorf("more than one firewall policy found named: %s", policyName)
+}
+
+func (s *Service) getFirewallGroupByName(name string) (*fwgroups.Group, error) {
+	groupList, err := s.client.ListFirewallGroup(fwgroups.ListOpts{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(groupList) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &groupList[0], nil
+	}
+	return nil, fmt.Errorf("more than one firewall group found named: %s", name)
+}
+
+func getRouterFirewallGroupName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", getRouterName(clusterName), "firewall")
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	fwgroups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/policies"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+)
+
+func Test_ReconcileRouterFirewall(t *testing.T) {
+	const (
+		clusterName = "test-cluster"
+		groupName   = "k8s-clusterapi-cluster-test-cluster-firewall"
+		routerID    = "router-id"
+	)
+	routerPorts := []ports.Port{
+		{ID: "interface-port", DeviceOwner: "network:router_interface"},
+		{ID: "gateway-port", DeviceOwner: "network:router_gateway"},
+	}
+
+	tests := []struct {
+		name        string
+		spec        *infrav1.RouterFirewall
+		status      *infrav1.FirewallGroup
+		expect      func(m *mock_networking.MockNetworkClientMockRecorder)
+		wantStatus  *infrav1.FirewallGroup
+		wantErrText string
+	}{
+		{
+			name: "creates the firewall group on the router interfaces",
+			spec: &infrav1.RouterFirewall{
+				IngressPolicy: &infrav1.FirewallPolicyFilter{Name: "cluster-ingress"},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFirewallPolicy(policies.ListOpts{Name: "cluster-ingress"}).Return([]policies.Policy{{ID: "ingress-policy-id"}}, nil)
+				m.ListPort(ports.ListOpts{DeviceID: routerID}).Return(routerPorts, nil)
+				m.ListFirewallGroup(fwgroups.ListOpts{Name: groupName}).Return(nil, nil)
+				m.CreateFirewallGroup(fwgroups.CreateOpts{
+					Name:                    groupName,
+					Description:             "Created by cluster-api-provider-openstack cluster test-cluster",
+					IngressFirewallPolicyID: "ingress-policy-id",
+					Ports:                   []string{"interface-port"},
+				}).Return(&fwgroups.Group{ID: "group-id", Name: groupName}, nil)
+			},
+			wantStatus: &infrav1.FirewallGroup{ID: "group-id", Name: groupName},
+		},
+		{
+			name: "leaves an up to date firewall group alone",
+			spec: &infrav1.RouterFirewall{
+				EgressPolicy: &infrav1.FirewallPolicyFilter{ID: "egress-policy-id"},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFirewallPolicy(policies.ListOpts{ID: "egress-policy-id"}).Return([]policies.Policy{{ID: "egress-policy-id"}}, nil)
+				m.ListPort(ports.ListOpts{DeviceID: routerID}).Return(routerPorts, nil)
+				m.ListFirewallGroup(fwgroups.ListOpts{Name: groupName}).Return([]fwgroups.Group{
+					{ID: "group-id", Name: groupName, EgressFirewallPolicyID: "egress-policy-id", Ports: []string{"interface-port"}},
+				}, nil)
+			},
+			wantStatus: &infrav1.FirewallGroup{ID: "group-id", Name: groupName},
+		},
+		{
+			name: "updates the policies of the firewall group",
+			spec: &infrav1.RouterFirewall{
+				EgressPolicy: &infrav1.FirewallPolicyFilter{ID: "egress-policy-id"},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFirewallPolicy(policies.ListOpts{ID: "egress-policy-id"}).Return([]policies.Policy{{ID: "egress-policy-id"}}, nil)
+				m.ListPort(ports.ListOpts{DeviceID: routerID}).Return(routerPorts, nil)
+				m.ListFirewallGroup(fwgroups.ListOpts{Name: groupName}).Return([]fwgroups.Group{
+					{ID: "group-id", Name: groupName, IngressFirewallPolicyID: "ingress-policy-id", Ports: []string{"interface-port"}},
+				}, nil)
+				m.UpdateFirewallGroup("group-id", firewallGroupUpdateOpts{
+					egressPolicyID: "egress-policy-id",
+					portIDs:        []string{"interface-port"},
+				}).Return(&fwgroups.Group{ID: "group-id", Name: groupName}, nil)
+			},
+			wantStatus: &infrav1.FirewallGroup{ID: "group-id", Name: groupName},
+		},
+		{
+			name: "fails when the policy does not exist",
+			spec: &infrav1.RouterFirewall{
+				IngressPolicy: &infrav1.FirewallPolicyFilter{Name: "cluster-ingress"},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFirewallPolicy(policies.ListOpts{Name: "cluster-ingress"}).Return(nil, nil)
+			},
+			wantErrText: "firewall policy cluster-ingress not found",
+		},
+		{
+			name:   "deletes the firewall group when the router firewall is removed",
+			status: &infrav1.FirewallGroup{ID: "group-id", Name: groupName},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFirewallGroup(fwgroups.ListOpts{Name: groupName}).Return([]fwgroups.Group{{ID: "group-id", Name: groupName}}, nil)
+				m.DeleteFirewallGroup("group-id").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("project-id", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{RouterFirewall: tt.spec},
+				Status: infrav1.OpenStackClusterStatus{
+					Network:             &infrav1.Network{Router: &infrav1.Router{ID: routerID}},
+					RouterFirewallGroup: tt.status,
+				},
+			}
+			err := s.ReconcileRouterFirewall(openStackCluster, clusterName)
+			if tt.wantErrText != "" {
+				g.Expect(err).To(MatchError(tt.wantErrText))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(openStackCluster.Status.RouterFirewallGroup).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+func Test_firewallGroupUpdateOpts(t *testing.T) {
+	g := NewWithT(t)
+
+	updateMap, err := firewallGroupUpdateOpts{ingressPolicyID: "ingress-policy-id"}.ToFirewallGroupUpdateMap()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(updateMap).To(Equal(map[string]interface{}{
+		"firewall_group": map[string]interface{}{
+			"ingress_firewall_policy_id": "ingress-policy-id",
+			"egress_firewall_policy_id":  nil,
+			"ports":                      []string{},
+		},
+	}))
+}
@@ -17,16 +17,20 @@ limitations under the License.
 package networking
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/cluster-api/util"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/approval"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
@@ -50,11 +54,16 @@ func (s *Service) GetOrCreateFloatingIP(eventObject runtime.Object, openStackClu
 	fpCreateOpts.FloatingNetworkID = openStackCluster.Status.ExternalNetwork.ID
 	fpCreateOpts.Description = names.GetDescription(clusterName)
 
+	if err := s.review(eventObject, approval.Request{Kind: approval.KindFloatingIP, FloatingIP: &approval.FloatingIP{NetworkID: fpCreateOpts.FloatingNetworkID, Address: ip}}); err != nil {
+		return nil, err
+	}
+
 	fp, err = s.client.CreateFloatingIP(fpCreateOpts)
 	if err != nil {
-		record.Warnf(eventObject, "FailedCreateFloatingIP", "Failed to create floating IP %s: %v", ip, err)
-		return nil, err
+		record.Failed(eventObject, record.Create, record.Resource{Kind: record.FloatingIP, Name: ip, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
+	fipEvent := record.Resource{Kind: record.FloatingIP, Name: fp.FloatingIP, ID: fp.ID, RequestID: s.scope.LastRequestID()}
 
 	if len(openStackCluster.Spec.Tags) > 0 {
 		mc := metrics.NewMetricPrometheusContext("floating_ip", "update")
@@ -66,10 +75,54 @@ func (s *Service) GetOrCreateFloatingIP(eventObject runtime.Object, openStackClu
 		}
 	}
 
-	record.Eventf(eventObject, "SuccessfulCreateFloatingIP", "Created floating IP %s with id %s", fp.FloatingIP, fp.ID)
+	record.Succeeded(eventObject, record.Create, fipEvent)
 	return fp, nil
 }
 
+// CreateFloatingIPForPool creates a floating IP for an OpenStackFloatingIPPool
+// in the given external network and, if it is not empty, subnet.
+func (s *Service) CreateFloatingIPForPool(openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool, networkID, subnetID string) (*floatingips.FloatingIP, error) {
+	if err := s.review(openStackFloatingIPPool, approval.Request{Kind: approval.KindFloatingIP, FloatingIP: &approval.FloatingIP{NetworkID: networkID}}); err != nil {
+		return nil, err
+	}
+
+	fp, err := s.client.CreateFloatingIP(floatingips.CreateOpts{
+		FloatingNetworkID: networkID,
+		SubnetID:          subnetID,
+		Description:       names.GetFloatingIPPoolDescription(openStackFloatingIPPool.Namespace, openStackFloatingIPPool.Name),
+	})
+	if err != nil {
+		record.Failed(openStackFloatingIPPool, record.Create, record.Resource{Kind: record.FloatingIP, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+
+	record.Succeeded(openStackFloatingIPPool, record.Create, record.Resource{Kind: record.FloatingIP, Name: fp.FloatingIP, ID: fp.ID, RequestID: s.scope.LastRequestID()})
+	return fp, nil
+}
+
+// ListFloatingIPsForPool returns the floating IPs created for an
+// OpenStackFloatingIPPool.
+func (s *Service) ListFloatingIPsForPool(openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool) ([]floatingips.FloatingIP, error) {
+	fipList, err := s.client.ListFloatingIP(floatingips.ListOpts{
+		Description: names.GetFloatingIPPoolDescription(openStackFloatingIPPool.Namespace, openStackFloatingIPPool.Name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list floating IPs of pool %s: %v", openStackFloatingIPPool.Name, err)
+	}
+	return fipList, nil
+}
+
+// ListFloatingIPsForCluster returns the floating IPs created for the cluster.
+func (s *Service) ListFloatingIPsForCluster(clusterName string) ([]floatingips.FloatingIP, error) {
+	fipList, err := s.client.ListFloatingIP(floatingips.ListOpts{
+		Description: names.GetDescription(clusterName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list floating IPs of cluster %s: %v", clusterName, err)
+	}
+	return fipList, nil
+}
+
 func (s *Service) GetFloatingIP(ip string) (*floatingips.FloatingIP, error) {
 	fpList, err := s.client.ListFloatingIP(floatingips.ListOpts{FloatingIP: ip})
 	if err != nil {
@@ -103,27 +156,47 @@ func (s *Service) DeleteFloatingIP(eventObject runtime.Object, ip string) error
 	}
 
 	err = s.client.DeleteFloatingIP(fip.ID)
+	fipEvent := record.Resource{Kind: record.FloatingIP, Name: ip, ID: fip.ID, RequestID: s.scope.LastRequestID()}
 	if err != nil {
-		record.Warnf(eventObject, "FailedDeleteFloatingIP", "Failed to delete floating IP %s: %v", ip, err)
-		return err
+		record.Failed(eventObject, record.Delete, fipEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
-	record.Eventf(eventObject, "SuccessfulDeleteFloatingIP", "Deleted floating IP %s", ip)
+	record.Succeeded(eventObject, record.Delete, fipEvent)
 	return nil
 }
 
-var backoff = wait.Backoff{
-	Steps:    10,
-	Duration: 30 * time.Second,
-	Factor:   1.0,
-	Jitter:   0.1,
+// DeleteOrphanedFloatingIPs deletes the floating IPs created for the cluster
+// which are not associated with a port, except for the given addresses.
+func (s *Service) DeleteOrphanedFloatingIPs(eventObject runtime.Object, clusterName string, keep ...string) error {
+	fipList, err := s.ListFloatingIPsForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	keepIPs := make(map[string]bool, len(keep))
+	for _, ip := range keep {
+		keepIPs[ip] = true
+	}
+	for _, fip := range fipList {
+		if fip.PortID != "" || keepIPs[fip.FloatingIP] {
+			continue
+		}
+		if err := s.DeleteFloatingIP(eventObject, fip.FloatingIP); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+const retryIntervalFloatingIP = 5 * time.Second
+
 func (s *Service) AssociateFloatingIP(eventObject runtime.Object, fp *floatingips.FloatingIP, portID string) error {
 	s.scope.Logger.Info("Associating floating IP", "id", fp.ID, "ip", fp.FloatingIP)
 
 	if fp.PortID == portID {
-		s.scope.Logger.Info("Floating IP already associated:", "id", fp.ID, "ip", fp.FloatingIP)
+		s.scope.Logger.V(logging.LevelDebug).Info("Floating IP already associated", "id", fp.ID, "ip", fp.FloatingIP)
 		return nil
 	}
 
@@ -132,17 +205,19 @@ func (s *Service) AssociateFloatingIP(eventObject runtime.Object, fp *floatingip
 	}
 
 	_, err := s.client.UpdateFloatingIP(fp.ID, fpUpdateOpts)
+	fipEvent := record.Resource{Kind: record.FloatingIP, Name: fp.FloatingIP, ID: fp.ID, RequestID: s.scope.LastRequestID(), Detail: "port " + portID}
 	if err != nil {
-		record.Warnf(eventObject, "FailedAssociateFloatingIP", "Failed to associate floating IP %s with port %s: %v", fp.FloatingIP, portID, err)
-		return err
+		record.Failed(eventObject, record.Associate, fipEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
 	if err = s.waitForFloatingIP(fp.ID, "ACTIVE"); err != nil {
-		record.Warnf(eventObject, "FailedAssociateFloatingIP", "Failed to associate floating IP %s with port %s: wait for floating IP ACTIVE: %v", fp.FloatingIP, portID, err)
+		fipEvent.Detail += ": wait for floating IP ACTIVE"
+		record.Failed(eventObject, record.Associate, fipEvent, err)
 		return err
 	}
 
-	record.Eventf(eventObject, "SuccessfulAssociateFloatingIP", "Associated floating IP %s with port %s", fp.FloatingIP, portID)
+	record.Succeeded(eventObject, record.Associate, fipEvent)
 	return nil
 }
 
@@ -152,7 +227,7 @@ func (s *Service) DisassociateFloatingIP(eventObject runtime.Object, ip string)
 		return err
 	}
 	if fip == nil || fip.FloatingIP == "" {
-		s.scope.Logger.Info("Floating IP not associated", "ip", ip)
+		s.scope.Logger.V(logging.LevelDebug).Info("Floating IP not associated", "ip", ip)
 		return nil
 	}
 
@@ -163,23 +238,25 @@ func (s *Service) DisassociateFloatingIP(eventObject runtime.Object, ip string)
 	}
 
 	_, err = s.client.UpdateFloatingIP(fip.ID, fpUpdateOpts)
+	fipEvent := record.Resource{Kind: record.FloatingIP, Name: fip.FloatingIP, ID: fip.ID, RequestID: s.scope.LastRequestID()}
 	if err != nil {
-		record.Warnf(eventObject, "FailedDisassociateFloatingIP", "Failed to disassociate floating IP %s: %v", fip.FloatingIP, err)
-		return err
+		record.Failed(eventObject, record.Disassociate, fipEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
 	if err = s.waitForFloatingIP(fip.ID, "DOWN"); err != nil {
-		record.Warnf(eventObject, "FailedDisassociateFloatingIP", "Failed to disassociate floating IP: wait for floating IP DOWN: %v", fip.FloatingIP, err)
+		fipEvent.Detail = "wait for floating IP DOWN"
+		record.Failed(eventObject, record.Disassociate, fipEvent, err)
 		return err
 	}
 
-	record.Eventf(eventObject, "SuccessfulDisassociateFloatingIP", "Disassociated floating IP %s", fip.FloatingIP)
+	record.Succeeded(eventObject, record.Disassociate, fipEvent)
 	return nil
 }
 
 func (s *Service) waitForFloatingIP(id, target string) error {
 	s.scope.Logger.Info("Waiting for floating IP", "id", id, "targetStatus", target)
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+	return util.PollImmediate(retryIntervalFloatingIP, s.floatingIPAssociateTimeout(), func() (bool, error) {
 		fip, err := s.client.GetFloatingIP(id)
 		if err != nil {
 			return false, err
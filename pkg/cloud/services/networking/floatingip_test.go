@@ -17,13 +17,19 @@ limitations under the License.
 package networking
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	. "github.com/onsi/gomega"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/approval"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
 )
 
@@ -84,3 +90,74 @@ func Test_GetOrCreateFloatingIP(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetOrCreateFloatingIPDenied(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(approval.Response{Allowed: false, Reason: "not approved"})
+	}))
+	defer server.Close()
+	webhook, err := approval.NewWebhook(server.URL, "", time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	approval.SetWebhook(webhook)
+	defer approval.SetWebhook(nil)
+
+	// The floating IP is not created
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().ListFloatingIP(floatingips.ListOpts{FloatingIP: "192.168.111.0"}).Return([]floatingips.FloatingIP{}, nil)
+	s := Service{
+		client: mockClient,
+	}
+	openStackCluster := &infrav1.OpenStackCluster{Status: infrav1.OpenStackClusterStatus{
+		ExternalNetwork: &infrav1.Network{ID: "external"},
+	}}
+	_, err = s.GetOrCreateFloatingIP(&infrav1.OpenStackMachine{}, openStackCluster, "test-cluster", "192.168.111.0")
+	g.Expect(approval.IsDenied(err)).To(BeTrue())
+}
+
+func Test_DeleteOrphanedFloatingIPs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	description := "Created by cluster-api-provider-openstack cluster test-cluster"
+
+	tests := []struct {
+		name   string
+		keep   []string
+		expect func(m *mock_networking.MockNetworkClientMockRecorder)
+	}{
+		{
+			name: "deletes unassociated floating IPs",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(floatingips.ListOpts{Description: description}).Return([]floatingips.FloatingIP{
+					{ID: "associated", FloatingIP: "192.168.111.1", PortID: "port"},
+					{ID: "orphaned", FloatingIP: "192.168.111.2"},
+				}, nil)
+				m.ListFloatingIP(floatingips.ListOpts{FloatingIP: "192.168.111.2"}).Return([]floatingips.FloatingIP{{ID: "orphaned", FloatingIP: "192.168.111.2"}}, nil)
+				m.DeleteFloatingIP("orphaned").Return(nil)
+			},
+		},
+		{
+			name: "keeps the given floating IPs",
+			keep: []string{"192.168.111.2"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListFloatingIP(floatingips.ListOpts{Description: description}).Return([]floatingips.FloatingIP{
+					{ID: "kept", FloatingIP: "192.168.111.2"},
+				}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("", mockClient, logr.Discard())
+			err := s.DeleteOrphanedFloatingIPs(&infrav1.OpenStackCluster{}, "test-cluster", tt.keep...)
+			g.Expect(err).ShouldNot(HaveOccurred())
+		})
+	}
+}
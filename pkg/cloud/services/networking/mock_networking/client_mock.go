@@ -26,9 +26,12 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	extensions "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions"
 	attributestags "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	groups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/groups"
+	policies "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/fwaas_v2/policies"
 	floatingips "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	routers "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
-	groups "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	quotas "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
+	groups0 "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	rules "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
 	trunks "github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
 	networks "github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
@@ -74,6 +77,21 @@ func (mr *MockNetworkClientMockRecorder) AddRouterInterface(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRouterInterface", reflect.TypeOf((*MockNetworkClient)(nil).AddRouterInterface), arg0, arg1)
 }
 
+// CreateFirewallGroup mocks base method.
+func (m *MockNetworkClient) CreateFirewallGroup(arg0 groups.CreateOptsBuilder) (*groups.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFirewallGroup", arg0)
+	ret0, _ := ret[0].(*groups.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFirewallGroup indicates an expected call of CreateFirewallGroup.
+func (mr *MockNetworkClientMockRecorder) CreateFirewallGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFirewallGroup", reflect.TypeOf((*MockNetworkClient)(nil).CreateFirewallGroup), arg0)
+}
+
 // CreateFloatingIP mocks base method.
 func (m *MockNetworkClient) CreateFloatingIP(arg0 floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
@@ -135,10 +153,10 @@ func (mr *MockNetworkClientMockRecorder) CreateRouter(arg0 interface{}) *gomock.
 }
 
 // CreateSecGroup mocks base method.
-func (m *MockNetworkClient) CreateSecGroup(arg0 groups.CreateOptsBuilder) (*groups.SecGroup, error) {
+func (m *MockNetworkClient) CreateSecGroup(arg0 groups0.CreateOptsBuilder) (*groups0.SecGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateSecGroup", arg0)
-	ret0, _ := ret[0].(*groups.SecGroup)
+	ret0, _ := ret[0].(*groups0.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -194,6 +212,20 @@ func (mr *MockNetworkClientMockRecorder) CreateTrunk(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrunk", reflect.TypeOf((*MockNetworkClient)(nil).CreateTrunk), arg0)
 }
 
+// DeleteFirewallGroup mocks base method.
+func (m *MockNetworkClient) DeleteFirewallGroup(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFirewallGroup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFirewallGroup indicates an expected call of DeleteFirewallGroup.
+func (mr *MockNetworkClientMockRecorder) DeleteFirewallGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFirewallGroup", reflect.TypeOf((*MockNetworkClient)(nil).DeleteFirewallGroup), arg0)
+}
+
 // DeleteFloatingIP mocks base method.
 func (m *MockNetworkClient) DeleteFloatingIP(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -306,6 +338,20 @@ func (mr *MockNetworkClientMockRecorder) DeleteTrunk(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrunk", reflect.TypeOf((*MockNetworkClient)(nil).DeleteTrunk), arg0)
 }
 
+// EachPortPage mocks base method.
+func (m *MockNetworkClient) EachPortPage(arg0 ports.ListOptsBuilder, arg1 func([]ports.Port) (bool, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachPortPage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachPortPage indicates an expected call of EachPortPage.
+func (mr *MockNetworkClientMockRecorder) EachPortPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachPortPage", reflect.TypeOf((*MockNetworkClient)(nil).EachPortPage), arg0, arg1)
+}
+
 // GetFloatingIP mocks base method.
 func (m *MockNetworkClient) GetFloatingIP(arg0 string) (*floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
@@ -336,6 +382,21 @@ func (mr *MockNetworkClientMockRecorder) GetNetwork(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetwork", reflect.TypeOf((*MockNetworkClient)(nil).GetNetwork), arg0)
 }
 
+// GetNetworkMTU mocks base method.
+func (m *MockNetworkClient) GetNetworkMTU(arg0 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkMTU", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkMTU indicates an expected call of GetNetworkMTU.
+func (mr *MockNetworkClientMockRecorder) GetNetworkMTU(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkMTU", reflect.TypeOf((*MockNetworkClient)(nil).GetNetworkMTU), arg0)
+}
+
 // GetPort mocks base method.
 func (m *MockNetworkClient) GetPort(arg0 string) (*ports.Port, error) {
 	m.ctrl.T.Helper()
@@ -351,6 +412,36 @@ func (mr *MockNetworkClientMockRecorder) GetPort(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPort", reflect.TypeOf((*MockNetworkClient)(nil).GetPort), arg0)
 }
 
+// GetQuota mocks base method.
+func (m *MockNetworkClient) GetQuota(arg0 string) (*quotas.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuota", arg0)
+	ret0, _ := ret[0].(*quotas.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuota indicates an expected call of GetQuota.
+func (mr *MockNetworkClientMockRecorder) GetQuota(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuota", reflect.TypeOf((*MockNetworkClient)(nil).GetQuota), arg0)
+}
+
+// GetQuotaDetail mocks base method.
+func (m *MockNetworkClient) GetQuotaDetail(arg0 string) (*quotas.QuotaDetailSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaDetail", arg0)
+	ret0, _ := ret[0].(*quotas.QuotaDetailSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaDetail indicates an expected call of GetQuotaDetail.
+func (mr *MockNetworkClientMockRecorder) GetQuotaDetail(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaDetail", reflect.TypeOf((*MockNetworkClient)(nil).GetQuotaDetail), arg0)
+}
+
 // GetRouter mocks base method.
 func (m *MockNetworkClient) GetRouter(arg0 string) (*routers.Router, error) {
 	m.ctrl.T.Helper()
@@ -367,10 +458,10 @@ func (mr *MockNetworkClientMockRecorder) GetRouter(arg0 interface{}) *gomock.Cal
 }
 
 // GetSecGroup mocks base method.
-func (m *MockNetworkClient) GetSecGroup(arg0 string) (*groups.SecGroup, error) {
+func (m *MockNetworkClient) GetSecGroup(arg0 string) (*groups0.SecGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSecGroup", arg0)
-	ret0, _ := ret[0].(*groups.SecGroup)
+	ret0, _ := ret[0].(*groups0.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -426,6 +517,36 @@ func (mr *MockNetworkClientMockRecorder) ListExtensions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExtensions", reflect.TypeOf((*MockNetworkClient)(nil).ListExtensions))
 }
 
+// ListFirewallGroup mocks base method.
+func (m *MockNetworkClient) ListFirewallGroup(arg0 groups.ListOptsBuilder) ([]groups.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFirewallGroup", arg0)
+	ret0, _ := ret[0].([]groups.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFirewallGroup indicates an expected call of ListFirewallGroup.
+func (mr *MockNetworkClientMockRecorder) ListFirewallGroup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFirewallGroup", reflect.TypeOf((*MockNetworkClient)(nil).ListFirewallGroup), arg0)
+}
+
+// ListFirewallPolicy mocks base method.
+func (m *MockNetworkClient) ListFirewallPolicy(arg0 policies.ListOptsBuilder) ([]policies.Policy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFirewallPolicy", arg0)
+	ret0, _ := ret[0].([]policies.Policy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFirewallPolicy indicates an expected call of ListFirewallPolicy.
+func (mr *MockNetworkClientMockRecorder) ListFirewallPolicy(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFirewallPolicy", reflect.TypeOf((*MockNetworkClient)(nil).ListFirewallPolicy), arg0)
+}
+
 // ListFloatingIP mocks base method.
 func (m *MockNetworkClient) ListFloatingIP(arg0 floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
@@ -487,10 +608,10 @@ func (mr *MockNetworkClientMockRecorder) ListRouter(arg0 interface{}) *gomock.Ca
 }
 
 // ListSecGroup mocks base method.
-func (m *MockNetworkClient) ListSecGroup(arg0 groups.ListOpts) ([]groups.SecGroup, error) {
+func (m *MockNetworkClient) ListSecGroup(arg0 groups0.ListOpts) ([]groups0.SecGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListSecGroup", arg0)
-	ret0, _ := ret[0].([]groups.SecGroup)
+	ret0, _ := ret[0].([]groups0.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -561,6 +682,20 @@ func (mr *MockNetworkClientMockRecorder) RemoveRouterInterface(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRouterInterface", reflect.TypeOf((*MockNetworkClient)(nil).RemoveRouterInterface), arg0, arg1)
 }
 
+// RemoveSubports mocks base method.
+func (m *MockNetworkClient) RemoveSubports(arg0 string, arg1 trunks.RemoveSubportsOptsBuilder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSubports", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSubports indicates an expected call of RemoveSubports.
+func (mr *MockNetworkClientMockRecorder) RemoveSubports(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSubports", reflect.TypeOf((*MockNetworkClient)(nil).RemoveSubports), arg0, arg1)
+}
+
 // ReplaceAllAttributesTags mocks base method.
 func (m *MockNetworkClient) ReplaceAllAttributesTags(arg0, arg1 string, arg2 attributestags.ReplaceAllOptsBuilder) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -576,6 +711,21 @@ func (mr *MockNetworkClientMockRecorder) ReplaceAllAttributesTags(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceAllAttributesTags", reflect.TypeOf((*MockNetworkClient)(nil).ReplaceAllAttributesTags), arg0, arg1, arg2)
 }
 
+// UpdateFirewallGroup mocks base method.
+func (m *MockNetworkClient) UpdateFirewallGroup(arg0 string, arg1 groups.UpdateOptsBuilder) (*groups.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFirewallGroup", arg0, arg1)
+	ret0, _ := ret[0].(*groups.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFirewallGroup indicates an expected call of UpdateFirewallGroup.
+func (mr *MockNetworkClientMockRecorder) UpdateFirewallGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFirewallGroup", reflect.TypeOf((*MockNetworkClient)(nil).UpdateFirewallGroup), arg0, arg1)
+}
+
 // UpdateFloatingIP mocks base method.
 func (m *MockNetworkClient) UpdateFloatingIP(arg0 string, arg1 floatingips.UpdateOptsBuilder) (*floatingips.FloatingIP, error) {
 	m.ctrl.T.Helper()
@@ -637,10 +787,10 @@ func (mr *MockNetworkClientMockRecorder) UpdateRouter(arg0, arg1 interface{}) *g
 }
 
 // UpdateSecGroup mocks base method.
-func (m *MockNetworkClient) UpdateSecGroup(arg0 string, arg1 groups.UpdateOptsBuilder) (*groups.SecGroup, error) {
+func (m *MockNetworkClient) UpdateSecGroup(arg0 string, arg1 groups0.UpdateOptsBuilder) (*groups0.SecGroup, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateSecGroup", arg0, arg1)
-	ret0, _ := ret[0].(*groups.SecGroup)
+	ret0, _ := ret[0].(*groups0.SecGroup)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -18,6 +18,7 @@ package networking
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
@@ -25,9 +26,11 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
@@ -82,7 +85,7 @@ func (s *Service) ReconcileExternalNetwork(openStackCluster *infrav1.OpenStackCl
 			Name: networkList[0].Name,
 			Tags: networkList[0].Tags,
 		}
-		s.scope.Logger.Info("External network found", "network id", networkList[0].ID)
+		s.scope.Logger.Info("External network found", "id", networkList[0].ID)
 		return nil
 	}
 	return fmt.Errorf("found %d external networks, which should not happen", len(networkList))
@@ -92,23 +95,32 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 	networkName := getNetworkName(clusterName)
 	s.scope.Logger.Info("Reconciling network", "name", networkName)
 
-	res, err := s.getNetworkByName(networkName)
+	res, err := s.getClusterNetwork(openStackCluster, networkName)
 	if err != nil {
 		return err
 	}
 
 	if res.ID != "" {
 		// Network exists
-		openStackCluster.Status.Network = &infrav1.Network{
-			ID:   res.ID,
-			Name: res.Name,
-			Tags: res.Tags,
+		if openStackCluster.Status.Network == nil || openStackCluster.Status.Network.ID != res.ID {
+			if openStackCluster.Status.Network != nil && openStackCluster.Status.Network.ID != "" {
+				record.Warnf(openStackCluster, "MissingNetwork", "Network %s with id %s was replaced by network with id %s", networkName, openStackCluster.Status.Network.ID, res.ID)
+			} else {
+				record.Eventf(openStackCluster, "AdoptedNetwork", "Adopted existing network %s with id %s", networkName, res.ID)
+			}
+			openStackCluster.Status.Network = &infrav1.Network{}
 		}
-		sInfo := fmt.Sprintf("Reuse Existing Network %s with id %s", res.Name, res.ID)
-		s.scope.Logger.V(6).Info(sInfo)
+		openStackCluster.Status.Network.ID = res.ID
+		openStackCluster.Status.Network.Name = res.Name
+		openStackCluster.Status.Network.Tags = res.Tags
+		s.scope.Logger.V(logging.LevelDebug).Info("Reusing existing network", "name", res.Name, "id", res.ID)
 		return nil
 	}
 
+	if openStackCluster.Status.Network != nil && openStackCluster.Status.Network.ID != "" {
+		record.Warnf(openStackCluster, "MissingNetwork", "Network %s with id %s no longer exists, recreating it", networkName, openStackCluster.Status.Network.ID)
+	}
+
 	var opts createOpts
 	if openStackCluster.Spec.DisablePortSecurity {
 		opts = createOpts{
@@ -147,9 +159,70 @@ func (s *Service) ReconcileNetwork(openStackCluster *infrav1.OpenStackCluster, c
 	return nil
 }
 
+// AdoptNetwork sets the network, subnet and router in the status of the
+// OpenStackCluster to the existing resources with the given IDs, without
+// changing them. Empty IDs and resources which no longer exist are skipped.
+func (s *Service) AdoptNetwork(openStackCluster *infrav1.OpenStackCluster, networkID, subnetID, routerID string) error {
+	if networkID == "" {
+		return nil
+	}
+	network, err := s.client.GetNetwork(networkID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			s.scope.Logger.Info("Network to adopt no longer exists", "id", networkID)
+			return nil
+		}
+		return err
+	}
+	openStackCluster.Status.Network = &infrav1.Network{
+		ID:   network.ID,
+		Name: network.Name,
+		Tags: network.Tags,
+	}
+
+	if subnetID != "" {
+		subnet, err := s.client.GetSubnet(subnetID)
+		switch {
+		case capoerrors.IsNotFound(err):
+			s.scope.Logger.Info("Subnet to adopt no longer exists", "id", subnetID)
+		case err != nil:
+			return err
+		default:
+			openStackCluster.Status.Network.Subnet = &infrav1.Subnet{
+				ID:   subnet.ID,
+				Name: subnet.Name,
+				CIDR: subnet.CIDR,
+				Tags: subnet.Tags,
+			}
+		}
+	}
+
+	if routerID != "" {
+		router, err := s.client.GetRouter(routerID)
+		switch {
+		case capoerrors.IsNotFound(err):
+			s.scope.Logger.Info("Router to adopt no longer exists", "id", routerID)
+		case err != nil:
+			return err
+		default:
+			routerIPs := []string{}
+			for _, ip := range router.GatewayInfo.ExternalFixedIPs {
+				routerIPs = append(routerIPs, ip.IPAddress)
+			}
+			openStackCluster.Status.Network.Router = &infrav1.Router{
+				Name: router.Name,
+				ID:   router.ID,
+				Tags: router.Tags,
+				IPs:  routerIPs,
+			}
+		}
+	}
+	return nil
+}
+
 func (s *Service) DeleteNetwork(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
 	networkName := getNetworkName(clusterName)
-	network, err := s.getNetworkByName(networkName)
+	network, err := s.getClusterNetwork(openStackCluster, networkName)
 	if err != nil {
 		return err
 	}
@@ -169,7 +242,7 @@ func (s *Service) DeleteNetwork(openStackCluster *infrav1.OpenStackCluster, clus
 
 func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
 	if openStackCluster.Status.Network == nil || openStackCluster.Status.Network.ID == "" {
-		s.scope.Logger.V(4).Info("No need to reconcile network components since no network exists.")
+		s.scope.Logger.V(logging.LevelDebug).Info("No need to reconcile network components since no network exists")
 		return nil
 	}
 
@@ -185,19 +258,31 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 	}
 
 	if len(subnetList) > 1 {
-		return fmt.Errorf("found %d subnets with the name %s, which should not happen", len(subnetList), subnetName)
+		return fmt.Errorf("found %d subnets with the CIDR %s, which should not happen", len(subnetList), openStackCluster.Spec.NodeCIDR)
 	}
 
 	var subnet *subnets.Subnet
 	if len(subnetList) == 0 {
+		if openStackCluster.Status.Network.Subnet != nil && openStackCluster.Status.Network.Subnet.ID != "" {
+			record.Warnf(openStackCluster, "MissingSubnet", "Subnet %s with id %s no longer exists, recreating it", subnetName, openStackCluster.Status.Network.Subnet.ID)
+		}
 		var err error
-		subnet, err = s.createSubnet(openStackCluster, clusterName, subnetName)
+		subnet, err = s.createSubnet(openStackCluster, clusterName, subnetName, openStackCluster.Spec.NodeCIDR)
 		if err != nil {
 			return err
 		}
 	} else if len(subnetList) == 1 {
 		subnet = &subnetList[0]
-		s.scope.Logger.V(6).Info(fmt.Sprintf("Reuse existing subnet %s with id %s", subnetName, subnet.ID))
+		s.scope.Logger.V(logging.LevelDebug).Info("Reusing existing subnet", "name", subnetName, "id", subnet.ID)
+		if openStackCluster.Status.Network.Subnet == nil || openStackCluster.Status.Network.Subnet.ID == "" {
+			record.Eventf(openStackCluster, "AdoptedSubnet", "Adopted existing subnet %s with id %s", subnet.Name, subnet.ID)
+		}
+
+		if !equalDNSNameservers(subnet.DNSNameservers, openStackCluster.Spec.DNSNameservers) {
+			if subnet, err = s.updateSubnetDNSNameservers(openStackCluster, subnet); err != nil {
+				return err
+			}
+		}
 	}
 
 	openStackCluster.Status.Network.Subnet = &infrav1.Subnet{
@@ -209,12 +294,61 @@ func (s *Service) ReconcileSubnet(openStackCluster *infrav1.OpenStackCluster, cl
 	return nil
 }
 
-func (s *Service) createSubnet(openStackCluster *infrav1.OpenStackCluster, clusterName string, name string) (*subnets.Subnet, error) {
+// ReconcileFailureDomainSubnets creates the subnets of the failure domains
+// which set a nodeCidr in the network of the cluster, and returns their IDs
+// by failure domain.
+func (s *Service) ReconcileFailureDomainSubnets(openStackCluster *infrav1.OpenStackCluster, clusterName string) (map[string]string, error) {
+	subnetIDs := map[string]string{}
+	if openStackCluster.Spec.FailureDomains == nil || openStackCluster.Status.Network == nil || openStackCluster.Status.Network.ID == "" {
+		return subnetIDs, nil
+	}
+
+	for _, mapping := range openStackCluster.Spec.FailureDomains.Mappings {
+		if mapping.NodeCIDR == "" {
+			continue
+		}
+
+		subnetName := getFailureDomainSubnetName(clusterName, mapping.Name)
+		s.scope.Logger.Info("Reconciling subnet of failure domain", "name", subnetName, "failureDomain", mapping.Name)
+
+		subnetList, err := s.client.ListSubnet(subnets.ListOpts{
+			NetworkID: openStackCluster.Status.Network.ID,
+			CIDR:      mapping.NodeCIDR,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var subnet *subnets.Subnet
+		switch len(subnetList) {
+		case 0:
+			subnet, err = s.createSubnet(openStackCluster, clusterName, subnetName, mapping.NodeCIDR)
+			if err != nil {
+				return nil, err
+			}
+		case 1:
+			subnet = &subnetList[0]
+			s.scope.Logger.V(logging.LevelDebug).Info("Reusing existing subnet", "name", subnetName, "id", subnet.ID)
+
+			if !equalDNSNameservers(subnet.DNSNameservers, openStackCluster.Spec.DNSNameservers) {
+				if subnet, err = s.updateSubnetDNSNameservers(openStackCluster, subnet); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("found %d subnets with the CIDR %s, which should not happen", len(subnetList), mapping.NodeCIDR)
+		}
+		subnetIDs[mapping.Name] = subnet.ID
+	}
+	return subnetIDs, nil
+}
+
+func (s *Service) createSubnet(openStackCluster *infrav1.OpenStackCluster, clusterName string, name string, cidr string) (*subnets.Subnet, error) {
 	opts := subnets.CreateOpts{
 		NetworkID:      openStackCluster.Status.Network.ID,
 		Name:           name,
 		IPVersion:      4,
-		CIDR:           openStackCluster.Spec.NodeCIDR,
+		CIDR:           cidr,
 		DNSNameservers: openStackCluster.Spec.DNSNameservers,
 		Description:    names.GetDescription(clusterName),
 	}
@@ -239,6 +373,38 @@ func (s *Service) createSubnet(openStackCluster *infrav1.OpenStackCluster, clust
 	return subnet, nil
 }
 
+// updateSubnetDNSNameservers sets the DNS nameservers of the subnet to the ones
+// in the spec of the OpenStackCluster.
+func (s *Service) updateSubnetDNSNameservers(openStackCluster *infrav1.OpenStackCluster, subnet *subnets.Subnet) (*subnets.Subnet, error) {
+	dnsNameservers := openStackCluster.Spec.DNSNameservers
+	if dnsNameservers == nil {
+		dnsNameservers = []string{}
+	}
+
+	updated, err := s.client.UpdateSubnet(subnet.ID, subnets.UpdateOpts{
+		DNSNameservers: &dnsNameservers,
+	})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateSubnet", "Failed to update DNS nameservers of subnet %s with id %s: %v", subnet.Name, subnet.ID, err)
+		return nil, err
+	}
+	record.Eventf(openStackCluster, "SuccessfulUpdateSubnet", "Updated DNS nameservers of subnet %s with id %s", subnet.Name, subnet.ID)
+	return updated, nil
+}
+
+// equalDNSNameservers returns true if both lists contain the same nameservers in the same order.
+func equalDNSNameservers(observed, desired []string) bool {
+	if len(observed) != len(desired) {
+		return false
+	}
+	for i := range observed {
+		if observed[i] != desired[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Service) getNetworkByID(networkID string) (networks.Network, error) {
 	opts := networks.ListOpts{
 		ID: networkID,
@@ -258,12 +424,13 @@ func (s *Service) getNetworkByID(networkID string) (networks.Network, error) {
 	return networks.Network{}, fmt.Errorf("found %d networks with id %s, which should not happen", len(networkList), networkID)
 }
 
-func (s *Service) getNetworkByName(networkName string) (networks.Network, error) {
-	opts := networks.ListOpts{
-		Name: networkName,
-	}
-
-	networkList, err := s.client.ListNetwork(opts)
+// getClusterNetwork returns the network of the cluster with the given name,
+// or an empty network if there is none. Several networks have the name if the
+// network was created again after the status of the OpenStackCluster was lost.
+// The network in the status is used then, or else the only one with the tags
+// of the cluster and a subnet with its node CIDR.
+func (s *Service) getClusterNetwork(openStackCluster *infrav1.OpenStackCluster, networkName string) (networks.Network, error) {
+	networkList, err := s.client.ListNetwork(networks.ListOpts{Name: networkName})
 	if err != nil {
 		return networks.Network{}, err
 	}
@@ -274,7 +441,35 @@ func (s *Service) getNetworkByName(networkName string) (networks.Network, error)
 	case 1:
 		return networkList[0], nil
 	}
-	return networks.Network{}, fmt.Errorf("found %d networks with the name %s, which should not happen", len(networkList), networkName)
+
+	if openStackCluster.Status.Network != nil {
+		for _, network := range networkList {
+			if network.ID == openStackCluster.Status.Network.ID {
+				return network, nil
+			}
+		}
+	}
+
+	taggedList, err := s.client.ListNetwork(networks.ListOpts{Name: networkName, Tags: strings.Join(openStackCluster.Spec.Tags, ",")})
+	if err != nil {
+		return networks.Network{}, err
+	}
+	var candidates []networks.Network
+	for _, network := range taggedList {
+		subnetList, err := s.client.ListSubnet(subnets.ListOpts{NetworkID: network.ID, CIDR: openStackCluster.Spec.NodeCIDR})
+		if err != nil {
+			return networks.Network{}, err
+		}
+		if len(subnetList) > 0 {
+			candidates = append(candidates, network)
+		}
+	}
+	if len(candidates) != 1 {
+		return networks.Network{}, fmt.Errorf("found %d networks with the name %s, of which %d have the tags of the cluster and a subnet with its node CIDR", len(networkList), networkName, len(candidates))
+	}
+
+	record.Warnf(openStackCluster, "DuplicateNetwork", "Found %d networks with the name %s, using network %s with the tags of the cluster and a subnet with its node CIDR", len(networkList), networkName, candidates[0].ID)
+	return candidates[0], nil
 }
 
 // GetNetworksByFilter retrieves networks by querying openstack with filters.
@@ -292,6 +487,21 @@ func (s *Service) GetNetworksByFilter(opts networks.ListOptsBuilder) ([]networks
 	return networkList, nil
 }
 
+// NetworkExists returns whether a network matches the filter.
+func (s *Service) NetworkExists(networkFilter infrav1.NetworkFilter) (bool, error) {
+	listOpts := networkFilter.ToListOpt()
+	networkList, err := s.client.ListNetwork(&listOpts)
+	if err != nil {
+		return false, err
+	}
+	return len(networkList) > 0, nil
+}
+
+// GetNetworkMTU returns the MTU of the network with the given id.
+func (s *Service) GetNetworkMTU(networkID string) (int, error) {
+	return s.client.GetNetworkMTU(networkID)
+}
+
 // GetNetworkIDsByFilter retrieves network ids by querying openstack with filters.
 func (s *Service) GetNetworkIDsByFilter(opts networks.ListOptsBuilder) ([]string, error) {
 	nets, err := s.GetNetworksByFilter(opts)
@@ -324,6 +534,10 @@ func getSubnetName(clusterName string) string {
 	return fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
 }
 
+func getFailureDomainSubnetName(clusterName, failureDomain string) string {
+	return fmt.Sprintf("%s-cluster-%s-%s", networkPrefix, clusterName, failureDomain)
+}
+
 func getNetworkName(clusterName string) string {
 	return fmt.Sprintf("%s-cluster-%s", networkPrefix, clusterName)
 }
@@ -0,0 +1,345 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+)
+
+func Test_ReconcileNetwork(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name          string
+		spec          infrav1.OpenStackClusterSpec
+		status        *infrav1.Network
+		expect        func(m *mock_networking.MockNetworkClientMockRecorder)
+		want          *infrav1.Network
+		wantCreateNet bool
+		wantErr       bool
+	}{
+		{
+			name: "keeps subnet of unchanged network",
+			status: &infrav1.Network{
+				ID:     "network-id",
+				Subnet: &infrav1.Subnet{ID: "subnet-id"},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster"}).
+					Return([]networks.Network{{ID: "network-id", Name: "k8s-clusterapi-cluster-test-cluster"}}, nil)
+			},
+			want: &infrav1.Network{
+				ID:     "network-id",
+				Name:   "k8s-clusterapi-cluster-test-cluster",
+				Subnet: &infrav1.Subnet{ID: "subnet-id"},
+			},
+		},
+		{
+			name: "resets status of replaced network",
+			status: &infrav1.Network{
+				ID:     "network-id",
+				Subnet: &infrav1.Subnet{ID: "subnet-id"},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster"}).
+					Return([]networks.Network{{ID: "new-network-id", Name: "k8s-clusterapi-cluster-test-cluster"}}, nil)
+			},
+			want: &infrav1.Network{
+				ID:   "new-network-id",
+				Name: "k8s-clusterapi-cluster-test-cluster",
+			},
+		},
+		{
+			name: "recreates deleted network",
+			status: &infrav1.Network{
+				ID: "network-id",
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster"}).
+					Return([]networks.Network{}, nil)
+				m.CreateNetwork(gomock.Any()).
+					Return(&networks.Network{ID: "new-network-id", Name: "k8s-clusterapi-cluster-test-cluster"}, nil)
+			},
+			want: &infrav1.Network{
+				ID:   "new-network-id",
+				Name: "k8s-clusterapi-cluster-test-cluster",
+			},
+		},
+		{
+			name: "adopts existing network without status",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster"}).
+					Return([]networks.Network{{ID: "network-id", Name: "k8s-clusterapi-cluster-test-cluster"}}, nil)
+			},
+			want: &infrav1.Network{
+				ID:   "network-id",
+				Name: "k8s-clusterapi-cluster-test-cluster",
+			},
+		},
+		{
+			name: "keeps network in status of duplicate networks",
+			status: &infrav1.Network{
+				ID:     "network-id",
+				Subnet: &infrav1.Subnet{ID: "subnet-id"},
+			},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster"}).
+					Return([]networks.Network{
+						{ID: "other-network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+						{ID: "network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+					}, nil)
+			},
+			want: &infrav1.Network{
+				ID:     "network-id",
+				Name:   "k8s-clusterapi-cluster-test-cluster",
+				Subnet: &infrav1.Subnet{ID: "subnet-id"},
+			},
+		},
+		{
+			name: "adopts duplicate network with tags and node CIDR",
+			spec: infrav1.OpenStackClusterSpec{NodeCIDR: "10.0.0.0/24", Tags: []string{"tag1", "tag2"}},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster"}).
+					Return([]networks.Network{
+						{ID: "other-network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+						{ID: "untagged-network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+						{ID: "network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+					}, nil)
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster", Tags: "tag1,tag2"}).
+					Return([]networks.Network{
+						{ID: "other-network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+						{ID: "network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+					}, nil)
+				m.ListSubnet(subnets.ListOpts{NetworkID: "other-network-id", CIDR: "10.0.0.0/24"}).
+					Return([]subnets.Subnet{}, nil)
+				m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.0.0/24"}).
+					Return([]subnets.Subnet{{ID: "subnet-id"}}, nil)
+			},
+			want: &infrav1.Network{
+				ID:   "network-id",
+				Name: "k8s-clusterapi-cluster-test-cluster",
+			},
+		},
+		{
+			name: "fails on ambiguous duplicate networks",
+			spec: infrav1.OpenStackClusterSpec{NodeCIDR: "10.0.0.0/24"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				duplicates := []networks.Network{
+					{ID: "other-network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+					{ID: "network-id", Name: "k8s-clusterapi-cluster-test-cluster"},
+				}
+				m.ListNetwork(networks.ListOpts{Name: "k8s-clusterapi-cluster-test-cluster"}).
+					Return(duplicates, nil).Times(2)
+				m.ListSubnet(subnets.ListOpts{NetworkID: "other-network-id", CIDR: "10.0.0.0/24"}).
+					Return([]subnets.Subnet{{ID: "other-subnet-id"}}, nil)
+				m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.0.0/24"}).
+					Return([]subnets.Subnet{{ID: "subnet-id"}}, nil)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec:   tt.spec,
+				Status: infrav1.OpenStackClusterStatus{Network: tt.status},
+			}
+			err := s.ReconcileNetwork(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_ReconcileSubnet(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	tests := []struct {
+		name           string
+		dnsNameservers []string
+		expect         func(m *mock_networking.MockNetworkClientMockRecorder)
+	}{
+		{
+			name:           "reuses existing subnet",
+			dnsNameservers: []string{"8.8.8.8"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.0.0/24"}).
+					Return([]subnets.Subnet{{ID: "subnet-id", DNSNameservers: []string{"8.8.8.8"}}}, nil)
+			},
+		},
+		{
+			name:           "corrects changed DNS nameservers",
+			dnsNameservers: []string{"8.8.8.8"},
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.0.0/24"}).
+					Return([]subnets.Subnet{{ID: "subnet-id", DNSNameservers: []string{"1.1.1.1"}}}, nil)
+				m.UpdateSubnet("subnet-id", subnets.UpdateOpts{DNSNameservers: &[]string{"8.8.8.8"}}).
+					Return(&subnets.Subnet{ID: "subnet-id", DNSNameservers: []string{"8.8.8.8"}}, nil)
+			},
+		},
+		{
+			name: "removes DNS nameservers not in spec",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.0.0/24"}).
+					Return([]subnets.Subnet{{ID: "subnet-id", DNSNameservers: []string{"1.1.1.1"}}}, nil)
+				m.UpdateSubnet("subnet-id", subnets.UpdateOpts{DNSNameservers: &[]string{}}).
+					Return(&subnets.Subnet{ID: "subnet-id"}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					NodeCIDR:       "10.0.0.0/24",
+					DNSNameservers: tt.dnsNameservers,
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: &infrav1.Network{ID: "network-id"},
+				},
+			}
+			err := s.ReconcileSubnet(openStackCluster, "test-cluster")
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network.Subnet.ID).To(Equal("subnet-id"))
+		})
+	}
+}
+
+func Test_ReconcileFailureDomainSubnets(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+	m := mockClient.EXPECT()
+	m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.1.0/24"}).
+		Return([]subnets.Subnet{{ID: "subnet-1"}}, nil)
+	m.ListSubnet(subnets.ListOpts{NetworkID: "network-id", CIDR: "10.0.2.0/24"}).
+		Return([]subnets.Subnet{}, nil)
+	m.CreateSubnet(subnets.CreateOpts{
+		NetworkID:   "network-id",
+		Name:        "k8s-clusterapi-cluster-test-cluster-fd-2",
+		IPVersion:   4,
+		CIDR:        "10.0.2.0/24",
+		Description: "Created by cluster-api-provider-openstack cluster test-cluster",
+	}).Return(&subnets.Subnet{ID: "subnet-2"}, nil)
+
+	s := NewTestService("", mockClient, logr.Discard())
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			NodeCIDR: "10.0.0.0/24",
+			FailureDomains: &infrav1.FailureDomainsConfig{
+				Mappings: []infrav1.FailureDomainMapping{
+					{Name: "fd-1", NodeCIDR: "10.0.1.0/24"},
+					{Name: "fd-2", NodeCIDR: "10.0.2.0/24"},
+					{Name: "fd-3", Subnet: &infrav1.SubnetFilter{Name: "existing"}},
+				},
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{ID: "network-id"},
+		},
+	}
+	subnetIDs, err := s.ReconcileFailureDomainSubnets(openStackCluster, "test-cluster")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(subnetIDs).To(Equal(map[string]string{"fd-1": "subnet-1", "fd-2": "subnet-2"}))
+}
+
+func Test_AdoptNetwork(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	notFound := gophercloud.ErrDefault404{}
+
+	tests := []struct {
+		name   string
+		expect func(m *mock_networking.MockNetworkClientMockRecorder)
+		want   *infrav1.Network
+	}{
+		{
+			name: "adopts network, subnet and router",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetNetwork("network-id").Return(&networks.Network{ID: "network-id", Name: "k8s-clusterapi-cluster-test-cluster"}, nil)
+				m.GetSubnet("subnet-id").Return(&subnets.Subnet{ID: "subnet-id", CIDR: "10.0.0.0/24"}, nil)
+				m.GetRouter("router-id").Return(&routers.Router{ID: "router-id", GatewayInfo: routers.GatewayInfo{
+					ExternalFixedIPs: []routers.ExternalFixedIP{{IPAddress: "203.0.113.5"}},
+				}}, nil)
+			},
+			want: &infrav1.Network{
+				ID:     "network-id",
+				Name:   "k8s-clusterapi-cluster-test-cluster",
+				Subnet: &infrav1.Subnet{ID: "subnet-id", CIDR: "10.0.0.0/24"},
+				Router: &infrav1.Router{ID: "router-id", IPs: []string{"203.0.113.5"}},
+			},
+		},
+		{
+			name: "skips deleted subnet and router",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetNetwork("network-id").Return(&networks.Network{ID: "network-id"}, nil)
+				m.GetSubnet("subnet-id").Return(nil, notFound)
+				m.GetRouter("router-id").Return(nil, notFound)
+			},
+			want: &infrav1.Network{ID: "network-id"},
+		},
+		{
+			name: "skips deleted network",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetNetwork("network-id").Return(nil, notFound)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{}
+			err := s.AdoptNetwork(openStackCluster, "network-id", "subnet-id", "router-id")
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(openStackCluster.Status.Network).To(Equal(tt.want))
+		})
+	}
+}
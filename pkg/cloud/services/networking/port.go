@@ -21,39 +21,42 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/extradhcpopts"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsbinding"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsecurity"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/cluster-api/util"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+	capostrings "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/strings"
 )
 
-const (
-	timeoutPortDelete       = 3 * time.Minute
-	retryIntervalPortDelete = 5 * time.Second
-)
+const retryIntervalPortDelete = 5 * time.Second
 
-// GetPortFromInstanceIP returns at most one port attached to the instance with given ID
-// and with the IP address provided.
-func (s *Service) GetPortFromInstanceIP(instanceID string, ip string) ([]ports.Port, error) {
-	portOpts := ports.ListOpts{
-		DeviceID: instanceID,
-		FixedIPs: []ports.FixedIPOpts{
-			{
-				IPAddress: ip,
-			},
-		},
-		Limit: 1,
-	}
-	return s.client.ListPort(portOpts)
+// ListPortsForInstance returns the ports attached to the instance with the given ID.
+func (s *Service) ListPortsForInstance(instanceID string) ([]ports.Port, error) {
+	portList, err := s.client.ListPort(ports.ListOpts{DeviceID: instanceID})
+	if err != nil {
+		return nil, fmt.Errorf("list ports of server %s: %v", instanceID, err)
+	}
+	return portList, nil
 }
 
-func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string, portName string, net infrav1.Network, instanceSecurityGroups *[]string, instanceTags []string) (*ports.Port, error) {
+// GetOrCreatePort returns the port with the given name on the network,
+// creating it for the server with the given name if it does not exist. If
+// the port options give the ID of an existing port, that port is returned.
+func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName, serverName, portName string, net infrav1.Network, instanceSecurityGroups *[]string, instanceTags []string) (*ports.Port, error) {
+	if net.PortOpts != nil && net.PortOpts.ID != "" {
+		return s.getExistingPort(net.PortOpts.ID)
+	}
+
 	existingPorts, err := s.client.ListPort(ports.ListOpts{
 		Name:      portName,
 		NetworkID: net.ID,
@@ -63,7 +66,19 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 	}
 
 	if len(existingPorts) == 1 {
-		return &existingPorts[0], nil
+		port := &existingPorts[0]
+		// The trunk of an existing port may be missing if the creation of
+		// the instance was interrupted, or the port may have been created
+		// outside of CAPO with a trunk we have to adopt.
+		if net.PortOpts != nil && net.PortOpts.Trunk != nil && *net.PortOpts.Trunk {
+			var tags []string
+			tags = append(tags, instanceTags...)
+			tags = append(tags, net.PortOpts.Tags...)
+			if err := s.ensureTrunk(eventObject, clusterName, port, tags); err != nil {
+				return nil, err
+			}
+		}
+		return port, nil
 	}
 
 	if len(existingPorts) > 1 {
@@ -78,7 +93,7 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 
 	description := portOpts.Description
 	if description == "" {
-		description = names.GetDescription(clusterName)
+		description = names.GetPortDescription(clusterName, serverName)
 	}
 
 	var securityGroups *[]string
@@ -90,7 +105,7 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 				MACAddress: ap.MACAddress,
 			})
 		}
-		securityGroups, err = s.CollectPortSecurityGroups(eventObject, portOpts.SecurityGroups, portOpts.SecurityGroupFilters)
+		securityGroups, err = s.CollectPortSecurityGroups(eventObject, portOpts.SecurityGroupFilters)
 		if err != nil {
 			return nil, err
 		}
@@ -100,9 +115,9 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 		}
 	}
 
-	var fixedIPs interface{}
+	var fips []ports.IP
 	if len(portOpts.FixedIPs) > 0 {
-		fips := make([]ports.IP, 0, len(portOpts.FixedIPs)+1)
+		fips = make([]ports.IP, 0, len(portOpts.FixedIPs)+1)
 		for _, fixedIP := range portOpts.FixedIPs {
 			subnetID, err := s.getSubnetIDForFixedIP(fixedIP.Subnet, net.ID)
 			if err != nil {
@@ -116,6 +131,16 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 		if net.Subnet.ID != "" {
 			fips = append(fips, ports.IP{SubnetID: net.Subnet.ID})
 		}
+	}
+	if portOpts.FixedIPPerSubnet {
+		fips, err = s.addFixedIPPerSubnet(net.ID, fips)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var fixedIPs interface{}
+	if len(fips) > 0 {
 		fixedIPs = fips
 	}
 
@@ -141,6 +166,21 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 		}
 	}
 
+	if len(portOpts.ExtraDHCPOpts) > 0 {
+		extraDHCPOpts := make([]extradhcpopts.CreateExtraDHCPOpt, 0, len(portOpts.ExtraDHCPOpts))
+		for _, opt := range portOpts.ExtraDHCPOpts {
+			extraDHCPOpts = append(extraDHCPOpts, extradhcpopts.CreateExtraDHCPOpt{
+				OptName:   opt.Name,
+				OptValue:  opt.Value,
+				IPVersion: gophercloud.IPVersion(opt.IPVersion),
+			})
+		}
+		createOpts = extradhcpopts.CreateOptsExt{
+			CreateOptsBuilder: createOpts,
+			ExtraDHCPOpts:     extraDHCPOpts,
+		}
+	}
+
 	createOpts = portsbinding.CreateOptsExt{
 		CreateOptsBuilder: createOpts,
 		HostID:            portOpts.HostID,
@@ -150,9 +190,10 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 
 	port, err := s.client.CreatePort(createOpts)
 	if err != nil {
-		record.Warnf(eventObject, "FailedCreatePort", "Failed to create port %s: %v", portName, err)
-		return nil, err
+		record.Failed(eventObject, record.Create, record.Resource{Kind: record.Port, Name: portName, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
+	portEvent := record.Resource{Kind: record.Port, Name: port.Name, ID: port.ID, RequestID: s.scope.LastRequestID()}
 
 	var tags []string
 	tags = append(tags, instanceTags...)
@@ -163,15 +204,9 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 			return nil, err
 		}
 	}
-	record.Eventf(eventObject, "SuccessfulCreatePort", "Created port %s with id %s", port.Name, port.ID)
+	record.Succeeded(eventObject, record.Create, portEvent)
 	if portOpts.Trunk != nil && *portOpts.Trunk {
-		trunk, err := s.getOrCreateTrunk(eventObject, clusterName, port.Name, port.ID)
-		if err != nil {
-			record.Warnf(eventObject, "FailedCreateTrunk", "Failed to create trunk for port %s: %v", portName, err)
-			return nil, err
-		}
-		if err = s.replaceAllAttributesTags(eventObject, trunkResource, trunk.ID, tags); err != nil {
-			record.Warnf(eventObject, "FailedReplaceTags", "Failed to replace trunk tags %s: %v", portName, err)
+		if err := s.ensureTrunk(eventObject, clusterName, port, tags); err != nil {
 			return nil, err
 		}
 	}
@@ -179,6 +214,92 @@ func (s *Service) GetOrCreatePort(eventObject runtime.Object, clusterName string
 	return port, nil
 }
 
+// getExistingPort returns the existing port with the given ID, which must
+// not be attached to a server.
+func (s *Service) getExistingPort(portID string) (*ports.Port, error) {
+	port, err := s.client.GetPort(portID)
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			return nil, fmt.Errorf("port %s does not exist", portID)
+		}
+		return nil, fmt.Errorf("get port %s: %v", portID, err)
+	}
+	if port.DeviceID != "" {
+		return nil, fmt.Errorf("port %s is already attached to device %s", portID, port.DeviceID)
+	}
+	return port, nil
+}
+
+// ensureTrunk makes the port the parent port of a trunk with the given tags.
+func (s *Service) ensureTrunk(eventObject runtime.Object, clusterName string, port *ports.Port, tags []string) error {
+	trunk, err := s.getOrCreateTrunk(eventObject, clusterName, port.Name, port.ID)
+	if err != nil {
+		record.Warnf(eventObject, "FailedCreateTrunk", "Failed to create trunk for port %s: %v", port.Name, err)
+		return err
+	}
+	// Existing trunks are only updated when their tags differ, so that
+	// reconciles without changes do not write to Neutron
+	if sets.NewString(trunk.Tags...).Equal(sets.NewString(tags...)) {
+		return nil
+	}
+	if err = s.replaceAllAttributesTags(eventObject, trunkResource, trunk.ID, tags); err != nil {
+		record.Warnf(eventObject, "FailedReplaceTags", "Failed to replace trunk tags %s: %v", port.Name, err)
+		return err
+	}
+	return nil
+}
+
+// EnsurePortSecurityGroups adds the given security groups to the port if it
+// is not already a member of them.
+func (s *Service) EnsurePortSecurityGroups(eventObject runtime.Object, portID string, securityGroups []string) error {
+	port, err := s.client.GetPort(portID)
+	if err != nil {
+		return fmt.Errorf("get port %s: %v", portID, err)
+	}
+
+	portSecurityGroups := capostrings.Unique(append(append([]string{}, port.SecurityGroups...), securityGroups...))
+	if len(portSecurityGroups) == len(capostrings.Unique(port.SecurityGroups)) {
+		return nil
+	}
+
+	_, err = s.client.UpdatePort(portID, ports.UpdateOpts{
+		SecurityGroups: &portSecurityGroups,
+	})
+	portEvent := record.Resource{Kind: record.Port, Name: port.Name, ID: portID, RequestID: s.scope.LastRequestID()}
+	if err != nil {
+		portEvent.Detail = "security groups"
+		record.Failed(eventObject, record.Update, portEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+
+	portEvent.Detail = fmt.Sprintf("security groups %v", portSecurityGroups)
+	record.Succeeded(eventObject, record.Update, portEvent)
+	return nil
+}
+
+// UpdatePortSecurityGroups replaces the security groups of the port with the
+// given security groups if they differ.
+func (s *Service) UpdatePortSecurityGroups(eventObject runtime.Object, port *ports.Port, securityGroups []string) error {
+	if sets.NewString(port.SecurityGroups...).Equal(sets.NewString(securityGroups...)) {
+		return nil
+	}
+
+	portSecurityGroups := capostrings.Unique(securityGroups)
+	_, err := s.client.UpdatePort(port.ID, ports.UpdateOpts{
+		SecurityGroups: &portSecurityGroups,
+	})
+	portEvent := record.Resource{Kind: record.Port, Name: port.Name, ID: port.ID, RequestID: s.scope.LastRequestID()}
+	if err != nil {
+		portEvent.Detail = "security groups"
+		record.Failed(eventObject, record.Update, portEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+
+	portEvent.Detail = fmt.Sprintf("security groups %v", portSecurityGroups)
+	record.Succeeded(eventObject, record.Update, portEvent)
+	return nil
+}
+
 func (s *Service) getSubnetIDForFixedIP(subnet *infrav1.SubnetFilter, networkID string) (string, error) {
 	if subnet == nil {
 		return "", nil
@@ -205,6 +326,29 @@ func (s *Service) getSubnetIDForFixedIP(subnet *infrav1.SubnetFilter, networkID
 	}
 }
 
+// addFixedIPPerSubnet adds a fixed IP to the given fixed IPs for every subnet
+// of the network which none of them is in yet.
+func (s *Service) addFixedIPPerSubnet(networkID string, fips []ports.IP) ([]ports.IP, error) {
+	subnetList, err := s.client.ListSubnet(subnets.ListOpts{NetworkID: networkID})
+	if err != nil {
+		return nil, fmt.Errorf("list subnets of network %s: %v", networkID, err)
+	}
+	if len(subnetList) == 0 {
+		return nil, fmt.Errorf("network %s has no subnets", networkID)
+	}
+
+	selected := make(map[string]bool, len(fips))
+	for _, fip := range fips {
+		selected[fip.SubnetID] = true
+	}
+	for _, subnet := range subnetList {
+		if !selected[subnet.ID] {
+			fips = append(fips, ports.IP{SubnetID: subnet.ID})
+		}
+	}
+	return fips, nil
+}
+
 func getPortProfile(p map[string]string) map[string]interface{} {
 	portProfile := make(map[string]interface{})
 	for k, v := range p {
@@ -222,11 +366,15 @@ func getPortProfile(p map[string]string) map[string]interface{} {
 
 func (s *Service) DeletePort(eventObject runtime.Object, portID string) error {
 	var err error
-	err = util.PollImmediate(retryIntervalPortDelete, timeoutPortDelete, func() (bool, error) {
+	portEvent := record.Resource{Kind: record.Port, ID: portID}
+	err = util.PollImmediate(retryIntervalPortDelete, s.deleteTimeout(), func() (bool, error) {
 		err = s.client.DeletePort(portID)
+		portEvent.RequestID = s.scope.LastRequestID()
 		if err != nil {
 			if capoerrors.IsNotFound(err) {
-				record.Eventf(eventObject, "SuccessfulDeletePort", "Port with id %d did not exist", portID)
+				portEvent.Detail = "port did not exist"
+				record.Succeeded(eventObject, record.Delete, portEvent)
+				portEvent.Detail = ""
 			}
 			if capoerrors.IsRetryable(err) {
 				return false, nil
@@ -236,19 +384,49 @@ func (s *Service) DeletePort(eventObject runtime.Object, portID string) error {
 		return true, nil
 	})
 	if err != nil {
-		record.Warnf(eventObject, "FailedDeletePort", "Failed to delete port with id %s: %v", portID, err)
-		return err
+		record.Failed(eventObject, record.Delete, portEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
 	}
 
-	record.Eventf(eventObject, "SuccessfulDeletePort", "Deleted port with id %s", portID)
+	record.Succeeded(eventObject, record.Delete, portEvent)
 	return nil
 }
 
+// WaitForPortRelease waits until the port is no longer attached to a device,
+// e.g. because the server it was attached to has been deleted, or is gone.
+func (s *Service) WaitForPortRelease(portID string) error {
+	return util.PollImmediate(retryIntervalPortDelete, s.deleteTimeout(), func() (bool, error) {
+		port, err := s.client.GetPort(portID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return true, nil
+			}
+			if capoerrors.IsRetryable(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return port.DeviceID == "", nil
+	})
+}
+
 func (s *Service) DeletePorts(openStackCluster *infrav1.OpenStackCluster) error {
 	networkID := openStackCluster.Spec.Network.ID
-	portList, err := s.client.ListPort(ports.ListOpts{
+
+	// The network may be shared with many other ports, so only the ports of
+	// the cluster are kept while reading them page by page
+	var portList []ports.Port
+	err := s.client.EachPortPage(ports.ListOpts{
 		NetworkID:   networkID,
 		DeviceOwner: "",
+		Limit:       portPageSize,
+	}, func(page []ports.Port) (bool, error) {
+		for _, port := range page {
+			if strings.HasPrefix(port.Name, openStackCluster.Name) {
+				portList = append(portList, port)
+			}
+		}
+		return true, nil
 	})
 	if err != nil {
 		if capoerrors.IsNotFound(err) {
@@ -258,11 +436,7 @@ func (s *Service) DeletePorts(openStackCluster *infrav1.OpenStackCluster) error
 	}
 
 	for _, port := range portList {
-		if strings.HasPrefix(port.Name, openStackCluster.Name) {
-			err := s.DeletePort(openStackCluster, port.ID)
-			if capoerrors.IsNotFound(err) {
-				continue
-			}
+		if err := s.DeletePort(openStackCluster, port.ID); err != nil && !capoerrors.IsNotFound(err) {
 			return fmt.Errorf("delete port %s of network %q failed : %v", port.ID, networkID, err)
 		}
 	}
@@ -270,6 +444,58 @@ func (s *Service) DeletePorts(openStackCluster *infrav1.OpenStackCluster) error
 	return nil
 }
 
+// DeleteOrphanedPorts deletes the ports created for the cluster which are not
+// attached to any device, together with their trunks. These are left behind
+// when the creation or deletion of an instance was interrupted.
+func (s *Service) DeleteOrphanedPorts(eventObject runtime.Object, clusterName string) error {
+	var portList []ports.Port
+	// The ports of servers carry the name of their server in their
+	// description, so they cannot be filtered by description in Neutron.
+	err := s.client.EachPortPage(ports.ListOpts{
+		Limit: portPageSize,
+	}, func(page []ports.Port) (bool, error) {
+		for _, port := range page {
+			if port.DeviceID == "" && names.HasClusterDescription(port.Description, clusterName) {
+				portList = append(portList, port)
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("list ports of cluster %s: %v", clusterName, err)
+	}
+
+	for _, port := range portList {
+		if err := s.DeleteTrunk(eventObject, port.ID); err != nil {
+			return fmt.Errorf("delete trunk of port %s failed: %v", port.ID, err)
+		}
+		if err := s.DeletePort(eventObject, port.ID); err != nil && !capoerrors.IsNotFound(err) {
+			return fmt.Errorf("delete port %s failed: %v", port.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// ListPortsForCluster returns the ports created for the cluster.
+func (s *Service) ListPortsForCluster(clusterName string) ([]ports.Port, error) {
+	var portList []ports.Port
+	err := s.client.EachPortPage(ports.ListOpts{
+		Limit: portPageSize,
+	}, func(page []ports.Port) (bool, error) {
+		for _, port := range page {
+			if names.HasClusterDescription(port.Description, clusterName) {
+				portList = append(portList, port)
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list ports of cluster %s: %v", clusterName, err)
+	}
+	return portList, nil
+}
+
 func (s *Service) GarbageCollectErrorInstancesPort(eventObject runtime.Object, instanceName string) error {
 	portList, err := s.client.ListPort(ports.ListOpts{
 		Name: instanceName,
@@ -286,20 +512,16 @@ func (s *Service) GarbageCollectErrorInstancesPort(eventObject runtime.Object, i
 	return nil
 }
 
-// CollectPortSecurityGroups collects distinct securityGroups from port.SecurityGroups and port.SecurityGroupFilter fields.
-func (s *Service) CollectPortSecurityGroups(eventObject runtime.Object, portSecurityGroups *[]string, portSecurityGroupFilters []infrav1.SecurityGroupParam) (*[]string, error) {
+// CollectPortSecurityGroups collects distinct securityGroups from port.SecurityGroupFilters field.
+func (s *Service) CollectPortSecurityGroups(eventObject runtime.Object, portSecurityGroupFilters []infrav1.SecurityGroupFilter) (*[]string, error) {
 	var allSecurityGroupIDs []string
 	// security groups provided with the portSecurityGroupFilters fields
 	securityGroupFiltersByID, err := s.GetSecurityGroups(portSecurityGroupFilters)
 	if err != nil {
-		return portSecurityGroups, fmt.Errorf("error getting security groups: %v", err)
+		return nil, fmt.Errorf("error getting security groups: %v", err)
 	}
 	allSecurityGroupIDs = append(allSecurityGroupIDs, securityGroupFiltersByID...)
 	securityGroupCount := 0
-	// security groups provided with the portSecurityGroups fields
-	if portSecurityGroups != nil {
-		allSecurityGroupIDs = append(allSecurityGroupIDs, *portSecurityGroups...)
-	}
 	// generate unique values
 	uids := make(map[string]int)
 	for _, sg := range allSecurityGroupIDs {
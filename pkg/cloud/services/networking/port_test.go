@@ -19,16 +19,20 @@ package networking
 import (
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/extradhcpopts"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsbinding"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/portsecurity"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
 )
 
@@ -139,7 +143,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 					CreatePort(portsbinding.CreateOptsExt{
 						CreateOptsBuilder: ports.CreateOpts{
 							Name:                "foo-port-1",
-							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+							Description:         "Created by cluster-api-provider-openstack cluster test-cluster for server test-server",
 							SecurityGroups:      &instanceSecurityGroups,
 							NetworkID:           netID,
 							AllowedAddressPairs: []ports.AddressPair{},
@@ -166,9 +170,9 @@ func Test_GetOrCreatePort(t *testing.T) {
 						},
 						IPAddress: "192.168.0.50",
 					}, {IPAddress: "192.168.1.50"}},
-					TenantID:       tenantID,
-					ProjectID:      projectID,
-					SecurityGroups: &portSecurityGroups,
+					TenantID:             tenantID,
+					ProjectID:            projectID,
+					SecurityGroupFilters: []infrav1.SecurityGroupFilter{{ID: "port-secgroup"}},
 					AllowedAddressPairs: []infrav1.AddressPair{{
 						IPAddress:  "10.10.10.10",
 						MACAddress: "f1:f1:f1:f1:f1:f1",
@@ -256,7 +260,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 					Description: "this is a test port",
 					FixedIPs: []infrav1.FixedIP{{
 						Subnet: &infrav1.SubnetFilter{
-							Tags: "Foo",
+							FilterByNeutronTags: infrav1.FilterByNeutronTags{Tags: []infrav1.NeutronTag{"Foo"}},
 						},
 						IPAddress: "192.168.0.50",
 					}},
@@ -290,13 +294,123 @@ func Test_GetOrCreatePort(t *testing.T) {
 			nil,
 			true,
 		},
+		{
+			"creates port with extra DHCP options",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					ExtraDHCPOpts: []infrav1.ExtraDHCPOpt{
+						{Name: "mtu", Value: "1450"},
+						{Name: "ntp-server", Value: "192.168.0.1", IPVersion: 4},
+					},
+				},
+			},
+			nil,
+			nil,
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.
+					ListPort(ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.
+					CreatePort(portsbinding.CreateOptsExt{
+						CreateOptsBuilder: extradhcpopts.CreateOptsExt{
+							CreateOptsBuilder: ports.CreateOpts{
+								Name:                "foo-port-1",
+								Description:         "Created by cluster-api-provider-openstack cluster test-cluster for server test-server",
+								NetworkID:           netID,
+								AllowedAddressPairs: []ports.AddressPair{},
+							},
+							ExtraDHCPOpts: []extradhcpopts.CreateExtraDHCPOpt{
+								{OptName: "mtu", OptValue: "1450"},
+								{OptName: "ntp-server", OptValue: "192.168.0.1", IPVersion: gophercloud.IPv4},
+							},
+						},
+					}).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
+		{
+			"creates port with a fixed IP from every subnet of the network",
+			"foo-port-1",
+			infrav1.Network{
+				ID:     netID,
+				Subnet: &infrav1.Subnet{},
+				PortOpts: &infrav1.PortOpts{
+					FixedIPs: []infrav1.FixedIP{{
+						Subnet:    &infrav1.SubnetFilter{ID: subnetID1},
+						IPAddress: "192.168.0.50",
+					}},
+					FixedIPPerSubnet: true,
+				},
+			},
+			nil,
+			nil,
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.
+					ListPort(ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.
+					ListSubnet(subnets.ListOpts{
+						NetworkID: netID,
+					}).Return([]subnets.Subnet{
+					{ID: subnetID1, NetworkID: netID},
+					{ID: subnetID2, NetworkID: netID},
+				}, nil)
+				m.
+					CreatePort(portsbinding.CreateOptsExt{
+						CreateOptsBuilder: ports.CreateOpts{
+							Name:        "foo-port-1",
+							Description: "Created by cluster-api-provider-openstack cluster test-cluster for server test-server",
+							NetworkID:   netID,
+							FixedIPs: []ports.IP{
+								{SubnetID: subnetID1, IPAddress: "192.168.0.50"},
+								{SubnetID: subnetID2},
+							},
+							AllowedAddressPairs: []ports.AddressPair{},
+						},
+					}).Return(&ports.Port{ID: portID1}, nil)
+			},
+			&ports.Port{ID: portID1},
+			false,
+		},
+		{
+			"fails to create port with a fixed IP from every subnet if the network has no subnets",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					FixedIPPerSubnet: true,
+				},
+			},
+			nil,
+			nil,
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.
+					ListPort(ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{}, nil)
+				m.
+					ListSubnet(subnets.ListOpts{
+						NetworkID: netID,
+					}).Return([]subnets.Subnet{}, nil)
+			},
+			nil,
+			true,
+		},
 		{
 			"overrides default (instance) security groups if port security groups are specified",
 			"foo-port-1",
 			infrav1.Network{
 				ID: netID,
 				PortOpts: &infrav1.PortOpts{
-					SecurityGroups: &portSecurityGroups,
+					SecurityGroupFilters: []infrav1.SecurityGroupFilter{{ID: "port-secgroup"}},
 				},
 			},
 			&instanceSecurityGroups,
@@ -312,7 +426,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 					CreatePort(portsbinding.CreateOptsExt{
 						CreateOptsBuilder: ports.CreateOpts{
 							Name:                "foo-port-1",
-							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+							Description:         "Created by cluster-api-provider-openstack cluster test-cluster for server test-server",
 							SecurityGroups:      &portSecurityGroups,
 							NetworkID:           netID,
 							AllowedAddressPairs: []ports.AddressPair{},
@@ -342,7 +456,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 				m.CreatePort(portsbinding.CreateOptsExt{
 					CreateOptsBuilder: ports.CreateOpts{
 						Name:                "foo-port-1",
-						Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+						Description:         "Created by cluster-api-provider-openstack cluster test-cluster for server test-server",
 						NetworkID:           netID,
 						AllowedAddressPairs: []ports.AddressPair{},
 					},
@@ -371,7 +485,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 				m.CreatePort(portsbinding.CreateOptsExt{
 					CreateOptsBuilder: ports.CreateOpts{
 						Name:                "foo-port-1",
-						Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+						Description:         "Created by cluster-api-provider-openstack cluster test-cluster for server test-server",
 						NetworkID:           netID,
 						AllowedAddressPairs: []ports.AddressPair{},
 					},
@@ -405,14 +519,13 @@ func Test_GetOrCreatePort(t *testing.T) {
 					CreatePort(portsbinding.CreateOptsExt{
 						CreateOptsBuilder: ports.CreateOpts{
 							Name:                "foo-port-1",
-							Description:         "Created by cluster-api-provider-openstack cluster test-cluster",
+							Description:         "Created by cluster-api-provider-openstack cluster test-cluster for server test-server",
 							NetworkID:           netID,
 							AllowedAddressPairs: []ports.AddressPair{},
 						},
 					}).Return(&ports.Port{Name: "foo-port-1", ID: portID1}, nil)
 				m.
 					ListTrunk(trunks.ListOpts{
-						Name:   "foo-port-1",
 						PortID: portID1,
 					}).Return([]trunks.Trunk{}, nil)
 				m.
@@ -428,6 +541,99 @@ func Test_GetOrCreatePort(t *testing.T) {
 			&ports.Port{Name: "foo-port-1", ID: portID1},
 			false,
 		},
+		{
+			"adopts the trunk of an existing port",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					Trunk: pointerToTrue,
+				},
+			},
+			nil,
+			[]string{"my-tag"},
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.
+					ListPort(ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{{Name: "foo-port-1", ID: portID1}}, nil)
+				m.
+					ListTrunk(trunks.ListOpts{
+						PortID: portID1,
+					}).Return([]trunks.Trunk{{Name: "byo-trunk", ID: trunkID}}, nil)
+				m.ReplaceAllAttributesTags("trunks", trunkID, attributestags.ReplaceAllOpts{Tags: []string{"my-tag"}}).Return([]string{"my-tag"}, nil)
+			},
+			&ports.Port{Name: "foo-port-1", ID: portID1},
+			false,
+		},
+		{
+			"leaves the tags of an up to date trunk alone",
+			"foo-port-1",
+			infrav1.Network{
+				ID: netID,
+				PortOpts: &infrav1.PortOpts{
+					Trunk: pointerToTrue,
+				},
+			},
+			nil,
+			[]string{"my-tag"},
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.
+					ListPort(ports.ListOpts{
+						Name:      "foo-port-1",
+						NetworkID: netID,
+					}).Return([]ports.Port{{Name: "foo-port-1", ID: portID1}}, nil)
+				m.
+					ListTrunk(trunks.ListOpts{
+						PortID: portID1,
+					}).Return([]trunks.Trunk{{Name: "foo-port-1", ID: trunkID, Tags: []string{"my-tag"}}}, nil)
+			},
+			&ports.Port{Name: "foo-port-1", ID: portID1},
+			false,
+		},
+		{
+			"returns existing port given by ID",
+			"foo-port-1",
+			infrav1.Network{
+				PortOpts: &infrav1.PortOpts{ID: portID2},
+			},
+			nil,
+			[]string{"my-tag"},
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetPort(portID2).Return(&ports.Port{Name: "byo-port", ID: portID2}, nil)
+			},
+			&ports.Port{Name: "byo-port", ID: portID2},
+			false,
+		},
+		{
+			"errors if existing port given by ID is attached to another server",
+			"foo-port-1",
+			infrav1.Network{
+				PortOpts: &infrav1.PortOpts{ID: portID2},
+			},
+			nil,
+			[]string{},
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetPort(portID2).Return(&ports.Port{Name: "byo-port", ID: portID2, DeviceID: hostID}, nil)
+			},
+			nil,
+			true,
+		},
+		{
+			"errors if existing port given by ID does not exist",
+			"foo-port-1",
+			infrav1.Network{
+				PortOpts: &infrav1.PortOpts{ID: portID2},
+			},
+			nil,
+			[]string{},
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.GetPort(portID2).Return(nil, gophercloud.ErrDefault404{})
+			},
+			nil,
+			true,
+		},
 	}
 
 	eventObject := &infrav1.OpenStackMachine{}
@@ -442,6 +648,7 @@ func Test_GetOrCreatePort(t *testing.T) {
 			got, err := s.GetOrCreatePort(
 				eventObject,
 				"test-cluster",
+				"test-server",
 				tt.portName,
 				tt.net,
 				tt.instanceSecurityGroups,
@@ -457,6 +664,145 @@ func Test_GetOrCreatePort(t *testing.T) {
 	}
 }
 
+// expectPortPages expects the ports matching opts to be read page by page,
+// returning the given pages.
+func expectPortPages(m *mock_networking.MockNetworkClientMockRecorder, opts ports.ListOpts, pages ...[]ports.Port) {
+	m.EachPortPage(opts, gomock.Any()).DoAndReturn(func(_ ports.ListOptsBuilder, handler func([]ports.Port) (bool, error)) error {
+		for _, page := range pages {
+			if ok, err := handler(page); err != nil || !ok {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func Test_DeleteOrphanedPorts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		attachedPortID = "0f2ae3d1-0c8c-4c5b-9b1a-5ad0c6a3c0c1"
+		orphanedPortID = "5f2b6a4e-8d36-4bb8-9c55-3a4d1f0b6d2a"
+		otherPortID    = "c4d3e2f1-6a5b-4c7d-8e9f-0a1b2c3d4e5f"
+		trunkID        = "7d1e6b0a-2c4f-4c3e-8f9a-1b2c3d4e5f60"
+	)
+	description := "Created by cluster-api-provider-openstack cluster test-cluster"
+
+	tests := []struct {
+		name    string
+		expect  func(m *mock_networking.MockNetworkClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "deletes unattached ports and their trunks",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				expectPortPages(m, ports.ListOpts{Limit: portPageSize},
+					[]ports.Port{
+						{ID: attachedPortID, Description: description, DeviceID: "a9b8c7d6-0000-4000-8000-000000000001"},
+						{ID: otherPortID, Description: "Created by cluster-api-provider-openstack cluster test-cluster-2"},
+					},
+					[]ports.Port{{ID: orphanedPortID, Description: description + " for server test-server"}},
+				)
+				m.ListTrunk(trunks.ListOpts{PortID: orphanedPortID}).Return([]trunks.Trunk{{ID: trunkID}}, nil)
+				m.DeleteTrunk(trunkID).Return(nil)
+				m.DeletePort(orphanedPortID).Return(nil)
+			},
+		},
+		{
+			name: "does nothing without ports",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				expectPortPages(m, ports.ListOpts{Limit: portPageSize})
+			},
+		},
+		{
+			name: "fails if ports cannot be listed",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.EachPortPage(ports.ListOpts{Limit: portPageSize}, gomock.Any()).Return(gophercloud.ErrDefault500{})
+			},
+			wantErr: true,
+		},
+	}
+
+	eventObject := &infrav1.OpenStackCluster{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("", mockClient, logr.Discard())
+			err := s.DeleteOrphanedPorts(eventObject, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_DeletePorts(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		networkID     = "b0d7c4f1-7a51-4e1c-9d0a-6e3f0a7c2b10"
+		clusterPortID = "3c1f6b2e-4d5a-4e8f-9a0b-1c2d3e4f5a60"
+		otherPortID   = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c60"
+	)
+
+	tests := []struct {
+		name    string
+		expect  func(m *mock_networking.MockNetworkClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "deletes the ports of the cluster from all pages",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				expectPortPages(m, ports.ListOpts{NetworkID: networkID, Limit: portPageSize},
+					[]ports.Port{{ID: otherPortID, Name: "other-port"}},
+					[]ports.Port{{ID: clusterPortID, Name: "test-cluster-control-plane-0"}},
+				)
+				m.DeletePort(clusterPortID).Return(nil)
+			},
+		},
+		{
+			name: "ignores a network which does not exist",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.EachPortPage(ports.ListOpts{NetworkID: networkID, Limit: portPageSize}, gomock.Any()).Return(gophercloud.ErrDefault404{})
+			},
+		},
+		{
+			name: "fails if ports cannot be listed",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.EachPortPage(ports.ListOpts{NetworkID: networkID, Limit: portPageSize}, gomock.Any()).Return(gophercloud.ErrDefault500{})
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("", mockClient, logr.Discard())
+			openStackCluster := &infrav1.OpenStackCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				Spec: infrav1.OpenStackClusterSpec{
+					Network: infrav1.NetworkFilter{ID: networkID},
+				},
+			}
+			err := s.DeletePorts(openStackCluster)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func pointerTo(b bool) *bool {
 	return &b
 }
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/quota"
+)
+
+// GetQuota returns the networking quota of the project.
+func (s *Service) GetQuota() (*quotas.Quota, error) {
+	quota, err := s.client.GetQuota(s.scope.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting network quota for project %s: %v", s.scope.ProjectID, err)
+	}
+
+	return quota, nil
+}
+
+// NetworkResources are the network resources required to create OpenStack
+// resources.
+type NetworkResources struct {
+	Networks       int
+	Subnets        int
+	Routers        int
+	Ports          int
+	FloatingIPs    int
+	SecurityGroups int
+}
+
+// Add adds the resources of other to r.
+func (r *NetworkResources) Add(other NetworkResources) {
+	r.Networks += other.Networks
+	r.Subnets += other.Subnets
+	r.Routers += other.Routers
+	r.Ports += other.Ports
+	r.FloatingIPs += other.FloatingIPs
+	r.SecurityGroups += other.SecurityGroups
+}
+
+// GetExhaustedQuotas returns the network quotas of the project which do not
+// leave enough for the required resources.
+func (s *Service) GetExhaustedQuotas(required NetworkResources) ([]quota.Usage, error) {
+	quotaDetailSet, err := s.client.GetQuotaDetail(s.scope.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting network quota usage for project %s: %v", s.scope.ProjectID, err)
+	}
+
+	return quota.Exhausted(
+		networkUsage("network.networks", quotaDetailSet.Network, required.Networks),
+		networkUsage("network.subnets", quotaDetailSet.Subnet, required.Subnets),
+		networkUsage("network.routers", quotaDetailSet.Router, required.Routers),
+		networkUsage("network.ports", quotaDetailSet.Port, required.Ports),
+		networkUsage("network.floatingips", quotaDetailSet.FloatingIP, required.FloatingIPs),
+		networkUsage("network.security_groups", quotaDetailSet.SecurityGroup, required.SecurityGroups),
+	), nil
+}
+
+func networkUsage(resource string, detail quotas.QuotaDetail, required int) quota.Usage {
+	return quota.Usage{
+		Resource: resource,
+		Limit:    detail.Limit,
+		InUse:    detail.Used + detail.Reserved,
+		Required: required,
+	}
+}
+
+// GetQuotaUsages returns the usage of the network quotas of the project.
+func (s *Service) GetQuotaUsages() ([]quota.Usage, error) {
+	quotaDetailSet, err := s.client.GetQuotaDetail(s.scope.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting network quota usage for project %s: %v", s.scope.ProjectID, err)
+	}
+
+	return []quota.Usage{
+		networkUsage("network.networks", quotaDetailSet.Network, 0),
+		networkUsage("network.subnets", quotaDetailSet.Subnet, 0),
+		networkUsage("network.routers", quotaDetailSet.Router, 0),
+		networkUsage("network.ports", quotaDetailSet.Port, 0),
+		networkUsage("network.trunks", quotaDetailSet.Trunk, 0),
+		networkUsage("network.floatingips", quotaDetailSet.FloatingIP, 0),
+		networkUsage("network.security_groups", quotaDetailSet.SecurityGroup, 0),
+		networkUsage("network.security_group_rules", quotaDetailSet.SecurityGroupRule, 0),
+	}, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/quotas"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/quota"
+)
+
+func Test_GetExhaustedQuotas(t *testing.T) {
+	quotaDetailSet := &quotas.QuotaDetailSet{
+		Network:       quotas.QuotaDetail{Used: 9, Limit: 10},
+		Subnet:        quotas.QuotaDetail{Used: 10, Limit: 10},
+		Router:        quotas.QuotaDetail{Used: 5, Limit: -1},
+		Port:          quotas.QuotaDetail{Used: 45, Reserved: 3, Limit: 50},
+		FloatingIP:    quotas.QuotaDetail{Used: 0, Limit: 0},
+		SecurityGroup: quotas.QuotaDetail{Used: 2, Limit: 10},
+	}
+
+	tests := []struct {
+		name     string
+		required NetworkResources
+		want     []quota.Usage
+	}{
+		{
+			name:     "Enough left",
+			required: NetworkResources{Networks: 1, Routers: 1, Ports: 2, SecurityGroups: 2},
+		},
+		{
+			name:     "Exhausted",
+			required: NetworkResources{Networks: 1, Subnets: 1, Ports: 3, FloatingIPs: 1},
+			want: []quota.Usage{
+				{Resource: "network.subnets", Limit: 10, InUse: 10, Required: 1},
+				{Resource: "network.ports", Limit: 50, InUse: 48, Required: 3},
+				{Resource: "network.floatingips", Limit: 0, InUse: 0, Required: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			mockClient.EXPECT().GetQuotaDetail("project-id").Return(quotaDetailSet, nil)
+			s := NewTestService("project-id", mockClient, logr.Discard())
+
+			exhausted, err := s.GetExhaustedQuotas(tt.required)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(exhausted).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_GetQuotaUsages(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().GetQuotaDetail("project-id").Return(&quotas.QuotaDetailSet{
+		Port:              quotas.QuotaDetail{Used: 45, Reserved: 3, Limit: 50},
+		Trunk:             quotas.QuotaDetail{Used: 2, Limit: -1},
+		SecurityGroupRule: quotas.QuotaDetail{Used: 80, Limit: 100},
+	}, nil)
+	s := NewTestService("project-id", mockClient, logr.Discard())
+
+	usages, err := s.GetQuotaUsages()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(usages).To(ContainElements(
+		quota.Usage{Resource: "network.ports", Limit: 50, InUse: 48},
+		quota.Usage{Resource: "network.trunks", Limit: -1, InUse: 2},
+		quota.Usage{Resource: "network.security_group_rules", Limit: 100, InUse: 80},
+	))
+}
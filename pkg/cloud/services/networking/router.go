@@ -18,29 +18,40 @@ package networking
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
-func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+// The device owners of the ports of a router in its internal subnets.
+var routerInterfaceDeviceOwners = map[string]bool{
+	"network:router_interface":             true,
+	"network:router_interface_distributed": true,
+}
+
+// ReconcileRouter creates the router of the cluster network, and connects it
+// to the subnet of the cluster, to the subnets of its failure domains and to
+// the subnets of spec.routerSubnets.
+func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, clusterName string, failureDomainSubnetIDs map[string]string) error {
 	if openStackCluster.Status.Network == nil || openStackCluster.Status.Network.ID == "" {
-		s.scope.Logger.V(3).Info("No need to reconcile router since no network exists.")
+		s.scope.Logger.V(logging.LevelDebug).Info("No need to reconcile router since no network exists")
 		return nil
 	}
 	if openStackCluster.Status.Network.Subnet == nil || openStackCluster.Status.Network.Subnet.ID == "" {
-		s.scope.Logger.V(4).Info("No need to reconcile router since no subnet exists.")
+		s.scope.Logger.V(logging.LevelDebug).Info("No need to reconcile router since no subnet exists")
 		return nil
 	}
 	if openStackCluster.Status.ExternalNetwork == nil || openStackCluster.Status.ExternalNetwork.ID == "" {
-		s.scope.Logger.V(3).Info("No need to create router, due to missing ExternalNetworkID.")
+		s.scope.Logger.V(logging.LevelDebug).Info("No need to create router, due to missing ExternalNetworkID")
 		return nil
 	}
 
@@ -60,6 +71,9 @@ func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, cl
 
 	var router *routers.Router
 	if len(routerList) == 0 {
+		if openStackCluster.Status.Network.Router != nil && openStackCluster.Status.Network.Router.ID != "" {
+			record.Warnf(openStackCluster, "MissingRouter", "Router %s with id %s no longer exists, recreating it", routerName, openStackCluster.Status.Network.Router.ID)
+		}
 		var err error
 		router, err = s.createRouter(openStackCluster, clusterName, routerName)
 		if err != nil {
@@ -67,7 +81,14 @@ func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, cl
 		}
 	} else {
 		router = &routerList[0]
-		s.scope.Logger.V(6).Info(fmt.Sprintf("Reuse existing Router %s with id %s", routerName, router.ID))
+		s.scope.Logger.V(logging.LevelDebug).Info("Reusing existing router", "name", routerName, "id", router.ID)
+
+		// The gateway is only set on create when no external IPs are configured, see createRouter
+		if len(openStackCluster.Spec.ExternalRouterIPs) == 0 && router.GatewayInfo.NetworkID != openStackCluster.Status.ExternalNetwork.ID {
+			if router, err = s.setRouterGateway(openStackCluster, router); err != nil {
+				return err
+			}
+		}
 	}
 
 	routerIPs := []string{}
@@ -93,29 +114,128 @@ func (s *Service) ReconcileRouter(openStackCluster *infrav1.OpenStackCluster, cl
 		return err
 	}
 
-	createInterface := true
+	subnetIDs := []string{openStackCluster.Status.Network.Subnet.ID}
+	for _, subnetID := range failureDomainSubnetIDs {
+		subnetIDs = append(subnetIDs, subnetID)
+	}
+	sort.Strings(subnetIDs[1:])
+
+	routerSubnetIDs, err := s.getRouterSubnetIDs(openStackCluster)
+	if err != nil {
+		return err
+	}
+	connectedSubnetIDs := make(map[string]bool, len(subnetIDs))
+	for _, subnetID := range subnetIDs {
+		connectedSubnetIDs[subnetID] = true
+	}
+	for _, subnetID := range routerSubnetIDs {
+		if !connectedSubnetIDs[subnetID] {
+			connectedSubnetIDs[subnetID] = true
+			subnetIDs = append(subnetIDs, subnetID)
+		}
+	}
+
+	for _, subnetID := range subnetIDs {
+		if err := s.reconcileRouterInterface(openStackCluster, router, routerInterfaces, subnetID, len(routerList) != 0); err != nil {
+			return err
+		}
+	}
+
+	// The next hops of the routes must be in the subnets of the interfaces
+	return s.reconcileRouterRoutes(openStackCluster, router)
+}
+
+// reconcileRouterRoutes adds the static routes of spec.routerRoutes to the
+// router, and removes the routes which CAPO added before and which are no
+// longer in the spec. Other routes of the router are left as they are.
+func (s *Service) reconcileRouterRoutes(openStackCluster *infrav1.OpenStackCluster, router *routers.Router) error {
+	specRoutes := make(map[routers.Route]bool, len(openStackCluster.Spec.RouterRoutes))
+	for _, route := range openStackCluster.Spec.RouterRoutes {
+		specRoutes[routers.Route{DestinationCIDR: route.Destination, NextHop: route.NextHop}] = true
+	}
+	removedRoutes := make(map[routers.Route]bool, len(openStackCluster.Status.RouterRoutes))
+	for _, route := range openStackCluster.Status.RouterRoutes {
+		if r := (routers.Route{DestinationCIDR: route.Destination, NextHop: route.NextHop}); !specRoutes[r] {
+			removedRoutes[r] = true
+		}
+	}
+
+	changed := false
+	routes := []routers.Route{}
+	existingRoutes := make(map[routers.Route]bool, len(router.Routes))
+	for _, route := range router.Routes {
+		if removedRoutes[route] {
+			changed = true
+			continue
+		}
+		existingRoutes[route] = true
+		routes = append(routes, route)
+	}
+	for _, route := range openStackCluster.Spec.RouterRoutes {
+		if r := (routers.Route{DestinationCIDR: route.Destination, NextHop: route.NextHop}); !existingRoutes[r] {
+			existingRoutes[r] = true
+			routes = append(routes, r)
+			changed = true
+		}
+	}
+
+	if changed {
+		s.scope.Logger.Info("Updating routes of router", "name", router.Name, "id", router.ID)
+		if _, err := s.client.UpdateRouter(router.ID, routers.UpdateOpts{Routes: &routes}); err != nil {
+			record.Warnf(openStackCluster, "FailedUpdateRouter", "Failed to update routes of router %s with id %s: %v", router.Name, router.ID, err)
+			return err
+		}
+		record.Eventf(openStackCluster, "SuccessfulUpdateRouter", "Updated routes of router %s with id %s", router.Name, router.ID)
+	}
+
+	openStackCluster.Status.RouterRoutes = nil
+	if len(openStackCluster.Spec.RouterRoutes) > 0 {
+		openStackCluster.Status.RouterRoutes = append([]infrav1.RouterRoute{}, openStackCluster.Spec.RouterRoutes...)
+	}
+	return nil
+}
+
+// getRouterSubnetIDs returns the IDs of the subnets of spec.routerSubnets.
+func (s *Service) getRouterSubnetIDs(openStackCluster *infrav1.OpenStackCluster) ([]string, error) {
+	var subnetIDs []string
+	for i := range openStackCluster.Spec.RouterSubnets {
+		listOpts := openStackCluster.Spec.RouterSubnets[i].ToListOpt()
+		subnetsByFilter, err := s.GetSubnetsByFilter(&listOpts)
+		if err != nil {
+			return nil, err
+		}
+		if len(subnetsByFilter) != 1 {
+			return nil, fmt.Errorf("routerSubnets[%d] matched %d subnets instead of exactly one", i, len(subnetsByFilter))
+		}
+		subnetIDs = append(subnetIDs, subnetsByFilter[0].ID)
+	}
+	return subnetIDs, nil
+}
+
+// reconcileRouterInterface creates an interface of the router in the subnet
+// with the given ID, unless one of its interfaces is already in the subnet.
+func (s *Service) reconcileRouterInterface(openStackCluster *infrav1.OpenStackCluster, router *routers.Router, routerInterfaces []ports.Port, subnetID string, existingRouter bool) error {
 	// check all router interfaces for an existing port in our subnet.
-INTERFACE_LOOP:
 	for _, iface := range routerInterfaces {
 		for _, ip := range iface.FixedIPs {
-			if ip.SubnetID == openStackCluster.Status.Network.Subnet.ID {
-				createInterface = false
-				break INTERFACE_LOOP
+			if ip.SubnetID == subnetID {
+				return nil
 			}
 		}
 	}
 
 	// ... and create a router interface for our subnet.
-	if createInterface {
-		s.scope.Logger.V(4).Info("Creating RouterInterface", "routerID", router.ID, "subnetID", openStackCluster.Status.Network.Subnet.ID)
-		routerInterface, err := s.client.AddRouterInterface(router.ID, routers.AddInterfaceOpts{
-			SubnetID: openStackCluster.Status.Network.Subnet.ID,
-		})
-		if err != nil {
-			return fmt.Errorf("unable to create router interface: %v", err)
-		}
-		s.scope.Logger.V(4).Info("Created RouterInterface", "id", routerInterface.ID)
+	if existingRouter {
+		record.Warnf(openStackCluster, "MissingRouterInterface", "Router %s with id %s has no interface in subnet %s, recreating it", router.Name, router.ID, subnetID)
 	}
+	s.scope.Logger.Info("Creating router interface", "routerID", router.ID, "subnetID", subnetID)
+	routerInterface, err := s.client.AddRouterInterface(router.ID, routers.AddInterfaceOpts{
+		SubnetID: subnetID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create router interface: %v", err)
+	}
+	s.scope.Logger.Info("Created router interface", "routerID", router.ID, "portID", routerInterface.PortID)
 	return nil
 }
 
@@ -153,6 +273,21 @@ func (s *Service) createRouter(openStackCluster *infrav1.OpenStackCluster, clust
 	return router, nil
 }
 
+func (s *Service) setRouterGateway(openStackCluster *infrav1.OpenStackCluster, router *routers.Router) (*routers.Router, error) {
+	updated, err := s.client.UpdateRouter(router.ID, routers.UpdateOpts{
+		GatewayInfo: &routers.GatewayInfo{
+			NetworkID: openStackCluster.Status.ExternalNetwork.ID,
+		},
+	})
+	if err != nil {
+		record.Warnf(openStackCluster, "FailedUpdateRouter", "Failed to set gateway of router %s with id %s: %v", router.Name, router.ID, err)
+		return nil, err
+	}
+
+	record.Eventf(openStackCluster, "SuccessfulUpdateRouter", "Set gateway of router %s with id %s", router.Name, router.ID)
+	return updated, nil
+}
+
 func (s *Service) setRouterExternalIPs(openStackCluster *infrav1.OpenStackCluster, router *routers.Router) error {
 	updateOpts := routers.UpdateOpts{
 		GatewayInfo: &routers.GatewayInfo{
@@ -179,6 +314,10 @@ func (s *Service) setRouterExternalIPs(openStackCluster *infrav1.OpenStackCluste
 		})
 	}
 
+	if gatewayUpToDate(&router.GatewayInfo, updateOpts.GatewayInfo) {
+		return nil
+	}
+
 	_, err := s.client.UpdateRouter(router.ID, updateOpts)
 	if err != nil {
 		record.Warnf(openStackCluster, "FailedUpdateRouter", "Failed to update router %s with id %s: %v", router.Name, router.ID, err)
@@ -189,6 +328,34 @@ func (s *Service) setRouterExternalIPs(openStackCluster *infrav1.OpenStackCluste
 	return nil
 }
 
+// gatewayUpToDate returns whether the router gateway is in the network of
+// the desired gateway and has its external IPs. External IPs without address
+// match any address which Neutron allocated in their subnet.
+func gatewayUpToDate(current, desired *routers.GatewayInfo) bool {
+	if current.NetworkID != desired.NetworkID || len(current.ExternalFixedIPs) != len(desired.ExternalFixedIPs) {
+		return false
+	}
+	matched := make([]bool, len(current.ExternalFixedIPs))
+	for _, desiredIP := range desired.ExternalFixedIPs {
+		found := false
+		for i, currentIP := range current.ExternalFixedIPs {
+			if matched[i] || currentIP.SubnetID != desiredIP.SubnetID {
+				continue
+			}
+			if desiredIP.IPAddress != "" && currentIP.IPAddress != desiredIP.IPAddress {
+				continue
+			}
+			matched[i] = true
+			found = true
+			break
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
 	router, subnet, err := s.getRouter(clusterName)
 	if err != nil {
@@ -199,17 +366,48 @@ func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, clust
 		return nil
 	}
 
-	if subnet.ID != "" {
-		_, err = s.client.RemoveRouterInterface(router.ID, routers.RemoveInterfaceOpts{
-			SubnetID: subnet.ID,
-		})
-		if err != nil {
-			if !capoerrors.IsNotFound(err) {
-				return fmt.Errorf("unable to remove router interface: %v", err)
+	// Neutron does not remove the interfaces of a router while routes use
+	// them as next hop
+	if len(router.Routes) > 0 {
+		if _, err := s.client.UpdateRouter(router.ID, routers.UpdateOpts{Routes: &[]routers.Route{}}); err != nil {
+			record.Warnf(openStackCluster, "FailedUpdateRouter", "Failed to remove routes of router %s with id %s: %v", router.Name, router.ID, err)
+			return err
+		}
+	}
+
+	if err := s.removeRouterInterface(router.ID, subnet.ID); err != nil {
+		return err
+	}
+
+	if openStackCluster.Spec.FailureDomains != nil {
+		for _, mapping := range openStackCluster.Spec.FailureDomains.Mappings {
+			if mapping.NodeCIDR == "" {
+				continue
+			}
+			subnet, err := s.getSubnetByName(getFailureDomainSubnetName(clusterName, mapping.Name))
+			if err != nil {
+				return err
+			}
+			if err := s.removeRouterInterface(router.ID, subnet.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Remove the interfaces in other subnets, e.g. those of spec.routerSubnets,
+	// including subnets which were removed from the spec since
+	routerInterfaces, err := s.getRouterInterfaces(router.ID)
+	if err != nil {
+		return err
+	}
+	for _, iface := range routerInterfaces {
+		if !routerInterfaceDeviceOwners[iface.DeviceOwner] {
+			continue
+		}
+		for _, ip := range iface.FixedIPs {
+			if err := s.removeRouterInterface(router.ID, ip.SubnetID); err != nil {
+				return err
 			}
-			s.scope.Logger.V(4).Info("Router Interface already removed, nothing to do", "id", router.ID)
-		} else {
-			s.scope.Logger.V(4).Info("Removed RouterInterface of Router", "id", router.ID)
 		}
 	}
 
@@ -223,6 +421,26 @@ func (s *Service) DeleteRouter(openStackCluster *infrav1.OpenStackCluster, clust
 	return nil
 }
 
+// removeRouterInterface removes the interface of the router in the subnet with
+// the given ID, if there is one.
+func (s *Service) removeRouterInterface(routerID, subnetID string) error {
+	if subnetID == "" {
+		return nil
+	}
+	_, err := s.client.RemoveRouterInterface(routerID, routers.RemoveInterfaceOpts{
+		SubnetID: subnetID,
+	})
+	if err != nil {
+		if !capoerrors.IsNotFound(err) {
+			return fmt.Errorf("unable to remove router interface: %v", err)
+		}
+		s.scope.Logger.V(logging.LevelDebug).Info("Router interface already removed, nothing to do", "routerID", routerID, "subnetID", subnetID)
+		return nil
+	}
+	s.scope.Logger.Info("Removed router interface", "routerID", routerID, "subnetID", subnetID)
+	return nil
+}
+
 func (s *Service) getRouterInterfaces(routerID string) ([]ports.Port, error) {
 	return s.client.ListPort(ports.ListOpts{
 		DeviceID: routerID,
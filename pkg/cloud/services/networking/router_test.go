@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+)
+
+func Test_reconcileRouterRoutes(t *testing.T) {
+	onPrem := infrav1.RouterRoute{Destination: "192.168.0.0/16", NextHop: "10.6.0.10"}
+	office := infrav1.RouterRoute{Destination: "172.16.0.0/12", NextHop: "10.6.0.10"}
+	manual := routers.Route{DestinationCIDR: "10.20.0.0/16", NextHop: "10.6.0.20"}
+
+	tests := []struct {
+		name         string
+		specRoutes   []infrav1.RouterRoute
+		statusRoutes []infrav1.RouterRoute
+		routerRoutes []routers.Route
+		wantRoutes   *[]routers.Route
+	}{
+		{
+			name: "router without routes",
+		},
+		{
+			name:         "adds the routes of the spec and keeps other routes",
+			specRoutes:   []infrav1.RouterRoute{onPrem},
+			routerRoutes: []routers.Route{manual},
+			wantRoutes:   &[]routers.Route{manual, {DestinationCIDR: onPrem.Destination, NextHop: onPrem.NextHop}},
+		},
+		{
+			name:         "leaves up to date routes alone",
+			specRoutes:   []infrav1.RouterRoute{onPrem},
+			statusRoutes: []infrav1.RouterRoute{onPrem},
+			routerRoutes: []routers.Route{{DestinationCIDR: onPrem.Destination, NextHop: onPrem.NextHop}, manual},
+		},
+		{
+			name:         "removes the routes removed from the spec",
+			specRoutes:   []infrav1.RouterRoute{office},
+			statusRoutes: []infrav1.RouterRoute{onPrem},
+			routerRoutes: []routers.Route{{DestinationCIDR: onPrem.Destination, NextHop: onPrem.NextHop}, manual},
+			wantRoutes:   &[]routers.Route{manual, {DestinationCIDR: office.Destination, NextHop: office.NextHop}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			if tt.wantRoutes != nil {
+				mockClient.EXPECT().UpdateRouter("router-id", routers.UpdateOpts{Routes: tt.wantRoutes}).Return(&routers.Router{}, nil)
+			}
+			s := NewTestService("project-id", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec:   infrav1.OpenStackClusterSpec{RouterRoutes: tt.specRoutes},
+				Status: infrav1.OpenStackClusterStatus{RouterRoutes: tt.statusRoutes},
+			}
+			router := &routers.Router{ID: "router-id", Routes: tt.routerRoutes}
+			g.Expect(s.reconcileRouterRoutes(openStackCluster, router)).To(Succeed())
+			g.Expect(openStackCluster.Status.RouterRoutes).To(Equal(tt.specRoutes))
+		})
+	}
+}
+
+func Test_getRouterSubnetIDs(t *testing.T) {
+	tests := []struct {
+		name        string
+		subnets     []subnets.Subnet
+		want        []string
+		wantErrText string
+	}{
+		{
+			name:    "subnet found",
+			subnets: []subnets.Subnet{{ID: "az-1-subnet-id"}},
+			want:    []string{"az-1-subnet-id"},
+		},
+		{
+			name:        "several subnets found",
+			subnets:     []subnets.Subnet{{ID: "subnet-1"}, {ID: "subnet-2"}},
+			wantErrText: "routerSubnets[0] matched 2 subnets instead of exactly one",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			mockClient.EXPECT().ListSubnet(&subnets.ListOpts{Name: "az-1-subnet"}).Return(tt.subnets, nil)
+			s := NewTestService("project-id", mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					RouterSubnets: []infrav1.SubnetFilter{{Name: "az-1-subnet"}},
+				},
+			}
+			subnetIDs, err := s.getRouterSubnetIDs(openStackCluster)
+			if tt.wantErrText != "" {
+				g.Expect(err).To(MatchError(tt.wantErrText))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(subnetIDs).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_gatewayUpToDate(t *testing.T) {
+	current := &routers.GatewayInfo{
+		NetworkID: "external-network-id",
+		ExternalFixedIPs: []routers.ExternalFixedIP{
+			{SubnetID: "subnet-1", IPAddress: "203.0.113.10"},
+			{SubnetID: "subnet-2", IPAddress: "198.51.100.20"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		desired *routers.GatewayInfo
+		want    bool
+	}{
+		{
+			name: "same external IPs",
+			desired: &routers.GatewayInfo{NetworkID: "external-network-id", ExternalFixedIPs: []routers.ExternalFixedIP{
+				{SubnetID: "subnet-2", IPAddress: "198.51.100.20"},
+				{SubnetID: "subnet-1", IPAddress: "203.0.113.10"},
+			}},
+			want: true,
+		},
+		{
+			name: "external IP allocated by Neutron",
+			desired: &routers.GatewayInfo{NetworkID: "external-network-id", ExternalFixedIPs: []routers.ExternalFixedIP{
+				{SubnetID: "subnet-1", IPAddress: "203.0.113.10"},
+				{SubnetID: "subnet-2"},
+			}},
+			want: true,
+		},
+		{
+			name: "other external IP",
+			desired: &routers.GatewayInfo{NetworkID: "external-network-id", ExternalFixedIPs: []routers.ExternalFixedIP{
+				{SubnetID: "subnet-1", IPAddress: "203.0.113.11"},
+				{SubnetID: "subnet-2", IPAddress: "198.51.100.20"},
+			}},
+			want: false,
+		},
+		{
+			name:    "other external network",
+			desired: &routers.GatewayInfo{NetworkID: "other-network-id", ExternalFixedIPs: current.ExternalFixedIPs},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(gatewayUpToDate(current, tt.desired)).To(Equal(tt.want))
+		})
+	}
+}
@@ -18,13 +18,18 @@ package networking
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/approval"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/parallel"
 )
 
 const (
@@ -37,9 +42,9 @@ const (
 
 // ReconcileSecurityGroups reconcile the security groups.
 func (s *Service) ReconcileSecurityGroups(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
-	s.scope.Logger.Info("Reconciling security groups", "cluster", clusterName)
-	if !openStackCluster.Spec.ManagedSecurityGroups {
-		s.scope.Logger.V(4).Info("No need to reconcile security groups", "cluster", clusterName)
+	s.scope.Logger.Info("Reconciling security groups")
+	if openStackCluster.Spec.ManagedSecurityGroups == nil {
+		s.scope.Logger.V(logging.LevelDebug).Info("No need to reconcile security groups")
 		return nil
 	}
 
@@ -55,29 +60,45 @@ func (s *Service) ReconcileSecurityGroups(openStackCluster *infrav1.OpenStackClu
 		secGroupNames[bastionSuffix] = secBastionGroupName
 	}
 
+	previousSecGroups := map[string]*infrav1.SecurityGroup{
+		controlPlaneSuffix: openStackCluster.Status.ControlPlaneSecurityGroup,
+		workerSuffix:       openStackCluster.Status.WorkerSecurityGroup,
+		bastionSuffix:      openStackCluster.Status.BastionSecurityGroup,
+	}
+
 	// create security groups first, because desired rules use group ids.
-	for _, v := range secGroupNames {
-		if err := s.createSecurityGroupIfNotExists(openStackCluster, v); err != nil {
-			return err
-		}
+	var mu sync.Mutex
+	observedSecGroups := make(map[string]*infrav1.SecurityGroup)
+	var createGroups []func() error
+	for k, v := range secGroupNames {
+		k, v := k, v
+		createGroups = append(createGroups, func() error {
+			groupService, err := s.WithOwnRequestIDs()
+			if err != nil {
+				return err
+			}
+			secGroup, err := groupService.getOrCreateSecurityGroup(openStackCluster, v, previousSecGroups[k])
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			observedSecGroups[k] = secGroup
+			return nil
+		})
+	}
+	if err := parallel.Run(s.Parallelism(), createGroups...); err != nil {
+		return err
 	}
 	// create desired security groups
-	desiredSecGroups, err := s.generateDesiredSecGroups(openStackCluster, secGroupNames)
-	if err != nil {
+	desiredSecGroups := s.generateDesiredSecGroups(openStackCluster, secGroupNames, observedSecGroups)
+	if err := s.applyRuleProfiles(openStackCluster.Spec.ManagedSecurityGroups.RuleProfiles, desiredSecGroups); err != nil {
 		return err
 	}
 
-	observedSecGroups := make(map[string]*infrav1.SecurityGroup)
 	for k, desiredSecGroup := range desiredSecGroups {
-		var err error
-		observedSecGroups[k], err = s.getSecurityGroupByName(desiredSecGroup.Name)
-
-		if err != nil {
-			return err
-		}
-
 		if observedSecGroups[k].ID != "" {
-			observedSecGroup, err := s.reconcileGroupRules(desiredSecGroup, *observedSecGroups[k])
+			observedSecGroup, err := s.reconcileGroupRules(openStackCluster, desiredSecGroup, *observedSecGroups[k])
 			if err != nil {
 				return err
 			}
@@ -93,17 +114,13 @@ func (s *Service) ReconcileSecurityGroups(openStackCluster *infrav1.OpenStackClu
 	return nil
 }
 
-func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCluster, secGroupNames map[string]string) (map[string]infrav1.SecurityGroup, error) {
+func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCluster, secGroupNames map[string]string, observedSecGroups map[string]*infrav1.SecurityGroup) map[string]infrav1.SecurityGroup {
 	desiredSecGroups := make(map[string]infrav1.SecurityGroup)
 
 	var secControlPlaneGroupID string
 	var secWorkerGroupID string
 	var secBastionGroupID string
-	for i, v := range secGroupNames {
-		secGroup, err := s.getSecurityGroupByName(v)
-		if err != nil {
-			return desiredSecGroups, err
-		}
+	for i, secGroup := range observedSecGroups {
 		switch i {
 		case controlPlaneSuffix:
 			secControlPlaneGroupID = secGroup.ID
@@ -118,10 +135,24 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 	controlPlaneRules := append([]infrav1.SecurityGroupRule{}, defaultRules...)
 	workerRules := append([]infrav1.SecurityGroupRule{}, defaultRules...)
 
-	controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneHTTPS()...)
-	workerRules = append(workerRules, GetSGWorkerNodePort()...)
+	if external := openStackCluster.Spec.ExternalAPIServerEndpoint; external != nil {
+		// The external load balancer forwards to the API server port of the
+		// control plane machines
+		apiServerPort := 6443
+		if openStackCluster.Spec.APIServerPort != 0 {
+			apiServerPort = openStackCluster.Spec.APIServerPort
+		}
+		controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneExternalHTTPS(apiServerPort, external.AllowedCIDRs)...)
+	} else {
+		controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneHTTPS()...)
+	}
+	if openStackCluster.Spec.ManagedSecurityGroups.NodePortsFromServices {
+		workerRules = append(workerRules, GetSGWorkerServiceNodePorts(openStackCluster.Status.ServiceNodePorts)...)
+	} else {
+		workerRules = append(workerRules, GetSGWorkerNodePort()...)
+	}
 
-	if openStackCluster.Spec.AllowAllInClusterTraffic {
+	if openStackCluster.Spec.ManagedSecurityGroups.AllowAllInClusterTraffic {
 		// Permit all ingress from the cluster security groups
 		controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneAllowAll(remoteGroupIDSelf, secWorkerGroupID)...)
 		workerRules = append(workerRules, GetSGWorkerAllowAll(remoteGroupIDSelf, secControlPlaneGroupID)...)
@@ -130,25 +161,35 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 		workerRules = append(workerRules, GetSGWorkerGeneral(remoteGroupIDSelf, secControlPlaneGroupID)...)
 	}
 
+	// Without these rules the firewall of the cloud may drop the neighbor
+	// discovery of IPv6 nodes, which silently breaks their IPv6 traffic
+	var icmpv6Rules []infrav1.SecurityGroupRule
+	if openStackCluster.Spec.ManagedSecurityGroups.IPv6 {
+		icmpv6Rules = GetSGICMPv6NeighborDiscovery()
+	}
+	controlPlaneRules = append(controlPlaneRules, icmpv6Rules...)
+	workerRules = append(workerRules, icmpv6Rules...)
+
 	if openStackCluster.Spec.Bastion != nil && openStackCluster.Spec.Bastion.Enabled {
 		controlPlaneRules = append(controlPlaneRules, GetSGControlPlaneSSH(secBastionGroupID)...)
 		controlPlaneRules = append(controlPlaneRules, GetSGWorkerSSH(secBastionGroupID)...)
 
-		desiredSecGroups[bastionSuffix] = infrav1.SecurityGroup{
-			Name: secGroupNames[bastionSuffix],
-			Rules: append(
-				[]infrav1.SecurityGroupRule{
-					{
-						Description:  "SSH",
-						Direction:    "ingress",
-						EtherType:    "IPv4",
-						PortRangeMin: 22,
-						PortRangeMax: 22,
-						Protocol:     "tcp",
-					},
+		bastionRules := append(
+			[]infrav1.SecurityGroupRule{
+				{
+					Description:  "SSH",
+					Direction:    "ingress",
+					EtherType:    "IPv4",
+					PortRangeMin: 22,
+					PortRangeMax: 22,
+					Protocol:     "tcp",
 				},
-				defaultRules...,
-			),
+			},
+			defaultRules...,
+		)
+		desiredSecGroups[bastionSuffix] = infrav1.SecurityGroup{
+			Name:  secGroupNames[bastionSuffix],
+			Rules: append(bastionRules, icmpv6Rules...),
 		}
 	}
 
@@ -162,27 +203,91 @@ func (s *Service) generateDesiredSecGroups(openStackCluster *infrav1.OpenStackCl
 		Rules: workerRules,
 	}
 
-	return desiredSecGroups, nil
+	return desiredSecGroups
+}
+
+// applyRuleProfiles replaces the rules of the desired security groups of the
+// roles with a rule profile by the rules of their profile.
+func (s *Service) applyRuleProfiles(profiles *infrav1.SecurityGroupRuleProfiles, desiredSecGroups map[string]infrav1.SecurityGroup) error {
+	if profiles == nil {
+		return nil
+	}
+
+	profileFilters := map[string]*infrav1.SecurityGroupFilter{
+		controlPlaneSuffix: profiles.ControlPlane,
+		workerSuffix:       profiles.Worker,
+		bastionSuffix:      profiles.Bastion,
+	}
+	for suffix, filter := range profileFilters {
+		desired, ok := desiredSecGroups[suffix]
+		if filter == nil || !ok {
+			continue
+		}
+		profileRules, err := s.getRuleProfile(*filter)
+		if err != nil {
+			return err
+		}
+		desired.Rules = profileRules
+		desiredSecGroups[suffix] = desired
+	}
+	return nil
+}
+
+// getRuleProfile returns the rules of the security group selected by the
+// filter of a rule profile, which must match exactly one security group.
+// Rules whose remote group is the profile itself refer to the managed
+// security group instead.
+func (s *Service) getRuleProfile(filter infrav1.SecurityGroupFilter) ([]infrav1.SecurityGroupRule, error) {
+	listOpts := filter.ToListOpt()
+	if listOpts.ProjectID == "" && !filter.IsIDOnly() {
+		listOpts.ProjectID = s.scope.ProjectID
+	}
+	SGList, err := s.client.ListSecGroup(listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	profileName := filter.Name
+	if profileName == "" {
+		profileName = filter.ID
+	}
+	switch len(SGList) {
+	case 0:
+		return nil, fmt.Errorf("security group rule profile %s not found", profileName)
+	case 1:
+	default:
+		return nil, fmt.Errorf("more than one security group found for rule profile %s", profileName)
+	}
+
+	profile := convertOSSecGroupToConfigSecGroup(SGList[0])
+	profileRules := make([]infrav1.SecurityGroupRule, 0, len(profile.Rules))
+	for _, rule := range profile.Rules {
+		rule.ID = ""
+		rule.SecurityGroupID = ""
+		if rule.RemoteGroupID == profile.ID {
+			rule.RemoteGroupID = remoteGroupIDSelf
+		}
+		profileRules = append(profileRules, rule)
+	}
+	return profileRules, nil
 }
 
-func (s *Service) GetSecurityGroups(securityGroupParams []infrav1.SecurityGroupParam) ([]string, error) {
+func (s *Service) GetSecurityGroups(securityGroupFilters []infrav1.SecurityGroupFilter) ([]string, error) {
 	var sgIDs []string
-	for _, sg := range securityGroupParams {
-		// Don't validate an explicit UUID if we were given one
-		if sg.UUID != "" {
-			if isDuplicate(sgIDs, sg.UUID) {
+	for _, sg := range securityGroupFilters {
+		// Don't validate an explicit ID if we were given one without any other filter
+		if sg.IsIDOnly() {
+			if isDuplicate(sgIDs, sg.ID) {
 				continue
 			}
-			sgIDs = append(sgIDs, sg.UUID)
+			sgIDs = append(sgIDs, sg.ID)
 			continue
 		}
 
-		listOpts := groups.ListOpts(sg.Filter)
+		listOpts := sg.ToListOpt()
 		if listOpts.ProjectID == "" {
 			listOpts.ProjectID = s.scope.ProjectID
 		}
-		listOpts.Name = sg.Name
-		listOpts.ID = sg.UUID
 		SGList, err := s.client.ListSecGroup(listOpts)
 		if err != nil {
 			return nil, err
@@ -202,6 +307,20 @@ func (s *Service) GetSecurityGroups(securityGroupParams []infrav1.SecurityGroupP
 	return sgIDs, nil
 }
 
+// SecurityGroupExists returns whether a security group matches the filter.
+// Unlike GetSecurityGroups, a security group selected by its ID is looked up.
+func (s *Service) SecurityGroupExists(securityGroupFilter infrav1.SecurityGroupFilter) (bool, error) {
+	listOpts := securityGroupFilter.ToListOpt()
+	if listOpts.ProjectID == "" && !securityGroupFilter.IsIDOnly() {
+		listOpts.ProjectID = s.scope.ProjectID
+	}
+	SGList, err := s.client.ListSecGroup(listOpts)
+	if err != nil {
+		return false, err
+	}
+	return len(SGList) > 0, nil
+}
+
 func (s *Service) DeleteSecurityGroups(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
 	secGroupNames := []string{
 		getSecControlPlaneGroupName(clusterName),
@@ -231,18 +350,20 @@ func (s *Service) deleteSecurityGroup(openStackCluster *infrav1.OpenStackCluster
 		return nil
 	}
 	err = s.client.DeleteSecGroup(group.ID)
+	groupEvent := record.Resource{Kind: record.SecurityGroup, Name: group.Name, ID: group.ID, RequestID: s.scope.LastRequestID()}
 	if err != nil {
-		record.Warnf(openStackCluster, "FailedDeleteSecurityGroup", "Failed to delete security group %s with id %s: %v", group.Name, group.ID, err)
-		return err
+		groupEvent.RequestID = capoerrors.RequestID(capoerrors.WithRequestID(err, groupEvent.RequestID))
+		record.Failed(openStackCluster, record.Delete, groupEvent, err)
+		return capoerrors.WithRequestID(err, groupEvent.RequestID)
 	}
 
-	record.Eventf(openStackCluster, "SuccessfulDeleteSecurityGroup", "Deleted security group %s with id %s", group.Name, group.ID)
+	record.Succeeded(openStackCluster, record.Delete, groupEvent)
 	return nil
 }
 
 // reconcileGroupRules reconciles an already existing observed group by deleting rules not needed anymore and
 // creating rules that are missing.
-func (s *Service) reconcileGroupRules(desired, observed infrav1.SecurityGroup) (infrav1.SecurityGroup, error) {
+func (s *Service) reconcileGroupRules(openStackCluster *infrav1.OpenStackCluster, desired, observed infrav1.SecurityGroup) (infrav1.SecurityGroup, error) {
 	rulesToDelete := []infrav1.SecurityGroupRule{}
 	// fills rulesToDelete by calculating observed - desired
 	for _, observedRule := range observed.Rules {
@@ -285,70 +406,128 @@ func (s *Service) reconcileGroupRules(desired, observed infrav1.SecurityGroup) (
 		}
 	}
 
-	s.scope.Logger.V(4).Info("Deleting rules not needed anymore for group", "name", observed.Name, "amount", len(rulesToDelete))
-	for _, rule := range rulesToDelete {
-		s.scope.Logger.V(6).Info("Deleting rule", "ruleID", rule.ID, "groupName", observed.Name)
-		err := s.client.DeleteSecGroupRule(rule.ID)
-		if err != nil {
-			return infrav1.SecurityGroup{}, err
-		}
+	if len(rulesToDelete) == 0 && len(rulesToCreate) == 0 {
+		return observed, nil
+	}
+	groupEvent := record.Resource{
+		Kind:   record.SecurityGroup,
+		Name:   observed.Name,
+		ID:     observed.ID,
+		Detail: fmt.Sprintf("deleted %d and created %d rules", len(rulesToDelete), len(rulesToCreate)),
 	}
 
-	s.scope.Logger.V(4).Info("Creating new rules needed for group", "name", observed.Name, "amount", len(rulesToCreate))
-	for _, rule := range rulesToCreate {
-		r := rule
+	s.scope.Logger.Info("Deleting rules not needed anymore for group", "name", observed.Name, "id", observed.ID, "amount", len(rulesToDelete))
+	deleteRules := make([]func() error, 0, len(rulesToDelete))
+	for _, rule := range rulesToDelete {
+		rule := rule
+		deleteRules = append(deleteRules, func() error {
+			ruleService, err := s.WithOwnRequestIDs()
+			if err != nil {
+				return err
+			}
+			ruleService.scope.Logger.V(logging.LevelTrace).Info("Deleting rule", "id", rule.ID, "securityGroupID", observed.ID)
+			err = ruleService.client.DeleteSecGroupRule(rule.ID)
+			return capoerrors.WithRequestID(err, ruleService.scope.LastRequestID())
+		})
+	}
+	if err := parallel.Run(s.Parallelism(), deleteRules...); err != nil {
+		groupEvent.RequestID = capoerrors.RequestID(err)
+		record.Failed(openStackCluster, record.Update, groupEvent, err)
+		return infrav1.SecurityGroup{}, err
+	}
+
+	s.scope.Logger.Info("Creating new rules needed for group", "name", observed.Name, "id", observed.ID, "amount", len(rulesToCreate))
+	newRules := make([]infrav1.SecurityGroupRule, len(rulesToCreate))
+	createRules := make([]func() error, 0, len(rulesToCreate))
+	for i, rule := range rulesToCreate {
+		i, r := i, rule
 		r.SecurityGroupID = observed.ID
 		if r.RemoteGroupID == remoteGroupIDSelf {
 			r.RemoteGroupID = observed.ID
 		}
-		newRule, err := s.createRule(r)
-		if err != nil {
-			return infrav1.SecurityGroup{}, err
-		}
-		reconciledRules = append(reconciledRules, newRule)
+		createRules = append(createRules, func() error {
+			ruleService, err := s.WithOwnRequestIDs()
+			if err != nil {
+				return err
+			}
+			newRule, err := ruleService.createRule(openStackCluster, r)
+			if err != nil {
+				return capoerrors.WithRequestID(err, ruleService.scope.LastRequestID())
+			}
+			newRules[i] = newRule
+			return nil
+		})
 	}
-	observed.Rules = reconciledRules
+	if err := parallel.Run(s.Parallelism(), createRules...); err != nil {
+		groupEvent.RequestID = capoerrors.RequestID(err)
+		record.Failed(openStackCluster, record.Update, groupEvent, err)
+		return infrav1.SecurityGroup{}, err
+	}
+	observed.Rules = append(reconciledRules, newRules...)
+
+	// The rules are changed by several requests, so the event does not name
+	// a single one of them.
+	record.Succeeded(openStackCluster, record.Update, groupEvent)
 
 	return observed, nil
 }
 
-func (s *Service) createSecurityGroupIfNotExists(openStackCluster *infrav1.OpenStackCluster, groupName string) error {
+// getOrCreateSecurityGroup returns the security group with the given name.
+// The group with the ID of previous is preferred, so that a group restored
+// from the resource IDs annotation is re-adopted even if its name changed.
+// The group is created if neither exists.
+func (s *Service) getOrCreateSecurityGroup(openStackCluster *infrav1.OpenStackCluster, groupName string, previous *infrav1.SecurityGroup) (*infrav1.SecurityGroup, error) {
+	if previous != nil && previous.ID != "" {
+		secGroup, err := s.client.GetSecGroup(previous.ID)
+		if err == nil {
+			s.scope.Logger.V(logging.LevelDebug).Info("Reusing existing security group", "name", secGroup.Name, "id", secGroup.ID)
+			return convertOSSecGroupToConfigSecGroup(*secGroup), nil
+		}
+		if !capoerrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
 	secGroup, err := s.getSecurityGroupByName(groupName)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if secGroup == nil || secGroup.ID == "" {
-		s.scope.Logger.V(6).Info("Group doesn't exist, creating it.", "name", groupName)
-
-		createOpts := groups.CreateOpts{
-			Name:        groupName,
-			Description: "Cluster API managed group",
-		}
-		s.scope.Logger.V(6).Info("Creating group", "name", groupName)
-
-		group, err := s.client.CreateSecGroup(createOpts)
-		if err != nil {
-			record.Warnf(openStackCluster, "FailedCreateSecurityGroup", "Failed to create security group %s: %v", groupName, err)
-			return err
+	if secGroup.ID != "" {
+		if previous != nil && previous.ID != "" {
+			record.Warnf(openStackCluster, "MissingSecurityGroup", "Security group %s with id %s no longer exists, replaced by id %s", groupName, previous.ID, secGroup.ID)
 		}
+		s.scope.Logger.V(logging.LevelDebug).Info("Reusing existing security group", "name", groupName, "id", secGroup.ID)
+		return secGroup, nil
+	}
 
-		if len(openStackCluster.Spec.Tags) > 0 {
-			_, err = s.client.ReplaceAllAttributesTags("security-groups", group.ID, attributestags.ReplaceAllOpts{
-				Tags: openStackCluster.Spec.Tags,
-			})
-			if err != nil {
-				return err
-			}
-		}
+	if previous != nil && previous.ID != "" {
+		record.Warnf(openStackCluster, "MissingSecurityGroup", "Security group %s with id %s no longer exists, recreating it", groupName, previous.ID)
+	}
+	s.scope.Logger.Info("Creating security group", "name", groupName)
 
-		record.Eventf(openStackCluster, "SuccessfulCreateSecurityGroup", "Created security group %s with id %s", groupName, group.ID)
-		return nil
+	createOpts := groups.CreateOpts{
+		Name:        groupName,
+		Description: "Cluster API managed group",
 	}
+	group, err := s.client.CreateSecGroup(createOpts)
+	if err != nil {
+		groupEvent := record.Resource{Kind: record.SecurityGroup, Name: groupName, RequestID: capoerrors.RequestID(capoerrors.WithRequestID(err, s.scope.LastRequestID()))}
+		record.Failed(openStackCluster, record.Create, groupEvent, err)
+		return nil, capoerrors.WithRequestID(err, groupEvent.RequestID)
+	}
+	groupEvent := record.Resource{Kind: record.SecurityGroup, Name: groupName, ID: group.ID, RequestID: s.scope.LastRequestID()}
 
-	sInfo := fmt.Sprintf("Reuse Existing SecurityGroup %s with %s", groupName, secGroup.ID)
-	s.scope.Logger.V(6).Info(sInfo)
+	if len(openStackCluster.Spec.Tags) > 0 {
+		_, err = s.client.ReplaceAllAttributesTags("security-groups", group.ID, attributestags.ReplaceAllOpts{
+			Tags: openStackCluster.Spec.Tags,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return nil
+	record.Succeeded(openStackCluster, record.Create, groupEvent)
+	return convertOSSecGroupToConfigSecGroup(*group), nil
 }
 
 func (s *Service) getSecurityGroupByName(name string) (*infrav1.SecurityGroup, error) {
@@ -356,7 +535,7 @@ func (s *Service) getSecurityGroupByName(name string) (*infrav1.SecurityGroup, e
 		Name: name,
 	}
 
-	s.scope.Logger.V(6).Info("Attempting to fetch security group with", "name", name)
+	s.scope.Logger.V(logging.LevelTrace).Info("Fetching security group", "name", name)
 	allGroups, err := s.client.ListSecGroup(opts)
 	if err != nil {
 		return &infrav1.SecurityGroup{}, err
@@ -372,7 +551,11 @@ func (s *Service) getSecurityGroupByName(name string) (*infrav1.SecurityGroup, e
 	return &infrav1.SecurityGroup{}, fmt.Errorf("more than one security group found named: %s", name)
 }
 
-func (s *Service) createRule(r infrav1.SecurityGroupRule) (infrav1.SecurityGroupRule, error) {
+func (s *Service) createRule(openStackCluster *infrav1.OpenStackCluster, r infrav1.SecurityGroupRule) (infrav1.SecurityGroupRule, error) {
+	if err := s.review(openStackCluster, approval.Request{Kind: approval.KindSecurityGroupRule, SecurityGroupRule: &r}); err != nil {
+		return infrav1.SecurityGroupRule{}, err
+	}
+
 	dir := rules.RuleDirection(r.Direction)
 	proto := rules.RuleProtocol(r.Protocol)
 	etherType := rules.RuleEtherType(r.EtherType)
@@ -388,7 +571,7 @@ func (s *Service) createRule(r infrav1.SecurityGroupRule) (infrav1.SecurityGroup
 		RemoteIPPrefix: r.RemoteIPPrefix,
 		SecGroupID:     r.SecurityGroupID,
 	}
-	s.scope.Logger.V(6).Info("Creating rule", "Description", r.Description, "Direction", dir, "PortRangeMin", r.PortRangeMin, "PortRangeMax", r.PortRangeMax, "Proto", proto, "etherType", etherType, "RemoteGroupID", r.RemoteGroupID, "RemoteIPPrefix", r.RemoteIPPrefix, "SecurityGroupID", r.SecurityGroupID)
+	s.scope.Logger.V(logging.LevelTrace).Info("Creating rule", "description", r.Description, "direction", dir, "portRangeMin", r.PortRangeMin, "portRangeMax", r.PortRangeMax, "protocol", proto, "etherType", etherType, "remoteGroupID", r.RemoteGroupID, "remoteIPPrefix", r.RemoteIPPrefix, "securityGroupID", r.SecurityGroupID)
 	rule, err := s.client.CreateSecGroupRule(createOpts)
 	if err != nil {
 		return infrav1.SecurityGroupRule{}, err
@@ -17,7 +17,9 @@ limitations under the License.
 package networking
 
 import (
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	"net"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 )
 
 var defaultRules = []infrav1.SecurityGroupRule{
@@ -341,6 +343,31 @@ func GetSGControlPlaneHTTPS() []infrav1.SecurityGroupRule {
 	}
 }
 
+// Allow the given CIDRs, or all traffic if there are none, to access the API
+// on the given port.
+func GetSGControlPlaneExternalHTTPS(port int, allowedCIDRs []string) []infrav1.SecurityGroupRule {
+	if len(allowedCIDRs) == 0 {
+		allowedCIDRs = []string{""}
+	}
+	rules := make([]infrav1.SecurityGroupRule, 0, len(allowedCIDRs))
+	for _, cidr := range allowedCIDRs {
+		etherType := "IPv4"
+		if ip, _, err := net.ParseCIDR(cidr); err == nil && ip.To4() == nil {
+			etherType = "IPv6"
+		}
+		rules = append(rules, infrav1.SecurityGroupRule{
+			Description:    "Kubernetes API",
+			Direction:      "ingress",
+			EtherType:      etherType,
+			PortRangeMin:   port,
+			PortRangeMax:   port,
+			Protocol:       "tcp",
+			RemoteIPPrefix: cidr,
+		})
+	}
+	return rules
+}
+
 // Allow all traffic, including from outside the cluster, to access node port services.
 func GetSGWorkerNodePort() []infrav1.SecurityGroupRule {
 	return []infrav1.SecurityGroupRule{
@@ -355,6 +382,55 @@ func GetSGWorkerNodePort() []infrav1.SecurityGroupRule {
 	}
 }
 
+// Allow all traffic, including from outside the cluster, to access the given
+// node ports of the Services of the workload cluster.
+func GetSGWorkerServiceNodePorts(nodePorts []infrav1.ServiceNodePort) []infrav1.SecurityGroupRule {
+	rules := make([]infrav1.SecurityGroupRule, 0, len(nodePorts))
+	for _, nodePort := range nodePorts {
+		rules = append(rules, infrav1.SecurityGroupRule{
+			Description:  "Node Port Services",
+			Direction:    "ingress",
+			EtherType:    "IPv4",
+			PortRangeMin: nodePort.Port,
+			PortRangeMax: nodePort.Port,
+			Protocol:     nodePort.Protocol,
+		})
+	}
+	return rules
+}
+
+// Permit the ICMPv6 messages of IPv6 router discovery and neighbor discovery.
+// They are sent from link-local addresses, so they cannot be matched by the
+// remote group of the cluster nodes.
+func GetSGICMPv6NeighborDiscovery() []infrav1.SecurityGroupRule {
+	return []infrav1.SecurityGroupRule{
+		{
+			Description:  "Router Advertisement (ICMPv6)",
+			Direction:    "ingress",
+			EtherType:    "IPv6",
+			PortRangeMin: 134,
+			PortRangeMax: 0,
+			Protocol:     "ipv6-icmp",
+		},
+		{
+			Description:  "Neighbor Solicitation (ICMPv6)",
+			Direction:    "ingress",
+			EtherType:    "IPv6",
+			PortRangeMin: 135,
+			PortRangeMax: 0,
+			Protocol:     "ipv6-icmp",
+		},
+		{
+			Description:  "Neighbor Advertisement (ICMPv6)",
+			Direction:    "ingress",
+			EtherType:    "IPv6",
+			PortRangeMin: 136,
+			PortRangeMax: 0,
+			Protocol:     "ipv6-icmp",
+		},
+	}
+}
+
 // Permit all ingress from the cluster security groups.
 func GetSGControlPlaneAllowAll(remoteGroupIDSelf, secWorkerGroupID string) []infrav1.SecurityGroupRule {
 	return []infrav1.SecurityGroupRule{
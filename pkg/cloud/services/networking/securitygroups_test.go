@@ -0,0 +1,305 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+func Test_generateDesiredSecGroupsAPIServerRules(t *testing.T) {
+	tests := []struct {
+		name string
+		spec infrav1.OpenStackClusterSpec
+		want []infrav1.SecurityGroupRule
+	}{
+		{
+			name: "API server open to all",
+			spec: infrav1.OpenStackClusterSpec{},
+			want: GetSGControlPlaneHTTPS(),
+		},
+		{
+			name: "External API server endpoint",
+			spec: infrav1.OpenStackClusterSpec{
+				APIServerPort:             8443,
+				ExternalAPIServerEndpoint: &infrav1.ExternalAPIServerEndpoint{},
+			},
+			want: []infrav1.SecurityGroupRule{
+				{Description: "Kubernetes API", Direction: "ingress", EtherType: "IPv4", PortRangeMin: 8443, PortRangeMax: 8443, Protocol: "tcp"},
+			},
+		},
+		{
+			name: "External API server endpoint with allowed CIDRs",
+			spec: infrav1.OpenStackClusterSpec{
+				ExternalAPIServerEndpoint: &infrav1.ExternalAPIServerEndpoint{
+					AllowedCIDRs: []string{"192.168.10.0/24", "2001:db8::/64"},
+				},
+			},
+			want: []infrav1.SecurityGroupRule{
+				{Description: "Kubernetes API", Direction: "ingress", EtherType: "IPv4", PortRangeMin: 6443, PortRangeMax: 6443, Protocol: "tcp", RemoteIPPrefix: "192.168.10.0/24"},
+				{Description: "Kubernetes API", Direction: "ingress", EtherType: "IPv6", PortRangeMin: 6443, PortRangeMax: 6443, Protocol: "tcp", RemoteIPPrefix: "2001:db8::/64"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &infrav1.OpenStackCluster{Spec: tt.spec}
+			openStackCluster.Spec.ManagedSecurityGroups = &infrav1.ManagedSecurityGroups{}
+			secGroupNames := map[string]string{
+				controlPlaneSuffix: "controlplane",
+				workerSuffix:       "worker",
+			}
+			desired := (&Service{}).generateDesiredSecGroups(openStackCluster, secGroupNames, map[string]*infrav1.SecurityGroup{})
+
+			var apiServerRules []infrav1.SecurityGroupRule
+			for _, rule := range desired[controlPlaneSuffix].Rules {
+				if rule.Description == "Kubernetes API" {
+					apiServerRules = append(apiServerRules, rule)
+				}
+			}
+			g.Expect(apiServerRules).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_generateDesiredSecGroupsICMPv6Rules(t *testing.T) {
+	tests := []struct {
+		name string
+		ipv6 bool
+		want []infrav1.SecurityGroupRule
+	}{
+		{
+			name: "IPv6 disabled",
+		},
+		{
+			name: "IPv6 enabled",
+			ipv6: true,
+			want: GetSGICMPv6NeighborDiscovery(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{IPv6: tt.ipv6},
+					Bastion:               &infrav1.Bastion{Enabled: true},
+				},
+			}
+			secGroupNames := map[string]string{
+				controlPlaneSuffix: "controlplane",
+				workerSuffix:       "worker",
+				bastionSuffix:      "bastion",
+			}
+			desired := (&Service{}).generateDesiredSecGroups(openStackCluster, secGroupNames, map[string]*infrav1.SecurityGroup{})
+
+			for _, suffix := range []string{controlPlaneSuffix, workerSuffix, bastionSuffix} {
+				var icmpv6Rules []infrav1.SecurityGroupRule
+				for _, rule := range desired[suffix].Rules {
+					if rule.Protocol == "ipv6-icmp" {
+						icmpv6Rules = append(icmpv6Rules, rule)
+					}
+				}
+				g.Expect(icmpv6Rules).To(Equal(tt.want), suffix)
+			}
+		})
+	}
+}
+
+func Test_generateDesiredSecGroupsNodePortRules(t *testing.T) {
+	tests := []struct {
+		name                  string
+		nodePortsFromServices bool
+		serviceNodePorts      []infrav1.ServiceNodePort
+		want                  []infrav1.SecurityGroupRule
+	}{
+		{
+			name:             "Node port range",
+			serviceNodePorts: []infrav1.ServiceNodePort{{Port: 30080, Protocol: "tcp"}},
+			want:             GetSGWorkerNodePort(),
+		},
+		{
+			name:                  "Node ports from Services",
+			nodePortsFromServices: true,
+			serviceNodePorts:      []infrav1.ServiceNodePort{{Port: 30080, Protocol: "tcp"}, {Port: 31053, Protocol: "udp"}},
+			want: []infrav1.SecurityGroupRule{
+				{Description: "Node Port Services", Direction: "ingress", EtherType: "IPv4", PortRangeMin: 30080, PortRangeMax: 30080, Protocol: "tcp"},
+				{Description: "Node Port Services", Direction: "ingress", EtherType: "IPv4", PortRangeMin: 31053, PortRangeMax: 31053, Protocol: "udp"},
+			},
+		},
+		{
+			name:                  "No Services with node ports",
+			nodePortsFromServices: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{
+					ManagedSecurityGroups: &infrav1.ManagedSecurityGroups{NodePortsFromServices: tt.nodePortsFromServices},
+				},
+				Status: infrav1.OpenStackClusterStatus{
+					ServiceNodePorts: tt.serviceNodePorts,
+				},
+			}
+			secGroupNames := map[string]string{
+				controlPlaneSuffix: "controlplane",
+				workerSuffix:       "worker",
+			}
+			desired := (&Service{}).generateDesiredSecGroups(openStackCluster, secGroupNames, map[string]*infrav1.SecurityGroup{})
+
+			var nodePortRules []infrav1.SecurityGroupRule
+			for _, rule := range desired[workerSuffix].Rules {
+				if rule.Description == "Node Port Services" {
+					nodePortRules = append(nodePortRules, rule)
+				}
+			}
+			g.Expect(nodePortRules).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_applyRuleProfiles(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().ListSecGroup(groups.ListOpts{Name: "worker-profile", ProjectID: "project-id"}).Return([]groups.SecGroup{
+		{
+			ID:   "profile-id",
+			Name: "worker-profile",
+			Rules: []rules.SecGroupRule{
+				{ID: "rule-1", SecGroupID: "profile-id", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 443, PortRangeMax: 443, RemoteIPPrefix: "10.0.0.0/8"},
+				{ID: "rule-2", SecGroupID: "profile-id", Direction: "ingress", EtherType: "IPv4", RemoteGroupID: "profile-id"},
+			},
+		},
+	}, nil)
+
+	s := NewTestService("project-id", mockClient, logr.Discard())
+	desired := map[string]infrav1.SecurityGroup{
+		controlPlaneSuffix: {Name: "controlplane", Rules: GetSGControlPlaneHTTPS()},
+		workerSuffix:       {Name: "worker", Rules: GetSGWorkerNodePort()},
+	}
+	profiles := &infrav1.SecurityGroupRuleProfiles{
+		Worker:  &infrav1.SecurityGroupFilter{Name: "worker-profile"},
+		Bastion: &infrav1.SecurityGroupFilter{Name: "bastion-profile"},
+	}
+
+	g.Expect(s.applyRuleProfiles(profiles, desired)).To(Succeed())
+	g.Expect(desired[controlPlaneSuffix].Rules).To(Equal(GetSGControlPlaneHTTPS()))
+	g.Expect(desired[workerSuffix].Rules).To(Equal([]infrav1.SecurityGroupRule{
+		{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 443, PortRangeMax: 443, RemoteIPPrefix: "10.0.0.0/8"},
+		{Direction: "ingress", EtherType: "IPv4", RemoteGroupID: remoteGroupIDSelf},
+	}))
+	g.Expect(desired).NotTo(HaveKey(bastionSuffix))
+}
+
+func Test_applyRuleProfilesNotFound(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+	mockClient.EXPECT().ListSecGroup(groups.ListOpts{ID: "profile-id"}).Return(nil, nil)
+
+	s := NewTestService("project-id", mockClient, logr.Discard())
+	desired := map[string]infrav1.SecurityGroup{
+		controlPlaneSuffix: {Name: "controlplane", Rules: GetSGControlPlaneHTTPS()},
+	}
+	profiles := &infrav1.SecurityGroupRuleProfiles{ControlPlane: &infrav1.SecurityGroupFilter{ID: "profile-id"}}
+
+	g.Expect(s.applyRuleProfiles(profiles, desired)).To(MatchError("security group rule profile profile-id not found"))
+}
+
+func Test_reconcileGroupRulesNormalizedPrefixes(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	// No rule may be deleted or created
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+
+	s := NewTestService("project-id", mockClient, logr.Discard())
+	desired := infrav1.SecurityGroup{Name: "worker", Rules: []infrav1.SecurityGroupRule{
+		{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 22, PortRangeMax: 22, RemoteIPPrefix: "10.6.0.5/24"},
+		{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 6443, PortRangeMax: 6443, RemoteIPPrefix: "192.168.1.10"},
+		{Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 443, PortRangeMax: 443, RemoteIPPrefix: "0.0.0.0/0"},
+	}}
+	observed := infrav1.SecurityGroup{Name: "worker", ID: "worker-id", Rules: []infrav1.SecurityGroupRule{
+		{ID: "rule-1", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 22, PortRangeMax: 22, RemoteIPPrefix: "10.6.0.0/24"},
+		{ID: "rule-2", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 6443, PortRangeMax: 6443, RemoteIPPrefix: "192.168.1.10/32"},
+		{ID: "rule-3", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 443, PortRangeMax: 443},
+	}}
+
+	reconciled, err := s.reconcileGroupRules(&infrav1.OpenStackCluster{}, desired, observed)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(reconciled).To(Equal(observed))
+}
+
+func Test_reconcileGroupRulesConcurrentRequestIDs(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+
+	// Both rules are deleted at the same time and fail with the ID of
+	// their own request.
+	var deleting sync.WaitGroup
+	deleting.Add(2)
+	deleteRule := func(id string) error {
+		deleting.Done()
+		deleting.Wait()
+		return gophercloud.ErrDefault409{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{
+			URL:            "https://neutron/v2.0/security-group-rules/" + id,
+			Actual:         409,
+			ResponseHeader: http.Header{"X-Openstack-Request-Id": []string{"req-" + id}},
+		}}
+	}
+	mockClient.EXPECT().DeleteSecGroupRule("rule-1").DoAndReturn(deleteRule)
+	mockClient.EXPECT().DeleteSecGroupRule("rule-2").DoAndReturn(deleteRule)
+
+	s := NewTestService("project-id", mockClient, logr.Discard())
+	observed := infrav1.SecurityGroup{Name: "worker", ID: "worker-id", Rules: []infrav1.SecurityGroupRule{
+		{ID: "rule-1", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 22, PortRangeMax: 22},
+		{ID: "rule-2", Direction: "ingress", EtherType: "IPv4", Protocol: "tcp", PortRangeMin: 443, PortRangeMax: 443},
+	}}
+
+	_, err := s.reconcileGroupRules(&infrav1.OpenStackCluster{}, infrav1.SecurityGroup{Name: "worker"}, observed)
+	var aggregate kerrors.Aggregate
+	g.Expect(errors.As(err, &aggregate)).To(BeTrue())
+	requestIDs := map[string]string{}
+	for _, err := range aggregate.Errors() {
+		var conflict gophercloud.ErrDefault409
+		g.Expect(errors.As(err, &conflict)).To(BeTrue())
+		requestIDs[path.Base(conflict.URL)] = capoerrors.RequestID(err)
+	}
+	g.Expect(requestIDs).To(Equal(map[string]string{"rule-1": "req-rule-1", "rule-2": "req-rule-2"}))
+}
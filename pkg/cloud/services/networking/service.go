@@ -17,8 +17,10 @@ limitations under the License.
 package networking
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/gophercloud/gophercloud"
@@ -26,8 +28,12 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/approval"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/dryrun"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/cache"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/logging"
 )
 
 const (
@@ -36,6 +42,29 @@ const (
 	portResource  string = "ports"
 )
 
+const (
+	timeoutDelete              = 5 * time.Minute
+	timeoutFloatingIPAssociate = 5 * time.Minute
+)
+
+// portPageSize is the number of ports read per request when ports are
+// processed page by page.
+const portPageSize = 500
+
+// defaultParallelism is the maximum number of independent networking
+// resources created or deleted concurrently by a reconcile.
+var defaultParallelism = 4
+
+// SetParallelism sets the maximum number of independent networking resources,
+// e.g. security group rules, created or deleted concurrently by a reconcile. A
+// parallelism of 1 or less creates them one after the other.
+func SetParallelism(parallelism int) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	defaultParallelism = parallelism
+}
+
 // Service interfaces with the OpenStack Networking API.
 // It will create a network related infrastructure for the cluster, like network, subnet, router, security groups.
 type Service struct {
@@ -57,8 +86,11 @@ func NewService(scope *scope.Scope) (*Service, error) {
 	}
 
 	return &Service{
-		scope:  scope,
-		client: networkClient{serviceClient},
+		scope: scope,
+		client: cachedNetworkClient{
+			NetworkClient: networkClient{serviceClient},
+			cache:         cache.ForScope(scope),
+		},
 	}, nil
 }
 
@@ -73,11 +105,69 @@ func NewTestService(projectID string, client NetworkClient, logger logr.Logger)
 	}
 }
 
+// WithOwnRequestIDs returns a service whose requests are recorded apart from
+// those of s, see scope.WithOwnRequestIDs. Each of the functions s runs
+// concurrently uses its own service, so that its events name the IDs of its
+// own requests.
+func (s *Service) WithOwnRequestIDs() (*Service, error) {
+	if s.scope.ProviderClient == nil {
+		return s, nil
+	}
+	return NewService(s.scope.WithOwnRequestIDs())
+}
+
+// Parallelism returns the maximum number of independent networking resources
+// the service creates or deletes concurrently. Dry-run reconciles create them
+// one after the other, so that their plan does not change between reconciles.
+func (s *Service) Parallelism() int {
+	if s.scope.ProviderClient != nil && dryrun.IsDryRun(s.scope.ProviderClient.Context) {
+		return 1
+	}
+	return defaultParallelism
+}
+
+// review asks the approval webhook whether the resource of the request may
+// be created for eventObject. Dry-run reconciles do not create resources, so
+// their requests are not reviewed.
+func (s *Service) review(eventObject runtime.Object, request approval.Request) error {
+	ctx := context.Background()
+	if s.scope != nil && s.scope.ProviderClient != nil && s.scope.ProviderClient.Context != nil {
+		ctx = s.scope.ProviderClient.Context
+	}
+	if dryrun.IsDryRun(ctx) {
+		return nil
+	}
+	request.Object = approval.ObjectReferenceFor(eventObject)
+	err := approval.Review(ctx, request)
+	if approval.IsDenied(err) {
+		record.Warnf(eventObject, "ApprovalDenied", "Creation of %s denied: %v", request.Kind, err)
+	}
+	return err
+}
+
+// deleteTimeout returns how long to wait for deleted ports and trunks to be
+// gone, and for the ports of deleted servers to be released.
+func (s *Service) deleteTimeout() time.Duration {
+	if s.scope.Timeouts.Deletion > 0 {
+		return s.scope.Timeouts.Deletion
+	}
+	return timeoutDelete
+}
+
+// floatingIPAssociateTimeout returns how long to wait for a floating IP to
+// become active once associated, or down once disassociated.
+func (s *Service) floatingIPAssociateTimeout() time.Duration {
+	if s.scope.Timeouts.FloatingIPAssociate > 0 {
+		return s.scope.Timeouts.FloatingIPAssociate
+	}
+	return timeoutFloatingIPAssociate
+}
+
 // replaceAllAttributesTags replaces all tags on a neworking resource.
 // the value of resourceType must match one of the allowed constants: trunkResource or portResource.
 func (s *Service) replaceAllAttributesTags(eventObject runtime.Object, resourceType string, resourceID string, tags []string) error {
 	if len(tags) == 0 {
-		s.scope.Logger.Info("no tags provided to ReplaceAllAttributesTags", "resourceType", resourceType, "resourceID", resourceID)
+		s.scope.Logger.V(logging.LevelDebug).Info("No tags provided to ReplaceAllAttributesTags", "resourceType", resourceType, "id", resourceID)
 		return nil
 	}
 	if resourceType != trunkResource && resourceType != portResource {
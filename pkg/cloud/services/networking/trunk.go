@@ -29,10 +29,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 )
 
-const (
-	timeoutTrunkDelete       = 3 * time.Minute
-	retryIntervalTrunkDelete = 5 * time.Second
-)
+const retryIntervalTrunkDelete = 5 * time.Second
 
 func (s *Service) GetTrunkSupport() (bool, error) {
 	allExts, err := s.client.ListExtensions()
@@ -48,9 +45,12 @@ func (s *Service) GetTrunkSupport() (bool, error) {
 	return false, nil
 }
 
+// getOrCreateTrunk returns the trunk whose parent port is the port with the
+// given ID, creating it if it does not exist. A trunk which already exists on
+// the port is adopted whatever its name, as a port can only be the parent
+// port of a single trunk.
 func (s *Service) getOrCreateTrunk(eventObject runtime.Object, clusterName, trunkName, portID string) (*trunks.Trunk, error) {
 	trunkList, err := s.client.ListTrunk(trunks.ListOpts{
-		Name:   trunkName,
 		PortID: portID,
 	})
 	if err != nil {
@@ -58,7 +58,11 @@ func (s *Service) getOrCreateTrunk(eventObject runtime.Object, clusterName, trun
 	}
 
 	if len(trunkList) != 0 {
-		return &trunkList[0], nil
+		trunk := &trunkList[0]
+		if trunk.Name != trunkName {
+			record.Eventf(eventObject, "SuccessfulAdoptTrunk", "Adopted trunk %s with id %s of port %s", trunk.Name, trunk.ID, portID)
+		}
+		return trunk, nil
 	}
 
 	trunkCreateOpts := trunks.CreateOpts{
@@ -77,6 +81,34 @@ func (s *Service) getOrCreateTrunk(eventObject runtime.Object, clusterName, trun
 	return trunk, nil
 }
 
+// ListTrunksForCluster returns the trunks created for the cluster.
+func (s *Service) ListTrunksForCluster(clusterName string) ([]trunks.Trunk, error) {
+	trunkList, err := s.client.ListTrunk(trunks.ListOpts{
+		Description: names.GetDescription(clusterName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list trunks of cluster %s: %v", clusterName, err)
+	}
+	return trunkList, nil
+}
+
+// GetTrunkByPortID returns the trunk whose parent port is the port with the
+// given ID, or nil if the port is not the parent port of a trunk.
+func (s *Service) GetTrunkByPortID(portID string) (*trunks.Trunk, error) {
+	trunkList, err := s.client.ListTrunk(trunks.ListOpts{PortID: portID})
+	if err != nil {
+		return nil, err
+	}
+	if len(trunkList) == 0 {
+		return nil, nil
+	}
+	return &trunkList[0], nil
+}
+
+// DeleteTrunk deletes the trunk whose parent port is the port with the given
+// ID. Its subports are detached first, as Neutron refuses to delete a trunk
+// which still has subports. The subport ports which were created for the same
+// cluster as the trunk are deleted afterwards, the others are left alone.
 func (s *Service) DeleteTrunk(eventObject runtime.Object, portID string) error {
 	listOpts := trunks.ListOpts{
 		PortID: portID,
@@ -88,11 +120,25 @@ func (s *Service) DeleteTrunk(eventObject runtime.Object, portID string) error {
 	if len(trunkInfo) != 1 {
 		return nil
 	}
+	trunk := trunkInfo[0]
 
-	err = util.PollImmediate(retryIntervalTrunkDelete, timeoutTrunkDelete, func() (bool, error) {
-		if err := s.client.DeleteTrunk(trunkInfo[0].ID); err != nil {
+	if len(trunk.Subports) > 0 {
+		removeOpts := trunks.RemoveSubportsOpts{
+			Subports: make([]trunks.RemoveSubport, 0, len(trunk.Subports)),
+		}
+		for _, subport := range trunk.Subports {
+			removeOpts.Subports = append(removeOpts.Subports, trunks.RemoveSubport{PortID: subport.PortID})
+		}
+		if err := s.client.RemoveSubports(trunk.ID, removeOpts); err != nil && !capoerrors.IsNotFound(err) {
+			record.Warnf(eventObject, "FailedDeleteTrunk", "Failed to remove subports of trunk %s with id %s: %v", trunk.Name, trunk.ID, err)
+			return err
+		}
+	}
+
+	err = util.PollImmediate(retryIntervalTrunkDelete, s.deleteTimeout(), func() (bool, error) {
+		if err := s.client.DeleteTrunk(trunk.ID); err != nil {
 			if capoerrors.IsNotFound(err) {
-				record.Eventf(eventObject, "SuccessfulDeleteTrunk", "Trunk %s with id %s did not exist", trunkInfo[0].Name, trunkInfo[0].ID)
+				record.Eventf(eventObject, "SuccessfulDeleteTrunk", "Trunk %s with id %s did not exist", trunk.Name, trunk.ID)
 				return true, nil
 			}
 			if capoerrors.IsConflict(err) {
@@ -106,10 +152,35 @@ func (s *Service) DeleteTrunk(eventObject runtime.Object, portID string) error {
 		return true, nil
 	})
 	if err != nil {
-		record.Warnf(eventObject, "FailedDeleteTrunk", "Failed to delete trunk %s with id %s: %v", trunkInfo[0].Name, trunkInfo[0].ID, err)
+		record.Warnf(eventObject, "FailedDeleteTrunk", "Failed to delete trunk %s with id %s: %v", trunk.Name, trunk.ID, err)
 		return err
 	}
 
-	record.Eventf(eventObject, "SuccessfulDeleteTrunk", "Deleted trunk %s with id %s", trunkInfo[0].Name, trunkInfo[0].ID)
+	record.Eventf(eventObject, "SuccessfulDeleteTrunk", "Deleted trunk %s with id %s", trunk.Name, trunk.ID)
+	return s.deleteSubportPorts(eventObject, &trunk)
+}
+
+// deleteSubportPorts deletes the subport ports of the trunk which were
+// created for the same cluster as the trunk. Ports of the subports of an
+// adopted trunk are not touched.
+func (s *Service) deleteSubportPorts(eventObject runtime.Object, trunk *trunks.Trunk) error {
+	if trunk.Description == "" {
+		return nil
+	}
+	for _, subport := range trunk.Subports {
+		port, err := s.client.GetPort(subport.PortID)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("get subport %s of trunk %s: %v", subport.PortID, trunk.ID, err)
+		}
+		if !names.MatchesDescription(port.Description, trunk.Description) {
+			continue
+		}
+		if err := s.DeletePort(eventObject, port.ID); err != nil && !capoerrors.IsNotFound(err) {
+			return fmt.Errorf("delete subport %s of trunk %s: %v", port.ID, trunk.ID, err)
+		}
+	}
 	return nil
 }
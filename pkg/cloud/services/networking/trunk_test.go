@@ -19,11 +19,14 @@ package networking
 import (
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	. "github.com/onsi/gomega"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking/mock_networking"
 )
 
@@ -48,7 +51,6 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 			func(m *mock_networking.MockNetworkClientMockRecorder) {
 				m.
 					ListTrunk(trunks.ListOpts{
-						Name:   "trunk-1",
 						PortID: "port-1",
 					}).Return([]trunks.Trunk{{
 					Name: "trunk-1",
@@ -58,6 +60,22 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 			&trunks.Trunk{Name: "trunk-1", ID: "port-1"},
 			false,
 		},
+		{
+			"adopts trunk of the port with another name",
+			"trunk-1",
+			"port-1",
+			func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.
+					ListTrunk(trunks.ListOpts{
+						PortID: "port-1",
+					}).Return([]trunks.Trunk{{
+					Name: "byo-trunk",
+					ID:   "trunk-1",
+				}}, nil)
+			},
+			&trunks.Trunk{Name: "byo-trunk", ID: "trunk-1"},
+			false,
+		},
 		{
 			"creates trunk if not found",
 			"trunk-1",
@@ -66,7 +84,6 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 				// No ports found
 				m.
 					ListTrunk(trunks.ListOpts{
-						Name:   "trunk-1",
 						PortID: "port-1",
 					}).Return([]trunks.Trunk{}, nil)
 				m.
@@ -106,3 +123,82 @@ func Test_GetOrCreateTrunk(t *testing.T) {
 		})
 	}
 }
+
+func Test_DeleteTrunk(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		parentPortID = "port-1"
+		trunkID      = "trunk-1"
+		ownPortID    = "subport-1"
+		byoPortID    = "subport-2"
+	)
+	description := "Created by cluster-api-provider-openstack cluster test-cluster"
+
+	tests := []struct {
+		name    string
+		expect  func(m *mock_networking.MockNetworkClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "does nothing without trunk",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListTrunk(trunks.ListOpts{PortID: parentPortID}).Return([]trunks.Trunk{}, nil)
+			},
+		},
+		{
+			name: "deletes trunk without subports",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListTrunk(trunks.ListOpts{PortID: parentPortID}).Return([]trunks.Trunk{{ID: trunkID, Description: description}}, nil)
+				m.DeleteTrunk(trunkID).Return(nil)
+			},
+		},
+		{
+			name: "detaches subports before deleting trunk and deletes own subport ports",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListTrunk(trunks.ListOpts{PortID: parentPortID}).Return([]trunks.Trunk{{
+					ID:          trunkID,
+					Description: description,
+					Subports:    []trunks.Subport{{PortID: ownPortID}, {PortID: byoPortID}},
+				}}, nil)
+				gomock.InOrder(
+					m.RemoveSubports(trunkID, trunks.RemoveSubportsOpts{
+						Subports: []trunks.RemoveSubport{{PortID: ownPortID}, {PortID: byoPortID}},
+					}).Return(nil),
+					m.DeleteTrunk(trunkID).Return(nil),
+					m.GetPort(ownPortID).Return(&ports.Port{ID: ownPortID, Description: description}, nil),
+					m.DeletePort(ownPortID).Return(nil),
+					m.GetPort(byoPortID).Return(&ports.Port{ID: byoPortID, Description: "byo"}, nil),
+				)
+			},
+		},
+		{
+			name: "fails if subports cannot be detached",
+			expect: func(m *mock_networking.MockNetworkClientMockRecorder) {
+				m.ListTrunk(trunks.ListOpts{PortID: parentPortID}).Return([]trunks.Trunk{{
+					ID:       trunkID,
+					Subports: []trunks.Subport{{PortID: byoPortID}},
+				}}, nil)
+				m.RemoveSubports(trunkID, gomock.Any()).Return(gophercloud.ErrDefault500{})
+			},
+			wantErr: true,
+		},
+	}
+
+	eventObject := &infrav1.OpenStackMachine{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_networking.NewMockNetworkClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService("", mockClient, logr.Discard())
+			err := s.DeleteTrunk(eventObject, parentPortID)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package objectstorage
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+type ObjectStorageClient interface {
+	CreateContainer(name string, opts containers.CreateOptsBuilder) error
+	GetContainer(name string) (*containers.GetHeader, error)
+	UpdateContainer(name string, opts containers.UpdateOptsBuilder) error
+	DeleteContainer(name string) error
+	ListObjects(containerName string, opts objects.ListOptsBuilder) ([]objects.Object, error)
+	CreateObject(containerName, name string, opts objects.CreateOptsBuilder) error
+	DeleteObject(containerName, name string) error
+	CreateTempURL(containerName, name string, opts objects.CreateTempURLOpts) (string, error)
+}
+
+type objectStorageClient struct {
+	serviceClient *gophercloud.ServiceClient
+}
+
+func (c objectStorageClient) CreateContainer(name string, opts containers.CreateOptsBuilder) error {
+	mc := metrics.NewMetricPrometheusContext("container", "create")
+	_, err := containers.Create(c.serviceClient, name, opts).Extract()
+	return mc.ObserveRequest(err)
+}
+
+func (c objectStorageClient) GetContainer(name string) (*containers.GetHeader, error) {
+	mc := metrics.NewMetricPrometheusContext("container", "get")
+	header, err := containers.Get(c.serviceClient, name, nil).Extract()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+func (c objectStorageClient) UpdateContainer(name string, opts containers.UpdateOptsBuilder) error {
+	mc := metrics.NewMetricPrometheusContext("container", "update")
+	_, err := containers.Update(c.serviceClient, name, opts).Extract()
+	return mc.ObserveRequest(err)
+}
+
+func (c objectStorageClient) DeleteContainer(name string) error {
+	mc := metrics.NewMetricPrometheusContext("container", "delete")
+	_, err := containers.Delete(c.serviceClient, name).Extract()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (c objectStorageClient) ListObjects(containerName string, opts objects.ListOptsBuilder) ([]objects.Object, error) {
+	mc := metrics.NewMetricPrometheusContext("object", "list")
+	allPages, err := objects.List(c.serviceClient, containerName, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return objects.ExtractInfo(allPages)
+}
+
+func (c objectStorageClient) CreateObject(containerName, name string, opts objects.CreateOptsBuilder) error {
+	mc := metrics.NewMetricPrometheusContext("object", "create")
+	_, err := objects.Create(c.serviceClient, containerName, name, opts).Extract()
+	return mc.ObserveRequest(err)
+}
+
+func (c objectStorageClient) DeleteObject(containerName, name string) error {
+	mc := metrics.NewMetricPrometheusContext("object", "delete")
+	_, err := objects.Delete(c.serviceClient, containerName, name, nil).Extract()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (c objectStorageClient) CreateTempURL(containerName, name string, opts objects.CreateTempURLOpts) (string, error) {
+	// Creating a temporary URL reads the temporary URL key of the container
+	mc := metrics.NewMetricPrometheusContext("container", "get")
+	url, err := objects.CreateTempURL(c.serviceClient, containerName, name, opts)
+	if mc.ObserveRequest(err) != nil {
+		return "", err
+	}
+	return url, nil
+}
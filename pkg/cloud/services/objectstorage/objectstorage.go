@@ -0,0 +1,171 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package objectstorage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const (
+	containerPrefix string = "k8s-clusterapi"
+
+	// tempURLKeyLength is the number of random bytes of the temporary URL
+	// key of a container.
+	tempURLKeyLength = 32
+)
+
+// ReconcileContainer ensures that the container of the cluster exists and
+// has a temporary URL key, and sets it in the status of the cluster.
+func (s *Service) ReconcileContainer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	name := GetContainerName(clusterName)
+
+	header, err := s.client.GetContainer(name)
+	if err != nil && !capoerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get container %s: %v", name, err)
+	}
+
+	switch {
+	case err != nil:
+		tempURLKey, err := generateTempURLKey()
+		if err != nil {
+			return err
+		}
+		if err := s.client.CreateContainer(name, containers.CreateOpts{TempURLKey: tempURLKey}); err != nil {
+			record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.Container, Name: name, RequestID: s.scope.LastRequestID()}, err)
+			return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+		}
+		record.Succeeded(openStackCluster, record.Create, record.Resource{Kind: record.Container, Name: name, RequestID: s.scope.LastRequestID()})
+	case header.TempURLKey == "":
+		// Without a key of the container, temporary URLs would be signed
+		// with the key of the account, if any
+		tempURLKey, err := generateTempURLKey()
+		if err != nil {
+			return err
+		}
+		if err := s.client.UpdateContainer(name, containers.UpdateOpts{TempURLKey: tempURLKey}); err != nil {
+			record.Failed(openStackCluster, record.Update, record.Resource{Kind: record.Container, Name: name, RequestID: s.scope.LastRequestID()}, err)
+			return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+		}
+		record.Succeeded(openStackCluster, record.Update, record.Resource{Kind: record.Container, Name: name, RequestID: s.scope.LastRequestID(), Detail: "temporary URL key"})
+	}
+
+	openStackCluster.Status.ObjectStorage = &infrav1.ObjectStorageStatus{ContainerName: name}
+	return nil
+}
+
+// DeleteContainer deletes all objects of the container of the cluster and
+// the container itself.
+func (s *Service) DeleteContainer(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	name := GetContainerName(clusterName)
+
+	objectList, err := s.client.ListObjects(name, objects.ListOpts{Full: true})
+	if err != nil {
+		if capoerrors.IsNotFound(err) {
+			openStackCluster.Status.ObjectStorage = nil
+			return nil
+		}
+		return fmt.Errorf("failed to list objects of container %s: %v", name, err)
+	}
+
+	containerEvent := record.Resource{Kind: record.Container, Name: name}
+	for _, object := range objectList {
+		if err := s.client.DeleteObject(name, object.Name); err != nil {
+			containerEvent.RequestID = s.scope.LastRequestID()
+			record.Failed(openStackCluster, record.Delete, containerEvent, fmt.Errorf("failed to delete object %s: %w", object.Name, err))
+			return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+		}
+	}
+
+	s.scope.Logger.Info("Deleting container", "name", name, "objects", len(objectList))
+	if err := s.client.DeleteContainer(name); err != nil {
+		containerEvent.RequestID = s.scope.LastRequestID()
+		record.Failed(openStackCluster, record.Delete, containerEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	containerEvent.RequestID = s.scope.LastRequestID()
+	record.Succeeded(openStackCluster, record.Delete, containerEvent)
+
+	openStackCluster.Status.ObjectStorage = nil
+	return nil
+}
+
+// StoreObject creates or replaces the object of the container with content.
+func (s *Service) StoreObject(eventObject runtime.Object, containerName, objectName, contentType string, content []byte) error {
+	opts := objects.CreateOpts{
+		Content:     bytes.NewReader(content),
+		ContentType: contentType,
+	}
+	objectEvent := record.Resource{Kind: record.Object, Name: objectName}
+	if err := s.client.CreateObject(containerName, objectName, opts); err != nil {
+		objectEvent.RequestID = s.scope.LastRequestID()
+		record.Failed(eventObject, record.Create, objectEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	objectEvent.RequestID = s.scope.LastRequestID()
+	record.Succeeded(eventObject, record.Create, objectEvent)
+	return nil
+}
+
+// DeleteObject deletes the object of the container, if it exists.
+func (s *Service) DeleteObject(eventObject runtime.Object, containerName, objectName string) error {
+	objectEvent := record.Resource{Kind: record.Object, Name: objectName}
+	if err := s.client.DeleteObject(containerName, objectName); err != nil {
+		objectEvent.RequestID = s.scope.LastRequestID()
+		record.Failed(eventObject, record.Delete, objectEvent, err)
+		return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	objectEvent.RequestID = s.scope.LastRequestID()
+	record.Succeeded(eventObject, record.Delete, objectEvent)
+	return nil
+}
+
+// GetTempURL returns a temporary URL which allows to download the object of
+// the container without credentials until ttl has passed.
+func (s *Service) GetTempURL(containerName, objectName string, ttl time.Duration) (string, error) {
+	url, err := s.client.CreateTempURL(containerName, objectName, objects.CreateTempURLOpts{
+		Method: objects.GET,
+		TTL:    int(ttl.Seconds()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary URL of object %s: %v", objectName, err)
+	}
+	return url, nil
+}
+
+// GetContainerName returns the name of the container of the cluster.
+func GetContainerName(clusterName string) string {
+	return fmt.Sprintf("%s-cluster-%s", containerPrefix, clusterName)
+}
+
+func generateTempURLKey() (string, error) {
+	key := make([]byte, tempURLKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate temporary URL key: %v", err)
+	}
+	return hex.EncodeToString(key), nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package objectstorage
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/objectstorage/mock_objectstorage"
+)
+
+func Test_ReconcileContainer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const name = "k8s-clusterapi-cluster-test-cluster"
+
+	tests := []struct {
+		name    string
+		expect  func(m *mock_objectstorage.MockObjectStorageClientMockRecorder)
+		wantErr bool
+	}{
+		{
+			name: "creates container with temporary URL key",
+			expect: func(m *mock_objectstorage.MockObjectStorageClientMockRecorder) {
+				m.GetContainer(name).Return(nil, gophercloud.ErrDefault404{})
+				m.CreateContainer(name, gomock.Any()).DoAndReturn(func(_ string, opts containers.CreateOptsBuilder) error {
+					if len(opts.(containers.CreateOpts).TempURLKey) != 2*tempURLKeyLength {
+						t.Errorf("unexpected temporary URL key %q", opts.(containers.CreateOpts).TempURLKey)
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name: "sets missing temporary URL key of existing container",
+			expect: func(m *mock_objectstorage.MockObjectStorageClientMockRecorder) {
+				m.GetContainer(name).Return(&containers.GetHeader{}, nil)
+				m.UpdateContainer(name, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "keeps existing container",
+			expect: func(m *mock_objectstorage.MockObjectStorageClientMockRecorder) {
+				m.GetContainer(name).Return(&containers.GetHeader{TempURLKey: "key"}, nil)
+			},
+		},
+		{
+			name: "fails to get container",
+			expect: func(m *mock_objectstorage.MockObjectStorageClientMockRecorder) {
+				m.GetContainer(name).Return(nil, gophercloud.ErrDefault500{})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_objectstorage.NewMockObjectStorageClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService(mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{ObjectStorage: &infrav1.ObjectStorage{}},
+			}
+			err := s.ReconcileContainer(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(openStackCluster.Status.ObjectStorage).To(BeNil())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(openStackCluster.Status.ObjectStorage).To(Equal(&infrav1.ObjectStorageStatus{ContainerName: name}))
+			}
+		})
+	}
+}
+
+func Test_DeleteContainer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const name = "k8s-clusterapi-cluster-test-cluster"
+
+	tests := []struct {
+		name   string
+		expect func(m *mock_objectstorage.MockObjectStorageClientMockRecorder)
+	}{
+		{
+			name: "deletes objects and container",
+			expect: func(m *mock_objectstorage.MockObjectStorageClientMockRecorder) {
+				m.ListObjects(name, objects.ListOpts{Full: true}).Return([]objects.Object{{Name: "bootstrap/machine-0"}, {Name: "console/machine-1-1700000000.log"}}, nil)
+				m.DeleteObject(name, "bootstrap/machine-0").Return(nil)
+				m.DeleteObject(name, "console/machine-1-1700000000.log").Return(nil)
+				m.DeleteContainer(name).Return(nil)
+			},
+		},
+		{
+			name: "container does not exist",
+			expect: func(m *mock_objectstorage.MockObjectStorageClientMockRecorder) {
+				m.ListObjects(name, objects.ListOpts{Full: true}).Return(nil, gophercloud.ErrDefault404{})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_objectstorage.NewMockObjectStorageClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService(mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Status: infrav1.OpenStackClusterStatus{ObjectStorage: &infrav1.ObjectStorageStatus{ContainerName: name}},
+			}
+			g.Expect(s.DeleteContainer(openStackCluster, "test-cluster")).To(Succeed())
+			g.Expect(openStackCluster.Status.ObjectStorage).To(BeNil())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package objectstorage
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// Service interfaces with the OpenStack Object Storage (Swift) API.
+type Service struct {
+	scope  *scope.Scope
+	client ObjectStorageClient
+}
+
+// NewService returns an instance of the object storage service.
+func NewService(scope *scope.Scope) (*Service, error) {
+	serviceClient, err := openstack.NewObjectStorageV1(scope.ProviderClient, gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object storage service client: %v", err)
+	}
+
+	return &Service{
+		scope:  scope,
+		client: objectStorageClient{serviceClient},
+	}, nil
+}
+
+// NewTestService returns a Service with no initialisation. It should only be used by tests.
+func NewTestService(client ObjectStorageClient, logger logr.Logger) *Service {
+	return &Service{
+		scope: &scope.Scope{
+			Logger: logger,
+		},
+		client: client,
+	}
+}
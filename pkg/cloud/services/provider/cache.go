@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+)
+
+const (
+	// tokenRefreshMargin is how long before token expiry a cached provider
+	// client is considered stale and is re-authenticated.
+	tokenRefreshMargin = 5 * time.Minute
+
+	// defaultCacheTTL is used when the token expiry cannot be determined
+	// from the authentication result.
+	defaultCacheTTL = 10 * time.Minute
+)
+
+// cachedClient is an authenticated provider client along with the data
+// returned by NewClient.
+type cachedClient struct {
+	provider   *gophercloud.ProviderClient
+	clientOpts *clientconfig.ClientOpts
+	projectID  string
+	expiresAt  time.Time
+}
+
+// clientCache holds authenticated provider clients keyed by the content of
+// the cloud configuration they were created from. This allows reconciles of
+// objects sharing the same credentials to reuse a Keystone token and service
+// catalog instead of re-authenticating every time.
+type clientCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedClient
+	now     func() time.Time
+}
+
+func newClientCache() *clientCache {
+	return &clientCache{
+		entries: make(map[string]*cachedClient),
+		now:     time.Now,
+	}
+}
+
+var defaultClientCache = newClientCache()
+
+// get returns the cached client for key if it is not about to expire.
+func (c *clientCache) get(key string) (*cachedClient, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.stale(entry) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+// set caches entry for key. Stale entries of other keys are removed, so that
+// the clients of rotated credentials or deleted clusters, which are never
+// looked up again, do not stay in the cache forever.
+func (c *clientCache) set(key string, entry *cachedClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, e := range c.entries {
+		if c.stale(e) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+}
+
+// stale returns true if the token of the entry is about to expire. The caller
+// must hold the lock.
+func (c *clientCache) stale(entry *cachedClient) bool {
+	return !c.now().Add(tokenRefreshMargin).Before(entry.expiresAt)
+}
+
+// expiresAt returns the time at which the token used by the provider client
+// expires. If it cannot be determined, defaultCacheTTL from now is returned.
+func (c *clientCache) expiresAt(authResult gophercloud.AuthResult) time.Time {
+	if createResult, ok := authResult.(tokens.CreateResult); ok {
+		if token, err := createResult.ExtractToken(); err == nil && !token.ExpiresAt.IsZero() {
+			return token.ExpiresAt
+		}
+	}
+	return c.now().Add(defaultCacheTTL)
+}
+
+// cacheKey computes a key identifying the credentials, region, CA
+// certificate and endpoint overrides used to create a provider client.
+func cacheKey(cloud clientconfig.Cloud, caCert []byte, endpointOverrides map[string]string) (string, error) {
+	data, err := json.Marshal(cloud)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cloud for cache key: %v", err)
+	}
+	overrides, err := json.Marshal(endpointOverrides)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal endpoint overrides for cache key: %v", err)
+	}
+	hasher := sha256.New()
+	hasher.Write(data)
+	hasher.Write(caCert)
+	hasher.Write(overrides)
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
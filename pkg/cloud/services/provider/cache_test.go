@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	. "github.com/onsi/gomega"
+)
+
+func Test_clientCache_get(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantHit   bool
+	}{
+		{
+			name:      "Token valid for a long time",
+			expiresAt: now.Add(time.Hour),
+			wantHit:   true,
+		},
+		{
+			name:      "Token within refresh margin",
+			expiresAt: now.Add(tokenRefreshMargin - time.Second),
+			wantHit:   false,
+		},
+		{
+			name:      "Token expired",
+			expiresAt: now.Add(-time.Minute),
+			wantHit:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := newClientCache()
+			c.now = func() time.Time { return now }
+			c.set("key", &cachedClient{projectID: "project", expiresAt: tt.expiresAt})
+
+			entry, ok := c.get("key")
+			g.Expect(ok).To(Equal(tt.wantHit))
+			if tt.wantHit {
+				g.Expect(entry.projectID).To(Equal("project"))
+			} else {
+				g.Expect(c.entries).NotTo(HaveKey("key"))
+			}
+		})
+	}
+}
+
+func Test_clientCache_set(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newClientCache()
+	c.now = func() time.Time { return now }
+	c.set("stale", &cachedClient{expiresAt: now.Add(time.Hour)})
+	c.set("valid", &cachedClient{expiresAt: now.Add(2 * time.Hour)})
+
+	// The stale key is never looked up again, but is evicted when another
+	// client is cached
+	now = now.Add(time.Hour)
+	c.set("new", &cachedClient{expiresAt: now.Add(time.Hour)})
+	g.Expect(c.entries).NotTo(HaveKey("stale"))
+	g.Expect(c.entries).To(HaveKey("valid"))
+	g.Expect(c.entries).To(HaveKey("new"))
+}
+
+func Test_cacheKey(t *testing.T) {
+	g := NewWithT(t)
+
+	cloud := clientconfig.Cloud{
+		RegionName: "RegionOne",
+		AuthInfo: &clientconfig.AuthInfo{
+			AuthURL:  "https://keystone.example.com:5000/v3",
+			Username: "user",
+			Password: "password",
+		},
+	}
+
+	key, err := cacheKey(cloud, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	sameKey, err := cacheKey(cloud, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(sameKey).To(Equal(key))
+
+	withCACert, err := cacheKey(cloud, []byte("cacert"), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(withCACert).NotTo(Equal(key))
+
+	otherRegion := cloud
+	otherRegion.RegionName = "RegionTwo"
+	otherRegionKey, err := cacheKey(otherRegion, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(otherRegionKey).NotTo(Equal(key))
+
+	otherPassword := cloud
+	otherPassword.AuthInfo = &clientconfig.AuthInfo{
+		AuthURL:  "https://keystone.example.com:5000/v3",
+		Username: "user",
+		Password: "rotated",
+	}
+	otherPasswordKey, err := cacheKey(otherPassword, nil, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(otherPasswordKey).NotTo(Equal(key))
+
+	withOverrides, err := cacheKey(cloud, nil, map[string]string{"network": "https://neutron.internal:9696/"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(withOverrides).NotTo(Equal(key))
+}
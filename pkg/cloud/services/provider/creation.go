@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// creationResources are the last path segments of the requests which create
+// servers, volumes and ports.
+var creationResources = map[string]bool{
+	"servers": true,
+	"volumes": true,
+	"ports":   true,
+}
+
+// defaultCreationSchedulers is shared by all provider clients. It is nil if
+// creations are not limited beyond the rate limit of all requests.
+var defaultCreationSchedulers *creationSchedulers
+
+// SetCreationLimits limits the creation of servers, volumes and ports by all
+// provider clients created afterwards. Creations are limited separately for
+// every cloud and kind of resource: at most concurrency create requests are
+// sent at once, and they are spread over time to qps requests per second,
+// allowing bursts of up to burst requests. A concurrency or qps of 0 or less
+// disables the respective limit. It must be called before any provider client
+// is created.
+func SetCreationLimits(concurrency int, qps float32, burst int) {
+	if concurrency <= 0 && qps <= 0 {
+		defaultCreationSchedulers = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	defaultCreationSchedulers = &creationSchedulers{
+		concurrency: concurrency,
+		qps:         qps,
+		burst:       burst,
+		schedulers:  map[string]*creationScheduler{},
+	}
+}
+
+// creationSchedulers holds the creation scheduler of every cloud and kind of
+// resource.
+type creationSchedulers struct {
+	concurrency int
+	qps         float32
+	burst       int
+
+	mu         sync.Mutex
+	schedulers map[string]*creationScheduler
+}
+
+// get returns the creation scheduler of resource in the given cloud, creating
+// it on first use.
+func (s *creationSchedulers) get(cloud, resource string) *creationScheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := cloud + "/" + resource
+	scheduler, ok := s.schedulers[key]
+	if !ok {
+		scheduler = &creationScheduler{}
+		if s.concurrency > 0 {
+			scheduler.slots = make(chan struct{}, s.concurrency)
+		}
+		if s.qps > 0 {
+			scheduler.limiter = flowcontrol.NewTokenBucketRateLimiter(s.qps, s.burst)
+		}
+		s.schedulers[key] = scheduler
+	}
+	return scheduler
+}
+
+// creationScheduler limits the create requests of one kind of resource in a
+// cloud.
+type creationScheduler struct {
+	// slots has a buffered element for every create request in flight. It is
+	// nil if the number of concurrent create requests is not limited.
+	slots chan struct{}
+	// limiter spreads create requests over time. It is nil if create requests
+	// are not rate limited.
+	limiter flowcontrol.RateLimiter
+}
+
+// acquire waits until a create request may be sent. The returned function
+// must be called once the request has completed.
+func (s *creationScheduler) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+			release = func() { <-s.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// creationRoundTripper schedules the create requests of servers, volumes and
+// ports sent to a cloud. All other requests are sent immediately.
+type creationRoundTripper struct {
+	rt         http.RoundTripper
+	cloud      string
+	schedulers *creationSchedulers
+}
+
+func (r *creationRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource := path.Base(req.URL.Path)
+	if req.Method != http.MethodPost || !creationResources[resource] {
+		return r.rt.RoundTrip(req)
+	}
+
+	release, err := r.schedulers.get(r.cloud, resource).acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return r.rt.RoundTrip(req)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// blockingRoundTripper holds requests until they are released.
+type blockingRoundTripper struct {
+	sent    chan *http.Request
+	release chan struct{}
+}
+
+func (b *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.sent <- req
+	<-b.release
+	return &http.Response{StatusCode: http.StatusAccepted}, nil
+}
+
+func Test_creationRoundTripper(t *testing.T) {
+	g := NewWithT(t)
+
+	rt := &blockingRoundTripper{
+		sent:    make(chan *http.Request, 10),
+		release: make(chan struct{}),
+	}
+	scheduled := &creationRoundTripper{
+		rt:    rt,
+		cloud: "https://identity.example.com/v3/RegionOne",
+		schedulers: &creationSchedulers{
+			concurrency: 1,
+			schedulers:  map[string]*creationScheduler{},
+		},
+	}
+
+	newRequest := func(ctx context.Context, method, url string) *http.Request {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		return req
+	}
+	roundTrip := func(req *http.Request) chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := scheduled.RoundTrip(req)
+			done <- err
+		}()
+		return done
+	}
+
+	// The first server creation takes the only slot
+	first := roundTrip(newRequest(context.Background(), http.MethodPost, "https://compute.example.com/v2.1/servers"))
+	g.Eventually(rt.sent).Should(Receive())
+
+	// A second server creation waits, and gives up when its context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	second := roundTrip(newRequest(ctx, http.MethodPost, "https://compute.example.com/v2.1/servers"))
+	g.Consistently(rt.sent).ShouldNot(Receive())
+	cancel()
+	g.Eventually(second).Should(Receive(HaveOccurred()))
+
+	// Other requests and creations of other resources are not held back
+	other := roundTrip(newRequest(context.Background(), http.MethodGet, "https://compute.example.com/v2.1/servers"))
+	g.Eventually(rt.sent).Should(Receive())
+	action := roundTrip(newRequest(context.Background(), http.MethodPost, "https://compute.example.com/v2.1/servers/1234/action"))
+	g.Eventually(rt.sent).Should(Receive())
+	port := roundTrip(newRequest(context.Background(), http.MethodPost, "https://network.example.com/v2.0/ports"))
+	g.Eventually(rt.sent).Should(Receive())
+
+	// The next server creation is sent once the first one completed
+	third := roundTrip(newRequest(context.Background(), http.MethodPost, "https://compute.example.com/v2.1/servers"))
+	g.Consistently(rt.sent).ShouldNot(Receive())
+	close(rt.release)
+	g.Eventually(rt.sent).Should(Receive())
+
+	for _, done := range []chan error{first, other, action, port, third} {
+		g.Eventually(done).Should(Receive(BeNil()))
+	}
+}
+
+func Test_creationSchedulers(t *testing.T) {
+	g := NewWithT(t)
+
+	schedulers := &creationSchedulers{
+		concurrency: 2,
+		qps:         1,
+		burst:       3,
+		schedulers:  map[string]*creationScheduler{},
+	}
+
+	servers := schedulers.get("cloud-a", "servers")
+	g.Expect(cap(servers.slots)).To(Equal(2))
+	g.Expect(servers.limiter.QPS()).To(BeNumerically("==", 1))
+	g.Expect(schedulers.get("cloud-a", "servers")).To(BeIdenticalTo(servers))
+	g.Expect(schedulers.get("cloud-a", "volumes")).NotTo(BeIdenticalTo(servers))
+	g.Expect(schedulers.get("cloud-b", "servers")).NotTo(BeIdenticalTo(servers))
+}
+
+func TestSetCreationLimits(t *testing.T) {
+	g := NewWithT(t)
+	defer SetCreationLimits(0, 0, 0)
+
+	SetCreationLimits(5, 0, 0)
+	g.Expect(defaultCreationSchedulers).NotTo(BeNil())
+	scheduler := defaultCreationSchedulers.get("cloud", "servers")
+	g.Expect(cap(scheduler.slots)).To(Equal(5))
+	g.Expect(scheduler.limiter).To(BeNil())
+
+	SetCreationLimits(0, 2, 0)
+	g.Expect(defaultCreationSchedulers).NotTo(BeNil())
+	scheduler = defaultCreationSchedulers.get("cloud", "servers")
+	g.Expect(scheduler.slots).To(BeNil())
+	g.Expect(scheduler.limiter.QPS()).To(BeNumerically("==", 2))
+
+	SetCreationLimits(0, 0, 5)
+	g.Expect(defaultCreationSchedulers).To(BeNil())
+}
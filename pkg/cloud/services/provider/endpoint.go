@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"sigs.k8s.io/yaml"
+)
+
+const endpointOverrideSuffix = "_endpoint_override"
+
+// getEndpointOverrides returns the service endpoint overrides of the given
+// cloud in clouds.yaml, keyed by service type. Overrides are specified the same
+// way as for openstacksdk, using `<service type>_endpoint_override` keys with
+// dashes in the service type replaced by underscores, e.g.
+// `network_endpoint_override` or `load_balancer_endpoint_override`.
+func getEndpointOverrides(content []byte, cloudName string) (map[string]string, error) {
+	var clouds struct {
+		Clouds map[string]map[string]interface{} `json:"clouds"`
+	}
+	if err := yaml.Unmarshal(content, &clouds); err != nil {
+		return nil, err
+	}
+
+	var overrides map[string]string
+	for key, value := range clouds.Clouds[cloudName] {
+		if !strings.HasSuffix(key, endpointOverrideSuffix) {
+			continue
+		}
+		endpoint, ok := value.(string)
+		if !ok || endpoint == "" {
+			return nil, fmt.Errorf("endpoint override %s of cloud %s must be a non-empty string", key, cloudName)
+		}
+		if overrides == nil {
+			overrides = make(map[string]string)
+		}
+		serviceType := strings.ReplaceAll(strings.TrimSuffix(key, endpointOverrideSuffix), "_", "-")
+		overrides[serviceType] = gophercloud.NormalizeURL(endpoint)
+	}
+	return overrides, nil
+}
+
+// setEndpointOverrides makes the provider client use the given endpoints
+// instead of the ones from the service catalog for the overridden service
+// types.
+func setEndpointOverrides(provider *gophercloud.ProviderClient, overrides map[string]string) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	locator := provider.EndpointLocator
+	provider.EndpointLocator = func(opts gophercloud.EndpointOpts) (string, error) {
+		if endpoint, ok := overrides[opts.Type]; ok {
+			return endpoint, nil
+		}
+		return locator(opts)
+	}
+}
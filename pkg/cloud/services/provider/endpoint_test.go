@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+const cloudsYAML = `
+clouds:
+  openstack:
+    auth:
+      auth_url: https://keystone.example.com:5000/v3
+    region_name: RegionOne
+    network_endpoint_override: https://neutron.internal:9696
+    load_balancer_endpoint_override: https://octavia.internal:9876/
+  other:
+    auth:
+      auth_url: https://keystone.example.com:5000/v3
+`
+
+func Test_getEndpointOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		cloudName string
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "Cloud with overrides",
+			content:   cloudsYAML,
+			cloudName: "openstack",
+			want: map[string]string{
+				"network":       "https://neutron.internal:9696/",
+				"load-balancer": "https://octavia.internal:9876/",
+			},
+		},
+		{
+			name:      "Cloud without overrides",
+			content:   cloudsYAML,
+			cloudName: "other",
+			want:      nil,
+		},
+		{
+			name: "Override is not a string",
+			content: `
+clouds:
+  openstack:
+    compute_endpoint_override: 1
+`,
+			cloudName: "openstack",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := getEndpointOverrides([]byte(tt.content), tt.cloudName)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_setEndpointOverrides(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := &gophercloud.ProviderClient{
+		EndpointLocator: func(opts gophercloud.EndpointOpts) (string, error) {
+			return "https://" + opts.Type + ".public/", nil
+		},
+	}
+	setEndpointOverrides(provider, map[string]string{"network": "https://neutron.internal:9696/"})
+
+	endpoint, err := provider.EndpointLocator(gophercloud.EndpointOpts{Type: "network"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(endpoint).To(Equal("https://neutron.internal:9696/"))
+
+	endpoint, err = provider.EndpointLocator(gophercloud.EndpointOpts{Type: "compute"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(endpoint).To(Equal("https://compute.public/"))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CloudChecker is a readiness check which verifies that the cloud of at least
+// one of the given identity secrets is reachable and accepts its credentials.
+// The result is cached for the given interval, so that probes do not
+// authenticate against the clouds more often than that.
+type CloudChecker struct {
+	client     client.Client
+	identities []types.NamespacedName
+	cloudName  string
+	interval   time.Duration
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+
+	now          func() time.Time
+	authenticate func(cloud clientconfig.Cloud, caCert []byte, endpointOverrides map[string]string) error
+}
+
+// NewCloudChecker returns a CloudChecker for the clouds named cloudName in the
+// given identity secrets, each given as <namespace>/<name>.
+func NewCloudChecker(ctrlClient client.Client, identities []string, cloudName string, interval time.Duration) (*CloudChecker, error) {
+	c := &CloudChecker{
+		client:    ctrlClient,
+		cloudName: cloudName,
+		interval:  interval,
+		now:       time.Now,
+		authenticate: func(cloud clientconfig.Cloud, caCert []byte, endpointOverrides map[string]string) error {
+			_, _, _, err := NewClient(cloud, caCert, endpointOverrides)
+			return err
+		},
+	}
+	for _, identity := range identities {
+		parts := strings.Split(identity, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid identity secret %q: must be <namespace>/<name>", identity)
+		}
+		c.identities = append(c.identities, types.NamespacedName{Namespace: parts[0], Name: parts[1]})
+	}
+	return c, nil
+}
+
+// Check implements healthz.Checker.
+func (c *CloudChecker) Check(req *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkedAt.IsZero() && c.now().Sub(c.checkedAt) < c.interval {
+		return c.err
+	}
+	c.err = c.check(req.Context())
+	c.checkedAt = c.now()
+	return c.err
+}
+
+func (c *CloudChecker) check(ctx context.Context) error {
+	var errs []error
+	for _, identity := range c.identities {
+		cloud, caCert, endpointOverrides, err := getCloudFromSecret(ctx, c.client, identity.Namespace, identity.Name, c.cloudName)
+		if err == nil {
+			err = c.authenticate(cloud, caCert, endpointOverrides)
+		}
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("cloud %q of identity %s: %v", c.cloudName, identity, err))
+	}
+	return kerrors.NewAggregate(errs)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewCloudChecker(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewCloudChecker(nil, []string{"capo-system/cloud-config"}, "openstack", time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, identity := range []string{"cloud-config", "/cloud-config", "capo-system/", "a/b/c"} {
+		_, err := NewCloudChecker(nil, []string{identity}, "openstack", time.Minute)
+		g.Expect(err).To(HaveOccurred(), identity)
+	}
+}
+
+func TestCloudChecker_Check(t *testing.T) {
+	secret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "capo-system", Name: name},
+			Data: map[string][]byte{
+				cloudsSecretKey: []byte("clouds:\n  openstack:\n    auth:\n      auth_url: https://" + name + ".example.com:5000/v3\n"),
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		identities []string
+		failing    []string
+		wantErr    bool
+	}{
+		{
+			name:       "Credentials are valid",
+			identities: []string{"capo-system/good"},
+		},
+		{
+			name:       "Credentials are invalid",
+			identities: []string{"capo-system/bad"},
+			failing:    []string{"https://bad.example.com:5000/v3"},
+			wantErr:    true,
+		},
+		{
+			name:       "Secret does not exist",
+			identities: []string{"capo-system/missing"},
+			wantErr:    true,
+		},
+		{
+			name:       "One of several clouds is reachable",
+			identities: []string{"capo-system/missing", "capo-system/bad", "capo-system/good"},
+			failing:    []string{"https://bad.example.com:5000/v3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctrlClient := fake.NewClientBuilder().WithObjects(secret("good"), secret("bad")).Build()
+			c, err := NewCloudChecker(ctrlClient, tt.identities, "openstack", time.Minute)
+			g.Expect(err).NotTo(HaveOccurred())
+			c.authenticate = func(cloud clientconfig.Cloud, _ []byte, _ map[string]string) error {
+				for _, url := range tt.failing {
+					if cloud.AuthInfo.AuthURL == url {
+						return errors.New("authentication failed")
+					}
+				}
+				return nil
+			}
+
+			err = c.Check(httptest.NewRequest("GET", "/readyz", nil))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestCloudChecker_Check_cached(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctrlClient := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "capo-system", Name: "cloud-config"},
+		Data: map[string][]byte{
+			cloudsSecretKey: []byte("clouds:\n  openstack:\n    auth:\n      auth_url: https://keystone.example.com:5000/v3\n"),
+		},
+	}).Build()
+	c, err := NewCloudChecker(ctrlClient, []string{"capo-system/cloud-config"}, "openstack", time.Minute)
+	g.Expect(err).NotTo(HaveOccurred())
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	authErr := errors.New("authentication failed")
+	c.authenticate = func(clientconfig.Cloud, []byte, map[string]string) error {
+		calls++
+		return authErr
+	}
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	g.Expect(c.Check(req)).To(HaveOccurred())
+	g.Expect(calls).To(Equal(1))
+
+	// Within the interval the cached result is returned
+	authErr = nil
+	now = now.Add(30 * time.Second)
+	g.Expect(c.Check(req)).To(HaveOccurred())
+	g.Expect(calls).To(Equal(1))
+
+	// After the interval the cloud is checked again
+	now = now.Add(time.Minute)
+	g.Expect(c.Check(req)).To(Succeed())
+	g.Expect(calls).To(Equal(2))
+}
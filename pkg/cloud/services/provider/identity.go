@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// identityNamespaces are the namespaces whose identity Secrets may be
+// referenced from other namespaces.
+var identityNamespaces = map[string]bool{}
+
+// SetIdentityNamespaces sets the namespaces whose identity Secrets may be
+// referenced by the identityRefs of resources in other namespaces, e.g. a
+// central namespace holding the credentials of a fleet of clusters. The
+// Secrets must also allow the namespaces of the resources with the
+// allowed-namespaces annotation. No Secrets may be referenced from other
+// namespaces if namespaces is empty.
+func SetIdentityNamespaces(namespaces []string) {
+	identityNamespaces = map[string]bool{}
+	for _, namespace := range namespaces {
+		identityNamespaces[namespace] = true
+	}
+}
+
+// IdentitySecretKey returns the key of the Secret referenced by identityRef
+// for a resource in namespace.
+func IdentitySecretKey(namespace string, identityRef *infrav1.OpenStackIdentityReference) types.NamespacedName {
+	if identityRef.Namespace != "" {
+		namespace = identityRef.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: identityRef.Name}
+}
+
+// getCloudFromIdentity returns the cloud of the Secret referenced by
+// identityRef for a resource in namespace. A Secret in another namespace is
+// only used if its namespace is an identity namespace and the Secret allows
+// namespace.
+func getCloudFromIdentity(ctx context.Context, ctrlClient client.Client, namespace string, identityRef *infrav1.OpenStackIdentityReference, cloudName string) (clientconfig.Cloud, []byte, map[string]string, error) {
+	key := IdentitySecretKey(namespace, identityRef)
+	if key.Namespace == namespace {
+		return getCloudFromSecret(ctx, ctrlClient, key.Namespace, key.Name, cloudName)
+	}
+
+	if !identityNamespaces[key.Namespace] {
+		return clientconfig.Cloud{}, nil, nil, fmt.Errorf("identity secret %s cannot be used from namespace %s: namespace %s is not an identity namespace of the controller", key, namespace, key.Namespace)
+	}
+	secret := &corev1.Secret{}
+	if err := ctrlClient.Get(ctx, key, secret); err != nil {
+		return clientconfig.Cloud{}, nil, nil, err
+	}
+	if !secretAllowsNamespace(secret, namespace) {
+		return clientconfig.Cloud{}, nil, nil, fmt.Errorf("identity secret %s cannot be used from namespace %s: namespace is not allowed by annotation %s", key, namespace, infrav1.IdentityAllowedNamespacesAnnotation)
+	}
+	return getCloudFromSecret(ctx, ctrlClient, key.Namespace, key.Name, cloudName)
+}
+
+// secretAllowsNamespace returns true if the allowed-namespaces annotation of
+// the Secret lists namespace, or is "*".
+func secretAllowsNamespace(secret *corev1.Secret, namespace string) bool {
+	for _, allowed := range strings.Split(secret.Annotations[infrav1.IdentityAllowedNamespacesAnnotation], ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || (allowed != "" && allowed == namespace) {
+			return true
+		}
+	}
+	return false
+}
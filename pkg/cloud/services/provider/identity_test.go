@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func Test_getCloudFromIdentity(t *testing.T) {
+	secret := func(namespace, allowedNamespaces string) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cloud-config"},
+			Data: map[string][]byte{
+				cloudsSecretKey: []byte("clouds:\n  openstack:\n    auth:\n      auth_url: https://" + namespace + ".example.com:5000/v3\n"),
+			},
+		}
+		if allowedNamespaces != "" {
+			s.Annotations = map[string]string{infrav1.IdentityAllowedNamespacesAnnotation: allowedNamespaces}
+		}
+		return s
+	}
+
+	tests := []struct {
+		name               string
+		secret             *corev1.Secret
+		identityNamespaces []string
+		identityNamespace  string
+		wantAuthURL        string
+		wantErr            bool
+	}{
+		{
+			name:        "Secret in the namespace of the resource",
+			secret:      secret("cluster-ns", ""),
+			wantAuthURL: "https://cluster-ns.example.com:5000/v3",
+		},
+		{
+			name:              "Secret in the namespace of the resource referenced explicitly",
+			secret:            secret("cluster-ns", ""),
+			identityNamespace: "cluster-ns",
+			wantAuthURL:       "https://cluster-ns.example.com:5000/v3",
+		},
+		{
+			name:               "Secret in an identity namespace allowing the namespace of the resource",
+			secret:             secret("identities", "other-ns, cluster-ns"),
+			identityNamespaces: []string{"identities"},
+			identityNamespace:  "identities",
+			wantAuthURL:        "https://identities.example.com:5000/v3",
+		},
+		{
+			name:               "Secret in an identity namespace allowing all namespaces",
+			secret:             secret("identities", "*"),
+			identityNamespaces: []string{"identities"},
+			identityNamespace:  "identities",
+			wantAuthURL:        "https://identities.example.com:5000/v3",
+		},
+		{
+			name:               "Secret in an identity namespace not allowing the namespace of the resource",
+			secret:             secret("identities", "other-ns"),
+			identityNamespaces: []string{"identities"},
+			identityNamespace:  "identities",
+			wantErr:            true,
+		},
+		{
+			name:               "Secret in an identity namespace without annotation",
+			secret:             secret("identities", ""),
+			identityNamespaces: []string{"identities"},
+			identityNamespace:  "identities",
+			wantErr:            true,
+		},
+		{
+			name:              "Secret in a namespace which is not an identity namespace",
+			secret:            secret("identities", "*"),
+			identityNamespace: "identities",
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			SetIdentityNamespaces(tt.identityNamespaces)
+			defer SetIdentityNamespaces(nil)
+
+			ctrlClient := fake.NewClientBuilder().WithObjects(tt.secret).Build()
+			identityRef := &infrav1.OpenStackIdentityReference{Kind: "Secret", Name: "cloud-config", Namespace: tt.identityNamespace}
+
+			cloud, _, _, err := getCloudFromIdentity(context.TODO(), ctrlClient, "cluster-ns", identityRef, "openstack")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cloud.AuthInfo.AuthURL).To(Equal(tt.wantAuthURL))
+		})
+	}
+}
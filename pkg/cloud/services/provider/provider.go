@@ -18,10 +18,7 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
-	"net/http"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
@@ -34,7 +31,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/dryrun"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/httpdebug"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
 )
 
 const (
@@ -42,41 +42,177 @@ const (
 	caSecretKey     = "cacert"
 )
 
-func NewClientFromMachine(ctx context.Context, ctrlClient client.Client, openStackMachine *infrav1.OpenStackMachine) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+// NewClientFromMachine returns a provider client for the given machine. The
+// identityRef and cloudName of the machine are used if set, otherwise those of
+// the cluster are used. If the machine sets a region, it overrides the region
+// of the cloud.
+func NewClientFromMachine(ctx context.Context, ctrlClient client.Client, openStackCluster *infrav1.OpenStackCluster, openStackMachine *infrav1.OpenStackMachine) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	return newClientFromMachineSpec(ctx, ctrlClient, openStackCluster, openStackMachine.Namespace, &openStackMachine.Spec)
+}
+
+// NewClientFromMachineTemplate returns a provider client for the machines
+// created from the given template, following the same rules as
+// NewClientFromMachine.
+func NewClientFromMachineTemplate(ctx context.Context, ctrlClient client.Client, openStackCluster *infrav1.OpenStackCluster, openStackMachineTemplate *infrav1.OpenStackMachineTemplate) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	return newClientFromMachineSpec(ctx, ctrlClient, openStackCluster, openStackMachineTemplate.Namespace, &openStackMachineTemplate.Spec.Template.Spec)
+}
+
+func newClientFromMachineSpec(ctx context.Context, ctrlClient client.Client, openStackCluster *infrav1.OpenStackCluster, namespace string, machineSpec *infrav1.OpenStackMachineSpec) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
 	var cloud clientconfig.Cloud
 	var caCert []byte
+	var endpointOverrides map[string]string
 
-	if openStackMachine.Spec.IdentityRef != nil {
+	machineSpec = openStackCluster.Spec.MachineSpecWithDefaults(machineSpec)
+	if machineSpec.IdentityRef != nil {
+		var err error
+		cloud, caCert, endpointOverrides, err = getCloudFromIdentity(ctx, ctrlClient, namespace, machineSpec.IdentityRef, machineSpec.CloudName)
+		if err != nil {
+			return nil, nil, "", err
+		}
+	} else if openStackCluster.Spec.IdentityRef != nil {
 		var err error
-		cloud, caCert, err = getCloudFromSecret(ctx, ctrlClient, openStackMachine.Namespace, openStackMachine.Spec.IdentityRef.Name, openStackMachine.Spec.CloudName)
+		cloud, caCert, endpointOverrides, err = getCloudFromIdentity(ctx, ctrlClient, openStackCluster.Namespace, openStackCluster.Spec.IdentityRef, openStackCluster.Spec.CloudName)
 		if err != nil {
 			return nil, nil, "", err
 		}
 	}
-	return NewClient(cloud, caCert)
+	if machineSpec.Region != "" {
+		cloud.RegionName = machineSpec.Region
+	}
+	provider, clientOpts, projectID, err := NewCachedClient(cloud, caCert, endpointOverrides)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return withContext(ctx, provider), clientOpts, projectID, nil
+}
+
+// NewClientFromServer returns a provider client for the given server, using
+// its identityRef and cloudName. If the server sets a region, it overrides
+// the region of the cloud.
+func NewClientFromServer(ctx context.Context, ctrlClient client.Client, openStackServer *infrav1.OpenStackServer) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	return newClientFromIdentity(ctx, ctrlClient, openStackServer.Namespace, openStackServer.Spec.IdentityRef, openStackServer.Spec.CloudName, openStackServer.Spec.Region)
+}
+
+// NewClientFromFloatingIPPool returns a provider client for the given
+// floating IP pool, using its identityRef and cloudName.
+func NewClientFromFloatingIPPool(ctx context.Context, ctrlClient client.Client, openStackFloatingIPPool *infrav1.OpenStackFloatingIPPool) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	return newClientFromIdentity(ctx, ctrlClient, openStackFloatingIPPool.Namespace, openStackFloatingIPPool.Spec.IdentityRef, openStackFloatingIPPool.Spec.CloudName, "")
+}
+
+func newClientFromIdentity(ctx context.Context, ctrlClient client.Client, namespace string, identityRef *infrav1.OpenStackIdentityReference, cloudName, region string) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	var cloud clientconfig.Cloud
+	var caCert []byte
+	var endpointOverrides map[string]string
+
+	if identityRef != nil {
+		var err error
+		cloud, caCert, endpointOverrides, err = getCloudFromIdentity(ctx, ctrlClient, namespace, identityRef, cloudName)
+		if err != nil {
+			return nil, nil, "", err
+		}
+	}
+	if region != "" {
+		cloud.RegionName = region
+	}
+	provider, clientOpts, projectID, err := NewCachedClient(cloud, caCert, endpointOverrides)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return withContext(ctx, provider), clientOpts, projectID, nil
 }
 
 func NewClientFromCluster(ctx context.Context, ctrlClient client.Client, openStackCluster *infrav1.OpenStackCluster) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
 	var cloud clientconfig.Cloud
 	var caCert []byte
+	var endpointOverrides map[string]string
 
 	if openStackCluster.Spec.IdentityRef != nil {
 		var err error
-		cloud, caCert, err = getCloudFromSecret(ctx, ctrlClient, openStackCluster.Namespace, openStackCluster.Spec.IdentityRef.Name, openStackCluster.Spec.CloudName)
+		cloud, caCert, endpointOverrides, err = getCloudFromIdentity(ctx, ctrlClient, openStackCluster.Namespace, openStackCluster.Spec.IdentityRef, openStackCluster.Spec.CloudName)
 		if err != nil {
 			return nil, nil, "", err
 		}
 	}
-	return NewClient(cloud, caCert)
+	provider, clientOpts, projectID, err := NewCachedClient(cloud, caCert, endpointOverrides)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return withContext(ctx, provider), clientOpts, projectID, nil
+}
+
+// GetCloudFromCluster returns the cloud and CA certificate of the identity of
+// the cluster, e.g. to configure the cloud provider of the workload cluster.
+func GetCloudFromCluster(ctx context.Context, ctrlClient client.Client, openStackCluster *infrav1.OpenStackCluster) (clientconfig.Cloud, []byte, error) {
+	if openStackCluster.Spec.IdentityRef == nil {
+		return clientconfig.Cloud{}, nil, nil
+	}
+	cloud, caCert, _, err := getCloudFromIdentity(ctx, ctrlClient, openStackCluster.Namespace, openStackCluster.Spec.IdentityRef, openStackCluster.Spec.CloudName)
+	return cloud, caCert, err
+}
+
+// withContext returns a copy of the shared provider client which sends its
+// requests with the given context, so that they are cancelled and traced
+// together with the reconcile. The copy shares the token of the provider
+// client.
+func withContext(ctx context.Context, provider *gophercloud.ProviderClient) *gophercloud.ProviderClient {
+	p := *provider
+	p.Context = ctx
+	if provider.ReauthFunc != nil {
+		p.ReauthFunc = func() error {
+			if err := provider.ReauthFunc(); err != nil {
+				return err
+			}
+			p.CopyTokenFrom(provider)
+			return nil
+		}
+	}
+	return &p
+}
+
+// NewCachedClient returns an authenticated provider client for the given
+// cloud, reusing a previously authenticated client with the same credentials
+// as long as its token is not about to expire.
+func NewCachedClient(cloud clientconfig.Cloud, caCert []byte, endpointOverrides map[string]string) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	return defaultClientCache.newClient(cloud, caCert, endpointOverrides)
 }
 
-func NewClient(cloud clientconfig.Cloud, caCert []byte) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+func (c *clientCache) newClient(cloud clientconfig.Cloud, caCert []byte, endpointOverrides map[string]string) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
+	key, err := cacheKey(cloud, caCert, endpointOverrides)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if entry, ok := c.get(key); ok {
+		clientOpts := *entry.clientOpts
+		return entry.provider, &clientOpts, entry.projectID, nil
+	}
+
+	provider, clientOpts, projectID, err := NewClient(cloud, caCert, endpointOverrides)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	c.set(key, &cachedClient{
+		provider:   provider,
+		clientOpts: clientOpts,
+		projectID:  projectID,
+		expiresAt:  c.expiresAt(provider.GetAuthResult()),
+	})
+
+	cachedOpts := *clientOpts
+	return provider, &cachedOpts, projectID, nil
+}
+
+// NewClient returns a provider client authenticated against the given cloud.
+// Requests for the services in endpointOverrides are sent to the given
+// endpoints instead of the ones from the service catalog.
+func NewClient(cloud clientconfig.Cloud, caCert []byte, endpointOverrides map[string]string) (*gophercloud.ProviderClient, *clientconfig.ClientOpts, string, error) {
 	clientOpts := new(clientconfig.ClientOpts)
 	if cloud.AuthInfo != nil {
 		clientOpts.AuthInfo = cloud.AuthInfo
 		clientOpts.AuthType = cloud.AuthType
-		clientOpts.RegionName = cloud.RegionName
 	}
+	clientOpts.RegionName = cloud.RegionName
 
 	opts, err := clientconfig.AuthOptions(clientOpts)
 	if err != nil {
@@ -88,29 +224,45 @@ func NewClient(cloud clientconfig.Cloud, caCert []byte) (*gophercloud.ProviderCl
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("create providerClient err: %v", err)
 	}
+	// Provider clients are shared between concurrent reconciles
+	provider.UseTokenLock()
 
-	config := &tls.Config{
-		RootCAs:    x509.NewCertPool(),
-		MinVersion: tls.VersionTLS12,
+	insecureSkipVerify := cloud.Verify != nil && !*cloud.Verify
+	provider.HTTPClient.Transport = &tracing.RoundTripper{
+		Rt: defaultTransportCache.get(insecureSkipVerify, caCert),
 	}
-	if cloud.Verify != nil {
-		config.InsecureSkipVerify = !*cloud.Verify
+	// Each attempt of a retried request is logged for debugging
+	provider.HTTPClient.Transport = &httpdebug.RoundTripper{Rt: provider.HTTPClient.Transport}
+	if defaultRateLimiter != nil {
+		provider.HTTPClient.Transport = &rateLimitedRoundTripper{
+			rt:      provider.HTTPClient.Transport,
+			limiter: defaultRateLimiter,
+		}
+	}
+	// Creations are scheduled per cloud before they wait for the rate limit
+	if defaultCreationSchedulers != nil {
+		provider.HTTPClient.Transport = &creationRoundTripper{
+			rt:         provider.HTTPClient.Transport,
+			cloud:      opts.IdentityEndpoint + "/" + cloud.RegionName,
+			schedulers: defaultCreationSchedulers,
+		}
 	}
-	if caCert != nil {
-		config.RootCAs.AppendCertsFromPEM(caCert)
+	if defaultRetryPolicy != nil {
+		defaultRetryPolicy.apply(provider)
 	}
-
-	provider.HTTPClient.Transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
 	if klog.V(6).Enabled() {
 		provider.HTTPClient.Transport = &osclient.RoundTripper{
 			Rt:     provider.HTTPClient.Transport,
 			Logger: &defaultLogger{},
 		}
 	}
+	// Requests of dry-run reconciles are recorded before they are logged or sent
+	provider.HTTPClient.Transport = &dryrun.RoundTripper{Rt: provider.HTTPClient.Transport}
 	err = openstack.Authenticate(provider, *opts)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("providerClient authentication err: %v", err)
 	}
+	setEndpointOverrides(provider, endpointOverrides)
 
 	projectID, err := getProjectIDFromAuthResult(provider.GetAuthResult())
 	if err != nil {
@@ -127,16 +279,16 @@ func (defaultLogger) Printf(format string, args ...interface{}) {
 	klog.V(6).Infof(format, args...)
 }
 
-// getCloudFromSecret extract a Cloud from the given namespace:secretName.
-func getCloudFromSecret(ctx context.Context, ctrlClient client.Client, secretNamespace string, secretName string, cloudName string) (clientconfig.Cloud, []byte, error) {
+// getCloudFromSecret extract a Cloud and its service endpoint overrides from the given namespace:secretName.
+func getCloudFromSecret(ctx context.Context, ctrlClient client.Client, secretNamespace string, secretName string, cloudName string) (clientconfig.Cloud, []byte, map[string]string, error) {
 	emptyCloud := clientconfig.Cloud{}
 
 	if secretName == "" {
-		return emptyCloud, nil, nil
+		return emptyCloud, nil, nil, nil
 	}
 
 	if cloudName == "" {
-		return emptyCloud, nil, fmt.Errorf("secret name set to %v but no cloud was specified. Please set cloud_name in your machine spec", secretName)
+		return emptyCloud, nil, nil, fmt.Errorf("secret name set to %v but no cloud was specified. Please set cloud_name in your machine spec", secretName)
 	}
 
 	secret := &corev1.Secret{}
@@ -145,26 +297,31 @@ func getCloudFromSecret(ctx context.Context, ctrlClient client.Client, secretNam
 		Name:      secretName,
 	}, secret)
 	if err != nil {
-		return emptyCloud, nil, err
+		return emptyCloud, nil, nil, err
 	}
 
 	content, ok := secret.Data[cloudsSecretKey]
 	if !ok {
-		return emptyCloud, nil, fmt.Errorf("OpenStack credentials secret %v did not contain key %v",
+		return emptyCloud, nil, nil, fmt.Errorf("OpenStack credentials secret %v did not contain key %v",
 			secretName, cloudsSecretKey)
 	}
 	var clouds clientconfig.Clouds
 	if err = yaml.Unmarshal(content, &clouds); err != nil {
-		return emptyCloud, nil, fmt.Errorf("failed to unmarshal clouds credentials stored in secret %v: %v", secretName, err)
+		return emptyCloud, nil, nil, fmt.Errorf("failed to unmarshal clouds credentials stored in secret %v: %v", secretName, err)
+	}
+
+	endpointOverrides, err := getEndpointOverrides(content, cloudName)
+	if err != nil {
+		return emptyCloud, nil, nil, fmt.Errorf("failed to get endpoint overrides from secret %v: %v", secretName, err)
 	}
 
 	// get caCert
 	caCert, ok := secret.Data[caSecretKey]
 	if !ok {
-		return clouds.Clouds[cloudName], nil, nil
+		return clouds.Clouds[cloudName], nil, endpointOverrides, nil
 	}
 
-	return clouds.Clouds[cloudName], caCert, nil
+	return clouds.Clouds[cloudName], caCert, endpointOverrides, nil
 }
 
 // getProjectIDFromAuthResult handles different auth mechanisms to retrieve the
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// defaultRateLimiter is shared by all provider clients. It is nil if requests
+// to OpenStack are not rate limited.
+var defaultRateLimiter flowcontrol.RateLimiter
+
+// SetRateLimit limits the requests sent to OpenStack by all provider clients
+// created afterwards to qps requests per second, allowing bursts of up to
+// burst requests. A qps of 0 or less disables rate limiting. It must be called
+// before any provider client is created.
+func SetRateLimit(qps float32, burst int) {
+	if qps <= 0 {
+		defaultRateLimiter = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	defaultRateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+}
+
+// rateLimitedRoundTripper waits for the rate limiter before sending each request.
+type rateLimitedRoundTripper struct {
+	rt      http.RoundTripper
+	limiter flowcontrol.RateLimiter
+}
+
+func (r *rateLimitedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := r.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return r.rt.RoundTrip(req)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+type countingRoundTripper struct {
+	requests int
+}
+
+func (c *countingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	c.requests++
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func Test_rateLimitedRoundTripper(t *testing.T) {
+	g := NewWithT(t)
+
+	rt := &countingRoundTripper{}
+	limited := &rateLimitedRoundTripper{
+		rt:      rt,
+		limiter: flowcontrol.NewTokenBucketRateLimiter(0.001, 1),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://compute.example.com/", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The first request uses the burst
+	_, err = limited.RoundTrip(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rt.requests).To(Equal(1))
+
+	// The second request has to wait, so it is not sent when the context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limited.RoundTrip(req.WithContext(ctx))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(rt.requests).To(Equal(1))
+}
+
+func TestSetRateLimit(t *testing.T) {
+	g := NewWithT(t)
+	defer SetRateLimit(0, 0)
+
+	SetRateLimit(10, 20)
+	g.Expect(defaultRateLimiter).NotTo(BeNil())
+	g.Expect(defaultRateLimiter.QPS()).To(BeNumerically("==", 10))
+
+	SetRateLimit(0, 20)
+	g.Expect(defaultRateLimiter).To(BeNil())
+}
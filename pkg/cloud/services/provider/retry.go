@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+// defaultRetryPolicy is used by all provider clients. It is nil if failed
+// requests to OpenStack are not retried.
+var defaultRetryPolicy *retryPolicy
+
+// SetRetryPolicy makes all provider clients created afterwards retry requests
+// which were rate limited or failed with a server error up to maxRetries
+// times. The delay before a retry doubles with every attempt, starting at
+// baseDelay and capped at maxDelay, unless OpenStack requests a delay with a
+// Retry-After header. A maxRetries of 0 or less disables retries. It must be
+// called before any provider client is created.
+func SetRetryPolicy(maxRetries int, baseDelay, maxDelay time.Duration) {
+	if maxRetries <= 0 {
+		defaultRetryPolicy = nil
+		return
+	}
+	if maxDelay < baseDelay {
+		maxDelay = baseDelay
+	}
+	defaultRetryPolicy = &retryPolicy{
+		maxRetries: uint(maxRetries),
+		baseDelay:  baseDelay,
+		maxDelay:   maxDelay,
+		now:        time.Now,
+	}
+}
+
+type retryPolicy struct {
+	maxRetries uint
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	now        func() time.Time
+}
+
+// apply configures provider to retry failed requests.
+func (p *retryPolicy) apply(provider *gophercloud.ProviderClient) {
+	provider.MaxBackoffRetries = p.maxRetries
+	provider.RetryBackoffFunc = p.retryBackoff
+	provider.RetryFunc = p.retry
+}
+
+// retryBackoff is called by gophercloud for requests which were rate limited
+// with a 429 response. These were not processed by OpenStack, so all of them
+// can be retried.
+func (p *retryPolicy) retryBackoff(ctx context.Context, _ *gophercloud.ErrUnexpectedResponseCode, err error, failCount uint) error {
+	return p.wait(ctx, err, failCount)
+}
+
+// retry is called by gophercloud for all other failed requests. Only
+// idempotent requests which failed with a server error are retried, as a
+// server error does not tell whether a resource was created. Requests which
+// were rate limited reach retry once retryBackoff gave up on them.
+func (p *retryPolicy) retry(ctx context.Context, method, _ string, _ *gophercloud.RequestOpts, err error, failCount uint) error {
+	if failCount > p.maxRetries || !isIdempotent(method) {
+		return err
+	}
+	statusCode, ok := capoerrors.StatusCode(err)
+	if !ok || statusCode == http.StatusTooManyRequests || !capoerrors.IsRetryable(err) {
+		return err
+	}
+	return p.wait(ctx, err, failCount)
+}
+
+// wait sleeps before the given retry of a request. It returns err if the
+// context is done first.
+func (p *retryPolicy) wait(ctx context.Context, err error, failCount uint) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(p.delay(err, failCount))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return err
+	case <-timer.C:
+		return nil
+	}
+}
+
+// delay returns the delay before the given retry of a request which failed
+// with err.
+func (p *retryPolicy) delay(err error, failCount uint) time.Duration {
+	if delay, ok := capoerrors.RetryAfter(err, p.now()); ok {
+		if delay > p.maxDelay {
+			return p.maxDelay
+		}
+		return delay
+	}
+
+	delay := p.baseDelay
+	for i := uint(1); i < failCount && delay < p.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	// Spread out the retries of concurrent reconciles which were rejected together
+	return wait.Jitter(delay, 0.1)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
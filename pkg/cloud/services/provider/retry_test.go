@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+func responseError(statusCode int, header http.Header) error {
+	respErr := gophercloud.ErrUnexpectedResponseCode{Actual: statusCode, ResponseHeader: header}
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return gophercloud.ErrDefault429{ErrUnexpectedResponseCode: respErr}
+	case http.StatusInternalServerError:
+		return gophercloud.ErrDefault500{ErrUnexpectedResponseCode: respErr}
+	case http.StatusServiceUnavailable:
+		return gophercloud.ErrDefault503{ErrUnexpectedResponseCode: respErr}
+	}
+	return respErr
+}
+
+func Test_retryPolicy_delay(t *testing.T) {
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	policy := &retryPolicy{
+		maxRetries: 10,
+		baseDelay:  time.Second,
+		maxDelay:   30 * time.Second,
+		now:        func() time.Time { return now },
+	}
+
+	tests := []struct {
+		name      string
+		err       error
+		failCount uint
+		min       time.Duration
+		max       time.Duration
+	}{
+		{
+			name:      "first retry",
+			err:       responseError(http.StatusServiceUnavailable, nil),
+			failCount: 1,
+			min:       time.Second,
+			max:       1100 * time.Millisecond,
+		},
+		{
+			name:      "doubles with every retry",
+			err:       responseError(http.StatusServiceUnavailable, nil),
+			failCount: 4,
+			min:       8 * time.Second,
+			max:       8800 * time.Millisecond,
+		},
+		{
+			name:      "capped at max delay",
+			err:       responseError(http.StatusServiceUnavailable, nil),
+			failCount: 10,
+			min:       30 * time.Second,
+			max:       33 * time.Second,
+		},
+		{
+			name:      "Retry-After in seconds",
+			err:       responseError(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"7"}}),
+			failCount: 4,
+			min:       7 * time.Second,
+			max:       7 * time.Second,
+		},
+		{
+			name:      "Retry-After as date",
+			err:       responseError(http.StatusTooManyRequests, http.Header{"Retry-After": []string{now.Add(12 * time.Second).Format(http.TimeFormat)}}),
+			failCount: 1,
+			min:       12 * time.Second,
+			max:       12 * time.Second,
+		},
+		{
+			name:      "Retry-After capped at max delay",
+			err:       responseError(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"3600"}}),
+			failCount: 1,
+			min:       30 * time.Second,
+			max:       30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			delay := policy.delay(tt.err, tt.failCount)
+			g.Expect(delay).To(BeNumerically(">=", tt.min))
+			g.Expect(delay).To(BeNumerically("<=", tt.max))
+		})
+	}
+}
+
+func Test_retryPolicy_retry(t *testing.T) {
+	policy := &retryPolicy{
+		maxRetries: 2,
+		baseDelay:  time.Millisecond,
+		maxDelay:   time.Millisecond,
+		now:        time.Now,
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		failCount uint
+		wantRetry bool
+	}{
+		{
+			name:      "server error of idempotent request",
+			method:    http.MethodGet,
+			err:       responseError(http.StatusInternalServerError, nil),
+			failCount: 1,
+			wantRetry: true,
+		},
+		{
+			name:      "server error of request which creates a resource",
+			method:    http.MethodPost,
+			err:       responseError(http.StatusServiceUnavailable, nil),
+			failCount: 1,
+		},
+		{
+			name:      "not implemented",
+			method:    http.MethodGet,
+			err:       responseError(http.StatusNotImplemented, nil),
+			failCount: 1,
+		},
+		{
+			name:      "permanent error",
+			method:    http.MethodDelete,
+			err:       responseError(http.StatusForbidden, nil),
+			failCount: 1,
+		},
+		{
+			name:      "rate limited after backoff gave up",
+			method:    http.MethodGet,
+			err:       responseError(http.StatusTooManyRequests, nil),
+			failCount: 2,
+		},
+		{
+			name:      "too many retries",
+			method:    http.MethodGet,
+			err:       responseError(http.StatusInternalServerError, nil),
+			failCount: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := policy.retry(context.TODO(), tt.method, "https://compute.example.com/", nil, tt.err, tt.failCount)
+			if tt.wantRetry {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+			}
+		})
+	}
+
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	policy.baseDelay, policy.maxDelay = time.Hour, time.Hour
+	err := policy.retry(ctx, http.MethodGet, "https://compute.example.com/", nil, responseError(http.StatusInternalServerError, nil), 1)
+	g.Expect(err).To(HaveOccurred(), "retry must not wait when the context is done")
+}
+
+func TestSetRetryPolicy(t *testing.T) {
+	g := NewWithT(t)
+	defer SetRetryPolicy(0, 0, 0)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	SetRetryPolicy(3, time.Millisecond, time.Millisecond)
+	g.Expect(defaultRetryPolicy).NotTo(BeNil())
+
+	provider := &gophercloud.ProviderClient{HTTPClient: *server.Client()}
+	defaultRetryPolicy.apply(provider)
+
+	_, err := provider.Request(http.MethodGet, server.URL, &gophercloud.RequestOpts{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requests).To(Equal(3))
+
+	// Requests which may have created a resource are not retried on server errors
+	requests = 1
+	_, err = provider.Request(http.MethodPost, server.URL, &gophercloud.RequestOpts{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(requests).To(Equal(2))
+
+	SetRetryPolicy(0, time.Second, time.Second)
+	g.Expect(defaultRetryPolicy).To(BeNil())
+}
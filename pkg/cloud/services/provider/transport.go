@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// transportOptions tune the HTTP transports used to send requests to
+// OpenStack.
+type transportOptions struct {
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	enableHTTP2         bool
+}
+
+// defaultTransportOptions are used by all transports created afterwards.
+var defaultTransportOptions = transportOptions{
+	maxIdleConnsPerHost: 10,
+	idleConnTimeout:     90 * time.Second,
+}
+
+// SetTransportOptions configures the HTTP transports used by all provider
+// clients created afterwards. Up to maxIdleConnsPerHost connections to each
+// OpenStack endpoint are kept open for idleConnTimeout after their last
+// request. If enableHTTP2 is set, HTTP/2 is used with endpoints supporting it.
+// A maxIdleConnsPerHost of 0 or less keeps the default. It must be called
+// before any provider client is created.
+func SetTransportOptions(maxIdleConnsPerHost int, idleConnTimeout time.Duration, enableHTTP2 bool) {
+	if maxIdleConnsPerHost > 0 {
+		defaultTransportOptions.maxIdleConnsPerHost = maxIdleConnsPerHost
+	}
+	defaultTransportOptions.idleConnTimeout = idleConnTimeout
+	defaultTransportOptions.enableHTTP2 = enableHTTP2
+}
+
+// transportCache holds HTTP transports keyed by their TLS configuration.
+// Provider clients are re-created whenever their token expires, and every
+// identity has its own provider client. Sharing the transports keeps their
+// connections, and so their TLS sessions, to the same cloud open across them.
+type transportCache struct {
+	mu         sync.Mutex
+	transports map[string]*http.Transport
+}
+
+func newTransportCache() *transportCache {
+	return &transportCache{
+		transports: make(map[string]*http.Transport),
+	}
+}
+
+var defaultTransportCache = newTransportCache()
+
+// get returns the transport for the given TLS settings, creating it if it
+// does not exist yet.
+func (c *transportCache) get(insecureSkipVerify bool, caCert []byte) *http.Transport {
+	key := transportKey(insecureSkipVerify, caCert)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if transport, ok := c.transports[key]; ok {
+		return transport
+	}
+	transport := newTransport(defaultTransportOptions, insecureSkipVerify, caCert)
+	c.transports[key] = transport
+	return transport
+}
+
+// transportKey computes a key identifying the TLS settings of a transport.
+func transportKey(insecureSkipVerify bool, caCert []byte) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(strconv.FormatBool(insecureSkipVerify)))
+	hasher.Write(caCert)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func newTransport(opts transportOptions, insecureSkipVerify bool, caCert []byte) *http.Transport {
+	config := &tls.Config{
+		RootCAs:            x509.NewCertPool(),
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if caCert != nil {
+		config.RootCAs.AppendCertsFromPEM(caCert)
+	}
+
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       config,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   opts.maxIdleConnsPerHost,
+		IdleConnTimeout:       opts.idleConnTimeout,
+		// A custom TLS configuration disables HTTP/2 unless it is forced
+		ForceAttemptHTTP2: opts.enableHTTP2,
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_transportCache(t *testing.T) {
+	g := NewWithT(t)
+
+	c := newTransportCache()
+	caCert := []byte("-----BEGIN CERTIFICATE-----")
+
+	transport := c.get(false, caCert)
+	g.Expect(c.get(false, caCert)).To(BeIdenticalTo(transport))
+	g.Expect(c.get(true, caCert)).NotTo(BeIdenticalTo(transport))
+	g.Expect(c.get(false, nil)).NotTo(BeIdenticalTo(transport))
+	g.Expect(c.get(true, caCert).TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+}
+
+func TestSetTransportOptions(t *testing.T) {
+	g := NewWithT(t)
+	defaults := defaultTransportOptions
+	defer func() { defaultTransportOptions = defaults }()
+
+	SetTransportOptions(50, time.Minute, true)
+	transport := newTransport(defaultTransportOptions, false, nil)
+	g.Expect(transport.MaxIdleConnsPerHost).To(Equal(50))
+	g.Expect(transport.IdleConnTimeout).To(Equal(time.Minute))
+	g.Expect(transport.ForceAttemptHTTP2).To(BeTrue())
+
+	SetTransportOptions(0, 0, false)
+	g.Expect(defaultTransportOptions.maxIdleConnsPerHost).To(Equal(50))
+	g.Expect(defaultTransportOptions.idleConnTimeout).To(BeZero())
+	g.Expect(defaultTransportOptions.enableHTTP2).To(BeFalse())
+}
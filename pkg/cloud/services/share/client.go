@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package share
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/sharenetworks"
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/metrics"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+type ShareClient interface {
+	CreateShare(opts shares.CreateOptsBuilder) (*shares.Share, error)
+	ListShares(opts shares.ListOptsBuilder) ([]shares.Share, error)
+	GetShare(id string) (*shares.Share, error)
+	DeleteShare(id string) error
+	ListExportLocations(id string) ([]shares.ExportLocation, error)
+	GrantAccess(id string, opts shares.GrantAccessOptsBuilder) (*shares.AccessRight, error)
+	ListAccessRights(id string) ([]shares.AccessRight, error)
+	CreateShareNetwork(opts sharenetworks.CreateOptsBuilder) (*sharenetworks.ShareNetwork, error)
+	ListShareNetworks(opts sharenetworks.ListOptsBuilder) ([]sharenetworks.ShareNetwork, error)
+	DeleteShareNetwork(id string) error
+}
+
+type shareClient struct {
+	serviceClient *gophercloud.ServiceClient
+}
+
+func (c shareClient) CreateShare(opts shares.CreateOptsBuilder) (*shares.Share, error) {
+	mc := metrics.NewMetricPrometheusContext("share", "create")
+	share, err := shares.Create(c.serviceClient, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (c shareClient) ListShares(opts shares.ListOptsBuilder) ([]shares.Share, error) {
+	mc := metrics.NewMetricPrometheusContext("share", "list")
+	allPages, err := shares.ListDetail(c.serviceClient, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return shares.ExtractShares(allPages)
+}
+
+func (c shareClient) GetShare(id string) (*shares.Share, error) {
+	mc := metrics.NewMetricPrometheusContext("share", "get")
+	share, err := shares.Get(c.serviceClient, id).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (c shareClient) DeleteShare(id string) error {
+	mc := metrics.NewMetricPrometheusContext("share", "delete")
+	err := shares.Delete(c.serviceClient, id).ExtractErr()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (c shareClient) ListExportLocations(id string) ([]shares.ExportLocation, error) {
+	mc := metrics.NewMetricPrometheusContext("share_export_location", "list")
+	exportLocations, err := shares.ListExportLocations(c.serviceClient, id).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return exportLocations, nil
+}
+
+func (c shareClient) GrantAccess(id string, opts shares.GrantAccessOptsBuilder) (*shares.AccessRight, error) {
+	mc := metrics.NewMetricPrometheusContext("share_access_rule", "create")
+	accessRight, err := shares.GrantAccess(c.serviceClient, id, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return accessRight, nil
+}
+
+func (c shareClient) ListAccessRights(id string) ([]shares.AccessRight, error) {
+	mc := metrics.NewMetricPrometheusContext("share_access_rule", "list")
+	accessRights, err := shares.ListAccessRights(c.serviceClient, id).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return accessRights, nil
+}
+
+func (c shareClient) CreateShareNetwork(opts sharenetworks.CreateOptsBuilder) (*sharenetworks.ShareNetwork, error) {
+	mc := metrics.NewMetricPrometheusContext("share_network", "create")
+	shareNetwork, err := sharenetworks.Create(c.serviceClient, opts).Extract()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return shareNetwork, nil
+}
+
+func (c shareClient) ListShareNetworks(opts sharenetworks.ListOptsBuilder) ([]sharenetworks.ShareNetwork, error) {
+	mc := metrics.NewMetricPrometheusContext("share_network", "list")
+	allPages, err := sharenetworks.ListDetail(c.serviceClient, opts).AllPages()
+	if mc.ObserveRequest(err) != nil {
+		return nil, err
+	}
+	return sharenetworks.ExtractShareNetworks(allPages)
+}
+
+func (c shareClient) DeleteShareNetwork(id string) error {
+	mc := metrics.NewMetricPrometheusContext("share_network", "delete")
+	err := sharenetworks.Delete(c.serviceClient, id).ExtractErr()
+	if mc.ObserveRequestIgnoreNotFound(err) != nil && !capoerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
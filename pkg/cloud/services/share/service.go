@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package share
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// minShareMicroversion is the Manila microversion which returns the access
+// keys of cephx access rules.
+const minShareMicroversion = "2.21"
+
+// Service interfaces with the OpenStack Shared File Systems (Manila) API.
+type Service struct {
+	scope  *scope.Scope
+	client ShareClient
+}
+
+// NewService returns an instance of the share service.
+func NewService(scope *scope.Scope) (*Service, error) {
+	serviceClient, err := openstack.NewSharedFileSystemV2(scope.ProviderClient, gophercloud.EndpointOpts{
+		Region: scope.ProviderClientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share service client: %v", err)
+	}
+	serviceClient.Microversion = minShareMicroversion
+
+	return &Service{
+		scope:  scope,
+		client: shareClient{serviceClient},
+	}, nil
+}
+
+// NewTestService returns a Service with no initialisation. It should only be used by tests.
+func NewTestService(client ShareClient, logger logr.Logger) *Service {
+	return &Service{
+		scope: &scope.Scope{
+			Logger: logger,
+		},
+		client: client,
+	}
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package share
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/sharenetworks"
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/record"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
+)
+
+const (
+	sharePrefix string = "k8s-clusterapi"
+
+	shareStatusAvailable     = "available"
+	shareStatusError         = "error"
+	shareStatusDeleting      = "deleting"
+	shareStatusErrorDeleting = "error_deleting"
+
+	accessRuleStateActive = "active"
+	accessRuleStateError  = "error"
+
+	// ProtocolNFS is the protocol of NFS shares.
+	ProtocolNFS = "NFS"
+	// ProtocolCephFS is the protocol of CephFS shares.
+	ProtocolCephFS = "CEPHFS"
+)
+
+var backoff = wait.Backoff{
+	Steps:    10,
+	Duration: 10 * time.Second,
+	Factor:   1.0,
+	Jitter:   0.1,
+}
+
+// ReconcileShare ensures that the share of the cluster exists with its share
+// network and an access rule for the cluster, and sets it in the status of
+// the cluster. It returns false while the share or its access rule are not
+// available yet.
+func (s *Service) ReconcileShare(openStackCluster *infrav1.OpenStackCluster, clusterName string) (bool, error) {
+	spec := openStackCluster.Spec.Share
+	network := openStackCluster.Status.Network
+	if network == nil || network.Subnet == nil {
+		return false, fmt.Errorf("the share requires the network of the cluster")
+	}
+
+	name := getShareName(clusterName)
+	shareStatus := &infrav1.Share{
+		Name:     name,
+		Protocol: spec.Protocol,
+	}
+
+	if !spec.DisableShareNetwork {
+		shareNetwork, err := s.getOrCreateShareNetwork(openStackCluster, name, clusterName, network.ID, network.Subnet.ID)
+		if err != nil {
+			return false, err
+		}
+		shareStatus.ShareNetworkID = shareNetwork.ID
+	}
+
+	share, err := s.getOrCreateShare(openStackCluster, name, clusterName, shareStatus.ShareNetworkID)
+	if err != nil {
+		return false, err
+	}
+	shareStatus.ID = share.ID
+	openStackCluster.Status.Share = shareStatus
+
+	switch share.Status {
+	case shareStatusAvailable:
+	case shareStatusError:
+		return false, fmt.Errorf("share %s is in error state", share.ID)
+	default:
+		s.scope.Logger.Info("Waiting for share", "id", share.ID, "status", share.Status)
+		return false, nil
+	}
+
+	accessRule, err := s.getOrCreateAccessRule(openStackCluster, share, clusterName, network.Subnet.CIDR)
+	if err != nil {
+		return false, err
+	}
+	shareStatus.AccessRuleID = accessRule.ID
+
+	switch accessRule.State {
+	case accessRuleStateActive:
+	case accessRuleStateError:
+		return false, fmt.Errorf("access rule %s of share %s is in error state", accessRule.ID, share.ID)
+	default:
+		s.scope.Logger.Info("Waiting for access rule of share", "id", accessRule.ID, "state", accessRule.State)
+		return false, nil
+	}
+
+	exportLocations, err := s.client.ListExportLocations(share.ID)
+	if err != nil {
+		return false, err
+	}
+	shareStatus.ExportLocation = preferredExportLocation(exportLocations)
+
+	return true, nil
+}
+
+func (s *Service) getOrCreateShareNetwork(openStackCluster *infrav1.OpenStackCluster, name, clusterName, networkID, subnetID string) (*sharenetworks.ShareNetwork, error) {
+	shareNetworkList, err := s.client.ListShareNetworks(sharenetworks.ListOpts{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share networks: %v", err)
+	}
+	if len(shareNetworkList) > 1 {
+		return nil, fmt.Errorf("found %d share networks with name %s", len(shareNetworkList), name)
+	}
+	if len(shareNetworkList) == 1 {
+		return &shareNetworkList[0], nil
+	}
+
+	opts := sharenetworks.CreateOpts{
+		Name:            name,
+		Description:     names.GetDescription(clusterName),
+		NeutronNetID:    networkID,
+		NeutronSubnetID: subnetID,
+	}
+	shareNetwork, err := s.client.CreateShareNetwork(opts)
+	if err != nil {
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.ShareNetwork, Name: name, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	record.Succeeded(openStackCluster, record.Create, record.Resource{Kind: record.ShareNetwork, Name: name, ID: shareNetwork.ID, RequestID: s.scope.LastRequestID()})
+	return shareNetwork, nil
+}
+
+func (s *Service) getOrCreateShare(openStackCluster *infrav1.OpenStackCluster, name, clusterName, shareNetworkID string) (*shares.Share, error) {
+	share, err := s.getShareByName(name)
+	if err != nil || share != nil {
+		return share, err
+	}
+
+	spec := openStackCluster.Spec.Share
+	opts := shares.CreateOpts{
+		ShareProto:       spec.Protocol,
+		Size:             spec.Size,
+		Name:             name,
+		Description:      names.GetDescription(clusterName),
+		ShareType:        spec.ShareType,
+		AvailabilityZone: spec.AvailabilityZone,
+		ShareNetworkID:   shareNetworkID,
+	}
+	share, err = s.client.CreateShare(opts)
+	if err != nil {
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.Share, Name: name, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	record.Succeeded(openStackCluster, record.Create, record.Resource{Kind: record.Share, Name: name, ID: share.ID, RequestID: s.scope.LastRequestID()})
+	return share, nil
+}
+
+// getOrCreateAccessRule returns the access rule of the share for the cluster.
+// NFS shares are accessible from the subnet of the cluster, CephFS shares
+// through a cephx user named after the cluster.
+func (s *Service) getOrCreateAccessRule(openStackCluster *infrav1.OpenStackCluster, share *shares.Share, clusterName, subnetCIDR string) (*shares.AccessRight, error) {
+	opts := shares.GrantAccessOpts{
+		AccessType:  "ip",
+		AccessTo:    subnetCIDR,
+		AccessLevel: "rw",
+	}
+	if share.ShareProto == ProtocolCephFS {
+		opts.AccessType = "cephx"
+		opts.AccessTo = clusterName
+	}
+
+	accessRights, err := s.client.ListAccessRights(share.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access rules of share %s: %v", share.ID, err)
+	}
+	for i := range accessRights {
+		if accessRights[i].AccessType == opts.AccessType && accessRights[i].AccessTo == opts.AccessTo {
+			return &accessRights[i], nil
+		}
+	}
+
+	accessRight, err := s.client.GrantAccess(share.ID, opts)
+	if err != nil {
+		record.Failed(openStackCluster, record.Create, record.Resource{Kind: record.AccessRule, Name: opts.AccessTo, RequestID: s.scope.LastRequestID()}, err)
+		return nil, capoerrors.WithRequestID(err, s.scope.LastRequestID())
+	}
+	record.Succeeded(openStackCluster, record.Create, record.Resource{Kind: record.AccessRule, Name: opts.AccessTo, ID: accessRight.ID, RequestID: s.scope.LastRequestID()})
+	return accessRight, nil
+}
+
+// GetAccessKey returns the access key of the cephx access rule of the share,
+// or an empty string for other access rules.
+func (s *Service) GetAccessKey(shareID, accessRuleID string) (string, error) {
+	accessRights, err := s.client.ListAccessRights(shareID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list access rules of share %s: %v", shareID, err)
+	}
+	for _, accessRight := range accessRights {
+		if accessRight.ID == accessRuleID {
+			return accessRight.AccessKey, nil
+		}
+	}
+	return "", fmt.Errorf("access rule %s of share %s not found", accessRuleID, shareID)
+}
+
+// DeleteShare deletes the share of the cluster and its share network, and
+// waits until the share is gone, as its share network cannot be deleted
+// before.
+func (s *Service) DeleteShare(openStackCluster *infrav1.OpenStackCluster, clusterName string) error {
+	name := getShareName(clusterName)
+	share, err := s.getShareByName(name)
+	if err != nil {
+		return err
+	}
+
+	if share != nil {
+		shareEvent := record.Resource{Kind: record.Share, Name: share.Name, ID: share.ID}
+		switch share.Status {
+		case shareStatusErrorDeleting:
+			err := fmt.Errorf("share %s is in error_deleting state", share.ID)
+			record.Failed(openStackCluster, record.Delete, shareEvent, err)
+			return err
+		case shareStatusDeleting:
+		default:
+			s.scope.Logger.Info("Deleting share", "name", share.Name, "id", share.ID)
+			if err := s.client.DeleteShare(share.ID); err != nil {
+				shareEvent.RequestID = s.scope.LastRequestID()
+				record.Failed(openStackCluster, record.Delete, shareEvent, err)
+				return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+			}
+		}
+
+		if err := s.waitForShareDeleted(share.ID); err != nil {
+			record.Failed(openStackCluster, record.Delete, shareEvent, err)
+			return fmt.Errorf("share %s was not deleted after timeout: %w", share.ID, err)
+		}
+		record.Succeeded(openStackCluster, record.Delete, shareEvent)
+	}
+
+	shareNetworkList, err := s.client.ListShareNetworks(sharenetworks.ListOpts{Name: name})
+	if err != nil {
+		return fmt.Errorf("failed to list share networks: %v", err)
+	}
+	for _, shareNetwork := range shareNetworkList {
+		shareNetworkEvent := record.Resource{Kind: record.ShareNetwork, Name: shareNetwork.Name, ID: shareNetwork.ID}
+		if err := s.client.DeleteShareNetwork(shareNetwork.ID); err != nil {
+			shareNetworkEvent.RequestID = s.scope.LastRequestID()
+			record.Failed(openStackCluster, record.Delete, shareNetworkEvent, err)
+			return capoerrors.WithRequestID(err, s.scope.LastRequestID())
+		}
+		record.Succeeded(openStackCluster, record.Delete, shareNetworkEvent)
+	}
+
+	openStackCluster.Status.Share = nil
+	return nil
+}
+
+func (s *Service) getShareByName(name string) (*shares.Share, error) {
+	shareList, err := s.client.ListShares(shares.ListOpts{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shares: %v", err)
+	}
+	switch len(shareList) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &shareList[0], nil
+	default:
+		return nil, fmt.Errorf("found %d shares with name %s", len(shareList), name)
+	}
+}
+
+func (s *Service) waitForShareDeleted(id string) error {
+	s.scope.Logger.Info("Waiting for share", "id", id, "targetStatus", "DELETED")
+	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+		share, err := s.client.GetShare(id)
+		if err != nil {
+			if capoerrors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		if share.Status == shareStatusErrorDeleting {
+			return false, fmt.Errorf("share %s is in error_deleting state", id)
+		}
+		return false, nil
+	})
+}
+
+// preferredExportLocation returns the path of the preferred export location
+// which is accessible to users, or of the first one if none is preferred.
+func preferredExportLocation(exportLocations []shares.ExportLocation) string {
+	var path string
+	for _, exportLocation := range exportLocations {
+		if exportLocation.IsAdminOnly {
+			continue
+		}
+		if exportLocation.Preferred {
+			return exportLocation.Path
+		}
+		if path == "" {
+			path = exportLocation.Path
+		}
+	}
+	return path
+}
+
+func getShareName(clusterName string) string {
+	return fmt.Sprintf("%s-cluster-%s", sharePrefix, clusterName)
+}
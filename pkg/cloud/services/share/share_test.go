@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package share
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/sharenetworks"
+	"github.com/gophercloud/gophercloud/openstack/sharedfilesystems/v2/shares"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/share/mock_share"
+)
+
+func Test_ReconcileShare(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const (
+		name        = "k8s-clusterapi-cluster-test-cluster"
+		description = "Created by cluster-api-provider-openstack cluster test-cluster"
+	)
+
+	tests := []struct {
+		name       string
+		spec       infrav1.ManagedShare
+		expect     func(m *mock_share.MockShareClientMockRecorder)
+		wantReady  bool
+		wantStatus *infrav1.Share
+		wantErr    bool
+	}{
+		{
+			name: "creates NFS share with share network",
+			spec: infrav1.ManagedShare{Protocol: ProtocolNFS, Size: 10},
+			expect: func(m *mock_share.MockShareClientMockRecorder) {
+				m.ListShareNetworks(sharenetworks.ListOpts{Name: name}).Return(nil, nil)
+				m.CreateShareNetwork(sharenetworks.CreateOpts{
+					Name:            name,
+					Description:     description,
+					NeutronNetID:    "network-id",
+					NeutronSubnetID: "subnet-id",
+				}).Return(&sharenetworks.ShareNetwork{ID: "share-network-id"}, nil)
+				m.ListShares(shares.ListOpts{Name: name}).Return(nil, nil)
+				m.CreateShare(shares.CreateOpts{
+					ShareProto:     ProtocolNFS,
+					Size:           10,
+					Name:           name,
+					Description:    description,
+					ShareNetworkID: "share-network-id",
+				}).Return(&shares.Share{ID: "share-id", ShareProto: ProtocolNFS, Status: "creating"}, nil)
+			},
+			wantReady: false,
+			wantStatus: &infrav1.Share{
+				ID:             "share-id",
+				Name:           name,
+				Protocol:       ProtocolNFS,
+				ShareNetworkID: "share-network-id",
+			},
+		},
+		{
+			name: "grants access to available NFS share",
+			spec: infrav1.ManagedShare{Protocol: ProtocolNFS, Size: 10, DisableShareNetwork: true},
+			expect: func(m *mock_share.MockShareClientMockRecorder) {
+				m.ListShares(shares.ListOpts{Name: name}).
+					Return([]shares.Share{{ID: "share-id", ShareProto: ProtocolNFS, Status: "available"}}, nil)
+				m.ListAccessRights("share-id").Return(nil, nil)
+				m.GrantAccess("share-id", shares.GrantAccessOpts{AccessType: "ip", AccessTo: "10.6.0.0/24", AccessLevel: "rw"}).
+					Return(&shares.AccessRight{ID: "access-id", State: "queued_to_apply"}, nil)
+			},
+			wantReady: false,
+			wantStatus: &infrav1.Share{
+				ID:           "share-id",
+				Name:         name,
+				Protocol:     ProtocolNFS,
+				AccessRuleID: "access-id",
+			},
+		},
+		{
+			name: "publishes export location of CephFS share",
+			spec: infrav1.ManagedShare{Protocol: ProtocolCephFS, Size: 10, DisableShareNetwork: true},
+			expect: func(m *mock_share.MockShareClientMockRecorder) {
+				m.ListShares(shares.ListOpts{Name: name}).
+					Return([]shares.Share{{ID: "share-id", ShareProto: ProtocolCephFS, Status: "available"}}, nil)
+				m.ListAccessRights("share-id").
+					Return([]shares.AccessRight{{ID: "access-id", AccessType: "cephx", AccessTo: "test-cluster", State: "active"}}, nil)
+				m.ListExportLocations("share-id").Return([]shares.ExportLocation{
+					{Path: "10.0.0.1:/admin", IsAdminOnly: true},
+					{Path: "10.0.0.2:/volumes/a"},
+					{Path: "10.0.0.3:/volumes/a", Preferred: true},
+				}, nil)
+			},
+			wantReady: true,
+			wantStatus: &infrav1.Share{
+				ID:             "share-id",
+				Name:           name,
+				Protocol:       ProtocolCephFS,
+				AccessRuleID:   "access-id",
+				ExportLocation: "10.0.0.3:/volumes/a",
+			},
+		},
+		{
+			name: "fails for share in error state",
+			spec: infrav1.ManagedShare{Protocol: ProtocolNFS, Size: 10, DisableShareNetwork: true},
+			expect: func(m *mock_share.MockShareClientMockRecorder) {
+				m.ListShares(shares.ListOpts{Name: name}).
+					Return([]shares.Share{{ID: "share-id", ShareProto: ProtocolNFS, Status: "error"}}, nil)
+			},
+			wantErr: true,
+			wantStatus: &infrav1.Share{
+				ID:       "share-id",
+				Name:     name,
+				Protocol: ProtocolNFS,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockClient := mock_share.NewMockShareClient(mockCtrl)
+			tt.expect(mockClient.EXPECT())
+			s := NewTestService(mockClient, logr.Discard())
+
+			openStackCluster := &infrav1.OpenStackCluster{
+				Spec: infrav1.OpenStackClusterSpec{Share: &tt.spec},
+				Status: infrav1.OpenStackClusterStatus{
+					Network: &infrav1.Network{
+						ID:     "network-id",
+						Subnet: &infrav1.Subnet{ID: "subnet-id", CIDR: "10.6.0.0/24"},
+					},
+				},
+			}
+			ready, err := s.ReconcileShare(openStackCluster, "test-cluster")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(ready).To(Equal(tt.wantReady))
+			g.Expect(openStackCluster.Status.Share).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+func Test_DeleteShare(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const name = "k8s-clusterapi-cluster-test-cluster"
+
+	g := NewWithT(t)
+	mockClient := mock_share.NewMockShareClient(mockCtrl)
+	m := mockClient.EXPECT()
+	m.ListShares(shares.ListOpts{Name: name}).Return([]shares.Share{{ID: "share-id", Name: name, Status: "available"}}, nil)
+	m.DeleteShare("share-id").Return(nil)
+	m.GetShare("share-id").Return(nil, gophercloud.ErrDefault404{})
+	m.ListShareNetworks(sharenetworks.ListOpts{Name: name}).Return([]sharenetworks.ShareNetwork{{ID: "share-network-id", Name: name}}, nil)
+	m.DeleteShareNetwork("share-network-id").Return(nil)
+
+	s := NewTestService(mockClient, logr.Discard())
+	openStackCluster := &infrav1.OpenStackCluster{
+		Status: infrav1.OpenStackClusterStatus{Share: &infrav1.Share{ID: "share-id"}},
+	}
+	g.Expect(s.DeleteShare(openStackCluster, "test-cluster")).To(Succeed())
+	g.Expect(openStackCluster.Status.Share).To(BeNil())
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun records the changes to OpenStack resources a reconcile would
+// apply instead of applying them.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// IDPrefix is the prefix of the placeholder IDs of resources which would be
+// created.
+const IDPrefix = "dry-run-"
+
+type planKey struct{}
+
+// plan holds the changes recorded for a context, and the resources which
+// would have been created or deleted by them, so that later requests of the
+// same reconcile see them.
+type plan struct {
+	mu      sync.Mutex
+	changes []infrav1.ResourceChange
+	created map[string]createdResource
+	deleted map[string]bool
+	lastID  int
+}
+
+type createdResource struct {
+	key    string
+	object map[string]interface{}
+}
+
+// WithPlan returns a context in which requests changing OpenStack resources
+// are not sent by provider clients, but recorded so that they can be
+// retrieved with Changes.
+func WithPlan(ctx context.Context) context.Context {
+	return context.WithValue(ctx, planKey{}, &plan{
+		created: map[string]createdResource{},
+		deleted: map[string]bool{},
+	})
+}
+
+// IsDryRun returns true if changes to OpenStack resources are recorded
+// instead of applied in the given context.
+func IsDryRun(ctx context.Context) bool {
+	return fromContext(ctx) != nil
+}
+
+// Changes returns the changes recorded in the given context, in the order
+// they were requested.
+func Changes(ctx context.Context) []infrav1.ResourceChange {
+	p := fromContext(ctx)
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]infrav1.ResourceChange(nil), p.changes...)
+}
+
+func fromContext(ctx context.Context) *plan {
+	if ctx == nil {
+		return nil
+	}
+	p, _ := ctx.Value(planKey{}).(*plan)
+	return p
+}
+
+func (p *plan) record(change infrav1.ResourceChange) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.changes = append(p.changes, change)
+}
+
+func (p *plan) create(key string, object map[string]interface{}) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastID++
+	id := fmt.Sprintf("%s%d", IDPrefix, p.lastID)
+	object["id"] = id
+	p.created[id] = createdResource{key: key, object: object}
+	return id
+}
+
+func (p *plan) delete(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.created, id)
+	p.deleted[id] = true
+}
+
+func (p *plan) lookup(id string) (createdResource, bool, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	resource, created := p.created[id]
+	return resource, created, p.deleted[id]
+}
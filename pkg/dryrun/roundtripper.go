@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+var uuidPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}(-?[0-9a-f]{4}){3}-?[0-9a-f]{12}$`)
+
+// RoundTripper records the requests which change OpenStack resources in the
+// plan of their context instead of sending them, see WithPlan. It answers
+// them with the resource of the request, so that the reconcile can continue
+// as if they were applied. Read requests for resources which would have been
+// created or deleted are answered accordingly, all other requests are sent.
+type RoundTripper struct {
+	Rt http.RoundTripper
+}
+
+func (r *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	p := fromContext(req.Context())
+	// Authentication does not change any resources
+	if p == nil || strings.HasSuffix(req.URL.Path, "/auth/tokens") {
+		return r.Rt.RoundTrip(req)
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		if resp := p.read(req); resp != nil {
+			return resp, nil
+		}
+		return r.Rt.RoundTrip(req)
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return p.apply(req)
+	}
+	return r.Rt.RoundTrip(req)
+}
+
+// read answers requests for a resource which would have been created or
+// deleted. It returns nil for all other requests.
+func (p *plan) read(req *http.Request) *http.Response {
+	segments := pathSegments(req)
+	if len(segments) == 0 {
+		return nil
+	}
+	for _, segment := range segments {
+		if _, _, deleted := p.lookup(segment); deleted {
+			return response(req, http.StatusNotFound, map[string]interface{}{
+				"itemNotFound": map[string]interface{}{"code": http.StatusNotFound, "message": segment + " would be deleted"},
+			})
+		}
+	}
+	resource, created, _ := p.lookup(segments[len(segments)-1])
+	if !created {
+		return nil
+	}
+	return response(req, http.StatusOK, map[string]interface{}{resource.key: resource.object})
+}
+
+// apply records the change requested by req and answers it as OpenStack
+// would if it was applied.
+func (p *plan) apply(req *http.Request) (*http.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := pathSegments(req)
+	change := infrav1.ResourceChange{Request: req.Method + " " + req.URL.Path}
+	key, object := resourceObject(body)
+	if name, ok := object["name"].(string); ok {
+		change.Name = name
+	}
+
+	switch {
+	case req.Method == http.MethodDelete:
+		change.Action = "Delete"
+		change.Kind, change.ID = resourceKindAndID(segments)
+		if change.ID != "" {
+			p.delete(change.ID)
+		}
+		p.record(change)
+		return response(req, http.StatusNoContent, nil), nil
+
+	case req.Method == http.MethodPost && len(segments) > 0 && segments[len(segments)-1] == "action":
+		// Server actions, e.g. reboot
+		change.Action = "Update"
+		change.Kind, change.ID = resourceKindAndID(segments)
+		p.record(change)
+		return response(req, http.StatusAccepted, nil), nil
+
+	case req.Method == http.MethodPost:
+		change.Action = "Create"
+		change.Kind = key
+		if change.Kind == "" && len(segments) > 0 {
+			change.Kind = singular(segments[len(segments)-1])
+		}
+		if object != nil {
+			object["status"] = "ACTIVE"
+			object["provisioning_status"] = "ACTIVE"
+			object["operating_status"] = "ONLINE"
+			change.ID = p.create(key, object)
+		}
+		p.record(change)
+		return response(req, http.StatusCreated, body), nil
+
+	default:
+		change.Action = "Update"
+		change.Kind, change.ID = resourceKindAndID(segments)
+		p.record(change)
+		return response(req, http.StatusOK, body), nil
+	}
+}
+
+// resourceObject returns the resource of a request body, which is the only
+// object in it, e.g. {"network": {...}}. Keys of extensions like
+// os:scheduler_hints are ignored.
+func resourceObject(body map[string]interface{}) (string, map[string]interface{}) {
+	var key string
+	var object map[string]interface{}
+	for k, v := range body {
+		o, ok := v.(map[string]interface{})
+		if !ok || strings.Contains(k, ":") {
+			continue
+		}
+		if object != nil {
+			return "", nil
+		}
+		key, object = k, o
+	}
+	return key, object
+}
+
+// resourceKindAndID returns the kind and ID of the resource a request for the
+// given path is about, e.g. security_group_rule and its ID for
+// /v2.0/security-group-rules/{id}, or server and its ID for
+// /servers/{id}/action.
+func resourceKindAndID(segments []string) (string, string) {
+	for i := len(segments) - 1; i > 0; i-- {
+		if isID(segments[i]) {
+			return singular(segments[i-1]), segments[i]
+		}
+	}
+	if len(segments) == 0 {
+		return "", ""
+	}
+	return singular(segments[len(segments)-1]), ""
+}
+
+func isID(segment string) bool {
+	return strings.HasPrefix(segment, IDPrefix) || uuidPattern.MatchString(segment)
+}
+
+func singular(collection string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(collection, "-", "_"), "s")
+}
+
+func pathSegments(req *http.Request) []string {
+	var segments []string
+	for _, s := range strings.Split(req.URL.Path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+func requestBody(req *http.Request) (map[string]interface{}, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("dry run of %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	return body, nil
+}
+
+func response(req *http.Request, statusCode int, body map[string]interface{}) *http.Response {
+	var data []byte
+	if body != nil {
+		// Marshalling a decoded JSON document cannot fail
+		data, _ = json.Marshal(body)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
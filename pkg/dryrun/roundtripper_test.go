@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/rules"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+)
+
+const existingNetworkID = "4e8e5957-649f-477b-9e5b-f1f75b21c03c"
+
+func TestRoundTripper(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"network": {"id": %q, "name": "existing"}}`, existingNetworkID)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	ctx := WithPlan(context.Background())
+	g.Expect(IsDryRun(ctx)).To(BeTrue())
+
+	provider := &gophercloud.ProviderClient{
+		HTTPClient: http.Client{Transport: &RoundTripper{Rt: http.DefaultTransport}},
+		Context:    ctx,
+	}
+	client := &gophercloud.ServiceClient{ProviderClient: provider, Endpoint: server.URL + "/", ResourceBase: server.URL + "/v2.0/"}
+
+	// Resources which would be created get a placeholder ID and can be read
+	network, err := networks.Create(client, networks.CreateOpts{Name: "k8s-clusterapi-cluster-default-test"}).Extract()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(network.ID).To(Equal(IDPrefix + "1"))
+	g.Expect(network.Name).To(Equal("k8s-clusterapi-cluster-default-test"))
+
+	network, err = networks.Get(client, network.ID).Extract()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(network.Name).To(Equal("k8s-clusterapi-cluster-default-test"))
+
+	_, err = rules.Create(client, rules.CreateOpts{SecGroupID: "1f1f3b8c-8b9e-4b6e-a4f5-6c6ddc4d6c57", Direction: rules.DirIngress, EtherType: rules.EtherType4}).Extract()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Existing resources are read from OpenStack, and are gone once they would be deleted
+	network, err = networks.Get(client, existingNetworkID).Extract()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(network.Name).To(Equal("existing"))
+
+	err = networks.Delete(client, existingNetworkID).ExtractErr()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = networks.Get(client, existingNetworkID).Extract()
+	g.Expect(capoerrors.IsNotFound(err)).To(BeTrue())
+
+	g.Expect(requests).To(Equal([]string{"GET /v2.0/networks/" + existingNetworkID}))
+	g.Expect(Changes(ctx)).To(Equal([]infrav1.ResourceChange{
+		{Action: "Create", Kind: "network", Name: "k8s-clusterapi-cluster-default-test", ID: "dry-run-1", Request: "POST /v2.0/networks"},
+		{Action: "Create", Kind: "security_group_rule", ID: "dry-run-2", Request: "POST /v2.0/security-group-rules"},
+		{Action: "Delete", Kind: "network", ID: existingNetworkID, Request: "DELETE /v2.0/networks/" + existingNetworkID},
+	}))
+
+	// Without a plan all requests are sent
+	provider.Context = context.Background()
+	g.Expect(IsDryRun(provider.Context)).To(BeFalse())
+	err = networks.Delete(client, existingNetworkID).ExtractErr()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(requests).To(HaveLen(2))
+	g.Expect(Changes(provider.Context)).To(BeEmpty())
+}
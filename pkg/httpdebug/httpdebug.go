@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpdebug logs the HTTP requests to OpenStack and their responses
+// for the reconciles which enable it, with credentials and tokens redacted.
+// Unlike the logging of gophercloud, which is enabled for all clusters with
+// the log level, it can be enabled for a single cluster at runtime.
+package httpdebug
+
+import (
+	"context"
+)
+
+type enabledKey struct{}
+
+// WithLogging returns a context in which the requests to OpenStack are
+// logged with the logger of the context.
+func WithLogging(ctx context.Context) context.Context {
+	return context.WithValue(ctx, enabledKey{}, true)
+}
+
+// IsEnabled returns whether the requests sent with the context are logged.
+func IsEnabled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(enabledKey{}).(bool)
+	return enabled
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpdebug
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	redacted = "***"
+
+	// maxBodyLength is the number of bytes of a body which are logged.
+	maxBodyLength = 16 * 1024
+)
+
+// redactedHeaders are the headers which carry credentials or tokens, including
+// the keys Swift signs temporary URLs with, e.g. of the bootstrap data.
+var redactedHeaders = sets.NewString(
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Account-Meta-Temp-Url-Key",
+	"X-Account-Meta-Temp-Url-Key-2",
+	"X-Auth-Token",
+	"X-Container-Meta-Temp-Url-Key",
+	"X-Container-Meta-Temp-Url-Key-2",
+	"X-Service-Token",
+	"X-Subject-Token",
+)
+
+// strippedQueryParameters are the query parameters of temporary URLs of Swift,
+// which grant access to an object to anyone knowing the URL.
+var strippedQueryParameters = []string{
+	"temp_url_expires",
+	"temp_url_sig",
+}
+
+// redactedFields are the fields of JSON bodies which carry credentials,
+// tokens or secrets, e.g. the bootstrap token in the user data of a server.
+var redactedFields = sets.NewString(
+	"access_key",
+	"adminPass",
+	"password",
+	"private_key",
+	"secret",
+	"token",
+	"user_data",
+)
+
+// RoundTripper logs the requests sent with a context for which logging was
+// enabled with WithLogging, and their responses.
+type RoundTripper struct {
+	Rt http.RoundTripper
+}
+
+func (r *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !IsEnabled(req.Context()) {
+		return r.Rt.RoundTrip(req)
+	}
+	log := logr.FromContextOrDiscard(req.Context())
+
+	var requestBody string
+	if req.Body != nil && isJSON(req.Header) {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		requestBody = formatBody(body)
+	}
+	log.Info("OpenStack HTTP request", "method", req.Method, "url", formatURL(req.URL), "header", formatHeader(req.Header), "body", requestBody)
+
+	start := time.Now()
+	resp, err := r.Rt.RoundTrip(req)
+	if err != nil {
+		log.Info("OpenStack HTTP request failed", "method", req.Method, "url", formatURL(req.URL), "duration", time.Since(start).String(), "error", err.Error())
+		return nil, err
+	}
+
+	var responseBody string
+	if resp.Body != nil && isJSON(resp.Header) {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		responseBody = formatBody(body)
+	}
+	log.Info("OpenStack HTTP response", "method", req.Method, "url", formatURL(req.URL), "status", resp.StatusCode, "duration", time.Since(start).String(), "header", formatHeader(resp.Header), "body", responseBody)
+	return resp, nil
+}
+
+// isJSON returns whether the body with the given headers is JSON. Other
+// bodies, e.g. image data, are not logged.
+func isJSON(header http.Header) bool {
+	return strings.Contains(header.Get("Content-Type"), "json")
+}
+
+// formatURL returns the URL without its password and the signature of a
+// temporary URL.
+func formatURL(u *url.URL) string {
+	query := u.Query()
+	stripped := false
+	for _, parameter := range strippedQueryParameters {
+		if query.Has(parameter) {
+			query.Del(parameter)
+			stripped = true
+		}
+	}
+	if !stripped {
+		return u.Redacted()
+	}
+	formatted := *u
+	formatted.RawQuery = query.Encode()
+	return formatted.Redacted()
+}
+
+func formatHeader(header http.Header) map[string]string {
+	formatted := make(map[string]string, len(header))
+	for name, values := range header {
+		if redactedHeaders.Has(http.CanonicalHeaderKey(name)) {
+			formatted[name] = redacted
+			continue
+		}
+		formatted[name] = strings.Join(values, ", ")
+	}
+	return formatted
+}
+
+func formatBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("<%d bytes of invalid JSON>", len(body))
+	}
+	formatted, err := json.Marshal(redact(value))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	if len(formatted) > maxBodyLength {
+		return string(formatted[:maxBodyLength]) + "..."
+	}
+	return string(formatted)
+}
+
+// redact replaces the values of the redacted fields of a decoded JSON value.
+func redact(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if redactedFields.Has(k) {
+				value[k] = redacted
+				continue
+			}
+			value[k] = redact(v)
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = redact(v)
+		}
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// clusterGaugeVec is a gauge of the clusters with the namespace and cluster
+// labels and a third label, e.g. a port or a resource. It remembers the values
+// of the third label set for each cluster, so that the series which are not
+// set anymore can be deleted.
+type clusterGaugeVec struct {
+	*prometheus.GaugeVec
+
+	lock   sync.Mutex
+	series map[[2]string]map[string]bool
+}
+
+func newClusterGaugeVec(subsystem, name, help, label string) *clusterGaugeVec {
+	return &clusterGaugeVec{
+		GaugeVec: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "capo",
+				Subsystem: subsystem,
+				Name:      name,
+				Help:      help,
+			}, []string{"namespace", "cluster", label}),
+		series: map[[2]string]map[string]bool{},
+	}
+}
+
+// set sets the values of the series of the cluster, and deletes the series of
+// the cluster which are not in values.
+func (g *clusterGaugeVec) set(namespace, cluster string, values map[string]float64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	key := [2]string{namespace, cluster}
+	for label := range g.series[key] {
+		if _, ok := values[label]; !ok {
+			g.DeleteLabelValues(namespace, cluster, label)
+		}
+	}
+	if len(values) == 0 {
+		delete(g.series, key)
+		return
+	}
+	labels := make(map[string]bool, len(values))
+	for label, value := range values {
+		g.WithLabelValues(namespace, cluster, label).Set(value)
+		labels[label] = true
+	}
+	g.series[key] = labels
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// LoadBalancerListenerStats are the statistics of a listener of the API
+// server load balancer of a cluster, as reported by Octavia.
+type LoadBalancerListenerStats struct {
+	Port              int
+	ActiveConnections int
+	TotalConnections  int
+	RequestErrors     int
+	BytesIn           int
+	BytesOut          int
+}
+
+func newLoadBalancerListenerGauge(name, help string) *clusterGaugeVec {
+	return newClusterGaugeVec("apiserver_loadbalancer_listener", name, help, "port")
+}
+
+var loadBalancerListenerMetrics = struct {
+	ActiveConnections *clusterGaugeVec
+	TotalConnections  *clusterGaugeVec
+	RequestErrors     *clusterGaugeVec
+	BytesIn           *clusterGaugeVec
+	BytesOut          *clusterGaugeVec
+}{
+	ActiveConnections: newLoadBalancerListenerGauge("active_connections", "Connections currently active on a listener of the API server load balancer"),
+	TotalConnections:  newLoadBalancerListenerGauge("connections", "Connections handled by a listener of the API server load balancer since its creation"),
+	RequestErrors:     newLoadBalancerListenerGauge("request_errors", "Requests a listener of the API server load balancer was unable to fulfill since its creation"),
+	BytesIn:           newLoadBalancerListenerGauge("received_bytes", "Bytes received by a listener of the API server load balancer since its creation"),
+	BytesOut:          newLoadBalancerListenerGauge("sent_bytes", "Bytes sent by a listener of the API server load balancer since its creation"),
+}
+
+// SetAPIServerLoadBalancerStats sets the metrics of the listeners of the API
+// server load balancer of a cluster. The metrics of listeners which are not
+// in stats are deleted.
+func SetAPIServerLoadBalancerStats(namespace, cluster string, stats []LoadBalancerListenerStats) {
+	activeConnections := map[string]float64{}
+	totalConnections := map[string]float64{}
+	requestErrors := map[string]float64{}
+	bytesIn := map[string]float64{}
+	bytesOut := map[string]float64{}
+	for _, s := range stats {
+		port := strconv.Itoa(s.Port)
+		activeConnections[port] = float64(s.ActiveConnections)
+		totalConnections[port] = float64(s.TotalConnections)
+		requestErrors[port] = float64(s.RequestErrors)
+		bytesIn[port] = float64(s.BytesIn)
+		bytesOut[port] = float64(s.BytesOut)
+	}
+
+	m := loadBalancerListenerMetrics
+	m.ActiveConnections.set(namespace, cluster, activeConnections)
+	m.TotalConnections.set(namespace, cluster, totalConnections)
+	m.RequestErrors.set(namespace, cluster, requestErrors)
+	m.BytesIn.set(namespace, cluster, bytesIn)
+	m.BytesOut.set(namespace, cluster, bytesOut)
+}
+
+// DeleteAPIServerLoadBalancerStats deletes the metrics of the listeners of the
+// API server load balancer of a cluster.
+func DeleteAPIServerLoadBalancerStats(namespace, cluster string) {
+	SetAPIServerLoadBalancerStats(namespace, cluster, nil)
+}
+
+var registerLoadBalancerPrometheusMetrics sync.Once
+
+// RegisterLoadBalancerPrometheusMetrics registers the metrics of the API
+// server load balancers of the clusters.
+func RegisterLoadBalancerPrometheusMetrics() {
+	registerLoadBalancerPrometheusMetrics.Do(func() {
+		m := loadBalancerListenerMetrics
+		for _, gauge := range []*clusterGaugeVec{m.ActiveConnections, m.TotalConnections, m.RequestErrors, m.BytesIn, m.BytesOut} {
+			metrics.Registry.MustRegister(gauge)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSetAPIServerLoadBalancerStats(t *testing.T) {
+	g := NewWithT(t)
+	activeConnections := loadBalancerListenerMetrics.ActiveConnections
+
+	SetAPIServerLoadBalancerStats("ns", "cluster", []LoadBalancerListenerStats{
+		{Port: 6443, ActiveConnections: 5},
+		{Port: 8443, ActiveConnections: 1},
+	})
+	SetAPIServerLoadBalancerStats("ns", "other-cluster", []LoadBalancerListenerStats{{Port: 6443, ActiveConnections: 2}})
+	g.Expect(testutil.CollectAndCount(activeConnections)).To(Equal(3))
+	g.Expect(testutil.ToFloat64(activeConnections.WithLabelValues("ns", "cluster", "6443"))).To(Equal(float64(5)))
+
+	// The metrics of a removed listener are deleted
+	SetAPIServerLoadBalancerStats("ns", "cluster", []LoadBalancerListenerStats{{Port: 6443, ActiveConnections: 7}})
+	g.Expect(testutil.CollectAndCount(activeConnections)).To(Equal(2))
+	g.Expect(testutil.ToFloat64(activeConnections.WithLabelValues("ns", "cluster", "6443"))).To(Equal(float64(7)))
+
+	// The metrics of a deleted cluster are deleted
+	DeleteAPIServerLoadBalancerStats("ns", "cluster")
+	DeleteAPIServerLoadBalancerStats("ns", "other-cluster")
+	g.Expect(testutil.CollectAndCount(activeConnections)).To(Equal(0))
+	g.Expect(activeConnections.series).To(BeEmpty())
+}
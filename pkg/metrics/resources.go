@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/quota"
+)
+
+// Kinds of the OpenStack resources counted for each cluster.
+const (
+	ResourcePort              = "port"
+	ResourceTrunk             = "trunk"
+	ResourceFloatingIP        = "floatingip"
+	ResourceSecurityGroupRule = "security_group_rule"
+)
+
+var (
+	clusterResources = newClusterGaugeVec("cluster", "openstack_resources",
+		"OpenStack resources managed for a cluster", "resource")
+	clusterQuotaUsage = newClusterGaugeVec("cluster", "quota_usage_percent",
+		"Percentage of the quota of a resource of the project of a cluster which is in use", "resource")
+)
+
+// SetClusterResources sets the number of OpenStack resources of each kind
+// managed for a cluster.
+func SetClusterResources(namespace, cluster string, counts map[string]int) {
+	values := make(map[string]float64, len(counts))
+	for resource, count := range counts {
+		values[resource] = float64(count)
+	}
+	clusterResources.set(namespace, cluster, values)
+}
+
+// SetClusterQuotaUsages sets the percentage of the quotas of the project of a
+// cluster which is in use. Resources without a limit are not reported.
+func SetClusterQuotaUsages(namespace, cluster string, usages []quota.Usage) {
+	values := make(map[string]float64, len(usages))
+	for _, usage := range usages {
+		if percent, ok := usage.Percent(); ok {
+			values[usage.Resource] = percent
+		}
+	}
+	clusterQuotaUsage.set(namespace, cluster, values)
+}
+
+// DeleteClusterResources deletes the resource and quota metrics of a cluster.
+func DeleteClusterResources(namespace, cluster string) {
+	clusterResources.set(namespace, cluster, nil)
+	clusterQuotaUsage.set(namespace, cluster, nil)
+}
+
+var registerClusterResourcePrometheusMetrics sync.Once
+
+// RegisterClusterResourcePrometheusMetrics registers the resource and quota
+// metrics of the clusters.
+func RegisterClusterResourcePrometheusMetrics() {
+	registerClusterResourcePrometheusMetrics.Do(func() {
+		metrics.Registry.MustRegister(clusterResources)
+		metrics.Registry.MustRegister(clusterQuotaUsage)
+	})
+}
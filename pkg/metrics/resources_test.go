@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/quota"
+)
+
+func TestSetClusterQuotaUsages(t *testing.T) {
+	g := NewWithT(t)
+
+	SetClusterQuotaUsages("ns", "cluster", []quota.Usage{
+		{Resource: "network.ports", Limit: 50, InUse: 45},
+		{Resource: "network.trunks", Limit: -1, InUse: 2},
+	})
+	g.Expect(testutil.CollectAndCount(clusterQuotaUsage)).To(Equal(1))
+	g.Expect(testutil.ToFloat64(clusterQuotaUsage.WithLabelValues("ns", "cluster", "network.ports"))).To(Equal(float64(90)))
+
+	SetClusterResources("ns", "cluster", map[string]int{ResourcePort: 12, ResourceFloatingIP: 1})
+	g.Expect(testutil.ToFloat64(clusterResources.WithLabelValues("ns", "cluster", ResourcePort))).To(Equal(float64(12)))
+
+	DeleteClusterResources("ns", "cluster")
+	g.Expect(testutil.CollectAndCount(clusterQuotaUsage)).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(clusterResources)).To(Equal(0))
+}
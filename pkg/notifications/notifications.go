@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notifications receives the notifications Nova and Neutron publish
+// with oslo.messaging and turns the ones about instances and their ports into
+// reconcile triggers, so that changes made to them outside of the controller
+// are handled without waiting for the next resync.
+package notifications
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxNotificationSize is the maximum size of a notification read.
+const maxNotificationSize = 1024 * 1024
+
+// Notification is a notification published with oslo.messaging.
+type Notification struct {
+	EventType   string          `json:"event_type"`
+	PublisherID string          `json:"publisher_id,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// envelope is the envelope of the messages of oslo.messaging, which carries
+// the notification serialized in oslo.message.
+type envelope struct {
+	Version string `json:"oslo.version"`
+	Message string `json:"oslo.message"`
+}
+
+// Parse parses a notification, either as sent on the message bus in an
+// oslo.messaging envelope, or as the notification itself.
+func Parse(data []byte) (*Notification, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid notification: %w", err)
+	}
+	if env.Message != "" {
+		data = []byte(env.Message)
+	}
+
+	notification := &Notification{}
+	if err := json.Unmarshal(data, notification); err != nil {
+		return nil, fmt.Errorf("invalid notification: %w", err)
+	}
+	if notification.EventType == "" {
+		return nil, fmt.Errorf("invalid notification: no event_type")
+	}
+	return notification, nil
+}
+
+// instancePayload holds the fields identifying the instance in the payloads
+// of the notifications about instances and ports.
+type instancePayload struct {
+	// InstanceID is the instance of the legacy Nova notifications.
+	InstanceID string `json:"instance_id"`
+	// NovaObject is the payload of the versioned Nova notifications.
+	NovaObject struct {
+		UUID string `json:"uuid"`
+	} `json:"nova_object.data"`
+	// Port is the port of the Neutron notifications.
+	Port struct {
+		DeviceID    string `json:"device_id"`
+		DeviceOwner string `json:"device_owner"`
+	} `json:"port"`
+}
+
+// InstanceID returns the ID of the instance the notification is about, or an
+// empty string if the notification is not about a settled change of an
+// instance or of a port of an instance. Notifications about the start of an
+// operation are ignored, as the instance keeps changing until its end.
+func (n *Notification) InstanceID() string {
+	if strings.HasSuffix(n.EventType, ".start") {
+		return ""
+	}
+
+	var payload instancePayload
+	if err := json.Unmarshal(n.Payload, &payload); err != nil {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(n.EventType, "compute.instance."):
+		return payload.InstanceID
+	case strings.HasPrefix(n.EventType, "instance."):
+		return payload.NovaObject.UUID
+	case n.EventType == "port.update.end" || n.EventType == "port.delete.end":
+		if strings.HasPrefix(payload.Port.DeviceOwner, "compute:") {
+			return payload.Port.DeviceID
+		}
+	}
+	return ""
+}
+
+// InstanceHandler handles a change of the instance with the given ID.
+type InstanceHandler func(ctx context.Context, instanceID string) error
+
+// Receiver is an http.Handler receiving notifications posted one per request,
+// e.g. by a bridge consuming the notification queues of Nova and Neutron. The
+// handler is called for the notifications about instances and their ports.
+type Receiver struct {
+	handler InstanceHandler
+	token   string
+}
+
+// NewReceiver returns a Receiver calling handler. If token is not empty,
+// requests must carry it as a bearer token.
+func NewReceiver(handler InstanceHandler, token string) *Receiver {
+	return &Receiver{handler: handler, token: token}
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.token != "" {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, maxNotificationSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	notification, err := Parse(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if instanceID := notification.InstanceID(); instanceID != "" {
+		if err := r.handler(req.Context(), instanceID); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const instanceID = "1c7d3e2a-8a3b-4c1e-9e5d-7f0a6b2c4d11"
+
+func TestInstanceID(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification string
+		want         string
+	}{
+		{
+			name:         "Legacy instance notification",
+			notification: `{"event_type": "compute.instance.power_off.end", "payload": {"instance_id": "` + instanceID + `"}}`,
+			want:         instanceID,
+		},
+		{
+			name:         "Versioned instance notification",
+			notification: `{"event_type": "instance.live_migration_post.end", "payload": {"nova_object.name": "InstanceActionPayload", "nova_object.data": {"uuid": "` + instanceID + `"}}}`,
+			want:         instanceID,
+		},
+		{
+			name:         "Notification in an oslo.messaging envelope",
+			notification: `{"oslo.version": "2.0", "oslo.message": ` + strconv.Quote(`{"event_type": "compute.instance.delete.end", "payload": {"instance_id": "`+instanceID+`"}}`) + `}`,
+			want:         instanceID,
+		},
+		{
+			name:         "Start of an operation",
+			notification: `{"event_type": "compute.instance.rebuild.start", "payload": {"instance_id": "` + instanceID + `"}}`,
+		},
+		{
+			name:         "Deleted port of an instance",
+			notification: `{"event_type": "port.delete.end", "payload": {"port_id": "port-id", "port": {"device_id": "` + instanceID + `", "device_owner": "compute:nova"}}}`,
+			want:         instanceID,
+		},
+		{
+			name:         "Deleted port of a router",
+			notification: `{"event_type": "port.delete.end", "payload": {"port_id": "port-id", "port": {"device_id": "router-id", "device_owner": "network:router_interface"}}}`,
+		},
+		{
+			name:         "Other notification",
+			notification: `{"event_type": "network.create.end", "payload": {"network": {"id": "network-id"}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			notification, err := Parse([]byte(tt.notification))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(notification.InstanceID()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, data := range []string{`not json`, `{"payload": {}}`, `{"oslo.version": "2.0", "oslo.message": "not json"}`} {
+		_, err := Parse([]byte(data))
+		g.Expect(err).To(HaveOccurred(), data)
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	var handled []string
+	var handlerErr error
+	receiver := NewReceiver(func(_ context.Context, instanceID string) error {
+		handled = append(handled, instanceID)
+		return handlerErr
+	}, "secret")
+
+	post := func(body, token string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	instanceNotification := `{"event_type": "compute.instance.update", "payload": {"instance_id": "` + instanceID + `"}}`
+
+	g := NewWithT(t)
+	g.Expect(post(instanceNotification, "secret")).To(Equal(http.StatusNoContent))
+	g.Expect(handled).To(Equal([]string{instanceID}))
+
+	g.Expect(post(instanceNotification, "")).To(Equal(http.StatusUnauthorized))
+	g.Expect(post(instanceNotification, "wrong")).To(Equal(http.StatusUnauthorized))
+	g.Expect(post(`not json`, "secret")).To(Equal(http.StatusBadRequest))
+	g.Expect(post(`{"event_type": "network.create.end", "payload": {}}`, "secret")).To(Equal(http.StatusNoContent))
+	g.Expect(handled).To(HaveLen(1))
+
+	// Failures are reported so that the bridge redelivers the notification
+	handlerErr = errors.New("cache not synced")
+	g.Expect(post(instanceNotification, "secret")).To(Equal(http.StatusServiceUnavailable))
+}
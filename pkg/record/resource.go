@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// ResourceKind is the kind of an OpenStack resource mutated by CAPO.
+type ResourceKind string
+
+const (
+	Server        ResourceKind = "Server"
+	ServerGroup   ResourceKind = "ServerGroup"
+	Port          ResourceKind = "Port"
+	SecurityGroup ResourceKind = "SecurityGroup"
+	FirewallGroup ResourceKind = "FirewallGroup"
+	LoadBalancer  ResourceKind = "LoadBalancer"
+	Listener      ResourceKind = "Listener"
+	Pool          ResourceKind = "Pool"
+	Monitor       ResourceKind = "Monitor"
+	PoolMember    ResourceKind = "PoolMember"
+	FloatingIP    ResourceKind = "FloatingIP"
+	Share         ResourceKind = "Share"
+	ShareNetwork  ResourceKind = "ShareNetwork"
+	AccessRule    ResourceKind = "AccessRule"
+	Container     ResourceKind = "Container"
+	Object        ResourceKind = "Object"
+)
+
+var resourceKindDescriptions = map[ResourceKind]string{
+	Server:        "server",
+	ServerGroup:   "server group",
+	Port:          "port",
+	SecurityGroup: "security group",
+	FirewallGroup: "firewall group",
+	LoadBalancer:  "load balancer",
+	Listener:      "listener",
+	Pool:          "pool",
+	Monitor:       "monitor",
+	PoolMember:    "pool member",
+	FloatingIP:    "floating IP",
+	Share:         "share",
+	ShareNetwork:  "share network",
+	AccessRule:    "access rule",
+	Container:     "container",
+	Object:        "object",
+}
+
+// Action is a mutation of an OpenStack resource.
+type Action string
+
+const (
+	Create       Action = "Create"
+	Update       Action = "Update"
+	Delete       Action = "Delete"
+	Adopt        Action = "Adopt"
+	Associate    Action = "Associate"
+	Disassociate Action = "Disassociate"
+	Reboot       Action = "Reboot"
+	Attach       Action = "Attach"
+	Detach       Action = "Detach"
+)
+
+var actionPastTense = map[Action]string{
+	Create:       "Created",
+	Update:       "Updated",
+	Delete:       "Deleted",
+	Adopt:        "Adopted",
+	Associate:    "Associated",
+	Disassociate: "Disassociated",
+	Reboot:       "Rebooted",
+	Attach:       "Attached",
+	Detach:       "Detached",
+}
+
+// Resource describes the OpenStack resource an event is about.
+type Resource struct {
+	Kind ResourceKind
+	Name string
+	ID   string
+	// RequestID is the ID OpenStack assigned to the request which mutated
+	// the resource, if known.
+	RequestID string
+	// Detail is appended to the message of the event.
+	Detail string
+}
+
+// Succeeded records a Normal event with reason Successful<Action><Kind> on
+// object for a successful mutation of an OpenStack resource.
+func Succeeded(object runtime.Object, action Action, resource Resource) {
+	if isDryRun(object) {
+		return
+	}
+	message := actionPastTense[action] + " " + resource.describe()
+	if resource.Detail != "" {
+		message += ": " + resource.Detail
+	}
+	defaultRecorder.Event(object, corev1.EventTypeNormal, "Successful"+string(action)+string(resource.Kind), message)
+}
+
+// Failed records a Warning event with reason Failed<Action><Kind> on object for
+// a failed mutation of an OpenStack resource.
+func Failed(object runtime.Object, action Action, resource Resource, err error) {
+	if isDryRun(object) {
+		return
+	}
+	message := "Failed to " + strings.ToLower(string(action)) + " " + resource.describe()
+	if resource.Detail != "" {
+		message += ": " + resource.Detail
+	}
+	message += fmt.Sprintf(": %v", err)
+	defaultRecorder.Event(object, corev1.EventTypeWarning, "Failed"+string(action)+string(resource.Kind), message)
+}
+
+// isDryRun returns true if the changes to the OpenStack resources of object
+// are only planned, so no resources are actually mutated.
+func isDryRun(object runtime.Object) bool {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return false
+	}
+	return accessor.GetAnnotations()[infrav1.DryRunAnnotation] == "true"
+}
+
+func (r Resource) describe() string {
+	var b strings.Builder
+	b.WriteString(resourceKindDescriptions[r.Kind])
+	if r.Name != "" {
+		b.WriteString(" " + r.Name)
+	}
+	if r.ID != "" {
+		b.WriteString(" with id " + r.ID)
+	}
+	if r.RequestID != "" {
+		b.WriteString(" (request " + r.RequestID + ")")
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package record
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestResourceEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func()
+		want   string
+	}{
+		{
+			name: "Dry run",
+			record: func() {
+				cluster := &infrav1.OpenStackCluster{}
+				cluster.SetAnnotations(map[string]string{infrav1.DryRunAnnotation: "true"})
+				Succeeded(cluster, Create, Resource{Kind: SecurityGroup, Name: "k8s-cluster-default-test-secgroup-controlplane", ID: "dry-run-1"})
+			},
+		},
+		{
+			name: "Created",
+			record: func() {
+				Succeeded(&infrav1.OpenStackMachine{}, Create, Resource{Kind: Server, Name: "machine-1", ID: "server-id", RequestID: "req-1"})
+			},
+			want: "Normal SuccessfulCreateServer Created server machine-1 with id server-id (request req-1)",
+		},
+		{
+			name: "Updated with detail",
+			record: func() {
+				Succeeded(&infrav1.OpenStackMachine{}, Update, Resource{Kind: Port, ID: "port-id", Detail: "security groups [sg-1]"})
+			},
+			want: "Normal SuccessfulUpdatePort Updated port with id port-id: security groups [sg-1]",
+		},
+		{
+			name: "Failed",
+			record: func() {
+				Failed(&infrav1.OpenStackCluster{}, Delete, Resource{Kind: FloatingIP, Name: "10.0.0.1", ID: "fip-id", RequestID: "req-2"}, errors.New("conflict"))
+			},
+			want: "Warning FailedDeleteFloatingIP Failed to delete floating IP 10.0.0.1 with id fip-id (request req-2): conflict",
+		},
+	}
+
+	previous := defaultRecorder
+	defer func() { defaultRecorder = previous }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(1)
+			defaultRecorder = recorder
+
+			tt.record()
+			if tt.want == "" {
+				g.Expect(recorder.Events).NotTo(Receive())
+				return
+			}
+			g.Expect(recorder.Events).To(Receive(Equal(tt.want)))
+		})
+	}
+}
@@ -17,9 +17,14 @@ limitations under the License.
 package scope
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
 	"github.com/gophercloud/utils/openstack/clientconfig"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
 )
 
 // Scope is used to initialize Services from Controllers and includes the
@@ -28,11 +33,78 @@ import (
 // The Gophercloud ProviderClient and ClientOpts are required to create new
 // Gophercloud API Clients (e.g. for Networking/Neutron).
 //
-// The Logger includes context values such as the cluster name.
+// The Logger includes context values such as the namespace and the names of
+// the reconciled object, its cluster and its machine. Log messages add the
+// IDs of the OpenStack resources they are about.
 type Scope struct {
 	ProviderClient     *gophercloud.ProviderClient
 	ProviderClientOpts *clientconfig.ClientOpts
 	ProjectID          string
 
 	Logger logr.Logger
+
+	// Timeouts override the default timeouts of the services.
+	Timeouts Timeouts
+}
+
+// Timeouts are the durations for which the services wait for OpenStack
+// resources to reach their desired state. The services use their own
+// defaults for the timeouts which are not set.
+type Timeouts struct {
+	InstanceCreate      time.Duration
+	LoadBalancerCreate  time.Duration
+	FloatingIPAssociate time.Duration
+	Deletion            time.Duration
+}
+
+// LastRequestID returns the ID of the last request OpenStack answered for this
+// scope, for inclusion in events.
+func (s *Scope) LastRequestID() string {
+	if s == nil || s.ProviderClient == nil {
+		return ""
+	}
+	return tracing.LastRequestID(s.ProviderClient.Context)
+}
+
+// WithOwnRequestIDs returns a copy of the scope whose provider client records
+// the IDs of its requests apart from those of s. Services used concurrently
+// are created from their own copy, so that LastRequestID returns the ID of
+// their own last request.
+func (s *Scope) WithOwnRequestIDs() *Scope {
+	if s == nil || s.ProviderClient == nil {
+		return s
+	}
+	parent := s.ProviderClient
+	providerClient := *parent
+	providerClient.Context = tracing.WithRequestIDRecorder(parent.Context)
+	if parent.ReauthFunc != nil {
+		providerClient.ReauthFunc = func() error {
+			if err := parent.ReauthFunc(); err != nil {
+				return err
+			}
+			providerClient.CopyTokenFrom(parent)
+			return nil
+		}
+	}
+
+	scope := *s
+	scope.ProviderClient = &providerClient
+	return &scope
+}
+
+// UserID returns the ID of the user the scope is authenticated as, or an
+// empty string if the authentication did not return it.
+func (s *Scope) UserID() string {
+	if s == nil || s.ProviderClient == nil {
+		return ""
+	}
+	authResult, ok := s.ProviderClient.GetAuthResult().(tokens.CreateResult)
+	if !ok {
+		return ""
+	}
+	user, err := authResult.ExtractUser()
+	if err != nil {
+		return ""
+	}
+	return user.ID
 }
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/tracing"
+)
+
+func TestScope_WithOwnRequestIDs(t *testing.T) {
+	g := NewWithT(t)
+
+	// The server answers both requests at the same time, each with its own
+	// request ID.
+	var requests sync.WaitGroup
+	requests.Add(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Done()
+		requests.Wait()
+		w.Header().Set("X-Openstack-Request-Id", "req"+r.URL.Path)
+	}))
+	defer server.Close()
+
+	parent := &Scope{ProviderClient: &gophercloud.ProviderClient{
+		HTTPClient: http.Client{Transport: &tracing.RoundTripper{Rt: http.DefaultTransport}},
+		Context:    tracing.WithRequestIDRecorder(context.Background()),
+	}}
+	scopes := map[string]*Scope{
+		"/network":        parent.WithOwnRequestIDs(),
+		"/security-group": parent.WithOwnRequestIDs(),
+	}
+
+	var wg sync.WaitGroup
+	for path, scope := range scopes {
+		path, scope := path, scope
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := scope.ProviderClient.Request(http.MethodGet, server.URL+path, &gophercloud.RequestOpts{})
+			g.Expect(err).NotTo(HaveOccurred())
+		}()
+	}
+	wg.Wait()
+
+	g.Expect(scopes["/network"].LastRequestID()).To(Equal("req/network"))
+	g.Expect(scopes["/security-group"].LastRequestID()).To(Equal("req/security-group"))
+	g.Expect(parent.LastRequestID()).To(BeEmpty())
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+type requestIDRecorderKey struct{}
+
+// requestIDRecorder holds the request ID of the last OpenStack response
+// received with a context.
+type requestIDRecorder struct {
+	mu        sync.Mutex
+	requestID string
+}
+
+// WithRequestIDRecorder returns a context in which the request IDs of OpenStack
+// responses are recorded, so that they can be retrieved with LastRequestID.
+func WithRequestIDRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDRecorderKey{}, &requestIDRecorder{})
+}
+
+// LastRequestID returns the request ID of the last OpenStack response received
+// with the given context, or an empty string if there is none.
+func LastRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	r, ok := ctx.Value(requestIDRecorderKey{}).(*requestIDRecorder)
+	if !ok {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requestID
+}
+
+func recordRequestID(ctx context.Context, requestID string) {
+	r, ok := ctx.Value(requestIDRecorderKey{}).(*requestIDRecorder)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requestID = requestID
+}
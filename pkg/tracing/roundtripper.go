@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDKey is the span attribute holding the ID OpenStack assigned to a request.
+const RequestIDKey = attribute.Key("openstack.request_id")
+
+// requestIDHeaders are the response headers in which OpenStack services
+// return the request ID.
+var requestIDHeaders = []string{"X-Openstack-Request-Id", "X-Compute-Request-Id"}
+
+// RoundTripper creates a span for each request to OpenStack as a child of the
+// span in the context of the request. It also records the request ID of the
+// response in the context, see WithRequestIDRecorder.
+type RoundTripper struct {
+	Rt http.RoundTripper
+}
+
+func (r *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Tracer().Start(req.Context(), fmt.Sprintf("OpenStack %s %s", req.Method, req.URL.Host),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPURLKey.String(req.URL.Redacted()),
+		),
+	)
+	defer span.End()
+
+	resp, err := r.Rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	requestID := RequestID(resp.Header)
+	if requestID != "" {
+		span.SetAttributes(RequestIDKey.String(requestID))
+	}
+	recordRequestID(req.Context(), requestID)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// RequestID returns the ID OpenStack assigned to the request of the response
+// with the given headers.
+func RequestID(header http.Header) string {
+	for _, h := range requestIDHeaders {
+		if id := header.Get(h); id != "" {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRoundTripper(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Openstack-Request-Id", "req-1234")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx, parent := Tracer().Start(WithRequestIDRecorder(context.Background()), "OpenStackMachine.Reconcile")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/servers/1234", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	resp, err := (&RoundTripper{Rt: http.DefaultTransport}).RoundTrip(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp.Body.Close()
+	parent.End()
+
+	g.Expect(LastRequestID(ctx)).To(Equal("req-1234"))
+
+	spans := recorder.Ended()
+	g.Expect(spans).To(HaveLen(2))
+	span := spans[0]
+	g.Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+	g.Expect(span.Status().Code).To(Equal(codes.Error))
+
+	attributes := map[string]string{}
+	for _, kv := range span.Attributes() {
+		attributes[string(kv.Key)] = kv.Value.Emit()
+	}
+	g.Expect(attributes).To(HaveKeyWithValue(string(RequestIDKey), "req-1234"))
+	g.Expect(attributes).To(HaveKeyWithValue("http.status_code", "404"))
+}
+
+func TestRequestID(t *testing.T) {
+	g := NewWithT(t)
+
+	header := http.Header{}
+	g.Expect(RequestID(header)).To(BeEmpty())
+
+	header.Set("X-Compute-Request-Id", "req-compute")
+	g.Expect(RequestID(header)).To(Equal("req-compute"))
+
+	header.Set("X-Openstack-Request-Id", "req-openstack")
+	g.Expect(RequestID(header)).To(Equal("req-openstack"))
+}
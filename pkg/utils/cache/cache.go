@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache implements a TTL cache for responses of the OpenStack APIs
+// which is shared by all reconciles.
+package cache
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// purgeThreshold is the number of entries above which expired entries are
+// removed when a new entry is added.
+const purgeThreshold = 1024
+
+// Cache is a TTL cache for responses of the OpenStack APIs.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	sliding bool
+	entries map[string]entry
+	now     func() time.Time
+}
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// New returns a cache whose entries expire after ttl. A ttl of 0 or less
+// disables the cache.
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: make(map[string]entry),
+		now:     time.Now,
+	}
+}
+
+// NewSliding returns a cache whose entries expire once they have not been
+// read for ttl. A ttl of 0 or less disables the cache.
+func NewSliding(ttl time.Duration) *Cache {
+	c := New(ttl)
+	c.sliding = true
+	return c
+}
+
+var (
+	defaultCache           = New(0)
+	defaultGenerationCache = NewSliding(0)
+)
+
+// SetDefaultTTL sets the TTL of the cache shared by all services. A ttl of 0
+// or less disables the cache. It must be called before any service is created.
+func SetDefaultTTL(ttl time.Duration) {
+	defaultCache = New(ttl)
+}
+
+// SetGenerationTTL sets how long the lookups cached for a generation of a
+// resource are kept after they were last used, see ForGeneration. A ttl of 0
+// or less disables the cache. It must be called before any service is created.
+func SetGenerationTTL(ttl time.Duration) {
+	defaultGenerationCache = NewSliding(ttl)
+}
+
+// Purge removes all entries of the cache shared by all services. Writes are
+// only invalidated in the cache of the replica which made them, so a replica
+// must purge the entries it cached while it was not the leader, e.g. for
+// webhooks, once it is elected.
+func Purge() {
+	defaultCache.invalidate("")
+	defaultGenerationCache.invalidate("")
+}
+
+// ForScope returns a view of the shared cache for the cloud, project and
+// region of the given scope.
+func ForScope(scope *scope.Scope) Scoped {
+	var identityBase, region string
+	if scope.ProviderClient != nil {
+		identityBase = scope.ProviderClient.IdentityBase
+	}
+	if scope.ProviderClientOpts != nil {
+		region = scope.ProviderClientOpts.RegionName
+	}
+	return Scoped{
+		cache:  defaultCache,
+		prefix: identityBase + "|" + scope.ProjectID + "|" + region + "|",
+	}
+}
+
+// ForGeneration returns a view of the cache of lookups for the given
+// generation of a resource, e.g. the OpenStackMachineTemplate a rollout
+// creates machines from, in the cloud, project and region of the given scope.
+// Its entries are kept for as long as machines of the generation are created,
+// so they reflect the cloud when the first of them was created.
+func ForGeneration(scope *scope.Scope, uid types.UID, generation int64) Scoped {
+	s := ForScope(scope)
+	s.cache = defaultGenerationCache
+	s.prefix += string(uid) + "|" + strconv.FormatInt(generation, 10) + "|"
+	return s
+}
+
+func (c *Cache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	now := c.now()
+	if !now.Before(e.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	if c.sliding {
+		e.expiresAt = now.Add(c.ttl)
+		c.entries[key] = e
+	}
+	return e.value, true
+}
+
+func (c *Cache) set(key string, value interface{}) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= purgeThreshold {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = entry{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *Cache) invalidate(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k := range c.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Scoped is a view of a Cache for a single cloud, project and region.
+type Scoped struct {
+	cache  *Cache
+	prefix string
+}
+
+// Get returns the cached value for the given resource type and key.
+func (s Scoped) Get(resource, key string) (interface{}, bool) {
+	return s.cache.get(s.prefix + resource + "|" + key)
+}
+
+// Set caches the value for the given resource type and key.
+func (s Scoped) Set(resource, key string, value interface{}) {
+	s.cache.set(s.prefix+resource+"|"+key, value)
+}
+
+// Invalidate removes all cached values of the given resource type. It must be
+// called after writing to resources of that type.
+func (s Scoped) Invalidate(resource string) {
+	s.cache.invalidate(s.prefix + resource + "|")
+}
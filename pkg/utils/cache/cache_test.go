@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScoped(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(time.Minute)
+	c.now = func() time.Time { return now }
+
+	projectA := Scoped{cache: c, prefix: "https://keystone|project-a|RegionOne|"}
+	projectB := Scoped{cache: c, prefix: "https://keystone|project-b|RegionOne|"}
+
+	projectA.Set("network", "name=foo", "network-a")
+	projectA.Set("image", "name=bar", "image-a")
+	projectB.Set("network", "name=foo", "network-b")
+
+	value, ok := projectA.Get("network", "name=foo")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(value).To(Equal("network-a"))
+
+	value, ok = projectB.Get("network", "name=foo")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(value).To(Equal("network-b"))
+
+	// Invalidation only affects the resource type of the given scope
+	projectA.Invalidate("network")
+	_, ok = projectA.Get("network", "name=foo")
+	g.Expect(ok).To(BeFalse())
+	_, ok = projectA.Get("image", "name=bar")
+	g.Expect(ok).To(BeTrue())
+	_, ok = projectB.Get("network", "name=foo")
+	g.Expect(ok).To(BeTrue())
+
+	// Entries expire after the TTL
+	now = now.Add(time.Minute)
+	_, ok = projectA.Get("image", "name=bar")
+	g.Expect(ok).To(BeFalse())
+	g.Expect(c.entries).NotTo(HaveKey("https://keystone|project-a|RegionOne|image|name=bar"))
+}
+
+func TestDisabled(t *testing.T) {
+	g := NewWithT(t)
+
+	s := Scoped{cache: New(0), prefix: "https://keystone|project|RegionOne|"}
+	s.Set("network", "name=foo", "network")
+
+	_, ok := s.Get("network", "name=foo")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestPurge(t *testing.T) {
+	g := NewWithT(t)
+
+	SetDefaultTTL(time.Minute)
+	defer SetDefaultTTL(0)
+
+	projectA := Scoped{cache: defaultCache, prefix: "https://keystone|project-a|RegionOne|"}
+	projectB := Scoped{cache: defaultCache, prefix: "https://keystone|project-b|RegionOne|"}
+	projectA.Set("network", "name=foo", "network-a")
+	projectB.Set("image", "name=bar", "image-b")
+
+	Purge()
+	_, ok := projectA.Get("network", "name=foo")
+	g.Expect(ok).To(BeFalse())
+	_, ok = projectB.Get("image", "name=bar")
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestSliding(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewSliding(time.Minute)
+	c.now = func() time.Time { return now }
+
+	s := Scoped{cache: c, prefix: "https://keystone|project|RegionOne|template-uid|1|"}
+	s.Set("flavor", "m1.small", "flavor-id")
+
+	// Entries are kept for as long as they are used
+	for i := 0; i < 5; i++ {
+		now = now.Add(30 * time.Second)
+		value, ok := s.Get("flavor", "m1.small")
+		g.Expect(ok).To(BeTrue())
+		g.Expect(value).To(Equal("flavor-id"))
+	}
+
+	// and expire once they have not been used for the TTL
+	now = now.Add(time.Minute)
+	_, ok := s.Get("flavor", "m1.small")
+	g.Expect(ok).To(BeFalse())
+}
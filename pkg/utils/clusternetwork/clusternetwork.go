@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusternetwork
+
+import (
+	"fmt"
+	"net"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// minimumPodMTU is the smallest MTU left to pods once the overhead of the CNI
+// overlay is subtracted. It is the minimum MTU of IPv6.
+const minimumPodMTU = 1280
+
+// cniOverlayOverhead is the number of bytes the CNI overlay adds to every
+// packet over an IPv4 network.
+var cniOverlayOverhead = map[infrav1.CNIOverlay]int{
+	infrav1.CNIOverlayVXLAN:     50,
+	infrav1.CNIOverlayGeneve:    50,
+	infrav1.CNIOverlayIPIP:      20,
+	infrav1.CNIOverlayWireGuard: 60,
+}
+
+// NamedCIDR is a CIDR together with a description of where it comes from.
+type NamedCIDR struct {
+	Name string
+	CIDR string
+}
+
+// ClusterCIDRs returns the pod and service CIDRs of the Cluster.
+func ClusterCIDRs(cluster *clusterv1.Cluster) []NamedCIDR {
+	if cluster == nil || cluster.Spec.ClusterNetwork == nil {
+		return nil
+	}
+	var cidrs []NamedCIDR
+	if pods := cluster.Spec.ClusterNetwork.Pods; pods != nil {
+		for _, cidr := range pods.CIDRBlocks {
+			cidrs = append(cidrs, NamedCIDR{Name: "pod CIDR", CIDR: cidr})
+		}
+	}
+	if services := cluster.Spec.ClusterNetwork.Services; services != nil {
+		for _, cidr := range services.CIDRBlocks {
+			cidrs = append(cidrs, NamedCIDR{Name: "service CIDR", CIDR: cidr})
+		}
+	}
+	return cidrs
+}
+
+// NodeCIDRs returns the CIDRs used by the nodes of the OpenStackCluster: its
+// nodeCidr, the nodeCidr of its failure domains and, once it is known, the
+// existing subnet it uses instead.
+func NodeCIDRs(openStackCluster *infrav1.OpenStackCluster) []NamedCIDR {
+	var cidrs []NamedCIDR
+	if openStackCluster.Spec.NodeCIDR != "" {
+		cidrs = append(cidrs, NamedCIDR{Name: "nodeCidr", CIDR: openStackCluster.Spec.NodeCIDR})
+	} else if network := openStackCluster.Status.Network; network != nil && network.Subnet != nil && network.Subnet.CIDR != "" {
+		cidrs = append(cidrs, NamedCIDR{Name: fmt.Sprintf("subnet %s", network.Subnet.Name), CIDR: network.Subnet.CIDR})
+	}
+	if openStackCluster.Spec.FailureDomains != nil {
+		for _, mapping := range openStackCluster.Spec.FailureDomains.Mappings {
+			if mapping.NodeCIDR != "" {
+				cidrs = append(cidrs, NamedCIDR{Name: fmt.Sprintf("nodeCidr of failure domain %s", mapping.Name), CIDR: mapping.NodeCIDR})
+			}
+		}
+	}
+	return cidrs
+}
+
+// ValidateCIDRs returns a problem for every pod or service CIDR of the Cluster
+// which overlaps a CIDR used by the nodes.
+func ValidateCIDRs(clusterCIDRs, nodeCIDRs []NamedCIDR) []string {
+	var problems []string
+	for _, clusterCIDR := range clusterCIDRs {
+		for _, nodeCIDR := range nodeCIDRs {
+			if cidrsOverlap(clusterCIDR.CIDR, nodeCIDR.CIDR) {
+				problems = append(problems, fmt.Sprintf("%s %s overlaps %s %s", clusterCIDR.Name, clusterCIDR.CIDR, nodeCIDR.Name, nodeCIDR.CIDR))
+			}
+		}
+	}
+	return problems
+}
+
+// cidrsOverlap returns whether the two CIDRs share an address. Invalid CIDRs
+// are rejected elsewhere and never overlap.
+func cidrsOverlap(a, b string) bool {
+	_, netA, err := net.ParseCIDR(a)
+	if err != nil {
+		return false
+	}
+	_, netB, err := net.ParseCIDR(b)
+	if err != nil {
+		return false
+	}
+	return netA.Contains(netB.IP) || netB.Contains(netA.IP)
+}
+
+// HasOverhead returns whether the CNI overlay encapsulates pod traffic, so
+// that the MTU of the cluster network must be checked.
+func HasOverhead(overlay infrav1.CNIOverlay) bool {
+	return cniOverlayOverhead[overlay] > 0
+}
+
+// ValidateMTU returns a problem if the CNI overlay leaves pods on a network
+// with the given MTU an MTU below minimumPodMTU.
+func ValidateMTU(overlay infrav1.CNIOverlay, mtu int) string {
+	if podMTU := mtu - cniOverlayOverhead[overlay]; podMTU < minimumPodMTU {
+		return fmt.Sprintf("the %s overlay leaves pods an MTU of %d on the cluster network with MTU %d, which is below the minimum of %d",
+			overlay, podMTU, mtu, minimumPodMTU)
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusternetwork
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestNodeCIDRs(t *testing.T) {
+	g := NewWithT(t)
+
+	openStackCluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			FailureDomains: &infrav1.FailureDomainsConfig{
+				Mappings: []infrav1.FailureDomainMapping{{Name: "az1", NodeCIDR: "10.7.0.0/24"}},
+			},
+		},
+		Status: infrav1.OpenStackClusterStatus{
+			Network: &infrav1.Network{Subnet: &infrav1.Subnet{Name: "private", CIDR: "10.0.0.0/8"}},
+		},
+	}
+	g.Expect(NodeCIDRs(openStackCluster)).To(Equal([]NamedCIDR{
+		{Name: "subnet private", CIDR: "10.0.0.0/8"},
+		{Name: "nodeCidr of failure domain az1", CIDR: "10.7.0.0/24"},
+	}))
+
+	// The subnet created from the nodeCidr is not listed twice
+	openStackCluster.Spec.NodeCIDR = "10.6.0.0/24"
+	g.Expect(NodeCIDRs(openStackCluster)).To(Equal([]NamedCIDR{
+		{Name: "nodeCidr", CIDR: "10.6.0.0/24"},
+		{Name: "nodeCidr of failure domain az1", CIDR: "10.7.0.0/24"},
+	}))
+}
+
+func TestValidateCIDRs(t *testing.T) {
+	clusterCIDRs := []NamedCIDR{
+		{Name: "pod CIDR", CIDR: "192.168.0.0/16"},
+		{Name: "service CIDR", CIDR: "10.96.0.0/12"},
+	}
+
+	tests := []struct {
+		name      string
+		nodeCIDRs []NamedCIDR
+		want      []string
+	}{
+		{
+			name:      "Disjoint CIDRs",
+			nodeCIDRs: []NamedCIDR{{Name: "nodeCidr", CIDR: "10.6.0.0/24"}},
+		},
+		{
+			name:      "Node CIDR inside the pod CIDR",
+			nodeCIDRs: []NamedCIDR{{Name: "nodeCidr", CIDR: "192.168.10.0/24"}},
+			want:      []string{"pod CIDR 192.168.0.0/16 overlaps nodeCidr 192.168.10.0/24"},
+		},
+		{
+			name:      "Subnet containing the service CIDR",
+			nodeCIDRs: []NamedCIDR{{Name: "subnet private", CIDR: "10.0.0.0/8"}},
+			want:      []string{"service CIDR 10.96.0.0/12 overlaps subnet private 10.0.0.0/8"},
+		},
+		{
+			name:      "Invalid CIDR",
+			nodeCIDRs: []NamedCIDR{{Name: "nodeCidr", CIDR: "192.168.10.0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ValidateCIDRs(clusterCIDRs, tt.nodeCIDRs)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestValidateMTU(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(ValidateMTU(infrav1.CNIOverlayVXLAN, 1450)).To(BeEmpty())
+	g.Expect(ValidateMTU(infrav1.CNIOverlayIPIP, 1300)).To(BeEmpty())
+	g.Expect(ValidateMTU(infrav1.CNIOverlayGeneve, 1300)).To(Equal("the Geneve overlay leaves pods an MTU of 1250 on the cluster network with MTU 1300, which is below the minimum of 1280"))
+}
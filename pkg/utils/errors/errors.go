@@ -19,19 +19,82 @@ package errors
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gophercloud/gophercloud"
 )
 
+// IsRetryable returns true if err is an OpenStack API error which may succeed
+// when the request is retried, i.e. the request was rate limited or failed
+// with a server error.
 func IsRetryable(err error) bool {
-	var errUnexpectedResponseCode gophercloud.ErrUnexpectedResponseCode
-	if errors.As(err, &errUnexpectedResponseCode) {
-		statusCode := errUnexpectedResponseCode.GetStatusCode()
-		return statusCode >= 500 && statusCode != http.StatusNotImplemented
+	statusCode, ok := StatusCode(err)
+	if !ok {
+		return false
+	}
+	return statusCode == http.StatusTooManyRequests || (statusCode >= 500 && statusCode != http.StatusNotImplemented)
+}
+
+// IsPermanent returns true if err is an OpenStack API error which will not
+// succeed when the same request is retried, because the request is invalid,
+// forbidden or refers to a resource which does not exist.
+func IsPermanent(err error) bool {
+	statusCode, ok := StatusCode(err)
+	if !ok {
+		return false
+	}
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// IsQuotaExceeded returns true if err is an OpenStack API error which rejected
+// the request because it exceeds a quota of the project.
+func IsQuotaExceeded(err error) bool {
+	var forbidden gophercloud.ErrDefault403
+	if errors.As(err, &forbidden) {
+		return strings.Contains(string(forbidden.Body), "Quota exceeded")
 	}
 	return false
 }
 
+// StatusCode returns the HTTP status code of the OpenStack API error in the
+// chain of err.
+func StatusCode(err error) (int, bool) {
+	var statusCodeError gophercloud.StatusCodeError
+	if errors.As(err, &statusCodeError) {
+		return statusCodeError.GetStatusCode(), true
+	}
+	return 0, false
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of the
+// response of the OpenStack API error in the chain of err. The header may
+// either hold a number of seconds or a date.
+func RetryAfter(err error, now time.Time) (time.Duration, bool) {
+	value := responseHeader(err).Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
 func IsNotFound(err error) bool {
 	var errDefault404 gophercloud.ErrDefault404
 	if errors.As(err, &errDefault404) {
@@ -53,6 +116,13 @@ func IsNotFound(err error) bool {
 	return false
 }
 
+// IsEndpointNotFound returns whether err is returned because the service
+// catalog of the cloud has no endpoint for a service.
+func IsEndpointNotFound(err error) bool {
+	var errEndpointNotFound *gophercloud.ErrEndpointNotFound
+	return errors.As(err, &errEndpointNotFound)
+}
+
 func IsInvalidError(err error) bool {
 	var errDefault400 gophercloud.ErrDefault400
 	if errors.As(err, &errDefault400) {
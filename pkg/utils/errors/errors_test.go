@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+func TestIsRetryableAndIsPermanent(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantRetryable bool
+		wantPermanent bool
+	}{
+		{
+			name:          "rate limited",
+			err:           gophercloud.ErrDefault429{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusTooManyRequests}},
+			wantRetryable: true,
+		},
+		{
+			name:          "wrapped server error",
+			err:           fmt.Errorf("create server: %w", gophercloud.ErrDefault500{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusInternalServerError}}),
+			wantRetryable: true,
+		},
+		{
+			name:          "bad gateway",
+			err:           gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusBadGateway},
+			wantRetryable: true,
+		},
+		{
+			name: "not implemented",
+			err:  gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusNotImplemented},
+		},
+		{
+			name:          "bad request",
+			err:           gophercloud.ErrDefault400{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusBadRequest}},
+			wantPermanent: true,
+		},
+		{
+			name:          "forbidden with request ID",
+			err:           WithRequestID(gophercloud.ErrDefault403{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusForbidden}}, "req-1"),
+			wantPermanent: true,
+		},
+		{
+			name:          "not found",
+			err:           gophercloud.ErrDefault404{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusNotFound}},
+			wantPermanent: true,
+		},
+		{
+			name: "conflict",
+			err:  gophercloud.ErrDefault409{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusConflict}},
+		},
+		{
+			name: "no API error",
+			err:  errors.New("connection refused"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsRetryable(tt.err)).To(Equal(tt.wantRetryable))
+			g.Expect(IsPermanent(tt.err)).To(Equal(tt.wantPermanent))
+		})
+	}
+}
+
+func TestIsQuotaExceeded(t *testing.T) {
+	g := NewWithT(t)
+
+	quotaExceeded := gophercloud.ErrDefault403{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{
+		Actual: http.StatusForbidden,
+		Body:   []byte(`{"forbidden": {"code": 403, "message": "Quota exceeded for cores: Requested 4, but already used 20 of 20 cores"}}`),
+	}}
+	g.Expect(IsQuotaExceeded(fmt.Errorf("create server: %w", quotaExceeded))).To(BeTrue())
+
+	policy := gophercloud.ErrDefault403{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{
+		Actual: http.StatusForbidden,
+		Body:   []byte(`{"forbidden": {"code": 403, "message": "Policy doesn't allow os_compute_api:servers:create to be performed."}}`),
+	}}
+	g.Expect(IsQuotaExceeded(policy)).To(BeFalse())
+	g.Expect(IsQuotaExceeded(errors.New("quota exceeded"))).To(BeFalse())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+		wantOK     bool
+	}{
+		{
+			name:       "seconds",
+			retryAfter: "5",
+			want:       5 * time.Second,
+			wantOK:     true,
+		},
+		{
+			name:       "date",
+			retryAfter: now.Add(time.Minute).Format(http.TimeFormat),
+			want:       time.Minute,
+			wantOK:     true,
+		},
+		{
+			name:       "date in the past",
+			retryAfter: now.Add(-time.Minute).Format(http.TimeFormat),
+			wantOK:     true,
+		},
+		{
+			name: "missing",
+		},
+		{
+			name:       "invalid",
+			retryAfter: "soon",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			header := http.Header{}
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+			err := gophercloud.ErrDefault429{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: http.StatusTooManyRequests, ResponseHeader: header}}
+
+			delay, ok := RetryAfter(err, now)
+			g.Expect(ok).To(Equal(tt.wantOK))
+			g.Expect(delay).To(Equal(tt.want))
+		})
+	}
+}
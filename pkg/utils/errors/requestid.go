@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// RequestIDError is an error returned by OpenStack for the request with the
+// given ID.
+type RequestIDError struct {
+	Err       error
+	RequestID string
+}
+
+func (e *RequestIDError) Error() string {
+	return fmt.Sprintf("%v (request %s)", e.Err, e.RequestID)
+}
+
+func (e *RequestIDError) Unwrap() error {
+	return e.Err
+}
+
+// WithRequestID annotates err with the ID of the OpenStack request which
+// failed, so that it is part of the error message. The ID in the response
+// headers held by err takes precedence over requestID. err is returned
+// unchanged if it is nil, already annotated or no request ID is known.
+func WithRequestID(err error, requestID string) error {
+	var requestIDError *RequestIDError
+	if err == nil || errors.As(err, &requestIDError) {
+		return err
+	}
+	if id := responseRequestID(err); id != "" {
+		requestID = id
+	}
+	if requestID == "" {
+		return err
+	}
+	return &RequestIDError{Err: err, RequestID: requestID}
+}
+
+// RequestID returns the ID of the failed OpenStack request err originates
+// from, or an empty string if it is not known.
+func RequestID(err error) string {
+	var requestIDError *RequestIDError
+	if errors.As(err, &requestIDError) {
+		return requestIDError.RequestID
+	}
+	return responseRequestID(err)
+}
+
+func responseRequestID(err error) string {
+	header := responseHeader(err)
+	for _, h := range []string{"X-Openstack-Request-Id", "X-Compute-Request-Id"} {
+		if id := header.Get(h); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// responseHeader returns the response headers of the gophercloud error in the
+// chain of err. The typed errors of gophercloud embed ErrUnexpectedResponseCode,
+// so they have to be checked one by one.
+func responseHeader(err error) http.Header {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case gophercloud.ErrUnexpectedResponseCode:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault400:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault401:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault403:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault404:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault405:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault408:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault409:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault429:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault500:
+			return e.ResponseHeader
+		case gophercloud.ErrDefault503:
+			return e.ResponseHeader
+		}
+	}
+	return nil
+}
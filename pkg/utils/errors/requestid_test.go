@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/gomega"
+)
+
+func TestWithRequestID(t *testing.T) {
+	notFound := gophercloud.ErrDefault404{
+		ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{
+			Actual:         http.StatusNotFound,
+			ResponseHeader: http.Header{"X-Openstack-Request-Id": []string{"req-header"}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		err           error
+		requestID     string
+		wantRequestID string
+		wantMessage   string
+	}{
+		{
+			name:          "request ID of response takes precedence",
+			err:           notFound,
+			requestID:     "req-context",
+			wantRequestID: "req-header",
+			wantMessage:   notFound.Error() + " (request req-header)",
+		},
+		{
+			name:          "request ID is used for errors without response",
+			err:           errors.New("timeout"),
+			requestID:     "req-context",
+			wantRequestID: "req-context",
+			wantMessage:   "timeout (request req-context)",
+		},
+		{
+			name:        "unknown request ID",
+			err:         errors.New("timeout"),
+			wantMessage: "timeout",
+		},
+		{
+			name:          "already annotated",
+			err:           fmt.Errorf("create port: %w", WithRequestID(errors.New("conflict"), "req-1")),
+			requestID:     "req-2",
+			wantRequestID: "req-1",
+			wantMessage:   "create port: conflict (request req-1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := WithRequestID(tt.err, tt.requestID)
+			g.Expect(err).To(MatchError(tt.wantMessage))
+			g.Expect(RequestID(err)).To(Equal(tt.wantRequestID))
+		})
+	}
+
+	g := NewWithT(t)
+	g.Expect(WithRequestID(nil, "req-context")).To(BeNil())
+	g.Expect(IsNotFound(WithRequestID(notFound, ""))).To(BeTrue())
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging defines the verbosity levels of the log messages of CAPO.
+// Changes to OpenStack resources and the progress of a reconcile are logged
+// at the default level 0. Log messages add the IDs of the OpenStack resources
+// they are about, while the logger of a reconcile carries the namespace and
+// the names of the reconciled object, its cluster and its machine.
+package logging
+
+const (
+	// LevelDebug is used for steps which change nothing, e.g. skipped steps
+	// and reused resources.
+	LevelDebug = 4
+	// LevelTrace is used for the details of single OpenStack API calls.
+	LevelTrace = 6
+)
@@ -18,8 +18,43 @@ package names
 
 import (
 	"fmt"
+	"strings"
 )
 
 func GetDescription(clusterName string) string {
 	return fmt.Sprintf("Created by cluster-api-provider-openstack cluster %s", clusterName)
 }
+
+// GetPortDescription returns the description of a port created for the
+// server with the given name, i.e. for the machine of the same name.
+func GetPortDescription(clusterName, serverName string) string {
+	return fmt.Sprintf("%s for server %s", GetDescription(clusterName), serverName)
+}
+
+// GetVolumeDescription returns the description of the root volume created
+// for the server with the given name.
+func GetVolumeDescription(clusterName, serverName string) string {
+	return fmt.Sprintf("Root volume for server %s created by cluster-api-provider-openstack cluster %s", serverName, clusterName)
+}
+
+// HasClusterDescription returns whether the description is the description of
+// a resource created for the cluster, including the ports of its servers.
+func HasClusterDescription(description, clusterName string) bool {
+	return MatchesDescription(description, GetDescription(clusterName))
+}
+
+// MatchesDescription returns whether the description is the given cluster
+// description, or the description of a port of a server of that cluster.
+func MatchesDescription(description, clusterDescription string) bool {
+	return description == clusterDescription || strings.HasPrefix(description, clusterDescription+" for server ")
+}
+
+func GetFloatingIPPoolDescription(namespace, poolName string) string {
+	return fmt.Sprintf("Created by cluster-api-provider-openstack floating IP pool %s/%s", namespace, poolName)
+}
+
+// IsCreatedByCAPO returns whether the description is the description of a
+// resource created by CAPO, for a cluster or a floating IP pool.
+func IsCreatedByCAPO(description string) bool {
+	return strings.HasPrefix(description, "Created by cluster-api-provider-openstack ")
+}
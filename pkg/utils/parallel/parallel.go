@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parallel
+
+import (
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Run calls the given functions concurrently, at most limit of them at a time,
+// and waits until all of them have returned. A limit of 1 or less calls them
+// one after the other in the given order. If a single function fails its error
+// is returned as is, while the errors of several functions are aggregated.
+func Run(limit int, fns ...func() error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	errs := make([]error, len(fns))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, fn := range fns {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, fn func() error) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn()
+		}(i, fn)
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return failed[0]
+	default:
+		return kerrors.NewAggregate(failed)
+	}
+}
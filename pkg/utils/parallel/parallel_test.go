@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parallel
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRun(t *testing.T) {
+	g := NewWithT(t)
+
+	var mu sync.Mutex
+	running, maxRunning, calls := 0, 0, 0
+	fn := func() error {
+		mu.Lock()
+		running++
+		calls++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+
+	g.Expect(Run(2, fn, fn, fn, fn, fn)).To(Succeed())
+	g.Expect(calls).To(Equal(5))
+	g.Expect(maxRunning).To(BeNumerically("<=", 2))
+}
+
+func TestRun_sequential(t *testing.T) {
+	g := NewWithT(t)
+
+	var order []int
+	fns := make([]func() error, 5)
+	for i := range fns {
+		i := i
+		fns[i] = func() error {
+			order = append(order, i)
+			return nil
+		}
+	}
+
+	g.Expect(Run(1, fns...)).To(Succeed())
+	g.Expect(order).To(Equal([]int{0, 1, 2, 3, 4}))
+}
+
+func TestRun_errors(t *testing.T) {
+	g := NewWithT(t)
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+	ok := func() error { return nil }
+
+	g.Expect(Run(4, ok, ok)).To(Succeed())
+	g.Expect(Run(4, ok, func() error { return errA }, ok)).To(BeIdenticalTo(errA))
+
+	err := Run(4, func() error { return errA }, ok, func() error { return errB })
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("a"))
+	g.Expect(err.Error()).To(ContainSubstring("b"))
+}
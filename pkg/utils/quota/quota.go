@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota compares the resources required to create OpenStack
+// resources with what is left in the quotas of a project.
+package quota
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Usage is the quota of a resource of a project and how much of it is
+// required by an operation.
+type Usage struct {
+	// Resource names the resource, e.g. compute.cores.
+	Resource string
+	// Limit is the quota of the resource. A negative limit means there is no
+	// limit.
+	Limit int
+	// InUse is the amount of the resource which is in use or reserved.
+	InUse int
+	// Required is the amount of the resource required by the operation.
+	Required int
+}
+
+// IsExhausted returns true if less than the required amount of the resource
+// is left.
+func (u Usage) IsExhausted() bool {
+	if u.Required <= 0 || u.Limit < 0 {
+		return false
+	}
+	return u.InUse+u.Required > u.Limit
+}
+
+// Percent returns the percentage of the quota of the resource which is in
+// use, and false if the resource has no limit.
+func (u Usage) Percent() (float64, bool) {
+	if u.Limit < 0 {
+		return 0, false
+	}
+	if u.Limit == 0 {
+		if u.InUse > 0 {
+			return 100, true
+		}
+		return 0, true
+	}
+	return float64(u.InUse) * 100 / float64(u.Limit), true
+}
+
+func (u Usage) String() string {
+	return fmt.Sprintf("%s (%d required, %d of %d used)", u.Resource, u.Required, u.InUse, u.Limit)
+}
+
+// Exhausted returns the usages of the resources of which less than the
+// required amount is left.
+func Exhausted(usages ...Usage) []Usage {
+	var exhausted []Usage
+	for _, usage := range usages {
+		if usage.IsExhausted() {
+			exhausted = append(exhausted, usage)
+		}
+	}
+	return exhausted
+}
+
+// Message lists the exhausted resources in a message suitable for a
+// condition.
+func Message(exhausted []Usage) string {
+	resources := make([]string, 0, len(exhausted))
+	for _, usage := range exhausted {
+		resources = append(resources, usage.String())
+	}
+	return "insufficient quota for " + strings.Join(resources, ", ")
+}
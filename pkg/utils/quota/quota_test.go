@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUsageIsExhausted(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage Usage
+		want  bool
+	}{
+		{
+			name:  "Enough left",
+			usage: Usage{Resource: "compute.cores", Limit: 10, InUse: 6, Required: 4},
+			want:  false,
+		},
+		{
+			name:  "Not enough left",
+			usage: Usage{Resource: "compute.cores", Limit: 10, InUse: 7, Required: 4},
+			want:  true,
+		},
+		{
+			name:  "Unlimited",
+			usage: Usage{Resource: "compute.cores", Limit: -1, InUse: 100, Required: 4},
+			want:  false,
+		},
+		{
+			name:  "Nothing required",
+			usage: Usage{Resource: "compute.cores", Limit: 10, InUse: 12, Required: 0},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tt.usage.IsExhausted()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestUsagePercent(t *testing.T) {
+	g := NewWithT(t)
+
+	percent, ok := Usage{Limit: 50, InUse: 40}.Percent()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(percent).To(Equal(float64(80)))
+
+	percent, ok = Usage{Limit: 0}.Percent()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(percent).To(Equal(float64(0)))
+
+	_, ok = Usage{Limit: -1, InUse: 40}.Percent()
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestMessage(t *testing.T) {
+	g := NewWithT(t)
+
+	exhausted := Exhausted(
+		Usage{Resource: "compute.instances", Limit: 10, InUse: 10, Required: 1},
+		Usage{Resource: "compute.cores", Limit: 40, InUse: 20, Required: 4},
+		Usage{Resource: "network.ports", Limit: 50, InUse: 49, Required: 2},
+	)
+	g.Expect(Message(exhausted)).To(Equal("insufficient quota for compute.instances (1 required, 10 of 10 used), network.ports (2 required, 49 of 50 used)"))
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// +kubebuilder:webhook:verbs=delete,path=/validate-cluster-x-k8s-io-v1beta1-cluster-deletion-protection,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=cluster.x-k8s.io,resources=clusters,versions=v1beta1,name=deletionprotection.cluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// ClusterDeletionProtectionValidator denies the deletion of Clusters whose
+// OpenStackCluster is deletion protected. Cluster API deletes the machines of
+// a cluster before its infrastructure cluster, so the deletion must be denied
+// on the Cluster to keep the whole cluster. The webhook fails closed, so
+// that no Cluster is deleted while it is unavailable.
+type ClusterDeletionProtectionValidator struct {
+	Client client.Client
+
+	decoder *admission.Decoder
+}
+
+func (v *ClusterDeletionProtectionValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register("/validate-cluster-x-k8s-io-v1beta1-cluster-deletion-protection", &webhook.Admission{Handler: v})
+	return nil
+}
+
+var _ admission.DecoderInjector = &ClusterDeletionProtectionValidator{}
+
+// InjectDecoder injects the decoder.
+func (v *ClusterDeletionProtectionValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle denies the deletion of the Cluster if its OpenStackCluster is
+// deletion protected.
+func (v *ClusterDeletionProtectionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	log := ctrl.LoggerFrom(ctx)
+
+	cluster := &clusterv1.Cluster{}
+	if err := v.decoder.DecodeRaw(req.OldObject, cluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "OpenStackCluster" {
+		return admission.Allowed("")
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	if err := v.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, openStackCluster); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "Failed to get the OpenStackCluster, allowing the deletion of the cluster", "cluster", cluster.Name)
+		}
+		return admission.Allowed("")
+	}
+
+	if openStackCluster.IsDeletionProtected() {
+		return admission.Denied(fmt.Sprintf("OpenStackCluster %s is protected by the %s annotation: set its %s annotation to %q to confirm the deletion of the cluster",
+			openStackCluster.Name, infrav1.DeletionProtectedAnnotation, infrav1.ConfirmDeletionAnnotation, openStackCluster.Name))
+	}
+	return admission.Allowed("")
+}
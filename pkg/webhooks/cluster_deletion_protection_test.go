@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestClusterDeletionProtectionValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := infrav1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func(kind string) admission.Request {
+		raw, err := json.Marshal(&clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+			Spec: clusterv1.ClusterSpec{
+				InfrastructureRef: &corev1.ObjectReference{Kind: kind, Name: "openstack-cluster"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Delete,
+			OldObject: runtime.RawExtension{Raw: raw},
+		}}
+	}
+	openStackCluster := func(annotations map[string]string) *infrav1.OpenStackCluster {
+		return &infrav1.OpenStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "openstack-cluster", Namespace: "default", Annotations: annotations},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		kind             string
+		openStackCluster *infrav1.OpenStackCluster
+		wantAllowed      bool
+	}{
+		{
+			name:             "Not protected",
+			kind:             "OpenStackCluster",
+			openStackCluster: openStackCluster(nil),
+			wantAllowed:      true,
+		},
+		{
+			name:             "Protected",
+			kind:             "OpenStackCluster",
+			openStackCluster: openStackCluster(map[string]string{infrav1.DeletionProtectedAnnotation: "true"}),
+			wantAllowed:      false,
+		},
+		{
+			name: "Protected with wrong confirmation",
+			kind: "OpenStackCluster",
+			openStackCluster: openStackCluster(map[string]string{
+				infrav1.DeletionProtectedAnnotation: "true",
+				infrav1.ConfirmDeletionAnnotation:   "cluster",
+			}),
+			wantAllowed: false,
+		},
+		{
+			name: "Protected with confirmation",
+			kind: "OpenStackCluster",
+			openStackCluster: openStackCluster(map[string]string{
+				infrav1.DeletionProtectedAnnotation: "true",
+				infrav1.ConfirmDeletionAnnotation:   "openstack-cluster",
+			}),
+			wantAllowed: true,
+		},
+		{
+			name:        "OpenStackCluster not found",
+			kind:        "OpenStackCluster",
+			wantAllowed: true,
+		},
+		{
+			name:             "Other infrastructure provider",
+			kind:             "DockerCluster",
+			openStackCluster: openStackCluster(map[string]string{infrav1.DeletionProtectedAnnotation: "true"}),
+			wantAllowed:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objects := []client.Object{}
+			if tt.openStackCluster != nil {
+				objects = append(objects, tt.openStackCluster)
+			}
+			v := &ClusterDeletionProtectionValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			resp := v.Handle(context.TODO(), newRequest(tt.kind))
+			g.Expect(resp.Allowed).To(Equal(tt.wantAllowed))
+		})
+	}
+}
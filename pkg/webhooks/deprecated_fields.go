@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-deprecated-fields,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters;openstackclustertemplates;openstackmachines;openstackmachinetemplates,versions=v1alpha7,name=deprecatedfields.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// DeprecatedFieldsValidator warns on admission about the fields of an object
+// which are deprecated in v1alpha7, naming the fields replacing them. Objects
+// of older API versions are converted to v1alpha7 before they are validated,
+// so their deprecated fields are reported with their v1alpha7 path. On update
+// only deprecated fields which were not set before are reported.
+type DeprecatedFieldsValidator struct {
+	decoder *admission.Decoder
+}
+
+func (v *DeprecatedFieldsValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1alpha7-deprecated-fields", &webhook.Admission{Handler: v})
+	return nil
+}
+
+var _ admission.DecoderInjector = &DeprecatedFieldsValidator{}
+
+// InjectDecoder injects the decoder.
+func (v *DeprecatedFieldsValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle admits the object with a warning for each deprecated field it sets.
+func (v *DeprecatedFieldsValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	warnings, err := v.deprecatedFields(req.Kind.Kind, req.Object)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if req.Operation == admissionv1.Update && len(warnings) > 0 {
+		oldWarnings, err := v.deprecatedFields(req.Kind.Kind, req.OldObject)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		warnings = withoutWarnings(warnings, oldWarnings)
+	}
+
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// deprecatedFields returns a warning for each deprecated field set in the
+// raw object of the given kind.
+func (v *DeprecatedFieldsValidator) deprecatedFields(kind string, raw runtime.RawExtension) ([]string, error) {
+	switch kind {
+	case "OpenStackCluster":
+		openStackCluster := &infrav1.OpenStackCluster{}
+		if err := v.decoder.DecodeRaw(raw, openStackCluster); err != nil {
+			return nil, err
+		}
+		return deprecatedClusterSpecFields(&openStackCluster.Spec, field.NewPath("spec")), nil
+	case "OpenStackClusterTemplate":
+		openStackClusterTemplate := &infrav1.OpenStackClusterTemplate{}
+		if err := v.decoder.DecodeRaw(raw, openStackClusterTemplate); err != nil {
+			return nil, err
+		}
+		return deprecatedClusterSpecFields(&openStackClusterTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec")), nil
+	case "OpenStackMachine":
+		openStackMachine := &infrav1.OpenStackMachine{}
+		if err := v.decoder.DecodeRaw(raw, openStackMachine); err != nil {
+			return nil, err
+		}
+		return deprecatedMachineSpecFields(&openStackMachine.Spec, field.NewPath("spec")), nil
+	case "OpenStackMachineTemplate":
+		openStackMachineTemplate := &infrav1.OpenStackMachineTemplate{}
+		if err := v.decoder.DecodeRaw(raw, openStackMachineTemplate); err != nil {
+			return nil, err
+		}
+		return deprecatedMachineSpecFields(&openStackMachineTemplate.Spec.Template.Spec, field.NewPath("spec", "template", "spec")), nil
+	}
+	return nil, nil
+}
+
+// deprecatedClusterSpecFields returns a warning for each deprecated field set
+// in the spec of an OpenStackCluster.
+func deprecatedClusterSpecFields(spec *infrav1.OpenStackClusterSpec, path *field.Path) []string {
+	if spec.Bastion == nil {
+		return nil
+	}
+	return deprecatedMachineSpecFields(&spec.Bastion.Instance, path.Child("bastion", "instance"))
+}
+
+// deprecatedMachineSpecFields returns a warning for each deprecated field set
+// in the spec of an OpenStackMachine. Networks are replaced by ports, so each
+// field of a network names the field of a port taking its place.
+func deprecatedMachineSpecFields(spec *infrav1.OpenStackMachineSpec, path *field.Path) []string {
+	var warnings []string
+	ports := path.Child("ports").String() + "[]"
+	for i := range spec.Networks {
+		network := &spec.Networks[i]
+		networkPath := path.Child("networks").Index(i)
+
+		var replaced []string
+		if network.UUID != "" {
+			replaced = append(replaced, deprecatedFieldWarning(networkPath.Child("uuid"), ports+".network.id"))
+		}
+		if !network.Filter.IsEmpty() {
+			replaced = append(replaced, deprecatedFieldWarning(networkPath.Child("filter"), ports+".network"))
+		}
+		if network.FixedIP != "" {
+			replaced = append(replaced, deprecatedFieldWarning(networkPath.Child("fixedIP"), ports+".fixedIPs[].ipAddress"))
+		}
+		if len(network.Subnets) > 0 {
+			replaced = append(replaced, deprecatedFieldWarning(networkPath.Child("subnets"), ports+".fixedIPs[].subnet"))
+		}
+		if len(replaced) == 0 {
+			replaced = append(replaced, deprecatedFieldWarning(networkPath, ports))
+		}
+		warnings = append(warnings, replaced...)
+	}
+	return warnings
+}
+
+func deprecatedFieldWarning(path *field.Path, replacement string) string {
+	return fmt.Sprintf("%s is deprecated, use %s instead", path, replacement)
+}
+
+// withoutWarnings returns the warnings which are not in the given list of
+// warnings to leave out.
+func withoutWarnings(warnings, leaveOut []string) []string {
+	leftOut := make(map[string]bool, len(leaveOut))
+	for _, warning := range leaveOut {
+		leftOut[warning] = true
+	}
+
+	var remaining []string
+	for _, warning := range warnings {
+		if !leftOut[warning] {
+			remaining = append(remaining, warning)
+		}
+	}
+	return remaining
+}
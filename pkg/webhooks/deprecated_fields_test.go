@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestDeprecatedMachineSpecFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks []infrav1.NetworkParam
+		want     []string
+	}{
+		{
+			name: "No networks",
+		},
+		{
+			name: "Network by UUID with a fixed IP",
+			networks: []infrav1.NetworkParam{
+				{UUID: "network-id", FixedIP: "10.0.0.10"},
+			},
+			want: []string{
+				"spec.template.spec.networks[0].uuid is deprecated, use spec.template.spec.ports[].network.id instead",
+				"spec.template.spec.networks[0].fixedIP is deprecated, use spec.template.spec.ports[].fixedIPs[].ipAddress instead",
+			},
+		},
+		{
+			name: "Network by filter with subnets",
+			networks: []infrav1.NetworkParam{
+				{},
+				{Filter: infrav1.NetworkFilter{Name: "private"}, Subnets: []infrav1.SubnetParam{{UUID: "subnet-id"}}},
+			},
+			want: []string{
+				"spec.template.spec.networks[0] is deprecated, use spec.template.spec.ports[] instead",
+				"spec.template.spec.networks[1].filter is deprecated, use spec.template.spec.ports[].network instead",
+				"spec.template.spec.networks[1].subnets is deprecated, use spec.template.spec.ports[].fixedIPs[].subnet instead",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			spec := &infrav1.OpenStackMachineSpec{Networks: tt.networks}
+			g.Expect(deprecatedMachineSpecFields(spec, field.NewPath("spec", "template", "spec"))).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDeprecatedFieldsValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := infrav1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawCluster := func(networks ...infrav1.NetworkParam) runtime.RawExtension {
+		raw, err := json.Marshal(&infrav1.OpenStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+			Spec: infrav1.OpenStackClusterSpec{
+				Bastion: &infrav1.Bastion{
+					Instance: infrav1.OpenStackMachineSpec{Networks: networks},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runtime.RawExtension{Raw: raw}
+	}
+	kind := metav1.GroupVersionKind{Group: infrav1.GroupVersion.Group, Version: infrav1.GroupVersion.Version, Kind: "OpenStackCluster"}
+
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		object    runtime.RawExtension
+		oldObject runtime.RawExtension
+		want      []string
+	}{
+		{
+			name:      "Create without deprecated fields",
+			operation: admissionv1.Create,
+			object:    rawCluster(),
+		},
+		{
+			name:      "Create with deprecated fields",
+			operation: admissionv1.Create,
+			object:    rawCluster(infrav1.NetworkParam{UUID: "network-id"}),
+			want:      []string{"spec.bastion.instance.networks[0].uuid is deprecated, use spec.bastion.instance.ports[].network.id instead"},
+		},
+		{
+			name:      "Update of unchanged deprecated fields",
+			operation: admissionv1.Update,
+			object:    rawCluster(infrav1.NetworkParam{UUID: "network-id"}),
+			oldObject: rawCluster(infrav1.NetworkParam{UUID: "network-id"}),
+		},
+		{
+			name:      "Update setting deprecated fields",
+			operation: admissionv1.Update,
+			object:    rawCluster(infrav1.NetworkParam{UUID: "network-id", FixedIP: "10.0.0.10"}),
+			oldObject: rawCluster(infrav1.NetworkParam{UUID: "network-id"}),
+			want:      []string{"spec.bastion.instance.networks[0].fixedIP is deprecated, use spec.bastion.instance.ports[].fixedIPs[].ipAddress instead"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			v := &DeprecatedFieldsValidator{}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			resp := v.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Kind:      kind,
+				Operation: tt.operation,
+				Object:    tt.object,
+				OldObject: tt.oldObject,
+			}})
+			g.Expect(resp.Allowed).To(BeTrue())
+			g.Expect(resp.Warnings).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import "fmt"
+
+// ValidationMode defines what happens when an object fails the validation of
+// an optional webhook.
+type ValidationMode string
+
+const (
+	// ValidationDisabled disables the validation.
+	ValidationDisabled ValidationMode = "disabled"
+	// ValidationWarn admits the object with warnings.
+	ValidationWarn ValidationMode = "warn"
+	// ValidationReject rejects the object.
+	ValidationReject ValidationMode = "reject"
+)
+
+// ParseValidationMode returns the ValidationMode for the given string.
+func ParseValidationMode(mode string) (ValidationMode, error) {
+	switch m := ValidationMode(mode); m {
+	case ValidationDisabled, ValidationWarn, ValidationReject:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid validation mode %q: must be one of %s, %s or %s", mode,
+		ValidationDisabled, ValidationWarn, ValidationReject)
+}
@@ -14,22 +14,23 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package v1alpha6
+package webhooks
 
 import (
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/validation/field"
+	"testing"
+
+	. "github.com/onsi/gomega"
 )
 
-func aggregateObjErrors(gk schema.GroupKind, name string, allErrs field.ErrorList) error {
-	if len(allErrs) == 0 {
-		return nil
+func TestParseValidationMode(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, mode := range []string{"disabled", "warn", "reject"} {
+		got, err := ParseValidationMode(mode)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(string(got)).To(Equal(mode))
 	}
 
-	return apierrors.NewInvalid(
-		gk,
-		name,
-		allErrs,
-	)
+	_, err := ParseValidationMode("invalid")
+	g.Expect(err).To(HaveOccurred())
 }
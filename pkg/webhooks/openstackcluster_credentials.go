@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/loadbalancer"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/networking"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackcluster-credentials,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,versions=v1alpha7,name=credentials.openstackcluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// OpenStackClusterCredentialValidator authenticates against the cloud of an
+// OpenStackCluster on admission and probes the services and quotas required
+// to create the cluster.
+type OpenStackClusterCredentialValidator struct {
+	Client client.Client
+	Mode   ValidationMode
+
+	decoder *admission.Decoder
+}
+
+func (v *OpenStackClusterCredentialValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackcluster-credentials", &webhook.Admission{Handler: v})
+	return nil
+}
+
+var _ admission.DecoderInjector = &OpenStackClusterCredentialValidator{}
+
+// InjectDecoder injects the decoder.
+func (v *OpenStackClusterCredentialValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the credentials of the OpenStackCluster.
+func (v *OpenStackClusterCredentialValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if v.Mode == "" || v.Mode == ValidationDisabled {
+		return admission.Allowed("")
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	if err := v.decoder.Decode(req, openStackCluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !openStackCluster.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	// clusterctl move recreates objects with their finalizers, possibly
+	// before the identity secret has been moved
+	if req.Operation == admissionv1.Create && controllerutil.ContainsFinalizer(openStackCluster, infrav1.ClusterFinalizer) {
+		return admission.Allowed("")
+	}
+
+	// Only validate on update if the credentials have changed
+	if req.Operation == admissionv1.Update {
+		old := &infrav1.OpenStackCluster{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if reflect.DeepEqual(old.Spec.IdentityRef, openStackCluster.Spec.IdentityRef) && old.Spec.CloudName == openStackCluster.Spec.CloudName {
+			return admission.Allowed("")
+		}
+	}
+
+	problems := v.validate(ctx, openStackCluster)
+	if len(problems) == 0 {
+		return admission.Allowed("")
+	}
+
+	if v.Mode == ValidationReject {
+		return admission.Denied(fmt.Sprintf("OpenStackCluster credential validation failed: %s", strings.Join(problems, "; ")))
+	}
+	return admission.Allowed("").WithWarnings(problems...)
+}
+
+// validate returns a list of problems found with the credentials of the OpenStackCluster.
+func (v *OpenStackClusterCredentialValidator) validate(ctx context.Context, openStackCluster *infrav1.OpenStackCluster) []string {
+	log := ctrl.LoggerFrom(ctx).WithValues("openStackCluster", openStackCluster.Name)
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromCluster(ctx, v.Client, openStackCluster)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to authenticate with cloud %q: %v", openStackCluster.Spec.CloudName, err)}
+	}
+
+	scope := &scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+	}
+
+	var problems []string
+
+	computeService, err := compute.NewService(scope)
+	if err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		quotaSet, err := computeService.GetQuotaSet()
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("compute service is not reachable: %v", err))
+		case quotaSet.Instances == 0 || quotaSet.Cores == 0 || quotaSet.RAM == 0:
+			problems = append(problems, fmt.Sprintf("compute quota of project %s does not allow creating servers: instances=%d cores=%d ram=%d",
+				projectID, quotaSet.Instances, quotaSet.Cores, quotaSet.RAM))
+		}
+	}
+
+	networkingService, err := networking.NewService(scope)
+	if err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		quota, err := networkingService.GetQuota()
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("networking service is not reachable: %v", err))
+		case quota.Port == 0:
+			problems = append(problems, fmt.Sprintf("network quota of project %s does not allow creating ports", projectID))
+		case openStackCluster.Spec.NodeCIDR != "" && (quota.Network == 0 || quota.Subnet == 0 || quota.Router == 0):
+			problems = append(problems, fmt.Sprintf("network quota of project %s does not allow creating the cluster network: networks=%d subnets=%d routers=%d",
+				projectID, quota.Network, quota.Subnet, quota.Router))
+		case openStackCluster.Spec.ManagedSecurityGroups != nil && quota.SecurityGroup == 0:
+			problems = append(problems, fmt.Sprintf("network quota of project %s does not allow creating security groups", projectID))
+		}
+	}
+
+	if openStackCluster.Spec.APIServerLoadBalancer.Enabled {
+		loadBalancerService, err := loadbalancer.NewService(scope)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if _, err := loadBalancerService.GetLoadBalancerProviders(); err != nil {
+			problems = append(problems, fmt.Sprintf("load balancer service is not reachable: %v", err))
+		}
+	}
+
+	return problems
+}
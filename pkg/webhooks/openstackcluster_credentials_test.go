@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestOpenStackClusterCredentialValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := infrav1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := &infrav1.OpenStackCluster{
+		Spec: infrav1.OpenStackClusterSpec{
+			CloudName: "openstack",
+			IdentityRef: &infrav1.OpenStackIdentityReference{
+				Kind: "Secret",
+				Name: "cloud-config",
+			},
+		},
+	}
+	raw, err := json.Marshal(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	movedCluster := cluster.DeepCopy()
+	movedCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+	movedRaw, err := json.Marshal(movedCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		mode ValidationMode
+		req  admissionv1.AdmissionRequest
+	}{
+		{
+			name: "Validation disabled",
+			mode: ValidationDisabled,
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		},
+		{
+			name: "Update without credential change",
+			mode: ValidationReject,
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    runtime.RawExtension{Raw: raw},
+				OldObject: runtime.RawExtension{Raw: raw},
+			},
+		},
+		{
+			name: "Create by clusterctl move",
+			mode: ValidationReject,
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: movedRaw},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			v := &OpenStackClusterCredentialValidator{Mode: tt.mode}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			resp := v.Handle(context.TODO(), admission.Request{AdmissionRequest: tt.req})
+			g.Expect(resp.Allowed).To(BeTrue())
+			g.Expect(resp.Warnings).To(BeEmpty())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/clusternetwork"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackcluster-network,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackclusters,versions=v1alpha7,name=network.openstackcluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// OpenStackClusterNetworkValidator checks on admission that the pod and
+// service CIDRs of the Cluster owning an OpenStackCluster do not overlap the
+// subnets of its nodes. It does not call OpenStack: existing subnets are only
+// checked once they are in the status of the OpenStackCluster, and the MTU of
+// the cluster network is only checked by the OpenStackCluster controller,
+// which also reports conflicts with CIDRs of Clusters changed later.
+type OpenStackClusterNetworkValidator struct {
+	Client client.Client
+	Mode   ValidationMode
+
+	decoder *admission.Decoder
+}
+
+func (v *OpenStackClusterNetworkValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackcluster-network", &webhook.Admission{Handler: v})
+	return nil
+}
+
+var _ admission.DecoderInjector = &OpenStackClusterNetworkValidator{}
+
+// InjectDecoder injects the decoder.
+func (v *OpenStackClusterNetworkValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the network configuration of the OpenStackCluster.
+func (v *OpenStackClusterNetworkValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if v.Mode == "" || v.Mode == ValidationDisabled {
+		return admission.Allowed("")
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	if err := v.decoder.Decode(req, openStackCluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !openStackCluster.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+	if req.Operation == admissionv1.Create && controllerutil.ContainsFinalizer(openStackCluster, infrav1.ClusterFinalizer) {
+		return admission.Allowed("")
+	}
+
+	// Only validate on update if the network configuration has changed
+	if req.Operation == admissionv1.Update {
+		old := &infrav1.OpenStackCluster{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if old.Spec.NodeCIDR == openStackCluster.Spec.NodeCIDR &&
+			reflect.DeepEqual(old.Spec.Network, openStackCluster.Spec.Network) &&
+			reflect.DeepEqual(old.Spec.Subnet, openStackCluster.Spec.Subnet) &&
+			reflect.DeepEqual(old.Spec.FailureDomains, openStackCluster.Spec.FailureDomains) {
+			return admission.Allowed("")
+		}
+	}
+
+	problems := v.validate(ctx, openStackCluster)
+	if len(problems) == 0 {
+		return admission.Allowed("")
+	}
+
+	if v.Mode == ValidationReject {
+		return admission.Denied(fmt.Sprintf("OpenStackCluster network validation failed: %s", strings.Join(problems, "; ")))
+	}
+	return admission.Allowed("").WithWarnings(problems...)
+}
+
+// validate returns a list of problems found with the network configuration of
+// the OpenStackCluster. The pod and service CIDRs are only checked if the
+// Cluster exists.
+func (v *OpenStackClusterNetworkValidator) validate(ctx context.Context, openStackCluster *infrav1.OpenStackCluster) []string {
+	cluster, err := v.getCluster(ctx, openStackCluster)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Failed to get the Cluster of the OpenStackCluster, skipping validation of its pod and service CIDRs", "openStackCluster", openStackCluster.Name)
+		return nil
+	}
+	return clusternetwork.ValidateCIDRs(clusternetwork.ClusterCIDRs(cluster), clusternetwork.NodeCIDRs(openStackCluster))
+}
+
+// getCluster returns the Cluster of the OpenStackCluster, or nil if there is
+// none yet.
+func (v *OpenStackClusterNetworkValidator) getCluster(ctx context.Context, openStackCluster *infrav1.OpenStackCluster) (*clusterv1.Cluster, error) {
+	cluster, err := util.GetOwnerCluster(ctx, v.Client, openStackCluster.ObjectMeta)
+	if err != nil || cluster != nil {
+		return cluster, err
+	}
+
+	// The Cluster only becomes the owner once it has been reconciled, so
+	// look for the Cluster referencing the OpenStackCluster on creation
+	clusterList := &clusterv1.ClusterList{}
+	if err := v.Client.List(ctx, clusterList, client.InNamespace(openStackCluster.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range clusterList.Items {
+		ref := clusterList.Items[i].Spec.InfrastructureRef
+		if ref != nil && ref.Kind == "OpenStackCluster" && ref.Name == openStackCluster.Name {
+			return &clusterList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestOpenStackClusterNetworkValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := infrav1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: &clusterv1.ClusterNetwork{
+				Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+			},
+			InfrastructureRef: &corev1.ObjectReference{Kind: "OpenStackCluster", Name: "cluster"},
+		},
+	}
+	newRequest := func(nodeCIDR string) admission.Request {
+		raw, err := json.Marshal(&infrav1.OpenStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+			Spec:       infrav1.OpenStackClusterSpec{NodeCIDR: nodeCIDR},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	tests := []struct {
+		name         string
+		mode         ValidationMode
+		nodeCIDR     string
+		wantAllowed  bool
+		wantWarnings []string
+	}{
+		{
+			name:        "Validation disabled",
+			mode:        ValidationDisabled,
+			nodeCIDR:    "192.168.1.0/24",
+			wantAllowed: true,
+		},
+		{
+			name:        "Disjoint CIDRs",
+			mode:        ValidationReject,
+			nodeCIDR:    "10.6.0.0/24",
+			wantAllowed: true,
+		},
+		{
+			name:         "Overlapping CIDRs with warnings",
+			mode:         ValidationWarn,
+			nodeCIDR:     "192.168.1.0/24",
+			wantAllowed:  true,
+			wantWarnings: []string{"pod CIDR 192.168.0.0/16 overlaps nodeCidr 192.168.1.0/24"},
+		},
+		{
+			name:        "Overlapping CIDRs rejected",
+			mode:        ValidationReject,
+			nodeCIDR:    "192.168.1.0/24",
+			wantAllowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			v := &OpenStackClusterNetworkValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster.DeepCopy()).Build(),
+				Mode:   tt.mode,
+			}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			resp := v.Handle(context.TODO(), newRequest(tt.nodeCIDR))
+			g.Expect(resp.Allowed).To(Equal(tt.wantAllowed))
+			g.Expect(resp.Warnings).To(Equal(tt.wantWarnings))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/scope"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackmachinetemplate-resources,mutating=false,failurePolicy=ignore,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=openstackmachinetemplates,versions=v1alpha7,name=resources.openstackmachinetemplate.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// OpenStackMachineTemplateResourceValidator checks on admission that the
+// flavor, image, networks and security groups referenced by an
+// OpenStackMachineTemplate exist in the cloud of its cluster, so that typos
+// are reported immediately instead of by machines which never get a server.
+type OpenStackMachineTemplateResourceValidator struct {
+	Client client.Client
+	Mode   ValidationMode
+
+	decoder *admission.Decoder
+}
+
+func (v *OpenStackMachineTemplateResourceValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1alpha7-openstackmachinetemplate-resources", &webhook.Admission{Handler: v})
+	return nil
+}
+
+var _ admission.DecoderInjector = &OpenStackMachineTemplateResourceValidator{}
+
+// InjectDecoder injects the decoder.
+func (v *OpenStackMachineTemplateResourceValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the resources referenced by the OpenStackMachineTemplate.
+func (v *OpenStackMachineTemplateResourceValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if v.Mode == "" || v.Mode == ValidationDisabled {
+		return admission.Allowed("")
+	}
+
+	openStackMachineTemplate := &infrav1.OpenStackMachineTemplate{}
+	if err := v.decoder.Decode(req, openStackMachineTemplate); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !openStackMachineTemplate.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+
+	// Only validate on update if the machine spec has changed
+	if req.Operation == admissionv1.Update {
+		old := &infrav1.OpenStackMachineTemplate{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if reflect.DeepEqual(old.Spec, openStackMachineTemplate.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	problems := v.validate(ctx, openStackMachineTemplate)
+	if len(problems) == 0 {
+		return admission.Allowed("")
+	}
+
+	if v.Mode == ValidationReject {
+		return admission.Denied(fmt.Sprintf("OpenStackMachineTemplate resource validation failed: %s", strings.Join(problems, "; ")))
+	}
+	return admission.Allowed("").WithWarnings(problems...)
+}
+
+// validate returns a problem for every resource referenced by the
+// OpenStackMachineTemplate which does not exist. The validation is skipped if
+// the OpenStackCluster of the template, whose credentials are used for the
+// lookups, does not exist yet, e.g. for the templates of a ClusterClass, or
+// the cloud is not reachable.
+func (v *OpenStackMachineTemplateResourceValidator) validate(ctx context.Context, openStackMachineTemplate *infrav1.OpenStackMachineTemplate) []string {
+	log := ctrl.LoggerFrom(ctx).WithValues("openStackMachineTemplate", openStackMachineTemplate.Name)
+
+	openStackCluster, err := v.getOpenStackCluster(ctx, openStackMachineTemplate)
+	if err != nil {
+		log.Error(err, "Failed to get the OpenStackCluster of the OpenStackMachineTemplate, skipping validation of its resources")
+		return nil
+	}
+	if openStackCluster == nil {
+		return nil
+	}
+
+	osProviderClient, clientOpts, projectID, err := provider.NewClientFromMachineTemplate(ctx, v.Client, openStackCluster, openStackMachineTemplate)
+	if err != nil {
+		log.Error(err, "Failed to authenticate with the cloud, skipping validation of the resources")
+		return nil
+	}
+	computeService, err := compute.NewService(&scope.Scope{
+		ProviderClient:     osProviderClient,
+		ProviderClientOpts: clientOpts,
+		ProjectID:          projectID,
+		Logger:             log,
+	})
+	if err != nil {
+		log.Error(err, "Failed to connect to the compute service, skipping validation of the resources")
+		return nil
+	}
+
+	machineSpec := openStackCluster.Spec.MachineSpecWithDefaults(&openStackMachineTemplate.Spec.Template.Spec)
+	problems, err := computeService.GetMissingResources(machineSpec)
+	if err != nil {
+		log.Error(err, "Failed to look up the resources, skipping their validation")
+		return nil
+	}
+	return problems
+}
+
+// getOpenStackCluster returns the OpenStackCluster of the Cluster the
+// OpenStackMachineTemplate belongs to, or nil if there is none yet.
+func (v *OpenStackMachineTemplateResourceValidator) getOpenStackCluster(ctx context.Context, openStackMachineTemplate *infrav1.OpenStackMachineTemplate) (*infrav1.OpenStackCluster, error) {
+	var cluster *clusterv1.Cluster
+	var err error
+	if openStackMachineTemplate.Labels[clusterv1.ClusterLabelName] != "" {
+		cluster, err = util.GetClusterFromMetadata(ctx, v.Client, openStackMachineTemplate.ObjectMeta)
+	} else {
+		// Templates created for a ClusterClass are owned by the Cluster
+		// instead of carrying the cluster label
+		cluster, err = util.GetOwnerCluster(ctx, v.Client, openStackMachineTemplate.ObjectMeta)
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if cluster == nil || cluster.Spec.InfrastructureRef == nil {
+		return nil, nil
+	}
+
+	openStackCluster := &infrav1.OpenStackCluster{}
+	key := client.ObjectKey{Namespace: openStackMachineTemplate.Namespace, Name: cluster.Spec.InfrastructureRef.Name}
+	if err := v.Client.Get(ctx, key, openStackCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return openStackCluster, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
+)
+
+func TestOpenStackMachineTemplateResourceValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := infrav1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := clusterv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+		Spec: clusterv1.ClusterSpec{
+			InfrastructureRef: &corev1.ObjectReference{Kind: "OpenStackCluster", Name: "cluster"},
+		},
+	}
+	newRequest := func(labels map[string]string) admission.Request {
+		raw, err := json.Marshal(&infrav1.OpenStackMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default", Labels: labels},
+			Spec: infrav1.OpenStackMachineTemplateSpec{
+				Template: infrav1.OpenStackMachineTemplateResource{
+					Spec: infrav1.OpenStackMachineSpec{Flavor: "m1.typo", Image: "ubuntu-2204"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	// The lookups need the OpenStackCluster, so the validation is skipped
+	// without contacting the cloud if it cannot be found
+	tests := []struct {
+		name   string
+		mode   ValidationMode
+		labels map[string]string
+	}{
+		{
+			name:   "Validation disabled",
+			mode:   ValidationDisabled,
+			labels: map[string]string{clusterv1.ClusterLabelName: "cluster"},
+		},
+		{
+			name: "Template of a ClusterClass",
+			mode: ValidationReject,
+		},
+		{
+			name:   "Cluster does not exist",
+			mode:   ValidationReject,
+			labels: map[string]string{clusterv1.ClusterLabelName: "other-cluster"},
+		},
+		{
+			name:   "OpenStackCluster does not exist",
+			mode:   ValidationReject,
+			labels: map[string]string{clusterv1.ClusterLabelName: "cluster"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			v := &OpenStackMachineTemplateResourceValidator{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster.DeepCopy()).Build(),
+				Mode:   tt.mode,
+			}
+			g.Expect(v.InjectDecoder(decoder)).To(Succeed())
+
+			resp := v.Handle(context.TODO(), newRequest(tt.labels))
+			g.Expect(resp.Allowed).To(BeTrue())
+			g.Expect(resp.Warnings).To(BeEmpty())
+		})
+	}
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
@@ -50,7 +51,7 @@ import (
 	"gopkg.in/ini.v1"
 	"sigs.k8s.io/yaml"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/compute"
 	"sigs.k8s.io/cluster-api-provider-openstack/pkg/cloud/services/provider"
 )
@@ -346,6 +347,32 @@ func DumpOpenStackSecurityGroups(e2eCtx *E2EContext, filter groups.ListOpts) ([]
 	return groupsList, nil
 }
 
+// DumpOpenStackFloatingIPs returns all OpenStack floating IPs matching the filter.
+func DumpOpenStackFloatingIPs(e2eCtx *E2EContext, filter floatingips.ListOpts) ([]floatingips.FloatingIP, error) {
+	providerClient, clientOpts, _, err := GetTenantProviderClient(e2eCtx)
+	if err != nil {
+		_, _ = fmt.Fprintf(GinkgoWriter, "error creating provider client: %s\n", err)
+		return nil, nil
+	}
+
+	networkClient, err := openstack.NewNetworkV2(providerClient, gophercloud.EndpointOpts{
+		Region: clientOpts.RegionName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating network client: %s", err)
+	}
+
+	allPages, err := floatingips.List(networkClient, filter).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("error listing floating IPs: %s", err)
+	}
+	floatingIPList, err := floatingips.ExtractFloatingIPs(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting floating IPs: %s", err)
+	}
+	return floatingIPList, nil
+}
+
 func DumpOpenStackPorts(e2eCtx *E2EContext, filter ports.ListOpts) ([]ports.Port, error) {
 	providerClient, clientOpts, _, err := GetTenantProviderClient(e2eCtx)
 	if err != nil {
@@ -493,7 +520,7 @@ func getProviderClient(e2eCtx *E2EContext, openstackCloud string) (*gophercloud.
 	clouds := getParsedOpenStackCloudYAML(openStackCloudYAMLFile)
 	cloud := clouds.Clouds[openstackCloud]
 
-	providerClient, clientOpts, projectID, err := provider.NewClient(cloud, nil)
+	providerClient, clientOpts, projectID, err := provider.NewClient(cloud, nil, nil)
 	if err != nil {
 		return nil, nil, nil, err
 	}
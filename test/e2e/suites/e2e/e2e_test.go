@@ -33,6 +33,7 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumetypes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/trunks"
@@ -50,13 +51,15 @@ import (
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/test/framework"
 	"sigs.k8s.io/cluster-api/test/framework/clusterctl"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
-	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha6"
+	infrav1 "sigs.k8s.io/cluster-api-provider-openstack/api/v1alpha7"
 	capoerrors "sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/errors"
+	"sigs.k8s.io/cluster-api-provider-openstack/pkg/utils/names"
 	"sigs.k8s.io/cluster-api-provider-openstack/test/e2e/shared"
 )
 
@@ -226,7 +229,7 @@ var _ = Describe("e2e tests", func() {
 					Trunk:       pointer.Bool(true),
 				},
 				{
-					SecurityGroupFilters: []infrav1.SecurityGroupParam{{Name: testSecurityGroupName}},
+					SecurityGroupFilters: []infrav1.SecurityGroupFilter{{Name: testSecurityGroupName}},
 				},
 			}
 
@@ -586,6 +589,78 @@ var _ = Describe("e2e tests", func() {
 		})
 	})
 
+	Describe("Workload cluster (clusterctl move)", func() {
+		It("should re-adopt its OpenStack resources after losing its status", func() {
+			shared.Byf("Creating a cluster")
+			clusterName := fmt.Sprintf("cluster-%s", namespace.Name)
+			configCluster := defaultConfigCluster(clusterName, namespace.Name)
+			configCluster.ControlPlaneMachineCount = pointer.Int64Ptr(1)
+			configCluster.WorkerMachineCount = pointer.Int64Ptr(1)
+			configCluster.Flavor = shared.FlavorDefault
+			createCluster(ctx, configCluster)
+
+			mgmtClient := e2eCtx.Environment.BootstrapClusterProxy.GetClient()
+			openStackCluster, err := shared.ClusterForSpec(ctx, e2eCtx, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(openStackCluster.Annotations).To(HaveKey(infrav1.ResourceIDsAnnotation))
+			before := openStackCluster.Status.DeepCopy()
+
+			shared.Byf("Checking that the identity secret is moved by clusterctl")
+			Expect(openStackCluster.Spec.IdentityRef).NotTo(BeNil())
+			secret := &corev1.Secret{}
+			Expect(mgmtClient.Get(ctx, apimachinerytypes.NamespacedName{Namespace: namespace.Name, Name: openStackCluster.Spec.IdentityRef.Name}, secret)).To(Succeed())
+			Expect(secret.Labels).To(HaveKey(clusterctlv1.ClusterctlMoveLabelName))
+
+			floatingIPFilter := floatingips.ListOpts{Description: names.GetDescription(fmt.Sprintf("%s-%s", namespace.Name, clusterName))}
+			floatingIPsBefore, err := shared.DumpOpenStackFloatingIPs(e2eCtx, floatingIPFilter)
+			Expect(err).NotTo(HaveOccurred())
+			securityGroupsBefore, err := shared.DumpOpenStackSecurityGroups(e2eCtx, groups.ListOpts{Tags: clusterName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// clusterctl move recreates all objects in the target cluster
+			// without their status, while the cluster is paused
+			shared.Byf("Dropping the status of the OpenStackCluster as clusterctl move does")
+			cluster := &clusterv1.Cluster{}
+			Expect(mgmtClient.Get(ctx, apimachinerytypes.NamespacedName{Namespace: namespace.Name, Name: clusterName}, cluster)).To(Succeed())
+			clusterPatch := crclient.MergeFrom(cluster.DeepCopy())
+			cluster.Spec.Paused = true
+			Expect(mgmtClient.Patch(ctx, cluster, clusterPatch)).To(Succeed())
+
+			openStackCluster.Status = infrav1.OpenStackClusterStatus{}
+			Expect(mgmtClient.Status().Update(ctx, openStackCluster)).To(Succeed())
+
+			clusterPatch = crclient.MergeFrom(cluster.DeepCopy())
+			cluster.Spec.Paused = false
+			Expect(mgmtClient.Patch(ctx, cluster, clusterPatch)).To(Succeed())
+
+			shared.Byf("Waiting for the OpenStackCluster to become ready again")
+			Eventually(func() bool {
+				openStackCluster, err = shared.ClusterForSpec(ctx, e2eCtx, namespace)
+				return err == nil && openStackCluster.Status.Ready
+			}, e2eCtx.E2EConfig.GetIntervals(specName, "wait-cluster")...).Should(BeTrue())
+
+			shared.Byf("Checking that no OpenStack resources were recreated")
+			after := openStackCluster.Status
+			Expect(after.Network.ID).To(Equal(before.Network.ID))
+			Expect(after.Network.Subnet.ID).To(Equal(before.Network.Subnet.ID))
+			Expect(after.Network.Router.ID).To(Equal(before.Network.Router.ID))
+			Expect(after.Network.APIServerLoadBalancer.ID).To(Equal(before.Network.APIServerLoadBalancer.ID))
+			Expect(after.Network.APIServerLoadBalancer.IP).To(Equal(before.Network.APIServerLoadBalancer.IP))
+			Expect(after.ControlPlaneSecurityGroup.ID).To(Equal(before.ControlPlaneSecurityGroup.ID))
+			Expect(after.WorkerSecurityGroup.ID).To(Equal(before.WorkerSecurityGroup.ID))
+			Expect(after.BastionSecurityGroup.ID).To(Equal(before.BastionSecurityGroup.ID))
+			Expect(after.Bastion.ID).To(Equal(before.Bastion.ID))
+			Expect(after.Bastion.FloatingIP).To(Equal(before.Bastion.FloatingIP))
+
+			floatingIPsAfter, err := shared.DumpOpenStackFloatingIPs(e2eCtx, floatingIPFilter)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(floatingIPsAfter).To(HaveLen(len(floatingIPsBefore)))
+			securityGroupsAfter, err := shared.DumpOpenStackSecurityGroups(e2eCtx, groups.ListOpts{Tags: clusterName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(securityGroupsAfter).To(HaveLen(len(securityGroupsBefore)))
+		})
+	})
+
 	AfterEach(func() {
 		shared.SetEnvVar("USE_CI_ARTIFACTS", "false", false)
 		// Dumps all the resources in the spec namespace, then cleanups the cluster object and the spec namespace itself.